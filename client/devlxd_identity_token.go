@@ -0,0 +1,22 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetIdentityToken retrieves a signed identity token of the instance for the given audience.
+func (r *ProtocolDevLXD) GetIdentityToken(audience string) (*api.DevLXDIdentityToken, error) {
+	var token api.DevLXDIdentityToken
+
+	url := api.NewURL().Path("identity-token").WithQuery("audience", audience).URL
+	r.setURLQueryAttributes(&url)
+
+	_, err := r.queryStruct(http.MethodGet, url.String(), nil, "", &token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -115,7 +115,9 @@ type InstanceServer interface {
 	CopyInstance(source InstanceServer, instance api.Instance, args *InstanceCopyArgs) (op RemoteOperation, err error)
 	UpdateInstance(name string, instance api.InstancePut, ETag string) (op Operation, err error)
 	RenameInstance(name string, instance api.InstancePost) (op Operation, err error)
+	ForkInstance(name string, req api.InstanceForkPost) (op Operation, err error)
 	MigrateInstance(name string, instance api.InstancePost) (op Operation, err error)
+	MigrateInstanceToClusterLink(name string, clusterLink string, instance api.InstancePost) (op Operation, err error)
 	DeleteInstance(name string, force bool) (op Operation, err error)
 	UpdateInstances(state api.InstancesPut, ETag string) (op Operation, err error)
 	RebuildInstance(instanceName string, req api.InstanceRebuildPost) (op Operation, err error)
@@ -126,6 +128,7 @@ type InstanceServer interface {
 	ExecInstance(instanceName string, exec api.InstanceExecPost, args *InstanceExecArgs) (op Operation, err error)
 	ConsoleInstance(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (op Operation, err error)
 	ConsoleInstanceDynamic(instanceName string, console api.InstanceConsolePost, args *InstanceConsoleArgs) (Operation, func(io.ReadWriteCloser) error, error)
+	PortForwardInstance(instanceName string, req api.InstancePortForwardPost, args *InstancePortForwardArgs) (Operation, func() (*websocket.Conn, error), error)
 
 	GetInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (content io.ReadCloser, err error)
 	DeleteInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) (err error)
@@ -133,6 +136,7 @@ type InstanceServer interface {
 	GetInstanceFile(instanceName string, path string) (content io.ReadCloser, resp *InstanceFileResponse, err error)
 	CreateInstanceFile(instanceName string, path string, args InstanceFileArgs) (err error)
 	DeleteInstanceFile(instanceName string, path string) (err error)
+	GetInstanceSBOM(instanceName string, format string) (content io.ReadCloser, err error)
 
 	GetInstanceFileSFTPConn(instanceName string) (net.Conn, error)
 	GetInstanceFileSFTP(instanceName string) (*sftp.Client, error)
@@ -174,15 +178,24 @@ type InstanceServer interface {
 	// Event handling functions
 	GetEvents() (listener *EventListener, err error)
 	GetEventsAllProjects() (listener *EventListener, err error)
+	GetEventsSince(since string) (listener *EventListener, err error)
+	GetEventsAllProjectsSince(since string) (listener *EventListener, err error)
 	SendEvent(event api.Event) error
 
 	// Image functions
 	CreateImage(image api.ImagesPost, args *ImageCreateArgs) (op Operation, err error)
 	CopyImage(source ImageServer, image api.Image, args *ImageCopyArgs) (op RemoteOperation, err error)
+	BuildImage(source ImageServer, image api.Image, req api.ImagesPost) (op Operation, err error)
 	UpdateImage(fingerprint string, image api.ImagePut, ETag string) (err error)
 	DeleteImage(fingerprint string) (op Operation, err error)
 	RefreshImage(fingerprint string) (op Operation, err error)
 	CreateImageSecret(fingerprint string) (op Operation, err error)
+	AddImageSignature(fingerprint string, signature api.ImageSignaturesPost) (err error)
+	GetImageSigningKey() (key *api.ImageSigningKey, err error)
+	RotateImageSigningKey() (key *api.ImageSigningKey, err error)
+	GetImageSBOM(fingerprint string, format string) (content io.ReadCloser, err error)
+	RegenerateImageSBOM(fingerprint string, req api.ImageSBOMPost) (op Operation, err error)
+	GetImageRetentionCandidates() (candidates []api.ImageRetentionCandidate, err error)
 	CreateImageAlias(alias api.ImageAliasesPost) (err error)
 	UpdateImageAlias(name string, alias api.ImageAliasesEntryPut, ETag string) (err error)
 	RenameImageAlias(name string, alias api.ImageAliasesEntryPost) (err error)
@@ -272,6 +285,7 @@ type InstanceServer interface {
 	GetOperationWaitSecret(uuid string, secret string, timeout int) (op *api.Operation, ETag string, err error)
 	GetOperationWebsocket(uuid string, secret string) (conn *websocket.Conn, err error)
 	DeleteOperation(uuid string) (err error)
+	ApproveOperation(uuid string, approval api.OperationApprovePost) (err error)
 
 	// Profile functions
 	GetProfilesAllProjects() (profiles []api.Profile, err error)
@@ -376,6 +390,7 @@ type InstanceServer interface {
 	UpdateClusterCertificate(certs api.ClusterCertificatePut, ETag string) (err error)
 	GetClusterMemberState(name string) (*api.ClusterMemberState, string, error)
 	UpdateClusterMemberState(name string, state api.ClusterMemberStatePost) (op Operation, err error)
+	GetClusterRebalancePlan() (plan *api.ClusterRebalancePlan, err error)
 	GetClusterGroups() ([]api.ClusterGroup, error)
 	GetClusterGroupNames() ([]string, error)
 	RenameClusterGroup(name string, group api.ClusterGroupPost) error
@@ -404,6 +419,19 @@ type InstanceServer interface {
 	RunReplicator(project string, name string, req api.ReplicatorStatePut) (op Operation, err error)
 	RenameReplicator(project string, name string, replicator api.ReplicatorPost) (err error)
 
+	// Audit log functions
+	GetAuditLog(args GetAuditLogArgs) (entries []api.AuditEntry, err error)
+
+	// Webhook functions
+	GetWebhookNames() (names []string, err error)
+	GetWebhooks() (webhooks []api.Webhook, err error)
+	GetWebhook(name string) (webhook *api.Webhook, ETag string, err error)
+	GetWebhookState(name string) (state *api.WebhookState, err error)
+	CreateWebhook(webhook api.WebhooksPost) (err error)
+	UpdateWebhook(name string, webhook api.WebhookPut, ETag string) (err error)
+	RenameWebhook(name string, webhook api.WebhookPost) (err error)
+	DeleteWebhook(name string) (err error)
+
 	// Warning functions
 	GetWarningUUIDs() (uuids []string, err error)
 	GetWarnings() (warnings []api.Warning, err error)
@@ -522,6 +550,9 @@ type DevLXDServer interface {
 	GetUbuntuPro() (*api.DevLXDUbuntuProSettings, error)
 	CreateUbuntuProToken() (*api.DevLXDUbuntuProGuestTokenResponse, error)
 
+	// DevLXD identity tokens.
+	GetIdentityToken(audience string) (*api.DevLXDIdentityToken, error)
+
 	// Internal functions (for internal use)
 	RawQuery(method string, path string, data any, queryETag string) (resp *api.DevLXDResponse, ETag string, err error)
 }
@@ -574,6 +605,11 @@ type ImageCreateArgs struct {
 
 	// Type of the image (container or virtual-machine)
 	Type string
+
+	// Signatures of the image
+	//
+	// API extension: image_signatures
+	Signatures []api.ImageSignature
 }
 
 // The ImageFileRequest struct is used for an image download request.
@@ -744,6 +780,12 @@ type InstanceConsoleArgs struct {
 	ConsoleDisconnect chan bool
 }
 
+// The InstancePortForwardArgs struct is used to pass additional options during instance port forwarding.
+type InstancePortForwardArgs struct {
+	// Closing this Channel ends the port forwarding and all its connections
+	Disconnect chan bool
+}
+
 // The InstanceConsoleLogArgs struct is used to pass additional options during a
 // instance console log request.
 type InstanceConsoleLogArgs struct {
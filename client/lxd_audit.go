@@ -0,0 +1,64 @@
+package lxd
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetAuditLogArgs represents the arguments for GetAuditLog.
+type GetAuditLogArgs struct {
+	// Since only returns the entries recorded at or after this date.
+	Since time.Time
+
+	// Until only returns the entries recorded at or before this date.
+	Until time.Time
+
+	// Identity only returns the entries of this identity.
+	Identity string
+
+	// Project only returns the entries of this project.
+	Project string
+
+	// EntityURL only returns the entries of this entity and of the entities below it.
+	EntityURL string
+}
+
+// GetAuditLog returns the entries of the audit log matching the given arguments.
+func (r *ProtocolLXD) GetAuditLog(args GetAuditLogArgs) ([]api.AuditEntry, error) {
+	err := r.CheckExtension("audit_log")
+	if err != nil {
+		return nil, err
+	}
+
+	v := url.Values{}
+	if !args.Since.IsZero() {
+		v.Set("since", args.Since.Format(time.RFC3339))
+	}
+
+	if !args.Until.IsZero() {
+		v.Set("until", args.Until.Format(time.RFC3339))
+	}
+
+	if args.Identity != "" {
+		v.Set("identity", args.Identity)
+	}
+
+	if args.Project != "" {
+		v.Set("project", args.Project)
+	}
+
+	if args.EntityURL != "" {
+		v.Set("entity_url", args.EntityURL)
+	}
+
+	entries := []api.AuditEntry{}
+	_, err = r.queryStruct(http.MethodGet, "/audit?"+v.Encode(), nil, "", &entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
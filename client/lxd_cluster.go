@@ -246,6 +246,22 @@ func (r *ProtocolLXD) UpdateClusterMemberState(name string, state api.ClusterMem
 	return op, nil
 }
 
+// GetClusterRebalancePlan returns the instance moves that the cluster rebalancer would perform now.
+func (r *ProtocolLXD) GetClusterRebalancePlan() (*api.ClusterRebalancePlan, error) {
+	err := r.CheckExtension("cluster_rebalance")
+	if err != nil {
+		return nil, err
+	}
+
+	plan := api.ClusterRebalancePlan{}
+	_, err = r.queryStruct(http.MethodGet, "/cluster/rebalance", nil, "", &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
 // GetClusterGroups returns the cluster groups.
 func (r *ProtocolLXD) GetClusterGroups() ([]api.ClusterGroup, error) {
 	err := r.CheckExtension("clustering_groups")
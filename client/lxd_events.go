@@ -2,6 +2,7 @@ package lxd
 
 import (
 	"errors"
+	"net/url"
 
 	"github.com/gorilla/websocket"
 
@@ -9,7 +10,8 @@ import (
 )
 
 // getEvents connects to the LXD monitoring interface.
-func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
+// If since is set, the events following the event with this ID are replayed first.
+func (r *ProtocolLXD) getEvents(allProjects bool, since *string) (*EventListener, error) {
 	// Resolve the project name.
 	connInfo, err := r.GetConnectionInfo()
 	if err != nil {
@@ -29,19 +31,31 @@ func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
 	// establish a new connection.
 	getWebsocket := func() (*websocket.Conn, error) {
 		// Resolve LXD events URL.
-		var url string
-		var err error
+		values := url.Values{}
 		if allProjects {
-			url, err = r.setQueryAttributes("/events?all-projects=true")
-		} else {
-			url, err = r.setQueryAttributes("/events")
+			values.Set("all-projects", "true")
 		}
 
+		if since != nil {
+			values.Set("since", *since)
+		}
+
+		path := "/events"
+		if len(values) > 0 {
+			path += "?" + values.Encode()
+		}
+
+		eventsURL, err := r.setQueryAttributes(path)
 		if err != nil {
 			return nil, err
 		}
 
-		return r.websocket(url)
+		return r.websocket(eventsURL)
+	}
+
+	// A resumed stream can't share the connection of the other listeners, as it starts at a different event.
+	if since != nil {
+		return newEventListenerManager(r.eventListenerManager.ctx).getEvents(r.ctxConnected, getWebsocket, project)
 	}
 
 	return r.eventListenerManager.getEvents(r.ctxConnected, getWebsocket, project)
@@ -49,12 +63,34 @@ func (r *ProtocolLXD) getEvents(allProjects bool) (*EventListener, error) {
 
 // GetEvents gets the events for the project defined on the client.
 func (r *ProtocolLXD) GetEvents() (*EventListener, error) {
-	return r.getEvents(false)
+	return r.getEvents(false, nil)
 }
 
 // GetEventsAllProjects gets events for all projects.
 func (r *ProtocolLXD) GetEventsAllProjects() (*EventListener, error) {
-	return r.getEvents(true)
+	return r.getEvents(true, nil)
+}
+
+// GetEventsSince gets the events for the project defined on the client, starting with the events following the event
+// with the given ID.
+func (r *ProtocolLXD) GetEventsSince(since string) (*EventListener, error) {
+	err := r.CheckExtension("event_history")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getEvents(false, &since)
+}
+
+// GetEventsAllProjectsSince gets the events for all projects, starting with the events following the event with the
+// given ID.
+func (r *ProtocolLXD) GetEventsAllProjectsSince(since string) (*EventListener, error) {
+	err := r.CheckExtension("event_history")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getEvents(true, &since)
 }
 
 // SendEvent send an event to the server via the client's event listener connection.
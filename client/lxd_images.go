@@ -3,6 +3,7 @@ package lxd
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -349,6 +350,23 @@ func (r *ProtocolLXD) GetImageAliases() ([]api.ImageAliasesEntry, error) {
 	return aliases, nil
 }
 
+// GetImageRetentionCandidates returns the images that the retention policy of the project deletes on its next run.
+func (r *ProtocolLXD) GetImageRetentionCandidates() ([]api.ImageRetentionCandidate, error) {
+	err := r.CheckExtension("image_retention")
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []api.ImageRetentionCandidate{}
+
+	_, err = r.queryStruct(http.MethodGet, "/images/retention", nil, "", &candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
 // GetImageAliasNames returns the list of available alias names.
 func (r *ProtocolLXD) GetImageAliasNames() ([]string, error) {
 	// Fetch the raw URL values.
@@ -420,6 +438,20 @@ func (r *ProtocolLXD) CreateImage(image api.ImagesPost, args *ImageCreateArgs) (
 		}
 	}
 
+	if image.Sign || (args != nil && len(args.Signatures) > 0) {
+		err := r.CheckExtension("image_signatures")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if image.Source != nil && image.Source.Build != nil {
+		err := r.CheckExtension("image_build")
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the JSON based request
 	if args == nil {
 		op, _, err := r.queryOperation(http.MethodPost, "/images", image, "", true)
@@ -544,6 +576,15 @@ func (r *ProtocolLXD) CreateImage(image api.ImagesPost, args *ImageCreateArgs) (
 		req.Header.Set("X-LXD-profiles", imgProfiles.Encode())
 	}
 
+	if len(args.Signatures) > 0 {
+		imgSignatures, err := json.Marshal(args.Signatures)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("X-LXD-signatures", string(imgSignatures))
+	}
+
 	// Set the user agent
 	if image.Source != nil && image.Source.Fingerprint != "" && image.Source.Secret != "" && image.Source.Mode == "push" {
 		// Set fingerprint
@@ -685,6 +726,32 @@ func (r *ProtocolLXD) tryCopyImage(req api.ImagesPost, urls []string) (RemoteOpe
 	return &rop, nil
 }
 
+// BuildImage builds a new image from the recipe in the request, using the given image from the source server as
+// the base image.
+func (r *ProtocolLXD) BuildImage(source ImageServer, image api.Image, req api.ImagesPost) (Operation, error) {
+	if req.Source == nil || req.Source.Build == nil {
+		return nil, errors.New("No build recipe provided")
+	}
+
+	req.Source.Type = "build"
+
+	info, err := r.getSourceImageConnectionInfo(source, image, &req.Source.Build.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let the server pull the base image from the source server.
+	if info != nil {
+		if len(info.Addresses) == 0 {
+			return nil, errors.New("The source server is not listening on the network")
+		}
+
+		req.Source.Build.Source.Server = info.Addresses[0]
+	}
+
+	return r.CreateImage(req, nil)
+}
+
 // CopyImage copies an image from a remote server. Additional options can be passed using ImageCopyArgs.
 func (r *ProtocolLXD) CopyImage(source ImageServer, image api.Image, args *ImageCopyArgs) (RemoteOperation, error) {
 	// Quick checks.
@@ -844,6 +911,11 @@ func (r *ProtocolLXD) CopyImage(source ImageServer, image api.Image, args *Image
 			Type:     image.Type,
 		}
 
+		// Only send the signatures to servers that can check them.
+		if r.HasExtension("image_signatures") {
+			createArgs.Signatures = image.Signatures
+		}
+
 		if resp.RootfsName != "" {
 			// Deal with split images
 			createArgs.RootfsFile = rootfsFile
@@ -980,6 +1052,119 @@ func (r *ProtocolLXD) CreateImageSecret(fingerprint string) (Operation, error) {
 	return op, nil
 }
 
+// AddImageSignature adds a signature made by the client to an image.
+func (r *ProtocolLXD) AddImageSignature(fingerprint string, signature api.ImageSignaturesPost) error {
+	err := r.CheckExtension("image_signatures")
+	if err != nil {
+		return err
+	}
+
+	// Send the request
+	_, _, err = r.query(http.MethodPost, "/images/"+url.PathEscape(fingerprint)+"/signatures", signature, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetImageSigningKey returns the key used by the server to sign images.
+func (r *ProtocolLXD) GetImageSigningKey() (*api.ImageSigningKey, error) {
+	err := r.CheckExtension("image_signatures")
+	if err != nil {
+		return nil, err
+	}
+
+	key := api.ImageSigningKey{}
+
+	// Fetch the raw value
+	_, err = r.queryStruct(http.MethodGet, "/image-signing-key", nil, "", &key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// RotateImageSigningKey replaces the key used by the server to sign images and returns the new key.
+func (r *ProtocolLXD) RotateImageSigningKey() (*api.ImageSigningKey, error) {
+	err := r.CheckExtension("image_signatures")
+	if err != nil {
+		return nil, err
+	}
+
+	key := api.ImageSigningKey{}
+
+	// Send the request
+	_, err = r.queryStruct(http.MethodPost, "/image-signing-key", nil, "", &key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// GetImageSBOM returns the software bill of materials of an image in the given format ("spdx" or "cyclonedx").
+func (r *ProtocolLXD) GetImageSBOM(fingerprint string, format string) (io.ReadCloser, error) {
+	err := r.CheckExtension("image_sbom")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getSBOM("/images/"+url.PathEscape(fingerprint)+"/sbom", format)
+}
+
+// RegenerateImageSBOM requests that LXD regenerates the stored software bill of materials of an image.
+func (r *ProtocolLXD) RegenerateImageSBOM(fingerprint string, req api.ImageSBOMPost) (Operation, error) {
+	err := r.CheckExtension("image_sbom")
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, "/images/"+url.PathEscape(fingerprint)+"/sbom", req, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// getSBOM returns the raw software bill of materials served at the given path.
+func (r *ProtocolLXD) getSBOM(path string, format string) (io.ReadCloser, error) {
+	// Prepare the HTTP request
+	requestURL, err := shared.URLEncode(r.httpBaseURL.String()+"/1.0"+path, map[string]string{"format": format})
+	if err != nil {
+		return nil, err
+	}
+
+	requestURL, err = r.setQueryAttributes(requestURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	resp, err := r.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the return value for a cleaner error
+	if resp.StatusCode != http.StatusOK {
+		_, _, err := lxdParseResponse(resp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp.Body, nil
+}
+
 // CreateImageAlias sets up a new image alias.
 func (r *ProtocolLXD) CreateImageAlias(alias api.ImageAliasesPost) error {
 	// Send the request
@@ -971,6 +971,48 @@ func (r *ProtocolLXD) RenameInstance(name string, instance api.InstancePost) (Op
 	return op, nil
 }
 
+// ForkInstance requests that LXD creates new instances from the memory state of a running instance.
+func (r *ProtocolLXD) ForkInstance(name string, req api.InstanceForkPost) (Operation, error) {
+	err := r.CheckExtension("instance_fork")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"?fork=true", req, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// MigrateInstanceToClusterLink requests that LXD migrates the instance to the cluster of the given cluster link.
+func (r *ProtocolLXD) MigrateInstanceToClusterLink(name string, clusterLink string, instance api.InstancePost) (Operation, error) {
+	err := r.CheckExtension("instance_move_cluster_link")
+	if err != nil {
+		return nil, err
+	}
+
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Send the request
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(name)+"?target-cluster-link="+url.QueryEscape(clusterLink), instance, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
 // tryMigrateInstance attempts to migrate a specific instance from a source server to one of the target URLs.
 // The function runs the migration operation asynchronously and returns a RemoteOperation to track the progress and handle any errors.
 func (r *ProtocolLXD) tryMigrateInstance(source InstanceServer, name string, req api.InstancePost, urls []string, op Operation) (RemoteOperation, error) {
@@ -2743,6 +2785,82 @@ func (r *ProtocolLXD) ConsoleInstanceDynamic(instanceName string, console api.In
 	return op, f, nil
 }
 
+// PortForwardInstance requests that LXD forwards connections to a port inside the instance.
+//
+// Every time the returned function is called, a new connection to the port is
+// established and a websocket connected to it is returned. Stream protocols use
+// binary messages for data and an empty text message to signal the end of the
+// stream, while datagram protocols carry one datagram per binary message.
+func (r *ProtocolLXD) PortForwardInstance(instanceName string, req api.InstancePortForwardPost, args *InstancePortForwardArgs) (Operation, func() (*websocket.Conn, error), error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = r.CheckExtension("instance_port_forward")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if args == nil {
+		return nil, nil, errors.New("No arguments provided")
+	}
+
+	// Send the request.
+	op, _, err := r.queryOperation(http.MethodPost, path+"/"+url.PathEscape(instanceName)+"/port-forward", req, "", true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opAPI := op.Get()
+
+	// Parse the fds.
+	fds := map[string]string{}
+
+	value, ok := opAPI.Metadata["fds"]
+	if ok {
+		values, ok := value.(map[string]any)
+		if ok {
+			for k, v := range values {
+				vStr, ok := v.(string)
+				if !ok {
+					continue
+				}
+
+				fds[k] = vStr
+			}
+		}
+	}
+
+	if fds[api.SecretNameControl] == "" || fds["0"] == "" {
+		return nil, nil, errors.New("Did not receive the file descriptors for the port forwarding")
+	}
+
+	controlConn, err := r.GetOperationWebsocket(opAPI.ID, fds[api.SecretNameControl])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go func() {
+		_, _, _ = controlConn.ReadMessage() // Consume pings from server.
+	}()
+
+	// Handle main disconnect.
+	go func(disconnect <-chan bool) {
+		<-disconnect
+		msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "Stopping port forwarding")
+		// We don't care if this fails. This is just for convenience.
+		_ = controlConn.WriteMessage(websocket.CloseMessage, msg)
+		_ = controlConn.Close()
+	}(args.Disconnect)
+
+	f := func() (*websocket.Conn, error) {
+		return r.GetOperationWebsocket(opAPI.ID, fds["0"])
+	}
+
+	return op, f, nil
+}
+
 // GetInstanceConsoleLog requests that LXD attaches to the console device of a instance.
 //
 // Note that it's the caller's responsibility to close the returned ReadCloser.
@@ -2787,6 +2905,22 @@ func (r *ProtocolLXD) GetInstanceConsoleLog(instanceName string, args *InstanceC
 	return resp.Body, err
 }
 
+// GetInstanceSBOM returns the software bill of materials of an instance in the given format ("spdx" or
+// "cyclonedx"). It is generated from the package databases of the instance.
+func (r *ProtocolLXD) GetInstanceSBOM(instanceName string, format string) (io.ReadCloser, error) {
+	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.CheckExtension("image_sbom")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.getSBOM(path+"/"+url.PathEscape(instanceName)+"/sbom", format)
+}
+
 // DeleteInstanceConsoleLog deletes the requested instance's console log.
 func (r *ProtocolLXD) DeleteInstanceConsoleLog(instanceName string, args *InstanceConsoleLogArgs) error {
 	path, _, err := r.instanceTypeToPath(api.InstanceTypeAny)
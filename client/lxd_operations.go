@@ -147,6 +147,21 @@ func (r *ProtocolLXD) GetOperationWebsocket(uuid string, secret string) (*websoc
 	return r.websocket(path)
 }
 
+// ApproveOperation approves or rejects an operation pending approval.
+func (r *ProtocolLXD) ApproveOperation(uuid string, approval api.OperationApprovePost) error {
+	err := r.CheckExtension("operation_approval")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, "/operations/"+url.PathEscape(uuid)+"/approve", approval, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // DeleteOperation deletes (cancels) a running operation.
 func (r *ProtocolLXD) DeleteOperation(uuid string) error {
 	// Send the request
@@ -0,0 +1,132 @@
+package lxd
+
+import (
+	"net/http"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// GetWebhookNames returns a list of webhook names.
+func (r *ProtocolLXD) GetWebhookNames() ([]string, error) {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return nil, err
+	}
+
+	urls := []string{}
+	baseURL := api.NewURL().Path("webhooks").String()
+	_, err = r.queryStruct(http.MethodGet, baseURL, nil, "", &urls)
+	if err != nil {
+		return nil, err
+	}
+
+	return urlsToResourceNames(baseURL, urls...)
+}
+
+// GetWebhooks returns the webhooks.
+func (r *ProtocolLXD) GetWebhooks() ([]api.Webhook, error) {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return nil, err
+	}
+
+	var webhooks []api.Webhook
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("webhooks").WithQuery("recursion", "1").String(), nil, "", &webhooks)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook gets a single webhook.
+func (r *ProtocolLXD) GetWebhook(name string) (*api.Webhook, string, error) {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return nil, "", err
+	}
+
+	var webhook api.Webhook
+	eTag, err := r.queryStruct(http.MethodGet, api.NewURL().Path("webhooks", name).String(), nil, "", &webhook)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &webhook, eTag, nil
+}
+
+// GetWebhookState gets the delivery status of a webhook.
+func (r *ProtocolLXD) GetWebhookState(name string) (*api.WebhookState, error) {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return nil, err
+	}
+
+	var state api.WebhookState
+	_, err = r.queryStruct(http.MethodGet, api.NewURL().Path("webhooks", name, "state").String(), nil, "", &state)
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// CreateWebhook creates a new webhook.
+func (r *ProtocolLXD) CreateWebhook(webhook api.WebhooksPost) error {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("webhooks").String(), webhook, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateWebhook fully overwrites the updatable fields of the webhook.
+func (r *ProtocolLXD) UpdateWebhook(name string, webhook api.WebhookPut, ETag string) error {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPut, api.NewURL().Path("webhooks", name).String(), webhook, ETag)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RenameWebhook renames the webhook.
+func (r *ProtocolLXD) RenameWebhook(name string, webhook api.WebhookPost) error {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodPost, api.NewURL().Path("webhooks", name).String(), webhook, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteWebhook deletes the webhook and its queued deliveries.
+func (r *ProtocolLXD) DeleteWebhook(name string) error {
+	err := r.CheckExtension("webhooks")
+	if err != nil {
+		return err
+	}
+
+	_, _, err = r.query(http.MethodDelete, api.NewURL().Path("webhooks", name).String(), nil, "")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -118,6 +118,7 @@ type cmdAction struct {
 	global *cmdGlobal
 
 	flagAll       bool
+	flagBootOrder bool
 	flagConsole   string
 	flagForce     bool
 	flagStateful  bool
@@ -138,6 +139,7 @@ func (c *cmdAction) Command(action string) *cobra.Command {
 		cmd.Flags().BoolVar(&c.flagStateful, "stateful", false, "Store the instance state")
 	case "start":
 		cmd.Flags().BoolVar(&c.flagStateless, "stateless", false, "Ignore the instance state")
+		cmd.Flags().BoolVar(&c.flagBootOrder, "boot-order", false, "Start the instances in a single request that honors their boot dependencies")
 	}
 
 	if slices.Contains([]string{"start", "restart", "stop"}, action) {
@@ -155,7 +157,8 @@ func (c *cmdAction) Command(action string) *cobra.Command {
 
 // doActionAll is a method of the cmdAction structure. It performs a specified action on all instances of a remote resource.
 // It ensures that flags and parameters are appropriately set, and handles any errors that may occur during the process.
-func (c *cmdAction) doActionAll(action string, resource remoteResource) error {
+// When instances is non-empty, only the listed instances are acted upon.
+func (c *cmdAction) doActionAll(action string, resource remoteResource, instances ...string) error {
 	if resource.name != "" {
 		// both --all and instance name given.
 		return errors.New("Both --all and instance name given")
@@ -182,6 +185,7 @@ func (c *cmdAction) doActionAll(action string, resource remoteResource) error {
 			Force:    c.flagForce,
 			Stateful: state,
 		},
+		Instances: instances,
 	}
 
 	// Update all instances.
@@ -211,6 +215,38 @@ func (c *cmdAction) doActionAll(action string, resource remoteResource) error {
 	return nil
 }
 
+// startGroup starts the given instances using the bulk API so that the server honors their boot dependencies.
+// All the instances must be located on the same remote and that remote must support boot dependencies.
+func (c *cmdAction) startGroup(names []string) error {
+	resources, err := c.global.ParseServers(names...)
+	if err != nil {
+		return err
+	}
+
+	instances := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		if resource.name == "" {
+			return errors.New("Missing instance name")
+		}
+
+		if resource.remote != resources[0].remote {
+			return errors.New("--boot-order requires all instances to be on the same remote")
+		}
+
+		instances = append(instances, resource.name)
+	}
+
+	resource := resources[0]
+	err = resource.server.CheckExtension("instance_boot_dependencies")
+	if err != nil {
+		return err
+	}
+
+	resource.name = ""
+
+	return c.doActionAll("start", resource, instances...)
+}
+
 // doAction is a method of the cmdAction structure. It carries out a specified action on an instance,
 // using a given config and instance name. It manages state changes, flag checks, error handling and console attachment.
 func (c *cmdAction) doAction(action string, conf *config.Config, nameArg string) error {
@@ -374,6 +410,15 @@ func (c *cmdAction) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Start the instances in a single request so that the server can honor their boot dependencies.
+	if c.flagBootOrder && !c.flagAll {
+		if c.flagConsole != "" {
+			return errors.New("--console cannot be used with --boot-order")
+		}
+
+		return c.startGroup(names)
+	}
+
 	if c.flagConsole != "" {
 		if c.flagAll {
 			return errors.New("--console cannot be used with --all")
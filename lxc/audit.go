@@ -0,0 +1,217 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdAudit struct {
+	global *cmdGlobal
+}
+
+func (c *cmdAudit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("audit")
+	cmd.Short = "Inspect the audit log"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	// List
+	auditListCmd := cmdAuditList{global: c.global, audit: c}
+	cmd.AddCommand(auditListCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdAuditList struct {
+	global *cmdGlobal
+	audit  *cmdAudit
+
+	flagColumns  string
+	flagFormat   string
+	flagSince    string
+	flagUntil    string
+	flagIdentity string
+	flagEntity   string
+}
+
+func (c *cmdAuditList) columns() []cli.ShorthandColumn[api.AuditEntry] {
+	return []cli.ShorthandColumn[api.AuditEntry]{
+		{Shorthand: 'd', Name: "DATE", Data: c.dateColumnData},
+		{Shorthand: 'i', Name: "IDENTITY", Data: c.identityColumnData},
+		{Shorthand: 'm', Name: "METHOD", Data: c.methodColumnData},
+		{Shorthand: 'e', Name: "ENTITY", Data: c.entityColumnData},
+		{Shorthand: 's', Name: "STATUS", Data: c.statusColumnData},
+	}
+}
+
+func (c *cmdAuditList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", "[<remote>:]")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List audit log entries"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The audit log records the requests that changed the server, oldest first.
+Use --project to only list the requests made in a given project.
+
+The -c option takes a (optionally comma-separated) list of arguments
+that control which entry attributes to output when displaying in table
+or csv format.
+
+Default column layout is: dimes
+
+Column shorthand chars:
+
+    a - Source address
+    d - Date
+    e - Entity URL
+    h - Request body hash
+    i - Identity
+    L - Location
+    m - Method
+    p - Project
+    P - Protocol
+    s - Status code`)
+	cmd.Example = cli.FormatSection("", `lxc audit list --since 2025-01-01T00:00:00Z
+    List the requests made since the beginning of 2025.
+
+lxc audit list --entity /1.0/instances/c1 --project default
+    List the requests made on instance c1 and its snapshots, backups and files.`)
+
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", cli.DefaultColumnString(c.columns()), cli.FormatStringFlagLabel("Columns"))
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().StringVar(&c.flagSince, "since", "", cli.FormatStringFlagLabel("Only list the entries recorded at or after this date (RFC3339)"))
+	cmd.Flags().StringVar(&c.flagUntil, "until", "", cli.FormatStringFlagLabel("Only list the entries recorded at or before this date (RFC3339)"))
+	cmd.Flags().StringVar(&c.flagIdentity, "identity", "", cli.FormatStringFlagLabel("Only list the entries of this identity"))
+	cmd.Flags().StringVar(&c.flagEntity, "entity", "", cli.FormatStringFlagLabel("Only list the entries of this entity URL and of the entities below it"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdAuditList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	remoteName, _, err := c.global.conf.ParseRemote(remote)
+	if err != nil {
+		return err
+	}
+
+	remoteServer, err := c.global.conf.GetInstanceServer(remoteName)
+	if err != nil {
+		return err
+	}
+
+	filter := lxd.GetAuditLogArgs{
+		Identity:  c.flagIdentity,
+		Project:   c.global.flagProject,
+		EntityURL: c.flagEntity,
+	}
+
+	if c.flagSince != "" {
+		filter.Since, err = time.Parse(time.RFC3339, c.flagSince)
+		if err != nil {
+			return fmt.Errorf("Invalid --since date: %w", err)
+		}
+	}
+
+	if c.flagUntil != "" {
+		filter.Until, err = time.Parse(time.RFC3339, c.flagUntil)
+		if err != nil {
+			return fmt.Errorf("Invalid --until date: %w", err)
+		}
+	}
+
+	entries, err := remoteServer.GetAuditLog(filter)
+	if err != nil {
+		return err
+	}
+
+	// Add non-default columns that are available for user selection.
+	cols := append(c.columns(),
+		cli.ShorthandColumn[api.AuditEntry]{Shorthand: 'a', Name: "SOURCE ADDRESS", Data: c.sourceAddressColumnData},
+		cli.ShorthandColumn[api.AuditEntry]{Shorthand: 'h', Name: "REQUEST HASH", Data: c.requestHashColumnData},
+		cli.ShorthandColumn[api.AuditEntry]{Shorthand: 'L', Name: "LOCATION", Data: c.locationColumnData},
+		cli.ShorthandColumn[api.AuditEntry]{Shorthand: 'p', Name: "PROJECT", Data: c.projectColumnData},
+		cli.ShorthandColumn[api.AuditEntry]{Shorthand: 'P', Name: "PROTOCOL", Data: c.protocolColumnData},
+	)
+
+	columns, err := cli.ParseShorthandColumns(c.flagColumns, cols)
+	if err != nil {
+		return err
+	}
+
+	// Render the table, keeping the chronological order of the entries.
+	data := cli.ColumnData(columns, entries)
+
+	rawData := make([]*api.AuditEntry, len(entries))
+	for i := range entries {
+		rawData[i] = &entries[i]
+	}
+
+	headers := cli.ColumnHeaders(columns)
+
+	return cli.RenderTable(c.flagFormat, headers, data, rawData)
+}
+
+func (c *cmdAuditList) dateColumnData(entry api.AuditEntry) string {
+	return entry.Date.UTC().Format(time.RFC3339)
+}
+
+func (c *cmdAuditList) entityColumnData(entry api.AuditEntry) string {
+	return entry.EntityURL
+}
+
+func (c *cmdAuditList) identityColumnData(entry api.AuditEntry) string {
+	return entry.Identity
+}
+
+func (c *cmdAuditList) locationColumnData(entry api.AuditEntry) string {
+	return entry.Location
+}
+
+func (c *cmdAuditList) methodColumnData(entry api.AuditEntry) string {
+	return entry.Method
+}
+
+func (c *cmdAuditList) projectColumnData(entry api.AuditEntry) string {
+	return entry.Project
+}
+
+func (c *cmdAuditList) protocolColumnData(entry api.AuditEntry) string {
+	return entry.Protocol
+}
+
+func (c *cmdAuditList) requestHashColumnData(entry api.AuditEntry) string {
+	return entry.RequestHash
+}
+
+func (c *cmdAuditList) sourceAddressColumnData(entry api.AuditEntry) string {
+	return entry.SourceAddress
+}
+
+func (c *cmdAuditList) statusColumnData(entry api.AuditEntry) string {
+	return strconv.Itoa(entry.StatusCode)
+}
@@ -750,6 +750,8 @@ func resolveIdentityTypeShorthand(identityArg string) (method string, identityTy
 		return api.AuthenticationMethodOIDC, api.IdentityTypeOIDCClient, idName, nil
 	case "devlxd":
 		return api.AuthenticationMethodBearer, api.IdentityTypeBearerTokenDevLXD, idName, nil
+	case "scim":
+		return api.AuthenticationMethodBearer, api.IdentityTypeBearerTokenSCIM, idName, nil
 	case "bearer":
 		return api.AuthenticationMethodBearer, "", idName, nil
 	case "cluster-link":
@@ -1023,7 +1025,7 @@ func (c *cmdIdentityCreate) createClusterLinkIdentity(remote string, name string
 	return nil
 }
 
-// createBearerIdentity is called via `lxc auth identity create devlxd/<name>`.
+// createBearerIdentity is called via `lxc auth identity create devlxd/<name>` or `lxc auth identity create scim/<name>`.
 // It accepts the remote name, and the name and type of the identity to be created.
 // These parameters, in addition to contents of stdin, are used to compose an [api.IdentitiesBearerPost] request body.
 func (c *cmdIdentityCreate) createBearerIdentity(remoteName string, identityName string, identityType string) error {
@@ -103,6 +103,9 @@ var topLevelInstanceServerResourceNameFuncs = map[string]func(server lxd.Instanc
 	"replicator": func(server lxd.InstanceServer) ([]string, error) {
 		return server.GetReplicatorNames()
 	},
+	"webhook": func(server lxd.InstanceServer) ([]string, error) {
+		return server.GetWebhookNames()
+	},
 }
 
 var topLevelImageServerResourceNameFuncs = map[string]func(server lxd.ImageServer) ([]string, error){
@@ -1421,6 +1424,30 @@ func (g *cmdGlobal) cmpPlacementGroupConfigs(placementGroupName string) ([]strin
 	return configs, cobra.ShellCompDirectiveNoFileComp
 }
 
+// cmpWebhookConfigs provides shell completion for webhook configs.
+// It takes a webhook name and returns a list of webhook configs along with a shell completion directive.
+func (g *cmdGlobal) cmpWebhookConfigs(webhookName string) ([]string, cobra.ShellCompDirective) {
+	resources, err := g.ParseServers(webhookName)
+	if err != nil || len(resources) == 0 {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	resource := resources[0]
+	client := resource.server
+
+	webhook, _, err := client.GetWebhook(resource.name)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	configs := make([]string, 0, len(webhook.Config))
+	for c := range webhook.Config {
+		configs = append(configs, c)
+	}
+
+	return configs, cobra.ShellCompDirectiveNoFileComp
+}
+
 // remoteCompletionFilter is passed into cmpRemotes to determine which remotes to include in the result.
 // Filter functions must match positively. E.g. Return true to filter the remote from the result.
 type remoteCompletionFilter func(name string, remote config.Remote) bool
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+// Fork.
+type cmdFork struct {
+	global *cmdGlobal
+
+	flagEphemeral bool
+}
+
+func (c *cmdFork) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("fork", "[<remote>:]<instance> <name>...")
+	cmd.Short = "Fork a running virtual machine"
+	cmd.Long = cli.FormatSection("Description", `Fork a running virtual machine
+
+The new instances are started from the memory state of the source instance.
+They get new MAC addresses and volatile keys, and the lxd-agent replaces the
+identity that the guest inherited from the source instance.
+
+The source instance must have migration.stateful enabled.`)
+	cmd.Example = cli.FormatSection("", `lxc fork v1 runner1 runner2
+	Create and start "runner1" and "runner2" from the memory state of "v1".`)
+
+	cmd.RunE = c.run
+	cmd.Flags().BoolVarP(&c.flagEphemeral, "ephemeral", "e", false, "Create ephemeral instances")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdFork) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if strings.Contains(resource.name, shared.SnapshotDelimiter) {
+		return fmt.Errorf("Instance snapshots cannot be forked: %s", resource.name)
+	}
+
+	names := args[1:]
+	for _, name := range names {
+		if strings.Contains(name, ":") {
+			return errors.New("Cannot specify a different remote for the new instances")
+		}
+	}
+
+	req := api.InstanceForkPost{
+		Names:     names,
+		Ephemeral: c.flagEphemeral,
+	}
+
+	op, err := resource.server.ForkInstance(resource.name, req)
+	if err != nil {
+		return err
+	}
+
+	progress := cli.ProgressRenderer{
+		Format: "Forking instance: %s",
+		Quiet:  c.global.flagQuiet,
+	}
+
+	_, err = op.AddHandler(progress.UpdateOp)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	err = cli.CancelableWait(op, &progress)
+	if err != nil {
+		progress.Done("")
+		return err
+	}
+
+	progress.Done("")
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Forked %s into: %s\n", resource.name, strings.Join(names, ", "))
+	}
+
+	return nil
+}
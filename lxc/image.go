@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	cli "github.com/canonical/lxd/shared/cmd"
 	"github.com/canonical/lxd/shared/termios"
+	"github.com/canonical/lxd/shared/trust"
 )
 
 type cmdImage struct {
@@ -50,6 +52,10 @@ hash or alias name (if one is set).`)
 	imageAliasCmd := cmdImageAlias{global: c.global, image: c}
 	cmd.AddCommand(imageAliasCmd.command())
 
+	// Build
+	imageBuildCmd := cmdImageBuild{global: c.global, image: c}
+	cmd.AddCommand(imageBuildCmd.command())
+
 	// Copy
 	imageCopyCmd := cmdImageCopy{global: c.global, image: c}
 	cmd.AddCommand(imageCopyCmd.command())
@@ -82,6 +88,18 @@ hash or alias name (if one is set).`)
 	imageRefreshCmd := cmdImageRefresh{global: c.global, image: c}
 	cmd.AddCommand(imageRefreshCmd.command())
 
+	// Retention
+	imageRetentionCmd := cmdImageRetention{global: c.global, image: c}
+	cmd.AddCommand(imageRetentionCmd.command())
+
+	// SBOM
+	imageSBOMCmd := cmdImageSBOM{global: c.global, image: c}
+	cmd.AddCommand(imageSBOMCmd.command())
+
+	// Sign
+	imageSignCmd := cmdImageSign{global: c.global, image: c}
+	cmd.AddCommand(imageSignCmd.command())
+
 	// Show
 	imageShowCmd := cmdImageShow{global: c.global, image: c}
 	cmd.AddCommand(imageShowCmd.command())
@@ -484,7 +502,8 @@ type cmdImageExport struct {
 	global *cmdGlobal
 	image  *cmdImage
 
-	flagVM bool
+	flagVM      bool
+	flagSignKey string
 }
 
 func (c *cmdImageExport) command() *cobra.Command {
@@ -493,9 +512,13 @@ func (c *cmdImageExport) command() *cobra.Command {
 	cmd.Short = "Export and download images"
 	cmd.Long = cli.FormatSection("Description", cmd.Short+`
 
-The output target is optional and defaults to the working directory.`)
+The output target is optional and defaults to the working directory.
+
+The signatures of the image, if any, are written next to it in a file
+with the ".signatures.json" suffix.`)
 
 	cmd.Flags().BoolVar(&c.flagVM, "vm", false, "Query virtual machine images")
+	cmd.Flags().StringVar(&c.flagSignKey, "sign-key", "", cli.FormatStringFlagLabel("Private key (PEM) to add a signature of the exported image with"))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -551,6 +574,17 @@ func (c *cmdImageExport) run(cmd *cobra.Command, args []string) error {
 	}
 	targetMeta = shared.HostPathFollow(targetMeta)
 	targetRootfs := targetMeta + ".root"
+	targetSignatures := targetMeta + ".signatures.json"
+
+	signatures := image.Signatures
+	if c.flagSignKey != "" {
+		signature, err := imageSignWithKey(c.flagSignKey, image.Fingerprint)
+		if err != nil {
+			return err
+		}
+
+		signatures = append(signatures, *signature)
+	}
 
 	// Prepare the files
 	dest, err := os.Create(targetMeta)
@@ -645,6 +679,20 @@ func (c *cmdImageExport) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Write signatures
+	if len(signatures) > 0 {
+		data, err := json.MarshalIndent(signatures, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile(targetSignatures, data, 0644)
+		if err != nil {
+			progress.Done("")
+			return err
+		}
+	}
+
 	progress.Done("Image exported successfully!")
 	return nil
 }
@@ -654,8 +702,9 @@ type cmdImageImport struct {
 	global *cmdGlobal
 	image  *cmdImage
 
-	flagPublic  bool
-	flagAliases []string
+	flagPublic     bool
+	flagAliases    []string
+	flagSignatures string
 }
 
 func (c *cmdImageImport) command() *cobra.Command {
@@ -670,6 +719,7 @@ Descriptive properties can be set by providing key=value pairs. Example: os=Ubun
 
 	cmd.Flags().BoolVar(&c.flagPublic, "public", false, "Make image public")
 	cmd.Flags().StringArrayVar(&c.flagAliases, "alias", nil, cli.FormatStringFlagLabel("New aliases to add to the image"))
+	cmd.Flags().StringVar(&c.flagSignatures, "signatures", "", cli.FormatStringFlagLabel("File with the signatures of the image (as written by image export)"))
 	cmd.RunE = c.run
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -796,6 +846,10 @@ func (c *cmdImageImport) run(cmd *cobra.Command, args []string) error {
 
 	imageType := "container"
 	if strings.HasPrefix(imageFile, "https://") {
+		if c.flagSignatures != "" {
+			return errors.New("--signatures can't be used when importing from a URL")
+		}
+
 		image.Source = &api.ImagesPostSource{}
 		image.Source.Type = "url"
 		image.Source.Mode = "pull"
@@ -856,6 +910,18 @@ func (c *cmdImageImport) run(cmd *cobra.Command, args []string) error {
 			Type:            imageType,
 		}
 
+		if c.flagSignatures != "" {
+			data, err := os.ReadFile(shared.HostPathFollow(c.flagSignatures))
+			if err != nil {
+				return fmt.Errorf("Failed reading image signatures: %w", err)
+			}
+
+			err = json.Unmarshal(data, &createArgs.Signatures)
+			if err != nil {
+				return fmt.Errorf("Failed parsing image signatures: %w", err)
+			}
+		}
+
 		image.Filename = createArgs.MetaName
 	}
 
@@ -1026,6 +1092,13 @@ func (c *cmdImageInfo) run(cmd *cobra.Command, args []string) error {
 		fmt.Printf("    Alias: %s\n", info.UpdateSource.Alias)
 	}
 
+	if len(info.Signatures) > 0 {
+		fmt.Println("Signatures:")
+		for _, signature := range info.Signatures {
+			fmt.Printf("    - %s\n", signature.KeyFingerprint)
+		}
+	}
+
 	if len(info.Profiles) == 0 {
 		fmt.Print("Profiles: []\n")
 	} else {
@@ -1465,6 +1538,278 @@ func (c *cmdImageRefresh) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// Retention.
+type cmdImageRetention struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagFormat string
+}
+
+func (c *cmdImageRetention) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("retention", "[<remote>:]")
+	cmd.Short = "List images deleted by the retention policy"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The images are deleted by the server once a day according to the
+images.retention.* configuration of the project. Images used by
+instances are never deleted.`)
+
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdImageRetention) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name != "" {
+		return errors.New("Filtering is not supported yet")
+	}
+
+	candidates, err := resource.server.GetImageRetentionCandidates()
+	if err != nil {
+		return err
+	}
+
+	// Render the table.
+	data := [][]string{}
+	for _, candidate := range candidates {
+		data = append(data, []string{candidate.Fingerprint[0:12], candidate.UploadedAt.UTC().Format("Jan 2, 2006 at 3:04pm (MST)"), candidate.Reason})
+	}
+
+	header := []string{
+		"FINGERPRINT",
+		"UPLOAD DATE",
+		"REASON",
+	}
+
+	return cli.RenderTable(c.flagFormat, header, data, candidates)
+}
+
+// SBOM.
+type cmdImageSBOM struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagFormat  string
+	flagRefresh bool
+	flagOutput  string
+}
+
+func (c *cmdImageSBOM) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("sbom", "[<remote>:]<image>")
+	cmd.Short = "Show the software bill of materials of images"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The software bill of materials (SBOM) lists the packages installed in the
+image, as found in its dpkg, rpm or apk database, in the SPDX or CycloneDX
+format. It is generated by the server on first request and stored along
+with the image. Use --refresh to generate it again.`)
+	cmd.Example = cli.FormatSection("", `lxc image sbom ubuntu-24.04
+    Show the SBOM of the ubuntu-24.04 image in the SPDX format.
+
+lxc image sbom ubuntu-24.04 --format=cyclonedx --output=sbom.json
+    Write the SBOM of the ubuntu-24.04 image in the CycloneDX format to sbom.json.`)
+
+	cmd.Flags().StringVar(&c.flagFormat, "format", api.SBOMFormatSPDX, cli.FormatStringFlagLabel("Format of the SBOM (spdx or cyclonedx)"))
+	cmd.Flags().BoolVar(&c.flagRefresh, "refresh", false, "Generate the SBOM again")
+	cmd.Flags().StringVarP(&c.flagOutput, "output", "o", "", cli.FormatStringFlagLabel("File to write the SBOM to instead of standard output"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpImages(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdImageSBOM) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	if !slices.Contains([]string{api.SBOMFormatSPDX, api.SBOMFormatCycloneDX}, c.flagFormat) {
+		return fmt.Errorf("Invalid SBOM format %q", c.flagFormat)
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New("Image identifier missing")
+	}
+
+	image, _, err := c.image.dereferenceAlias(resource.server, "", resource.name)
+	if err != nil {
+		return err
+	}
+
+	if c.flagRefresh {
+		op, err := resource.server.RegenerateImageSBOM(image.Fingerprint, api.ImageSBOMPost{Format: c.flagFormat})
+		if err != nil {
+			return err
+		}
+
+		err = op.Wait()
+		if err != nil {
+			return err
+		}
+	}
+
+	content, err := resource.server.GetImageSBOM(image.Fingerprint, c.flagFormat)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = content.Close() }()
+
+	var target io.Writer = os.Stdout
+	if c.flagOutput != "" {
+		f, err := os.Create(shared.HostPathFollow(c.flagOutput))
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		target = f
+	}
+
+	_, err = io.Copy(target, content)
+	if err != nil {
+		return err
+	}
+
+	if c.flagOutput == "" {
+		fmt.Println("")
+	}
+
+	return nil
+}
+
+// Sign.
+type cmdImageSign struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagKey string
+}
+
+func (c *cmdImageSign) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("sign", "[<remote>:]<image>")
+	cmd.Short = "Sign images"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The image is signed with the given private key, which defaults to the key
+of the client certificate.
+
+To sign an image with the key of the server, use "lxc publish --sign" when
+publishing it from an instance.`)
+
+	cmd.Flags().StringVar(&c.flagKey, "key", "", cli.FormatStringFlagLabel("Private key (PEM) to sign the image with"))
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpImages(toComplete, false)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdImageSign) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name == "" {
+		return errors.New("Image identifier missing")
+	}
+
+	image, _, err := c.image.dereferenceAlias(resource.server, "", resource.name)
+	if err != nil {
+		return err
+	}
+
+	keyPath := c.flagKey
+	if keyPath == "" {
+		keyPath = c.global.conf.ConfigPath("client.key")
+	}
+
+	signature, err := imageSignWithKey(keyPath, image.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	return resource.server.AddImageSignature(image.Fingerprint, api.ImageSignaturesPost{Signature: signature})
+}
+
+// imageSignWithKey signs the image with the given fingerprint with the PEM private key stored at keyPath.
+func imageSignWithKey(keyPath string, fingerprint string) (*api.ImageSignature, error) {
+	content, err := os.ReadFile(shared.HostPathFollow(keyPath))
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading signing key: %w", err)
+	}
+
+	key, err := trust.ParseSigningKey(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return trust.SignImage(key, fingerprint)
+}
+
 // Show.
 type cmdImageShow struct {
 	global *cmdGlobal
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+// imageBuildRecipe is the format of the recipe files used by "lxc image build".
+type imageBuildRecipe struct {
+	Base                 string                 `yaml:"base"`
+	VM                   bool                   `yaml:"vm"`
+	Profiles             []string               `yaml:"profiles"`
+	Config               map[string]string      `yaml:"config"`
+	Environment          map[string]string      `yaml:"environment"`
+	Files                []imageBuildRecipeFile `yaml:"files"`
+	Commands             []string               `yaml:"commands"`
+	Cleanup              []string               `yaml:"cleanup"`
+	Properties           map[string]string      `yaml:"properties"`
+	Aliases              []string               `yaml:"aliases"`
+	CompressionAlgorithm string                 `yaml:"compression_algorithm"`
+	Public               bool                   `yaml:"public"`
+	ExpiresAt            string                 `yaml:"expires_at"`
+}
+
+// imageBuildRecipeFile is a file written into the build instance.
+// Its content is either given inline or read from a local file, relative to the recipe.
+type imageBuildRecipeFile struct {
+	Path    string `yaml:"path"`
+	Source  string `yaml:"source"`
+	Content string `yaml:"content"`
+	Mode    string `yaml:"mode"`
+	UID     int64  `yaml:"uid"`
+	GID     int64  `yaml:"gid"`
+}
+
+// Build.
+type cmdImageBuild struct {
+	global *cmdGlobal
+	image  *cmdImage
+
+	flagAliases              []string
+	flagCompressionAlgorithm string
+	flagMakePublic           bool
+	flagReuse                bool
+}
+
+func (c *cmdImageBuild) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("build", "<recipe> [<remote>:]")
+	cmd.Short = "Build images from a recipe"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+The recipe is a YAML file describing the base image, files to write,
+commands to run and cleanup commands. The server runs those steps in a
+temporary instance and publishes the result as a new image.`)
+	cmd.Example = cli.FormatSection("", `lxc image build recipe.yaml
+    Build an image from the following recipe:
+
+    base: ubuntu:24.04
+    environment:
+      DEBIAN_FRONTEND: noninteractive
+    files:
+      - path: /etc/motd
+        content: "Built by LXD\n"
+      - path: /etc/nginx/sites-enabled/default
+        source: ./nginx.conf
+        mode: "0644"
+    commands:
+      - apt-get update
+      - apt-get install -y nginx
+    cleanup:
+      - apt-get clean
+    properties:
+      os: Ubuntu
+      release: noble
+      variant: nginx
+    aliases:
+      - nginx`)
+
+	cmd.Flags().StringArrayVar(&c.flagAliases, "alias", nil, cli.FormatStringFlagLabel("New alias to define at target"))
+	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", cli.FormatStringFlagLabel("Compression algorithm to use (`none` for uncompressed)"))
+	cmd.Flags().BoolVar(&c.flagMakePublic, "public", false, "Make the image public (accessible to unauthenticated clients as well)")
+	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, "If the image alias already exists, move it to the new image")
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parseImageBuildRecipe reads the recipe at the given path.
+func parseImageBuildRecipe(path string) (*imageBuildRecipe, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := imageBuildRecipe{}
+	err = yaml.UnmarshalStrict(content, &recipe)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing recipe %q: %w", path, err)
+	}
+
+	if recipe.Base == "" {
+		return nil, fmt.Errorf("Recipe %q doesn't have a base image", path)
+	}
+
+	// Load the content of local files relative to the recipe.
+	for i, file := range recipe.Files {
+		if file.Source == "" {
+			continue
+		}
+
+		if file.Content != "" {
+			return nil, fmt.Errorf("File %q can't have both a source and a content", file.Path)
+		}
+
+		source := file.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(filepath.Dir(path), source)
+		}
+
+		content, err := os.ReadFile(shared.HostPathFollow(source))
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading source of file %q: %w", file.Path, err)
+		}
+
+		recipe.Files[i].Content = string(content)
+	}
+
+	return &recipe, nil
+}
+
+func (c *cmdImageBuild) run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 2)
+	if exit {
+		return err
+	}
+
+	recipe, err := parseImageBuildRecipe(args[0])
+	if err != nil {
+		return err
+	}
+
+	// Parse remote
+	remote := ""
+	if len(args) > 1 {
+		remote = args[1]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+	if resource.name != "" {
+		return errors.New("Cannot provide a name for the target image")
+	}
+
+	d := resource.server
+
+	// Resolve the base image, the same way as when launching an instance.
+	iremote, image, err := conf.ParseRemote(recipe.Base)
+	if err != nil {
+		return err
+	}
+
+	iremote, image = guessImage(conf, d, resource.remote, iremote, image)
+
+	build := &api.ImageBuildRecipe{
+		Type:        api.InstanceTypeContainer,
+		Profiles:    recipe.Profiles,
+		Config:      recipe.Config,
+		Environment: recipe.Environment,
+		Commands:    recipe.Commands,
+		Cleanup:     recipe.Cleanup,
+	}
+
+	if recipe.VM {
+		build.Type = api.InstanceTypeVM
+	}
+
+	for _, file := range recipe.Files {
+		build.Files = append(build.Files, api.ImageBuildFile{
+			Path:    file.Path,
+			Content: file.Content,
+			Mode:    file.Mode,
+			UID:     file.UID,
+			GID:     file.GID,
+		})
+	}
+
+	imgServer, imgInfo, err := getImgInfo(conf, iremote, image, "", &build.Source)
+	if err != nil {
+		return err
+	}
+
+	req := api.ImagesPost{
+		Source: &api.ImagesPostSource{
+			Type:  "build",
+			Build: build,
+		},
+		CompressionAlgorithm: recipe.CompressionAlgorithm,
+	}
+
+	if c.flagCompressionAlgorithm != "" {
+		req.CompressionAlgorithm = c.flagCompressionAlgorithm
+	}
+
+	req.Properties = recipe.Properties
+	req.Public = recipe.Public || c.flagMakePublic
+
+	if recipe.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, recipe.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("Invalid expiration date: %w", err)
+		}
+
+		req.ExpiresAt = expiresAt
+	}
+
+	// Reformat aliases
+	aliases := []api.ImageAlias{}
+	for _, entry := range append(recipe.Aliases, c.flagAliases...) {
+		aliases = append(aliases, api.ImageAlias{Name: entry})
+	}
+
+	existingAliases, err := GetCommonAliases(d, aliases...)
+	if err != nil {
+		return fmt.Errorf("Error retrieving aliases: %w", err)
+	}
+
+	if !c.flagReuse && len(existingAliases) > 0 {
+		names := []string{}
+		for _, alias := range existingAliases {
+			names = append(names, alias.Name)
+		}
+
+		return fmt.Errorf("Aliases already exists: %s", strings.Join(names, ", "))
+	}
+
+	op, err := d.BuildImage(imgServer, *imgInfo, req)
+	if err != nil {
+		return err
+	}
+
+	// Print the build steps and the output of the build commands as they come.
+	var lastStep string
+	var lastLine float64
+	_, err = op.AddHandler(func(op api.Operation) {
+		if c.global.flagQuiet {
+			return
+		}
+
+		step, _ := op.Metadata["build_step"].(string)
+		if step != "" && step != lastStep {
+			lastStep = step
+			fmt.Println("==> " + step)
+		}
+
+		lines, _ := op.Metadata["build_log_lines"].(float64)
+		line, _ := op.Metadata["build_log_line"].(string)
+		if lines > lastLine {
+			lastLine = lines
+			fmt.Println(line)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	err = cli.CancelableWait(op, nil)
+	if err != nil {
+		return err
+	}
+
+	opAPI := op.Get()
+
+	// Grab the fingerprint
+	fingerprint, ok := opAPI.Metadata["fingerprint"].(string)
+	if !ok {
+		return fmt.Errorf(`Invalid type %T for "fingerprint" key in operation metadata`, fingerprint)
+	}
+
+	// Existing aliases are moved to the new image.
+	err = ensureImageAliases(d, aliases, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Image built with fingerprint: %s\n", fingerprint)
+
+	return nil
+}
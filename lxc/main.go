@@ -148,6 +148,10 @@ For help with any of those, simply call them with --help.`)
 	aliasCmd := cmdAlias{global: &globalCmd}
 	app.AddCommand(aliasCmd.command())
 
+	// audit sub-command
+	auditCmd := cmdAudit{global: &globalCmd}
+	app.AddCommand(auditCmd.command())
+
 	// cluster sub-command
 	clusterCmd := cmdCluster{global: &globalCmd}
 	app.AddCommand(clusterCmd.command())
@@ -180,6 +184,10 @@ For help with any of those, simply call them with --help.`)
 	fileCmd := cmdFile{global: &globalCmd}
 	app.AddCommand(fileCmd.command())
 
+	// fork sub-command
+	forkCmd := cmdFork{global: &globalCmd}
+	app.AddCommand(forkCmd.command())
+
 	// import sub-command
 	importCmd := cmdImport{global: &globalCmd}
 	app.AddCommand(importCmd.command())
@@ -228,6 +236,10 @@ For help with any of those, simply call them with --help.`)
 	pauseCmd := cmdPause{global: &globalCmd}
 	app.AddCommand(pauseCmd.command())
 
+	// port-forward sub-command
+	portForwardCmd := cmdPortForward{global: &globalCmd}
+	app.AddCommand(portForwardCmd.command())
+
 	// publish sub-command
 	publishCmd := cmdPublish{global: &globalCmd}
 	app.AddCommand(publishCmd.command())
@@ -298,6 +310,9 @@ For help with any of those, simply call them with --help.`)
 	placementGroupCmd := cmdPlacementGroup{global: &globalCmd}
 	app.AddCommand(placementGroupCmd.command())
 
+	webhookCmd := cmdWebhook{global: &globalCmd}
+	app.AddCommand(webhookCmd.command())
+
 	// Get help command
 	app.InitDefaultHelpCmd()
 	var help *cobra.Command
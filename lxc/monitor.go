@@ -24,6 +24,7 @@ type cmdMonitor struct {
 	flagLogLevel    string
 	flagAllProjects bool
 	flagFormat      string
+	flagSince       string
 }
 
 func (c *cmdMonitor) command() *cobra.Command {
@@ -40,7 +41,10 @@ lxc monitor --pretty --type=logging --loglevel=info
     Show a pretty log of messages with info level or higher.
 
 lxc monitor --type=lifecycle
-    Only show lifecycle events.`)
+    Only show lifecycle events.
+
+lxc monitor --since=5c8c2b0e-5b8a-4a4f-9c43-1c5e0d3f2a61:1042
+    Show the events following the given event, then new events.`)
 
 	cmd.RunE = c.run
 	cmd.Flags().BoolVar(&c.flagPretty, "pretty", false, "Pretty rendering (short for --format=pretty)")
@@ -48,6 +52,7 @@ lxc monitor --type=lifecycle
 	cmd.Flags().StringArrayVar(&c.flagType, "type", nil, cli.FormatStringFlagLabel("Event type to listen for"))
 	cmd.Flags().StringVar(&c.flagLogLevel, "loglevel", "", cli.FormatStringFlagLabel("Minimum level for log messages (only available when using pretty format)"))
 	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "yaml", cli.FormatStringFlagLabel("Format (json|pretty|yaml)"))
+	cmd.Flags().StringVar(&c.flagSince, "since", "", cli.FormatStringFlagLabel("Replay the events following the event with this ID"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
 		if len(args) > 0 {
@@ -104,7 +109,13 @@ func (c *cmdMonitor) run(cmd *cobra.Command, args []string) error {
 	}
 
 	var listener *lxd.EventListener
-	if c.flagAllProjects {
+	if c.flagSince != "" {
+		if c.flagAllProjects {
+			listener, err = d.GetEventsAllProjectsSince(c.flagSince)
+		} else {
+			listener, err = d.GetEventsSince(c.flagSince)
+		}
+	} else if c.flagAllProjects {
 		listener, err = d.GetEventsAllProjects()
 	} else {
 		listener, err = d.GetEvents()
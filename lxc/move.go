@@ -8,6 +8,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
 	cli "github.com/canonical/lxd/shared/cmd"
@@ -27,6 +28,8 @@ type cmdMove struct {
 	flagTarget            string
 	flagTargetProject     string
 	flagAllowInconsistent bool
+	flagTargetClusterLink string
+	flagKeepSource        bool
 }
 
 func (c *cmdMove) command() *cobra.Command {
@@ -50,7 +53,10 @@ lxc move <old name> <new name> [--instance-only]
     Rename a local instance.
 
 lxc move <instance>/<old snapshot name> <instance>/<new snapshot name>
-    Rename a snapshot.`)
+    Rename a snapshot.
+
+lxc move [<remote>:]<instance> --target-cluster-link <link> [--keep-source]
+    Migrate an instance to the cluster of a cluster link, without the client connecting to that cluster.`)
 
 	cmd.RunE = c.run
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, cli.FormatStringFlagLabel("Config key/value to apply to the target instance"))
@@ -64,6 +70,8 @@ lxc move <instance>/<old snapshot name> <instance>/<new snapshot name>
 	cmd.Flags().StringVar(&c.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member name"))
 	cmd.Flags().StringVar(&c.flagTargetProject, "target-project", "", cli.FormatStringFlagLabel("Copy to a project different from the source"))
 	cmd.Flags().BoolVar(&c.flagAllowInconsistent, "allow-inconsistent", false, "Ignore copy errors for volatile files")
+	cmd.Flags().StringVar(&c.flagTargetClusterLink, "target-cluster-link", "", cli.FormatStringFlagLabel("Cluster link to migrate the instance to"))
+	cmd.Flags().BoolVar(&c.flagKeepSource, "keep-source", false, "Keep the source instance when migrating to another cluster")
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -89,7 +97,7 @@ func (c *cmdMove) run(cmd *cobra.Command, args []string) error {
 	conf := c.global.conf
 
 	// Quick checks.
-	if c.flagTarget == "" && c.flagTargetProject == "" && c.flagStorage == "" {
+	if c.flagTarget == "" && c.flagTargetProject == "" && c.flagStorage == "" && c.flagTargetClusterLink == "" {
 		exit, err := c.global.CheckArgs(cmd, args, 2, 2)
 		if exit {
 			return err
@@ -122,6 +130,28 @@ func (c *cmdMove) run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if c.flagKeepSource && c.flagTargetClusterLink == "" {
+		return errors.New("--keep-source can only be used with --target-cluster-link")
+	}
+
+	// Migrations to another cluster are run by the source server, through the cluster link.
+	if c.flagTargetClusterLink != "" {
+		if c.flagTarget != "" {
+			return errors.New("--target cannot be used with --target-cluster-link")
+		}
+
+		if sourceRemote != destRemote {
+			return errors.New("The destination remote must be the same as the source with --target-cluster-link")
+		}
+
+		destResource := args[0]
+		if len(args) == 2 {
+			destResource = args[1]
+		}
+
+		return c.moveInstance(args[0], destResource, !c.flagStateless)
+	}
+
 	// As an optimization, if the source and destination are the same, do
 	// this via a simple rename. This only works for instances that aren't
 	// running, instances that are running should be live migrated (of
@@ -306,6 +336,7 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 		Pool:         c.flagStorage,
 		Project:      c.flagTargetProject,
 		Live:         stateful,
+		KeepSource:   c.flagKeepSource,
 	}
 
 	// Override profiles.
@@ -366,7 +397,13 @@ func (c *cmdMove) moveInstance(sourceResource string, destResource string, state
 	}
 
 	// Move the instance.
-	op, err := source.MigrateInstance(sourceName, req)
+	var op lxd.Operation
+	if c.flagTargetClusterLink != "" {
+		op, err = source.MigrateInstanceToClusterLink(sourceName, c.flagTargetClusterLink, req)
+	} else {
+		op, err = source.MigrateInstance(sourceName, req)
+	}
+
 	if err != nil {
 		return fmt.Errorf("Migration API failure: %w", err)
 	}
@@ -24,6 +24,10 @@ func (c *cmdOperation) command() *cobra.Command {
 	cmd.Short = "Manage background operations"
 	cmd.Long = cli.FormatSection("Description", cmd.Short)
 
+	// Approve
+	operationApproveCmd := cmdOperationApprove{global: c.global, operation: c, approved: true}
+	cmd.AddCommand(operationApproveCmd.command())
+
 	// Delete
 	operationDeleteCmd := cmdOperationDelete{global: c.global, operation: c}
 	cmd.AddCommand(operationDeleteCmd.command())
@@ -36,6 +40,10 @@ func (c *cmdOperation) command() *cobra.Command {
 	operationListChildrenCmd := cmdOperationListChildren{global: c.global, operation: c}
 	cmd.AddCommand(operationListChildrenCmd.command())
 
+	// Reject
+	operationRejectCmd := cmdOperationApprove{global: c.global, operation: c, approved: false}
+	cmd.AddCommand(operationRejectCmd.command())
+
 	// Show
 	operationShowCmd := cmdOperationShow{global: c.global, operation: c}
 	cmd.AddCommand(operationShowCmd.command())
@@ -46,6 +54,71 @@ func (c *cmdOperation) command() *cobra.Command {
 	return cmd
 }
 
+// Approve and reject.
+type cmdOperationApprove struct {
+	global    *cmdGlobal
+	operation *cmdOperation
+	approved  bool
+
+	flagReason string
+}
+
+func (c *cmdOperationApprove) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	if c.approved {
+		cmd.Use = usage("approve", "[<remote>:]<operation>")
+		cmd.Short = "Approve a background operation pending approval"
+		cmd.Example = cli.FormatSection("", `lxc operation approve 344a79e4-d88a-45bf-9c39-c72c26f6ab8a --reason "Planned decommissioning"
+    Approve the operation, which then starts running`)
+	} else {
+		cmd.Use = usage("reject", "[<remote>:]<operation>")
+		cmd.Short = "Reject a background operation pending approval"
+		cmd.Example = cli.FormatSection("", `lxc operation reject 344a79e4-d88a-45bf-9c39-c72c26f6ab8a --reason "Instance still in use"
+    Reject the operation, which then fails`)
+	}
+
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+Operations pending approval must be approved or rejected by a different identity than the one that requested them.`)
+	cmd.Flags().StringVar(&c.flagReason, "reason", "", cli.FormatStringFlagLabel("Reason for the decision"))
+
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdOperationApprove) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// Approve or reject the operation
+	err = resource.server.ApproveOperation(resource.name, api.OperationApprovePost{Approved: c.approved, Reason: c.flagReason})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		if c.approved {
+			fmt.Printf("Operation %s approved\n", resource.name)
+		} else {
+			fmt.Printf("Operation %s rejected\n", resource.name)
+		}
+	}
+
+	return nil
+}
+
 // Delete.
 type cmdOperationDelete struct {
 	global    *cmdGlobal
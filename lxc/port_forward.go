@@ -0,0 +1,346 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+type cmdPortForward struct {
+	global *cmdGlobal
+
+	flagProtocol string
+	flagAddress  string
+}
+
+// portForwardSpec is a local address forwarded to a port inside the instance.
+type portForwardSpec struct {
+	listenAddress string
+	port          int
+}
+
+func (c *cmdPortForward) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("port-forward", "[<remote>:]<instance> [<local address>:]<local port>[:<instance port>]...")
+	cmd.Short = "Forward local ports to instances"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+Listens on local ports and forwards each connection to a port inside the instance
+through the LXD API, without changing the instance configuration.
+
+The local address defaults to 127.0.0.1 and the instance port to the local port.
+Port forwarding runs until interrupted.`)
+	cmd.Example = cli.FormatSection("", `lxc port-forward c1 8080:80
+   Forward connections to port 8080 on 127.0.0.1 to port 80 inside instance c1.
+
+lxc port-forward c1 0.0.0.0:5353:53 --protocol udp
+   Forward UDP packets sent to port 5353 on any address to port 53 inside instance c1.`)
+
+	cmd.Flags().StringVar(&c.flagProtocol, "protocol", "tcp", cli.FormatStringFlagLabel("Protocol of the forwarded ports (tcp or udp)"))
+	cmd.Flags().StringVar(&c.flagAddress, "address", "127.0.0.1", cli.FormatStringFlagLabel("Address to connect to inside the instance"))
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("instance", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// parsePortForwardSpec parses a "[<local address>:]<local port>[:<instance port>]" port specification.
+func parsePortForwardSpec(spec string) (*portForwardSpec, error) {
+	host := "127.0.0.1"
+	fields := strings.Split(spec, ":")
+
+	// IPv6 local addresses are enclosed in square brackets.
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]:")
+		if end < 0 {
+			return nil, fmt.Errorf("Invalid port specification %q", spec)
+		}
+
+		host = spec[1:end]
+		fields = strings.Split(spec[end+2:], ":")
+	} else if len(fields) == 3 {
+		host = fields[0]
+		fields = fields[1:]
+	}
+
+	if len(fields) > 2 {
+		return nil, fmt.Errorf("Invalid port specification %q", spec)
+	}
+
+	localPort := fields[0]
+	instancePort := fields[len(fields)-1]
+
+	_, err := strconv.ParseUint(localPort, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid local port in %q", spec)
+	}
+
+	port, err := strconv.ParseUint(instancePort, 10, 16)
+	if err != nil || port == 0 {
+		return nil, fmt.Errorf("Invalid instance port in %q", spec)
+	}
+
+	return &portForwardSpec{listenAddress: net.JoinHostPort(host, localPort), port: int(port)}, nil
+}
+
+func (c *cmdPortForward) run(cmd *cobra.Command, args []string) error {
+	conf := c.global.conf
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Validate flags.
+	if !slices.Contains([]string{"tcp", "udp"}, c.flagProtocol) {
+		return fmt.Errorf("Unsupported protocol %q", c.flagProtocol)
+	}
+
+	specs := make([]*portForwardSpec, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		spec, err := parsePortForwardSpec(arg)
+		if err != nil {
+			return err
+		}
+
+		specs = append(specs, spec)
+	}
+
+	// Connect to LXD.
+	remote, name, err := conf.ParseRemote(args[0])
+	if err != nil {
+		return err
+	}
+
+	d, err := conf.GetInstanceServer(remote)
+	if err != nil {
+		return err
+	}
+
+	// Create a context that is canceled on signal reception.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	wg := sync.WaitGroup{}
+	errs := make([]error, len(specs))
+	for i, spec := range specs {
+		err := c.forward(ctx, d, name, spec, &wg, &errs[i])
+		if err != nil {
+			cancel()
+			wg.Wait()
+			return err
+		}
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// forward starts forwarding a local address to the instance until the context is cancelled or the operation ends.
+func (c *cmdPortForward) forward(ctx context.Context, d lxd.InstanceServer, name string, spec *portForwardSpec, wg *sync.WaitGroup, result *error) error {
+	lc := net.ListenConfig{}
+
+	var listener net.Listener
+	var packetConn net.PacketConn
+	var err error
+	var localAddress string
+	if c.flagProtocol == "udp" {
+		packetConn, err = lc.ListenPacket(ctx, "udp", spec.listenAddress)
+		if err != nil {
+			return err
+		}
+
+		localAddress = packetConn.LocalAddr().String()
+	} else {
+		listener, err = lc.Listen(ctx, "tcp", spec.listenAddress)
+		if err != nil {
+			return err
+		}
+
+		localAddress = listener.Addr().String()
+	}
+
+	closeLocal := func() {
+		if listener != nil {
+			_ = listener.Close()
+		} else {
+			_ = packetConn.Close()
+		}
+	}
+
+	req := api.InstancePortForwardPost{
+		Protocol: c.flagProtocol,
+		Address:  c.flagAddress,
+		Port:     spec.port,
+	}
+
+	disconnect := make(chan bool)
+	op, connect, err := d.PortForwardInstance(name, req, &lxd.InstancePortForwardArgs{Disconnect: disconnect})
+	if err != nil {
+		closeLocal()
+		return err
+	}
+
+	fmt.Printf("Forwarding %s %s to %s in instance %s\n", c.flagProtocol, localAddress, net.JoinHostPort(c.flagAddress, strconv.Itoa(spec.port)), name)
+
+	// Stop listening and end the operation when interrupted, or stop listening when the operation ends.
+	opDone := make(chan error, 1)
+	go func() {
+		opDone <- op.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		select {
+		case <-ctx.Done():
+			closeLocal()
+			close(disconnect)
+			*result = <-opDone
+		case err := <-opDone:
+			closeLocal()
+			close(disconnect)
+			*result = err
+		}
+	}()
+
+	if listener != nil {
+		go c.forwardStream(listener, connect)
+	} else {
+		go c.forwardPackets(packetConn, connect)
+	}
+
+	return nil
+}
+
+// forwardStream forwards each accepted connection over its own websocket.
+func (c *cmdPortForward) forwardStream(listener net.Listener, connect func() (*websocket.Conn, error)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			wsConn, err := connect()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed forwarding connection from %s: %v\n", conn.RemoteAddr(), err)
+				_ = conn.Close()
+				return
+			}
+
+			defer func() { _ = wsConn.Close() }()
+
+			err = ws.Forward(wsConn, conn)
+			if err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				fmt.Fprintf(os.Stderr, "Failed forwarding connection from %s: %v\n", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// forwardPackets forwards the datagrams of each local peer over its own websocket.
+func (c *cmdPortForward) forwardPackets(packetConn net.PacketConn, connect func() (*websocket.Conn, error)) {
+	peers := map[string]*websocket.Conn{}
+	peersLock := sync.Mutex{}
+
+	defer func() {
+		peersLock.Lock()
+		defer peersLock.Unlock()
+
+		for _, conn := range peers {
+			_ = conn.Close()
+		}
+	}()
+
+	buf := make([]byte, 65535)
+	for {
+		n, peer, err := packetConn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		peersLock.Lock()
+		conn := peers[peer.String()]
+		peersLock.Unlock()
+
+		if conn == nil {
+			conn, err = connect()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed forwarding datagrams from %s: %v\n", peer, err)
+				continue
+			}
+
+			peersLock.Lock()
+			peers[peer.String()] = conn
+			peersLock.Unlock()
+
+			// Send the replies back to the peer.
+			go func() {
+				defer func() {
+					peersLock.Lock()
+					delete(peers, peer.String())
+					peersLock.Unlock()
+
+					_ = conn.Close()
+				}()
+
+				for {
+					mt, data, err := conn.ReadMessage()
+					if err != nil {
+						if !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+							logger.Debug("Stopped forwarding datagrams", logger.Ctx{"peer": peer.String(), "err": err})
+						}
+
+						return
+					}
+
+					if mt != websocket.BinaryMessage {
+						return
+					}
+
+					_, err = packetConn.WriteTo(data, peer)
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+
+		err = conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed forwarding datagram from %s: %v\n", peer, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+)
+
+func Test_parsePortForwardSpec(t *testing.T) {
+	tests := []struct {
+		spec          string
+		listenAddress string
+		port          int
+		wantErr       bool
+	}{
+		{spec: "8080", listenAddress: "127.0.0.1:8080", port: 8080},
+		{spec: "8080:80", listenAddress: "127.0.0.1:8080", port: 80},
+		{spec: "0:80", listenAddress: "127.0.0.1:0", port: 80},
+		{spec: "0.0.0.0:5353:53", listenAddress: "0.0.0.0:5353", port: 53},
+		{spec: "[::1]:8080:80", listenAddress: "[::1]:8080", port: 80},
+		{spec: "[::1]:8080", listenAddress: "[::1]:8080", port: 8080},
+		{spec: "8080:0", wantErr: true},
+		{spec: "foo:80", wantErr: true},
+		{spec: "8080:70000", wantErr: true},
+		{spec: "[::1]8080", wantErr: true},
+		{spec: "a:1:2:3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := parsePortForwardSpec(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", spec)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if spec.listenAddress != tt.listenAddress || spec.port != tt.port {
+				t.Errorf("Expected %s to port %d, got %s to port %d", tt.listenAddress, tt.port, spec.listenAddress, spec.port)
+			}
+		})
+	}
+}
@@ -24,6 +24,8 @@ type cmdPublish struct {
 	flagMakePublic           bool
 	flagForce                bool
 	flagReuse                bool
+	flagSign                 bool
+	flagSignKey              string
 }
 
 func (c *cmdPublish) command() *cobra.Command {
@@ -39,6 +41,8 @@ func (c *cmdPublish) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagCompressionAlgorithm, "compression", "", cli.FormatStringFlagLabel("Compression algorithm to use (`none` for uncompressed)"))
 	cmd.Flags().StringVar(&c.flagExpiresAt, "expire", "", cli.FormatStringFlagLabel("Image expiration date (format: rfc3339)"))
 	cmd.Flags().BoolVar(&c.flagReuse, "reuse", false, "If the image alias already exists, delete and create a new one")
+	cmd.Flags().BoolVar(&c.flagSign, "sign", false, "Sign the image with the key of the server")
+	cmd.Flags().StringVar(&c.flagSignKey, "sign-key", "", cli.FormatStringFlagLabel("Private key (PEM) to sign the image with"))
 
 	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) == 0 {
@@ -217,6 +221,7 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 			Name: cName,
 		},
 		CompressionAlgorithm: c.flagCompressionAlgorithm,
+		Sign:                 c.flagSign,
 	}
 
 	req.Properties = properties
@@ -288,6 +293,19 @@ func (c *cmdPublish) run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(`Invalid type %T for "fingerprint" key in operation metadata`, fingerprint)
 	}
 
+	// Sign the image with the client key, before it is copied so that the signature follows it
+	if c.flagSignKey != "" {
+		signature, err := imageSignWithKey(c.flagSignKey, fingerprint)
+		if err != nil {
+			return err
+		}
+
+		err = s.AddImageSignature(fingerprint, api.ImageSignaturesPost{Signature: signature})
+		if err != nil {
+			return err
+		}
+	}
+
 	// For remote publish, copy to target now
 	if cRemote != iRemote {
 		defer func() { _, _ = s.DeleteImage(fingerprint) }()
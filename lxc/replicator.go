@@ -900,6 +900,20 @@ func (c *cmdReplicatorInfo) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Render the outcome of the last run for the instance dependencies as a table.
+	if len(state.Entities) > 0 {
+		fmt.Println("Dependencies:")
+		entityData := make([][]string, 0, len(state.Entities))
+		for _, replicatorEntity := range state.Entities {
+			entityData = append(entityData, []string{replicatorEntity.EntityURL, replicatorEntity.Status, replicatorEntity.Message})
+		}
+
+		err = cli.RenderTable(cli.TableFormatTable, []string{"ENTITY", "STATUS", "MESSAGE"}, entityData, state.Entities)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,844 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v2"
+
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/termios"
+)
+
+type cmdWebhook struct {
+	global *cmdGlobal
+}
+
+func (c *cmdWebhook) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("webhook")
+	cmd.Short = "Manage webhooks"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	// List.
+	webhookListCmd := cmdWebhookList{global: c.global, webhook: c}
+	cmd.AddCommand(webhookListCmd.command())
+
+	// Show.
+	webhookShowCmd := cmdWebhookShow{global: c.global, webhook: c}
+	cmd.AddCommand(webhookShowCmd.command())
+
+	// Create.
+	webhookCreateCmd := cmdWebhookCreate{global: c.global, webhook: c}
+	cmd.AddCommand(webhookCreateCmd.command())
+
+	// Edit.
+	webhookEditCmd := cmdWebhookEdit{global: c.global, webhook: c}
+	cmd.AddCommand(webhookEditCmd.command())
+
+	// Get.
+	webhookGetCmd := cmdWebhookGet{global: c.global, webhook: c}
+	cmd.AddCommand(webhookGetCmd.command())
+
+	// Set.
+	webhookSetCmd := cmdWebhookSet{global: c.global, webhook: c}
+	cmd.AddCommand(webhookSetCmd.command())
+
+	// Unset.
+	webhookUnsetCmd := cmdWebhookUnset{global: c.global, webhook: c, webhookSet: &webhookSetCmd}
+	cmd.AddCommand(webhookUnsetCmd.command())
+
+	// Delete.
+	webhookDeleteCmd := cmdWebhookDelete{global: c.global, webhook: c}
+	cmd.AddCommand(webhookDeleteCmd.command())
+
+	// Rename.
+	webhookRenameCmd := cmdWebhookRename{global: c.global, webhook: c}
+	cmd.AddCommand(webhookRenameCmd.command())
+
+	// Info.
+	webhookInfoCmd := cmdWebhookInfo{global: c.global, webhook: c}
+	cmd.AddCommand(webhookInfoCmd.command())
+
+	// Workaround for subcommand usage errors. See: https://github.com/spf13/cobra/issues/706
+	cmd.Args = cobra.NoArgs
+	cmd.Run = func(cmd *cobra.Command, args []string) { _ = cmd.Usage() }
+	return cmd
+}
+
+// List.
+type cmdWebhookList struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagFormat  string
+	flagColumns string
+}
+
+// columns returns the ordered column definitions for webhook list.
+func (c *cmdWebhookList) columns() []cli.ShorthandColumn[api.Webhook] {
+	return []cli.ShorthandColumn[api.Webhook]{
+		{Shorthand: 'n', Name: "NAME", Data: c.nameColumnData},
+		{Shorthand: 'd', Name: "DESCRIPTION", Data: c.descriptionColumnData},
+		{Shorthand: 'u', Name: "URL", Data: c.urlColumnData},
+		{Shorthand: 't', Name: "TYPES", Data: c.typesColumnData},
+	}
+}
+
+func (c *cmdWebhookList) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("list", "[<remote>:]")
+	cmd.Aliases = []string{"ls"}
+	cmd.Short = "List available webhooks"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", "table", cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().StringVarP(&c.flagColumns, "columns", "c", cli.DefaultColumnString(c.columns()), cli.FormatStringFlagLabel("Columns"))
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpRemotes(toComplete, ":", true, instanceServerRemoteCompletionFilters(*c.global.conf)...)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookList) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 0, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	resources, err := c.global.ParseServers(remote)
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	// List the webhooks.
+	if resource.name != "" {
+		return errors.New("Filtering is not supported yet")
+	}
+
+	webhooks, err := resource.server.GetWebhooks()
+	if err != nil {
+		return err
+	}
+
+	// Parse column flags.
+	columns, err := cli.ParseShorthandColumns(c.flagColumns, c.columns())
+	if err != nil {
+		return err
+	}
+
+	data := cli.ColumnData(columns, webhooks)
+	sort.Sort(cli.SortColumnsNaturally(data))
+	header := cli.ColumnHeaders(columns)
+
+	return cli.RenderTable(c.flagFormat, header, data, webhooks)
+}
+
+func (c *cmdWebhookList) nameColumnData(webhook api.Webhook) string {
+	return webhook.Name
+}
+
+func (c *cmdWebhookList) descriptionColumnData(webhook api.Webhook) string {
+	return webhook.Description
+}
+
+func (c *cmdWebhookList) urlColumnData(webhook api.Webhook) string {
+	return webhook.Config["url"]
+}
+
+func (c *cmdWebhookList) typesColumnData(webhook api.Webhook) string {
+	return webhook.Config["types"]
+}
+
+// Show.
+type cmdWebhookShow struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookShow) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("show", "[<remote>:]<webhook>")
+	cmd.Short = "Show webhook configurations"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookShow) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Show the webhook config.
+	webhook, _, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", data)
+
+	return nil
+}
+
+// Create.
+type cmdWebhookCreate struct {
+	global          *cmdGlobal
+	webhook         *cmdWebhook
+	flagConfig      []string
+	flagDescription string
+}
+
+func (c *cmdWebhookCreate) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("create", "[<remote>:]<webhook> [key=value...]")
+	cmd.Short = "Create new webhook"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.Example = cli.FormatSection("", `lxc webhook create chat-ops url=https://chat.example.com/hooks/lxd secret=s3cr3t
+    Create webhook chat-ops sending lifecycle and security events, signed with the given secret
+
+lxc webhook create chat-ops url=https://chat.example.com/hooks/lxd types=lifecycle lifecycle.actions=instance-*
+    Create webhook chat-ops only sending the lifecycle events of instances
+
+lxc webhook create policy url=https://policy.example.com/lxd type=admission admission.entities=instance
+    Create admission webhook policy reviewing the creation and update of instances
+
+lxc webhook create chat-ops < config.yaml
+    Create webhook chat-ops with configuration from config.yaml`)
+
+	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, cli.FormatStringFlagLabel("Config key/value to apply to the new webhook"))
+	cmd.Flags().StringVar(&c.flagDescription, "description", "", cli.FormatStringFlagLabel("Description of the webhook"))
+	cmd.RunE = c.run
+
+	return cmd
+}
+
+func (c *cmdWebhookCreate) run(cmd *cobra.Command, args []string) error {
+	var stdinData api.WebhookPut
+
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, -1)
+	if exit {
+		return err
+	}
+
+	// If stdin isn't a terminal, read yaml from it.
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		err = yaml.UnmarshalStrict(contents, &stdinData)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Create the webhook.
+	webhook := api.WebhooksPost{}
+	webhook.Name = resource.name
+	webhook.WebhookPut = stdinData
+
+	if webhook.Config == nil {
+		webhook.Config = map[string]string{}
+	}
+
+	// Parse config from command line arguments.
+	for _, entry := range args[1:] {
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			return fmt.Errorf("Bad key=value pair: %q", entry)
+		}
+
+		webhook.Config[key] = value
+	}
+
+	// Parse config from flags.
+	for _, entry := range c.flagConfig {
+		key, value, found := strings.Cut(entry, "=")
+		if !found {
+			return fmt.Errorf("Bad key=value pair: %q", entry)
+		}
+
+		webhook.Config[key] = value
+	}
+
+	if c.flagDescription != "" {
+		webhook.Description = c.flagDescription
+	}
+
+	err = resource.server.CreateWebhook(webhook)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Webhook %s created\n", resource.name)
+	}
+
+	return nil
+}
+
+// Edit.
+type cmdWebhookEdit struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookEdit) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("edit", "[<remote>:]<webhook>")
+	cmd.Short = "Edit webhook configurations as YAML"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookEdit) helpTemplate() string {
+	return `### This is a YAML representation of the webhook.
+### Any line starting with a '#' will be ignored.
+###
+### An example webhook structure is shown below.
+### The name field cannot be modified.
+###
+### name: chat-ops
+### description: Notify the operations channel
+### config:
+###   url: https://chat.example.com/hooks/lxd
+###   types: lifecycle
+###   projects: default,production
+###   lifecycle.actions: instance-created,instance-deleted
+###   secret: s3cr3t
+`
+}
+
+func (c *cmdWebhookEdit) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// If stdin isn't a terminal, read text from it
+	if !termios.IsTerminal(getStdinFd()) {
+		contents, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		// Allow output of `lxc webhook show` command to be passed in here, but only take the contents
+		// of the [api.WebhookPut] fields when updating the webhook. The other fields are silently discarded.
+		newdata := api.Webhook{}
+		err = yaml.UnmarshalStrict(contents, &newdata)
+		if err != nil {
+			return err
+		}
+
+		return resource.server.UpdateWebhook(resource.name, newdata.Writable(), "")
+	}
+
+	// Get the current config.
+	webhook, etag, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(&webhook)
+	if err != nil {
+		return err
+	}
+
+	// Spawn the editor.
+	content, err := shared.TextEditor("", []byte(c.helpTemplate()+"\n\n"+string(data)))
+	if err != nil {
+		return err
+	}
+
+	for {
+		// Parse the text received from the editor.
+		newdata := api.Webhook{} // We show the full webhook info, but only send the writable fields.
+		err = yaml.UnmarshalStrict(content, &newdata)
+		if err == nil {
+			err = resource.server.UpdateWebhook(resource.name, newdata.Writable(), etag)
+		}
+
+		// Respawn the editor.
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Config parsing error: %s\n", err)
+			fmt.Println("Press enter to open the editor again or ctrl+c to abort change")
+
+			_, err := os.Stdin.Read(make([]byte, 1))
+			if err != nil {
+				return err
+			}
+
+			content, err = shared.TextEditor("", content)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		break
+	}
+
+	return nil
+}
+
+// Get.
+type cmdWebhookGet struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookGet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("get", "[<remote>:]<webhook> <key>")
+	cmd.Short = "Get value for webhook configuration key"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpWebhookConfigs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookGet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Get the configuration key.
+	webhook, _, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", webhook.Config[args[1]])
+
+	return nil
+}
+
+// Set.
+type cmdWebhookSet struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookSet) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("set", "[<remote>:]<webhook> <key>=<value>...")
+	cmd.Short = "Set webhook configuration keys"
+	cmd.Long = cli.FormatSection("Description", cmd.Short+`
+
+For backward compatibility, a single configuration key may still be set with:
+    lxc webhook set [<remote>:]<webhook> <key> <value>`)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookSet) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, -1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Get the webhook.
+	webhook, etag, err := resource.server.GetWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	// Set the configuration key.
+	keys, err := getConfig(args[1:]...)
+	if err != nil {
+		return err
+	}
+
+	writable := webhook.Writable()
+	maps.Copy(writable.Config, keys)
+
+	return resource.server.UpdateWebhook(resource.name, writable, etag)
+}
+
+// Unset.
+type cmdWebhookUnset struct {
+	global     *cmdGlobal
+	webhook    *cmdWebhook
+	webhookSet *cmdWebhookSet
+}
+
+func (c *cmdWebhookUnset) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("unset", "[<remote>:]<webhook> <key>")
+	cmd.Short = "Unset webhook configuration key"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		if len(args) == 1 {
+			return c.global.cmpWebhookConfigs(args[0])
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookUnset) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	args = append(args, "")
+	return c.webhookSet.run(cmd, args)
+}
+
+// Delete.
+type cmdWebhookDelete struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookDelete) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("delete", "[<remote>:]<webhook>")
+	cmd.Aliases = []string{"rm"}
+	cmd.Short = "Delete webhook"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookDelete) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Delete the webhook.
+	err = resource.server.DeleteWebhook(resource.name)
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Webhook %s deleted\n", resource.name)
+	}
+
+	return nil
+}
+
+// Rename.
+type cmdWebhookRename struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+}
+
+func (c *cmdWebhookRename) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("rename", "[<remote>:]<old_name> <new_name>")
+	cmd.Aliases = []string{"mv"}
+	cmd.Short = "Rename webhook"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.RunE = c.run
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookRename) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 2, 2)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	// Rename the webhook.
+	err = resource.server.RenameWebhook(resource.name, api.WebhookPost{Name: args[1]})
+	if err != nil {
+		return err
+	}
+
+	if !c.global.flagQuiet {
+		fmt.Printf("Webhook %s renamed to %s\n", resource.name, args[1])
+	}
+
+	return nil
+}
+
+// Info.
+type cmdWebhookInfo struct {
+	global  *cmdGlobal
+	webhook *cmdWebhook
+
+	flagShowDeliveries bool
+}
+
+func (c *cmdWebhookInfo) command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = usage("info", "[<remote>:]<webhook>")
+	cmd.Short = "Show the delivery status of a webhook"
+	cmd.Long = cli.FormatSection("Description", cmd.Short)
+	cmd.RunE = c.run
+
+	cmd.Flags().BoolVar(&c.flagShowDeliveries, "show-deliveries", false, "Show the queued and failed deliveries")
+
+	cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return c.global.cmpTopLevelResource("webhook", toComplete)
+		}
+
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+func (c *cmdWebhookInfo) run(cmd *cobra.Command, args []string) error {
+	// Quick checks.
+	exit, err := c.global.CheckArgs(cmd, args, 1, 1)
+	if exit {
+		return err
+	}
+
+	// Parse remote.
+	resources, err := c.global.ParseServers(args[0])
+	if err != nil {
+		return err
+	}
+
+	resource := resources[0]
+
+	if resource.name == "" {
+		return errors.New("Missing webhook name")
+	}
+
+	state, err := resource.server.GetWebhookState(resource.name)
+	if err != nil {
+		return err
+	}
+
+	const layout = "2006/01/02 15:04 MST"
+
+	fmt.Printf("Name: %s\n", resource.name)
+	fmt.Printf("Pending deliveries: %d\n", state.Pending)
+	fmt.Printf("Failed deliveries: %d\n", state.Failed)
+
+	if !state.LastDeliveryAt.IsZero() {
+		fmt.Printf("Last delivery: %s\n", state.LastDeliveryAt.Local().Format(layout))
+	}
+
+	if !state.LastFailureAt.IsZero() {
+		fmt.Printf("Last failure: %s\n", state.LastFailureAt.Local().Format(layout))
+		fmt.Printf("Last error: %s\n", state.LastError)
+	}
+
+	if !c.flagShowDeliveries || len(state.Deliveries) == 0 {
+		return nil
+	}
+
+	data := make([][]string, 0, len(state.Deliveries))
+	for _, delivery := range state.Deliveries {
+		data = append(data, []string{
+			delivery.UUID,
+			delivery.Location,
+			delivery.EventType,
+			delivery.Status,
+			strconv.Itoa(delivery.Attempts),
+			delivery.CreatedAt.Local().Format(layout),
+			delivery.LastError,
+		})
+	}
+
+	fmt.Println("\nDeliveries:")
+	header := []string{"UUID", "LOCATION", "TYPE", "STATUS", "ATTEMPTS", "CREATED AT", "LAST ERROR"}
+
+	return cli.RenderTable(cli.TableFormatTable, header, data, state.Deliveries)
+}
@@ -18,3 +18,17 @@ type API10Put struct {
 	// Example: true
 	Devlxd bool `json:"devlxd" yaml:"devlxd"`
 }
+
+// FreezePost contains the fields used to quiesce the guest before a snapshot.
+type FreezePost struct {
+	// Maximum duration of the freeze in seconds, after which the filesystems are thawed automatically
+	// Example: 30
+	Timeout int64 `json:"timeout" yaml:"timeout"`
+}
+
+// Freeze represents the filesystems frozen by the lxd-agent.
+type Freeze struct {
+	// Mount points of the frozen filesystems
+	// Example: ["/var/lib/postgresql", "/"]
+	Filesystems []string `json:"filesystems" yaml:"filesystems"`
+}
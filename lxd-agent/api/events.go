@@ -9,3 +9,16 @@ const (
 	DeviceRemoved DeviceEventAction = "removed"
 	DeviceUpdated DeviceEventAction = "updated"
 )
+
+// IdentityEvent represents the identity sent to the lxd-agent when the guest was started from the memory state
+// of another instance.
+type IdentityEvent struct {
+	// Name of the instance.
+	Name string `json:"name"`
+
+	// UUID of the instance.
+	UUID string `json:"uuid"`
+
+	// Interfaces maps the MAC addresses inherited from the source instance to the new MAC addresses.
+	Interfaces map[string]string `json:"interfaces"`
+}
@@ -29,11 +29,13 @@ var api10 = []APIEndpoint{
 	api10Cmd,
 	execCmd,
 	eventsCmd,
+	freezeCmd,
 	metricsCmd,
 	operationsCmd,
 	operationCmd,
 	operationWebsocket,
 	operationWait,
+	portForwardCmd,
 	sftpCmd,
 	stateCmd,
 }
@@ -30,6 +30,10 @@ type Daemon struct {
 	devlxdRunning bool
 	devlxdMu      sync.Mutex
 	devlxdEnabled bool
+
+	// The filesystems frozen for a snapshot.
+	freeze   *filesystemFreeze
+	freezeMu sync.Mutex
 }
 
 // newDaemon returns a new Daemon object with the given configuration.
@@ -68,6 +68,7 @@ var devLXDEndpoints = []devLXDAPIEndpoint{
 	devLXDStoragePoolVolumeSnapshotsEndpoint,
 	devLXDUbuntuProEndpoint,
 	devLXDUbuntuProTokenEndpoint,
+	devLXDIdentityTokenEndpoint,
 }
 
 // devLxdServer creates an http.Server capable of handling requests against the
@@ -365,6 +366,27 @@ func devLXDUbuntuProTokenPostHandler(d *Daemon, r *http.Request) *devLXDResponse
 	return okResponse(token, "json")
 }
 
+var devLXDIdentityTokenEndpoint = devLXDAPIEndpoint{
+	Path: "identity-token",
+	Get:  devLXDAPIEndpointAction{Handler: devLXDIdentityTokenGetHandler},
+}
+
+func devLXDIdentityTokenGetHandler(d *Daemon, r *http.Request) *devLXDResponse {
+	client, err := getDevLXDVsockClient(d, r)
+	if err != nil {
+		return smartResponse(fmt.Errorf("Failed connecting to devLXD over vsock: %w", err))
+	}
+
+	defer client.Disconnect()
+
+	token, err := client.GetIdentityToken(r.URL.Query().Get("audience"))
+	if err != nil {
+		return smartResponse(err)
+	}
+
+	return okResponse(token, "json")
+}
+
 func devLXDAPI(d *Daemon) http.Handler {
 	router := http.NewServeMux()
 
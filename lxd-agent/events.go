@@ -122,11 +122,18 @@ func eventsPost(d *Daemon, r *http.Request) response.Response {
 }
 
 func eventsProcess(event api.Event) error {
-	// We currently only need to react to device events.
-	if event.Type != "device" {
-		return nil
+	// We currently only need to react to device and identity events.
+	switch event.Type {
+	case "device":
+		return eventsProcessDevice(event)
+	case "identity":
+		return eventsProcessIdentity(event)
 	}
 
+	return nil
+}
+
+func eventsProcessDevice(event api.Event) error {
 	type deviceEvent struct {
 		Action agentAPI.DeviceEventAction `json:"action"`
 		Config map[string]string          `json:"config"`
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	agentAPI "github.com/canonical/lxd/lxd-agent/api"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Directories containing the executables run before freezing and after thawing the filesystems of the guest.
+const (
+	preSnapshotHooksDir  = "/etc/lxd/pre-snapshot.d"
+	postSnapshotHooksDir = "/etc/lxd/post-snapshot.d"
+)
+
+// Filesystem freeze ioctl requests from linux/fs.h.
+const (
+	ioctlFIFREEZE = 0xC0045877
+	ioctlFITHAW   = 0xC0045878
+)
+
+// Filesystem types that can't be frozen or don't need to be.
+var freezeSkipFSTypes = []string{"iso9660", "squashfs", "udf"}
+
+var freezeCmd = APIEndpoint{
+	Name: "freeze",
+	Path: "freeze",
+
+	Post:   APIEndpointAction{Handler: freezePost},
+	Delete: APIEndpointAction{Handler: freezeDelete},
+}
+
+// filesystemFreeze represents the filesystems frozen for a snapshot.
+type filesystemFreeze struct {
+	// Mount points in the order they were frozen.
+	mountpoints []string
+
+	// Timer thawing the filesystems if LXD doesn't do so in time.
+	timer *time.Timer
+}
+
+func freezePost(d *Daemon, r *http.Request) response.Response {
+	req := agentAPI.FreezePost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if req.Timeout <= 0 {
+		return response.BadRequest(errors.New("Freeze timeout must be positive"))
+	}
+
+	timeout := time.Duration(req.Timeout) * time.Second
+
+	d.freezeMu.Lock()
+	defer d.freezeMu.Unlock()
+
+	if d.freeze != nil {
+		return response.Conflict(errors.New("Filesystems are already frozen"))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	err = runSnapshotHooks(ctx, preSnapshotHooksDir)
+	if err != nil {
+		// Let the applications resume whatever the pre-snapshot hooks that succeeded did.
+		_ = runSnapshotHooks(context.Background(), postSnapshotHooksDir)
+		return response.InternalError(err)
+	}
+
+	mountpoints, err := freezeFilesystems()
+	if err != nil {
+		_ = runSnapshotHooks(context.Background(), postSnapshotHooksDir)
+		return response.InternalError(err)
+	}
+
+	freeze := &filesystemFreeze{mountpoints: mountpoints}
+	freeze.timer = time.AfterFunc(timeout, func() {
+		d.freezeMu.Lock()
+		defer d.freezeMu.Unlock()
+
+		if d.freeze != freeze {
+			return
+		}
+
+		logger.Warn("Thawing filesystems after freeze timeout", logger.Ctx{"timeout": timeout})
+		err := thawSnapshotFreeze(d)
+		if err != nil {
+			logger.Error("Failed thawing filesystems", logger.Ctx{"err": err})
+		}
+	})
+
+	d.freeze = freeze
+
+	return response.SyncResponse(true, agentAPI.Freeze{Filesystems: mountpoints})
+}
+
+func freezeDelete(d *Daemon, r *http.Request) response.Response {
+	d.freezeMu.Lock()
+	defer d.freezeMu.Unlock()
+
+	if d.freeze == nil {
+		return response.NotFound(errors.New("Filesystems aren't frozen"))
+	}
+
+	d.freeze.timer.Stop()
+
+	err := thawSnapshotFreeze(d)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// thawSnapshotFreeze thaws the frozen filesystems and runs the post-snapshot hooks.
+// The freezeMu lock must be held by the caller.
+func thawSnapshotFreeze(d *Daemon) error {
+	mountpoints := d.freeze.mountpoints
+	d.freeze = nil
+
+	errs := []error{}
+	for i := len(mountpoints) - 1; i >= 0; i-- {
+		err := freezeIoctl(mountpoints[i], ioctlFITHAW)
+		if err != nil && !errors.Is(err, unix.EINVAL) {
+			errs = append(errs, fmt.Errorf("Failed thawing %q: %w", mountpoints[i], err))
+		}
+	}
+
+	err := runSnapshotHooks(context.Background(), postSnapshotHooksDir)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// freezeFilesystems freezes the block device backed filesystems of the guest, nested mounts first, and returns
+// their mount points in that order. Already frozen filesystems are thawed again on failure.
+func freezeFilesystems() ([]string, error) {
+	mountpoints, err := freezableMountpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	frozen := make([]string, 0, len(mountpoints))
+	for i := len(mountpoints) - 1; i >= 0; i-- {
+		err := freezeIoctl(mountpoints[i], ioctlFIFREEZE)
+		if err != nil {
+			// Skip filesystems that don't support freezing.
+			if errors.Is(err, unix.EOPNOTSUPP) || errors.Is(err, unix.ENOTTY) {
+				continue
+			}
+
+			for j := len(frozen) - 1; j >= 0; j-- {
+				_ = freezeIoctl(frozen[j], ioctlFITHAW)
+			}
+
+			return nil, fmt.Errorf("Failed freezing %q: %w", mountpoints[i], err)
+		}
+
+		frozen = append(frozen, mountpoints[i])
+	}
+
+	return frozen, nil
+}
+
+// freezableMountpoints returns the mount points of the block device backed filesystems in mount order, keeping
+// a single mount point per filesystem.
+func freezableMountpoints() ([]string, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return parseFreezableMountpoints(f)
+}
+
+// parseFreezableMountpoints returns the freezable mount points of the given mountinfo table.
+func parseFreezableMountpoints(r io.Reader) ([]string, error) {
+	devices := map[string]bool{}
+	mountpoints := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		// Format: <id> <parent> <major:minor> <root> <mountpoint> <options> [<optional>...] - <fstype> <source> <superoptions>
+		fields := strings.Fields(scanner.Text())
+		separator := slices.Index(fields, "-")
+		if len(fields) < 5 || separator < 0 || len(fields) < separator+3 {
+			continue
+		}
+
+		device := fields[2]
+		fsType := fields[separator+1]
+		source := fields[separator+2]
+
+		if !strings.HasPrefix(source, "/dev/") || slices.Contains(freezeSkipFSTypes, fsType) || devices[device] {
+			continue
+		}
+
+		devices[device] = true
+		mountpoints = append(mountpoints, unescapeMountinfo(fields[4]))
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return mountpoints, nil
+}
+
+// unescapeMountinfo decodes the octal escapes used for spaces and other special characters in mountinfo paths.
+func unescapeMountinfo(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			c, err := strconv.ParseUint(path[i+1:i+4], 8, 8)
+			if err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
+
+// freezeIoctl sends a freeze or thaw request for the filesystem mounted at the given path.
+func freezeIoctl(mountpoint string, request uint) error {
+	fd, err := unix.Open(mountpoint, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = unix.Close(fd) }()
+
+	return unix.IoctlSetInt(fd, request, 0)
+}
+
+// hookPaths returns the executables of the given directory in lexical order.
+func hookPaths(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("Failed reading hooks directory %q: %w", dir, err)
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+
+	return paths, nil
+}
+
+// runSnapshotHooks runs all hooks of the given directory and returns their errors.
+func runSnapshotHooks(ctx context.Context, dir string) error {
+	paths, err := hookPaths(dir)
+	if err != nil {
+		return err
+	}
+
+	errs := []error{}
+	for _, hookPath := range paths {
+		_, err = shared.RunCommand(ctx, hookPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Failed running snapshot hook %q: %w", hookPath, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
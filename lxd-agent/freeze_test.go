@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFreezableMountpoints(t *testing.T) {
+	mountinfo := `22 1 252:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw
+24 22 0:5 / /dev rw,nosuid,relatime shared:2 - devtmpfs udev rw,size=4010836k
+25 22 252:2 / /srv/my\040data rw,relatime shared:3 - xfs /dev/sda2 rw
+26 22 252:1 /var/lib/docker /var/lib/docker rw,relatime shared:1 - ext4 /dev/sda1 rw
+27 22 7:0 / /snap/core/1 ro,nodev,relatime shared:4 - squashfs /dev/loop0 ro
+28 22 11:0 / /media/cdrom ro,relatime shared:5 - iso9660 /dev/sr0 ro
+29 22 252:3 / /home rw,relatime shared:6 master:1 - btrfs /dev/sda3 rw
+30 22 0:30 / /mnt/lxd_agent rw,relatime - 9p lxd_agent rw
+invalid line
+`
+
+	mountpoints, err := parseFreezableMountpoints(strings.NewReader(mountinfo))
+	require.NoError(t, err)
+
+	// Pseudo and read-only image filesystems are skipped, as well as the bind mounts of already listed filesystems.
+	assert.Equal(t, []string{"/", "/srv/my data", "/home"}, mountpoints)
+}
+
+func TestUnescapeMountinfo(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/", want: "/"},
+		{path: `/srv/my\040data`, want: "/srv/my data"},
+		{path: `/srv/tab\011and\012newline`, want: "/srv/tab\tand\nnewline"},
+		{path: `/srv/back\134slash`, want: `/srv/back\slash`},
+		{path: `/srv/end\040`, want: "/srv/end "},
+		{path: `/srv/short\04`, want: `/srv/short\04`},
+		{path: `/srv/invalid\999`, want: `/srv/invalid\999`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, unescapeMountinfo(tt.path), tt.path)
+	}
+}
+
+func TestHookPaths(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, mode := range map[string]os.FileMode{
+		"20-flush-db": 0755,
+		"10-stop-app": 0700,
+		"30-disabled": 0644,
+		"README":      0644,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), mode))
+	}
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "40-subdir"), 0755))
+
+	// Only executable files are run, in lexical order.
+	paths, err := hookPaths(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "10-stop-app"), filepath.Join(dir, "20-flush-db")}, paths)
+
+	// A missing directory has no hooks.
+	paths, err = hookPaths(filepath.Join(dir, "missing"))
+	assert.NoError(t, err)
+	assert.Empty(t, paths)
+}
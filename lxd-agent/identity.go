@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+
+	agentAPI "github.com/canonical/lxd/lxd-agent/api"
+	"github.com/canonical/lxd/lxd/ip"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// forkHooksDir is the directory containing the executables run after the guest identity was replaced.
+const forkHooksDir = "/etc/lxd-agent/fork.d"
+
+// eventsProcessIdentity replaces the identity that the guest inherited when it was started from the memory state
+// of another instance.
+func eventsProcessIdentity(event api.Event) error {
+	e := agentAPI.IdentityEvent{}
+	err := json.Unmarshal(event.Metadata, &e)
+	if err != nil {
+		return err
+	}
+
+	l := logger.AddContext(logger.Ctx{"name": e.Name})
+	l.Info("Applying new instance identity")
+
+	// Move the network interfaces over to their new MAC addresses.
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return fmt.Errorf("Failed listing network interfaces: %w", err)
+	}
+
+	for _, iface := range ifaces {
+		newHwaddr, ok := e.Interfaces[iface.HardwareAddr.String()]
+		if !ok {
+			continue
+		}
+
+		hwaddr, err := net.ParseMAC(newHwaddr)
+		if err != nil {
+			return fmt.Errorf("Invalid MAC address %q: %w", newHwaddr, err)
+		}
+
+		link := &ip.Link{Name: iface.Name}
+		err = link.SetAddress(hwaddr)
+		if err != nil {
+			return fmt.Errorf("Failed setting MAC address of %q: %w", iface.Name, err)
+		}
+	}
+
+	// Regenerate the machine ID if the guest has one.
+	if shared.PathExists("/etc/machine-id") {
+		machineID := make([]byte, 16)
+		_, err = rand.Read(machineID)
+		if err != nil {
+			return err
+		}
+
+		err = os.WriteFile("/etc/machine-id", []byte(hex.EncodeToString(machineID)+"\n"), 0444)
+		if err != nil {
+			return fmt.Errorf("Failed writing machine ID: %w", err)
+		}
+	}
+
+	// Use the instance name as host name.
+	if e.Name != "" {
+		err = unix.Sethostname([]byte(e.Name))
+		if err != nil {
+			return fmt.Errorf("Failed setting host name: %w", err)
+		}
+
+		if shared.PathExists("/etc/hostname") {
+			err = os.WriteFile("/etc/hostname", []byte(e.Name+"\n"), 0644)
+			if err != nil {
+				return fmt.Errorf("Failed writing host name: %w", err)
+			}
+		}
+	}
+
+	// Run the guest provided hooks in lexical order.
+	hooks, err := hookPaths(forkHooksDir)
+	if err != nil {
+		return err
+	}
+
+	for _, hookPath := range hooks {
+		_, err = shared.RunCommand(context.Background(), hookPath, e.Name)
+		if err != nil {
+			l.Warn("Failed running fork hook", logger.Ctx{"hook": hookPath, "err": err})
+		}
+	}
+
+	return nil
+}
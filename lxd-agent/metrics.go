@@ -15,6 +15,7 @@ import (
 	"github.com/canonical/lxd/lxd/metrics"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/storage/filesystem"
+	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/logger"
 )
 
@@ -73,6 +74,13 @@ func metricsGet(d *Daemon, r *http.Request) response.Response {
 		out.CPU = cpuStats
 	}
 
+	pressureStats, err := getPressureMetrics()
+	if err != nil {
+		logger.Debug("Failed getting pressure metrics", logger.Ctx{"err": err})
+	} else {
+		out.Pressure = pressureStats
+	}
+
 	return response.SyncResponse(true, &out)
 }
 
@@ -168,6 +176,25 @@ func getCPUMetrics() (map[string]metrics.CPUMetrics, error) {
 	return out, nil
 }
 
+// getPressureMetrics returns the pressure stall information of the guest, keyed by resource.
+func getPressureMetrics() (map[string]api.InstanceStatePressure, error) {
+	out := make(map[string]api.InstanceStatePressure, len(metrics.PressureResources))
+
+	for _, resource := range metrics.PressureResources {
+		content, err := os.ReadFile(filepath.Join("/proc/pressure", resource))
+		if err != nil {
+			return nil, fmt.Errorf("Failed reading pressure stall information: %w", err)
+		}
+
+		out[resource], err = metrics.ParsePressure(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("Failed parsing %q pressure stall information: %w", resource, err)
+		}
+	}
+
+	return out, nil
+}
+
 func getTotalProcesses() (uint64, error) {
 	entries, err := os.ReadDir("/proc")
 	if err != nil {
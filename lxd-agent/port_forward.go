@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+var portForwardCmd = APIEndpoint{
+	Name: "port-forward",
+	Path: "port-forward",
+
+	Get: APIEndpointAction{Handler: portForwardHandler},
+}
+
+// portForwardHandler connects to the requested address and mirrors the traffic of the upgraded websocket to it.
+func portForwardHandler(d *Daemon, r *http.Request) response.Response {
+	protocol := r.FormValue("protocol")
+	if !slices.Contains([]string{"tcp", "udp"}, protocol) {
+		return response.BadRequest(fmt.Errorf("Unsupported protocol %q", protocol))
+	}
+
+	address := r.FormValue("address")
+	_, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid address %q: %w", address, err))
+	}
+
+	// Connect before upgrading so that failures are reported to the caller.
+	target, err := net.Dial(protocol, address)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			_ = target.Close()
+			return err
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		err = ws.Forward(conn, target)
+		if err != nil {
+			logger.Debug("Port forwarding ended with an error", logger.Ctx{"protocol": protocol, "address": address, "err": err})
+		}
+
+		return nil
+	})
+}
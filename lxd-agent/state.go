@@ -39,9 +39,20 @@ func renderState() *api.InstanceState {
 		Network:   networkState(),
 		Pid:       1,
 		Processes: processesState(),
+		Pressure:  pressureState(),
 	}
 }
 
+// pressureState returns the pressure stall information of the guest, keyed by resource.
+func pressureState() map[string]api.InstanceStatePressure {
+	pressures, err := getPressureMetrics()
+	if err != nil {
+		return nil
+	}
+
+	return pressures
+}
+
 func cpuState() api.InstanceStateCPU {
 	cpu := api.InstanceStateCPU{}
 
@@ -0,0 +1,129 @@
+package benchmark
+
+// Comparison is the change of a metric of a scenario between two benchmark runs.
+type Comparison struct {
+	Scenario string  `json:"scenario"`
+	Label    string  `json:"label"`
+	Metric   string  `json:"metric"`
+	Base     float64 `json:"base"`
+	Current  float64 `json:"current"`
+
+	// Change is the relative change from the base value, in percent.
+	Change float64 `json:"change"`
+
+	// Regression is true when the metric got worse by more than the threshold.
+	Regression bool `json:"regression"`
+}
+
+// comparedMetric is a metric used to compare two results.
+type comparedMetric struct {
+	name string
+
+	// higherIsBetter is true for throughput metrics and false for latency and error metrics.
+	higherIsBetter bool
+
+	value func(result Result) (float64, bool)
+}
+
+var comparedMetrics = []comparedMetric{
+	{name: "duration", value: func(result Result) (float64, bool) {
+		return result.Duration, result.Latency == nil
+	}},
+	{name: "p50", value: func(result Result) (float64, bool) {
+		return latencyValue(result, func(l *LatencySummary) float64 { return l.P50 })
+	}},
+	{name: "p90", value: func(result Result) (float64, bool) {
+		return latencyValue(result, func(l *LatencySummary) float64 { return l.P90 })
+	}},
+	{name: "p99", value: func(result Result) (float64, bool) {
+		return latencyValue(result, func(l *LatencySummary) float64 { return l.P99 })
+	}},
+	{name: "throughput", higherIsBetter: true, value: func(result Result) (float64, bool) {
+		return result.Throughput, result.Latency != nil
+	}},
+	{name: "errors", value: func(result Result) (float64, bool) {
+		return float64(result.Errors), true
+	}},
+}
+
+func latencyValue(result Result, value func(l *LatencySummary) float64) (float64, bool) {
+	if result.Latency == nil {
+		return 0, false
+	}
+
+	return value(result.Latency), true
+}
+
+// CompareResults compares the results of two benchmark runs. Results are matched by scenario and label, and
+// the latest result is used when a report holds several runs of the same scenario. A metric is a regression when
+// it got worse by more than threshold percent. Any new error is a regression.
+func CompareResults(base []Result, current []Result, threshold float64) []Comparison {
+	type key struct {
+		scenario string
+		label    string
+	}
+
+	baseResults := map[key]Result{}
+	for _, result := range base {
+		baseResults[key{result.Scenario, result.Label}] = result
+	}
+
+	currentResults := map[key]Result{}
+	keys := []key{}
+	for _, result := range current {
+		k := key{result.Scenario, result.Label}
+		_, ok := currentResults[k]
+		if !ok {
+			keys = append(keys, k)
+		}
+
+		currentResults[k] = result
+	}
+
+	comparisons := []Comparison{}
+	for _, k := range keys {
+		baseResult, ok := baseResults[k]
+		if !ok {
+			continue
+		}
+
+		currentResult := currentResults[k]
+
+		for _, metric := range comparedMetrics {
+			baseValue, ok := metric.value(baseResult)
+			if !ok {
+				continue
+			}
+
+			currentValue, ok := metric.value(currentResult)
+			if !ok {
+				continue
+			}
+
+			comparison := Comparison{
+				Scenario: k.scenario,
+				Label:    k.label,
+				Metric:   metric.name,
+				Base:     baseValue,
+				Current:  currentValue,
+			}
+
+			if baseValue != 0 {
+				comparison.Change = (currentValue - baseValue) / baseValue * 100
+			}
+
+			switch {
+			case metric.name == "errors":
+				comparison.Regression = currentValue > baseValue
+			case metric.higherIsBetter:
+				comparison.Regression = comparison.Change < -threshold
+			default:
+				comparison.Regression = comparison.Change > threshold
+			}
+
+			comparisons = append(comparisons, comparison)
+		}
+	}
+
+	return comparisons
+}
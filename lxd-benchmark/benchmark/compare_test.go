@@ -0,0 +1,143 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compareTestResult returns the result of a scenario with a latency distribution.
+func compareTestResult(scenario string, p50 float64, throughput float64, errors int) Result {
+	return Result{
+		Scenario:   scenario,
+		Operations: 100,
+		Errors:     errors,
+		Duration:   10,
+		Throughput: throughput,
+		Latency:    &LatencySummary{P50: p50, P90: p50, P99: p50},
+	}
+}
+
+// compareTestMetrics returns the comparisons of the given scenario by metric.
+func compareTestMetrics(comparisons []Comparison, scenario string) map[string]Comparison {
+	metrics := map[string]Comparison{}
+	for _, comparison := range comparisons {
+		if comparison.Scenario == scenario {
+			metrics[comparison.Metric] = comparison
+		}
+	}
+
+	return metrics
+}
+
+func TestCompareResults_Direction(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       Result
+		current    Result
+		regression map[string]bool
+	}{
+		{
+			name:       "Slower latency",
+			base:       compareTestResult("exec", 1, 100, 0),
+			current:    compareTestResult("exec", 1.2, 100, 0),
+			regression: map[string]bool{"p50": true, "p90": true, "p99": true, "throughput": false, "errors": false},
+		},
+		{
+			name:       "Faster latency",
+			base:       compareTestResult("exec", 1, 100, 0),
+			current:    compareTestResult("exec", 0.5, 100, 0),
+			regression: map[string]bool{"p50": false, "p90": false, "p99": false, "throughput": false, "errors": false},
+		},
+		{
+			name:       "Lower throughput",
+			base:       compareTestResult("exec", 1, 100, 0),
+			current:    compareTestResult("exec", 1, 80, 0),
+			regression: map[string]bool{"p50": false, "p90": false, "p99": false, "throughput": true, "errors": false},
+		},
+		{
+			name:       "Higher throughput",
+			base:       compareTestResult("exec", 1, 100, 0),
+			current:    compareTestResult("exec", 1, 200, 0),
+			regression: map[string]bool{"p50": false, "p90": false, "p99": false, "throughput": false, "errors": false},
+		},
+		{
+			name:       "Within threshold",
+			base:       compareTestResult("exec", 1, 100, 0),
+			current:    compareTestResult("exec", 1.05, 95, 0),
+			regression: map[string]bool{"p50": false, "p90": false, "p99": false, "throughput": false, "errors": false},
+		},
+		{
+			name:       "Batch scenario",
+			base:       Result{Scenario: "create", Duration: 10},
+			current:    Result{Scenario: "create", Duration: 12},
+			regression: map[string]bool{"duration": true, "errors": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := compareTestMetrics(CompareResults([]Result{tt.base}, []Result{tt.current}, 10), tt.base.Scenario)
+			require.Len(t, metrics, len(tt.regression))
+
+			for metric, regression := range tt.regression {
+				assert.Equal(t, regression, metrics[metric].Regression, metric)
+			}
+		})
+	}
+}
+
+func TestCompareResults_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		base       int
+		current    int
+		regression bool
+	}{
+		{name: "New errors", base: 0, current: 1, regression: true},
+		{name: "More errors", base: 10, current: 11, regression: true},
+		{name: "Same errors", base: 2, current: 2},
+		{name: "Fewer errors", base: 2, current: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := compareTestResult("exec", 1, 100, tt.base)
+			current := compareTestResult("exec", 1, 100, tt.current)
+
+			// Errors aren't subject to the threshold.
+			metrics := compareTestMetrics(CompareResults([]Result{base}, []Result{current}, 1000), "exec")
+			assert.Equal(t, tt.regression, metrics["errors"].Regression)
+		})
+	}
+}
+
+func TestCompareResults_Matching(t *testing.T) {
+	base := []Result{
+		compareTestResult("exec", 1, 100, 0),
+		compareTestResult("file-push", 1, 100, 0),
+	}
+
+	labeled := compareTestResult("exec", 2, 100, 0)
+	labeled.Label = "other"
+
+	current := []Result{
+		// Superseded by the later run of the same scenario.
+		compareTestResult("exec", 5, 100, 0),
+		compareTestResult("exec", 1, 100, 0),
+
+		// Without a base result.
+		compareTestResult("file-pull", 5, 100, 0),
+		labeled,
+	}
+
+	comparisons := CompareResults(base, current, 10)
+	for _, comparison := range comparisons {
+		assert.Equal(t, "exec", comparison.Scenario)
+		assert.Empty(t, comparison.Label)
+		assert.False(t, comparison.Regression, comparison.Metric)
+	}
+
+	assert.Len(t, comparisons, 5)
+}
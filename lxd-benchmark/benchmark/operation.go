@@ -1,6 +1,10 @@
 package benchmark
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
 )
@@ -78,3 +82,117 @@ func copyImage(c lxd.InstanceServer, s lxd.ImageServer, image api.Image) error {
 
 	return op.Wait()
 }
+
+func execContainer(c lxd.InstanceServer, name string, command []string) error {
+	req := api.InstanceExecPost{
+		Command:   command,
+		WaitForWS: true,
+	}
+
+	dataDone := make(chan bool)
+	args := lxd.InstanceExecArgs{
+		Stdout:   io.Discard,
+		Stderr:   io.Discard,
+		DataDone: dataDone,
+	}
+
+	op, err := c.ExecInstance(name, req, &args)
+	if err != nil {
+		return err
+	}
+
+	err = op.Wait()
+	if err != nil {
+		return err
+	}
+
+	<-dataDone
+
+	exitCode, _ := op.Get().Metadata["return"].(float64)
+	if exitCode != 0 {
+		return fmt.Errorf("Command exited with code %d", int(exitCode))
+	}
+
+	return nil
+}
+
+func pushFile(c lxd.InstanceServer, name string, path string, content []byte) error {
+	args := lxd.InstanceFileArgs{
+		Content: bytes.NewReader(content),
+		Mode:    0600,
+		Type:    "file",
+	}
+
+	return c.CreateInstanceFile(name, path, args)
+}
+
+func pullFile(c lxd.InstanceServer, name string, path string) (int64, error) {
+	content, _, err := c.GetInstanceFile(name, path)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = content.Close() }()
+
+	return io.Copy(io.Discard, content)
+}
+
+func createSnapshot(c lxd.InstanceServer, name string, snapshot string) error {
+	op, err := c.CreateInstanceSnapshot(name, api.InstanceSnapshotsPost{Name: snapshot})
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+func restoreSnapshot(c lxd.InstanceServer, name string, snapshot string) error {
+	op, err := c.UpdateInstance(name, api.InstancePut{Restore: snapshot}, "")
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+func deleteSnapshot(c lxd.InstanceServer, name string, snapshot string) error {
+	op, err := c.DeleteInstanceSnapshot(name, snapshot, "")
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+func copyContainer(c lxd.InstanceServer, container api.Instance, name string, target string) error {
+	dest := c
+	if target != "" {
+		dest = c.UseTarget(target)
+	}
+
+	args := lxd.InstanceCopyArgs{
+		Name:         name,
+		InstanceOnly: true,
+	}
+
+	op, err := dest.CopyInstance(c, container, &args)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+func migrateContainer(c lxd.InstanceServer, name string, target string) error {
+	req := api.InstancePost{
+		Name:      name,
+		Migration: true,
+	}
+
+	op, err := c.UseTarget(target).MigrateInstance(name, req)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
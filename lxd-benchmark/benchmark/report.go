@@ -2,10 +2,12 @@ package benchmark
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,6 +22,13 @@ var csvFields = []string{
 	"success", // "true" or "false"
 }
 
+// Report is a report file that benchmark results are added to.
+type Report interface {
+	Load() error
+	AddResult(result Result) error
+	Write() error
+}
+
 // CSVReport reads/writes a CSV report file.
 type CSVReport struct {
 	Filename string
@@ -103,6 +112,16 @@ func (r *CSVReport) AddRecord(label string, elapsed time.Duration) error {
 	return r.addRecord(record)
 }
 
+// AddResult adds a record with the duration of the result to the report.
+func (r *CSVReport) AddResult(result Result) error {
+	label := result.Scenario
+	if result.Label != "" {
+		label = result.Label
+	}
+
+	return r.AddRecord(label, time.Duration(result.Duration*float64(time.Second)))
+}
+
 func (r *CSVReport) addRecord(record []string) error {
 	if len(record) != len(csvFields) {
 		return fmt.Errorf("Invalid number of fields : %q", record)
@@ -111,3 +130,140 @@ func (r *CSVReport) addRecord(record []string) error {
 	r.records = append(r.records, record)
 	return nil
 }
+
+// JSONReport reads/writes a JSON report file holding the results of all the benchmark runs.
+type JSONReport struct {
+	Filename string `json:"-"`
+
+	Results []Result `json:"results"`
+}
+
+// Load reads current content of the filename and loads results.
+func (r *JSONReport) Load() error {
+	content, err := os.ReadFile(r.Filename)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(content, r)
+	if err != nil {
+		return fmt.Errorf("Failed parsing report file %q: %w", r.Filename, err)
+	}
+
+	logf("Loaded report file %s", r.Filename)
+	return nil
+}
+
+// Write writes current results to file.
+func (r *JSONReport) Write() error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(r.Filename, append(content, '\n'), 0640)
+	if err != nil {
+		return err
+	}
+
+	logf("Written report file %s", r.Filename)
+	return nil
+}
+
+// AddResult adds a result to the report.
+func (r *JSONReport) AddResult(result Result) error {
+	r.Results = append(r.Results, result)
+	return nil
+}
+
+// PrometheusReport writes the results of a benchmark run to a file in the Prometheus text exposition format.
+// Unlike the other reports, the file is replaced on each run.
+type PrometheusReport struct {
+	Filename string
+
+	results []Result
+}
+
+// Load does nothing as the previous results aren't kept.
+func (r *PrometheusReport) Load() error {
+	return nil
+}
+
+// AddResult adds a result to the report.
+func (r *PrometheusReport) AddResult(result Result) error {
+	r.results = append(r.results, result)
+	return nil
+}
+
+// Write writes current results to file.
+func (r *PrometheusReport) Write() error {
+	var b strings.Builder
+
+	metric := func(name string, metricType string, help string, value func(result Result) (float64, bool)) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+		for _, result := range r.results {
+			v, ok := value(result)
+			if ok {
+				fmt.Fprintf(&b, "%s{%s} %s\n", name, prometheusLabels(result), strconv.FormatFloat(v, 'g', -1, 64))
+			}
+		}
+	}
+
+	metric("lxd_benchmark_operations", "gauge", "Number of successful operations.", func(result Result) (float64, bool) {
+		return float64(result.Operations), true
+	})
+
+	metric("lxd_benchmark_errors", "gauge", "Number of failed operations.", func(result Result) (float64, bool) {
+		return float64(result.Errors), true
+	})
+
+	metric("lxd_benchmark_duration_seconds", "gauge", "Total duration of the scenario.", func(result Result) (float64, bool) {
+		return result.Duration, true
+	})
+
+	metric("lxd_benchmark_throughput_operations_per_second", "gauge", "Successful operations per second.", func(result Result) (float64, bool) {
+		return result.Throughput, result.Latency != nil
+	})
+
+	metric("lxd_benchmark_throughput_bytes_per_second", "gauge", "Transferred bytes per second.", func(result Result) (float64, bool) {
+		return result.BytesPerSecond, result.BytesPerSecond > 0
+	})
+
+	fmt.Fprint(&b, "# HELP lxd_benchmark_latency_seconds Latency of the successful operations.\n# TYPE lxd_benchmark_latency_seconds summary\n")
+	for _, result := range r.results {
+		if result.Latency == nil {
+			continue
+		}
+
+		labels := prometheusLabels(result)
+		quantiles := []struct {
+			quantile string
+			value    float64
+		}{
+			{"0.5", result.Latency.P50},
+			{"0.9", result.Latency.P90},
+			{"0.99", result.Latency.P99},
+		}
+
+		for _, q := range quantiles {
+			fmt.Fprintf(&b, "lxd_benchmark_latency_seconds{%s,quantile=%q} %s\n", labels, q.quantile, strconv.FormatFloat(q.value, 'g', -1, 64))
+		}
+
+		fmt.Fprintf(&b, "lxd_benchmark_latency_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(result.Latency.Mean*float64(result.Operations), 'g', -1, 64))
+		fmt.Fprintf(&b, "lxd_benchmark_latency_seconds_count{%s} %d\n", labels, result.Operations)
+	}
+
+	err := os.WriteFile(r.Filename, []byte(b.String()), 0640)
+	if err != nil {
+		return err
+	}
+
+	logf("Written report file %s", r.Filename)
+	return nil
+}
+
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func prometheusLabels(result Result) string {
+	return fmt.Sprintf(`scenario="%s",label="%s"`, prometheusLabelReplacer.Replace(result.Scenario), prometheusLabelReplacer.Replace(result.Label))
+}
@@ -0,0 +1,141 @@
+package benchmark
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Result holds the measurements of a benchmark scenario.
+type Result struct {
+	// Scenario is the name of the measured operation, such as "exec" or "file-push".
+	Scenario string `json:"scenario"`
+
+	// Label is the user provided label of the benchmark run.
+	Label string `json:"label,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+
+	// Operations is the number of successful operations.
+	Operations int `json:"operations"`
+	Errors     int `json:"errors"`
+
+	// Duration is the total time of the scenario in seconds.
+	Duration float64 `json:"duration"`
+
+	// Throughput is the number of successful operations per second.
+	Throughput float64 `json:"throughput"`
+
+	// BytesPerSecond is the data throughput of transfer scenarios.
+	BytesPerSecond float64 `json:"bytes_per_second,omitempty"`
+
+	Latency *LatencySummary `json:"latency,omitempty"`
+}
+
+// LatencySummary holds the distribution of the latencies of the successful operations, in seconds.
+type LatencySummary struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P90  float64 `json:"p90"`
+	P99  float64 `json:"p99"`
+	Max  float64 `json:"max"`
+}
+
+// recorder collects the latencies of the operations of a scenario.
+type recorder struct {
+	scenario string
+
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+	bytes     int64
+}
+
+func newRecorder(scenario string) *recorder {
+	return &recorder{scenario: scenario}
+}
+
+// measure runs the operation and records its latency. The operation returns the number of bytes it transferred.
+func (r *recorder) measure(op func() (int64, error)) error {
+	start := time.Now()
+	n, err := op()
+	elapsed := time.Since(start)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors++
+		return err
+	}
+
+	r.latencies = append(r.latencies, elapsed)
+	r.bytes += n
+	return nil
+}
+
+// result returns the measurements of the scenario which ran for the given duration.
+func (r *recorder) result(duration time.Duration) Result {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := Result{
+		Scenario:   r.scenario,
+		Timestamp:  time.Now().UTC(),
+		Operations: len(r.latencies),
+		Errors:     r.errors,
+		Duration:   duration.Seconds(),
+	}
+
+	if duration > 0 {
+		result.Throughput = float64(result.Operations) / duration.Seconds()
+		result.BytesPerSecond = float64(r.bytes) / duration.Seconds()
+	}
+
+	if len(r.latencies) == 0 {
+		return result
+	}
+
+	latencies := slices.Clone(r.latencies)
+	slices.Sort(latencies)
+
+	var total time.Duration
+	for _, latency := range latencies {
+		total += latency
+	}
+
+	result.Latency = &LatencySummary{
+		Min:  latencies[0].Seconds(),
+		Mean: (total / time.Duration(len(latencies))).Seconds(),
+		P50:  percentile(latencies, 50).Seconds(),
+		P90:  percentile(latencies, 90).Seconds(),
+		P99:  percentile(latencies, 99).Seconds(),
+		Max:  latencies[len(latencies)-1].Seconds(),
+	}
+
+	return result
+}
+
+// percentile returns the nearest-rank percentile of the sorted latencies.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := max(int(math.Ceil(p/100*float64(len(sorted)))), 1)
+
+	return sorted[rank-1]
+}
+
+func printResult(result Result) {
+	if result.Latency == nil {
+		logf("%s: %d operations, %d errors", result.Scenario, result.Operations, result.Errors)
+		return
+	}
+
+	logf("%s: %d operations, %d errors, %.3f/s, latency p50 %s p90 %s p99 %s",
+		result.Scenario, result.Operations, result.Errors, result.Throughput,
+		formatSeconds(result.Latency.P50), formatSeconds(result.Latency.P90), formatSeconds(result.Latency.P99))
+}
+
+func formatSeconds(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Microsecond).String()
+}
@@ -0,0 +1,66 @@
+package benchmark
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	// 1ms to 10ms.
+	latencies := make([]time.Duration, 10)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		sorted []time.Duration
+		p      float64
+		want   time.Duration
+	}{
+		{sorted: latencies, p: 0, want: time.Millisecond},
+		{sorted: latencies, p: 10, want: time.Millisecond},
+		{sorted: latencies, p: 11, want: 2 * time.Millisecond},
+		{sorted: latencies, p: 50, want: 5 * time.Millisecond},
+		{sorted: latencies, p: 90, want: 9 * time.Millisecond},
+		{sorted: latencies, p: 99, want: 10 * time.Millisecond},
+		{sorted: latencies, p: 100, want: 10 * time.Millisecond},
+		{sorted: latencies[:1], p: 50, want: time.Millisecond},
+		{sorted: latencies[:1], p: 99, want: time.Millisecond},
+		{sorted: latencies[:3], p: 50, want: 2 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, percentile(tt.sorted, tt.p), "p%v of %d latencies", tt.p, len(tt.sorted))
+	}
+}
+
+func TestRecorderResult(t *testing.T) {
+	r := newRecorder("exec")
+
+	// Only the latencies of successful operations are recorded.
+	for _, n := range []int64{10, 20, 30} {
+		require.NoError(t, r.measure(func() (int64, error) { return n, nil }))
+	}
+
+	assert.Error(t, r.measure(func() (int64, error) { return 100, errors.New("failed") }))
+
+	result := r.result(2 * time.Second)
+	assert.Equal(t, "exec", result.Scenario)
+	assert.Equal(t, 3, result.Operations)
+	assert.Equal(t, 1, result.Errors)
+	assert.Equal(t, 1.5, result.Throughput)
+	assert.Equal(t, 30.0, result.BytesPerSecond)
+	require.NotNil(t, result.Latency)
+	assert.LessOrEqual(t, result.Latency.Min, result.Latency.P50)
+	assert.LessOrEqual(t, result.Latency.P50, result.Latency.P99)
+	assert.Equal(t, result.Latency.P99, result.Latency.Max)
+
+	// Without successful operations, there is no latency distribution.
+	result = newRecorder("exec").result(0)
+	assert.Nil(t, result.Latency)
+	assert.Zero(t, result.Throughput)
+}
@@ -0,0 +1,259 @@
+package benchmark
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// ExecContainers measures the round-trip latency of running a command in the running containers created by the
+// benchmark. The command is run the given number of times in each container.
+func ExecContainers(c lxd.InstanceServer, containers []api.Instance, iterations int, parallel int, command []string) ([]Result, error) {
+	containers = runningContainers(containers)
+	if len(containers) == 0 {
+		return nil, errors.New("No running benchmark containers")
+	}
+
+	batchSize := getBatchSize(parallel)
+
+	logf("Running %q %d times in %d containers", command, iterations, len(containers))
+
+	exec := newRecorder("exec")
+
+	batchExec := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		name := containers[index].Name
+		for range iterations {
+			err := exec.measure(func() (int64, error) {
+				return 0, execContainer(c, name, command)
+			})
+			if err != nil {
+				logf("Failed running command in container %q: %s", name, err)
+			}
+		}
+	}
+
+	duration := processBatch(len(containers), batchSize, batchExec)
+	return results(duration, exec), nil
+}
+
+// TransferFiles measures the throughput of pushing a file of the given size to the containers created by the
+// benchmark and pulling it back. The transfer is repeated the given number of times for each container.
+func TransferFiles(c lxd.InstanceServer, containers []api.Instance, iterations int, parallel int, path string, size int64) ([]Result, error) {
+	if len(containers) == 0 {
+		return nil, errors.New("No benchmark containers")
+	}
+
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := getBatchSize(parallel)
+
+	logf("Transferring a %d bytes file %d times with %d containers", size, iterations, len(containers))
+
+	push := newRecorder("file-push")
+	pull := newRecorder("file-pull")
+
+	batchTransfer := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		name := containers[index].Name
+		for range iterations {
+			err := push.measure(func() (int64, error) {
+				return size, pushFile(c, name, path, content)
+			})
+			if err != nil {
+				logf("Failed pushing file to container %q: %s", name, err)
+				continue
+			}
+
+			err = pull.measure(func() (int64, error) {
+				return pullFile(c, name, path)
+			})
+			if err != nil {
+				logf("Failed pulling file from container %q: %s", name, err)
+			}
+		}
+
+		err := c.DeleteInstanceFile(name, path)
+		if err != nil {
+			logf("Failed deleting file from container %q: %s", name, err)
+		}
+	}
+
+	duration := processBatch(len(containers), batchSize, batchTransfer)
+	return results(duration, push, pull), nil
+}
+
+// SnapshotContainers measures the latency of creating, restoring and deleting snapshots of the containers created
+// by the benchmark. The cycle is repeated the given number of times for each container.
+func SnapshotContainers(c lxd.InstanceServer, containers []api.Instance, iterations int, parallel int) ([]Result, error) {
+	if len(containers) == 0 {
+		return nil, errors.New("No benchmark containers")
+	}
+
+	batchSize := getBatchSize(parallel)
+
+	logf("Snapshotting %d containers %d times", len(containers), iterations)
+
+	create := newRecorder("snapshot-create")
+	restore := newRecorder("snapshot-restore")
+	remove := newRecorder("snapshot-delete")
+
+	batchSnapshot := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		name := containers[index].Name
+		for i := range iterations {
+			snapshot := "benchmark-" + strconv.Itoa(i+1)
+
+			err := create.measure(func() (int64, error) {
+				return 0, createSnapshot(c, name, snapshot)
+			})
+			if err != nil {
+				logf("Failed creating snapshot of container %q: %s", name, err)
+				continue
+			}
+
+			err = restore.measure(func() (int64, error) {
+				return 0, restoreSnapshot(c, name, snapshot)
+			})
+			if err != nil {
+				logf("Failed restoring snapshot of container %q: %s", name, err)
+			}
+
+			err = remove.measure(func() (int64, error) {
+				return 0, deleteSnapshot(c, name, snapshot)
+			})
+			if err != nil {
+				logf("Failed deleting snapshot of container %q: %s", name, err)
+			}
+		}
+	}
+
+	duration := processBatch(len(containers), batchSize, batchSnapshot)
+	return results(duration, create, restore, remove), nil
+}
+
+// CopyContainers measures the latency of copying the containers created by the benchmark, optionally to another
+// cluster member. Each container is copied the given number of times. The copies are benchmark containers too.
+func CopyContainers(c lxd.InstanceServer, containers []api.Instance, iterations int, parallel int, target string) ([]Result, error) {
+	if len(containers) == 0 {
+		return nil, errors.New("No benchmark containers")
+	}
+
+	batchSize := getBatchSize(parallel)
+
+	logf("Copying %d containers %d times", len(containers), iterations)
+
+	instanceCopy := newRecorder("copy")
+
+	batchCopy := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		container := containers[index]
+		for i := range iterations {
+			name := fmt.Sprintf("%s-copy-%d", container.Name, i+1)
+
+			err := instanceCopy.measure(func() (int64, error) {
+				return 0, copyContainer(c, container, name, target)
+			})
+			if err != nil {
+				logf("Failed copying container %q: %s", container.Name, err)
+			}
+		}
+	}
+
+	duration := processBatch(len(containers), batchSize, batchCopy)
+	return results(duration, instanceCopy), nil
+}
+
+// MigrateContainers measures the latency of moving the stopped containers created by the benchmark to another
+// cluster member.
+func MigrateContainers(c lxd.InstanceServer, containers []api.Instance, parallel int, target string) ([]Result, error) {
+	if len(containers) == 0 {
+		return nil, errors.New("No benchmark containers")
+	}
+
+	batchSize := getBatchSize(parallel)
+
+	logf("Migrating %d containers to %q", len(containers), target)
+
+	migrate := newRecorder("migrate")
+
+	batchMigrate := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		container := containers[index]
+		err := migrate.measure(func() (int64, error) {
+			if container.IsActive() {
+				return 0, errors.New("Container is running")
+			}
+
+			return 0, migrateContainer(c, container.Name, target)
+		})
+		if err != nil {
+			logf("Failed migrating container %q: %s", container.Name, err)
+		}
+	}
+
+	duration := processBatch(len(containers), batchSize, batchMigrate)
+	return results(duration, migrate), nil
+}
+
+// QueryAPI measures the latency of the given number of GET requests to an API endpoint.
+func QueryAPI(c lxd.InstanceServer, count int, parallel int, path string) ([]Result, error) {
+	batchSize := getBatchSize(parallel)
+
+	logf("Querying %q %d times", path, count)
+
+	query := newRecorder("api")
+
+	batchQuery := func(index int, wg *sync.WaitGroup) {
+		defer wg.Done()
+
+		err := query.measure(func() (int64, error) {
+			_, _, err := c.RawQuery("GET", path, nil, "")
+			return 0, err
+		})
+		if err != nil {
+			logf("Failed querying %q: %s", path, err)
+		}
+	}
+
+	duration := processBatch(count, batchSize, batchQuery)
+	return results(duration, query), nil
+}
+
+func runningContainers(containers []api.Instance) []api.Instance {
+	running := []api.Instance{}
+	for _, container := range containers {
+		if container.IsActive() {
+			running = append(running, container)
+		}
+	}
+
+	return running
+}
+
+// results returns and prints the measurements of the recorders of a scenario.
+func results(duration time.Duration, recorders ...*recorder) []Result {
+	results := make([]Result, 0, len(recorders))
+	for _, r := range recorders {
+		result := r.result(duration)
+		printResult(result)
+		results = append(results, result)
+	}
+
+	return results
+}
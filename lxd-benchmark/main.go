@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -13,16 +14,18 @@ import (
 )
 
 type cmdGlobal struct {
-	flagHelp        bool
-	flagParallel    int
-	flagProject     string
-	flagReportFile  string
-	flagReportLabel string
-	flagVersion     bool
+	flagHelp         bool
+	flagParallel     int
+	flagProject      string
+	flagReportFile   string
+	flagReportFormat string
+	flagReportLabel  string
+	flagVersion      bool
 
 	srv            lxd.InstanceServer
-	report         *benchmark.CSVReport
+	report         benchmark.Report
 	reportDuration time.Duration
+	reportResults  []benchmark.Result
 }
 
 func (c *cmdGlobal) Run(cmd *cobra.Command, args []string) error {
@@ -42,7 +45,17 @@ func (c *cmdGlobal) Run(cmd *cobra.Command, args []string) error {
 
 	// Setup report handling
 	if c.flagReportFile != "" {
-		c.report = &benchmark.CSVReport{Filename: c.flagReportFile}
+		switch c.flagReportFormat {
+		case "csv":
+			c.report = &benchmark.CSVReport{Filename: c.flagReportFile}
+		case "json":
+			c.report = &benchmark.JSONReport{Filename: c.flagReportFile}
+		case "prometheus":
+			c.report = &benchmark.PrometheusReport{Filename: c.flagReportFile}
+		default:
+			return fmt.Errorf("Invalid report format %q: Valid values are [csv, json, prometheus]", c.flagReportFormat)
+		}
+
 		err := c.report.Load()
 		if err != nil && !os.IsNotExist(err) {
 			return err
@@ -58,17 +71,26 @@ func (c *cmdGlobal) Teardown(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	label := cmd.Name()
-	if c.flagReportLabel != "" {
-		label = c.flagReportLabel
+	// Actions without per-operation measurements only report their total duration.
+	results := c.reportResults
+	if len(results) == 0 {
+		results = []benchmark.Result{{
+			Scenario:  cmd.Name(),
+			Timestamp: time.Now().UTC(),
+			Duration:  c.reportDuration.Seconds(),
+		}}
 	}
 
-	err := c.report.AddRecord(label, c.reportDuration)
-	if err != nil {
-		return err
+	for _, result := range results {
+		result.Label = c.flagReportLabel
+
+		err := c.report.AddResult(result)
+		if err != nil {
+			return err
+		}
 	}
 
-	err = c.report.Write()
+	err := c.report.Write()
 	if err != nil {
 		return err
 	}
@@ -90,6 +112,9 @@ func main() {
   when doing changes to the LXD codebase.
 
   A CSV report can be produced to be consumed by graphing software.
+  JSON reports hold the latency percentiles, errors and throughput of
+  each scenario and can be compared to catch performance regressions.
+  Prometheus reports expose the same measurements to monitoring systems.
 `
 	app.Example = `  # Spawn 20 Ubuntu containers in batches of 4
   lxd-benchmark launch --count 20 --parallel 4
@@ -97,6 +122,12 @@ func main() {
   # Create 50 Ubuntu Minimal 24.04 containers in batches of 10
   lxd-benchmark init --count 50 --parallel 10 ubuntu-minimal:24.04
 
+  # Measure the exec round-trip latency in the running test containers
+  lxd-benchmark exec --iterations 100 --report-file new.json --report-format json
+
+  # Compare the results with a previous run
+  lxd-benchmark compare old.json new.json
+
   # Delete all test containers using dynamic batch size
   lxd-benchmark delete`
 	app.SilenceUsage = true
@@ -109,7 +140,8 @@ func main() {
 	app.PersistentFlags().BoolVar(&globalCmd.flagVersion, "version", false, "Print version number")
 	app.PersistentFlags().BoolVarP(&globalCmd.flagHelp, "help", "h", false, "Print help")
 	app.PersistentFlags().IntVarP(&globalCmd.flagParallel, "parallel", "P", -1, "Number of threads to use")
-	app.PersistentFlags().StringVar(&globalCmd.flagReportFile, "report-file", "", cli.FormatStringFlagLabel("Path to the CSV report file"))
+	app.PersistentFlags().StringVar(&globalCmd.flagReportFile, "report-file", "", cli.FormatStringFlagLabel("Path to the report file"))
+	app.PersistentFlags().StringVar(&globalCmd.flagReportFormat, "report-format", "csv", cli.FormatStringFlagLabel("Format of the report file (csv, json or prometheus)"))
 	app.PersistentFlags().StringVar(&globalCmd.flagReportLabel, "report-label", "", cli.FormatStringFlagLabel("Label for the new entry in the report [default=ACTION]"))
 	app.PersistentFlags().StringVar(&globalCmd.flagProject, "project", "default", cli.FormatStringFlagLabel("Project to use"))
 
@@ -137,6 +169,34 @@ func main() {
 	deleteCmd := cmdDelete{global: &globalCmd}
 	app.AddCommand(deleteCmd.Command())
 
+	// exec sub-command
+	execCmd := cmdExec{global: &globalCmd}
+	app.AddCommand(execCmd.Command())
+
+	// file sub-command
+	fileCmd := cmdFile{global: &globalCmd}
+	app.AddCommand(fileCmd.Command())
+
+	// snapshot sub-command
+	snapshotCmd := cmdSnapshot{global: &globalCmd}
+	app.AddCommand(snapshotCmd.Command())
+
+	// copy sub-command
+	copyCmd := cmdCopy{global: &globalCmd}
+	app.AddCommand(copyCmd.Command())
+
+	// migrate sub-command
+	migrateCmd := cmdMigrate{global: &globalCmd}
+	app.AddCommand(migrateCmd.Command())
+
+	// api sub-command
+	apiCmd := cmdAPI{global: &globalCmd}
+	app.AddCommand(apiCmd.Command())
+
+	// compare sub-command
+	compareCmd := cmdCompare{global: &globalCmd}
+	app.AddCommand(compareCmd.Command())
+
 	// Run the main command and handle errors
 	err := app.Execute()
 	if err != nil {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdAPI struct {
+	global *cmdGlobal
+
+	flagCount int
+	flagPath  string
+}
+
+func (c *cmdAPI) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "api"
+	cmd.Short = "Measure API read latency"
+	cmd.RunE = c.Run
+	cmd.Flags().IntVarP(&c.flagCount, "count", "C", 1000, "Number of requests")
+	cmd.Flags().StringVar(&c.flagPath, "path", "/1.0/instances?recursion=1", cli.FormatStringFlagLabel("API endpoint to query"))
+
+	return cmd
+}
+
+func (c *cmdAPI) Run(cmd *cobra.Command, args []string) error {
+	// Run the test
+	results, err := benchmark.QueryAPI(c.global.srv, c.flagCount, c.global.flagParallel, c.flagPath)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
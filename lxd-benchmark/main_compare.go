@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdCompare struct {
+	global *cmdGlobal
+
+	flagFormat    string
+	flagThreshold float64
+}
+
+func (c *cmdCompare) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "compare <base report> <report>"
+	cmd.Short = "Compare two JSON reports"
+	cmd.Long = `Description:
+  Compare two JSON reports
+
+  The latency percentiles, throughput and errors of the scenarios found in
+  both reports are compared. The command fails if any of them got worse by
+  more than the threshold, or if new errors occurred.
+`
+	cmd.Args = cobra.ExactArgs(2)
+	cmd.RunE = c.Run
+	cmd.Flags().StringVarP(&c.flagFormat, "format", "f", cli.TableFormatTable, cli.FormatStringFlagLabel("Format (csv|json|table|yaml|compact)"))
+	cmd.Flags().Float64Var(&c.flagThreshold, "threshold", 10, "Relative change, in percent, above which a metric is a regression")
+
+	// Comparing reports doesn't need a connection to LXD.
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error { return nil }
+	cmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error { return nil }
+
+	return cmd
+}
+
+func (c *cmdCompare) Run(cmd *cobra.Command, args []string) error {
+	base := benchmark.JSONReport{Filename: args[0]}
+	err := base.Load()
+	if err != nil {
+		return err
+	}
+
+	current := benchmark.JSONReport{Filename: args[1]}
+	err = current.Load()
+	if err != nil {
+		return err
+	}
+
+	comparisons := benchmark.CompareResults(base.Results, current.Results, c.flagThreshold)
+
+	regressions := 0
+	data := [][]string{}
+	for _, comparison := range comparisons {
+		status := "ok"
+		if comparison.Regression {
+			status = "REGRESSION"
+			regressions++
+		}
+
+		data = append(data, []string{
+			comparison.Scenario,
+			comparison.Label,
+			comparison.Metric,
+			strconv.FormatFloat(comparison.Base, 'g', 6, 64),
+			strconv.FormatFloat(comparison.Current, 'g', 6, 64),
+			fmt.Sprintf("%+.1f%%", comparison.Change),
+			status,
+		})
+	}
+
+	header := []string{"SCENARIO", "LABEL", "METRIC", "BASE", "CURRENT", "CHANGE", "STATUS"}
+
+	err = cli.RenderTable(c.flagFormat, header, data, comparisons)
+	if err != nil {
+		return err
+	}
+
+	if regressions > 0 {
+		return fmt.Errorf("Found %d regressions above the %.1f%% threshold", regressions, c.flagThreshold)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdCopy struct {
+	global *cmdGlobal
+
+	flagIterations int
+	flagTarget     string
+}
+
+func (c *cmdCopy) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "copy"
+	cmd.Short = "Measure container copy latency"
+	cmd.Long = `Description:
+  Measure container copy latency
+
+  Each benchmark container is copied the given number of times, optionally
+  to another cluster member. The copies are deleted with the other
+  benchmark containers.
+`
+	cmd.RunE = c.Run
+	cmd.Flags().IntVar(&c.flagIterations, "iterations", 1, "Number of copies of each container")
+	cmd.Flags().StringVar(&c.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member to copy the containers to"))
+
+	return cmd
+}
+
+func (c *cmdCopy) Run(cmd *cobra.Command, args []string) error {
+	// Get the containers
+	containers, err := benchmark.GetContainers(c.global.srv)
+	if err != nil {
+		return err
+	}
+
+	// Run the test
+	results, err := benchmark.CopyContainers(c.global.srv, containers, c.flagIterations, c.global.flagParallel, c.flagTarget)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
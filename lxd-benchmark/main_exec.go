@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+)
+
+type cmdExec struct {
+	global *cmdGlobal
+
+	flagIterations int
+}
+
+func (c *cmdExec) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "exec [<command>...]"
+	cmd.Short = "Measure command execution latency in containers"
+	cmd.Long = `Description:
+  Measure command execution latency in containers
+
+  The command (by default "true") is run the given number of times in
+  each running benchmark container.
+`
+	cmd.RunE = c.Run
+	cmd.Flags().IntVar(&c.flagIterations, "iterations", 10, "Number of times to run the command in each container")
+
+	return cmd
+}
+
+func (c *cmdExec) Run(cmd *cobra.Command, args []string) error {
+	command := []string{"true"}
+	if len(args) > 0 {
+		command = args
+	}
+
+	// Get the containers
+	containers, err := benchmark.GetContainers(c.global.srv)
+	if err != nil {
+		return err
+	}
+
+	// Run the test
+	results, err := benchmark.ExecContainers(c.global.srv, containers, c.flagIterations, c.global.flagParallel, command)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+	cli "github.com/canonical/lxd/shared/cmd"
+	"github.com/canonical/lxd/shared/units"
+)
+
+type cmdFile struct {
+	global *cmdGlobal
+
+	flagIterations int
+	flagPath       string
+	flagSize       string
+}
+
+func (c *cmdFile) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "file"
+	cmd.Short = "Measure file push and pull throughput"
+	cmd.RunE = c.Run
+	cmd.Flags().IntVar(&c.flagIterations, "iterations", 10, "Number of times to transfer the file with each container")
+	cmd.Flags().StringVar(&c.flagPath, "path", "/root/lxd-benchmark.bin", cli.FormatStringFlagLabel("Path of the file in the containers"))
+	cmd.Flags().StringVar(&c.flagSize, "size", "16MiB", cli.FormatStringFlagLabel("Size of the file"))
+
+	return cmd
+}
+
+func (c *cmdFile) Run(cmd *cobra.Command, args []string) error {
+	size, err := units.ParseByteSizeString(c.flagSize)
+	if err != nil {
+		return err
+	}
+
+	// Get the containers
+	containers, err := benchmark.GetContainers(c.global.srv)
+	if err != nil {
+		return err
+	}
+
+	// Run the test
+	results, err := benchmark.TransferFiles(c.global.srv, containers, c.flagIterations, c.global.flagParallel, c.flagPath, size)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
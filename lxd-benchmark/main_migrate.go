@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+	cli "github.com/canonical/lxd/shared/cmd"
+)
+
+type cmdMigrate struct {
+	global *cmdGlobal
+
+	flagTarget string
+}
+
+func (c *cmdMigrate) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "migrate"
+	cmd.Short = "Measure container migration latency between cluster members"
+	cmd.RunE = c.Run
+	cmd.Flags().StringVar(&c.flagTarget, "target", "", cli.FormatStringFlagLabel("Cluster member to move the stopped containers to"))
+
+	return cmd
+}
+
+func (c *cmdMigrate) Run(cmd *cobra.Command, args []string) error {
+	if c.flagTarget == "" {
+		return errors.New("A target cluster member is required")
+	}
+
+	// Get the containers
+	containers, err := benchmark.GetContainers(c.global.srv)
+	if err != nil {
+		return err
+	}
+
+	// Run the test
+	results, err := benchmark.MigrateContainers(c.global.srv, containers, c.global.flagParallel, c.flagTarget)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/canonical/lxd/lxd-benchmark/benchmark"
+)
+
+type cmdSnapshot struct {
+	global *cmdGlobal
+
+	flagIterations int
+}
+
+func (c *cmdSnapshot) Command() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Use = "snapshot"
+	cmd.Short = "Measure snapshot create, restore and delete latency"
+	cmd.RunE = c.Run
+	cmd.Flags().IntVar(&c.flagIterations, "iterations", 1, "Number of snapshots to create and restore for each container")
+
+	return cmd
+}
+
+func (c *cmdSnapshot) Run(cmd *cobra.Command, args []string) error {
+	// Get the containers
+	containers, err := benchmark.GetContainers(c.global.srv)
+	if err != nil {
+		return err
+	}
+
+	// Run the test
+	results, err := benchmark.SnapshotContainers(c.global.srv, containers, c.flagIterations, c.global.flagParallel)
+	if err != nil {
+		return err
+	}
+
+	c.global.reportResults = results
+
+	return nil
+}
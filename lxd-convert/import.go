@@ -0,0 +1,495 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/migration"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+// importDescriptor is the hardware description of a virtual machine exported from another hypervisor.
+type importDescriptor struct {
+	Name       string
+	CPUs       int64
+	Memory     int64
+	Firmware   string
+	SecureBoot bool
+	NICs       []importNIC
+
+	// Disks are the paths of the disk files, the first one being the boot disk.
+	Disks []string
+}
+
+// importNIC is a network adapter of an imported virtual machine.
+type importNIC struct {
+	// Network is the name of the network on the source hypervisor.
+	Network string
+	MAC     string
+}
+
+// importSource is an OVA package, an OVF descriptor or a VMX file used as the source of a conversion.
+type importSource struct {
+	descriptor *importDescriptor
+
+	// workDir holds the extracted OVA package and the disks converted locally.
+	workDir string
+}
+
+// isImportSource returns whether the given path is an OVA package, an OVF descriptor or a VMX file.
+func isImportSource(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ova", ".ovf", ".vmx":
+		return true
+	}
+
+	return false
+}
+
+// loadImportSource reads the hardware description of an OVA package, an OVF descriptor or a VMX file.
+// OVA packages are extracted into a temporary directory, which is removed by cleanup.
+func loadImportSource(path string) (*importSource, error) {
+	workDir, err := os.MkdirTemp("", "lxd-convert_import_")
+	if err != nil {
+		return nil, err
+	}
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	source := &importSource{workDir: workDir}
+	revert.Add(source.cleanup)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ova":
+		ovfPath, err := extractOVA(path, workDir)
+		if err != nil {
+			return nil, err
+		}
+
+		source.descriptor, err = parseImportFile(ovfPath, parseOVF)
+		if err != nil {
+			return nil, err
+		}
+
+	case ".ovf":
+		source.descriptor, err = parseImportFile(path, parseOVF)
+		if err != nil {
+			return nil, err
+		}
+
+	case ".vmx":
+		source.descriptor, err = parseImportFile(path, parseVMX)
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported source %q", path)
+	}
+
+	for _, disk := range source.descriptor.Disks {
+		if !shared.PathExists(disk) {
+			return nil, fmt.Errorf("Disk %q not found", disk)
+		}
+	}
+
+	revert.Success()
+	return source, nil
+}
+
+// parseImportFile opens the descriptor at path and parses it with the given parser.
+func parseImportFile(path string, parse func(r io.Reader, dir string) (*importDescriptor, error)) (*importDescriptor, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	return parse(f, filepath.Dir(path))
+}
+
+// extractOVA extracts an OVA package into dir and returns the path of its OVF descriptor.
+func extractOVA(path string, dir string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	fmt.Printf("Extracting %q\n", path)
+
+	ovfPath := ""
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("Failed reading OVA package: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// OVA packages are flat, this also prevents writing outside of the target directory.
+		target := filepath.Join(dir, filepath.Base(hdr.Name))
+
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return "", err
+		}
+
+		_, err = io.Copy(out, tr)
+		if err != nil {
+			_ = out.Close()
+			return "", fmt.Errorf("Failed extracting %q: %w", hdr.Name, err)
+		}
+
+		err = out.Close()
+		if err != nil {
+			return "", err
+		}
+
+		if ovfPath == "" && strings.EqualFold(filepath.Ext(target), ".ovf") {
+			ovfPath = target
+		}
+	}
+
+	if ovfPath == "" {
+		return "", errors.New("OVA package doesn't contain an OVF descriptor")
+	}
+
+	return ovfPath, nil
+}
+
+// cleanup removes the temporary files of the import.
+func (s *importSource) cleanup() {
+	_ = os.RemoveAll(s.workDir)
+}
+
+// apply maps the hardware of the imported virtual machine to the configuration and devices of the new instance.
+// Configuration keys set by the user are kept. The NICs are connected to the network mapped to their source
+// network in networks, or to defaultNetwork.
+func (s *importSource) apply(config *cmdConvertData, networks map[string]string, defaultNetwork string) {
+	desc := s.descriptor
+	instConfig := config.InstanceArgs.Config
+
+	setDefault := func(key string, value string) {
+		_, ok := instConfig[key]
+		if !ok {
+			instConfig[key] = value
+		}
+	}
+
+	if desc.CPUs > 0 {
+		setDefault("limits.cpu", strconv.FormatInt(desc.CPUs, 10))
+	}
+
+	if desc.Memory > 0 {
+		if desc.Memory%(1<<20) == 0 {
+			setDefault("limits.memory", fmt.Sprintf("%dMiB", desc.Memory>>20))
+		} else {
+			setDefault("limits.memory", fmt.Sprintf("%dB", desc.Memory))
+		}
+	}
+
+	switch {
+	case desc.Firmware != "efi":
+		setDefault("boot.mode", instancetype.BootModeBIOS)
+	case desc.SecureBoot:
+		setDefault("boot.mode", instancetype.BootModeUEFISecureBoot)
+	default:
+		setDefault("boot.mode", instancetype.BootModeUEFINoSecureBoot)
+	}
+
+	for i, nic := range desc.NICs {
+		name := "eth" + strconv.Itoa(i)
+
+		network := networks[nic.Network]
+		if network == "" {
+			network = defaultNetwork
+		}
+
+		if network == "" {
+			fmt.Printf("No network provided for NIC %q (source network %q), it won't be created\n", name, nic.Network)
+			continue
+		}
+
+		device := map[string]string{
+			"type":    "nic",
+			"name":    name,
+			"network": network,
+		}
+
+		if nic.MAC != "" {
+			device["hwaddr"] = strings.ToLower(nic.MAC)
+		}
+
+		config.InstanceArgs.Devices[name] = device
+	}
+
+	config.InstanceArgs.Type = api.InstanceTypeVM
+	config.SourcePath = desc.Disks[0]
+	config.AdditionalDisks = desc.Disks[1:]
+}
+
+// isVMDKDescriptor returns whether the file at path is a text VMDK descriptor referencing separate extent files.
+// Such disks need to be converted locally as only a single file can be transferred to the server.
+func isVMDKDescriptor(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	buf := make([]byte, 1024)
+	n, err := f.Read(buf)
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+
+	return strings.Contains(string(buf[:n]), "# Disk DescriptorFile"), nil
+}
+
+// convertDiskToRaw converts the disk at path to a raw disk image in the work directory and returns its path.
+// Raw disks are returned unchanged.
+func (s *importSource) convertDiskToRaw(ctx context.Context, path string) (string, error) {
+	isRaw, err := isImageTypeRaw(path)
+	if err != nil {
+		return "", err
+	}
+
+	if isRaw {
+		return path, nil
+	}
+
+	_, err = exec.LookPath("qemu-img")
+	if err != nil {
+		return "", fmt.Errorf("Converting disk %q requires qemu-img: %w", path, err)
+	}
+
+	target := filepath.Join(s.workDir, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))+".raw")
+
+	fmt.Printf("Converting disk %q to raw format\n", path)
+
+	out, err := exec.CommandContext(ctx, "qemu-img", "convert", "-O", "raw", path, target).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("Failed converting disk %q: %v\n%s", path, err, out)
+	}
+
+	return target, nil
+}
+
+// prepareRootDisk converts the boot disk locally when the server can't do it.
+func (s *importSource) prepareRootDisk(ctx context.Context, config *cmdConvertData) error {
+	isDescriptor, err := isVMDKDescriptor(config.SourcePath)
+	if err != nil {
+		return err
+	}
+
+	serverConversion := config.InstanceArgs.Source.Type == api.SourceTypeConversion && slices.Contains(config.InstanceArgs.Source.ConversionOptions, "format")
+	if serverConversion && !isDescriptor {
+		return nil
+	}
+
+	config.SourcePath, err = s.convertDiskToRaw(ctx, config.SourcePath)
+	return err
+}
+
+// importStoragePool returns the storage pool of the root disk of the new instance.
+func importStoragePool(server lxd.InstanceServer, config *cmdConvertData) (string, error) {
+	root, ok := config.InstanceArgs.Devices["root"]
+	if ok && root["pool"] != "" {
+		return root["pool"], nil
+	}
+
+	// Devices of later profiles override those of earlier ones.
+	pool := ""
+	for _, name := range config.InstanceArgs.Profiles {
+		profile, _, err := server.GetProfile(name)
+		if err != nil {
+			return "", err
+		}
+
+		for _, device := range profile.Devices {
+			if device["type"] == "disk" && device["path"] == "/" && device["pool"] != "" {
+				pool = device["pool"]
+			}
+		}
+	}
+
+	if pool == "" {
+		return "", errors.New("Failed finding the storage pool of the root disk, please provide one with --storage")
+	}
+
+	return pool, nil
+}
+
+// importAdditionalDisks transfers the additional disks of an imported virtual machine to custom block volumes
+// and attaches them to the new instance. The volumes are deleted by the reverter.
+func (s *importSource) importAdditionalDisks(ctx context.Context, server lxd.InstanceServer, config *cmdConvertData, reverter *revert.Reverter) error {
+	if len(config.AdditionalDisks) == 0 {
+		return nil
+	}
+
+	if config.InstanceArgs.Name == "" {
+		return errors.New("Instance name is required to import additional disks")
+	}
+
+	pool, err := importStoragePool(server, config)
+	if err != nil {
+		return err
+	}
+
+	for i, disk := range config.AdditionalDisks {
+		path, err := s.convertDiskToRaw(ctx, disk)
+		if err != nil {
+			return err
+		}
+
+		stat, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		deviceName := "disk" + strconv.Itoa(i+1)
+		volName := config.InstanceArgs.Name + "-" + deviceName
+
+		req := api.StorageVolumesPost{
+			Name:        volName,
+			Type:        "custom",
+			ContentType: "block",
+			StorageVolumePut: api.StorageVolumePut{
+				Config: map[string]string{
+					"size": strconv.FormatInt(stat.Size(), 10) + "B",
+				},
+			},
+			Source: api.StorageVolumeSource{
+				Type: api.SourceTypeMigration,
+				Mode: "push",
+			},
+		}
+
+		op, err := server.CreateStoragePoolVolume(pool, req)
+		if err != nil {
+			return fmt.Errorf("Failed creating volume %q: %w", volName, err)
+		}
+
+		reverter.Add(func() {
+			_, _ = server.DeleteStoragePoolVolume(pool, "custom", volName)
+		})
+
+		fmt.Printf("Transferring disk %q to volume %q\n", disk, volName)
+
+		err = transferBlockVolume(ctx, op, path)
+		if err != nil {
+			return fmt.Errorf("Failed transferring disk %q: %w", disk, err)
+		}
+
+		config.InstanceArgs.Devices[deviceName] = map[string]string{
+			"type":   "disk",
+			"pool":   pool,
+			"source": volName,
+		}
+	}
+
+	return nil
+}
+
+// transferBlockVolume sends a raw disk image to a custom block volume being created in push migration mode.
+func transferBlockVolume(ctx context.Context, op lxd.Operation, path string) error {
+	opAPI := op.Get()
+
+	wsControl, err := op.GetWebsocket(opAPI.Metadata[api.SecretNameControl].(string))
+	if err != nil {
+		return err
+	}
+
+	abort := func(err error) error {
+		protoSendError(wsControl, err)
+		return err
+	}
+
+	wsFs, err := op.GetWebsocket(opAPI.Metadata[api.SecretNameFilesystem].(string))
+	if err != nil {
+		return abort(err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		return abort(err)
+	}
+
+	fs := migration.MigrationFSType_BLOCK_AND_RSYNC
+	rsyncHasFeature := false
+	size := stat.Size()
+
+	offerHeader := migration.MigrationHeader{
+		RsyncFeatures: &migration.RsyncFeatures{
+			Xattrs:   &rsyncHasFeature,
+			Delete:   &rsyncHasFeature,
+			Compress: &rsyncHasFeature,
+		},
+		Fs:         &fs,
+		VolumeSize: &size,
+	}
+
+	err = migration.ProtoSend(wsControl, &offerHeader)
+	if err != nil {
+		return abort(err)
+	}
+
+	var respHeader migration.MigrationHeader
+	err = migration.ProtoRecv(wsControl, &respHeader)
+	if err != nil {
+		return abort(err)
+	}
+
+	rsyncFeaturesOffered := offerHeader.GetRsyncFeaturesSlice()
+	rsyncFeaturesResponse := respHeader.GetRsyncFeaturesSlice()
+
+	if !reflect.DeepEqual(rsyncFeaturesOffered, rsyncFeaturesResponse) {
+		return abort(fmt.Errorf("Offered rsync features (%v) differ from those in the migration response (%v)", rsyncFeaturesOffered, rsyncFeaturesResponse))
+	}
+
+	// Custom block volumes only have a block part.
+	err = sendBlockVol(ctx, ws.NewWrapper(wsFs), path)
+	if err != nil {
+		return abort(err)
+	}
+
+	msg := migration.MigrationControl{}
+	err = migration.ProtoRecv(wsControl, &msg)
+	if err != nil {
+		_ = wsControl.Close()
+		return err
+	}
+
+	if !msg.GetSuccess() {
+		return errors.New(msg.GetMessage())
+	}
+
+	return op.Wait()
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// OVF (DMTF DSP0243) resource types of the virtual hardware items used for the conversion.
+const (
+	ovfResourceTypeCPU      = 3
+	ovfResourceTypeMemory   = 4
+	ovfResourceTypeEthernet = 10
+	ovfResourceTypeDisk     = 17
+)
+
+type ovfEnvelope struct {
+	Files         []ovfFile          `xml:"References>File"`
+	Disks         []ovfDisk          `xml:"DiskSection>Disk"`
+	Systems       []ovfVirtualSystem `xml:"VirtualSystem"`
+	SystemsNested []ovfVirtualSystem `xml:"VirtualSystemCollection>VirtualSystem"`
+}
+
+type ovfFile struct {
+	ID          string `xml:"id,attr"`
+	Href        string `xml:"href,attr"`
+	Compression string `xml:"compression,attr"`
+}
+
+type ovfDisk struct {
+	DiskID  string `xml:"diskId,attr"`
+	FileRef string `xml:"fileRef,attr"`
+}
+
+type ovfVirtualSystem struct {
+	ID       string      `xml:"id,attr"`
+	Name     string      `xml:"Name"`
+	Hardware ovfHardware `xml:"VirtualHardwareSection"`
+}
+
+type ovfHardware struct {
+	Items             []ovfItem   `xml:"Item"`
+	StorageItems      []ovfItem   `xml:"StorageItem"`
+	EthernetPortItems []ovfItem   `xml:"EthernetPortItem"`
+	Config            []ovfConfig `xml:"Config"`
+}
+
+type ovfItem struct {
+	ResourceType    int      `xml:"ResourceType"`
+	VirtualQuantity int64    `xml:"VirtualQuantity"`
+	AllocationUnits string   `xml:"AllocationUnits"`
+	Address         string   `xml:"Address"`
+	Connection      string   `xml:"Connection"`
+	HostResource    []string `xml:"HostResource"`
+}
+
+// ovfConfig is a VMware specific configuration entry of the virtual hardware.
+type ovfConfig struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// parseOVF reads the hardware description of the first virtual system of an OVF descriptor.
+// The disk paths are resolved relative to dir.
+func parseOVF(r io.Reader, dir string) (*importDescriptor, error) {
+	envelope := ovfEnvelope{}
+	err := xml.NewDecoder(r).Decode(&envelope)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing OVF descriptor: %w", err)
+	}
+
+	systems := append(envelope.Systems, envelope.SystemsNested...)
+	if len(systems) == 0 {
+		return nil, errors.New("OVF descriptor doesn't contain any virtual system")
+	}
+
+	system := systems[0]
+
+	desc := &importDescriptor{
+		Name:     system.Name,
+		Firmware: "bios",
+	}
+
+	if desc.Name == "" {
+		desc.Name = system.ID
+	}
+
+	for _, config := range system.Hardware.Config {
+		switch strings.ToLower(config.Key) {
+		case "firmware":
+			desc.Firmware = strings.ToLower(config.Value)
+		case "uefi.secureboot.enabled":
+			desc.SecureBoot = strings.EqualFold(config.Value, "true")
+		}
+	}
+
+	items := append(system.Hardware.Items, system.Hardware.StorageItems...)
+	items = append(items, system.Hardware.EthernetPortItems...)
+
+	for _, item := range items {
+		switch item.ResourceType {
+		case ovfResourceTypeCPU:
+			desc.CPUs = item.VirtualQuantity
+		case ovfResourceTypeMemory:
+			multiplier, err := ovfAllocationUnits(item.AllocationUnits)
+			if err != nil {
+				return nil, err
+			}
+
+			desc.Memory = item.VirtualQuantity * multiplier
+		case ovfResourceTypeEthernet:
+			desc.NICs = append(desc.NICs, importNIC{Network: item.Connection, MAC: item.Address})
+		case ovfResourceTypeDisk:
+			if len(item.HostResource) == 0 {
+				continue
+			}
+
+			href, err := ovfDiskFile(envelope, item.HostResource[0])
+			if err != nil {
+				return nil, err
+			}
+
+			desc.Disks = append(desc.Disks, filepath.Join(dir, filepath.Base(href)))
+		}
+	}
+
+	if len(desc.Disks) == 0 {
+		return nil, errors.New("OVF descriptor doesn't contain any disk")
+	}
+
+	return desc, nil
+}
+
+// ovfDiskFile returns the reference of the file backing the disk with the given host resource, such as
+// "ovf:/disk/vmdisk1" or "ovf:/file/file1".
+func ovfDiskFile(envelope ovfEnvelope, hostResource string) (string, error) {
+	resource := strings.TrimPrefix(strings.TrimPrefix(hostResource, "ovf:"), "/")
+
+	kind, id, found := strings.Cut(resource, "/")
+	if !found {
+		return "", fmt.Errorf("Invalid disk host resource %q", hostResource)
+	}
+
+	fileRef := id
+	if kind == "disk" {
+		fileRef = ""
+		for _, disk := range envelope.Disks {
+			if disk.DiskID == id {
+				fileRef = disk.FileRef
+				break
+			}
+		}
+
+		if fileRef == "" {
+			return "", fmt.Errorf("Disk %q not found in the OVF descriptor", id)
+		}
+	} else if kind != "file" {
+		return "", fmt.Errorf("Invalid disk host resource %q", hostResource)
+	}
+
+	for _, file := range envelope.Files {
+		if file.ID != fileRef {
+			continue
+		}
+
+		if file.Compression != "" {
+			return "", fmt.Errorf("Compressed disk file %q isn't supported", file.Href)
+		}
+
+		return file.Href, nil
+	}
+
+	return "", fmt.Errorf("File %q not found in the OVF descriptor", fileRef)
+}
+
+// ovfAllocationUnits returns the number of bytes of the given OVF allocation unit, such as "byte * 2^20".
+func ovfAllocationUnits(units string) (int64, error) {
+	units = strings.ReplaceAll(strings.ToLower(units), " ", "")
+
+	switch units {
+	case "", "megabytes", "mb":
+		return 1 << 20, nil
+	case "byte", "bytes":
+		return 1, nil
+	case "kilobytes", "kb":
+		return 1 << 10, nil
+	case "gigabytes", "gb":
+		return 1 << 30, nil
+	}
+
+	exponent, found := strings.CutPrefix(units, "byte*2^")
+	if found {
+		n, err := strconv.Atoi(exponent)
+		if err == nil && n >= 0 && n < 63 {
+			return 1 << n, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unsupported OVF allocation units %q", units)
+}
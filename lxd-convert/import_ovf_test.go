@@ -0,0 +1,209 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ovfTestDescriptor is an OVF descriptor exported by vSphere, with a disk referenced through the disk section, a disk
+// referenced directly by its file, a CD-ROM and a network adapter.
+const ovfTestDescriptor = `<?xml version="1.0" encoding="UTF-8"?>
+<Envelope xmlns="http://schemas.dmtf.org/ovf/envelope/1" xmlns:ovf="http://schemas.dmtf.org/ovf/envelope/1" xmlns:rasd="http://schemas.dmtf.org/wbem/wscim/1/cim-schema/2/CIM_ResourceAllocationSettingData" xmlns:vmw="http://www.vmware.com/schema/ovf">
+  <References>
+    <File ovf:id="file1" ovf:href="web-disk1.vmdk"/>
+    <File ovf:id="file2" ovf:href="disks/web-disk2.vmdk"/>
+    <File ovf:id="file3" ovf:href="install.iso"/>
+  </References>
+  <DiskSection>
+    <Disk ovf:diskId="vmdisk1" ovf:fileRef="file1" ovf:capacity="20"/>
+  </DiskSection>
+  <VirtualSystem ovf:id="vm-42">
+    <Name>web</Name>
+    <VirtualHardwareSection>
+      <Item>
+        <rasd:ResourceType>3</rasd:ResourceType>
+        <rasd:VirtualQuantity>4</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:AllocationUnits>byte * 2^20</rasd:AllocationUnits>
+        <rasd:ResourceType>4</rasd:ResourceType>
+        <rasd:VirtualQuantity>2048</rasd:VirtualQuantity>
+      </Item>
+      <Item>
+        <rasd:HostResource>ovf:/disk/vmdisk1</rasd:HostResource>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:HostResource>ovf:/file/file2</rasd:HostResource>
+        <rasd:ResourceType>17</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:HostResource>ovf:/file/file3</rasd:HostResource>
+        <rasd:ResourceType>15</rasd:ResourceType>
+      </Item>
+      <Item>
+        <rasd:Address>00:50:56:aa:bb:cc</rasd:Address>
+        <rasd:Connection>VM Network</rasd:Connection>
+        <rasd:ResourceType>10</rasd:ResourceType>
+      </Item>
+      <vmw:Config ovf:required="false" vmw:key="firmware" vmw:value="efi"/>
+      <vmw:Config ovf:required="false" vmw:key="uefi.secureBoot.enabled" vmw:value="true"/>
+    </VirtualHardwareSection>
+  </VirtualSystem>
+</Envelope>
+`
+
+func TestParseOVF(t *testing.T) {
+	desc, err := parseOVF(strings.NewReader(ovfTestDescriptor), "/tmp/web")
+	require.NoError(t, err)
+
+	assert.Equal(t, &importDescriptor{
+		Name:       "web",
+		CPUs:       4,
+		Memory:     2 << 30,
+		Firmware:   "efi",
+		SecureBoot: true,
+		NICs:       []importNIC{{Network: "VM Network", MAC: "00:50:56:aa:bb:cc"}},
+		Disks:      []string{"/tmp/web/web-disk1.vmdk", "/tmp/web/web-disk2.vmdk"},
+	}, desc)
+}
+
+func TestParseOVF_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		descriptor string
+		err        string
+	}{
+		{
+			name:       "Invalid XML",
+			descriptor: "<Envelope>",
+			err:        "Failed parsing OVF descriptor",
+		},
+		{
+			name:       "No virtual system",
+			descriptor: "<Envelope></Envelope>",
+			err:        "doesn't contain any virtual system",
+		},
+		{
+			name:       "No disk",
+			descriptor: "<Envelope><VirtualSystem><Name>web</Name></VirtualSystem></Envelope>",
+			err:        "doesn't contain any disk",
+		},
+		{
+			name: "Invalid memory units",
+			descriptor: `<Envelope><VirtualSystem><VirtualHardwareSection>
+<Item><ResourceType>4</ResourceType><VirtualQuantity>1</VirtualQuantity><AllocationUnits>pages</AllocationUnits></Item>
+</VirtualHardwareSection></VirtualSystem></Envelope>`,
+			err: `Unsupported OVF allocation units "pages"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseOVF(strings.NewReader(tt.descriptor), "/tmp")
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseOVF_NestedSystem(t *testing.T) {
+	descriptor := `<Envelope>
+  <References><File id="file1" href="disk1.vmdk"/></References>
+  <VirtualSystemCollection>
+    <VirtualSystem id="vm-1">
+      <VirtualHardwareSection>
+        <StorageItem><ResourceType>17</ResourceType><HostResource>ovf:/file/file1</HostResource></StorageItem>
+        <EthernetPortItem><ResourceType>10</ResourceType><Connection>lan</Connection></EthernetPortItem>
+      </VirtualHardwareSection>
+    </VirtualSystem>
+  </VirtualSystemCollection>
+</Envelope>`
+
+	desc, err := parseOVF(strings.NewReader(descriptor), "/tmp")
+	require.NoError(t, err)
+
+	// The ID names the virtual system if it has no name, and the firmware defaults to BIOS.
+	assert.Equal(t, "vm-1", desc.Name)
+	assert.Equal(t, "bios", desc.Firmware)
+	assert.False(t, desc.SecureBoot)
+	assert.Equal(t, []importNIC{{Network: "lan"}}, desc.NICs)
+	assert.Equal(t, []string{"/tmp/disk1.vmdk"}, desc.Disks)
+}
+
+func TestOVFDiskFile(t *testing.T) {
+	envelope := ovfEnvelope{
+		Files: []ovfFile{
+			{ID: "file1", Href: "disk1.vmdk"},
+			{ID: "file2", Href: "disk2.vmdk.gz", Compression: "gzip"},
+		},
+		Disks: []ovfDisk{
+			{DiskID: "vmdisk1", FileRef: "file1"},
+			{DiskID: "vmdisk2", FileRef: "file2"},
+			{DiskID: "vmdisk3", FileRef: "file3"},
+		},
+	}
+
+	tests := []struct {
+		hostResource string
+		href         string
+		err          string
+	}{
+		{hostResource: "ovf:/disk/vmdisk1", href: "disk1.vmdk"},
+		{hostResource: "/disk/vmdisk1", href: "disk1.vmdk"},
+		{hostResource: "ovf:/file/file1", href: "disk1.vmdk"},
+		{hostResource: "ovf:/disk/vmdisk2", err: `Compressed disk file "disk2.vmdk.gz" isn't supported`},
+		{hostResource: "ovf:/disk/vmdisk3", err: `File "file3" not found`},
+		{hostResource: "ovf:/disk/vmdisk4", err: `Disk "vmdisk4" not found`},
+		{hostResource: "ovf:/file/file4", err: `File "file4" not found`},
+		{hostResource: "ovf:/device/vmdisk1", err: "Invalid disk host resource"},
+		{hostResource: "vmdisk1", err: "Invalid disk host resource"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostResource, func(t *testing.T) {
+			href, err := ovfDiskFile(envelope, tt.hostResource)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.href, href)
+		})
+	}
+}
+
+func TestOVFAllocationUnits(t *testing.T) {
+	tests := []struct {
+		units string
+		bytes int64
+		err   bool
+	}{
+		{units: "", bytes: 1 << 20},
+		{units: "MegaBytes", bytes: 1 << 20},
+		{units: "byte", bytes: 1},
+		{units: "KB", bytes: 1 << 10},
+		{units: "gigabytes", bytes: 1 << 30},
+		{units: "byte * 2^20", bytes: 1 << 20},
+		{units: "byte*2^30", bytes: 1 << 30},
+		{units: "byte * 2^63", err: true},
+		{units: "byte * 2^x", err: true},
+		{units: "pages", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.units, func(t *testing.T) {
+			bytes, err := ovfAllocationUnits(tt.units)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.bytes, bytes)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// vmxDiskKey matches the file name keys of the disks attached to the SCSI, SATA, NVMe and IDE controllers.
+var vmxDiskKey = regexp.MustCompile(`^((scsi|sata|nvme|ide)([0-9]+):([0-9]+))\.filename$`)
+
+// vmxBuses are the disk controller types, in the order their disks are tried when booting.
+var vmxBuses = []string{"scsi", "sata", "nvme", "ide"}
+
+// vmxDisk is a disk slot of a VMX file, such as "scsi0:1".
+type vmxDisk struct {
+	name       string
+	bus        string
+	controller int
+	unit       int
+}
+
+// vmxNICKey matches the keys of the network adapters.
+var vmxNICKey = regexp.MustCompile(`^(ethernet[0-9]+)\.present$`)
+
+// parseVMX reads the hardware description of a VMware virtual machine configuration file.
+// The disk paths are resolved relative to dir.
+func parseVMX(r io.Reader, dir string) (*importDescriptor, error) {
+	values := map[string]string{}
+	var disks []vmxDisk
+	var nics []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ".encoding") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		values[key] = value
+
+		match := vmxDiskKey.FindStringSubmatch(key)
+		if match != nil {
+			controller, _ := strconv.Atoi(match[3])
+			unit, _ := strconv.Atoi(match[4])
+			disks = append(disks, vmxDisk{name: match[1], bus: match[2], controller: controller, unit: unit})
+		}
+
+		// Keep the network adapters in the order of the file.
+		match = vmxNICKey.FindStringSubmatch(key)
+		if match != nil && !slices.Contains(nics, match[1]) {
+			nics = append(nics, match[1])
+		}
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("Failed reading VMX file: %w", err)
+	}
+
+	desc := &importDescriptor{
+		Name:       values["displayname"],
+		Firmware:   "bios",
+		SecureBoot: strings.EqualFold(values["uefi.secureboot.enabled"], "true"),
+	}
+
+	if strings.EqualFold(values["firmware"], "efi") {
+		desc.Firmware = "efi"
+	}
+
+	if values["numvcpus"] != "" {
+		desc.CPUs, err = strconv.ParseInt(values["numvcpus"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid CPU count %q: %w", values["numvcpus"], err)
+		}
+	}
+
+	if values["memsize"] != "" {
+		memory, err := strconv.ParseInt(values["memsize"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid memory size %q: %w", values["memsize"], err)
+		}
+
+		desc.Memory = memory << 20
+	}
+
+	for _, nic := range nics {
+		if !strings.EqualFold(values[nic+".present"], "true") {
+			continue
+		}
+
+		mac := values[nic+".address"]
+		if mac == "" {
+			mac = values[nic+".generatedaddress"]
+		}
+
+		desc.NICs = append(desc.NICs, importNIC{Network: values[nic+".networkname"], MAC: mac})
+	}
+
+	// Order the disks by slot so that the boot disk comes first, unless the BIOS boot disk is set explicitly.
+	bootDisk := strings.ToLower(strings.TrimSpace(strings.Split(values["bios.hddorder"], ",")[0]))
+	slices.SortFunc(disks, func(a vmxDisk, b vmxDisk) int {
+		if a.name == bootDisk || b.name == bootDisk {
+			return cmp.Compare(vmxDiskIsBoot(b, bootDisk), vmxDiskIsBoot(a, bootDisk))
+		}
+
+		return cmp.Or(
+			cmp.Compare(slices.Index(vmxBuses, a.bus), slices.Index(vmxBuses, b.bus)),
+			cmp.Compare(a.controller, b.controller),
+			cmp.Compare(a.unit, b.unit),
+		)
+	})
+
+	for _, disk := range disks {
+		if values[disk.name+".present"] != "" && !strings.EqualFold(values[disk.name+".present"], "true") {
+			continue
+		}
+
+		// Skip CD-ROM drives.
+		if strings.Contains(strings.ToLower(values[disk.name+".devicetype"]), "cdrom") {
+			continue
+		}
+
+		path := values[disk.name+".filename"]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+
+		desc.Disks = append(desc.Disks, path)
+	}
+
+	if len(desc.Disks) == 0 {
+		return nil, errors.New("VMX file doesn't contain any disk")
+	}
+
+	return desc, nil
+}
+
+// vmxDiskIsBoot returns 1 if the disk is the given boot disk, and 0 otherwise.
+func vmxDiskIsBoot(disk vmxDisk, bootDisk string) int {
+	if disk.name == bootDisk {
+		return 1
+	}
+
+	return 0
+}
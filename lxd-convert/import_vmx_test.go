@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVMX(t *testing.T) {
+	tests := []struct {
+		name string
+		vmx  string
+		want *importDescriptor
+		err  string
+	}{
+		{
+			name: "EFI with secure boot",
+			vmx: `.encoding = "UTF-8"
+displayName = "web"
+firmware = "efi"
+uefi.secureBoot.enabled = "TRUE"
+numvcpus = "2"
+memSize = "4096"
+# The data disk comes first in the file, but the boot disk is on the first slot.
+scsi0:1.fileName = "web_1.vmdk"
+scsi0:1.present = "TRUE"
+scsi0:0.fileName = "web.vmdk"
+scsi0:0.present = "TRUE"
+sata0:0.deviceType = "cdrom-image"
+sata0:0.fileName = "/vmfs/volumes/iso/install.iso"
+sata0:0.present = "TRUE"
+ethernet0.present = "TRUE"
+ethernet0.networkName = "VM Network"
+ethernet0.addressType = "generated"
+ethernet0.generatedAddress = "00:0c:29:aa:bb:cc"
+ethernet1.present = "TRUE"
+ethernet1.networkName = "Storage"
+ethernet1.addressType = "static"
+ethernet1.address = "00:50:56:00:00:01"
+ethernet2.present = "FALSE"
+ethernet2.networkName = "Unused"
+`,
+			want: &importDescriptor{
+				Name:       "web",
+				CPUs:       2,
+				Memory:     4 << 30,
+				Firmware:   "efi",
+				SecureBoot: true,
+				NICs: []importNIC{
+					{Network: "VM Network", MAC: "00:0c:29:aa:bb:cc"},
+					{Network: "Storage", MAC: "00:50:56:00:00:01"},
+				},
+				Disks: []string{"/vm/web.vmdk", "/vm/web_1.vmdk"},
+			},
+		},
+		{
+			name: "BIOS with controllers of several types",
+			vmx: `displayName = "db"
+memsize = "1024"
+nvme0:0.fileName = "db_nvme.vmdk"
+ide0:0.fileName = "db_ide.vmdk"
+scsi1:0.fileName = "db_scsi1.vmdk"
+scsi0:2.fileName = "/vmfs/volumes/ds1/db/db_scsi0.vmdk"
+sata0:1.fileName = "db_removed.vmdk"
+sata0:1.present = "FALSE"
+`,
+			want: &importDescriptor{
+				Name:     "db",
+				Memory:   1 << 30,
+				Firmware: "bios",
+				Disks:    []string{"/vmfs/volumes/ds1/db/db_scsi0.vmdk", "/vm/db_scsi1.vmdk", "/vm/db_nvme.vmdk", "/vm/db_ide.vmdk"},
+			},
+		},
+		{
+			name: "BIOS boot disk",
+			vmx: `displayName = "app"
+bios.hddOrder = "sata0:1"
+sata0:0.fileName = "app_data.vmdk"
+sata0:1.fileName = "app.vmdk"
+`,
+			want: &importDescriptor{
+				Name:     "app",
+				Firmware: "bios",
+				Disks:    []string{"/vm/app.vmdk", "/vm/app_data.vmdk"},
+			},
+		},
+		{
+			name: "Only a CD-ROM",
+			vmx: `displayName = "live"
+ide1:0.deviceType = "atapi-cdrom"
+ide1:0.fileName = "auto detect"
+`,
+			err: "VMX file doesn't contain any disk",
+		},
+		{
+			name: "Invalid memory size",
+			vmx: `memsize = "4G"
+scsi0:0.fileName = "web.vmdk"
+`,
+			err: `Invalid memory size "4G"`,
+		},
+		{
+			name: "Invalid CPU count",
+			vmx: `numvcpus = "two"
+scsi0:0.fileName = "web.vmdk"
+`,
+			err: `Invalid CPU count "two"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, err := parseVMX(strings.NewReader(tt.vmx), "/vm")
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, desc)
+		})
+	}
+}
@@ -43,6 +43,7 @@ type cmdConvert struct {
 	flagStorage      string
 	flagStorageSize  string
 	flagNetwork      string
+	flagNetworkMap   []string
 	flagMountPaths   []string
 	flagConfig       []string
 	flagSource       string
@@ -72,6 +73,10 @@ func (c *cmdConvert) command() *cobra.Command {
   It will setup a clean mount tree made of the root filesystem and any
   additional mount you list, then transfer this through LXD's conversion
   API to create a new instance from it.
+
+  Virtual machines exported from other hypervisors as an OVA package, an OVF
+  descriptor or a VMware VMX file can be used as the source. Their CPU count,
+  memory, firmware, network adapters and disks are mapped to the new instance.
 `
 	cmd.RunE = c.run
 
@@ -84,9 +89,10 @@ func (c *cmdConvert) command() *cobra.Command {
 	cmd.Flags().StringVar(&c.flagStorage, "storage", "", cli.FormatStringFlagLabel("Storage pool name"))
 	cmd.Flags().StringVar(&c.flagStorageSize, "storage-size", "", cli.FormatStringFlagLabel("Size of the instance's storage volume"))
 	cmd.Flags().StringVar(&c.flagNetwork, "network", "", cli.FormatStringFlagLabel("Network name"))
+	cmd.Flags().StringArrayVar(&c.flagNetworkMap, "network-map", nil, cli.FormatStringFlagLabel("Network to connect the NICs of an imported virtual machine to, as source=target"))
 	cmd.Flags().StringArrayVar(&c.flagMountPaths, "mount-path", nil, cli.FormatStringFlagLabel("Additional container mount paths"))
 	cmd.Flags().StringArrayVarP(&c.flagConfig, "config", "c", nil, cli.FormatStringFlagLabel("Config key/value to apply to the new instance"))
-	cmd.Flags().StringVar(&c.flagSource, "source", "", cli.FormatStringFlagLabel("Path to the root filesystem for containers, or to the block device or disk image file for virtual machines"))
+	cmd.Flags().StringVar(&c.flagSource, "source", "", cli.FormatStringFlagLabel("Path to the root filesystem for containers, or to the block device, disk image file, OVA package, OVF descriptor or VMX file for virtual machines"))
 	// Target server.
 	cmd.Flags().StringVar(&c.flagServer, "server", "", cli.FormatStringFlagLabel("Unix or HTTPS URL of the target server"))
 	cmd.Flags().StringVar(&c.flagToken, "token", "", cli.FormatStringFlagLabel("Authentication token for HTTPS remote"))
@@ -102,10 +108,15 @@ func (c *cmdConvert) command() *cobra.Command {
 }
 
 type cmdConvertData struct {
-	SourcePath   string
-	Mounts       []string
-	InstanceArgs api.InstancesPost
-	Project      string
+	SourcePath      string
+	AdditionalDisks []string
+	Mounts          []string
+	InstanceArgs    api.InstancesPost
+	Project         string
+
+	// importSource is set when converting a virtual machine from an OVA package, an OVF descriptor or a VMX file.
+	importSource *importSource
+	networkMap   map[string]string
 }
 
 func (c *cmdConvertData) render() string {
@@ -114,6 +125,7 @@ func (c *cmdConvertData) render() string {
 		Project     string            `yaml:"Project"`
 		Type        api.InstanceType  `yaml:"Type"`
 		Source      string            `yaml:"Source"`
+		Disks       []string          `yaml:"Additional disks,omitempty"`
 		Mounts      []string          `yaml:"Mounts,omitempty"`
 		Profiles    []string          `yaml:"Profiles,omitempty"`
 		StoragePool string            `yaml:"Storage pool,omitempty"`
@@ -125,6 +137,7 @@ func (c *cmdConvertData) render() string {
 		c.Project,
 		c.InstanceArgs.Type,
 		c.SourcePath,
+		c.AdditionalDisks,
 		c.Mounts,
 		c.InstanceArgs.Profiles,
 		"",
@@ -508,6 +521,28 @@ func (c *cmdConvert) newConvertData(server lxd.InstanceServer) (*cmdConvertData,
 		}
 	}
 
+	// Parse the networks of imported virtual machines from flags.
+	if len(c.flagNetworkMap) > 0 {
+		networks, err := server.GetNetworkNames()
+		if err != nil {
+			return nil, err
+		}
+
+		config.networkMap = map[string]string{}
+		for _, entry := range c.flagNetworkMap {
+			source, target, found := strings.Cut(entry, "=")
+			if !found {
+				return nil, fmt.Errorf("Invalid network mapping: Entry %q is not in source=target format", entry)
+			}
+
+			if !slices.Contains(networks, target) {
+				return nil, fmt.Errorf("Network %q not found", target)
+			}
+
+			config.networkMap[source] = target
+		}
+	}
+
 	// Configure additional mounts for containers.
 	if len(c.flagMountPaths) > 0 {
 		if config.InstanceArgs.Type != "" && config.InstanceArgs.Type != api.InstanceTypeContainer {
@@ -523,9 +558,51 @@ func (c *cmdConvert) newConvertData(server lxd.InstanceServer) (*cmdConvertData,
 		}
 	}
 
+	// Read the hardware of an imported virtual machine last, so that it doesn't override the flags.
+	if config.SourcePath != "" && isImportSource(config.SourcePath) {
+		err := c.loadImportSource(server, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return config, nil
 }
 
+// loadImportSource reads the OVA package, OVF descriptor or VMX file at the source path and maps its hardware to
+// the new instance. In non-interactive mode, the name of the imported virtual machine is used as the instance
+// name if none is provided and it is available.
+func (c *cmdConvert) loadImportSource(server lxd.InstanceServer, config *cmdConvertData) error {
+	source, err := loadImportSource(config.SourcePath)
+	if err != nil {
+		return fmt.Errorf("Failed reading source %q: %w", config.SourcePath, err)
+	}
+
+	config.importSource = source
+	source.apply(config, config.networkMap, c.flagNetwork)
+
+	if c.flagNonInteractive && config.InstanceArgs.Name == "" && c.importInstanceNameAvailable(server, source.descriptor.Name) {
+		config.InstanceArgs.Name = source.descriptor.Name
+	}
+
+	return nil
+}
+
+// importInstanceNameAvailable returns whether the given name of an imported virtual machine can be used as the
+// instance name.
+func (c *cmdConvert) importInstanceNameAvailable(server lxd.InstanceServer, name string) bool {
+	if name == "" || instancetype.ValidName(name, false) != nil {
+		return false
+	}
+
+	instanceNames, err := server.GetInstanceNames(api.InstanceTypeAny)
+	if err != nil {
+		return false
+	}
+
+	return !slices.Contains(instanceNames, name)
+}
+
 // runInteractive populates the conversion request by interacting with the user. If any value is already
 // provided using flags, the corresponding questions are skipped.
 func (c *cmdConvert) runInteractive(config *cmdConvertData, server lxd.InstanceServer) error {
@@ -580,8 +657,15 @@ func (c *cmdConvert) runInteractive(config *cmdConvertData, server lxd.InstanceS
 			return err
 		}
 
+		question := "Name of the new instance: "
+		defaultName := ""
+		if config.importSource != nil && c.importInstanceNameAvailable(server, config.importSource.descriptor.Name) {
+			defaultName = config.importSource.descriptor.Name
+			question = fmt.Sprintf("Name of the new instance [default=%s]: ", defaultName)
+		}
+
 		for {
-			instanceName, err := c.global.asker.AskString("Name of the new instance: ", "", nil)
+			instanceName, err := c.global.asker.AskString(question, defaultName, nil)
 			if err != nil {
 				return err
 			}
@@ -616,11 +700,18 @@ func (c *cmdConvert) runInteractive(config *cmdConvertData, server lxd.InstanceS
 		if err != nil {
 			return err
 		}
+
+		if isImportSource(config.SourcePath) {
+			err = c.loadImportSource(server, config)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Ask whether the VM supports UEFI secure boot. In non-interactive mode, boot.mode can be
 	// configured using --config flag.
-	if !c.flagNonInteractive && config.InstanceArgs.Type == api.InstanceTypeVM {
+	if !c.flagNonInteractive && config.InstanceArgs.Type == api.InstanceTypeVM && config.InstanceArgs.Config["boot.mode"] == "" {
 		architectureName, _ := osarch.ArchitectureGetLocal()
 
 		if slices.Contains([]string{"x86_64", "aarch64"}, architectureName) {
@@ -726,7 +817,7 @@ func (c *cmdConvert) run(cmd *cobra.Command, args []string) error {
 
 	// Check the required flags in non-interactive mode.
 	if c.flagNonInteractive {
-		if c.flagInstanceType == "" {
+		if c.flagInstanceType == "" && !isImportSource(c.flagSource) {
 			return errors.New("Instance type is required in non-interactive mode")
 		}
 
@@ -806,6 +897,12 @@ func (c *cmdConvert) run(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	defer func() {
+		if config.importSource != nil {
+			config.importSource.cleanup()
+		}
+	}()
+
 	if c.flagNonInteractive {
 		// In non-interactive mode, print the instance to be created and continue with the migration.
 		fmt.Println("\nInstance to be created:")
@@ -881,6 +978,13 @@ func (c *cmdConvert) run(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("Failed setting up the source: %w", err)
 		}
 	} else {
+		if config.importSource != nil {
+			err = config.importSource.prepareRootDisk(ctx, config)
+			if err != nil {
+				return err
+			}
+		}
+
 		isImageTypeRaw, err := isImageTypeRaw(config.SourcePath)
 		if err != nil {
 			return err
@@ -934,6 +1038,14 @@ func (c *cmdConvert) run(cmd *cobra.Command, args []string) error {
 	revert := revert.New()
 	defer revert.Fail()
 
+	// Transfer the additional disks of an imported virtual machine to custom volumes attached to the instance.
+	if config.importSource != nil {
+		err = config.importSource.importAdditionalDisks(ctx, server, config, revert)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Create the instance
 	op, err := server.CreateInstance(config.InstanceArgs)
 	if err != nil {
@@ -1107,6 +1219,15 @@ func (c *cmdConvert) checkSource(path string, instanceType api.InstanceType, mig
 
 	defer file.Close()
 
+	// Disks of OVA packages, OVF descriptors and VMX files are converted during the import.
+	if isImportSource(path) {
+		if instanceType == api.InstanceTypeContainer {
+			return errors.New("OVA, OVF and VMX sources can only be converted to virtual machines")
+		}
+
+		return nil
+	}
+
 	if instanceType == api.InstanceTypeVM && migrationMode == api.SourceTypeMigration {
 		isImageTypeRaw, err := isImageTypeRaw(path)
 		if err != nil {
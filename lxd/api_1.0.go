@@ -65,6 +65,7 @@ var api10 = []APIEndpoint{
 	clusterLinksCmd,
 	clusterLinkStateCmd,
 	clusterCertificateCmd,
+	clusterRebalanceCmd,
 	replicatorCmd,
 	replicatorsCmd,
 	replicatorStateCmd,
@@ -75,12 +76,14 @@ var api10 = []APIEndpoint{
 	instanceConsoleCmd,
 	instanceExecCmd,
 	instanceFileCmd,
+	instanceSBOMCmd,
 	instanceExecOutputCmd,
 	instanceExecOutputsCmd,
 	instanceLogCmd,
 	instanceLogsCmd,
 	instanceMetadataCmd,
 	instanceMetadataTemplatesCmd,
+	instancePortForwardCmd,
 	instancesCmd,
 	instanceRebuildCmd,
 	instanceSFTPCmd,
@@ -90,6 +93,7 @@ var api10 = []APIEndpoint{
 	instanceUEFIVarsCmd,
 	eventsCmd,
 	imageAliasesCmd,
+	imageRetentionCmd,
 	imagesCmd,
 	imageSubCmd,
 	metadataConfigurationCmd,
@@ -115,6 +119,7 @@ var api10 = []APIEndpoint{
 	networkZoneRecordCmd,
 	networkZoneRecordsCmd,
 	operationCmd,
+	operationApprove,
 	operationsCmd,
 	operationWait,
 	operationWebsocket,
@@ -141,6 +146,11 @@ var api10 = []APIEndpoint{
 	storagePoolVolumeTypeStateCmd,
 	warningsCmd,
 	warningCmd,
+	auditCmd,
+	imageSigningKeyCmd,
+	webhooksCmd,
+	webhookCmd,
+	webhookStateCmd,
 	metricsCmd,
 	identitiesCmd,
 	currentIdentityCmd,
@@ -162,6 +172,13 @@ var api10 = []APIEndpoint{
 	oidcSessionCmd,
 	placementGroupsCmd,
 	placementGroupCmd,
+	scimServiceProviderConfigCmd,
+	scimResourceTypesCmd,
+	scimSchemasCmd,
+	scimUsersCmd,
+	scimUserCmd,
+	scimGroupsCmd,
+	scimGroupCmd,
 }
 
 // swagger:operation GET /1.0?public server server_get_untrusted
@@ -321,6 +338,11 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	imageSigningKeyFingerprint, err := imageSigningKeyFingerprintGet(r.Context(), s)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
 	architectures := []string{}
 
 	for _, architecture := range s.OS.Architectures {
@@ -343,6 +365,7 @@ func api10Get(d *Daemon, r *http.Request) response.Response {
 		BackupMetadataVersionRange: []uint32{api.BackupMetadataVersion1, backupConfig.MaxMetadataVersion},
 		Certificate:                certificate,
 		CertificateFingerprint:     certificateFingerprint,
+		ImageSigningKeyFingerprint: imageSigningKeyFingerprint,
 		Kernel:                     s.OS.Uname.Sysname,
 		KernelArchitecture:         s.OS.Uname.Machine,
 		KernelVersion:              s.OS.Uname.Release,
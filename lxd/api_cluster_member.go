@@ -714,6 +714,23 @@ func updateClusterMember(s *state.State, gateway *cluster.Gateway, r *http.Reque
 			}
 		}
 
+		// Volatile keys are managed by LXD and cannot be changed through the API.
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k := range req.Config {
+			if strings.HasPrefix(k, "volatile.") {
+				delete(req.Config, k)
+			}
+		}
+
+		for k, v := range nodeInfo.Config {
+			if strings.HasPrefix(k, "volatile.") {
+				req.Config[k] = v
+			}
+		}
+
 		// Update node config.
 		err = tx.UpdateNodeConfig(ctx, nodeInfo.ID, req.Config)
 		if err != nil {
@@ -867,6 +884,42 @@ func clusterValidateConfig(config map[string]string) error {
 		//  defaultdesc: `all`
 		//  shortdesc: Controls how instances are scheduled to run on this member
 		"scheduler.instance": validate.Optional(validate.IsOneOf("all", "group", "manual")),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.overcommit.cpu)
+		// Ratio applied to the number of CPUs of this member to determine how many CPUs can be
+		// reserved by instances through {config:option}`instance-placement:reservations.cpu`.
+		// Values above 1 allow overcommitting, values below 1 keep some CPUs unreserved.
+		// See {ref}`clustering-instance-reservations` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `1`
+		//  shortdesc: Overcommit ratio for CPU reservations on this member
+		"scheduler.overcommit.cpu": validate.Optional(clusterValidateOvercommitRatio),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=scheduler.overcommit.memory)
+		// Ratio applied to the total memory of this member to determine how much memory can be
+		// reserved by instances through {config:option}`instance-placement:reservations.memory`.
+		// Values above 1 allow overcommitting, values below 1 keep some memory unreserved.
+		// See {ref}`clustering-instance-reservations` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `1`
+		//  shortdesc: Overcommit ratio for memory reservations on this member
+		"scheduler.overcommit.memory": validate.Optional(clusterValidateOvercommitRatio),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=volatile.capacity.cpu)
+		// This key is set by LXD when the member starts and cannot be modified.
+		// ---
+		//  type: integer
+		//  shortdesc: Number of logical CPUs detected on this member
+		placement.CapacityCPUKey: validate.Optional(validate.IsUint64),
+
+		// lxdmeta:generate(entities=cluster; group=cluster; key=volatile.capacity.memory)
+		// This key is set by LXD when the member starts and cannot be modified.
+		// ---
+		//  type: integer
+		//  shortdesc: Total memory in bytes detected on this member
+		placement.CapacityMemoryKey: validate.Optional(validate.IsUint64),
 	}
 
 	for k, v := range config {
@@ -895,6 +948,20 @@ func clusterValidateConfig(config map[string]string) error {
 	return nil
 }
 
+// clusterValidateOvercommitRatio validates that value is a positive ratio.
+func clusterValidateOvercommitRatio(value string) error {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return fmt.Errorf("Invalid ratio %q: %w", value, err)
+	}
+
+	if ratio <= 0 {
+		return errors.New("Ratio must be greater than 0")
+	}
+
+	return nil
+}
+
 // swagger:operation POST /1.0/cluster/members/{name} cluster cluster_member_post
 //
 //	Rename the cluster member
@@ -966,6 +1033,7 @@ func clusterMemberPost(d *Daemon, r *http.Request) response.Response {
 //	Delete the cluster member
 //
 //	Removes the member from the cluster.
+//	If the removal requires approval (see `cluster.approval.member_remove`), an operation pending approval is returned.
 //
 //	---
 //	produces:
@@ -973,6 +1041,8 @@ func clusterMemberPost(d *Daemon, r *http.Request) response.Response {
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
+//	  "202":
+//	    $ref: "#/responses/Operation"
 //	  "400":
 //	    $ref: "#/responses/BadRequest"
 //	  "403":
@@ -998,6 +1068,44 @@ func clusterMemberDelete(d *Daemon, r *http.Request) response.Response {
 
 	localClusterAddress := s.LocalConfig.ClusterAddress()
 
+	// If the removal requires approval, create an operation that sends the request back to this member once approved.
+	// The approved request is forwarded, so it isn't subject to approval again.
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !requestor.IsForwarded() && !requestor.IsClusterNotification() {
+		approvalExpiry, err := clusterMemberRemoveApprovalExpiry(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if approvalExpiry > 0 {
+			run := func(ctx context.Context, _ *operations.Operation) error {
+				client, err := cluster.Connect(ctx, localClusterAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+				if err != nil {
+					return err
+				}
+
+				return client.DeleteClusterMember(name, force)
+			}
+
+			op, err := operations.ScheduleUserOperationFromRequest(s, r, operations.OperationArgs{
+				Type:           operationtype.ClusterMemberRemove,
+				Class:          operationtype.OperationClassTask,
+				Metadata:       map[string]any{api.MetadataEntityURL: api.NewURL().Path(version.APIVersion, "cluster", "members", name).String()},
+				RunHook:        run,
+				ApprovalExpiry: approvalExpiry,
+			})
+			if err != nil {
+				return response.SmartError(err)
+			}
+
+			return response.OperationResponse(op)
+		}
+	}
+
 	var localInfo, leaderNodeInfo db.NodeInfo
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		localInfo, err = tx.GetNodeByAddress(ctx, localClusterAddress)
@@ -1236,8 +1344,7 @@ func clusterMemberDelete(d *Daemon, r *http.Request) response.Response {
 		logger.Warn("Failed syncing images")
 	}
 
-	requestor := request.CreateRequestor(r.Context())
-	s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberRemoved.Event(name, requestor, nil))
+	s.Events.SendLifecycle(request.ProjectParam(r), lifecycle.ClusterMemberRemoved.Event(name, request.CreateRequestor(r.Context()), nil))
 
 	return response.EmptySyncResponse
 }
@@ -1979,6 +2086,22 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 			candidateMembers = newMembers
 		}
 
+		// Filter candidates that cannot accommodate the instance's reservations.
+		reservations, err := placement.InstanceReservations(inst.ExpandedConfig())
+		if err != nil {
+			return err
+		}
+
+		candidateMembers, err = placement.FilterReservations(ctx, tx, candidateMembers, reservations, inst.ID())
+		if err != nil {
+			// Signal not found so caller can skip instance during evacuation.
+			if api.StatusErrorCheck(err, http.StatusConflict) {
+				return api.StatusErrorf(http.StatusNotFound, "No eligible target cluster members with enough unreserved resources")
+			}
+
+			return err
+		}
+
 		// Find the least loaded cluster member which supports the instance's architecture.
 		targetMemberInfo, err = tx.GetNodeWithLeastInstances(ctx, candidateMembers)
 		if err != nil {
@@ -1994,6 +2117,46 @@ func evacuateClusterSelectTarget(ctx context.Context, s *state.State, inst insta
 	return targetMemberInfo, nil
 }
 
+// restoreClusterMemberStartInstances starts the given instances of a restored cluster member in boot order.
+// Instances whose boot dependencies failed to start or to become healthy aren't started.
+func restoreClusterMemberStartInstances(ctx context.Context, op *operations.Operation, instances []instance.Instance) error {
+	instances = instance.BootOrder(instances)
+	dependencies := instancesBootDependencies(instances)
+	failed := make(map[string]error)
+
+	for _, inst := range instances {
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+		instKey := instanceBootKey(inst.Project().Name, inst.Name())
+
+		err := instanceBootDependencyError(inst, failed)
+		if err != nil {
+			failed[instKey] = err
+			l.Warn("Not starting instance", logger.Ctx{"err": err})
+
+			continue
+		}
+
+		reportEvacuationProgress(op, fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name))
+
+		err = inst.Start(ctx, false, op)
+		if err != nil {
+			return fmt.Errorf("Failed starting instance %q: %w", inst.Name(), err)
+		}
+
+		if dependencies[instKey] {
+			reportEvacuationProgress(op, fmt.Sprintf("Waiting for %q in project %q to be healthy", inst.Name(), inst.Project().Name))
+
+			err = instanceBootWaitHealthy(ctx, inst)
+			if err != nil {
+				failed[instKey] = err
+				l.Warn("Instance didn't become healthy", logger.Ctx{"err": err})
+			}
+		}
+	}
+
+	return nil
+}
+
 func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Response {
 	s := d.State()
 
@@ -2073,6 +2236,9 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 		}
 
 		if !skipInstances {
+			// Instances to start once all instances are back, in boot order.
+			var startInstances []instance.Instance
+
 			// Restart the local instances.
 			for _, inst := range localInstances {
 				// Don't start instances which were stopped by the user.
@@ -2085,13 +2251,7 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 					continue
 				}
 
-				// Start the instance.
-				reportEvacuationProgress(op, fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name))
-
-				err = inst.Start(ctx, false, op)
-				if err != nil {
-					return fmt.Errorf("Failed starting instance %q: %w", inst.Name(), err)
-				}
+				startInstances = append(startInstances, inst)
 			}
 
 			// Migrate back the remote instances.
@@ -2210,12 +2370,12 @@ func restoreClusterMember(d *Daemon, r *http.Request, mode string) response.Resp
 					continue
 				}
 
-				reportEvacuationProgress(op, fmt.Sprintf("Starting %q in project %q", inst.Name(), inst.Project().Name))
+				startInstances = append(startInstances, inst)
+			}
 
-				err = inst.Start(ctx, false, op)
-				if err != nil {
-					return fmt.Errorf("Failed starting instance %q: %w", inst.Name(), err)
-				}
+			err = restoreClusterMemberStartInstances(ctx, op, startInstances)
+			if err != nil {
+				return err
 			}
 
 			logger.Info("Cluster member restored", logger.Ctx{"member": originName})
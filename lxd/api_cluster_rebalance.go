@@ -0,0 +1,555 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var clusterRebalanceCmd = APIEndpoint{
+	Path:        "cluster/rebalance",
+	MetricsType: entity.TypeClusterMember,
+
+	Get: APIEndpointAction{Handler: clusterRebalanceGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewResources)},
+}
+
+// swagger:operation GET /1.0/cluster/rebalance cluster cluster_rebalance_get
+//
+//	Get the cluster rebalance plan
+//
+//	Returns the load of each cluster member and the instance moves that the cluster rebalancer would perform
+//	if it ran now. Nothing is moved.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Cluster rebalance plan
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ClusterRebalancePlan"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func clusterRebalanceGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if !s.ServerClustered {
+		return response.BadRequest(errors.New("This server is not clustered"))
+	}
+
+	plan, err := clusterRebalancePlan(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, plan)
+}
+
+var internalClusterLoadCmd = APIEndpoint{
+	Path:        "cluster/load",
+	MetricsType: entity.TypeClusterMember,
+
+	Get: APIEndpointAction{Handler: internalClusterLoadGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewResources)},
+}
+
+// internalClusterLoadGet returns the load of the local cluster member as seen by the cluster rebalancer.
+func internalClusterLoadGet(d *Daemon, r *http.Request) response.Response {
+	load, err := clusterRebalanceLocalLoad(r.Context(), d.State())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, load)
+}
+
+// clusterRebalanceSampleInterval is the time between the two load samples taken when no recent sample is available.
+const clusterRebalanceSampleInterval = 5 * time.Second
+
+// clusterRebalanceSampleMaxAge is the age after which a previous load sample is too old to compute rates from.
+const clusterRebalanceSampleMaxAge = 30 * time.Minute
+
+// clusterRebalanceLastSample is the last load sample of the local cluster member.
+var clusterRebalanceLastSample placement.LoadSample
+var clusterRebalanceLastSampleMu sync.Mutex
+
+// clusterRebalanceLocalSample returns a load sample built from the metrics of the instances running on the local
+// cluster member.
+func clusterRebalanceLocalSample(s *state.State) (placement.LoadSample, error) {
+	instances, err := instance.LoadNodeAll(s, instancetype.Any)
+	if err != nil {
+		return placement.LoadSample{}, fmt.Errorf("Failed loading instances: %w", err)
+	}
+
+	hostInterfaces, _ := net.Interfaces()
+
+	instanceMetrics := make(map[string]*metrics.MetricSet, len(instances))
+	for _, inst := range instances {
+		if !inst.IsRunning() {
+			continue
+		}
+
+		set, err := inst.Metrics(hostInterfaces)
+		if err != nil {
+			logger.Debug("Failed getting instance metrics for rebalancing", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": err})
+			continue
+		}
+
+		instanceMetrics[project.Instance(inst.Project().Name, inst.Name())] = set
+	}
+
+	return placement.NewLoadSample(time.Now(), instanceMetrics), nil
+}
+
+// clusterRebalanceLocalLoad returns the load of the local cluster member computed from the CPU, memory and pressure
+// metrics of its instances. Rates are computed against the previous sample. If there is no recent previous sample,
+// a new one is taken first and the load is computed over clusterRebalanceSampleInterval.
+func clusterRebalanceLocalLoad(ctx context.Context, s *state.State) (*api.ClusterRebalanceMember, error) {
+	sysInfo, err := cluster.LocalSysInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRebalanceLastSampleMu.Lock()
+	defer clusterRebalanceLastSampleMu.Unlock()
+
+	previous := clusterRebalanceLastSample
+	if previous.Time.IsZero() || time.Since(previous.Time) > clusterRebalanceSampleMaxAge || time.Since(previous.Time) < time.Second {
+		previous, err = clusterRebalanceLocalSample(s)
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(clusterRebalanceSampleInterval):
+		}
+	}
+
+	current, err := clusterRebalanceLocalSample(s)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterRebalanceLastSample = current
+
+	load := placement.MemberLoad(s.ServerName, previous, current, int(sysInfo.LogicalCPUs), sysInfo.TotalRAM)
+
+	return &load, nil
+}
+
+// clusterRebalanceMemberLoads returns the load of the given cluster members. Members whose load cannot be
+// retrieved are skipped.
+func clusterRebalanceMemberLoads(ctx context.Context, s *state.State, members []db.NodeInfo) []api.ClusterRebalanceMember {
+	loads := make([]api.ClusterRebalanceMember, len(members))
+	errs := make([]error, len(members))
+
+	// Members are sampled in parallel as computing a load may take clusterRebalanceSampleInterval.
+	wg := sync.WaitGroup{}
+	for i, member := range members {
+		wg.Go(func() {
+			var load *api.ClusterRebalanceMember
+			if member.Name == s.ServerName {
+				load, errs[i] = clusterRebalanceLocalLoad(ctx, s)
+			} else {
+				load, errs[i] = clusterRebalanceRemoteLoad(ctx, s, member)
+			}
+
+			if errs[i] == nil {
+				loads[i] = *load
+			}
+		})
+	}
+
+	wg.Wait()
+
+	result := make([]api.ClusterRebalanceMember, 0, len(members))
+	for i, member := range members {
+		if errs[i] != nil {
+			logger.Warn("Failed getting cluster member load, skipping it for rebalancing", logger.Ctx{"member": member.Name, "err": errs[i]})
+			continue
+		}
+
+		result = append(result, loads[i])
+	}
+
+	return result
+}
+
+// clusterRebalanceRemoteLoad returns the load of a remote cluster member.
+func clusterRebalanceRemoteLoad(ctx context.Context, s *state.State, member db.NodeInfo) (*api.ClusterRebalanceMember, error) {
+	client, err := cluster.Connect(ctx, member.Address, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, _, err := client.RawQuery(http.MethodGet, "/internal/cluster/load", nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	load := &api.ClusterRebalanceMember{}
+	err = resp.MetadataAsStruct(load)
+	if err != nil {
+		return nil, err
+	}
+
+	return load, nil
+}
+
+// clusterRebalanceMode returns whether an instance with the given type and expanded config may be moved by the
+// cluster rebalancer and whether it would be live-migrated.
+// The rebalancer follows the instance's cluster.evacuate setting. In auto mode, only instances that can be
+// live-migrated are moved so that automatic rebalancing never causes downtime.
+func clusterRebalanceMode(instType instancetype.Type, expandedConfig map[string]string) (movable bool, live bool) {
+	switch expandedConfig["cluster.evacuate"] {
+	case api.ClusterEvacuateModeStop:
+		return false, false
+	case api.ClusterEvacuateModeMigrate:
+		return true, false
+	case api.ClusterEvacuateModeLiveMigrate:
+		return true, true
+	default:
+		live = instType == instancetype.VM && shared.IsTrue(expandedConfig["migration.stateful"])
+		return live, live
+	}
+}
+
+// clusterRebalancePlan computes the instance moves that the cluster rebalancer would perform now.
+func clusterRebalancePlan(ctx context.Context, s *state.State) (*api.ClusterRebalancePlan, error) {
+	_, threshold, batch := s.GlobalConfig.ClusterRebalance()
+	offlineThreshold := s.GlobalConfig.OfflineThreshold()
+
+	var allMembers []db.NodeInfo
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		allMembers, err = tx.GetNodes(ctx)
+		if err != nil {
+			return fmt.Errorf("Failed getting cluster members: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only online members that aren't evacuated take part in rebalancing.
+	members := make([]db.NodeInfo, 0, len(allMembers))
+	for _, member := range allMembers {
+		if member.State != db.ClusterMemberStateCreated || member.IsOffline(offlineThreshold) {
+			continue
+		}
+
+		members = append(members, member)
+	}
+
+	plan := &api.ClusterRebalancePlan{
+		Members: clusterRebalanceMemberLoads(ctx, s, members),
+		Moves:   []api.ClusterRebalanceMove{},
+	}
+
+	if len(plan.Members) < 2 {
+		return plan, nil
+	}
+
+	loaded := make(map[string]bool, len(plan.Members))
+	for _, load := range plan.Members {
+		loaded[load.Name] = true
+	}
+
+	globalConfig := s.GlobalConfig.Dump()
+	pgCache := placement.NewCache()
+	var instances []placement.RebalanceInstance
+
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		committed, err := placement.GetCommittedReservations(ctx, tx, 0)
+		if err != nil {
+			return err
+		}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, p api.Project) error {
+			if !loaded[inst.Node] || inst.Config["volatile.last_state.power"] != instance.PowerStateRunning {
+				return nil
+			}
+
+			rebalanceInst := placement.RebalanceInstance{
+				Project: inst.Project,
+				Name:    inst.Name,
+				Member:  inst.Node,
+			}
+
+			expandedConfig := instancetype.ExpandInstanceConfig(globalConfig, inst.Config, inst.Profiles)
+
+			movable, live := clusterRebalanceMode(inst.Type, expandedConfig)
+			if movable {
+				rebalanceInst.Live = live
+				rebalanceInst.Targets, err = clusterRebalanceTargets(ctx, tx, s, pgCache, allMembers, inst, p, expandedConfig, committed)
+				if err != nil {
+					return err
+				}
+			}
+
+			instances = append(instances, rebalanceInst)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Moves = placement.PlanRebalance(plan.Members, instances, float64(threshold), int(batch))
+
+	return plan, nil
+}
+
+// clusterRebalanceTargets returns the names of the cluster members an instance may be moved to. It takes into
+// account the instance's architecture, cluster group, placement group and reservations, as well as the cluster
+// group restrictions of its project.
+func clusterRebalanceTargets(ctx context.Context, tx *db.ClusterTx, s *state.State, pgCache *placement.Cache, allMembers []db.NodeInfo, inst db.InstanceArgs, p api.Project, expandedConfig map[string]string, committed map[string]placement.Reservations) ([]string, error) {
+	_, clusterGroupName := limits.TargetDetect(inst.Config["volatile.cluster.group"])
+	clusterGroupsAllowed := limits.GetRestrictedClusterGroups(&p)
+
+	candidates, err := tx.GetCandidateMembers(ctx, allMembers, []int{inst.Architecture}, clusterGroupName, clusterGroupsAllowed, s.GlobalConfig.OfflineThreshold())
+	if err != nil {
+		return nil, err
+	}
+
+	placementGroupName := expandedConfig["placement.group"]
+	if placementGroupName != "" {
+		apiPlacementGroup, err := pgCache.Get(ctx, tx, placementGroupName, inst.Project)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates, err = placement.Filter(ctx, tx, candidates, *apiPlacementGroup, false)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusConflict) {
+				return nil, nil
+			}
+
+			return nil, err
+		}
+	}
+
+	reservations, err := placement.InstanceReservations(expandedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Name == inst.Node {
+			continue
+		}
+
+		allocatable, err := placement.Allocatable(candidate.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		err = placement.Fits(allocatable, committed[candidate.Name], reservations)
+		if err != nil {
+			continue
+		}
+
+		targets = append(targets, candidate.Name)
+	}
+
+	return targets, nil
+}
+
+// clusterRebalanceMove moves an instance as decided by the cluster rebalancer.
+// Instances that aren't live-migrated are stopped before being moved and started again on the target.
+func clusterRebalanceMove(ctx context.Context, s *state.State, move api.ClusterRebalanceMove) error {
+	var sourceAddress string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		member, err := tx.GetNodeByName(ctx, move.Source)
+		if err != nil {
+			return err
+		}
+
+		sourceAddress = member.Address
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading source cluster member %q: %w", move.Source, err)
+	}
+
+	client, err := cluster.Connect(ctx, sourceAddress, s.Endpoints.NetworkCert(), s.ServerCert(), true)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to cluster member %q: %w", move.Source, err)
+	}
+
+	client = client.UseProject(move.Project)
+
+	changeState := func(action string) error {
+		op, err := client.UpdateInstanceState(move.Instance, api.InstanceStatePut{Action: action, Timeout: evacuateHostShutdownDefaultTimeout}, "")
+		if err != nil {
+			return err
+		}
+
+		return op.Wait()
+	}
+
+	if !move.Live {
+		err = changeState("stop")
+		if err != nil {
+			return fmt.Errorf("Failed stopping instance: %w", err)
+		}
+	}
+
+	op, err := client.UseTarget(move.Target).MigrateInstance(move.Instance, api.InstancePost{Migration: true, Live: move.Live})
+	if err != nil {
+		return fmt.Errorf("Failed moving instance: %w", err)
+	}
+
+	err = op.Wait()
+	if err != nil {
+		// Bring the instance back up on the source member.
+		if !move.Live {
+			_ = changeState("start")
+		}
+
+		return fmt.Errorf("Failed moving instance: %w", err)
+	}
+
+	if !move.Live {
+		err = changeState("start")
+		if err != nil {
+			return fmt.Errorf("Failed starting instance: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// clusterRebalance moves instances off the busiest cluster members.
+func clusterRebalance(ctx context.Context, s *state.State, op *operations.Operation, moves []api.ClusterRebalanceMove) error {
+	// Report all the planned moves first, as a failed move stops the rebalancing.
+	for _, move := range moves {
+		instURL := api.NewURL().Path(version.APIVersion, "instances", move.Instance).Project(move.Project)
+		s.Events.SendLifecycle(move.Project, lifecycle.ClusterMemberRebalancePlanned.Event(move.Source, op.EventLifecycleRequestor(), map[string]any{"instance": instURL.String(), "target": move.Target, "live": move.Live}))
+	}
+
+	for _, move := range moves {
+		l := logger.AddContext(logger.Ctx{"project": move.Project, "instance": move.Instance, "source": move.Source, "target": move.Target, "live": move.Live})
+		l.Info("Rebalancing instance")
+
+		err := clusterRebalanceMove(ctx, s, move)
+		if err != nil {
+			return fmt.Errorf("Failed rebalancing instance %q in project %q: %w", move.Instance, move.Project, err)
+		}
+
+		instURL := api.NewURL().Path(version.APIVersion, "instances", move.Instance).Project(move.Project)
+		s.Events.SendLifecycle(move.Project, lifecycle.ClusterMemberRebalanced.Event(move.Source, op.EventLifecycleRequestor(), map[string]any{"instance": instURL.String(), "target": move.Target, "live": move.Live}))
+	}
+
+	return nil
+}
+
+// clusterRebalanceTask returns the task that periodically rebalances instances across cluster members.
+// The task is only run by the leader and only if cluster.rebalance.interval is set.
+func clusterRebalanceTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil || !leaderInfo.Clustered || !leaderInfo.Leader {
+			return
+		}
+
+		plan, err := clusterRebalancePlan(ctx, s)
+		if err != nil {
+			logger.Error("Failed planning cluster rebalance", logger.Ctx{"err": err})
+			return
+		}
+
+		if len(plan.Moves) == 0 {
+			return
+		}
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return clusterRebalance(ctx, s, op, plan.Moves)
+		}
+
+		args := operations.OperationArgs{
+			Type:    operationtype.ClusterRebalance,
+			Class:   operationtype.OperationClassTask,
+			RunHook: opRun,
+			// Don't move instances around while a cluster member is being evacuated.
+			ConflictReference: clusterMemberEvacuateConflictReference,
+		}
+
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Warn("Failed creating cluster rebalance operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed rebalancing cluster", logger.Ctx{"err": err})
+		}
+	}
+
+	schedule := func() (time.Duration, error) {
+		interval, _, _ := stateFunc().GlobalConfig.ClusterRebalance()
+		if interval == 0 {
+			// Check again later in case rebalancing gets enabled.
+			return time.Minute, task.ErrSkip
+		}
+
+		return interval, nil
+	}
+
+	return f, schedule
+}
@@ -48,6 +48,7 @@ var apiInternal = []APIEndpoint{
 	internalClusterAssignCmd,
 	internalClusterHandoverCmd,
 	internalClusterHealCmd,
+	internalClusterLoadCmd,
 	internalClusterLinkRefreshVolatileAddressesCmd,
 	internalReplicatorRunSchedulerCmd,
 	internalClusterRaftNodeCmd,
@@ -59,6 +60,7 @@ var apiInternal = []APIEndpoint{
 	internalGarbageCollectorCmd,
 	internalImageOptimizeCmd,
 	internalImageRefreshCmd,
+	internalInstanceBootHealthCmd,
 	internalRAFTSnapshotCmd,
 	internalReadyCmd,
 	internalShutdownCmd,
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/ratelimit"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+const (
+	// apiLimitsConfigPrefix is the prefix of the configuration keys of the API limits.
+	apiLimitsConfigPrefix = "limits.api."
+
+	// apiLimitsOperationsRetryAfter is the delay suggested to the clients rejected because of a limit of running
+	// operations, as there is no way to tell when an operation completes.
+	apiLimitsOperationsRetryAfter = 5 * time.Second
+)
+
+// apiLimitsConfigKeys returns the validators of the configuration keys of the API limits.
+// These keys can be set on identities, authorization groups and projects.
+func apiLimitsConfigKeys() map[string]func(value string) error {
+	return map[string]func(value string) error{
+		// lxdmeta:generate(entities=identity,auth-group,project; group=limits; key=limits.api.requests.rate)
+		// Requests above this rate are rejected with a `429 Too Many Requests` error and a `Retry-After` header.
+		// For an identity or a group, the limit applies to each identity separately.
+		// For a project, it applies to all requests targeting the project.
+		// The limit is enforced on each cluster member separately.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of API requests per second
+		"limits.api.requests.rate": validate.Optional(validate.IsUint32),
+
+		// lxdmeta:generate(entities=identity,auth-group,project; group=limits; key=limits.api.requests.burst)
+		// This is the number of requests that can be sent at once before the maximum rate of requests applies.
+		// ---
+		//  type: integer
+		//  defaultdesc: same as `limits.api.requests.rate`
+		//  shortdesc: Maximum number of API requests in a burst
+		"limits.api.requests.burst": validate.Optional(validate.IsUint32),
+
+		// lxdmeta:generate(entities=identity,auth-group,project; group=limits; key=limits.api.operations)
+		// While this number of operations is pending, running or being cancelled, requests that could create new
+		// operations (all requests except `GET` requests) are rejected with a `429 Too Many Requests` error.
+		// The operations of all cluster members are counted, but the limit is only enforced on a best-effort basis:
+		// requests handled at the same time by several cluster members can exceed it.
+		// ---
+		//  type: integer
+		//  shortdesc: Maximum number of running operations
+		"limits.api.operations": validate.Optional(validate.IsUint32),
+	}
+}
+
+// validateAPILimitsConfig validates the configuration of an identity or an authorization group, which only supports
+// the API limits.
+func validateAPILimitsConfig(config map[string]string) error {
+	configKeys := apiLimitsConfigKeys()
+	for k, v := range config {
+		validator, ok := configKeys[k]
+		if !ok {
+			return fmt.Errorf("Invalid configuration key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return fmt.Errorf("Invalid value for configuration key %q: %w", k, err)
+		}
+	}
+
+	return nil
+}
+
+// apiLimitsConfig is the parsed configuration of the API limits of an identity, an authorization group or a project.
+// Zero values mean no limit.
+type apiLimitsConfig struct {
+	requests   ratelimit.Limit
+	operations int
+}
+
+// newAPILimitsConfig parses the API limits of the given (validated) configuration.
+func newAPILimitsConfig(config map[string]string) apiLimitsConfig {
+	rate, _ := strconv.Atoi(config["limits.api.requests.rate"])
+	burst, _ := strconv.Atoi(config["limits.api.requests.burst"])
+	operations, _ := strconv.Atoi(config["limits.api.operations"])
+
+	return apiLimitsConfig{
+		requests:   ratelimit.Limit{Rate: float64(rate), Burst: burst},
+		operations: operations,
+	}
+}
+
+// apiLimits holds the API limits configured on identities, authorization groups and projects, and enforces them.
+type apiLimits struct {
+	mu         sync.RWMutex
+	identities map[int64]apiLimitsConfig
+	groups     map[string]apiLimitsConfig
+	projects   map[string]apiLimitsConfig
+
+	requests *ratelimit.Limiter
+
+	// operationsMu protects reserved, so that concurrent requests handled by this member can't exceed the limits of
+	// running operations. It isn't held while counting the operations in the database.
+	operationsMu sync.Mutex
+
+	// reserved is the number of requests allowed to create an operation that are still being handled by this member,
+	// by identity and project key. Their operations may not be counted yet.
+	reserved map[string]int
+}
+
+// newAPILimits returns an [apiLimits] without any limit.
+func newAPILimits() *apiLimits {
+	return &apiLimits{
+		requests: ratelimit.NewLimiter(),
+		reserved: map[string]int{},
+	}
+}
+
+// load replaces the API limits with the configuration from the database.
+func (l *apiLimits) load(ctx context.Context, tx *db.ClusterTx) error {
+	clause := "WHERE %s.key LIKE '" + apiLimitsConfigPrefix + "%%'"
+
+	identityConfigs, err := dbCluster.IdentitiesConfigStore().Select(ctx, tx.Tx(), fmt.Sprintf(clause, "identities_config"))
+	if err != nil {
+		return fmt.Errorf("Failed loading identity configuration: %w", err)
+	}
+
+	groupConfigs, err := dbCluster.AuthGroupsConfigStore().Select(ctx, tx.Tx(), fmt.Sprintf(clause, "auth_groups_config"))
+	if err != nil {
+		return fmt.Errorf("Failed loading group configuration: %w", err)
+	}
+
+	groups, err := query.Select[dbCluster.AuthGroupsRow](ctx, tx.Tx(), "")
+	if err != nil {
+		return fmt.Errorf("Failed loading groups: %w", err)
+	}
+
+	projectConfigs, err := dbCluster.GetProjectsConfigByKeyPrefix(ctx, tx.Tx(), apiLimitsConfigPrefix)
+	if err != nil {
+		return err
+	}
+
+	identities := make(map[int64]apiLimitsConfig, len(identityConfigs))
+	for id, config := range identityConfigs {
+		identities[id] = newAPILimitsConfig(config)
+	}
+
+	groupLimits := make(map[string]apiLimitsConfig, len(groupConfigs))
+	for _, group := range groups {
+		config, ok := groupConfigs[group.ID]
+		if ok {
+			groupLimits[group.Name] = newAPILimitsConfig(config)
+		}
+	}
+
+	projects := make(map[string]apiLimitsConfig, len(projectConfigs))
+	for name, config := range projectConfigs {
+		projects[name] = newAPILimitsConfig(config)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.identities = identities
+	l.groups = groupLimits
+	l.projects = projects
+
+	return nil
+}
+
+// identityLimits returns the limits of the identity with the given ID and effective groups.
+// The limits set on the identity take precedence. Otherwise, the highest limits set on its groups apply.
+func (l *apiLimits) identityLimits(identityID int64, groups []string) apiLimitsConfig {
+	limits := l.identities[identityID]
+
+	var groupLimits apiLimitsConfig
+	for _, group := range groups {
+		config := l.groups[group]
+		if config.requests.Rate > groupLimits.requests.Rate {
+			groupLimits.requests = config.requests
+		}
+
+		groupLimits.operations = max(groupLimits.operations, config.operations)
+	}
+
+	if limits.requests.Rate == 0 {
+		limits.requests = groupLimits.requests
+	}
+
+	if limits.operations == 0 {
+		limits.operations = groupLimits.operations
+	}
+
+	return limits
+}
+
+// hasProject returns true if limits are set on the project with the given name.
+func (l *apiLimits) hasProject(projectName string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	_, ok := l.projects[projectName]
+	return ok
+}
+
+// apiLimitsOperationCounter returns the number of unfinished operations requested by the identity with the given ID,
+// and of those belonging to the given project.
+type apiLimitsOperationCounter func(identityID int64, projectName string) (identityOps int, projectOps int, err error)
+
+// check returns an error if the request exceeds the limits of the requestor or of the given project (if not empty).
+// It also returns the delay after which the client should retry. If the request is allowed, the returned function
+// must be called once the request has been handled.
+func (l *apiLimits) check(r *http.Request, requestor *request.Requestor, projectName string, countOperations apiLimitsOperationCounter) (func(), time.Duration, error) {
+	var identityID int64
+	if requestor.IdentityID != nil {
+		identityID = *requestor.IdentityID
+	}
+
+	l.mu.RLock()
+	identityLimits := l.identityLimits(identityID, requestor.CallerEffectiveAuthorizationGroupNames())
+	projectLimits := l.projects[projectName]
+	l.mu.RUnlock()
+
+	identityKey := "identity/" + strconv.FormatInt(identityID, 10)
+	projectKey := "project/" + projectName
+
+	release := func() {}
+	if (identityLimits.operations > 0 || projectLimits.operations > 0) && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		var retryAfter time.Duration
+		var err error
+		release, retryAfter, err = l.reserveOperation(identityID, identityKey, identityLimits.operations, projectName, projectKey, projectLimits.operations, countOperations)
+		if err != nil {
+			return nil, retryAfter, err
+		}
+	}
+
+	buckets := []ratelimit.Bucket{{Key: identityKey, Limit: identityLimits.requests}}
+	if projectName != "" {
+		buckets = append(buckets, ratelimit.Bucket{Key: projectKey, Limit: projectLimits.requests})
+	}
+
+	// The identity is checked first, and no token is taken from any bucket if the request is rejected.
+	rejected, retryAfter := l.requests.AllowAll(buckets...)
+	switch rejected {
+	case -1:
+		return release, 0, nil
+	case 0:
+		release()
+		metrics.CountThrottledRequest(entity.TypeIdentity, metrics.ThrottledRequestRate)
+		return nil, retryAfter, api.NewStatusError(http.StatusTooManyRequests, "Too many requests for the identity")
+	default:
+		release()
+		metrics.CountThrottledRequest(entity.TypeProject, metrics.ThrottledRequestRate)
+		return nil, retryAfter, api.StatusErrorf(http.StatusTooManyRequests, "Too many requests in project %q", projectName)
+	}
+}
+
+// reserveOperation checks the limits of unfinished operations of the identity and of the project, and reserves a slot
+// for the operation that the request may create. The returned function releases the slot once the request has been
+// handled, after which the operation that it created is counted instead.
+//
+// The check is best effort: the slots are only reserved on this member, so requests handled at the same time by other
+// cluster members, or whose operations are created between the count and the reservation, can exceed the limits.
+func (l *apiLimits) reserveOperation(identityID int64, identityKey string, identityLimit int, projectName string, projectKey string, projectLimit int, countOperations apiLimitsOperationCounter) (func(), time.Duration, error) {
+	identityOps, projectOps, err := countOperations(identityID, projectName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	l.operationsMu.Lock()
+	defer l.operationsMu.Unlock()
+
+	identityOps += l.reserved[identityKey]
+	if identityLimit > 0 && identityOps >= identityLimit {
+		metrics.CountThrottledRequest(entity.TypeIdentity, metrics.ThrottledOperations)
+		return nil, apiLimitsOperationsRetryAfter, api.StatusErrorf(http.StatusTooManyRequests, "Too many running operations for the identity (limit is %d)", identityLimit)
+	}
+
+	projectOps += l.reserved[projectKey]
+	if projectLimit > 0 && projectOps >= projectLimit {
+		metrics.CountThrottledRequest(entity.TypeProject, metrics.ThrottledOperations)
+		return nil, apiLimitsOperationsRetryAfter, api.StatusErrorf(http.StatusTooManyRequests, "Too many running operations in project %q (limit is %d)", projectName, projectLimit)
+	}
+
+	keys := []string{identityKey}
+	if projectName != "" {
+		keys = append(keys, projectKey)
+	}
+
+	for _, key := range keys {
+		l.reserved[key]++
+	}
+
+	return sync.OnceFunc(func() {
+		l.operationsMu.Lock()
+		defer l.operationsMu.Unlock()
+
+		for _, key := range keys {
+			l.reserved[key]--
+			if l.reserved[key] <= 0 {
+				delete(l.reserved, key)
+			}
+		}
+	}), 0, nil
+}
+
+// checkAPILimits enforces the API limits on a request to the given endpoint.
+// Only the requests of remote identities are limited. Requests forwarded by other cluster members were already checked
+// by the member that received them.
+// If the request is rejected, the returned error is sent to the client after setting the `Retry-After` header.
+// Otherwise, the returned function must be called once the request has been handled.
+func (d *Daemon) checkAPILimits(w http.ResponseWriter, r *http.Request, endpoint APIEndpoint) (func(), error) {
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	if !requestor.IsTrusted() || requestor.IsForwarded() || requestor.IdentityID == nil || slices.Contains([]string{request.ProtocolUnix, request.ProtocolCluster}, requestor.Protocol) {
+		return func() {}, nil
+	}
+
+	var projectName string
+	if endpoint.ProjectSpecific {
+		name, allProjects, err := request.ProjectParams(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if !allProjects {
+			projectName = name
+		}
+	}
+
+	// The operations of all cluster members are counted.
+	s := d.State()
+	countOperations := func(identityID int64, projectName string) (identityOps int, projectOps int, err error) {
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			identityOps, projectOps, err = dbCluster.CountUnfinishedOperations(ctx, tx.Tx(), identityID, projectName)
+			return err
+		})
+
+		return identityOps, projectOps, err
+	}
+
+	release, retryAfter, err := d.apiLimits.check(r, requestor, projectName, countOperations)
+	if err != nil {
+		if retryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		}
+
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// apiLimitsConfigChanged returns true if the API limits differ between the two configurations.
+func apiLimitsConfigChanged(oldConfig map[string]string, newConfig map[string]string) bool {
+	for k := range apiLimitsConfigKeys() {
+		if oldConfig[k] != newConfig[k] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// refreshAPILimits reloads the API limits on all cluster members after a change of the limits of a group or a project,
+// or after renaming or deleting a group or a project with limits.
+func refreshAPILimits(s *state.State) error {
+	notifier, err := cluster.NewNotifier(s, s.Endpoints.NetworkCert(), s.ServerCert(), cluster.NotifyAlive)
+	if err != nil {
+		return err
+	}
+
+	// The API limits are reloaded along with the identity cache.
+	err = notifier(func(member db.NodeInfo, client lxd.InstanceServer) error {
+		_, _, err := client.RawQuery(http.MethodPost, "/internal/identity-cache-refresh", nil, "")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.UpdateIdentityCache()
+
+	return nil
+}
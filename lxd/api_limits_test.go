@@ -0,0 +1,159 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/ratelimit"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// apiLimitsTestRequestor returns the requestor of the identity with the given ID.
+func apiLimitsTestRequestor(identityID int64) *request.Requestor {
+	return &request.Requestor{RequestorAuditor: request.RequestorAuditor{IdentityID: &identityID}}
+}
+
+// apiLimitsNoOperations is an operation counter for a cluster without any operation.
+func apiLimitsNoOperations(identityID int64, projectName string) (int, int, error) {
+	return 0, 0, nil
+}
+
+func TestAPILimitsIdentityLimits(t *testing.T) {
+	l := newAPILimits()
+	l.identities = map[int64]apiLimitsConfig{
+		1: {requests: ratelimit.Limit{Rate: 1}},
+	}
+
+	l.groups = map[string]apiLimitsConfig{
+		"g1": {requests: ratelimit.Limit{Rate: 10}, operations: 2},
+		"g2": {requests: ratelimit.Limit{Rate: 20}, operations: 1},
+	}
+
+	// The limits of the identity take precedence over those of its groups.
+	assert.Equal(t, apiLimitsConfig{requests: ratelimit.Limit{Rate: 1}, operations: 2}, l.identityLimits(1, []string{"g1", "g2"}))
+
+	// Otherwise, the highest limits of its groups apply.
+	assert.Equal(t, apiLimitsConfig{requests: ratelimit.Limit{Rate: 20}, operations: 2}, l.identityLimits(2, []string{"g1", "g2"}))
+	assert.Equal(t, apiLimitsConfig{}, l.identityLimits(2, nil))
+}
+
+func TestAPILimitsCheck_Operations(t *testing.T) {
+	l := newAPILimits()
+	l.identities = map[int64]apiLimitsConfig{1: {operations: 2}}
+	l.projects = map[string]apiLimitsConfig{"p1": {operations: 3}}
+
+	post := httptest.NewRequest(http.MethodPost, "/1.0/instances", nil)
+
+	// Operations in the database count against the limits.
+	countOperations := func(identityID int64, projectName string) (int, int, error) {
+		return 1, 2, nil
+	}
+
+	release, _, err := l.check(post, apiLimitsTestRequestor(1), "p1", countOperations)
+	require.NoError(t, err)
+
+	// A request that was allowed holds its slot until it has been handled.
+	_, retryAfter, err := l.check(post, apiLimitsTestRequestor(1), "p1", countOperations)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusTooManyRequests))
+	assert.Equal(t, apiLimitsOperationsRetryAfter, retryAfter)
+
+	_, _, err = l.check(post, apiLimitsTestRequestor(2), "p1", countOperations)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusTooManyRequests))
+
+	// Requests that can't create operations aren't limited.
+	get := httptest.NewRequest(http.MethodGet, "/1.0/instances", nil)
+	_, _, err = l.check(get, apiLimitsTestRequestor(1), "p1", countOperations)
+	assert.NoError(t, err)
+
+	// Releasing the slot twice has no effect.
+	release()
+	release()
+	assert.Empty(t, l.reserved)
+
+	_, _, err = l.check(post, apiLimitsTestRequestor(2), "p1", countOperations)
+	assert.NoError(t, err)
+}
+
+func TestAPILimitsCheck_ConcurrentOperations(t *testing.T) {
+	l := newAPILimits()
+	l.identities = map[int64]apiLimitsConfig{1: {operations: 5}}
+
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodPost, "/1.0/instances", nil)
+			_, _, err := l.check(req, apiLimitsTestRequestor(1), "", apiLimitsNoOperations)
+			if err == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int64(5), allowed.Load())
+}
+
+func TestAPILimitsCheck_SlowOperationCount(t *testing.T) {
+	l := newAPILimits()
+	l.identities = map[int64]apiLimitsConfig{1: {operations: 1}, 2: {operations: 1}}
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	slowCount := func(identityID int64, projectName string) (int, int, error) {
+		close(started)
+		<-unblock
+		return 0, 0, nil
+	}
+
+	done := make(chan error)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, "/1.0/instances", nil)
+		_, _, err := l.check(req, apiLimitsTestRequestor(1), "", slowCount)
+		done <- err
+	}()
+
+	<-started
+
+	// A slow count of the operations of an identity doesn't hold up the requests of other identities.
+	req := httptest.NewRequest(http.MethodPost, "/1.0/instances", nil)
+	_, _, err := l.check(req, apiLimitsTestRequestor(2), "", apiLimitsNoOperations)
+	assert.NoError(t, err)
+
+	close(unblock)
+	assert.NoError(t, <-done)
+}
+
+func TestAPILimitsCheck_Requests(t *testing.T) {
+	l := newAPILimits()
+	l.identities = map[int64]apiLimitsConfig{1: {requests: ratelimit.Limit{Rate: 0.001, Burst: 1}}}
+	l.projects = map[string]apiLimitsConfig{"p1": {requests: ratelimit.Limit{Rate: 0.001, Burst: 2}}}
+
+	req := httptest.NewRequest(http.MethodGet, "/1.0/instances", nil)
+
+	_, _, err := l.check(req, apiLimitsTestRequestor(1), "p1", apiLimitsNoOperations)
+	require.NoError(t, err)
+
+	// The requests rejected because of the limits of the identity don't consume the tokens of the project.
+	for range 3 {
+		_, retryAfter, err := l.check(req, apiLimitsTestRequestor(1), "p1", apiLimitsNoOperations)
+		assert.ErrorContains(t, err, "Too many requests for the identity")
+		assert.Positive(t, retryAfter)
+	}
+
+	_, _, err = l.check(req, apiLimitsTestRequestor(2), "p1", apiLimitsNoOperations)
+	assert.NoError(t, err)
+
+	_, _, err = l.check(req, apiLimitsTestRequestor(2), "p1", apiLimitsNoOperations)
+	assert.ErrorContains(t, err, `Too many requests in project "p1"`)
+}
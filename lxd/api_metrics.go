@@ -451,6 +451,18 @@ func internalMetrics(ctx context.Context, s *state.State, tx *db.ClusterTx) *met
 		}
 	}
 
+	for _, entityType := range metrics.ThrottledMetricsEntityTypes() {
+		for reason, reasonName := range metrics.GetThrottleReasonNames() {
+			out.AddSamples(
+				metrics.APIThrottledRequests,
+				metrics.Sample{
+					Labels: map[string]string{"entity_type": entityType.String(), "reason": reasonName},
+					Value:  float64(metrics.GetThrottledRequests(entityType, reason)),
+				},
+			)
+		}
+	}
+
 	// Daemon uptime
 	out.AddSamples(metrics.UptimeSeconds, metrics.Sample{Value: time.Since(s.StartTime).Seconds()})
 
@@ -330,6 +330,11 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProject, api.WebhookAdmissionOperationCreate, project.Name, entity.ProjectURL(project.Name), &project)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Quick checks.
 	err = projecthelpers.ValidName(project.Name)
 	if err != nil {
@@ -408,6 +413,13 @@ func projectsPost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed creating project %q: %w", project.Name, err))
 	}
 
+	if apiLimitsConfigChanged(nil, project.Config) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	lc := lifecycle.ProjectCreated.Event(project.Name, requestor.EventLifecycleRequestor(), nil)
 	s.Events.SendLifecycle(project.Name, lc)
 
@@ -620,6 +632,11 @@ func projectPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProject, api.WebhookAdmissionOperationUpdate, project.Name, entity.ProjectURL(project.Name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r.Context())
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
@@ -733,6 +750,11 @@ func projectPatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProject, api.WebhookAdmissionOperationUpdate, project.Name, entity.ProjectURL(project.Name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	requestor := request.CreateRequestor(r.Context())
 	s.Events.SendLifecycle(project.Name, lifecycle.ProjectUpdated.Event(project.Name, requestor, nil))
 
@@ -857,6 +879,13 @@ func projectChange(ctx context.Context, s *state.State, project *api.Project, re
 		return response.SmartError(err)
 	}
 
+	if apiLimitsConfigChanged(project.Config, req.Config) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	return response.EmptySyncResponse
 }
 
@@ -1031,6 +1060,14 @@ func projectPost(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		// The API limits of projects are looked up by name.
+		if d.apiLimits.hasProject(name) {
+			err = refreshAPILimits(s)
+			if err != nil {
+				return err
+			}
+		}
+
 		requestor := request.CreateRequestor(r.Context())
 		s.Events.SendLifecycle(req.Name, lifecycle.ProjectRenamed.Event(req.Name, requestor, logger.Ctx{"old_name": name}))
 
@@ -1393,15 +1430,29 @@ func projectDelete(d *Daemon, r *http.Request) response.Response {
 			return fmt.Errorf("Failed deleting project: %w", err)
 		}
 
+		if d.apiLimits.hasProject(name) {
+			err = refreshAPILimits(s)
+			if err != nil {
+				return err
+			}
+		}
+
 		s.Events.SendLifecycle(name, lifecycle.ProjectDeleted.Event(name, requestor.EventLifecycleRequestor(), nil))
 		return nil
 	}
 
+	// The operation isn't associated with the project being deleted, so it must be approved on the server.
+	approvalExpiry, err := projectApprovalExpiry(r.Context(), s, project.Name, operationApprovalProjectDelete)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, operations.OperationArgs{
-		Type:      operationtype.ProjectDelete,
-		Class:     operationtype.OperationClassTask,
-		EntityURL: entity.ProjectURL(project.Name),
-		RunHook:   run,
+		Type:           operationtype.ProjectDelete,
+		Class:          operationtype.OperationClassTask,
+		EntityURL:      entity.ProjectURL(project.Name),
+		RunHook:        run,
+		ApprovalExpiry: approvalExpiry,
 	})
 	if err != nil {
 		return response.SmartError(err)
@@ -1795,8 +1846,40 @@ func isEitherAllowOrBlockOrManaged(value string) error {
 // Any value checks that rely on the state of the database should be performed on AllowProjectUpdate,
 // so that we are performing these checks and updating the project in a single transaction.
 func projectValidateConfig(ctx context.Context, s *state.State, config map[string]string, defaultNetwork string, projectName string) error {
+	isClusterLink := func(value string) error {
+		err := s.DB.Cluster.Transaction(ctx, func(dbCtx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.GetClusterLink(dbCtx, tx.Tx(), value)
+			if err != nil {
+				return api.StatusErrorf(http.StatusNotFound, "Cluster link %q not found", value)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.Authorizer.CheckPermission(ctx, entity.ClusterLinkURL(value), auth.EntitlementCanView)
+	}
+
 	// Validate the project configuration.
 	projectConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=project; group=specific; key=approval.actions)
+		// Specify a comma-separated list of actions that must be approved by a second identity before they run.
+		// Possible values are `instance-delete`, `instance-protection` (changes to `security.protection.*` instance
+		// options), `image-delete`, and `project-delete`.
+		// See {ref}`operation-approval`.
+		// ---
+		//  type: string
+		//  shortdesc: Actions that require approval
+		"approval.actions": validate.Optional(validate.IsListOf(validate.IsOneOf(operationApprovalActions...))),
+		// lxdmeta:generate(entities=project; group=specific; key=approval.expiry)
+		// Specify the time after which an action that hasn't been approved fails.
+		// ---
+		//  type: string
+		//  defaultdesc: `1d`
+		//  shortdesc: Time after which an unapproved action expires
+		"approval.expiry": operationApprovalValidateExpiry,
 		// lxdmeta:generate(entities=project; group=specific; key=backups.compression_algorithm)
 		// Specify which compression algorithm to use for backups in this project.
 		// Possible values are `bzip2`, `gzip`, `lzma`, `xz`, or `none`.
@@ -1883,6 +1966,70 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		//  type: integer
 		//  shortdesc: When an unused cached remote image is flushed in the project
 		"images.remote_cache_expiry": validate.Optional(validate.IsInt64),
+		// lxdmeta:generate(entities=project; group=specific; key=images.retention.group_by)
+		// Possible values are `properties` (images with the same `os`, `release`, `variant` and architecture)
+		// and `alias` (images that have or had the same alias).
+		// See {ref}`images-retention` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `properties`
+		//  shortdesc: How image versions are grouped for retention
+		"images.retention.group_by": validate.Optional(validate.IsOneOf("properties", "alias")),
+		// lxdmeta:generate(entities=project; group=specific; key=images.retention.unaliased_expiry)
+		// Specify the number of days after which locally added images without any alias are deleted.
+		// Images used by instances are never deleted.
+		// ---
+		//  type: integer
+		//  shortdesc: When locally added images without an alias are deleted
+		"images.retention.unaliased_expiry": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=specific; key=images.retention.versions)
+		// Specify the number of most recent versions of an image to keep.
+		// Older versions are deleted unless they are used by instances.
+		// ---
+		//  type: integer
+		//  shortdesc: Number of image versions to keep in the project
+		"images.retention.versions": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=specific; key=images.require_signature)
+		// When enabled, images must be signed by one of the keys listed in {config:option}`project-specific:images.trusted_keys`
+		// to be imported, copied from a remote server, auto-updated or used to create instances.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether images must be signed by a trusted key
+		"images.require_signature": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=project; group=specific; key=images.simplestreams.deltas)
+		// Delta files are generated with `xdelta3` from up to this number of previous versions of each image.
+		// They are only generated for images whose root file system is a SquashFS or a virtual machine disk image.
+		// ---
+		//  type: integer
+		//  defaultdesc: `0`
+		//  shortdesc: Number of previous versions to generate delta files from
+		"images.simplestreams.deltas": validate.Optional(validate.IsUint32),
+		// lxdmeta:generate(entities=project; group=specific; key=images.simplestreams.enabled)
+		// When enabled, the images of the project are published as a simplestreams tree at `/simplestreams/<project>/`,
+		// which doesn't require authentication.
+		// See {ref}`images-simplestreams`.
+		// ---
+		//  type: bool
+		//  defaultdesc: `false`
+		//  shortdesc: Whether to serve the images of the project over simplestreams
+		"images.simplestreams.enabled": validate.Optional(validate.IsBool),
+		// lxdmeta:generate(entities=project; group=specific; key=images.simplestreams.property)
+		// Specify a property in the `key=value` form.
+		// When set, the images with this property are published, whether they are public or not.
+		// Otherwise, the public images of the project are published.
+		// ---
+		//  type: string
+		//  shortdesc: Property of the images to serve over simplestreams
+		"images.simplestreams.property": validate.Optional(validateImageSimplestreamsProperty),
+		// lxdmeta:generate(entities=project; group=specific; key=images.trusted_keys)
+		// Specify a comma-separated list of key fingerprints.
+		// The fingerprint of a key is the SHA-256 hash of its public key in DER format.
+		// The fingerprint of the key used by the server to sign images is available in the `image_signing_key_fingerprint` field of the server environment.
+		// ---
+		//  type: string
+		//  shortdesc: Fingerprints of the keys trusted to sign images
+		"images.trusted_keys": validate.Optional(validate.IsListOf(validateImageSigningKeyFingerprint)),
 		// lxdmeta:generate(entities=project; group=limits; key=limits.instances)
 		//
 		// ---
@@ -2184,23 +2331,48 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		// ---
 		//  type: string
 		//  shortdesc: Cluster link allowed to replicate to this standby project.
-		"replica.cluster": validate.Optional(func(value string) error {
-			err := s.DB.Cluster.Transaction(ctx, func(dbCtx context.Context, tx *db.ClusterTx) error {
-				_, err := dbCluster.GetClusterLink(dbCtx, tx.Tx(), value)
-				if err != nil {
-					return api.StatusErrorf(http.StatusNotFound, "Cluster link %q not found", value)
-				}
+		"replica.cluster": validate.Optional(isClusterLink),
 
-				return nil
-			})
-			if err != nil {
-				return err
-			}
+		// lxdmeta:generate(entities=project; group=replica; key=replica.failover.mode)
+		// This setting is used on standby projects and is usually set by the replicator of the leader project
+		// from its {config:option}`replicator-conf:failover.mode` configuration.
+		// When set to `automatic`, the standby project is promoted to leader once the leader identified by
+		// {config:option}`project-replica:replica.cluster` fails the configured number of health checks.
+		// ---
+		//  type: string
+		//  defaultdesc: `manual`
+		//  shortdesc: Whether the standby project fails over automatically
+		"replica.failover.mode": validate.Optional(validate.IsOneOf("manual", "automatic")),
+
+		// lxdmeta:generate(entities=project; group=replica; key=replica.failover.witness)
+		// This setting is used on standby projects and is usually set by the replicator of the leader project
+		// from its {config:option}`replicator-conf:failover.witness` configuration.
+		// It is required when {config:option}`project-replica:replica.failover.mode` is `automatic`.
+		// ---
+		//  type: string
+		//  shortdesc: Cluster link to the witness cluster of the automatic failover
+		"replica.failover.witness": validate.Optional(isClusterLink),
 
-			return s.Authorizer.CheckPermission(ctx, entity.ClusterLinkURL(value), auth.EntitlementCanView)
-		}),
+		// lxdmeta:generate(entities=project; group=replica; key=replica.failover.health_check.interval)
+		// This setting is used on standby projects and is usually set by the replicator of the leader project.
+		// ---
+		//  type: integer
+		//  defaultdesc: `30`
+		//  shortdesc: Seconds between two health checks of the leader
+		"replica.failover.health_check.interval": validate.Optional(validate.IsInRange(10, 3600)),
+
+		// lxdmeta:generate(entities=project; group=replica; key=replica.failover.health_check.threshold)
+		// This setting is used on standby projects and is usually set by the replicator of the leader project.
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  shortdesc: Number of consecutive failed health checks before failing over
+		"replica.failover.health_check.threshold": validate.Optional(validate.IsInRange(1, 100)),
 	}
 
+	// Add the API limits keys.
+	maps.Copy(projectConfigKeys, apiLimitsConfigKeys())
+
 	// Add the storage pool keys.
 	err := s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
@@ -2292,6 +2464,11 @@ func projectValidateConfig(ctx context.Context, s *state.State, config map[strin
 		}
 	}
 
+	// Without a witness, a network partition between the clusters would leave both projects in leader mode.
+	if config["replica.failover.mode"] == "automatic" && config["replica.failover.witness"] == "" {
+		return fmt.Errorf("Project configuration key %q is required with automatic failover", "replica.failover.witness")
+	}
+
 	// Ensure that restricted projects have their own profiles. Otherwise restrictions in this project could
 	// be bypassed by settings from the default project's profiles that are not checked against this project's
 	// restrictions when they are configured.
@@ -335,6 +335,26 @@ func replicatorGet(d *Daemon, r *http.Request) response.Response {
 // replicatorValidateConfig validates replicator configuration keys and values.
 // It also checks that the caller has permission to view any referenced cluster link.
 func replicatorValidateConfig(ctx context.Context, s *state.State, config map[string]string) error {
+	isClusterLink := func(value string) error {
+		err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			_, err := dbCluster.GetClusterLink(ctx, tx.Tx(), value)
+			if err != nil {
+				if api.StatusErrorCheck(err, http.StatusNotFound) {
+					return api.StatusErrorf(http.StatusNotFound, "Cluster link %q not found", value)
+				}
+
+				return err
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.Authorizer.CheckPermission(ctx, entity.ClusterLinkURL(value), auth.EntitlementCanView)
+	}
+
 	replicatorConfigKeys := map[string]func(value string) error{
 		// lxdmeta:generate(entities=replicator; group=conf; key=cluster)
 		// Required when creating a replicator.
@@ -342,25 +362,7 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		//  type: string
 		//  shortdesc: Target cluster link name.
 		//  scope: global
-		"cluster": validate.Required(func(value string) error {
-			err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
-				_, err := dbCluster.GetClusterLink(ctx, tx.Tx(), value)
-				if err != nil {
-					if api.StatusErrorCheck(err, http.StatusNotFound) {
-						return api.StatusErrorf(http.StatusNotFound, "Cluster link %q not found", value)
-					}
-
-					return err
-				}
-
-				return nil
-			})
-			if err != nil {
-				return err
-			}
-
-			return s.Authorizer.CheckPermission(ctx, entity.ClusterLinkURL(value), auth.EntitlementCanView)
-		}),
+		"cluster": validate.Required(isClusterLink),
 
 		// lxdmeta:generate(entities=replicator; group=conf; key=schedule)
 		// Specify a cron expression for the replication schedule. For example, `@daily` or `0 6 * * *`.
@@ -369,6 +371,47 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		//  shortdesc: Cron expression for the replication schedule.
 		//  scope: global
 		"schedule": validate.Optional(validate.IsCron([]string{"@hourly", "@daily", "@midnight", "@weekly", "@monthly", "@annually", "@yearly"})),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=failover.mode)
+		// When set to `automatic`, the standby cluster monitors the leader cluster through its cluster link and
+		// promotes the standby project if the leader fails too many health checks.
+		// The policy is applied to the standby project on each replicator run.
+		// See {ref}`exp-replicators-automatic-failover` for more information.
+		// ---
+		//  type: string
+		//  defaultdesc: `manual`
+		//  shortdesc: Failover policy of the standby project (`manual` or `automatic`).
+		//  scope: global
+		"failover.mode": validate.Optional(validate.IsOneOf("manual", "automatic")),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=failover.witness)
+		// Required when {config:option}`replicator-conf:failover.mode` is `automatic`.
+		// The leader cluster keeps a lease on the project of the same name in the witness cluster, and the standby
+		// cluster only promotes its project once that lease has expired.
+		// A cluster link with the same name must exist on the standby cluster.
+		// ---
+		//  type: string
+		//  shortdesc: Cluster link to the witness cluster.
+		//  scope: global
+		"failover.witness": validate.Optional(isClusterLink),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=failover.health_check.interval)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `30`
+		//  shortdesc: Seconds between two health checks of the leader cluster.
+		//  scope: global
+		"failover.health_check.interval": validate.Optional(validate.IsInRange(10, 3600)),
+
+		// lxdmeta:generate(entities=replicator; group=conf; key=failover.health_check.threshold)
+		//
+		// ---
+		//  type: integer
+		//  defaultdesc: `3`
+		//  shortdesc: Number of consecutive failed health checks before the standby project is promoted.
+		//  scope: global
+		"failover.health_check.threshold": validate.Optional(validate.IsInRange(1, 100)),
 	}
 
 	for k, v := range config {
@@ -398,6 +441,17 @@ func replicatorValidateConfig(ctx context.Context, s *state.State, config map[st
 		return fmt.Errorf("Replicator configuration key %q is required", "cluster")
 	}
 
+	// Without a witness, a network partition between the clusters would leave both projects in leader mode.
+	if config["failover.mode"] == "automatic" {
+		if config["failover.witness"] == "" {
+			return fmt.Errorf("Replicator configuration key %q is required with automatic failover", "failover.witness")
+		}
+
+		if config["failover.witness"] == config["cluster"] {
+			return fmt.Errorf("Replicator configuration key %q must refer to a different cluster link than %q", "failover.witness", "cluster")
+		}
+	}
+
 	return nil
 }
 
@@ -958,16 +1012,26 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 	}
 
 	name := r.PathValue("name")
-	var status string
+	replicatorState := api.ReplicatorState{}
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		dbReplicator, err := dbCluster.GetReplicator(ctx, tx.Tx(), name, projectName)
 		if err != nil {
 			return err
 		}
 
-		status = api.ReplicatorStatusPending
+		replicatorState.Status = api.ReplicatorStatusPending
 		if dbReplicator.Row.LastRunStatus != "" {
-			status = dbReplicator.Row.LastRunStatus
+			replicatorState.Status = dbReplicator.Row.LastRunStatus
+		}
+
+		entities, err := dbCluster.GetReplicatorEntities(ctx, tx.Tx(), dbReplicator.Row.ID)
+		if err != nil {
+			return err
+		}
+
+		replicatorState.Entities = make([]api.ReplicatorStateEntity, 0, len(entities))
+		for _, replicatorEntity := range entities {
+			replicatorState.Entities = append(replicatorState.Entities, replicatorEntity.ToAPI())
 		}
 
 		return nil
@@ -976,7 +1040,7 @@ func replicatorStateGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed loading replicator state for %q: %w", name, err))
 	}
 
-	return response.SyncResponse(true, api.ReplicatorState{Status: status})
+	return response.SyncResponse(true, replicatorState)
 }
 
 // runScheduledReplicatorsTask returns a background task that checks replicator schedules every minute
@@ -1011,6 +1075,8 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 	var targetCert *x509.Certificate
 	var sourceProject *api.Project
 	var allInsts []instance.Instance
+	var deps *replicatorDependencies
+	var replicatorConfig map[string]string
 	var nodeAddressByName map[string]string
 	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
@@ -1019,6 +1085,13 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			return err
 		}
 
+		allConfigs, err := dbCluster.ReplicatorsConfigStore().GetByEntityIDs(ctx, tx.Tx(), replicatorID)
+		if err != nil {
+			return fmt.Errorf("Failed loading replicator config: %w", err)
+		}
+
+		replicatorConfig = allConfigs[replicatorID]
+
 		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
 		if err != nil {
 			return err
@@ -1045,6 +1118,14 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			return fmt.Errorf("Failed listing project instances: %w", err)
 		}
 
+		// Load the entities that the instances depend on so they can be reconciled before the instances.
+		if !restore {
+			deps, err = replicatorLoadDependencies(ctx, tx, sourceProject, allInsts)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance dependencies: %w", err)
+			}
+		}
+
 		// Pre-load node addresses for forwarding to other cluster members.
 		nodes, err := tx.GetNodes(ctx)
 		if err != nil {
@@ -1072,7 +1153,7 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 
 	targetClient = targetClient.UseProject(projectName)
 
-	targetProject, _, err := targetClient.GetProject(projectName)
+	targetProject, targetProjectETag, err := targetClient.GetProject(projectName)
 	if err != nil {
 		return operations.OperationArgs{}, fmt.Errorf("Failed getting target project: %w", err)
 	}
@@ -1082,6 +1163,14 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		return operations.OperationArgs{}, api.StatusErrorf(http.StatusBadRequest, "%s", err)
 	}
 
+	// Apply the failover policy of the replicator to the standby project.
+	if !restore {
+		err = replicatorApplyFailoverPolicy(targetClient, targetProject, targetProjectETag, replicatorConfig)
+		if err != nil {
+			logger.Warn("Failed applying failover policy to standby project", logger.Ctx{"project": projectName, "replicator": name, "err": err})
+		}
+	}
+
 	targetCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: targetCert.Raw}))
 
 	// In restore mode, all project instances across all cluster members must be stopped
@@ -1110,25 +1199,57 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 		for _, ri := range remoteInsts {
 			iterNames = append(iterNames, ri.Name)
 		}
+
+		// Load the entities that the instances depend on from the current leader cluster so they can be
+		// restored before the instances.
+		deps, err = replicatorLoadRemoteDependencies(targetClient, sourceProject, remoteInsts, allInsts)
+		if err != nil {
+			return operations.OperationArgs{}, fmt.Errorf("Failed loading instance dependencies from current leader cluster: %w", err)
+		}
 	}
 
 	replicatorURL := entity.ReplicatorURL(projectName, name)
 	projectURL := entity.ProjectURL(projectName)
 
-	// Forward replication: iterate over all loaded instances directly.
+	// Forward replication: reconcile the instance dependencies, then iterate over all loaded instances directly.
 	if !restore {
-		childArgs := make([]*operations.OperationArgs, 0, len(allInsts))
+		connect := func(ctx context.Context) (lxd.InstanceServer, error) {
+			dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+			if err != nil {
+				return nil, fmt.Errorf("Failed connecting to target cluster: %w", err)
+			}
+
+			return dstClient.UseProject(projectName), nil
+		}
+
+		// The instances are only replicated once all their dependencies have been reconciled.
+		depsResult := newReplicatorDependenciesResult()
+
+		childArgs := make([]*operations.OperationArgs, 0, len(allInsts)+1)
+		childArgs = append(childArgs, replicatorDependenciesOperation(projectName, depsResult, func(ctx context.Context, op *operations.Operation) ([]dbCluster.ReplicatorEntity, error) {
+			dstClient, err := connect(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			return replicatorReconcileDependencies(projectName, deps, dstClient, func(vol replicatorVolume) error {
+				return replicatorSyncVolume(ctx, s, op, projectName, vol, dstClient, nodeAddressByName[vol.volume.Location], targetCertPEM)
+			}), nil
+		}))
 
 		for _, inst := range allInsts {
 			memberAddress := nodeAddressByName[inst.Location()]
 
 			copyFunc := func(ctx context.Context, op *operations.Operation) error {
-				dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+				err := depsResult.wait(ctx)
 				if err != nil {
-					return fmt.Errorf("Failed connecting to target cluster: %w", err)
+					return err
 				}
 
-				dstClient = dstClient.UseProject(projectName)
+				dstClient, err := connect(ctx)
+				if err != nil {
+					return err
+				}
 
 				return replicateInstance(ctx, s, op, inst, memberAddress, dstClient, targetCertPEM)
 			}
@@ -1164,14 +1285,40 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 				// Use a fresh context so the status write always completes, even if the operation context was cancelled.
 				// Only the status is updated here; last_run_date was already set when the operation started.
 				return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+					err := dbCluster.ReplaceReplicatorEntities(ctx, tx.Tx(), replicatorID, depsResult.entities)
+					if err != nil {
+						return err
+					}
+
 					return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
 				})
 			},
 		}, nil
 	}
 
-	// Restore mode: iterate over the current leader cluster's instance list.
-	childArgs := make([]*operations.OperationArgs, 0, len(iterNames))
+	// Restore mode: restore the instance dependencies, then iterate over the current leader cluster's instance list.
+	depsResult := newReplicatorDependenciesResult()
+
+	childArgs := make([]*operations.OperationArgs, 0, len(iterNames)+1)
+	childArgs = append(childArgs, replicatorDependenciesOperation(projectName, depsResult, func(ctx context.Context, _ *operations.Operation) ([]dbCluster.ReplicatorEntity, error) {
+		srcClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to target cluster: %w", err)
+		}
+
+		srcClient = srcClient.UseProject(projectName)
+
+		localClient, err := lxd.ConnectLXDUnix(s.OS.GetUnixSocket(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("Failed connecting to local server: %w", err)
+		}
+
+		localClient = localClient.UseProject(projectName)
+
+		return replicatorReconcileDependencies(projectName, deps, localClient, func(vol replicatorVolume) error {
+			return replicatorRestoreVolume(srcClient, localClient, vol)
+		}), nil
+	}))
 
 	// Use our cluster certificate so the leader can verify TLS when
 	// pushing data back to us.
@@ -1179,6 +1326,11 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 
 	for _, instName := range iterNames {
 		copyFunc := func(ctx context.Context, op *operations.Operation) error {
+			err := depsResult.wait(ctx)
+			if err != nil {
+				return err
+			}
+
 			dstClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(clusterCert, targetCert))
 			if err != nil {
 				return fmt.Errorf("Failed connecting to target cluster: %w", err)
@@ -1420,6 +1572,11 @@ func prepareReplicatorRunOperation(ctx context.Context, s *state.State, projectN
 			// Use a fresh context so the status write always completes, even if the operation context was cancelled.
 			// Only the status is updated here; last_run_date was already set when the operation started.
 			return s.DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+				err := dbCluster.ReplaceReplicatorEntities(ctx, tx.Tx(), replicatorID, depsResult.entities)
+				if err != nil {
+					return err
+				}
+
 				return dbCluster.UpdateReplicatorLastRunStatus(ctx, tx.Tx(), replicatorID, runStatus)
 			})
 		},
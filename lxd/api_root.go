@@ -19,9 +19,12 @@ var apiRoot = []APIEndpoint{
 	bearerLogoutCmd,
 	documentationCmd,
 	documentationRedirectCmd,
+	imageSimplestreamsCmd,
+	jwksCmd,
 	oidcCallbackCmd,
 	oidcLoginCmd,
 	oidcLogoutCmd,
+	openIDConfigurationCmd,
 	rootCmd,
 	uiCmd,
 	uiRedirectCmd,
@@ -79,6 +82,20 @@ var acmeChallengeCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: acmeProvideChallenge, AllowUntrusted: true},
 }
 
+// openIDConfigurationCmd publishes the OpenID provider metadata of the instance identity tokens.
+var openIDConfigurationCmd = APIEndpoint{
+	Path: ".well-known/openid-configuration",
+
+	Get: APIEndpointAction{Handler: openIDConfigurationGet, AllowUntrusted: true},
+}
+
+// jwksCmd publishes the keys that relying parties use to verify instance identity tokens.
+var jwksCmd = APIEndpoint{
+	Path: ".well-known/jwks.json",
+
+	Get: APIEndpointAction{Handler: jwksGet, AllowUntrusted: true},
+}
+
 var oidcLoginCmd = APIEndpoint{
 	Path: "oidc/login",
 
@@ -0,0 +1,671 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+var webhooksCmd = APIEndpoint{
+	Path:        "webhooks",
+	MetricsType: entity.TypeWebhook,
+
+	Get:  APIEndpointAction{Handler: webhooksGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: webhooksPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanCreateWebhooks)},
+}
+
+var webhookCmd = APIEndpoint{
+	Path:        "webhooks/{name}",
+	MetricsType: entity.TypeWebhook,
+
+	Get:    APIEndpointAction{Handler: webhookGet, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanView, "name")},
+	Post:   APIEndpointAction{Handler: webhookPost, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanEdit, "name")},
+	Patch:  APIEndpointAction{Handler: webhookPatch, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanEdit, "name")},
+	Put:    APIEndpointAction{Handler: webhookPut, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanEdit, "name")},
+	Delete: APIEndpointAction{Handler: webhookDelete, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanDelete, "name")},
+}
+
+var webhookStateCmd = APIEndpoint{
+	Path:        "webhooks/{name}/state",
+	MetricsType: entity.TypeWebhook,
+
+	Get: APIEndpointAction{Handler: webhookStateGet, AccessHandler: allowPermission(entity.TypeWebhook, auth.EntitlementCanView, "name")},
+}
+
+// swagger:operation GET /1.0/webhooks webhooks webhooks_get
+//
+//	Get the webhooks
+//
+//	Returns a list of webhooks (URLs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of endpoints
+//	          items:
+//	            type: string
+//	          example: |-
+//	            [
+//	              "/1.0/webhooks/chat-ops",
+//	              "/1.0/webhooks/ticketing"
+//	            ]
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+
+// swagger:operation GET /1.0/webhooks?recursion=1 webhooks webhooks_get_recursion1
+//
+//	Get the webhooks
+//
+//	Returns a list of webhooks (structs).
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Webhooks
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of webhooks
+//	          items:
+//	            $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	recursion, _ := util.IsRecursionRequest(r)
+	withEntitlements, err := extractEntitlementsFromQuery(r, entity.TypeWebhook, true)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanView, entity.TypeWebhook)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	var webhooks []dbCluster.WebhookRow
+	var allConfigs map[int64]map[string]string
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		allWebhooks, err := dbCluster.GetWebhooks(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, webhook := range allWebhooks {
+			if userHasPermission(entity.WebhookURL(webhook.Name)) {
+				webhooks = append(webhooks, webhook)
+			}
+		}
+
+		if recursion != 0 && len(webhooks) > 0 {
+			allConfigs, err = dbCluster.WebhooksConfigStore().GetAll(ctx, tx.Tx())
+			if err != nil {
+				return fmt.Errorf("Failed loading webhook configs: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if recursion == 0 {
+		urls := make([]string, 0, len(webhooks))
+		for _, webhook := range webhooks {
+			urls = append(urls, entity.WebhookURL(webhook.Name).String())
+		}
+
+		return response.SyncResponse(true, urls)
+	}
+
+	apiWebhooks := make([]*api.Webhook, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		apiWebhooks = append(apiWebhooks, webhook.ToAPI(allConfigs))
+	}
+
+	if len(withEntitlements) > 0 {
+		urlToWebhook := make(map[*api.URL]auth.EntitlementReporter, len(apiWebhooks))
+		for _, w := range apiWebhooks {
+			urlToWebhook[entity.WebhookURL(w.Name)] = w
+		}
+
+		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypeWebhook, withEntitlements, urlToWebhook)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.SyncResponse(true, apiWebhooks)
+}
+
+// swagger:operation POST /1.0/webhooks webhooks webhooks_post
+//
+//	Add a webhook
+//
+//	Creates a new webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: The new webhook
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhooksPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhooksPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.WebhooksPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validate.IsEntityName(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid webhook name: %w", err))
+	}
+
+	err = webhookValidateConfig(req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := dbCluster.CreateWebhook(ctx, tx.Tx(), dbCluster.WebhookRow{
+			Name:        req.Name,
+			Description: req.Description,
+		})
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.WebhooksConfigStore().Set(ctx, tx.Tx(), id, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	webhookReload(r.Context(), d)
+
+	lc := lifecycle.WebhookCreated.Event(req.Name, request.CreateRequestor(r.Context()), nil)
+	s.Events.SendLifecycle(api.ProjectDefaultName, lc)
+
+	return response.SyncResponseLocation(true, nil, lc.Source)
+}
+
+// swagger:operation GET /1.0/webhooks/{name} webhooks webhook_get
+//
+//	Get the webhook
+//
+//	Gets a specific webhook.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Webhook
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/Webhook"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	withEntitlements, err := extractEntitlementsFromQuery(r, entity.TypeWebhook, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	name := r.PathValue("name")
+	var apiWebhook *api.Webhook
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbWebhook, err := dbCluster.GetWebhook(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		config, err := dbCluster.WebhooksConfigStore().GetByEntityIDs(ctx, tx.Tx(), dbWebhook.ID)
+		if err != nil {
+			return fmt.Errorf("Failed loading webhook config: %w", err)
+		}
+
+		apiWebhook = dbWebhook.ToAPI(config)
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if len(withEntitlements) > 0 {
+		err = reportEntitlements(r.Context(), s.Authorizer, entity.TypeWebhook, withEntitlements, map[*api.URL]auth.EntitlementReporter{entity.WebhookURL(name): apiWebhook})
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
+	return response.SyncResponseETag(true, apiWebhook, apiWebhook.Writable())
+}
+
+// updateWebhook is shared between [webhookPut] and [webhookPatch].
+func updateWebhook(d *Daemon, r *http.Request, isPatch bool) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+	var dbWebhook *dbCluster.WebhookRow
+	var apiWebhook *api.Webhook
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		dbWebhook, err = dbCluster.GetWebhook(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		config, err := dbCluster.WebhooksConfigStore().GetByEntityIDs(ctx, tx.Tx(), dbWebhook.ID)
+		if err != nil {
+			return fmt.Errorf("Failed loading webhook config: %w", err)
+		}
+
+		apiWebhook = dbWebhook.ToAPI(config)
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Validate ETag.
+	err = util.EtagCheck(r, apiWebhook.Writable())
+	if err != nil {
+		return response.PreconditionFailed(err)
+	}
+
+	req := api.WebhookPut{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if isPatch {
+		// Populate request config with current values.
+		if req.Config == nil {
+			req.Config = apiWebhook.Config
+		} else {
+			for k, v := range apiWebhook.Config {
+				_, ok := req.Config[k]
+				if !ok {
+					req.Config[k] = v
+				}
+			}
+		}
+	}
+
+	err = webhookValidateConfig(req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !isPatch || req.Description != "" {
+		dbWebhook.Description = req.Description
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := dbCluster.UpdateWebhook(ctx, tx.Tx(), *dbWebhook)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.WebhooksConfigStore().Set(ctx, tx.Tx(), dbWebhook.ID, req.Config)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	webhookReload(r.Context(), d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookUpdated.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation PATCH /1.0/webhooks/{name} webhooks webhook_patch
+//
+//	Partially update the webhook
+//
+//	Updates a subset of the webhook configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPatch(d *Daemon, r *http.Request) response.Response {
+	return updateWebhook(d, r, true)
+}
+
+// swagger:operation PUT /1.0/webhooks/{name} webhooks webhook_put
+//
+//	Update the webhook
+//
+//	Updates the entire webhook configuration.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Webhook configuration
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPut"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "412":
+//	    $ref: "#/responses/PreconditionFailed"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPut(d *Daemon, r *http.Request) response.Response {
+	return updateWebhook(d, r, false)
+}
+
+// swagger:operation POST /1.0/webhooks/{name} webhooks webhook_post
+//
+//	Rename the webhook
+//
+//	Renames the webhook.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: webhook
+//	    description: Rename webhook request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/WebhookPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	req := api.WebhookPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	err = validate.IsEntityName(req.Name)
+	if err != nil {
+		return response.BadRequest(fmt.Errorf("Invalid webhook name: %w", err))
+	}
+
+	name := r.PathValue("name")
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbWebhook, err := dbCluster.GetWebhook(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		dbWebhook.Name = req.Name
+		return dbCluster.UpdateWebhook(ctx, tx.Tx(), *dbWebhook)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookRenamed.Event(req.Name, request.CreateRequestor(r.Context()), logger.Ctx{"old_name": name}))
+
+	return response.SyncResponseLocation(true, nil, entity.WebhookURL(req.Name).String())
+}
+
+// swagger:operation DELETE /1.0/webhooks/{name} webhooks webhook_delete
+//
+//	Delete the webhook
+//
+//	Deletes the webhook and its pending deliveries.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookDelete(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.DeleteWebhook(ctx, tx.Tx(), name)
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	webhookReload(r.Context(), d)
+
+	s.Events.SendLifecycle(api.ProjectDefaultName, lifecycle.WebhookDeleted.Event(name, request.CreateRequestor(r.Context()), nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/webhooks/{name}/state webhooks webhook_state_get
+//
+//	Get the webhook state
+//
+//	Gets the delivery status of a specific webhook, across all cluster members.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Webhook state
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/WebhookState"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func webhookStateGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	name := r.PathValue("name")
+	state := api.WebhookState{Deliveries: []api.WebhookDelivery{}}
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbWebhook, err := dbCluster.GetWebhook(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		status, err := dbCluster.GetWebhookStatus(ctx, tx.Tx(), dbWebhook.ID)
+		if err != nil {
+			return err
+		}
+
+		state.LastDeliveryAt = status.LastDeliveryDate.Time
+		state.LastFailureAt = status.LastFailureDate.Time
+		state.LastError = status.LastError
+
+		deliveries, err := dbCluster.GetWebhookDeliveries(ctx, tx.Tx(), name)
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			apiDelivery := delivery.ToAPI()
+			if apiDelivery.Status == api.WebhookDeliveryStatusFailed {
+				state.Failed++
+			} else {
+				state.Pending++
+			}
+
+			state.Deliveries = append(state.Deliveries, apiDelivery)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, state)
+}
+
+// webhookReload refreshes the webhooks of this member after a change. Other members pick up the change on the
+// next run of their delivery task.
+func webhookReload(ctx context.Context, d *Daemon) {
+	err := d.webhooks.reload(ctx)
+	if err != nil {
+		logger.Warn("Failed reloading webhooks", logger.Ctx{"err": err})
+	}
+}
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var auditCmd = APIEndpoint{
+	Path:        "audit",
+	MetricsType: entity.TypeServer,
+
+	Get: APIEndpointAction{Handler: auditGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanViewAuditLog)},
+}
+
+// swagger:operation GET /1.0/audit audit audit_get
+//
+//	Get the audit log
+//
+//	Returns the entries of the audit log, oldest first.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: since
+//	    description: Only return the entries recorded at or after this date (RFC3339)
+//	    type: string
+//	    example: 2021-03-23T17:38:37Z
+//	  - in: query
+//	    name: until
+//	    description: Only return the entries recorded at or before this date (RFC3339)
+//	    type: string
+//	    example: 2021-03-24T17:38:37Z
+//	  - in: query
+//	    name: identity
+//	    description: Only return the entries of this identity
+//	    type: string
+//	    example: 2e28a8a1b6d1ec8ea2bdb88ad7b5d12a3ea7dc2a6f7e3f48d1c4cd1f12c7e2d6
+//	  - in: query
+//	    name: project
+//	    description: Only return the entries of this project
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: entity_url
+//	    description: Only return the entries of this entity and of the entities below it
+//	    type: string
+//	    example: /1.0/instances/c1
+//	responses:
+//	  "200":
+//	    description: Audit log entries
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of audit log entries
+//	          items:
+//	            $ref: "#/definitions/AuditEntry"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func auditGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	filter := dbCluster.AuditLogFilter{}
+	for key, dest := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := request.QueryParam(r, key)
+		if value == "" {
+			continue
+		}
+
+		date, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return response.BadRequest(fmt.Errorf("Invalid %q date: %w", key, err))
+		}
+
+		*dest = &date
+	}
+
+	for key, dest := range map[string]**string{"identity": &filter.Identity, "project": &filter.Project, "entity_url": &filter.EntityURL} {
+		value := request.QueryParam(r, key)
+		if value != "" {
+			*dest = &value
+		}
+	}
+
+	entries := []api.AuditEntry{}
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		rows, err := dbCluster.GetAuditLogEntries(ctx, tx.Tx(), filter)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			entries = append(entries, row.ToAPI())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, entries)
+}
+
+// auditBody hashes the request body as it's read by the handler.
+type auditBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+// Read implements [io.Reader].
+func (b *auditBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	_, _ = b.hash.Write(p[:n])
+	return n, err
+}
+
+// auditResponseWriter records the status code of the response.
+type auditResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader implements [http.ResponseWriter].
+func (w *auditResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 {
+		w.statusCode = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements [http.ResponseWriter].
+func (w *auditResponseWriter) Write(b []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements [http.Flusher].
+func (w *auditResponseWriter) Flush() {
+	flusher, ok := w.ResponseWriter.(http.Flusher)
+	if ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements [http.Hijacker], which is needed for websocket upgrades.
+func (w *auditResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("Response writer doesn't support hijacking")
+	}
+
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped response writer, for use by [http.ResponseController].
+func (w *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// auditLogMaxPending is the maximum number of entries waiting to be written to the audit log. Once reached, the
+// entries are written by the request handlers themselves.
+const auditLogMaxPending = 1024
+
+// auditLog writes the entries of the audit log in the background, through a queue that is flushed on shutdown.
+type auditLog struct {
+	stateFunc func() *state.State
+	entries   chan dbCluster.AuditLogRow
+	done      chan struct{}
+
+	// Cancelled on shutdown to stop waiting for the operations of asynchronous requests.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	stopped bool
+	waiting sync.WaitGroup
+}
+
+// newAuditLog returns an audit log and starts writing the queued entries.
+func newAuditLog(stateFunc func() *state.State) *auditLog {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	a := &auditLog{
+		stateFunc: stateFunc,
+		entries:   make(chan dbCluster.AuditLogRow, auditLogMaxPending),
+		done:      make(chan struct{}),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	go func() {
+		defer close(a.done)
+
+		for entry := range a.entries {
+			a.write(entry)
+		}
+	}()
+
+	return a
+}
+
+// write records an entry in the audit log.
+func (a *auditLog) write(entry dbCluster.AuditLogRow) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := a.stateFunc().DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.CreateAuditLogEntry(ctx, tx.Tx(), entry)
+	})
+	if err != nil {
+		logger.Warn("Failed recording request in audit log", logger.Ctx{"method": entry.Method, "url": entry.EntityURL, "err": err})
+	}
+}
+
+// add queues an entry for writing. The entry is written synchronously if the queue is full or stopped.
+func (a *auditLog) add(entry dbCluster.AuditLogRow) {
+	a.mu.Lock()
+	if !a.stopped {
+		select {
+		case a.entries <- entry:
+			a.mu.Unlock()
+			return
+		default:
+		}
+	}
+
+	a.mu.Unlock()
+
+	a.write(entry)
+}
+
+// addAfterOperation queues an entry once the operation of the given URL has finished, with the final status code of
+// the operation. If the audit log is stopped first, the entry is queued with the status code of the request.
+func (a *auditLog) addAfterOperation(entry dbCluster.AuditLogRow, operationURL string) {
+	a.mu.Lock()
+	if a.stopped {
+		a.mu.Unlock()
+		a.add(entry)
+		return
+	}
+
+	a.waiting.Add(1)
+	a.mu.Unlock()
+
+	go func() {
+		defer a.waiting.Done()
+
+		statusCode, err := auditOperationStatus(a.ctx, a.stateFunc(), path.Base(operationURL))
+		if err != nil {
+			logger.Debug("Failed getting final status of audited operation", logger.Ctx{"operation": operationURL, "err": err})
+		} else {
+			entry.StatusCode = int(statusCode)
+		}
+
+		a.add(entry)
+	}()
+}
+
+// stop stops waiting for the operations of asynchronous requests and writes all queued entries.
+func (a *auditLog) stop(timeout time.Duration) error {
+	// Mark the log as stopped first so that no request starts waiting for its operation once the waiting ones are
+	// cancelled. Those write their entries synchronously, as the queue is closed.
+	a.mu.Lock()
+	a.stopped = true
+	close(a.entries)
+	a.mu.Unlock()
+
+	a.cancel()
+	a.waiting.Wait()
+
+	select {
+	case <-a.done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("Timed out writing the audit log")
+	}
+}
+
+// auditOperationStatus waits for an operation to finish and returns its status code. Operations running on other
+// cluster members are waited for through the local API, which forwards the request to them.
+func auditOperationStatus(ctx context.Context, s *state.State, operationID string) (api.StatusCode, error) {
+	op, err := operations.OperationGetInternal(operationID)
+	if err == nil {
+		_ = op.Wait(ctx)
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+
+		return op.Status(), nil
+	}
+
+	client, err := lxd.ConnectLXDUnixWithContext(ctx, s.OS.GetUnixSocket(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer client.Disconnect()
+
+	// Wait in short steps so that a shutdown doesn't have to wait for long running operations.
+	for {
+		apiOp, _, err := client.GetOperationWait(operationID, 5)
+		if err != nil {
+			return 0, err
+		}
+
+		if apiOp.StatusCode.IsFinal() {
+			return apiOp.StatusCode, nil
+		}
+
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+	}
+}
+
+// auditStart prepares the recording of a request in the audit log. It returns the response writer and request to
+// use for handling the request, and a function recording the request once handled. Only mutating requests made by
+// API clients are recorded, requests forwarded or sent by other cluster members are recorded by the member that
+// received them from the client. Asynchronous requests are recorded once their operation has finished, with the
+// status code of the operation.
+func auditStart(s *state.State, audit *auditLog, w http.ResponseWriter, r *http.Request) (http.ResponseWriter, *http.Request, func()) {
+	if slices.Contains([]string{http.MethodGet, http.MethodHead, http.MethodOptions}, r.Method) {
+		return w, r, func() {}
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil || requestor.IsForwarded() || requestor.IsClusterNotification() || requestor.Protocol == request.ProtocolCluster {
+		return w, r, func() {}
+	}
+
+	body := &auditBody{ReadCloser: r.Body, hash: sha256.New()}
+	r.Body = body
+	recorder := &auditResponseWriter{ResponseWriter: w}
+
+	sourceAddress := requestor.OriginAddress
+	if sourceAddress == "" {
+		sourceAddress = r.RemoteAddr
+	}
+
+	projectName := request.ProjectParam(r)
+	entityURL := &api.URL{URL: url.URL{Path: r.URL.Path}}
+
+	entry := dbCluster.AuditLogRow{
+		Date:          time.Now().UTC(),
+		Location:      s.ServerName,
+		Identity:      requestor.Username,
+		Protocol:      requestor.Protocol,
+		SourceAddress: sourceAddress,
+		Method:        r.Method,
+		Project:       projectName,
+		EntityURL:     entityURL.Project(projectName).String(),
+	}
+
+	record := func() {
+		entry.RequestHash = hex.EncodeToString(body.hash.Sum(nil))
+		entry.StatusCode = recorder.statusCode
+
+		operationURL := recorder.Header().Get("Location")
+		if entry.StatusCode == http.StatusAccepted && strings.HasPrefix(operationURL, "/"+version.APIVersion+"/operations/") {
+			audit.addAfterOperation(entry, operationURL)
+			return
+		}
+
+		audit.add(entry)
+	}
+
+	return recorder, r, record
+}
+
+// expireAuditLogTask returns a task that removes the audit log entries older than core.audit_log_expiry.
+func expireAuditLogTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		// Only the leader removes the expired entries of the whole cluster.
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil || (leaderInfo.Clustered && !leaderInfo.Leader) {
+			return
+		}
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return expireAuditLog(ctx, s)
+		}
+
+		args := operations.OperationArgs{
+			Type:    operationtype.AuditLogExpire,
+			Class:   operationtype.OperationClassTask,
+			RunHook: opRun,
+		}
+
+		logger.Info("Expiring audit log entries")
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Error("Failed creating audit log expiry operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed expiring audit log entries", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done expiring audit log entries")
+	}
+
+	return f, task.Daily()
+}
+
+func expireAuditLog(ctx context.Context, s *state.State) error {
+	now := time.Now().UTC()
+	expiry, err := shared.GetExpiry(now, s.GlobalConfig.AuditLogExpiry())
+	if err != nil {
+		return fmt.Errorf("Failed parsing audit log expiry: %w", err)
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		_, err := dbCluster.DeleteAuditLogEntriesBefore(ctx, tx.Tx(), now.Add(-expiry.Sub(now)))
+		return err
+	})
+}
@@ -100,6 +100,7 @@ func (s *tlsSuite) TestTLSAuthorizer() {
 				auth.EntitlementCanOverrideClusterTargetRestriction,
 				auth.EntitlementCanViewEvents,
 				auth.EntitlementCanViewWarnings,
+				auth.EntitlementCanSignImages,
 			},
 			expectErr:     true,
 			expectErrCode: http.StatusForbidden,
@@ -0,0 +1,147 @@
+package encryption
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// IdentityTokenSigningAlgorithm is the JWS algorithm used for instance identity tokens.
+const IdentityTokenSigningAlgorithm = "ES256"
+
+// IdentityTokenClaims are the claims of an instance identity token.
+type IdentityTokenClaims struct {
+	jwt.RegisteredClaims
+
+	// ClusterUUID is the UUID of the LXD cluster (or standalone server) that issued the token.
+	ClusterUUID string `json:"cluster_uuid"`
+
+	// Project is the project of the instance.
+	Project string `json:"project"`
+
+	// Instance is the name of the instance.
+	Instance string `json:"instance"`
+
+	// InstanceUUID is the UUID of the instance (volatile.uuid).
+	InstanceUUID string `json:"instance_uuid"`
+
+	// InstanceType is the type of the instance (container or virtual-machine).
+	InstanceType string `json:"instance_type"`
+
+	// User contains the user.* configuration keys selected for the token, without their "user." prefix.
+	User map[string]string `json:"user,omitempty"`
+}
+
+// IdentityTokenSubject returns the sub claim of the identity tokens issued to the instance with the given UUID.
+// The subject is based on the UUID rather than the name, as names are reused after instances are deleted or renamed.
+func IdentityTokenSubject(instanceUUID string) string {
+	return "instance:" + instanceUUID
+}
+
+// IdentityTokenSigningKey returns an ECDSA P-256 key suitable for signing instance identity tokens with ES256.
+// The key is derived from the given secret and cluster UUID so that all cluster members use the same key.
+func IdentityTokenSigningKey(secret []byte, clusterUUID string) (*ecdsa.PrivateKey, error) {
+	salt, err := uuid.Parse(clusterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid cluster UUID: %w", err)
+	}
+
+	scalar, err := deriveKey(secret, salt[:], "IDENTITY", 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// The derived value is out of range with a probability of about 2^-32, which is left to secret rotation.
+	key, err := ecdsa.ParseRawPrivateKey(elliptic.P256(), scalar)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating identity token signing key: %w", err)
+	}
+
+	return key, nil
+}
+
+// IdentityTokenJWK returns the JSON Web Key of the public part of an identity token signing key.
+// The key ID is the JWK thumbprint (RFC 7638) of the key.
+func IdentityTokenJWK(key *ecdsa.PublicKey) (*api.JSONWebKey, error) {
+	point, err := key.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Uncompressed point: 0x04 || X || Y.
+	size := (len(point) - 1) / 2
+	jwk := api.JSONWebKey{
+		KeyType:   "EC",
+		Use:       "sig",
+		Algorithm: IdentityTokenSigningAlgorithm,
+		Curve:     "P-256",
+		X:         base64.RawURLEncoding.EncodeToString(point[1 : 1+size]),
+		Y:         base64.RawURLEncoding.EncodeToString(point[1+size:]),
+	}
+
+	// The thumbprint covers the required members in lexicographic order.
+	thumbprintInput, err := json.Marshal(struct {
+		Crv string `json:"crv"`
+		Kty string `json:"kty"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}{Crv: jwk.Curve, Kty: jwk.KeyType, X: jwk.X, Y: jwk.Y})
+	if err != nil {
+		return nil, err
+	}
+
+	thumbprint := sha256.Sum256(thumbprintInput)
+	jwk.KeyID = base64.RawURLEncoding.EncodeToString(thumbprint[:])
+
+	return &jwk, nil
+}
+
+// GetIdentityToken signs an instance identity token with the given key. For registered claims it has:
+// - Issuer (iss): The given issuer URL.
+// - Subject (sub): "instance:{instance_uuid}".
+// - Audience (aud): The given audience.
+// - Not before (nbf): time now (UTC).
+// - Issued at (iat): time now (UTC).
+// - Expiry (exp): The given time (UTC).
+// - JWT ID (jti): A random UUID.
+func GetIdentityToken(key *ecdsa.PrivateKey, issuer string, audience string, expiresAt time.Time, claims IdentityTokenClaims) (string, error) {
+	if claims.InstanceUUID == "" {
+		return "", errors.New("Instance UUID is required")
+	}
+
+	jwk, err := IdentityTokenJWK(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    issuer,
+		Subject:   IdentityTokenSubject(claims.InstanceUUID),
+		Audience:  jwt.ClaimStrings{audience},
+		NotBefore: jwt.NewNumericDate(now),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt.UTC()),
+		ID:        uuid.NewString(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = jwk.KeyID
+
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed signing JWT: %w", err)
+	}
+
+	return signedToken, nil
+}
@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentityTokenSigningKey(t *testing.T) {
+	secret := slices.Repeat([]byte{'0'}, 64)
+	clusterUUID := "a4b1b8d2-5b0e-4f6e-9f5a-2f0c9d1e7b3a"
+
+	key1, err := IdentityTokenSigningKey(secret, clusterUUID)
+	require.NoError(t, err)
+
+	// The key is derived deterministically so that all cluster members agree on it.
+	key2, err := IdentityTokenSigningKey(secret, clusterUUID)
+	require.NoError(t, err)
+	assert.True(t, key1.Equal(key2))
+
+	// A different cluster gets a different key.
+	key3, err := IdentityTokenSigningKey(secret, "0b6f3c0e-1d2a-4c5b-8e7f-9a0b1c2d3e4f")
+	require.NoError(t, err)
+	assert.False(t, key1.Equal(key3))
+
+	_, err = IdentityTokenSigningKey(secret, "not-a-uuid")
+	assert.Error(t, err)
+}
+
+func TestGetIdentityToken(t *testing.T) {
+	clusterUUID := "a4b1b8d2-5b0e-4f6e-9f5a-2f0c9d1e7b3a"
+	key, err := IdentityTokenSigningKey(slices.Repeat([]byte{'0'}, 64), clusterUUID)
+	require.NoError(t, err)
+
+	jwk, err := IdentityTokenJWK(&key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "EC", jwk.KeyType)
+	assert.Equal(t, "P-256", jwk.Curve)
+	assert.Len(t, jwk.X, 43)
+	assert.Len(t, jwk.Y, 43)
+
+	expiresAt := time.Now().Add(10 * time.Minute)
+	signed, err := GetIdentityToken(key, "https://lxd.example.net:8443", "vault", expiresAt, IdentityTokenClaims{
+		ClusterUUID:  clusterUUID,
+		Project:      "default",
+		Instance:     "c1",
+		InstanceUUID: "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f",
+		InstanceType: "container",
+		User:         map[string]string{"role": "web"},
+	})
+	require.NoError(t, err)
+
+	claims := IdentityTokenClaims{}
+	token, err := jwt.ParseWithClaims(signed, &claims, func(token *jwt.Token) (any, error) {
+		assert.Equal(t, jwk.KeyID, token.Header["kid"])
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{IdentityTokenSigningAlgorithm}), jwt.WithIssuer("https://lxd.example.net:8443"), jwt.WithAudience("vault"), jwt.WithExpirationRequired())
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+
+	assert.Equal(t, "instance:6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f", claims.Subject)
+	assert.Equal(t, clusterUUID, claims.ClusterUUID)
+	assert.Equal(t, "default", claims.Project)
+	assert.Equal(t, "c1", claims.Instance)
+	assert.Equal(t, "6f1c2d3e-4a5b-4c6d-8e9f-0a1b2c3d4e5f", claims.InstanceUUID)
+	assert.Equal(t, "container", claims.InstanceType)
+	assert.Equal(t, map[string]string{"role": "web"}, claims.User)
+	assert.NotEmpty(t, claims.ID)
+
+	// The subject is based on the UUID of the instance, so tokens can't be issued without it.
+	_, err = GetIdentityToken(key, "https://lxd.example.net:8443", "vault", expiresAt, IdentityTokenClaims{Project: "default", Instance: "c1"})
+	assert.Error(t, err)
+
+	// Tokens signed by another key don't verify.
+	otherKey, err := IdentityTokenSigningKey(slices.Repeat([]byte{'1'}, 64), clusterUUID)
+	require.NoError(t, err)
+
+	_, err = jwt.ParseWithClaims(signed, &IdentityTokenClaims{}, func(token *jwt.Token) (any, error) {
+		return &otherKey.PublicKey, nil
+	})
+	assert.Error(t, err)
+}
@@ -1,9 +1,12 @@
 package encryption
 
 import (
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha512"
 	"fmt"
+
+	"github.com/google/uuid"
 )
 
 var hashFunc = sha512.New
@@ -23,6 +26,22 @@ func TokenSigningKey(secret []byte, salt []byte) ([]byte, error) {
 	return deriveKey(secret, salt, "SIGNATURE", 64)
 }
 
+// ImageSigningKey returns an Ed25519 key suitable for signing images.
+// The key is derived from the given secret and cluster UUID so that all cluster members use the same key.
+func ImageSigningKey(secret []byte, clusterUUID string) (ed25519.PrivateKey, error) {
+	salt, err := uuid.Parse(clusterUUID)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid cluster UUID: %w", err)
+	}
+
+	seed, err := deriveKey(secret, salt[:], "IMAGE", ed25519.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
 // deriveKey uses HMAC to derive a key from a secret, a salt, and a separator. We can use HMAC directly because our
 // initial key material is uniformly random and of sufficient length.
 func deriveKey(secret []byte, salt []byte, usageSeparator string, length uint) ([]byte, error) {
@@ -119,3 +119,24 @@ func Test_deriveKey(t *testing.T) {
 		assert.Equal(t, tt.want, got)
 	}
 }
+
+func TestImageSigningKey(t *testing.T) {
+	secret := slices.Repeat([]byte{'0'}, 64)
+	clusterUUID := "a4b1b8d2-5b0e-4f6e-9f5a-2f0c9d1e7b3a"
+
+	key1, err := ImageSigningKey(secret, clusterUUID)
+	assert.NoError(t, err)
+
+	// The key is derived deterministically so that all cluster members agree on it.
+	key2, err := ImageSigningKey(secret, clusterUUID)
+	assert.NoError(t, err)
+	assert.True(t, key1.Equal(key2))
+
+	// A new secret gives a new key.
+	key3, err := ImageSigningKey(slices.Repeat([]byte{'1'}, 64), clusterUUID)
+	assert.NoError(t, err)
+	assert.False(t, key1.Equal(key3))
+
+	_, err = ImageSigningKey(secret, "not-a-uuid")
+	assert.Error(t, err)
+}
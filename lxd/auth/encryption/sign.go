@@ -14,6 +14,7 @@ import (
 
 const (
 	audienceDevLXD = "devlxd"
+	audienceSCIM   = "scim"
 )
 
 // DevLXDAudience returns the aud claim for all DevLXD tokens issued by this cluster.
@@ -21,6 +22,11 @@ func DevLXDAudience(clusterUUID string) string {
 	return strings.Join([]string{audienceDevLXD, clusterUUID}, ":")
 }
 
+// SCIMAudience returns the aud claim for all SCIM tokens issued by this cluster.
+func SCIMAudience(clusterUUID string) string {
+	return strings.Join([]string{audienceSCIM, clusterUUID}, ":")
+}
+
 // Issuer returns the iss claim for all tokens issued by this LXD cluster.
 func Issuer(clusterUUID string) string {
 	return strings.Join([]string{"lxd", clusterUUID}, ":")
@@ -43,6 +49,17 @@ func GetDevLXDBearerToken(secret []byte, identityIdentifier string, clusterUUID
 	return getToken(secret, nil, identityIdentifier, clusterUUID, DevLXDAudience, expiresAt, "")
 }
 
+// GetSCIMBearerToken generates and signs a token for use with the SCIM provisioning API. For claims it has:
+// - Subject (sub): Identity identifier (UUID)
+// - Issuer (iss): "lxd:{cluster_uuid}"
+// - Audience (aud): "scim:{cluster_uuid}"
+// - Not before (nbf): time now (UTC)
+// - Issued at (iat): time now (UTC)
+// - Expiry (exp): The given time (UTC).
+func GetSCIMBearerToken(secret []byte, identityIdentifier string, clusterUUID string, expiresAt time.Time) (string, error) {
+	return getToken(secret, nil, identityIdentifier, clusterUUID, SCIMAudience, expiresAt, "")
+}
+
 // GetClientBearerToken generates and signs a token for use with the main LXD API. For claims it has:
 // - Subject (sub): Identity identifier (UUID)
 // - Issuer (iss): "lxd:{cluster_uuid}"
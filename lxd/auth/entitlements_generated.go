@@ -10,13 +10,13 @@ import (
 type Entitlement string
 
 const (
-	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStorageVolume.
+	// EntitlementCanView is the "can_view" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStorageVolume, entity.TypeWebhook.
 	EntitlementCanView Entitlement = "can_view"
 
-	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanEdit is the "can_edit" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeServer, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume, entity.TypeWebhook.
 	EntitlementCanEdit Entitlement = "can_edit"
 
-	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume.
+	// EntitlementCanDelete is the "can_delete" entitlement. It applies to the following entities: entity.TypeCertificate, entity.TypeClusterLink, entity.TypeAuthGroup, entity.TypeIdentity, entity.TypeIdentityProviderGroup, entity.TypeImage, entity.TypeImageAlias, entity.TypeInstance, entity.TypeNetwork, entity.TypeNetworkACL, entity.TypeNetworkZone, entity.TypePlacementGroup, entity.TypeProfile, entity.TypeProject, entity.TypeReplicator, entity.TypeStorageBucket, entity.TypeStoragePool, entity.TypeStorageVolume, entity.TypeWebhook.
 	EntitlementCanDelete Entitlement = "can_delete"
 
 	// EntitlementAdmin is the "admin" entitlement. It applies to the following entities: entity.TypeServer.
@@ -103,6 +103,9 @@ const (
 	// EntitlementCanViewOperations is the "can_view_operations" entitlement. It applies to the following entities: entity.TypeProject, entity.TypeServer.
 	EntitlementCanViewOperations Entitlement = "can_view_operations"
 
+	// EntitlementCanApproveOperations is the "can_approve_operations" entitlement. It applies to the following entities: entity.TypeProject, entity.TypeServer.
+	EntitlementCanApproveOperations Entitlement = "can_approve_operations"
+
 	// EntitlementCanViewResources is the "can_view_resources" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanViewResources Entitlement = "can_view_resources"
 
@@ -112,6 +115,12 @@ const (
 	// EntitlementCanViewWarnings is the "can_view_warnings" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanViewWarnings Entitlement = "can_view_warnings"
 
+	// EntitlementCanViewAuditLog is the "can_view_audit_log" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanViewAuditLog Entitlement = "can_view_audit_log"
+
+	// EntitlementCanSignImages is the "can_sign_images" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanSignImages Entitlement = "can_sign_images"
+
 	// EntitlementCanViewUnmanagedNetworks is the "can_view_unmanaged_networks" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanViewUnmanagedNetworks Entitlement = "can_view_unmanaged_networks"
 
@@ -127,6 +136,18 @@ const (
 	// EntitlementCanDeleteClusterLinks is the "can_delete_cluster_links" entitlement. It applies to the following entities: entity.TypeServer.
 	EntitlementCanDeleteClusterLinks Entitlement = "can_delete_cluster_links"
 
+	// EntitlementCanCreateWebhooks is the "can_create_webhooks" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanCreateWebhooks Entitlement = "can_create_webhooks"
+
+	// EntitlementCanViewWebhooks is the "can_view_webhooks" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanViewWebhooks Entitlement = "can_view_webhooks"
+
+	// EntitlementCanEditWebhooks is the "can_edit_webhooks" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanEditWebhooks Entitlement = "can_edit_webhooks"
+
+	// EntitlementCanDeleteWebhooks is the "can_delete_webhooks" entitlement. It applies to the following entities: entity.TypeServer.
+	EntitlementCanDeleteWebhooks Entitlement = "can_delete_webhooks"
+
 	// EntitlementOperator is the "operator" entitlement. It applies to the following entities: entity.TypeInstance, entity.TypeProject.
 	EntitlementOperator Entitlement = "operator"
 
@@ -321,6 +342,9 @@ const (
 
 	// EntitlementCanExec is the "can_exec" entitlement. It applies to the following entities: entity.TypeInstance.
 	EntitlementCanExec Entitlement = "can_exec"
+
+	// EntitlementCanPortForward is the "can_port_forward" entitlement. It applies to the following entities: entity.TypeInstance.
+	EntitlementCanPortForward Entitlement = "can_port_forward"
 )
 
 var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
@@ -405,6 +429,8 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanAccessConsole,
 		// Grants permission to start a terminal session.
 		EntitlementCanExec,
+		// Grants permission to forward ports to the instance.
+		EntitlementCanPortForward,
 	},
 	entity.TypeNetwork: {
 		// Grants permission to edit the network.
@@ -571,6 +597,8 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanDeleteReplicators,
 		// Grants permission to view operations relating to the project.
 		EntitlementCanViewOperations,
+		// Grants permission to approve or reject operations pending approval in the project.
+		EntitlementCanApproveOperations,
 		// Grants permission to view life cycle events relating to the project.
 		EntitlementCanViewEvents,
 		// Grants permission to view project level metrics.
@@ -643,12 +671,18 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanViewEvents,
 		// Grants permission to view operations that are not specific to a project.
 		EntitlementCanViewOperations,
+		// Grants permission to approve or reject operations pending approval, in any project and for operations that are not specific to a project.
+		EntitlementCanApproveOperations,
 		// Grants permission to view server and storage pool resource usage information.
 		EntitlementCanViewResources,
 		// Grants permission to view all server and project level metrics.
 		EntitlementCanViewMetrics,
 		// Grants permission to view warnings.
 		EntitlementCanViewWarnings,
+		// Grants permission to view the audit log.
+		EntitlementCanViewAuditLog,
+		// Grants permission to sign images with the image signing key of the server.
+		EntitlementCanSignImages,
 		// Grants permission to view unmanaged networks on the LXD host machines.
 		EntitlementCanViewUnmanagedNetworks,
 		// Grants permission to create cluster links.
@@ -659,6 +693,14 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		EntitlementCanEditClusterLinks,
 		// Grants permission to delete cluster links.
 		EntitlementCanDeleteClusterLinks,
+		// Grants permission to create webhooks.
+		EntitlementCanCreateWebhooks,
+		// Grants permission to view webhooks, including their signing secrets.
+		EntitlementCanViewWebhooks,
+		// Grants permission to edit webhooks.
+		EntitlementCanEditWebhooks,
+		// Grants permission to delete webhooks.
+		EntitlementCanDeleteWebhooks,
 	},
 	entity.TypeStorageBucket: {
 		// Grants permission to edit the storage bucket.
@@ -686,4 +728,12 @@ var EntityTypeToEntitlements = map[entity.Type][]Entitlement{
 		// Grants permission to create and delete backups of the storage volume.
 		EntitlementCanManageBackups,
 	},
+	entity.TypeWebhook: {
+		// Grants permission to view the webhook.
+		EntitlementCanView,
+		// Grants permission to edit the webhook.
+		EntitlementCanEdit,
+		// Grants permission to delete the webhook.
+		EntitlementCanDelete,
+	},
 }
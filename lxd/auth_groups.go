@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"slices"
@@ -334,6 +335,11 @@ func createAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	err = validateAPILimitsConfig(group.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	s := d.State()
 	validatedPermissions, err := validatePermissions(r.Context(), s, group.Permissions)
 	if err != nil {
@@ -354,12 +360,19 @@ func createAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		return nil
+		return dbCluster.AuthGroupsConfigStore().Set(ctx, tx.Tx(), groupID, group.Config)
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if apiLimitsConfigChanged(nil, group.Config) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Send a lifecycle event for the group creation
 	lc := lifecycle.AuthGroupCreated.Event(group.Name, request.CreateRequestor(r.Context()), nil)
 	s.Events.SendLifecycle("", lc)
@@ -488,6 +501,11 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(fmt.Errorf("Invalid request body: %w", err))
 	}
 
+	err = validateAPILimitsConfig(groupPut.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	s := d.State()
 	validatedPermissions, err := validatePermissions(r.Context(), s, groupPut.Permissions)
 	if err != nil {
@@ -504,6 +522,7 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(fmt.Errorf("Failed getting a permission checker: %w", err))
 	}
 
+	var oldConfig map[string]string
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		group, err := dbCluster.GetAuthGroup(ctx, tx.Tx(), groupName)
 		if err != nil {
@@ -520,6 +539,7 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		oldConfig = apiGroup.Config
 		group.Description = groupPut.Description
 
 		err = query.UpdateByPrimaryKey(ctx, tx.Tx(), group)
@@ -532,12 +552,19 @@ func updateAuthGroup(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
-		return nil
+		return dbCluster.AuthGroupsConfigStore().Set(ctx, tx.Tx(), group.ID, groupPut.Config)
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if apiLimitsConfigChanged(oldConfig, groupPut.Config) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Send a lifecycle event for the group update
 	lc := lifecycle.AuthGroupUpdated.Event(groupName, request.CreateRequestor(r.Context()), nil)
 	s.Events.SendLifecycle("", lc)
@@ -600,6 +627,8 @@ func patchAuthGroup(d *Daemon, r *http.Request) response.Response {
 
 	newPermissions := make([]api.Permission, 0, len(groupPut.Permissions))
 	var group *dbCluster.AuthGroupsRow
+	var oldConfig map[string]string
+	newConfig := map[string]string{}
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		group, err = dbCluster.GetAuthGroup(ctx, tx.Tx(), groupName)
 		if err != nil {
@@ -622,12 +651,22 @@ func patchAuthGroup(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		// Merge the given configuration keys into the existing configuration.
+		oldConfig = apiGroup.Config
+		maps.Copy(newConfig, oldConfig)
+		maps.Copy(newConfig, groupPut.Config)
+
 		return nil
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	err = validateAPILimitsConfig(newConfig)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	newDBPermissions, err := validatePermissions(r.Context(), s, newPermissions)
 	if err != nil {
 		return response.SmartError(err)
@@ -642,12 +681,24 @@ func patchAuthGroup(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		return dbCluster.SetAuthGroupPermissions(ctx, tx.Tx(), group.ID, newDBPermissions)
+		err = dbCluster.SetAuthGroupPermissions(ctx, tx.Tx(), group.ID, newDBPermissions)
+		if err != nil {
+			return err
+		}
+
+		return dbCluster.AuthGroupsConfigStore().Set(ctx, tx.Tx(), group.ID, newConfig)
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if apiLimitsConfigChanged(oldConfig, newConfig) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Send a lifecycle event for the group update
 	lc := lifecycle.AuthGroupUpdated.Event(groupName, request.CreateRequestor(r.Context()), nil)
 	s.Events.SendLifecycle("", lc)
@@ -703,12 +754,18 @@ func renameAuthGroup(d *Daemon, r *http.Request) response.Response {
 	}
 
 	s := d.State()
+	var config map[string]string
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		group, err := dbCluster.GetAuthGroup(ctx, tx.Tx(), groupName)
 		if err != nil {
 			return err
 		}
 
+		config, err = dbCluster.AuthGroupsConfigStore().GetByEntityID(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return err
+		}
+
 		group.Name = groupPost.Name
 		return query.UpdateByPrimaryKey(ctx, tx.Tx(), group)
 	})
@@ -716,6 +773,14 @@ func renameAuthGroup(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// The API limits of groups are looked up by name.
+	if apiLimitsConfigChanged(nil, config) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Send a lifecycle event for the group rename
 	lc := lifecycle.AuthGroupRenamed.Event(groupPost.Name, request.CreateRequestor(r.Context()), map[string]any{"old_name": groupName})
 	s.Events.SendLifecycle("", lc)
@@ -753,13 +818,31 @@ func deleteAuthGroup(d *Daemon, r *http.Request) response.Response {
 	}
 
 	s := d.State()
+	var config map[string]string
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
-		return query.DeleteOne[dbCluster.AuthGroupsRow](ctx, tx.Tx(), "WHERE name = ?", groupName)
+		group, err := dbCluster.GetAuthGroup(ctx, tx.Tx(), groupName)
+		if err != nil {
+			return err
+		}
+
+		config, err = dbCluster.AuthGroupsConfigStore().GetByEntityID(ctx, tx.Tx(), group.ID)
+		if err != nil {
+			return err
+		}
+
+		return query.DeleteByPrimaryKey(ctx, tx.Tx(), group)
 	})
 	if err != nil {
 		return response.SmartError(err)
 	}
 
+	if apiLimitsConfigChanged(config, nil) {
+		err = refreshAPILimits(s)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	// Send a lifecycle event for the group deletion
 	lc := lifecycle.AuthGroupDeleted.Event(groupName, request.CreateRequestor(r.Context()), nil)
 	s.Events.SendLifecycle("", lc)
@@ -1077,3 +1077,22 @@ func (cg *CGroup) GetIOStats() (map[string]*IOStats, error) {
 
 	return nil, ErrUnknownVersion
 }
+
+// GetPressure returns the content of the pressure stall information file of the given resource (cpu, memory or io).
+// Pressure stall information is only available with cgroup v2.
+func (cg *CGroup) GetPressure(resource string) (string, error) {
+	controller := resource
+	if resource == "io" {
+		controller = "blkio"
+	}
+
+	version := cgControllers[controller]
+	switch version {
+	case Unavailable, V1:
+		return "", ErrControllerMissing
+	case V2:
+		return cg.rw.Get(version, resource, resource+".pressure")
+	}
+
+	return "", ErrUnknownVersion
+}
@@ -190,6 +190,11 @@ func (c *Config) InstancesNICHostname() string {
 	return c.m.GetString("instances.nic.host_name")
 }
 
+// InstancesIdentityTokensIssuer returns the issuer URL of instance identity tokens, or an empty string when disabled.
+func (c *Config) InstancesIdentityTokensIssuer() string {
+	return strings.TrimSuffix(c.m.GetString("instances.identity_tokens.issuer"), "/")
+}
+
 // InstancesMigrationStateful returns the whether or not to auto enable migration.stateful for all VM instances.
 func (c *Config) InstancesMigrationStateful() bool {
 	return c.m.GetBool("instances.migration.stateful")
@@ -218,6 +223,16 @@ func (c *Config) ClusterJoinTokenExpiry() string {
 	return c.m.GetString("cluster.join_token_expiry")
 }
 
+// ClusterApprovalMemberRemove returns whether the removal of a cluster member requires approval.
+func (c *Config) ClusterApprovalMemberRemove() bool {
+	return c.m.GetBool("cluster.approval.member_remove")
+}
+
+// ClusterApprovalExpiry returns the time after which an operation pending approval on the server expires.
+func (c *Config) ClusterApprovalExpiry() string {
+	return c.m.GetString("cluster.approval.expiry")
+}
+
 // RemoteTokenExpiry returns the time after which a remote add token expires.
 func (c *Config) RemoteTokenExpiry() string {
 	return c.m.GetString("core.remote_token_expiry")
@@ -246,6 +261,11 @@ func (c *Config) OIDCDeviceClientID() string {
 	return c.m.GetString("oidc.device.client.id")
 }
 
+// AuditLogExpiry returns the expiry of the audit log entries.
+func (c *Config) AuditLogExpiry() string {
+	return c.m.GetString("core.audit_log_expiry")
+}
+
 // OIDCSessionExpiry returns the expiry of an OIDC session. This is separate from OIDCServer as it is passed into the
 // session manager via a function that gets the value as necessary, so we don't need to refresh the [oidc.Verifier] each
 // time this changes.
@@ -273,6 +293,17 @@ func (c *Config) ClusterHealingThreshold() time.Duration {
 	return healingThreshold
 }
 
+// ClusterRebalance returns the interval between two runs of the cluster rebalancer (0 if disabled), the minimum
+// load score difference in percent between the busiest and least busy members that triggers a move and the maximum
+// number of instances moved per run.
+func (c *Config) ClusterRebalance() (interval time.Duration, threshold int64, batch int64) {
+	interval = time.Duration(c.m.GetInt64("cluster.rebalance.interval")) * time.Minute
+	threshold = c.m.GetInt64("cluster.rebalance.threshold")
+	batch = c.m.GetInt64("cluster.rebalance.batch")
+
+	return interval, threshold, batch
+}
+
 // Dump current configuration keys and their values. Keys with values matching
 // their defaults are omitted.
 func (c *Config) Dump() map[string]string {
@@ -400,6 +431,26 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Number of cluster members that replicate an image
 		"cluster.images_minimal_replica": {Type: config.Int64, Default: "3", Validator: imageMinimalReplicaValidator},
 
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.approval.member_remove)
+		// When enabled, removing a cluster member creates an operation that must be approved by an identity with the
+		// `can_approve_operations` entitlement on the server before the member is removed.
+		// See {ref}`operation-approval`.
+		// ---
+		//  type: bool
+		//  scope: global
+		//  defaultdesc: `false`
+		//  shortdesc: Whether removing a cluster member requires approval
+		"cluster.approval.member_remove": {Type: config.Bool, Default: "false"},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.approval.expiry)
+		// Specify the time after which a cluster member removal that hasn't been approved fails.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `1d`
+		//  shortdesc: Time after which an unapproved cluster member removal expires
+		"cluster.approval.expiry": {Type: config.String, Default: "1d", Validator: expiryValidator},
+
 		// lxdmeta:generate(entities=server; group=cluster; key=cluster.healing_threshold)
 		// Specify the number of seconds after which an offline cluster member is to be evacuated.
 		// To disable evacuating offline members, set this option to `0`.
@@ -439,6 +490,36 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Number of database stand-by members
 		"cluster.max_standby": {Type: config.Int64, Default: "2", Validator: maxStandByValidator},
 
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.interval)
+		// Specify the number of minutes between two runs of the cluster rebalancer.
+		// To disable automatic rebalancing, set this option to `0`.
+		// See {ref}`cluster-rebalance` for more information.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `0`
+		//  shortdesc: Interval between two runs of the cluster rebalancer
+		"cluster.rebalance.interval": {Type: config.Int64, Default: "0", Validator: validate.Optional(validate.IsInRange(0, 10080))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.threshold)
+		// Specify the minimum difference, in percent, between the load scores of the busiest and the least busy
+		// cluster members for the rebalancer to move instances.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `20`
+		//  shortdesc: Load difference that triggers the cluster rebalancer
+		"cluster.rebalance.threshold": {Type: config.Int64, Default: "20", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
+		// lxdmeta:generate(entities=server; group=cluster; key=cluster.rebalance.batch)
+		// Specify the maximum number of instances that the cluster rebalancer moves in a single run.
+		// ---
+		//  type: integer
+		//  scope: global
+		//  defaultdesc: `1`
+		//  shortdesc: Maximum number of instances moved per rebalancer run
+		"cluster.rebalance.batch": {Type: config.Int64, Default: "1", Validator: validate.Optional(validate.IsInRange(1, 100))},
+
 		// lxdmeta:generate(entities=server; group=core; key=core.metrics_authentication)
 		//
 		// ---
@@ -551,6 +632,19 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Whether to automatically trust clients signed by the CA
 		"core.trust_ca_certificates": {Type: config.Bool, Default: "false"},
 
+		// lxdmeta:generate(entities=server; group=core; key=core.audit_log_expiry)
+		// Every mutating API request is recorded in the audit log, see {ref}`audit-log`.
+		// Entries older than the configured expiry are removed once a day.
+		//
+		// This configuration option accepts multiple space-separated values of the form `[0-9]+(S|M|H|d|w|m|y)`,
+		// where `S` is seconds, `M` is minutes, `H` is hours, `d` is days, `w` is weeks, `m` is months, and `y` is years.
+		// ---
+		//  type: string
+		//  scope: global
+		//  defaultdesc: `90d`
+		//  shortdesc: How long to keep audit log entries
+		"core.audit_log_expiry": {Type: config.String, Default: "90d", Validator: expiryValidator},
+
 		// lxdmeta:generate(entities=server; group=core; key=core.auth_secret_expiry)
 		// The secret is used for various cryptographic purposes, such as cookie encryption.
 		// When a given secret is older than the configured expiry, a new secret is generated.
@@ -645,6 +739,17 @@ var ConfigSchema = config.Schema{
 		//  shortdesc: Whether to set `migration.stateful` to `true` for the instances
 		"instances.migration.stateful": {Type: config.Bool, Default: "false"},
 
+		// lxdmeta:generate(entities=server; group=miscellaneous; key=instances.identity_tokens.issuer)
+		// Set this to the HTTPS URL at which relying parties can reach the LXD API, for example `https://lxd.example.net:8443`.
+		// It is used as the `iss` claim of the identity tokens that instances request through the `/dev/lxd` API,
+		// and as the base URL of the OpenID Connect discovery document and JSON Web Key Set that are used to verify them.
+		// Instance identity tokens are disabled if this option is not set.
+		// ---
+		//  type: string
+		//  scope: global
+		//  shortdesc: Issuer URL of instance identity tokens
+		"instances.identity_tokens.issuer": {Validator: validate.Optional(validate.IsHTTPSURL)},
+
 		// TODO: Remove after sunset period
 		// lxdmeta:generate(entities=server; group=miscellaneous; key=user.instances.placement.scriptlet)
 		// Stores the migrated value from the deprecated `instances.placement.scriptlet` configuration key. LXD ignores this key; changing it has no effect. It exists only to preserve previously stored data and may be removed in a future release.
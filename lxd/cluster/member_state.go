@@ -13,6 +13,7 @@ import (
 
 	"github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/state"
 	storagePools "github.com/canonical/lxd/lxd/storage"
 	"github.com/canonical/lxd/shared"
@@ -167,8 +168,21 @@ func MemberState(ctx context.Context, s *state.State) (*api.ClusterMemberState,
 	var pools map[int64]api.StoragePool
 	var poolMembers map[int64]map[int64]db.StoragePoolNode
 
+	var member db.NodeInfo
+	var committed map[string]placement.Reservations
+
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		pools, poolMembers, err = tx.GetStoragePools(ctx, &stateCreated)
+		if err != nil {
+			return err
+		}
+
+		member, err = tx.GetNodeByID(ctx, tx.GetNodeID())
+		if err != nil {
+			return err
+		}
+
+		committed, err = placement.GetCommittedReservations(ctx, tx, 0)
 
 		return err
 	})
@@ -176,6 +190,18 @@ func MemberState(ctx context.Context, s *state.State) (*api.ClusterMemberState,
 		return nil, fmt.Errorf("Failed loading storage pools: %w", err)
 	}
 
+	allocatable, err := placement.Allocatable(member.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	memberState.Reservations = api.ClusterMemberReservations{
+		CPUCommitted:      committed[member.Name].CPU,
+		CPUAllocatable:    allocatable.CPU,
+		MemoryCommitted:   committed[member.Name].Memory,
+		MemoryAllocatable: allocatable.Memory,
+	}
+
 	memberState.StoragePools = make(map[string]api.StoragePoolState, len(pools))
 
 	for poolID := range pools {
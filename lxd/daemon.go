@@ -61,6 +61,7 @@ import (
 	networkZone "github.com/canonical/lxd/lxd/network/zone"
 	"github.com/canonical/lxd/lxd/node"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/request/security"
 	"github.com/canonical/lxd/lxd/response"
@@ -157,6 +158,15 @@ type Daemon struct {
 
 	lokiClient *loki.Client
 
+	// Webhook event deliveries.
+	webhooks *webhookDispatcher
+
+	// Audit log.
+	auditLog *auditLog
+
+	// API limits of identities, authorization groups and projects.
+	apiLimits *apiLimits
+
 	// HTTP-01 challenge provider for ACME
 	http01Provider acme.HTTP01Provider
 
@@ -188,6 +198,7 @@ func newDaemon(config *DaemonConfig, os *sys.OS) *Daemon {
 
 	d := &Daemon{
 		identityCache:    &identity.Cache{},
+		apiLimits:        newAPILimits(),
 		config:           config,
 		tasks:            task.NewGroup(),
 		clusterTasks:     task.NewGroup(),
@@ -583,6 +594,19 @@ func (d *Daemon) Authenticate(w http.ResponseWriter, r *http.Request, oidcVerifi
 		}
 	}
 
+	// Only check SCIM bearer tokens if it is a SCIM API route.
+	if strings.HasPrefix(r.URL.Path, "/1.0/scim/") {
+		isSCIMRequest, token, subject := bearer.IsSCIMRequest(r, clusterUUID)
+		if isSCIMRequest {
+			scimRequestor, err := bearer.Authenticate(r.Context(), subject, token, auth.TokenLocationAuthorizationBearer, d.identityCache, d.events.SendSecurity)
+			if err != nil {
+				return nil, fmt.Errorf("Failed verifying SCIM bearer token: %w", err)
+			}
+
+			return scimRequestor, nil
+		}
+	}
+
 	// Check if the caller has a bearer token.
 	isBearerRequest, tokenLocation, token, subject := bearer.IsAPIRequest(r, clusterUUID)
 	if isBearerRequest {
@@ -925,6 +949,17 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, version string, c APIEndpoint
 		// Set OpenFGA cache in request context.
 		request.SetContextValue(r, request.CtxOpenFGARequestCache, &openfga.RequestCache{})
 
+		// Reject the requests of the main API exceeding the API limits.
+		if version == "1.0" {
+			releaseAPILimits, err := d.checkAPILimits(w, r, endpoint)
+			if err != nil {
+				_ = response.SmartError(err).Render(w, r)
+				return
+			}
+
+			defer releaseAPILimits()
+		}
+
 		// Dump full request JSON when in debug mode
 		if daemon.Debug && r.Method != "GET" && util.IsJSONRequest(r) {
 			newBody := &bytes.Buffer{}
@@ -940,6 +975,13 @@ func (d *Daemon) createCmd(restAPI *http.ServeMux, version string, c APIEndpoint
 			util.DebugJSON("API Request", captured, logger.AddContext(logCtx))
 		}
 
+		// Record mutating requests of the main API in the audit log.
+		if version == "1.0" {
+			var recordAudit func()
+			w, r, recordAudit = auditStart(d.State(), d.auditLog, w, r)
+			defer recordAudit()
+		}
+
 		// Actually process the request
 		var resp response.Response
 
@@ -1201,6 +1243,8 @@ func (d *Daemon) init() error {
 
 	// Setup internal event listener
 	d.internalListener = events.NewInternalListener(d.shutdownCtx, d.events)
+	d.webhooks = newWebhookDispatcher(d.State, d.internalListener)
+	d.auditLog = newAuditLog(d.State)
 
 	// Lets check if there's an existing LXD running
 	err = endpoints.CheckAlreadyRunning(d.os.GetUnixSocket())
@@ -1768,6 +1812,17 @@ func (d *Daemon) init() error {
 		d.globalConfig = config
 		d.globalConfigMu.Unlock()
 
+		// Record the capacity of the local member so that reservations can be checked by any member.
+		sysInfo, err := cluster.LocalSysInfo()
+		if err != nil {
+			return err
+		}
+
+		err = placement.RecordCapacity(ctx, tx, tx.GetNodeID(), sysInfo.LogicalCPUs, sysInfo.TotalRAM)
+		if err != nil {
+			return fmt.Errorf("Failed recording cluster member capacity: %w", err)
+		}
+
 		return nil
 	})
 	if err != nil {
@@ -2005,6 +2060,9 @@ func (d *Daemon) init() error {
 		// Remove expired images (daily)
 		d.taskPruneImages = d.tasks.Add(pruneExpiredImagesTask(d.State))
 
+		// Enforce project image retention policies (daily)
+		d.tasks.Add(imageRetentionTask(d.State))
+
 		// Auto-update images (every 6 hours, configurable)
 		d.tasks.Add(autoUpdateImagesTask(d.State))
 
@@ -2023,6 +2081,9 @@ func (d *Daemon) init() error {
 		// Remove resolved warnings (daily)
 		d.tasks.Add(pruneResolvedWarningsTask(d.State))
 
+		// Remove expired audit log entries (daily)
+		d.tasks.Add(expireAuditLogTask(d.State))
+
 		// Auto-renew server certificate (daily)
 		d.tasks.Add(autoRenewCertificateTask(d))
 
@@ -2031,6 +2092,20 @@ func (d *Daemon) init() error {
 
 		// Run scheduled replicators (minutely check of configurable cron expression)
 		d.tasks.Add(runScheduledReplicatorsTask(d.State))
+
+		// Check the resource pressure of instances against their thresholds (minutely)
+		d.tasks.Add(instancePressureCheckTask(d.State))
+
+		// Monitor the leaders of standby projects for automatic failover (every 10 seconds)
+		d.tasks.Add(replicatorFailoverTask(d.State))
+
+		// Deliver the queued events of webhooks (every 5 seconds)
+		d.webhooks.start(d.shutdownCtx)
+		d.tasks.Add(webhookDeliveryTask(d.webhooks))
+
+		// Prepare the images published over simplestreams (every 5 minutes, and when images or projects change)
+		taskSimplestreams := d.tasks.Add(imageSimplestreamsTask(d.State))
+		d.internalListener.AddHandler("simplestreams", imageSimplestreamsEventHandler(taskSimplestreams))
 	}
 
 	// Load Ubuntu Pro configuration before starting any instances.
@@ -2108,10 +2183,17 @@ func (d *Daemon) requestorHook(ctx context.Context, authenticationMethod string,
 				return fmt.Errorf("Failed reading OIDC identity metadata: %w", err)
 			}
 
-			if len(metadata.IdentityProviderGroups) > 0 {
+			// Identities that were deprovisioned via SCIM cannot be used.
+			if metadata.Disabled {
+				return api.NewStatusError(http.StatusForbidden, "Identity is disabled")
+			}
+
+			// Groups from the IdP claims and groups provisioned via SCIM are both mapped.
+			idpGroups := metadata.AllIdentityProviderGroups()
+			if len(idpGroups) > 0 {
 				// If IdP groups are set, map them to LXD auth groups.
-				res.IdentityProviderGroups = metadata.IdentityProviderGroups
-				res.EffectiveAuthGroups, err = dbCluster.GetDistinctAuthGroupNamesFromIDPGroupNames(ctx, tx.Tx(), metadata.IdentityProviderGroups)
+				res.IdentityProviderGroups = idpGroups
+				res.EffectiveAuthGroups, err = dbCluster.GetDistinctAuthGroupNamesFromIDPGroupNames(ctx, tx.Tx(), idpGroups)
 				if err != nil {
 					return fmt.Errorf("Failed mapping identity provider groups to authorization groups: %w", err)
 				}
@@ -2149,6 +2231,9 @@ func (d *Daemon) startClusterTasks() {
 	// Perform automatic evacuation for offline cluster members
 	d.clusterTasks.Add(autoHealClusterTask(d.State, d.gateway))
 
+	// Move instances off busy cluster members (configurable interval)
+	d.clusterTasks.Add(clusterRebalanceTask(d.State))
+
 	// Remove expired OIDC sessions
 	d.clusterTasks.Add(pruneExpiredOIDCSessionsTask(d.State))
 
@@ -2332,6 +2417,10 @@ func (d *Daemon) Stop(ctx context.Context, sig os.Signal) error {
 	n := d.numRunningInstances(instances)
 	shouldUnmount := instancesLoaded && n <= 0
 
+	if d.auditLog != nil && d.db.Cluster != nil {
+		trackError(d.auditLog.stop(10*time.Second), "Flush audit log") // Write the queued entries before closing the database.
+	}
+
 	if d.db.Cluster != nil {
 		logger.Info("Closing the database")
 		err := d.db.Cluster.Close()
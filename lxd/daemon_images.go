@@ -153,6 +153,11 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		return nil, err
 	}
 
+	policy, err := loadImageSignaturePolicy(ctx, s, args.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure we are the only ones operating on this image.
 	unlock, err := imageOperationLock(fp)
 	if err != nil {
@@ -194,6 +199,11 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		return err
 	})
 	if err == nil {
+		err = policy.check(imgInfo.Fingerprint, imgInfo.Signatures)
+		if err != nil {
+			return nil, err
+		}
+
 		var nodeAddress string
 
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -229,6 +239,12 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 			return err
 		})
 		if err == nil {
+			// The signatures apply to the image in all projects.
+			err = policy.check(imgInfo.Fingerprint, imgInfo.Signatures)
+			if err != nil {
+				return nil, err
+			}
+
 			var nodeAddress string
 			otherProject := imgInfo.Project
 
@@ -362,6 +378,17 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 		return nil, fmt.Errorf("Unsupported protocol: %v", protocol)
 	}
 
+	// Check the signatures provided by the remote server before downloading the image.
+	err = verifyImageSignatures(fp, info.Signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	err = policy.check(fp, info.Signatures)
+	if err != nil {
+		return nil, err
+	}
+
 	// Begin downloading
 	if op != nil {
 		l = l.AddContext(logger.Ctx{"trigger": op.URL(), "operation": op.ID()})
@@ -491,7 +518,12 @@ func ImageDownload(ctx context.Context, s *state.State, op *operations.Operation
 
 	err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 		// Create the database entry
-		return tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		err := tx.CreateImage(ctx, args.ProjectName, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, nil)
+		if err != nil {
+			return err
+		}
+
+		return cluster.CreateImageSignatures(ctx, tx.Tx(), info.Fingerprint, info.Signatures)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("Failed creating image record: %w", err)
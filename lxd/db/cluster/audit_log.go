@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// AuditLogRow represents a single row of the audit_log table.
+// db:model audit_log
+type AuditLogRow struct {
+	ID            int64     `db:"id"`
+	Date          time.Time `db:"date"`
+	Location      string    `db:"location"`
+	Identity      string    `db:"identity"`
+	Protocol      string    `db:"protocol"`
+	SourceAddress string    `db:"source_address"`
+	Method        string    `db:"method"`
+	Project       string    `db:"project"`
+	EntityURL     string    `db:"entity_url"`
+	RequestHash   string    `db:"request_hash"`
+	StatusCode    int       `db:"status_code"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (AuditLogRow) APIName() string {
+	return "Audit log entry"
+}
+
+// APIPluralName implements [query.APIPluralNamer] for API friendly error messages.
+func (AuditLogRow) APIPluralName() string {
+	return "Audit log entries"
+}
+
+// AuditLogFilter contains fields that can be used to filter results when getting audit log entries.
+type AuditLogFilter struct {
+	Since     *time.Time
+	Until     *time.Time
+	Identity  *string
+	Project   *string
+	EntityURL *string
+}
+
+// ToAPI converts the [AuditLogRow] to an [api.AuditEntry].
+func (a AuditLogRow) ToAPI() api.AuditEntry {
+	return api.AuditEntry{
+		Date:          a.Date,
+		Location:      a.Location,
+		Identity:      a.Identity,
+		Protocol:      a.Protocol,
+		SourceAddress: a.SourceAddress,
+		Method:        a.Method,
+		Project:       a.Project,
+		EntityURL:     a.EntityURL,
+		RequestHash:   a.RequestHash,
+		StatusCode:    a.StatusCode,
+	}
+}
+
+// CreateAuditLogEntry adds an entry to the audit log.
+func CreateAuditLogEntry(ctx context.Context, tx *sql.Tx, entry AuditLogRow) error {
+	_, err := query.Create(ctx, tx, entry)
+	return err
+}
+
+// GetAuditLogEntries returns the audit log entries matching the given filter, oldest first.
+// The entity URL filter matches the given URL, with or without query parameters, and the URLs of the entities below it.
+func GetAuditLogEntries(ctx context.Context, tx *sql.Tx, filter AuditLogFilter) ([]AuditLogRow, error) {
+	var clauses []string
+	var args []any
+
+	if filter.Since != nil {
+		clauses = append(clauses, "audit_log.date >= ?")
+		args = append(args, *filter.Since)
+	}
+
+	if filter.Until != nil {
+		clauses = append(clauses, "audit_log.date <= ?")
+		args = append(args, *filter.Until)
+	}
+
+	if filter.Identity != nil {
+		clauses = append(clauses, "audit_log.identity = ?")
+		args = append(args, *filter.Identity)
+	}
+
+	if filter.Project != nil {
+		clauses = append(clauses, "audit_log.project = ?")
+		args = append(args, *filter.Project)
+	}
+
+	if filter.EntityURL != nil {
+		pattern := escapeLike(*filter.EntityURL)
+		clauses = append(clauses, "(audit_log.entity_url = ? OR audit_log.entity_url LIKE ? ESCAPE '\\' OR audit_log.entity_url LIKE ? ESCAPE '\\')")
+		args = append(args, *filter.EntityURL, pattern+"?%", pattern+"/%")
+	}
+
+	var b strings.Builder
+	if len(clauses) > 0 {
+		b.WriteString("WHERE ")
+		b.WriteString(strings.Join(clauses, " AND "))
+		b.WriteString(" ")
+	}
+
+	b.WriteString("ORDER BY audit_log.date, audit_log.id")
+
+	return query.Select[AuditLogRow](ctx, tx, b.String(), args...)
+}
+
+// DeleteAuditLogEntriesBefore deletes the audit log entries older than the given date.
+func DeleteAuditLogEntriesBefore(ctx context.Context, tx *sql.Tx, date time.Time) (int64, error) {
+	return query.DeleteMany[AuditLogRow](ctx, tx, "WHERE date < ?", date)
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
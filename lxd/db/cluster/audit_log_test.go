@@ -0,0 +1,63 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().UTC()
+	entries := []AuditLogRow{
+		{Date: now.Add(-48 * time.Hour), Identity: "alice", Project: "default", Method: "POST", EntityURL: "/1.0/instances"},
+		{Date: now.Add(-time.Hour), Identity: "bob", Project: "foo", Method: "PUT", EntityURL: "/1.0/instances/c1?project=foo"},
+		{Date: now, Identity: "alice", Project: "foo", Method: "DELETE", EntityURL: "/1.0/instances/c1/snapshots/snap0?project=foo"},
+		{Date: now, Identity: "alice", Project: "default", Method: "DELETE", EntityURL: "/1.0/instances/c10"},
+	}
+
+	for _, entry := range entries {
+		err = CreateAuditLogEntry(ctx, tx, entry)
+		require.NoError(t, err)
+	}
+
+	// No filter returns all the entries, oldest first.
+	rows, err := GetAuditLogEntries(ctx, tx, AuditLogFilter{})
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	require.Equal(t, "POST", rows[0].Method)
+
+	// Filter by identity and date.
+	identity := "alice"
+	since := now.Add(-2 * time.Hour)
+	rows, err = GetAuditLogEntries(ctx, tx, AuditLogFilter{Identity: &identity, Since: &since})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	// Filter by entity matches the entity and the entities below it.
+	entityURL := "/1.0/instances/c1"
+	rows, err = GetAuditLogEntries(ctx, tx, AuditLogFilter{EntityURL: &entityURL})
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	require.Equal(t, "bob", rows[0].Identity)
+
+	// Old entries are pruned.
+	deleted, err := DeleteAuditLogEntriesBefore(ctx, tx, now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	project := "default"
+	rows, err = GetAuditLogEntries(ctx, tx, AuditLogFilter{Project: &project})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, "/1.0/instances/c10", rows[0].EntityURL)
+}
@@ -110,6 +110,11 @@ func (g *AuthGroupsRow) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity a
 
 	group.Permissions = apiPermissions
 
+	group.Config, err = AuthGroupsConfigStore().GetByEntityID(ctx, tx, g.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	identities, err := GetIdentitiesByAuthGroupID(ctx, tx, g.ID)
 	if err != nil {
 		return nil, err
@@ -137,6 +142,15 @@ func (g *AuthGroupsRow) ToAPI(ctx context.Context, tx *sql.Tx, canViewIdentity a
 	return group, nil
 }
 
+// AuthGroupsConfigStore returns a [query.EntityConfigStore] for authorization groups.
+func AuthGroupsConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "auth_groups",
+		ConfigTable:               "auth_groups_config",
+		ConfigTableEntityIDColumn: "auth_group_id",
+	}
+}
+
 // GetIdentitiesByAuthGroupID returns the identities that are members of the group with the given ID.
 func GetIdentitiesByAuthGroupID(ctx context.Context, tx *sql.Tx, groupID int64) ([]IdentitiesRow, error) {
 	stmt := `
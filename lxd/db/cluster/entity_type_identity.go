@@ -15,10 +15,11 @@ type entityTypeIdentity struct {
 	entityTypeCommon
 }
 
-// identityTypes returns the list of identity type codes that are considered fine-grained.
+// identityTypes returns the list of identity type codes that are considered fine-grained, or that are otherwise
+// managed via the identities API.
 func (e entityTypeIdentity) identityTypes() (types []int64) {
 	for _, t := range identity.Types() {
-		if t.IsFineGrained() || t.Name() == api.IdentityTypeBearerTokenInitialUI {
+		if t.IsFineGrained() || t.Name() == api.IdentityTypeBearerTokenInitialUI || t.Name() == api.IdentityTypeBearerTokenSCIM {
 			types = append(types, t.Code())
 		}
 	}
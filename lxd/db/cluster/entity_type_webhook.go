@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/canonical/lxd/lxd/db/query"
+)
+
+// entityTypeWebhook implements entityTypeDBInfo for a [WebhookRow].
+type entityTypeWebhook struct {
+	entityTypeCommon
+}
+
+func (e entityTypeWebhook) code() int64 {
+	return entityTypeCodeWebhook
+}
+
+func (e entityTypeWebhook) allURLsQuery() string {
+	return fmt.Sprintf(`SELECT %d, webhooks.id, '', '', json_array(webhooks.name) FROM webhooks`, e.code())
+}
+
+func (e entityTypeWebhook) urlsByProjectQuery() string {
+	return ""
+}
+
+func (e entityTypeWebhook) urlsByIDsQuery(ids ...int64) string {
+	return e.allURLsQuery() + " WHERE webhooks.id IN " + query.IntParams(ids...)
+}
+
+func (e entityTypeWebhook) idFromURLQuery() string {
+	return `
+SELECT ?, webhooks.id
+FROM webhooks
+WHERE '' = ?
+	AND '' = ?
+	AND webhooks.name = ?`
+}
+
+func (e entityTypeWebhook) onDeleteTriggerSQL() (name string, sql string) {
+	return standardOnDeleteTriggerSQL("on_webhook_delete", "webhooks", e.code())
+}
@@ -4,6 +4,71 @@ package cluster
 
 // Generated by dbgen - DO NOT EDIT
 
+// TableName returns the table name for [AuditLogRow] entities.
+func (a AuditLogRow) TableName() string {
+	return "audit_log"
+}
+
+// SelectColumns returns a slice of column names for [AuditLogRow] entities.
+func (a AuditLogRow) SelectColumns() []string {
+	return []string{
+		"audit_log.id",
+		"audit_log.date",
+		"audit_log.location",
+		"audit_log.identity",
+		"audit_log.protocol",
+		"audit_log.source_address",
+		"audit_log.method",
+		"audit_log.project",
+		"audit_log.entity_url",
+		"audit_log.request_hash",
+		"audit_log.status_code",
+	}
+}
+
+// Joins returns a slice of join expressions for [AuditLogRow].
+func (a AuditLogRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [AuditLogRow].
+// This returns references to struct fields in definition order.
+func (a *AuditLogRow) ScanArgs() []any {
+	return []any{&a.ID, &a.Date, &a.Location, &a.Identity, &a.Protocol, &a.SourceAddress, &a.Method, &a.Project, &a.EntityURL, &a.RequestHash, &a.StatusCode}
+}
+
+// CreateValues returns a list of values from [AuditLogRow] entities matching the bind arguments in [CreateStmt].
+func (a AuditLogRow) CreateValues() []any {
+	return []any{a.Date, a.Location, a.Identity, a.Protocol, a.SourceAddress, a.Method, a.Project, a.EntityURL, a.RequestHash, a.StatusCode}
+}
+
+// UpdateValues returns a list of values from [AuditLogRow] entities matching the columns in [UpdateStmt].
+func (a AuditLogRow) UpdateValues() []any {
+	return []any{a.Date, a.Location, a.Identity, a.Protocol, a.SourceAddress, a.Method, a.Project, a.EntityURL, a.RequestHash, a.StatusCode}
+}
+
+// PKColumns returns the column names for the primary key of a [AuditLogRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (a AuditLogRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [AuditLogRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (a AuditLogRow) PKValues() []any {
+	return []any{a.ID}
+}
+
+// CreateStmt returns a query that creates a [AuditLogRow] entity.
+func (a AuditLogRow) CreateStmt() string {
+	return "INSERT INTO audit_log (date, location, identity, protocol, source_address, method, project, entity_url, request_hash, status_code) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [AuditLogRow] by primary key.
+func (a AuditLogRow) UpdateStmt() string {
+	return "UPDATE audit_log SET date = ?, location = ?, identity = ?, protocol = ?, source_address = ?, method = ?, project = ?, entity_url = ?, request_hash = ?, status_code = ? "
+}
+
 // TableName returns the table name for [AuthGroupsRow] entities.
 func (a AuthGroupsRow) TableName() string {
 	return "auth_groups"
@@ -1032,3 +1097,225 @@ func (r ReplicatorRow) CreateStmt() string {
 func (r ReplicatorRow) UpdateStmt() string {
 	return "UPDATE replicators SET name = ?, project_id = ?, description = ?, last_run_date = ?, last_run_status = ? "
 }
+
+// TableName returns the table name for [WebhookDelivery] entities.
+func (w WebhookDelivery) TableName() string {
+	return "webhooks_deliveries"
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (w WebhookDelivery) APIName() string {
+	return w.Row.APIName()
+}
+
+// SelectColumns returns a slice of column names for [WebhookDelivery] entities.
+func (w WebhookDelivery) SelectColumns() []string {
+	return []string{
+		"webhooks_deliveries.id",
+		"webhooks_deliveries.uuid",
+		"webhooks_deliveries.webhook_id",
+		"webhooks_deliveries.node_id",
+		"webhooks_deliveries.event_type",
+		"webhooks_deliveries.event",
+		"webhooks_deliveries.status",
+		"webhooks_deliveries.attempts",
+		"webhooks_deliveries.creation_date",
+		"webhooks_deliveries.next_attempt_date",
+		"webhooks_deliveries.last_error",
+		"nodes.name",
+	}
+}
+
+// Joins returns a slice of join expressions for [WebhookDelivery].
+func (w WebhookDelivery) Joins() []string {
+	return []string{
+		"JOIN nodes ON webhooks_deliveries.node_id = nodes.id",
+	}
+}
+
+// ScanArgs implements [query.ScanArger] for [WebhookDelivery].
+// This returns references to struct fields in definition order.
+func (w *WebhookDelivery) ScanArgs() []any {
+	return []any{&w.Row.ID, &w.Row.UUID, &w.Row.WebhookID, &w.Row.NodeID, &w.Row.EventType, &w.Row.Event, &w.Row.Status, &w.Row.Attempts, &w.Row.CreationDate, &w.Row.NextAttemptDate, &w.Row.LastError, &w.Location}
+}
+
+// TableName returns the table name for [WebhookDeliveryRow] entities.
+func (w WebhookDeliveryRow) TableName() string {
+	return "webhooks_deliveries"
+}
+
+// SelectColumns returns a slice of column names for [WebhookDeliveryRow] entities.
+func (w WebhookDeliveryRow) SelectColumns() []string {
+	return []string{
+		"webhooks_deliveries.id",
+		"webhooks_deliveries.uuid",
+		"webhooks_deliveries.webhook_id",
+		"webhooks_deliveries.node_id",
+		"webhooks_deliveries.event_type",
+		"webhooks_deliveries.event",
+		"webhooks_deliveries.status",
+		"webhooks_deliveries.attempts",
+		"webhooks_deliveries.creation_date",
+		"webhooks_deliveries.next_attempt_date",
+		"webhooks_deliveries.last_error",
+	}
+}
+
+// Joins returns a slice of join expressions for [WebhookDeliveryRow].
+func (w WebhookDeliveryRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [WebhookDeliveryRow].
+// This returns references to struct fields in definition order.
+func (w *WebhookDeliveryRow) ScanArgs() []any {
+	return []any{&w.ID, &w.UUID, &w.WebhookID, &w.NodeID, &w.EventType, &w.Event, &w.Status, &w.Attempts, &w.CreationDate, &w.NextAttemptDate, &w.LastError}
+}
+
+// CreateValues returns a list of values from [WebhookDeliveryRow] entities matching the bind arguments in [CreateStmt].
+func (w WebhookDeliveryRow) CreateValues() []any {
+	return []any{w.UUID, w.WebhookID, w.NodeID, w.EventType, w.Event, w.Status, w.Attempts, w.CreationDate, w.NextAttemptDate, w.LastError}
+}
+
+// UpdateValues returns a list of values from [WebhookDeliveryRow] entities matching the columns in [UpdateStmt].
+func (w WebhookDeliveryRow) UpdateValues() []any {
+	return []any{w.UUID, w.WebhookID, w.NodeID, w.EventType, w.Event, w.Status, w.Attempts, w.CreationDate, w.NextAttemptDate, w.LastError}
+}
+
+// PKColumns returns the column names for the primary key of a [WebhookDeliveryRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (w WebhookDeliveryRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [WebhookDeliveryRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (w WebhookDeliveryRow) PKValues() []any {
+	return []any{w.ID}
+}
+
+// CreateStmt returns a query that creates a [WebhookDeliveryRow] entity.
+func (w WebhookDeliveryRow) CreateStmt() string {
+	return "INSERT INTO webhooks_deliveries (uuid, webhook_id, node_id, event_type, event, status, attempts, creation_date, next_attempt_date, last_error) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [WebhookDeliveryRow] by primary key.
+func (w WebhookDeliveryRow) UpdateStmt() string {
+	return "UPDATE webhooks_deliveries SET uuid = ?, webhook_id = ?, node_id = ?, event_type = ?, event = ?, status = ?, attempts = ?, creation_date = ?, next_attempt_date = ?, last_error = ? "
+}
+
+// TableName returns the table name for [WebhookRow] entities.
+func (w WebhookRow) TableName() string {
+	return "webhooks"
+}
+
+// SelectColumns returns a slice of column names for [WebhookRow] entities.
+func (w WebhookRow) SelectColumns() []string {
+	return []string{
+		"webhooks.id",
+		"webhooks.name",
+		"webhooks.description",
+	}
+}
+
+// Joins returns a slice of join expressions for [WebhookRow].
+func (w WebhookRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [WebhookRow].
+// This returns references to struct fields in definition order.
+func (w *WebhookRow) ScanArgs() []any {
+	return []any{&w.ID, &w.Name, &w.Description}
+}
+
+// CreateValues returns a list of values from [WebhookRow] entities matching the bind arguments in [CreateStmt].
+func (w WebhookRow) CreateValues() []any {
+	return []any{w.Name, w.Description}
+}
+
+// UpdateValues returns a list of values from [WebhookRow] entities matching the columns in [UpdateStmt].
+func (w WebhookRow) UpdateValues() []any {
+	return []any{w.Name, w.Description}
+}
+
+// PKColumns returns the column names for the primary key of a [WebhookRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (w WebhookRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [WebhookRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (w WebhookRow) PKValues() []any {
+	return []any{w.ID}
+}
+
+// CreateStmt returns a query that creates a [WebhookRow] entity.
+func (w WebhookRow) CreateStmt() string {
+	return "INSERT INTO webhooks (name, description) VALUES (?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [WebhookRow] by primary key.
+func (w WebhookRow) UpdateStmt() string {
+	return "UPDATE webhooks SET name = ?, description = ? "
+}
+
+// TableName returns the table name for [WebhookStatusRow] entities.
+func (w WebhookStatusRow) TableName() string {
+	return "webhooks_status"
+}
+
+// SelectColumns returns a slice of column names for [WebhookStatusRow] entities.
+func (w WebhookStatusRow) SelectColumns() []string {
+	return []string{
+		"webhooks_status.id",
+		"webhooks_status.webhook_id",
+		"webhooks_status.last_delivery_date",
+		"webhooks_status.last_failure_date",
+		"webhooks_status.last_error",
+	}
+}
+
+// Joins returns a slice of join expressions for [WebhookStatusRow].
+func (w WebhookStatusRow) Joins() []string {
+	return []string{}
+}
+
+// ScanArgs implements [query.ScanArger] for [WebhookStatusRow].
+// This returns references to struct fields in definition order.
+func (w *WebhookStatusRow) ScanArgs() []any {
+	return []any{&w.ID, &w.WebhookID, &w.LastDeliveryDate, &w.LastFailureDate, &w.LastError}
+}
+
+// CreateValues returns a list of values from [WebhookStatusRow] entities matching the bind arguments in [CreateStmt].
+func (w WebhookStatusRow) CreateValues() []any {
+	return []any{w.WebhookID, w.LastDeliveryDate, w.LastFailureDate, w.LastError}
+}
+
+// UpdateValues returns a list of values from [WebhookStatusRow] entities matching the columns in [UpdateStmt].
+func (w WebhookStatusRow) UpdateValues() []any {
+	return []any{w.WebhookID, w.LastDeliveryDate, w.LastFailureDate, w.LastError}
+}
+
+// PKColumns returns the column names for the primary key of a [WebhookStatusRow] entity used during an update.
+// The returned slice must have the same number of elements as PKValues.
+func (w WebhookStatusRow) PKColumns() []string {
+	return []string{"id"}
+}
+
+// PKValues returns the values for the primary key of a [WebhookStatusRow] entity used during an update.
+// The returned slice must have the same number of elements as PKColumns.
+func (w WebhookStatusRow) PKValues() []any {
+	return []any{w.ID}
+}
+
+// CreateStmt returns a query that creates a [WebhookStatusRow] entity.
+func (w WebhookStatusRow) CreateStmt() string {
+	return "INSERT INTO webhooks_status (webhook_id, last_delivery_date, last_failure_date, last_error) VALUES (?, ?, ?, ?)"
+}
+
+// UpdateStmt returns a query that updates a [WebhookStatusRow] by primary key.
+func (w WebhookStatusRow) UpdateStmt() string {
+	return "UPDATE webhooks_status SET webhook_id = ?, last_delivery_date = ?, last_failure_date = ?, last_error = ? "
+}
@@ -210,17 +210,50 @@ func (i IdentitiesRow) ToCertificate(idToCert map[int64][]string) (*CertificateL
 type OIDCMetadata struct {
 	Subject                string   `json:"subject"`
 	IdentityProviderGroups []string `json:"identity_provider_groups"`
+
+	// SCIMExternalID is the identifier of the identity in the SCIM client (the identity provider).
+	// It is not necessarily equal to the subject, which is set on first login.
+	SCIMExternalID string `json:"scim_external_id,omitempty"`
+
+	// SCIMGroups are the identity provider groups that the identity was added to via SCIM.
+	// Unlike IdentityProviderGroups, these are not overwritten when the identity logs in.
+	SCIMGroups []string `json:"scim_groups,omitempty"`
+
+	// Disabled is set when the identity was deactivated via SCIM. Disabled identities cannot authenticate.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Deprovisioned is set when the identity was deleted via SCIM. The identity is kept disabled, so that it isn't
+	// created again on the next OIDC login, but it is no longer served by the SCIM API.
+	Deprovisioned bool `json:"deprovisioned,omitempty"`
 }
 
 // Equals returns true if the given [OIDCMetadata] is equal to the receiver.
 func (o OIDCMetadata) Equals(m OIDCMetadata) bool {
-	if o.Subject != m.Subject {
+	if o.Subject != m.Subject || o.SCIMExternalID != m.SCIMExternalID || o.Disabled != m.Disabled || o.Deprovisioned != m.Deprovisioned {
 		return false
 	}
 
 	slices.Sort(o.IdentityProviderGroups)
 	slices.Sort(m.IdentityProviderGroups)
-	return slices.Equal(o.IdentityProviderGroups, m.IdentityProviderGroups)
+	if !slices.Equal(o.IdentityProviderGroups, m.IdentityProviderGroups) {
+		return false
+	}
+
+	slices.Sort(o.SCIMGroups)
+	slices.Sort(m.SCIMGroups)
+	return slices.Equal(o.SCIMGroups, m.SCIMGroups)
+}
+
+// AllIdentityProviderGroups returns the distinct union of the identity provider groups from the IdP claims and the
+// groups provisioned via SCIM.
+func (o OIDCMetadata) AllIdentityProviderGroups() []string {
+	if len(o.SCIMGroups) == 0 {
+		return o.IdentityProviderGroups
+	}
+
+	groups := slices.Concat(o.IdentityProviderGroups, o.SCIMGroups)
+	slices.Sort(groups)
+	return slices.Compact(groups)
 }
 
 // OIDCMetadata returns the identity metadata as [OIDCMetadata]. The [AuthMethod] of the [IdentitiesRow] must be [api.AuthenticationMethodOIDC].
@@ -327,7 +360,7 @@ WHERE id = ? AND auth_method = ?`
 }
 
 // ToAPI converts an [IdentitiesRow] to an [api.Identity], executing database queries as necessary.
-func (i *IdentitiesRow) ToAPI(idToGroups map[int64][]string, idToCertificates map[int64][]string) (*api.Identity, error) {
+func (i *IdentitiesRow) ToAPI(idToGroups map[int64][]string, idToCertificates map[int64][]string, idToConfig map[int64]map[string]string) (*api.Identity, error) {
 	if idToGroups == nil {
 		return nil, errors.New("Missing required authorization group data")
 	}
@@ -378,6 +411,11 @@ func (i *IdentitiesRow) ToAPI(idToGroups map[int64][]string, idToCertificates ma
 		groups = []string{}
 	}
 
+	config := idToConfig[i.ID]
+	if config == nil {
+		config = map[string]string{}
+	}
+
 	return &api.Identity{
 		AuthenticationMethod: string(i.AuthMethod),
 		Type:                 string(i.Type),
@@ -386,9 +424,19 @@ func (i *IdentitiesRow) ToAPI(idToGroups map[int64][]string, idToCertificates ma
 		Groups:               groups,
 		TLSCertificate:       tlsCertificate,
 		ExpiresAt:            expiresAt,
+		Config:               config,
 	}, nil
 }
 
+// IdentitiesConfigStore returns a [query.EntityConfigStore] for identities.
+func IdentitiesConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "identities",
+		ConfigTable:               "identities_config",
+		ConfigTableEntityIDColumn: "identity_id",
+	}
+}
+
 // GetIdentityByAuthenticationMethodAndIdentifier gets a single identity by authentication method and identifier.
 func GetIdentityByAuthenticationMethodAndIdentifier(ctx context.Context, tx *sql.Tx, authenticationMethod string, identifier string) (*IdentitiesRow, error) {
 	return query.SelectOne[IdentitiesRow](ctx, tx, "WHERE auth_method = ? AND identifier = ?", AuthMethod(authenticationMethod), identifier)
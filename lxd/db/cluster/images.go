@@ -104,6 +104,12 @@ func (img *Image) ToAPI(ctx context.Context, tx *sql.Tx, profileProject string)
 		}
 	}
 
+	// Add signatures.
+	image.Signatures, err = GetImageSignatures(ctx, tx, img.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
 	// Add source info.
 	_, source, err := GetImageSource(ctx, tx, img.ID)
 	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
@@ -152,6 +158,106 @@ func (img *Image) ToAPI(ctx context.Context, tx *sql.Tx, profileProject string)
 	return &image, nil
 }
 
+// GetImageSignatures returns the signatures of the image with the given fingerprint.
+func GetImageSignatures(ctx context.Context, tx *sql.Tx, fingerprint string) ([]api.ImageSignature, error) {
+	signatures := make([]api.ImageSignature, 0)
+
+	q := "SELECT key_fingerprint, public_key, signature FROM images_signatures WHERE fingerprint=? ORDER BY id"
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		signature := api.ImageSignature{}
+
+		err := scan(&signature.KeyFingerprint, &signature.PublicKey, &signature.Signature)
+		if err != nil {
+			return err
+		}
+
+		signatures = append(signatures, signature)
+		return nil
+	}, fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading image signatures: %w", err)
+	}
+
+	return signatures, nil
+}
+
+// CreateImageSignatures adds signatures to the image with the given fingerprint.
+// An existing signature made with the same key is replaced.
+func CreateImageSignatures(ctx context.Context, tx *sql.Tx, fingerprint string, signatures []api.ImageSignature) error {
+	q := `
+INSERT INTO images_signatures (fingerprint, key_fingerprint, public_key, signature) VALUES (?, ?, ?, ?)
+  ON CONFLICT (fingerprint, key_fingerprint) DO UPDATE SET public_key=excluded.public_key, signature=excluded.signature
+`
+	for _, signature := range signatures {
+		_, err := tx.ExecContext(ctx, q, fingerprint, signature.KeyFingerprint, signature.PublicKey, signature.Signature)
+		if err != nil {
+			return fmt.Errorf("Failed adding image signature: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetImageAliasHistory returns the names of the current and former aliases of the images of the given project, keyed
+// by image ID.
+func GetImageAliasHistory(ctx context.Context, tx *sql.Tx, projectName string) (map[int][]string, error) {
+	history := map[int][]string{}
+
+	q := `
+SELECT images_aliases_history.image_id, images_aliases_history.name
+  FROM images_aliases_history
+  JOIN images ON images.id = images_aliases_history.image_id
+  JOIN projects ON projects.id = images.project_id
+ WHERE projects.name = ?
+ ORDER BY images_aliases_history.id
+`
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		var imageID int
+		var name string
+
+		err := scan(&imageID, &name)
+		if err != nil {
+			return err
+		}
+
+		history[imageID] = append(history[imageID], name)
+		return nil
+	}, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading image alias history: %w", err)
+	}
+
+	return history, nil
+}
+
+// GetImagesUsedByInstances returns the fingerprints of the images that instances and instance snapshots of all
+// projects were created from.
+func GetImagesUsedByInstances(ctx context.Context, tx *sql.Tx) (map[string]bool, error) {
+	fingerprints := map[string]bool{}
+
+	q := `
+SELECT value FROM instances_config WHERE key = 'volatile.base_image'
+UNION
+SELECT value FROM instances_snapshots_config WHERE key = 'volatile.base_image'
+`
+	err := query.Scan(ctx, tx, q, func(scan func(dest ...any) error) error {
+		var fingerprint string
+
+		err := scan(&fingerprint)
+		if err != nil {
+			return err
+		}
+
+		fingerprints[fingerprint] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading images used by instances: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
 // ImageFilter can be used to filter results yielded by GetImages.
 type ImageFilter struct {
 	ID          *int
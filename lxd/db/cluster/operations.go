@@ -420,3 +420,25 @@ func CountOperationChildren(ctx context.Context, tx *sql.Tx, parentID int64) (in
 
 	return count, nil
 }
+
+// CountUnfinishedOperations returns the number of operations of all cluster members that are pending, running or being
+// cancelled, and that were requested by the identity with the given ID or that belong to the project with the given
+// name. Child operations of bulk operations aren't counted, as they belong to their parent operation.
+func CountUnfinishedOperations(ctx context.Context, tx *sql.Tx, identityID int64, projectName string) (identityOps int, projectOps int, err error) {
+	stmt := `
+SELECT
+	COUNT(CASE WHEN operations.requestor_identity_id = ? THEN 1 END),
+	COUNT(CASE WHEN projects.name = ? THEN 1 END)
+FROM operations
+LEFT JOIN projects ON operations.project_id = projects.id
+WHERE operations.parent IS NULL AND operations.status_code IN (?, ?, ?)`
+
+	err = query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		return scan(&identityOps, &projectOps)
+	}, identityID, projectName, api.Pending, api.Running, api.Cancelling)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Failed counting unfinished operations: %w", err)
+	}
+
+	return identityOps, projectOps, nil
+}
@@ -217,6 +217,37 @@ func GetProjectConfig(ctx context.Context, tx *sql.Tx, projectName string) (map[
 	return result, nil
 }
 
+// GetProjectsConfigByKeyPrefix returns the configuration keys starting with the given prefix of all projects, keyed by
+// project name. Projects without any such key are omitted.
+func GetProjectsConfigByKeyPrefix(ctx context.Context, tx *sql.Tx, prefix string) (map[string]map[string]string, error) {
+	stmt := `
+SELECT projects.name, projects_config.key, projects_config.value FROM projects_config
+JOIN projects ON projects.id = projects_config.project_id
+WHERE projects_config.key LIKE ? ESCAPE '\'
+`
+
+	result := make(map[string]map[string]string)
+	err := query.Scan(ctx, tx, stmt, func(scan func(dest ...any) error) error {
+		var name, key, value string
+		err := scan(&name, &key, &value)
+		if err != nil {
+			return err
+		}
+
+		if result[name] == nil {
+			result[name] = make(map[string]string)
+		}
+
+		result[name][key] = value
+		return nil
+	}, escapeLike(prefix)+"%")
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading project configuration: %w", err)
+	}
+
+	return result, nil
+}
+
 // GetProjectNames returns the names of all available projects.
 func GetProjectNames(ctx context.Context, tx *sql.Tx) ([]string, error) {
 	stmt := "SELECT name FROM projects"
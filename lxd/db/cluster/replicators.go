@@ -152,3 +152,58 @@ func UpdateReplicatorLastRunStatus(ctx context.Context, tx *sql.Tx, id int64, st
 	_, err := tx.ExecContext(ctx, `UPDATE replicators SET last_run_status=? WHERE id=?`, status, id)
 	return err
 }
+
+// ReplicatorEntity represents the outcome of the last run of a replicator for a dependent entity.
+type ReplicatorEntity struct {
+	EntityURL string
+	Status    string
+	Message   string
+}
+
+// ToAPI converts the [ReplicatorEntity] to an [api.ReplicatorStateEntity].
+func (e ReplicatorEntity) ToAPI() api.ReplicatorStateEntity {
+	return api.ReplicatorStateEntity{
+		EntityURL: e.EntityURL,
+		Status:    e.Status,
+		Message:   e.Message,
+	}
+}
+
+// GetReplicatorEntities returns the outcome of the last run of the replicator with the given ID for its dependent
+// entities.
+func GetReplicatorEntities(ctx context.Context, tx *sql.Tx, id int64) ([]ReplicatorEntity, error) {
+	entities := []ReplicatorEntity{}
+	err := query.Scan(ctx, tx, `SELECT entity_url, status, message FROM replicators_entities WHERE replicator_id=? ORDER BY id`, func(scan func(dest ...any) error) error {
+		var replicatorEntity ReplicatorEntity
+		err := scan(&replicatorEntity.EntityURL, &replicatorEntity.Status, &replicatorEntity.Message)
+		if err != nil {
+			return err
+		}
+
+		entities = append(entities, replicatorEntity)
+		return nil
+	}, id)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading replicator entities: %w", err)
+	}
+
+	return entities, nil
+}
+
+// ReplaceReplicatorEntities replaces the outcome of the last run of the replicator with the given ID for its
+// dependent entities.
+func ReplaceReplicatorEntities(ctx context.Context, tx *sql.Tx, id int64, entities []ReplicatorEntity) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM replicators_entities WHERE replicator_id=?`, id)
+	if err != nil {
+		return err
+	}
+
+	for _, replicatorEntity := range entities {
+		_, err = tx.ExecContext(ctx, `INSERT INTO replicators_entities (replicator_id, entity_url, status, message) VALUES (?, ?, ?, ?)`, id, replicatorEntity.EntityURL, replicatorEntity.Status, replicatorEntity.Message)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
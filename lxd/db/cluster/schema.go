@@ -6,12 +6,34 @@ package cluster
 // modify the database schema, please add a new schema update to update.go
 // and the run 'make update-schema'.
 const freshSchema = `
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	date DATETIME NOT NULL,
+	location TEXT NOT NULL,
+	identity TEXT NOT NULL,
+	protocol TEXT NOT NULL,
+	source_address TEXT NOT NULL,
+	method TEXT NOT NULL,
+	project TEXT NOT NULL,
+	entity_url TEXT NOT NULL,
+	request_hash TEXT NOT NULL,
+	status_code INTEGER NOT NULL
+);
+CREATE INDEX audit_log_date_idx ON audit_log (date);
 CREATE TABLE auth_groups (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     name TEXT NOT NULL,
     description TEXT NOT NULL,
     UNIQUE (name)
 );
+CREATE TABLE auth_groups_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	auth_group_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (auth_group_id, key),
+	FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
 CREATE TABLE auth_groups_identity_provider_groups (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     auth_group_id INTEGER NOT NULL,
@@ -105,6 +127,14 @@ CREATE TABLE identities_certificates (
     PRIMARY KEY (identity_id,
     certificate_id)
 ) WITHOUT ROWID;
+CREATE TABLE identities_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	identity_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (identity_id, key),
+	FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE
+);
 CREATE TABLE identities_projects (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     identity_id INTEGER NOT NULL,
@@ -149,6 +179,28 @@ CREATE TABLE "images_aliases" (
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE,
     FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
+CREATE TABLE images_aliases_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	image_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	UNIQUE (image_id, name),
+	FOREIGN KEY (image_id) REFERENCES images (id) ON DELETE CASCADE
+);
+CREATE TRIGGER images_aliases_history_after_alias_insert
+	AFTER INSERT ON images_aliases
+	BEGIN
+	INSERT OR IGNORE INTO images_aliases_history (image_id,
+    name) VALUES (NEW.image_id,
+    NEW.name);
+	END;
+CREATE TRIGGER images_aliases_history_after_alias_update
+	AFTER UPDATE OF image_id,
+    name ON images_aliases
+	BEGIN
+	INSERT OR IGNORE INTO images_aliases_history (image_id,
+    name) VALUES (NEW.image_id,
+    NEW.name);
+	END;
 CREATE INDEX images_aliases_project_id_idx ON images_aliases (project_id);
 CREATE TABLE "images_nodes" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
@@ -174,6 +226,21 @@ CREATE TABLE "images_properties" (
     value TEXT,
     FOREIGN KEY (image_id) REFERENCES "images" (id) ON DELETE CASCADE
 );
+CREATE TABLE images_signatures (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	key_fingerprint TEXT NOT NULL,
+	public_key TEXT NOT NULL,
+	signature TEXT NOT NULL,
+	UNIQUE (fingerprint, key_fingerprint)
+);
+CREATE TRIGGER images_signatures_after_image_delete
+	AFTER DELETE ON images
+	WHEN NOT EXISTS (SELECT 1 FROM images WHERE fingerprint = OLD.fingerprint)
+	BEGIN
+	DELETE FROM images_signatures
+		WHERE images_signatures.fingerprint = OLD.fingerprint;
+	END;
 CREATE TABLE "images_source" (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     image_id INTEGER NOT NULL,
@@ -630,6 +697,15 @@ CREATE TABLE replicators_config (
 	PRIMARY KEY (replicator_id,
     key)
 ) WITHOUT ROWID;
+CREATE TABLE replicators_entities (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	entity_url TEXT NOT NULL,
+	status TEXT NOT NULL,
+	message TEXT NOT NULL,
+	UNIQUE(replicator_id, entity_url),
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
 CREATE TABLE secrets (
     id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
     entity_type INTEGER NOT NULL,
@@ -825,6 +901,47 @@ CREATE TABLE "warnings" (
 	FOREIGN KEY (project_id) REFERENCES "projects" (id) ON DELETE CASCADE
 );
 CREATE UNIQUE INDEX warnings_unique_node_id_project_id_entity_type_code_entity_id_type_code ON warnings(IFNULL(node_id, -1), IFNULL(project_id, -1), entity_type_code, entity_id, type_code);
+CREATE TABLE webhooks (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	UNIQUE (name)
+);
+CREATE TABLE webhooks_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (webhook_id, key),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+CREATE TABLE webhooks_deliveries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	uuid TEXT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	node_id INTEGER NOT NULL,
+	event_type TEXT NOT NULL,
+	event TEXT NOT NULL,
+	status INTEGER NOT NULL,
+	attempts INTEGER NOT NULL,
+	creation_date DATETIME NOT NULL,
+	next_attempt_date DATETIME NOT NULL,
+	last_error TEXT NOT NULL,
+	UNIQUE (uuid),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE,
+	FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE
+);
+CREATE INDEX webhooks_deliveries_node_id_next_attempt_date_idx ON webhooks_deliveries (node_id,
+    next_attempt_date);
+CREATE TABLE webhooks_status (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	last_delivery_date DATETIME,
+	last_failure_date DATETIME,
+	last_error TEXT NOT NULL,
+	UNIQUE (webhook_id),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
 
-INSERT INTO schema (version, updated_at) VALUES (88, strftime("%s"))
+INSERT INTO schema (version, updated_at) VALUES (94, strftime("%s"))
 `
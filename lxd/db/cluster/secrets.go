@@ -90,12 +90,16 @@ const (
 
 	// SecretTypeBearerSigningKey is the SecretType for bearer identity signing keys.
 	SecretTypeBearerSigningKey SecretType = "bearer_signing_key"
+
+	// SecretTypeImageSigningKey is the SecretType for the key material of the image signing key of the server.
+	SecretTypeImageSigningKey SecretType = "image_signing_key"
 )
 
 const (
 	// secretTypeCodeCoreAuth is the database code for SecretTypeCoreAuth.
 	secretTypeCodeCoreAuth         int64 = 1
 	secretTypeCodeBearerSigningKey int64 = 2
+	secretTypeCodeImageSigningKey  int64 = 3
 )
 
 // Value implements [driver.Valuer] for SecretType.
@@ -105,6 +109,8 @@ func (s SecretType) Value() (driver.Value, error) {
 		return secretTypeCodeCoreAuth, nil
 	case SecretTypeBearerSigningKey:
 		return secretTypeCodeBearerSigningKey, nil
+	case SecretTypeImageSigningKey:
+		return secretTypeCodeImageSigningKey, nil
 	}
 
 	return nil, fmt.Errorf("Invalid secret type %q", s)
@@ -117,6 +123,8 @@ func (s *SecretType) ScanInteger(code int64) error {
 		*s = SecretTypeCoreAuth
 	case secretTypeCodeBearerSigningKey:
 		*s = SecretTypeBearerSigningKey
+	case secretTypeCodeImageSigningKey:
+		*s = SecretTypeImageSigningKey
 	default:
 		return fmt.Errorf("Invalid secret type code %d", code)
 	}
@@ -378,3 +386,51 @@ func RotateBearerIdentitySigningKey(ctx context.Context, tx *sql.Tx, identityID
 
 	return signingKey, nil
 }
+
+// GetImageSigningKey returns the key material of the image signing key of the server. It returns an
+// [api.StatusError] with [http.StatusNotFound] if no key has been created yet.
+func GetImageSigningKey(ctx context.Context, tx *sql.Tx) (*AuthSecret, error) {
+	q := `SELECT id, value, creation_date FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ?`
+
+	var secrets []AuthSecret
+	scanFunc := func(scan func(dest ...any) error) error {
+		var secret AuthSecret
+		err := scan(&secret.ID, &secret.Value, &secret.CreationDate)
+		if err != nil {
+			return err
+		}
+
+		secrets = append(secrets, secret)
+		return nil
+	}
+
+	err := query.Scan(ctx, tx, q, scanFunc, EntityType(entity.TypeServer), 0, SecretTypeImageSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed getting image signing key: %w", err)
+	}
+
+	switch len(secrets) {
+	case 0:
+		return nil, api.NewStatusError(http.StatusNotFound, "No image signing key exists")
+	case 1:
+		return &secrets[0], nil
+	}
+
+	return nil, errors.New("Encountered more than one image signing key")
+}
+
+// RotateImageSigningKey deletes the image signing key of the server, if any, and creates a new one.
+func RotateImageSigningKey(ctx context.Context, tx *sql.Tx) (*AuthSecret, error) {
+	_, err := tx.ExecContext(ctx, "DELETE FROM secrets WHERE entity_type = ? AND entity_id = ? AND type = ?", EntityType(entity.TypeServer), 0, SecretTypeImageSigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("Failed deleting image signing key: %w", err)
+	}
+
+	secret := newAuthSecret()
+	secret.ID, err = createSecret(ctx, tx, entity.TypeServer, 0, SecretTypeImageSigningKey, secret.Value, secret.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating image signing key: %w", err)
+	}
+
+	return &secret, nil
+}
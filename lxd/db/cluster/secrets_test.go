@@ -3,10 +3,13 @@ package cluster
 import (
 	"context"
 	"database/sql"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
 )
 
 func TestAuthSecrets(t *testing.T) {
@@ -77,3 +80,52 @@ func TestAuthSecrets(t *testing.T) {
 		require.Equal(t, rotatedSecrets[i].CreationDate.String(), dbSecrets[i].CreationDate.String())
 	}
 }
+
+func TestImageSigningKey(t *testing.T) {
+	db := newDB(t)
+	doTx := func(f func(ctx context.Context, tx *sql.Tx)) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		tx, err := db.Begin()
+		require.NoError(t, err)
+
+		f(ctx, tx)
+		require.NoError(t, tx.Commit())
+	}
+
+	// No key exists until one is created.
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		_, err := GetImageSigningKey(ctx, tx)
+		require.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+	})
+
+	var key1 *AuthSecret
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		var err error
+		key1, err = RotateImageSigningKey(ctx, tx)
+		require.NoError(t, err)
+
+		// The image signing key is kept apart from the core auth secrets.
+		secrets, err := GetCoreAuthSecrets(ctx, tx)
+		require.NoError(t, err)
+		require.Empty(t, secrets)
+	})
+
+	// Rotating the key replaces it.
+	var key2 *AuthSecret
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		var err error
+		key2, err = RotateImageSigningKey(ctx, tx)
+		require.NoError(t, err)
+	})
+
+	require.NotEqual(t, key1.Value.String(), key2.Value.String())
+
+	doTx(func(ctx context.Context, tx *sql.Tx) {
+		key, err := GetImageSigningKey(ctx, tx)
+		require.NoError(t, err)
+		require.Equal(t, key2.ID, key.ID)
+		require.Equal(t, key2.Value.String(), key.Value.String())
+	})
+}
@@ -132,6 +132,178 @@ var updates = map[int]schema.Update{
 	86: updateFromV85,
 	87: updateFromV86,
 	88: updateFromV87,
+	89: updateFromV88,
+	90: updateFromV89,
+	91: updateFromV90,
+	92: updateFromV91,
+	93: updateFromV92,
+	94: updateFromV93,
+}
+
+func updateFromV93(ctx context.Context, tx *sql.Tx) error {
+	// Record the aliases images have had, so that image retention policies can keep the latest versions per alias
+	// after an alias is moved to a newer image.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE images_aliases_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	image_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	UNIQUE (image_id, name),
+	FOREIGN KEY (image_id) REFERENCES images (id) ON DELETE CASCADE
+);
+
+INSERT INTO images_aliases_history (image_id, name) SELECT image_id, name FROM images_aliases;
+
+CREATE TRIGGER images_aliases_history_after_alias_insert
+	AFTER INSERT ON images_aliases
+	BEGIN
+	INSERT OR IGNORE INTO images_aliases_history (image_id, name) VALUES (NEW.image_id, NEW.name);
+	END;
+
+CREATE TRIGGER images_aliases_history_after_alias_update
+	AFTER UPDATE OF image_id, name ON images_aliases
+	BEGIN
+	INSERT OR IGNORE INTO images_aliases_history (image_id, name) VALUES (NEW.image_id, NEW.name);
+	END;
+`)
+	return err
+}
+
+func updateFromV92(ctx context.Context, tx *sql.Tx) error {
+	// Add image signatures. They are stored by image fingerprint, as they apply to the image in all projects, and are
+	// deleted along with the last image record with this fingerprint.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE images_signatures (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	fingerprint TEXT NOT NULL,
+	key_fingerprint TEXT NOT NULL,
+	public_key TEXT NOT NULL,
+	signature TEXT NOT NULL,
+	UNIQUE (fingerprint, key_fingerprint)
+);
+
+CREATE TRIGGER images_signatures_after_image_delete
+	AFTER DELETE ON images
+	WHEN NOT EXISTS (SELECT 1 FROM images WHERE fingerprint = OLD.fingerprint)
+	BEGIN
+	DELETE FROM images_signatures
+		WHERE images_signatures.fingerprint = OLD.fingerprint;
+	END;
+`)
+	return err
+}
+
+func updateFromV91(ctx context.Context, tx *sql.Tx) error {
+	// Add configuration to identities and authorization groups.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE identities_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	identity_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (identity_id, key),
+	FOREIGN KEY (identity_id) REFERENCES identities (id) ON DELETE CASCADE
+);
+
+CREATE TABLE auth_groups_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	auth_group_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (auth_group_id, key),
+	FOREIGN KEY (auth_group_id) REFERENCES auth_groups (id) ON DELETE CASCADE
+);
+`)
+	return err
+}
+
+func updateFromV90(ctx context.Context, tx *sql.Tx) error {
+	// Add webhooks, their persisted delivery queue and their delivery status.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE webhooks (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	name TEXT NOT NULL,
+	description TEXT NOT NULL,
+	UNIQUE (name)
+);
+
+CREATE TABLE webhooks_config (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT,
+	UNIQUE (webhook_id, key),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhooks_deliveries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	uuid TEXT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	node_id INTEGER NOT NULL,
+	event_type TEXT NOT NULL,
+	event TEXT NOT NULL,
+	status INTEGER NOT NULL,
+	attempts INTEGER NOT NULL,
+	creation_date DATETIME NOT NULL,
+	next_attempt_date DATETIME NOT NULL,
+	last_error TEXT NOT NULL,
+	UNIQUE (uuid),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE,
+	FOREIGN KEY (node_id) REFERENCES nodes (id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhooks_deliveries_node_id_next_attempt_date_idx ON webhooks_deliveries (node_id, next_attempt_date);
+
+CREATE TABLE webhooks_status (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	webhook_id INTEGER NOT NULL,
+	last_delivery_date DATETIME,
+	last_failure_date DATETIME,
+	last_error TEXT NOT NULL,
+	UNIQUE (webhook_id),
+	FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
+`)
+	return err
+}
+
+func updateFromV89(ctx context.Context, tx *sql.Tx) error {
+	// Record the mutating API requests for auditing.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	date DATETIME NOT NULL,
+	location TEXT NOT NULL,
+	identity TEXT NOT NULL,
+	protocol TEXT NOT NULL,
+	source_address TEXT NOT NULL,
+	method TEXT NOT NULL,
+	project TEXT NOT NULL,
+	entity_url TEXT NOT NULL,
+	request_hash TEXT NOT NULL,
+	status_code INTEGER NOT NULL
+);
+
+CREATE INDEX audit_log_date_idx ON audit_log (date);
+`)
+	return err
+}
+
+func updateFromV88(ctx context.Context, tx *sql.Tx) error {
+	// Record the outcome of the last run of a replicator for each of the dependent entities it reconciles.
+	_, err := tx.ExecContext(ctx, `
+CREATE TABLE replicators_entities (
+	id INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+	replicator_id INTEGER NOT NULL,
+	entity_url TEXT NOT NULL,
+	status TEXT NOT NULL,
+	message TEXT NOT NULL,
+	UNIQUE(replicator_id, entity_url),
+	FOREIGN KEY (replicator_id) REFERENCES replicators (id) ON DELETE CASCADE
+);
+`)
+	return err
 }
 
 func updateFromV87(ctx context.Context, tx *sql.Tx) error {
@@ -951,3 +951,37 @@ FROM identities
 	require.NoError(t, err)
 	require.Equal(t, pendingTLSMetadata, gotPendingTLSMetadata)
 }
+
+func TestUpdateFromV88(t *testing.T) {
+	schema := Schema()
+	db, err := schema.ExerciseUpdate(89, func(db *sql.DB) {
+		_, err := db.Exec(`
+INSERT INTO replicators (name, project_id, description, last_run_status) VALUES ('r1', 1, '', 'Pending');
+INSERT INTO replicators (name, project_id, description, last_run_status) VALUES ('r2', 1, '', 'Pending');
+`)
+		require.NoError(t, err)
+	})
+	require.NoError(t, err)
+
+	_, err = db.Exec(`PRAGMA foreign_keys = ON`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO replicators_entities (replicator_id, entity_url, status, message) VALUES (1, '/1.0/profiles/p1', 'Synced', '')`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO replicators_entities (replicator_id, entity_url, status, message) VALUES (2, '/1.0/profiles/p1', 'Conflict', 'Profile exists')`)
+	require.NoError(t, err)
+
+	// Each entity is only recorded once per replicator.
+	_, err = db.Exec(`INSERT INTO replicators_entities (replicator_id, entity_url, status, message) VALUES (1, '/1.0/profiles/p1', 'Failed', '')`)
+	require.Error(t, err)
+
+	// The entities are removed along with the replicator.
+	_, err = db.Exec(`DELETE FROM replicators WHERE id = 1`)
+	require.NoError(t, err)
+
+	var count int
+	err = db.QueryRow(`SELECT count(*) FROM replicators_entities`).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
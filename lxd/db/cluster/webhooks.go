@@ -0,0 +1,271 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db/query"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// WebhookDeliveryStatus represents the status of a webhook delivery stored as an integer in the database.
+//
+// This type implements the [sql.Scanner] and [driver.Value] interfaces to automatically handle conversion between API constants and their int64 representation in the database.
+type WebhookDeliveryStatus string
+
+const (
+	webhookDeliveryStatusPending int64 = 0
+	webhookDeliveryStatusFailed  int64 = 1
+)
+
+// ScanInteger implements [query.IntegerScanner] for [WebhookDeliveryStatus].
+func (s *WebhookDeliveryStatus) ScanInteger(statusCode int64) error {
+	switch statusCode {
+	case webhookDeliveryStatusPending:
+		*s = api.WebhookDeliveryStatusPending
+	case webhookDeliveryStatusFailed:
+		*s = api.WebhookDeliveryStatusFailed
+	default:
+		return fmt.Errorf("Unknown webhook delivery status %d", statusCode)
+	}
+
+	return nil
+}
+
+// Scan implements [sql.Scanner] for [WebhookDeliveryStatus]. This converts the database integer value back into the correct API constant or returns an error.
+func (s *WebhookDeliveryStatus) Scan(value any) error {
+	return query.ScanValue(value, s, false)
+}
+
+// Value implements [driver.Value] for [WebhookDeliveryStatus]. This converts the API constant into its integer database representation or throws an error.
+func (s WebhookDeliveryStatus) Value() (driver.Value, error) {
+	switch s {
+	case api.WebhookDeliveryStatusPending:
+		return webhookDeliveryStatusPending, nil
+	case api.WebhookDeliveryStatusFailed:
+		return webhookDeliveryStatusFailed, nil
+	}
+
+	return nil, fmt.Errorf("Invalid webhook delivery status %q", s)
+}
+
+// WebhookRow represents a single row of the webhooks table.
+// db:model webhooks
+type WebhookRow struct {
+	ID          int64  `db:"id"`
+	Name        string `db:"name"`
+	Description string `db:"description"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (WebhookRow) APIName() string {
+	return "Webhook"
+}
+
+// ToAPI converts the database [WebhookRow] struct to API type [api.Webhook].
+func (r *WebhookRow) ToAPI(allConfigs map[int64]map[string]string) *api.Webhook {
+	config := allConfigs[r.ID]
+	if config == nil {
+		config = map[string]string{}
+	}
+
+	return &api.Webhook{
+		Name:        r.Name,
+		Description: r.Description,
+		Config:      config,
+	}
+}
+
+// WebhooksConfigStore returns a [query.EntityConfigStore] for webhooks.
+func WebhooksConfigStore() *query.EntityConfigStore {
+	return &query.EntityConfigStore{
+		EntityTable:               "webhooks",
+		ConfigTable:               "webhooks_config",
+		ConfigTableEntityIDColumn: "webhook_id",
+	}
+}
+
+// GetWebhooks returns all webhooks.
+func GetWebhooks(ctx context.Context, tx *sql.Tx) ([]WebhookRow, error) {
+	return query.Select[WebhookRow](ctx, tx, "ORDER BY name")
+}
+
+// GetWebhook returns the webhook with the given name.
+func GetWebhook(ctx context.Context, tx *sql.Tx, name string) (*WebhookRow, error) {
+	return query.SelectOne[WebhookRow](ctx, tx, "WHERE name = ?", name)
+}
+
+// CreateWebhook adds a new webhook to the database.
+func CreateWebhook(ctx context.Context, tx *sql.Tx, object WebhookRow) (int64, error) {
+	return query.Create(ctx, tx, object)
+}
+
+// UpdateWebhook updates the webhook row by its ID.
+func UpdateWebhook(ctx context.Context, tx *sql.Tx, object WebhookRow) error {
+	return query.UpdateByPrimaryKey(ctx, tx, object)
+}
+
+// DeleteWebhook deletes the webhook with the given name.
+func DeleteWebhook(ctx context.Context, tx *sql.Tx, name string) error {
+	return query.DeleteOne[WebhookRow, *WebhookRow](ctx, tx, "WHERE name = ?", name)
+}
+
+// WebhookDeliveryRow represents a single row of the webhooks_deliveries table.
+// db:model webhooks_deliveries
+type WebhookDeliveryRow struct {
+	ID              int64                 `db:"id"`
+	UUID            string                `db:"uuid"`
+	WebhookID       int64                 `db:"webhook_id"`
+	NodeID          int64                 `db:"node_id"`
+	EventType       string                `db:"event_type"`
+	Event           string                `db:"event"`
+	Status          WebhookDeliveryStatus `db:"status"`
+	Attempts        int                   `db:"attempts"`
+	CreationDate    time.Time             `db:"creation_date"`
+	NextAttemptDate time.Time             `db:"next_attempt_date"`
+	LastError       string                `db:"last_error"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (WebhookDeliveryRow) APIName() string {
+	return "Webhook delivery"
+}
+
+// APIPluralName implements [query.APIPluralNamer] for API friendly error messages.
+func (WebhookDeliveryRow) APIPluralName() string {
+	return "Webhook deliveries"
+}
+
+// WebhookDelivery contains [WebhookDeliveryRow] with additional joins.
+// db:model webhooks_deliveries
+type WebhookDelivery struct {
+	Row WebhookDeliveryRow
+
+	// db:join JOIN nodes ON webhooks_deliveries.node_id = nodes.id
+	Location string `db:"nodes.name"`
+}
+
+// ToAPI converts the [WebhookDelivery] to an [api.WebhookDelivery].
+func (d WebhookDelivery) ToAPI() api.WebhookDelivery {
+	return api.WebhookDelivery{
+		UUID:          d.Row.UUID,
+		Location:      d.Location,
+		EventType:     d.Row.EventType,
+		Status:        string(d.Row.Status),
+		Attempts:      d.Row.Attempts,
+		CreatedAt:     d.Row.CreationDate,
+		NextAttemptAt: d.Row.NextAttemptDate,
+		LastError:     d.Row.LastError,
+	}
+}
+
+// CreateWebhookDeliveries adds deliveries to the queue of webhooks.
+func CreateWebhookDeliveries(ctx context.Context, tx *sql.Tx, deliveries []WebhookDeliveryRow) error {
+	for _, delivery := range deliveries {
+		_, err := query.Create(ctx, tx, delivery)
+		if err != nil {
+			return fmt.Errorf("Failed queuing webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CountWebhookDeliveries returns the number of pending deliveries of the webhook with the given ID on the given member.
+func CountWebhookDeliveries(ctx context.Context, tx *sql.Tx, webhookID int64, nodeID int64) (int, error) {
+	return query.Count(ctx, tx, "webhooks_deliveries", "webhook_id = ? AND node_id = ? AND status = ?", webhookID, nodeID, webhookDeliveryStatusPending)
+}
+
+// GetWebhookDeliveries returns the pending and failed deliveries of the webhook with the given name, oldest first.
+func GetWebhookDeliveries(ctx context.Context, tx *sql.Tx, webhookName string) ([]WebhookDelivery, error) {
+	return query.Select[WebhookDelivery](ctx, tx, "JOIN webhooks ON webhooks_deliveries.webhook_id = webhooks.id WHERE webhooks.name = ? ORDER BY webhooks_deliveries.id", webhookName)
+}
+
+// GetDueWebhookDeliveries returns the pending deliveries of the given member whose next attempt is due, oldest first.
+// Deliveries queued after a delivery of the same webhook waiting for a retry are skipped, so that the events of a
+// webhook are delivered in order.
+func GetDueWebhookDeliveries(ctx context.Context, tx *sql.Tx, nodeID int64, now time.Time, limit int) ([]WebhookDeliveryRow, error) {
+	return query.Select[WebhookDeliveryRow](ctx, tx, `
+WHERE node_id = ? AND status = ? AND next_attempt_date <= ? AND NOT EXISTS (
+	SELECT 1 FROM webhooks_deliveries AS previous
+	WHERE previous.webhook_id = webhooks_deliveries.webhook_id AND previous.node_id = webhooks_deliveries.node_id
+	AND previous.status = ? AND previous.next_attempt_date > ? AND previous.id < webhooks_deliveries.id
+) ORDER BY id LIMIT ?`, nodeID, webhookDeliveryStatusPending, now, webhookDeliveryStatusPending, now, limit)
+}
+
+// UpdateWebhookDelivery updates the webhook delivery row by its ID.
+func UpdateWebhookDelivery(ctx context.Context, tx *sql.Tx, object WebhookDeliveryRow) error {
+	return query.UpdateByPrimaryKey(ctx, tx, object)
+}
+
+// DeleteWebhookDelivery deletes the given webhook delivery.
+func DeleteWebhookDelivery(ctx context.Context, tx *sql.Tx, object WebhookDeliveryRow) error {
+	return query.DeleteByPrimaryKey(ctx, tx, object)
+}
+
+// DeleteFailedWebhookDeliveriesBefore deletes the failed deliveries of the given member created before the given date.
+func DeleteFailedWebhookDeliveriesBefore(ctx context.Context, tx *sql.Tx, nodeID int64, date time.Time) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM webhooks_deliveries WHERE node_id = ? AND status = ? AND creation_date < ?", nodeID, webhookDeliveryStatusFailed, date)
+	if err != nil {
+		return fmt.Errorf("Failed deleting failed webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// WebhookStatusRow represents a single row of the webhooks_status table.
+// db:model webhooks_status
+type WebhookStatusRow struct {
+	ID               int64        `db:"id"`
+	WebhookID        int64        `db:"webhook_id"`
+	LastDeliveryDate sql.NullTime `db:"last_delivery_date"`
+	LastFailureDate  sql.NullTime `db:"last_failure_date"`
+	LastError        string       `db:"last_error"`
+}
+
+// APIName implements [query.APINamer] for API friendly error messages.
+func (WebhookStatusRow) APIName() string {
+	return "Webhook status"
+}
+
+// GetWebhookStatus returns the delivery status of the webhook with the given ID.
+// An empty status is returned if nothing was delivered yet.
+func GetWebhookStatus(ctx context.Context, tx *sql.Tx, webhookID int64) (*WebhookStatusRow, error) {
+	statuses, err := query.Select[WebhookStatusRow](ctx, tx, "WHERE webhook_id = ?", webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(statuses) == 0 {
+		return &WebhookStatusRow{WebhookID: webhookID}, nil
+	}
+
+	return &statuses[0], nil
+}
+
+// SetWebhookDelivered records a successful delivery of the webhook with the given ID.
+func SetWebhookDelivered(ctx context.Context, tx *sql.Tx, webhookID int64, date time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO webhooks_status (webhook_id, last_delivery_date, last_error) VALUES (?, ?, '')
+	ON CONFLICT (webhook_id) DO UPDATE SET last_delivery_date = excluded.last_delivery_date`, webhookID, date)
+	if err != nil {
+		return fmt.Errorf("Failed updating webhook status: %w", err)
+	}
+
+	return nil
+}
+
+// SetWebhookFailed records a failed delivery attempt of the webhook with the given ID.
+func SetWebhookFailed(ctx context.Context, tx *sql.Tx, webhookID int64, date time.Time, lastError string) error {
+	_, err := tx.ExecContext(ctx, `
+INSERT INTO webhooks_status (webhook_id, last_failure_date, last_error) VALUES (?, ?, ?)
+	ON CONFLICT (webhook_id) DO UPDATE SET last_failure_date = excluded.last_failure_date, last_error = excluded.last_error`, webhookID, date, lastError)
+	if err != nil {
+		return fmt.Errorf("Failed updating webhook status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package cluster
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestWebhookDeliveries(t *testing.T) {
+	db := newDB(t)
+	ctx := context.Background()
+
+	addNode(t, db, "1.2.3.4:666", 1, 1)
+
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	webhookID, err := CreateWebhook(ctx, tx, WebhookRow{Name: "chat-ops"})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	deliveries := []WebhookDeliveryRow{
+		{UUID: "a", WebhookID: webhookID, NodeID: 1, EventType: "lifecycle", Event: "{}", Status: api.WebhookDeliveryStatusPending, CreationDate: now, NextAttemptDate: now.Add(-time.Minute)},
+		{UUID: "b", WebhookID: webhookID, NodeID: 1, EventType: "lifecycle", Event: "{}", Status: api.WebhookDeliveryStatusPending, CreationDate: now, NextAttemptDate: now.Add(time.Minute)},
+		{UUID: "c", WebhookID: webhookID, NodeID: 1, EventType: "security", Event: "{}", Status: api.WebhookDeliveryStatusFailed, CreationDate: now.Add(-8 * 24 * time.Hour), NextAttemptDate: now},
+	}
+
+	err = CreateWebhookDeliveries(ctx, tx, deliveries)
+	require.NoError(t, err)
+
+	count, err := CountWebhookDeliveries(ctx, tx, webhookID, 1)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	// Only the pending deliveries whose next attempt is due are returned.
+	due, err := GetDueWebhookDeliveries(ctx, tx, 1, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "a", due[0].UUID)
+
+	// A delivery waiting for a retry holds back the following deliveries of the webhook.
+	err = CreateWebhookDeliveries(ctx, tx, []WebhookDeliveryRow{
+		{UUID: "d", WebhookID: webhookID, NodeID: 1, EventType: "lifecycle", Event: "{}", Status: api.WebhookDeliveryStatusPending, CreationDate: now, NextAttemptDate: now},
+	})
+	require.NoError(t, err)
+
+	due, err = GetDueWebhookDeliveries(ctx, tx, 1, now, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "a", due[0].UUID)
+
+	err = DeleteWebhookDelivery(ctx, tx, due[0])
+	require.NoError(t, err)
+
+	err = DeleteFailedWebhookDeliveriesBefore(ctx, tx, 1, now.Add(-7*24*time.Hour))
+	require.NoError(t, err)
+
+	all, err := GetWebhookDeliveries(ctx, tx, "chat-ops")
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	require.Equal(t, "b", all[0].Row.UUID)
+	require.Equal(t, "node at 1.2.3.4:666", all[0].Location)
+
+	// The status records the last delivery and the last failure.
+	status, err := GetWebhookStatus(ctx, tx, webhookID)
+	require.NoError(t, err)
+	require.False(t, status.LastDeliveryDate.Valid)
+
+	err = SetWebhookDelivered(ctx, tx, webhookID, now)
+	require.NoError(t, err)
+
+	err = SetWebhookFailed(ctx, tx, webhookID, now, "Unexpected status code 503")
+	require.NoError(t, err)
+
+	status, err = GetWebhookStatus(ctx, tx, webhookID)
+	require.NoError(t, err)
+	require.True(t, status.LastDeliveryDate.Valid)
+	require.True(t, status.LastFailureDate.Valid)
+	require.Equal(t, "Unexpected status code 503", status.LastError)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
 )
 
 func TestLocateImage(t *testing.T) {
@@ -106,3 +107,81 @@ func TestGetImage(t *testing.T) {
 		return nil
 	})
 }
+
+func TestImageSignatures(t *testing.T) {
+	dbCluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+	project := "default"
+
+	_ = dbCluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.CreateImage(ctx, project, "abcd1", "x.gz", 16, false, false, "amd64", time.Now(), time.Now(), map[string]string{}, "container", nil)
+		require.NoError(t, err)
+
+		signature := api.ImageSignature{KeyFingerprint: "key1", PublicKey: "public1", Signature: "signature1"}
+		err = cluster.CreateImageSignatures(ctx, tx.Tx(), "abcd1", []api.ImageSignature{signature})
+		require.NoError(t, err)
+
+		// A new signature made with the same key replaces the existing one.
+		signature.Signature = "signature2"
+		err = cluster.CreateImageSignatures(ctx, tx.Tx(), "abcd1", []api.ImageSignature{signature})
+		require.NoError(t, err)
+
+		id, img, err := tx.GetImage(ctx, "abcd1", cluster.ImageFilter{Project: &project})
+		require.NoError(t, err)
+		assert.Equal(t, []api.ImageSignature{signature}, img.Signatures)
+
+		// The signatures are deleted along with the image.
+		err = tx.DeleteImage(ctx, id)
+		require.NoError(t, err)
+
+		signatures, err := cluster.GetImageSignatures(ctx, tx.Tx(), "abcd1")
+		require.NoError(t, err)
+		assert.Empty(t, signatures)
+
+		return nil
+	})
+}
+
+func TestImageAliasHistory(t *testing.T) {
+	dbCluster, cleanup := db.NewTestCluster(t)
+	defer cleanup()
+	project := "default"
+
+	_ = dbCluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		err := tx.CreateImage(ctx, project, "abcd1", "x.gz", 16, false, false, "amd64", time.Now(), time.Now(), map[string]string{}, "container", nil)
+		require.NoError(t, err)
+
+		err = tx.CreateImage(ctx, project, "abcd2", "x.gz", 16, false, false, "amd64", time.Now(), time.Now(), map[string]string{}, "container", nil)
+		require.NoError(t, err)
+
+		id1, _, err := tx.GetImage(ctx, "abcd1", cluster.ImageFilter{Project: &project})
+		require.NoError(t, err)
+
+		id2, _, err := tx.GetImage(ctx, "abcd2", cluster.ImageFilter{Project: &project})
+		require.NoError(t, err)
+
+		err = tx.CreateImageAlias(ctx, project, "nightly", id1, "")
+		require.NoError(t, err)
+
+		// Moving the alias to a newer image keeps track of its former target.
+		aliasID, _, err := tx.GetImageAlias(ctx, project, "nightly", true)
+		require.NoError(t, err)
+
+		err = tx.UpdateImageAlias(ctx, aliasID, id2, "")
+		require.NoError(t, err)
+
+		history, err := cluster.GetImageAliasHistory(ctx, tx.Tx(), project)
+		require.NoError(t, err)
+		assert.Equal(t, map[int][]string{id1: {"nightly"}, id2: {"nightly"}}, history)
+
+		// The history is deleted along with the image.
+		err = tx.DeleteImage(ctx, id1)
+		require.NoError(t, err)
+
+		history, err = cluster.GetImageAliasHistory(ctx, tx.Tx(), project)
+		require.NoError(t, err)
+		assert.Equal(t, map[int][]string{id2: {"nightly"}}, history)
+
+		return nil
+	})
+}
@@ -99,7 +99,17 @@ func (s *sessionHandler) StartSession(r *http.Request, res oidc.AuthenticationRe
 				return fmt.Errorf("Failed getting OIDC metadata: %w", err)
 			}
 
-			if newMetadata.Subject != existingMetadata.Subject {
+			// Identities that were deactivated via SCIM may not start new sessions.
+			if existingMetadata.Disabled {
+				return api.NewStatusError(http.StatusForbidden, "Identity is disabled")
+			}
+
+			// Groups and state provisioned via SCIM are not part of the IdP claims, so keep them.
+			newMetadata.SCIMExternalID = existingMetadata.SCIMExternalID
+			newMetadata.SCIMGroups = existingMetadata.SCIMGroups
+
+			// Identities provisioned via SCIM without an external ID have no subject until their first login.
+			if existingMetadata.Subject != "" && newMetadata.Subject != existingMetadata.Subject {
 				// We have historically allowed the IdP subject for a user with a given email address to change.
 				// This was with the view that the end user may authenticate to the IdP with a different mechanism (such
 				// as social login) and should still have the same permissions.
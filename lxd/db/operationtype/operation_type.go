@@ -145,6 +145,15 @@ const (
 	ReplicatorRun
 	ReplicatorRunInstance
 	ProjectReplicaModeUpdate
+	InstanceFork
+	ClusterRebalance
+	ReplicatorRunDependencies
+	AuditLogExpire
+	ClusterMemberRemove
+	ImageBuild
+	ImageSBOMGenerate
+	ImagesRetention
+	InstancePortForward
 
 	// upperBound is used only to enforce consistency in the package on init.
 	// Make sure it's always the last item in this list.
@@ -396,6 +405,24 @@ func (t Type) Description() string {
 		return "Replicating instance"
 	case ProjectReplicaModeUpdate:
 		return "Updating project replica mode"
+	case InstanceFork:
+		return "Forking instance"
+	case ClusterRebalance:
+		return "Rebalancing cluster instances"
+	case ReplicatorRunDependencies:
+		return "Replicating instance dependencies"
+	case AuditLogExpire:
+		return "Cleaning up expired audit log entries"
+	case ClusterMemberRemove:
+		return "Removing cluster member"
+	case ImageBuild:
+		return "Building image"
+	case ImageSBOMGenerate:
+		return "Generating image SBOM"
+	case ImagesRetention:
+		return "Enforcing image retention policies"
+	case InstancePortForward:
+		return "Forwarding port"
 
 	// It should never be possible to reach the default clause.
 	// See the init function.
@@ -414,7 +441,7 @@ func (t Type) EntityType() entity.Type {
 		BackupsExpire, SnapshotsExpire, ClusterJoinToken, CertificateAddToken, RenewServerCertificate,
 		ClusterHeal, ImagesUpdate, VolumeSnapshotsCreateScheduled, SnapshotsCreateScheduled,
 		PruneExpiredOperations, RefreshClusterLinkVolatileAddresses,
-		StoragePoolCreate, Wait:
+		StoragePoolCreate, Wait, ClusterRebalance, AuditLogExpire, ClusterMemberRemove, ImagesRetention:
 		return entity.TypeServer
 
 	// Project level operations.
@@ -422,7 +449,7 @@ func (t Type) EntityType() entity.Type {
 	// (the entity being created is not yet referenceable).
 	case VolumeCreate, ProjectRename, InstanceCreate, ImageDownload, ImageUploadToken, CustomVolumeBackupRestore,
 		InstanceStateUpdateBulk, BackupRestore, ProjectDelete, NetworkCreate, NetworkACLCreate, StorageBucketCreate,
-		NetworkZoneCreate, ReplicatorRunInstance, ProjectReplicaModeUpdate:
+		NetworkZoneCreate, ReplicatorRunInstance, ProjectReplicaModeUpdate, ReplicatorRunDependencies, ImageBuild:
 		return entity.TypeProject
 
 	// Storage bucket operations.
@@ -440,7 +467,8 @@ func (t Type) EntityType() entity.Type {
 	// Instance operations.
 	case BackupCreate, ConsoleShow, InstanceFreeze, InstanceUpdate, InstanceUnfreeze,
 		InstanceStart, InstanceStop, InstanceRestart, InstanceRename, InstanceMigrate, InstanceLiveMigrate,
-		InstanceDelete, InstanceRebuild, SnapshotRestore, CommandExec, SnapshotCreate, InstanceCopy:
+		InstanceDelete, InstanceRebuild, SnapshotRestore, CommandExec, SnapshotCreate, InstanceCopy, InstanceFork,
+		InstancePortForward:
 		return entity.TypeInstance
 
 	// Instance backup operations.
@@ -452,7 +480,7 @@ func (t Type) EntityType() entity.Type {
 		return entity.TypeInstanceSnapshot
 
 	// Image operations.
-	case ImageDelete, ImageRefresh, ImageDownloadToken, ImageUpload:
+	case ImageDelete, ImageRefresh, ImageDownloadToken, ImageUpload, ImageSBOMGenerate:
 		return entity.TypeImage
 
 	// Volume backup operations.
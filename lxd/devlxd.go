@@ -41,6 +41,10 @@ const (
 	// The security.devlxd.images key is used to enable devLXD image export.
 	devLXDSecurityImagesKey DevLXDSecurityKey = "security.devlxd.images"
 
+	// The security.devlxd.identity_tokens key is used to allow instances to
+	// request identity tokens through devLXD.
+	devLXDSecurityIdentityTokensKey DevLXDSecurityKey = "security.devlxd.identity_tokens"
+
 	// The security.devlxd.management.volumes key is used to allow volume
 	// management through devLXD.
 	devLXDSecurityManagementVolumesKey DevLXDSecurityKey = "security.devlxd.management.volumes"
@@ -87,6 +91,7 @@ var apiDevLXD = []APIEndpoint{
 	devLXDStoragePoolVolumeSnapshotsEndpoint,
 	devLXDUbuntuProEndpoint,
 	devLXDUbuntuProTokenEndpoint,
+	devLXDIdentityTokenEndpoint,
 }
 
 var devLXD10Endpoint = APIEndpoint{
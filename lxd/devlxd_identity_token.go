@@ -0,0 +1,82 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth/encryption"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// devLXDIdentityTokenLifetime is how long instance identity tokens are valid for.
+const devLXDIdentityTokenLifetime = 10 * time.Minute
+
+var devLXDIdentityTokenEndpoint = APIEndpoint{
+	Path:        "identity-token",
+	MetricsType: entity.TypeInstance,
+	Get:         APIEndpointAction{Handler: devLXDIdentityTokenGetHandler, AllowUntrusted: true},
+}
+
+func devLXDIdentityTokenGetHandler(d *Daemon, r *http.Request) response.Response {
+	inst, err := getInstanceFromContextAndCheckSecurityFlags(r.Context(), devLXDSecurityKey, devLXDSecurityIdentityTokensKey)
+	if err != nil {
+		return response.DevLXDErrorResponse(err)
+	}
+
+	s := d.State()
+
+	issuer := s.GlobalConfig.InstancesIdentityTokensIssuer()
+	if issuer == "" {
+		return response.DevLXDErrorResponse(api.NewStatusError(http.StatusForbidden, "Instance identity tokens are not enabled on the server"))
+	}
+
+	audience := r.URL.Query().Get("audience")
+	if audience == "" {
+		return response.DevLXDErrorResponse(api.NewStatusError(http.StatusBadRequest, "Missing audience"))
+	}
+
+	if len(audience) > 255 {
+		return response.DevLXDErrorResponse(api.NewStatusError(http.StatusBadRequest, "Audience is too long"))
+	}
+
+	expandedConfig := inst.ExpandedConfig()
+
+	var userClaims map[string]string
+	userKeys := expandedConfig["security.devlxd.identity_tokens.user_keys"]
+	if userKeys != "" {
+		userClaims = map[string]string{}
+		for key := range strings.SplitSeq(userKeys, ",") {
+			key = strings.TrimSpace(key)
+			value, ok := expandedConfig[key]
+			if ok && strings.HasPrefix(key, "user.") {
+				userClaims[strings.TrimPrefix(key, "user.")] = value
+			}
+		}
+	}
+
+	keys, err := identityTokenSigningKeys(r.Context(), s)
+	if err != nil {
+		logger.Error("Failed getting identity token signing keys", logger.Ctx{"err": err})
+		return response.DevLXDErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"))
+	}
+
+	expiresAt := time.Now().Add(devLXDIdentityTokenLifetime).UTC().Truncate(time.Second)
+	token, err := encryption.GetIdentityToken(keys[0], issuer, audience, expiresAt, encryption.IdentityTokenClaims{
+		ClusterUUID:  s.GlobalConfig.ClusterUUID(),
+		Project:      inst.Project().Name,
+		Instance:     inst.Name(),
+		InstanceUUID: inst.LocalConfig()["volatile.uuid"],
+		InstanceType: inst.Type().String(),
+		User:         userClaims,
+	})
+	if err != nil {
+		logger.Error("Failed signing instance identity token", logger.Ctx{"instance": inst.Name(), "project": inst.Project().Name, "err": err})
+		return response.DevLXDErrorResponse(api.StatusErrorf(http.StatusInternalServerError, "internal server error"))
+	}
+
+	return response.DevLXDResponse(http.StatusOK, api.DevLXDIdentityToken{Token: token, ExpiresAt: expiresAt}, "json")
+}
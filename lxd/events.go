@@ -133,6 +133,20 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 		}
 	}
 
+	// Resume the stream after the given event ID, taken from the query or from the header set by event source clients.
+	since := r.FormValue("since")
+	if since == "" {
+		since = r.Header.Get("Last-Event-ID")
+	}
+
+	if since != "" {
+		// Report missing history before upgrading the connection. This is checked again when adding the listener.
+		err = s.Events.CheckHistory(since)
+		if err != nil {
+			return err
+		}
+	}
+
 	l := logger.AddContext(logger.Ctx{"remote": r.RemoteAddr})
 
 	requestor, err := request.GetRequestor(r.Context())
@@ -254,7 +268,13 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 	defer func() { _ = conn.Close() }() // Ensure listener below ends when this function ends.
 
 	listenerConnection := events.NewWebsocketListenerConnection(conn)
-	listener, err := s.Events.AddListener(projectName, allProjects, filter, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	var listener *events.Listener
+	if since != "" {
+		listener, err = s.Events.AddListenerSince(since, projectName, allProjects, filter, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	} else {
+		listener, err = s.Events.AddListener(projectName, allProjects, filter, listenerConnection, types, excludeSources, recvFunc, excludeLocations)
+	}
+
 	if err != nil {
 		l.Warn("Failed adding event listener", logger.Ctx{"err": err})
 		return nil
@@ -289,13 +309,22 @@ func eventsSocket(s *state.State, r *http.Request, w http.ResponseWriter) error
 //	    name: all-projects
 //	    description: Retrieve instances from all projects
 //	    type: boolean
+//	  - in: query
+//	    name: since
+//	    description: Replay the events following the event with this ID before streaming new events (also read from the Last-Event-ID header)
+//	    type: string
+//	    example: 5c8c2b0e-5b8a-4a4f-9c43-1c5e0d3f2a61:1042
 //	responses:
 //	  "200":
 //	    description: Websocket message (JSON)
 //	    schema:
 //	      $ref: "#/definitions/Event"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
 //	  "403":
 //	    $ref: "#/responses/Forbidden"
+//	  "410":
+//	    description: The events following the requested event ID are no longer available
 //	  "500":
 //	    $ref: "#/responses/InternalServerError"
 func eventsGet(d *Daemon, r *http.Request) response.Response {
@@ -41,6 +41,7 @@ type Server struct {
 	notify            NotifyFunc
 	location          string
 	clusterIdentifier string
+	history           *history
 
 	// logger is a [logger.Logger] that can be used while an event is being processed.
 	// This is necessary because the global [logger.Logger] is configured with a hook that will send logging events to the server.
@@ -64,6 +65,7 @@ func NewServer(debug bool, verbose bool, notify NotifyFunc) (*Server, error) {
 		},
 		listeners: map[string]*Listener{},
 		notify:    notify,
+		history:   newHistory(),
 		logger:    eventServerLogger,
 	}
 
@@ -95,6 +97,25 @@ func (s *Server) SetClusterIdentifier(clusterIdentifier string) {
 // Warn: The filter must not call the default logger or send any events of its own. Otherwise, the event server will
 // deadlock when it tries to broadcast the logging event.
 func (s *Server) AddListener(projectName string, allProjects bool, filter func(logger.Logger, api.Event) bool, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(nil, projectName, allProjects, filter, connection, messageTypes, excludeSources, recvFunc, excludeLocations)
+}
+
+// AddListenerSince is like [Server.AddListener], but first sends the events of the history following the event with
+// the given ID to the listener. An error is returned if some of those events are no longer in the history.
+func (s *Server) AddListenerSince(since string, projectName string, allProjects bool, filter func(logger.Logger, api.Event) bool, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
+	return s.addListener(&since, projectName, allProjects, filter, connection, messageTypes, excludeSources, recvFunc, excludeLocations)
+}
+
+// CheckHistory returns an error if the events following the event with the given ID are no longer in the history.
+func (s *Server) CheckHistory(since string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	_, err := s.history.since(since)
+	return err
+}
+
+func (s *Server) addListener(since *string, projectName string, allProjects bool, filter func(logger.Logger, api.Event) bool, connection EventListenerConnection, messageTypes []string, excludeSources []EventSource, recvFunc EventHandler, excludeLocations []string) (*Listener, error) {
 	if allProjects && projectName != "" {
 		return nil, errors.New("Cannot specify project name when listening for events on all projects")
 	}
@@ -122,19 +143,78 @@ func (s *Server) AddListener(projectName string, allProjects bool, filter func(l
 	}
 
 	s.lock.Lock()
-	defer s.lock.Unlock()
 
 	if s.listeners[listener.id] != nil {
+		s.lock.Unlock()
 		return nil, fmt.Errorf("A listener with ID %q already exists", listener.id)
 	}
 
+	// Collect the missed events while holding the lock, so that no event is lost between the replay and the
+	// registration of the listener.
+	var missed []api.Event
+	if since != nil {
+		entries, err := s.history.since(*since)
+		if err != nil {
+			s.lock.Unlock()
+			return nil, err
+		}
+
+		filterLogger := s.logger.AddContext(logger.Ctx{"replay": true})
+		for _, entry := range entries {
+			if listener.accepts(filterLogger, entry.event, entry.source) {
+				missed = append(missed, entry.event)
+			}
+		}
+
+		// Events broadcast during the replay are queued behind the missed events.
+		listener.replaying = true
+	}
+
 	s.listeners[listener.id] = listener
+	s.lock.Unlock()
+
+	if since != nil {
+		err := s.replay(listener, missed)
+		if err != nil {
+			s.lock.Lock()
+			delete(s.listeners, listener.id)
+			s.lock.Unlock()
+
+			listener.Close()
+			return nil, fmt.Errorf("Failed replaying events: %w", err)
+		}
+	}
 
 	go listener.start()
 
 	return listener, nil
 }
 
+// replay sends the missed events to the listener, followed by the events queued while they were being sent. Live
+// delivery of the events to the listener only starts once the queue is empty, so that the events are received in
+// order.
+func (s *Server) replay(listener *Listener, events []api.Event) error {
+	for {
+		for _, event := range events {
+			err := listener.WriteJSON(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.lock.Lock()
+		events = listener.queue
+		listener.queue = nil
+		if len(events) == 0 {
+			listener.replaying = false
+			s.lock.Unlock()
+			return nil
+		}
+
+		s.lock.Unlock()
+	}
+}
+
 // SendLifecycle broadcasts a lifecycle event and logs it to syslog.
 func (s *Server) SendLifecycle(projectName string, event api.EventLifecycle) {
 	// Log before Send so the server logger is called outside the broadcast lock.
@@ -270,10 +350,6 @@ func (s *Server) Inject(event api.Event, eventSource EventSource) {
 }
 
 func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
-	sourceInSlice := func(source EventSource, sources []EventSource) bool {
-		return slices.Contains(sources, source)
-	}
-
 	s.lock.Lock()
 
 	// Set the Location for local events to the local serverName if not already populated (do it here rather
@@ -288,29 +364,18 @@ func (s *Server) broadcast(event api.Event, eventSource EventSource) error {
 		s.notify(event)
 	}
 
+	// Record the event in the history, which also assigns its ID.
+	s.history.add(&event, eventSource)
+
 	filterLogger := s.logger.AddContext(logger.Ctx{"source": eventSource})
 	listeners := s.listeners
 	for _, listener := range listeners {
-		// If the event is project specific, check if the listener is requesting events from that project.
-		if event.Project != "" && !listener.allProjects && event.Project != listener.projectName {
-			continue
-		}
-
-		if sourceInSlice(eventSource, listener.excludeSources) {
-			continue
-		}
-
-		if !slices.Contains(listener.messageTypes, event.Type) {
+		if !listener.accepts(filterLogger, event, eventSource) {
 			continue
 		}
 
-		// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
-		if eventSource != EventSourceLocal && slices.Contains(listener.excludeLocations, event.Location) {
-			continue
-		}
-
-		// Apply any further filters.
-		if !listener.filter(filterLogger, event) {
+		if listener.replaying {
+			listener.queue = append(listener.queue, event)
 			continue
 		}
 
@@ -355,4 +420,33 @@ type Listener struct {
 	filter           func(logger.Logger, api.Event) bool
 	excludeSources   []EventSource
 	excludeLocations []string
+
+	// replaying is set while the missed events are sent to a listener resuming from an event ID. The events
+	// broadcast in the meantime are added to the queue. Both are protected by the server lock.
+	replaying bool
+	queue     []api.Event
+}
+
+// accepts returns whether the event from the given source should be delivered to the listener.
+func (l *Listener) accepts(filterLogger logger.Logger, event api.Event, eventSource EventSource) bool {
+	// If the event is project specific, check if the listener is requesting events from that project.
+	if event.Project != "" && !l.allProjects && event.Project != l.projectName {
+		return false
+	}
+
+	if slices.Contains(l.excludeSources, eventSource) {
+		return false
+	}
+
+	if !slices.Contains(l.messageTypes, event.Type) {
+		return false
+	}
+
+	// If the event doesn't come from this member and has been excluded by listener, don't deliver it.
+	if eventSource != EventSourceLocal && slices.Contains(l.excludeLocations, event.Location) {
+		return false
+	}
+
+	// Apply any further filters.
+	return l.filter(filterLogger, event)
 }
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// testListenerConnection records the IDs of the events written to it, and calls onWrite after each write.
+type testListenerConnection struct {
+	mu      sync.Mutex
+	ids     []string
+	onWrite func()
+}
+
+func (c *testListenerConnection) Reader(ctx context.Context, recvFunc EventHandler) {
+	<-ctx.Done()
+}
+
+func (c *testListenerConnection) WriteJSON(event any) error {
+	c.mu.Lock()
+	c.ids = append(c.ids, event.(api.Event).ID)
+	onWrite := c.onWrite
+	c.onWrite = nil
+	c.mu.Unlock()
+
+	if onWrite != nil {
+		onWrite()
+	}
+
+	return nil
+}
+
+func (c *testListenerConnection) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string{}, c.ids...)
+}
+
+func (c *testListenerConnection) Close() error {
+	return nil
+}
+
+func (c *testListenerConnection) LocalAddr() net.Addr {
+	return nil
+}
+
+func (c *testListenerConnection) RemoteAddr() net.Addr {
+	return nil
+}
+
+func TestServerAddListenerSince(t *testing.T) {
+	s, err := NewServer(false, false, nil)
+	require.NoError(t, err)
+
+	for range 3 {
+		require.NoError(t, s.Send("", api.EventTypeLifecycle, api.EventLifecycle{}))
+	}
+
+	// An event broadcast while the missed events are being replayed is received after them.
+	conn := &testListenerConnection{}
+	conn.onWrite = func() {
+		assert.NoError(t, s.Send("", api.EventTypeLifecycle, api.EventLifecycle{}))
+	}
+
+	listener, err := s.AddListenerSince(s.history.eventID(1), "", true, nil, conn, []string{api.EventTypeLifecycle}, nil, nil, nil)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	assert.Equal(t, historyEventIDs(s.history, 2, 3, 4), conn.received())
+
+	// Live delivery resumes once the replay is complete.
+	require.NoError(t, s.Send("", api.EventTypeLifecycle, api.EventLifecycle{}))
+	assert.Eventually(t, func() bool { return len(conn.received()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, historyEventIDs(s.history, 2, 3, 4, 5), conn.received())
+
+	// Resuming from an event that is no longer in the history fails.
+	_, err = s.AddListenerSince(s.history.eventID(10), "", true, nil, &testListenerConnection{}, []string{api.EventTypeLifecycle}, nil, nil, nil)
+	assert.Error(t, err)
+}
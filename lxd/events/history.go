@@ -0,0 +1,90 @@
+package events
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// historySize is the number of events kept in the history of an event server.
+const historySize = 10000
+
+// historyEntry is an event kept in the history along with its source.
+type historyEntry struct {
+	event  api.Event
+	source EventSource
+}
+
+// history is a ring buffer of the most recent events broadcast by an event server.
+// It is not safe for concurrent use, the server lock must be held.
+type history struct {
+	// bootID identifies the run of the server, as the sequence numbers of the events start over on restart.
+	bootID  string
+	entries []historyEntry
+	next    int
+	lastSeq uint64
+}
+
+// newHistory returns an empty history with a new boot ID.
+func newHistory() *history {
+	return &history{bootID: uuid.NewString()}
+}
+
+// eventID returns the ID of the event with the given sequence number, in the "<boot ID>:<sequence number>" form.
+func (h *history) eventID(seq uint64) string {
+	return h.bootID + ":" + strconv.FormatUint(seq, 10)
+}
+
+// add assigns the next ID to the event and adds it to the history, evicting the oldest event if the history is full.
+func (h *history) add(event *api.Event, source EventSource) {
+	h.lastSeq++
+	event.ID = h.eventID(h.lastSeq)
+
+	entry := historyEntry{event: *event, source: source}
+	if len(h.entries) < historySize {
+		h.entries = append(h.entries, entry)
+		return
+	}
+
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % historySize
+}
+
+// since returns the events following the event with the given ID, oldest first.
+// An error is returned if some of those events are no longer in the history, either because they were evicted or
+// because the ID comes from a previous run of the server.
+func (h *history) since(id string) ([]historyEntry, error) {
+	bootID, seqStr, ok := strings.Cut(id, ":")
+	if !ok {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid event ID %q", id)
+	}
+
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid event ID %q", id)
+	}
+
+	if bootID != h.bootID {
+		return nil, api.StatusErrorf(http.StatusGone, "Event ID %q comes from a previous run of the server", id)
+	}
+
+	if seq > h.lastSeq {
+		return nil, api.StatusErrorf(http.StatusGone, "Event ID %q is newer than the last event ID %q", id, h.eventID(h.lastSeq))
+	}
+
+	missed := int(h.lastSeq - seq)
+	if missed > len(h.entries) {
+		return nil, api.StatusErrorf(http.StatusGone, "Events following ID %q are no longer in the history", id)
+	}
+
+	entries := make([]historyEntry, 0, missed)
+	for i := len(h.entries) - missed; i < len(h.entries); i++ {
+		entries = append(entries, h.entries[(h.next+i)%len(h.entries)])
+	}
+
+	return entries, nil
+}
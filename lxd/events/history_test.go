@@ -0,0 +1,108 @@
+package events
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func historyIDs(entries []historyEntry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.event.ID)
+	}
+
+	return ids
+}
+
+// historyEventIDs returns the IDs of the events of the history with the given sequence numbers.
+func historyEventIDs(h *history, seqs ...uint64) []string {
+	ids := make([]string, 0, len(seqs))
+	for _, seq := range seqs {
+		ids = append(ids, h.eventID(seq))
+	}
+
+	return ids
+}
+
+func TestHistory(t *testing.T) {
+	h := newHistory()
+
+	entries, err := h.since(h.eventID(0))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	for range 3 {
+		event := api.Event{}
+		h.add(&event, EventSourceLocal)
+	}
+
+	entries, err = h.since(h.eventID(1))
+	require.NoError(t, err)
+	assert.Equal(t, historyEventIDs(h, 2, 3), historyIDs(entries))
+
+	entries, err = h.since(h.eventID(3))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = h.since(h.eventID(4))
+	assert.True(t, api.StatusErrorCheck(err, http.StatusGone))
+
+	for _, id := range []string{"", "1042", h.bootID + ":", h.bootID + ":-1"} {
+		_, err = h.since(id)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest), id)
+	}
+}
+
+func TestHistory_Restart(t *testing.T) {
+	before := newHistory()
+	for range 5 {
+		event := api.Event{}
+		before.add(&event, EventSourceLocal)
+	}
+
+	after := newHistory()
+	for range 5 {
+		event := api.Event{}
+		after.add(&event, EventSourceLocal)
+	}
+
+	// The sequence numbers start over after a restart, but an ID from before it can't be resumed from, even if the
+	// new history has reached the same sequence number.
+	assert.NotEqual(t, before.eventID(2), after.eventID(2))
+
+	_, err := after.since(before.eventID(2))
+	assert.True(t, api.StatusErrorCheck(err, http.StatusGone))
+	assert.ErrorContains(t, err, "previous run of the server")
+}
+
+func TestHistory_Wraparound(t *testing.T) {
+	h := newHistory()
+
+	for range historySize + 5 {
+		event := api.Event{}
+		h.add(&event, EventSourceLocal)
+	}
+
+	assert.Len(t, h.entries, historySize)
+
+	// The oldest events have been evicted, the remaining ones are returned oldest first.
+	entries, err := h.since(h.eventID(5))
+	require.NoError(t, err)
+	require.Len(t, entries, historySize)
+	assert.Equal(t, h.eventID(6), entries[0].event.ID)
+	assert.Equal(t, h.eventID(historySize+5), entries[historySize-1].event.ID)
+
+	entries, err = h.since(h.eventID(historySize + 2))
+	require.NoError(t, err)
+	assert.Equal(t, historyEventIDs(h, historySize+3, historySize+4, historySize+5), historyIDs(entries))
+
+	// The events following an evicted event are no longer all in the history.
+	_, err = h.since(h.eventID(4))
+	assert.True(t, api.StatusErrorCheck(err, http.StatusGone))
+	assert.ErrorContains(t, err, "no longer in the history")
+}
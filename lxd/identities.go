@@ -7,6 +7,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
 	"time"
@@ -569,6 +570,8 @@ func identityBearerTokenPost(d *Daemon, r *http.Request) response.Response {
 		token, err = encryption.GetClientBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt, serverCertFingerprint)
 	case api.IdentityTypeBearerTokenDevLXD:
 		token, err = encryption.GetDevLXDBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt)
+	case api.IdentityTypeBearerTokenSCIM:
+		token, err = encryption.GetSCIMBearerToken(secret, id.Identifier, s.GlobalConfig.ClusterUUID(), expiresAt)
 	default:
 		err = api.StatusErrorf(http.StatusBadRequest, "Token cannot be issued for identity of type %q", id.Type)
 	}
@@ -1306,6 +1309,7 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 		var identityURLs []string
 		var groupsByIdentityID map[int64][]string
 		var certificatesByIdentityID map[int64][]string
+		var configByIdentityID map[int64]map[string]string
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 			var authMethodFilter *string
 			if authenticationMethod != "" {
@@ -1340,6 +1344,16 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 				return err
 			}
 
+			if identityIDFilter != nil {
+				configByIdentityID, err = dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), *identityIDFilter)
+			} else {
+				configByIdentityID, err = dbCluster.IdentitiesConfigStore().GetAll(ctx, tx.Tx())
+			}
+
+			if err != nil {
+				return err
+			}
+
 			return nil
 		})
 		if err != nil {
@@ -1354,7 +1368,7 @@ func identitiesGet(authenticationMethod string) func(d *Daemon, r *http.Request)
 		apiIdentities := make([]*api.Identity, 0, len(identities))
 		urlToIdentity := make(map[*api.URL]auth.EntitlementReporter, len(identities))
 		for _, id := range identities {
-			apiIdentity, err := id.ToAPI(groupsByIdentityID, certificatesByIdentityID)
+			apiIdentity, err := id.ToAPI(groupsByIdentityID, certificatesByIdentityID, configByIdentityID)
 			if err != nil {
 				return response.SmartError(err)
 			}
@@ -1502,6 +1516,7 @@ func identityGet(d *Daemon, r *http.Request) response.Response {
 
 	var groups map[int64][]string
 	var certificates map[int64][]string
+	var config map[int64]map[string]string
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
 		var err error
 		groups, err = dbCluster.GetIdentityAuthGroupNames(ctx, tx.Tx(), &id.ID, func(row dbCluster.AuthGroupsRow) (bool, error) {
@@ -1511,6 +1526,11 @@ func identityGet(d *Daemon, r *http.Request) response.Response {
 			return err
 		}
 
+		config, err = dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
 		if idType.AuthenticationMethod() == api.AuthenticationMethodTLS && !idType.IsPending() {
 			certificates, err = dbCluster.GetIdentitiesPEMCertificates(ctx, tx.Tx(), &id.ID)
 			if err != nil {
@@ -1524,7 +1544,7 @@ func identityGet(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	apiIdentity, err := id.ToAPI(groups, certificates)
+	apiIdentity, err := id.ToAPI(groups, certificates, config)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -1600,6 +1620,7 @@ func identityGetCurrent(d *Daemon, r *http.Request) response.Response {
 	var effectiveGroups []string
 	var permissions []dbCluster.Permission
 	var certificates map[int64][]string
+	var config map[int64]map[string]string
 	var entityURLs map[entity.Type]map[int]*api.URL
 	var id *dbCluster.IdentitiesRow
 	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -1620,6 +1641,11 @@ func identityGetCurrent(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
+		config, err = dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
 		effectiveGroups = requestor.CallerEffectiveAuthorizationGroupNames()
 		permissions, err = dbCluster.GetDistinctPermissionsByGroupNames(ctx, tx.Tx(), effectiveGroups)
 		if err != nil {
@@ -1637,7 +1663,7 @@ func identityGetCurrent(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	apiIdentity, err := id.ToAPI(map[int64][]string{id.ID: requestor.CallerAuthorizationGroupNames()}, certificates)
+	apiIdentity, err := id.ToAPI(map[int64][]string{id.ID: requestor.CallerAuthorizationGroupNames()}, certificates, config)
 	if err != nil {
 		return response.SmartError(err)
 	}
@@ -1829,7 +1855,12 @@ func updateSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluste
 			return err
 		}
 
-		apiIdentity, err := id.ToAPI(groups, certs)
+		config, err := dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
+		apiIdentity, err := id.ToAPI(groups, certs, config)
 		if err != nil {
 			return err
 		}
@@ -1839,8 +1870,8 @@ func updateSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluste
 			return err
 		}
 
-		// Return an error if the caller tries to update their own groups.
-		if !slices.Equal(identityPut.Groups, apiIdentity.Groups) {
+		// Return an error if the caller tries to update their own groups or configuration.
+		if !slices.Equal(identityPut.Groups, apiIdentity.Groups) || !maps.Equal(identityPut.Config, apiIdentity.Config) {
 			return api.NewStatusError(http.StatusForbidden, "Only the certificate may be changed")
 		}
 
@@ -1870,6 +1901,11 @@ func updateSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluste
 
 // updateIdentityPrivileged is called when the caller has `can_edit` on the identity. It must account for both OIDC and TLS identities.
 func updateIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.IdentitiesRow, identityPut api.IdentityPut) response.Response {
+	err := validateAPILimitsConfig(identityPut.Config)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
 	// Validate certificate if given (not present for OIDC or pending TLS identities).
 	var fingerprint string
 	if identityPut.TLSCertificate != "" {
@@ -1910,7 +1946,12 @@ func updateIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Iden
 			return err
 		}
 
-		apiIdentity, err := id.ToAPI(groups, certs)
+		config, err := dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
+		apiIdentity, err := id.ToAPI(groups, certs, config)
 		if err != nil {
 			return err
 		}
@@ -1926,6 +1967,11 @@ func updateIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Iden
 			return err
 		}
 
+		err = dbCluster.IdentitiesConfigStore().Set(ctx, tx.Tx(), id.ID, identityPut.Config)
+		if err != nil {
+			return err
+		}
+
 		if identityPut.TLSCertificate == "" || fingerprint == id.Identifier {
 			return nil
 		}
@@ -2066,7 +2112,7 @@ func identityPatch(authenticationMethod string) func(d *Daemon, r *http.Request)
 			return response.BadRequest(fmt.Errorf("Cannot update certificate for identities of type %q", id.Type))
 		}
 
-		if len(identityPut.Groups) == 0 && identityPut.TLSCertificate == "" {
+		if len(identityPut.Groups) == 0 && identityPut.TLSCertificate == "" && len(identityPut.Config) == 0 {
 			// Nothing to do
 			return response.EmptySyncResponse
 		}
@@ -2134,7 +2180,12 @@ func patchIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Ident
 			return err
 		}
 
-		apiIdentity, err := id.ToAPI(groups, certs)
+		config, err := dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
+		apiIdentity, err := id.ToAPI(groups, certs, config)
 		if err != nil {
 			return err
 		}
@@ -2152,6 +2203,22 @@ func patchIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Ident
 			}
 		}
 
+		// Merge the given configuration keys into the existing configuration.
+		if len(identityPut.Config) > 0 {
+			config := apiIdentity.Config
+			maps.Copy(config, identityPut.Config)
+
+			err = validateAPILimitsConfig(config)
+			if err != nil {
+				return api.StatusErrorf(http.StatusBadRequest, "%w", err)
+			}
+
+			err = dbCluster.IdentitiesConfigStore().Set(ctx, tx.Tx(), id.ID, config)
+			if err != nil {
+				return err
+			}
+		}
+
 		// Only update the certificate if it is given. Additionally, we don't need to update it if it's the same as the
 		// existing one.
 		if identityPut.TLSCertificate != "" && fingerprint != id.Identifier {
@@ -2177,7 +2244,7 @@ func patchIdentityPrivileged(s *state.State, r *http.Request, id dbCluster.Ident
 // patchSelfIdentityUnprivileged is only invoked when an identity of type api.IdentityTypeClientCertificate updates their
 // own identity and does not have permission to change their own groups.
 func patchSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluster.IdentitiesRow, identityPut api.IdentityPut) response.Response {
-	if len(identityPut.Groups) > 0 {
+	if len(identityPut.Groups) > 0 || len(identityPut.Config) > 0 {
 		return response.Forbidden(errors.New("Only the certificate may be changed"))
 	}
 
@@ -2212,7 +2279,12 @@ func patchSelfIdentityUnprivileged(s *state.State, r *http.Request, id dbCluster
 			return err
 		}
 
-		apiIdentity, err := id.ToAPI(groups, certs)
+		config, err := dbCluster.IdentitiesConfigStore().GetByEntityIDs(ctx, tx.Tx(), id.ID)
+		if err != nil {
+			return err
+		}
+
+		apiIdentity, err := id.ToAPI(groups, certs, config)
 		if err != nil {
 			return err
 		}
@@ -2311,7 +2383,7 @@ func identityDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
-	if !identityType.IsFineGrained() && identityType.Name() != api.IdentityTypeBearerTokenInitialUI {
+	if !identityType.IsFineGrained() && !slices.Contains([]string{api.IdentityTypeBearerTokenInitialUI, api.IdentityTypeBearerTokenSCIM}, identityType.Name()) {
 		return response.NotImplemented(fmt.Errorf("Identities of type %q cannot be modified via this API", id.Type))
 	}
 
@@ -2554,6 +2626,14 @@ func updateIdentityCache(d *Daemon) {
 	}
 
 	d.identityCache.ReplaceAll(serverCerts, clientCerts, metricsCerts, secrets, initialUITokenSecret)
+
+	// The API limits are configured on identities, groups and projects, and are reloaded along with the cache.
+	err = s.DB.Cluster.Transaction(d.shutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return d.apiLimits.load(ctx, tx)
+	})
+	if err != nil {
+		logger.Warn("Failed loading API limits", logger.Ctx{"err": err})
+	}
 }
 
 // updateIdentityCacheFromLocal loads trusted server certificates from local database into the identity cache.
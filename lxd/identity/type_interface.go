@@ -83,6 +83,9 @@ const (
 
 	// identityTypeCertificateClusterLinkPending represents cluster links for which a token has been issued but who have not yet authenticated with a linked LXD cluster.
 	identityTypeCertificateClusterLinkPending int64 = 13
+
+	// identityTypeBearerSCIM is the code for [api.IdentityTypeBearerTokenSCIM].
+	identityTypeBearerSCIM int64 = 14
 )
 
 // types is a slice of all identity types that implement the [Type] interface.
@@ -100,6 +103,7 @@ var types = []Type{
 	TokenBearerDevLXD{},
 	TokenBearerClient{},
 	TokenBearerInitialUI{},
+	TokenBearerSCIM{},
 }
 
 var nameToType = make(map[string]Type, len(types))
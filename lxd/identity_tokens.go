@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth/encryption"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// identityTokenSigningKeys returns the keys used to sign instance identity tokens, newest first.
+// Tokens are signed with the first key. All the keys are published so that tokens signed before a rotation of the
+// core auth secrets can still be verified.
+func identityTokenSigningKeys(ctx context.Context, s *state.State) ([]*ecdsa.PrivateKey, error) {
+	secrets, err := s.CoreAuthSecrets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, errors.New("No core auth secrets available")
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 0, len(secrets))
+	for _, secret := range secrets {
+		key, err := encryption.IdentityTokenSigningKey(secret.Value, s.GlobalConfig.ClusterUUID())
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// identityTokenJSONResponse writes a plain JSON document, as expected by OpenID Connect relying parties.
+func identityTokenJSONResponse(body any) response.Response {
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(http.StatusOK)
+
+		return util.WriteJSON(w, body, nil)
+	})
+}
+
+// swagger:operation GET /.well-known/openid-configuration server openid_configuration_get
+//
+//	Get the OpenID Connect discovery document
+//
+//	Returns the OpenID Connect discovery document of the instance identity token issuer.
+//	It is only available when `instances.identity_tokens.issuer` is set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: OpenID Connect discovery document
+//	    schema:
+//	      $ref: "#/definitions/OpenIDConfiguration"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+func openIDConfigurationGet(d *Daemon, r *http.Request) response.Response {
+	issuer := d.State().GlobalConfig.InstancesIdentityTokensIssuer()
+	if issuer == "" {
+		return response.NotFound(nil)
+	}
+
+	return identityTokenJSONResponse(api.OpenIDConfiguration{
+		Issuer:                           issuer,
+		JWKSURI:                          issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:           []string{"id_token"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: []string{encryption.IdentityTokenSigningAlgorithm},
+		ClaimsSupported:                  []string{"iss", "sub", "aud", "exp", "nbf", "iat", "jti", "cluster_uuid", "project", "instance", "instance_uuid", "instance_type", "user"},
+	})
+}
+
+// swagger:operation GET /.well-known/jwks.json server jwks_get
+//
+//	Get the instance identity token keys
+//
+//	Returns the JSON Web Key Set used to verify instance identity tokens.
+//	It is only available when `instances.identity_tokens.issuer` is set.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: JSON Web Key Set
+//	    schema:
+//	      $ref: "#/definitions/JSONWebKeySet"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func jwksGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	if s.GlobalConfig.InstancesIdentityTokensIssuer() == "" {
+		return response.NotFound(nil)
+	}
+
+	keys, err := identityTokenSigningKeys(r.Context(), s)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	jwks := api.JSONWebKeySet{Keys: make([]api.JSONWebKey, 0, len(keys))}
+	for _, key := range keys {
+		jwk, err := encryption.IdentityTokenJWK(&key.PublicKey)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		jwks.Keys = append(jwks.Keys, *jwk)
+	}
+
+	return identityTokenJSONResponse(jwks)
+}
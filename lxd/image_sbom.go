@@ -0,0 +1,499 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/archive"
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/locking"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/sbom"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+var imageSBOMCmd = APIEndpoint{
+	Path:            "images/{fingerprint}/sbom",
+	MetricsType:     entity.TypeImage,
+	ProjectSpecific: true,
+
+	Get:  APIEndpointAction{Handler: imageSBOMGet, AccessHandler: imageAccessHandler(auth.EntitlementCanView)},
+	Post: APIEndpointAction{Handler: imageSBOMPost, AccessHandler: imageAccessHandler(auth.EntitlementCanEdit)},
+}
+
+var instanceSBOMCmd = APIEndpoint{
+	Path:            "instances/{name}/sbom",
+	MetricsType:     entity.TypeInstance,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: instanceSBOMGet, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanAccessFiles, "name")},
+}
+
+// sbomFormat returns the SBOM format requested by the client, defaulting to SPDX.
+func sbomFormat(format string) (string, error) {
+	if format == "" {
+		return api.SBOMFormatSPDX, nil
+	}
+
+	if !slices.Contains([]string{api.SBOMFormatSPDX, api.SBOMFormatCycloneDX}, format) {
+		return "", api.StatusErrorf(http.StatusBadRequest, "Invalid SBOM format %q", format)
+	}
+
+	return format, nil
+}
+
+// sbomResponse returns an SBOM document as a raw JSON response.
+func sbomResponse(r *http.Request, format string, name string, modified time.Time, content []byte) response.Response {
+	contentType := "application/spdx+json"
+	if format == api.SBOMFormatCycloneDX {
+		contentType = "application/vnd.cyclonedx+json"
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		// Replace the Content-Type header pre-set by createCmd.
+		w.Header().Set("Content-Type", contentType)
+		http.ServeContent(w, r, name+"."+format+".json", modified, bytes.NewReader(content))
+		return nil
+	})
+}
+
+// imageSBOMPath returns the path of the SBOM of an image in the given format. It is prefixed by the fingerprint of
+// the image so that it gets cleaned up with the image files.
+func imageSBOMPath(imagePath string, format string) string {
+	return imagePath + ".sbom." + format + ".json"
+}
+
+// imageSBOMLock locks the SBOM files of an image.
+func imageSBOMLock(ctx context.Context, fingerprint string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, "ImageSBOM_"+fingerprint)
+}
+
+// imageSBOMLoad returns the SBOM of a locally available image in the given format and when it was generated. The
+// SBOM is generated and stored next to the image files the first time, or when refresh is true.
+func imageSBOMLoad(ctx context.Context, s *state.State, projectName string, img api.Image, format string, refresh bool) ([]byte, time.Time, error) {
+	unlock, err := imageSBOMLock(ctx, img.Fingerprint)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	defer unlock()
+
+	imagePath := filepath.Join(s.ImagesStoragePath(projectName), img.Fingerprint)
+	path := imageSBOMPath(imagePath, format)
+
+	if !refresh {
+		info, err := os.Stat(path)
+		if err == nil {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return nil, time.Time{}, err
+			}
+
+			return content, info.ModTime(), nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, err
+		}
+	}
+
+	inventory, err := imageSBOMScan(ctx, s, projectName, imagePath)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("Failed inspecting image %q: %w", img.Fingerprint, err)
+	}
+
+	now := time.Now()
+	content, err := sbom.Generate(inventory, format, img.Fingerprint, now)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = os.WriteFile(path+".tmp", content, 0600)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	err = os.Rename(path+".tmp", path)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return content, now, nil
+}
+
+// imageSBOMScan builds the inventory of the root filesystem of an image. Only the package databases are extracted
+// from the image, in a temporary directory.
+func imageSBOMScan(ctx context.Context, s *state.State, projectName string, imagePath string) (*sbom.Inventory, error) {
+	tmpDir, err := os.MkdirTemp(s.ImagesStoragePath(projectName), "lxd_sbom_")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	err = imageSBOMExtract(ctx, s, imagePath, tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := os.OpenRoot(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = root.Close() }()
+
+	return sbom.Scan(func(path string) (io.ReadCloser, error) {
+		// Links aren't extracted, the files they commonly point to are looked up too.
+		info, err := root.Lstat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil, fs.ErrNotExist
+		}
+
+		return root.Open(path)
+	})
+}
+
+// imageSBOMExtract extracts the files read to build the inventory of an image from its root filesystem into dir.
+// The root filesystem is either the rootfs file of a split image or the rootfs directory of a unified tarball.
+func imageSBOMExtract(ctx context.Context, s *state.State, imagePath string, dir string) error {
+	rootfsPath := imagePath + ".rootfs"
+	prefix := ""
+	if !shared.PathExists(rootfsPath) {
+		rootfsPath = imagePath
+		prefix = "rootfs/"
+	}
+
+	_, ext, unpacker, err := shared.DetectCompression(rootfsPath)
+	if err != nil {
+		return fmt.Errorf("Failed detecting compression of image root filesystem: %w", err)
+	}
+
+	if ext == ".squashfs" {
+		output, err := os.Open(dir)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = output.Close() }()
+
+		// Only the given paths are extracted, missing ones are skipped.
+		args := []string{"-f", "-d", dir, "-n", rootfsPath}
+		args = append(args, sbom.Paths...)
+
+		return archive.ExtractWithFds(s, "unsquashfs", args, nil, nil, output)
+	}
+
+	if !strings.HasPrefix(ext, ".tar") {
+		return api.StatusErrorf(http.StatusNotImplemented, "SBOM generation isn't supported for %q root filesystems", ext)
+	}
+
+	f, err := os.Open(rootfsPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	tr, cancelFunc, err := archive.CompressedTarReader(s, ctx, f, unpacker, dir)
+	if err != nil {
+		return err
+	}
+
+	defer cancelFunc()
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("Failed reading image root filesystem: %w", err)
+		}
+
+		name, found := strings.CutPrefix(strings.TrimPrefix(hdr.Name, "./"), prefix)
+		if !found || hdr.Typeflag != tar.TypeReg || !slices.Contains(sbom.Paths, name) {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+		if err != nil {
+			return err
+		}
+
+		target, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(target, tr)
+		_ = target.Close()
+		if err != nil {
+			return fmt.Errorf("Failed extracting %q from image root filesystem: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// swagger:operation GET /1.0/images/{fingerprint}/sbom images image_sbom_get
+//
+//	Get the software bill of materials of the image
+//
+//	Gets the software bill of materials (SBOM) listing the packages installed in the root filesystem of the image.
+//	The SBOM is generated the first time it is requested and stored along with the image files.
+//
+//	---
+//	produces:
+//	  - application/spdx+json
+//	  - application/vnd.cyclonedx+json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: format
+//	    description: SBOM format (spdx or cyclonedx)
+//	    type: string
+//	    example: spdx
+//	responses:
+//	  "200":
+//	     description: SBOM document
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSBOMGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	details, err := request.GetContextValue[imageDetails](r.Context(), ctxImageDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	format, err := sbomFormat(r.FormValue("format"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	img := details.image
+
+	err = ensureImageIsLocallyAvailable(r.Context(), s, &img, projectName)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	content, modified, err := imageSBOMLoad(r.Context(), s, projectName, img, format, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return sbomResponse(r, format, img.Fingerprint, modified, content)
+}
+
+// swagger:operation POST /1.0/images/{fingerprint}/sbom images image_sbom_post
+//
+//	Regenerate the software bill of materials of the image
+//
+//	Regenerates the stored software bill of materials (SBOM) of the image in the given format.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: sbom
+//	    description: SBOM request
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ImageSBOMPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSBOMPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	details, err := request.GetContextValue[imageDetails](r.Context(), ctxImageDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ImageSBOMPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	format, err := sbomFormat(req.Format)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	img := details.image
+	requestor := request.CreateRequestor(r.Context())
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		err := ensureImageIsLocallyAvailable(ctx, s, &img, projectName)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = imageSBOMLoad(ctx, s, projectName, img, format, true)
+		if err != nil {
+			return err
+		}
+
+		s.Events.SendLifecycle(projectName, lifecycle.ImageSBOMGenerated.Event(img.Fingerprint, projectName, requestor, logger.Ctx{"format": format}))
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "images", img.Fingerprint).Project(img.Project),
+		Type:        operationtype.ImageSBOMGenerate,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// swagger:operation GET /1.0/instances/{name}/sbom instances instance_sbom_get
+//
+//	Get the software bill of materials of the instance
+//
+//	Generates the software bill of materials (SBOM) listing the packages installed in the instance, by reading its
+//	package databases through the instance file access. Nothing is run inside of the instance.
+//
+//	---
+//	produces:
+//	  - application/spdx+json
+//	  - application/vnd.cyclonedx+json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: query
+//	    name: format
+//	    description: SBOM format (spdx or cyclonedx)
+//	    type: string
+//	    example: spdx
+//	responses:
+//	  "200":
+//	     description: SBOM document
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instanceSBOMGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	name := r.PathValue("name")
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	format, err := sbomFormat(r.FormValue("format"))
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Redirect to correct server if needed.
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	resp, err := forwardedResponseIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	defer func() { _ = client.Close() }()
+
+	inventory, err := sbom.Scan(func(path string) (io.ReadCloser, error) {
+		return client.Open("/" + path)
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed inspecting instance %q: %w", name, err))
+	}
+
+	now := time.Now()
+	content, err := sbom.Generate(inventory, format, name, now)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return sbomResponse(r, format, name, now, content)
+}
@@ -0,0 +1,421 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/auth/encryption"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/trust"
+	"github.com/canonical/lxd/shared/validate"
+)
+
+var imageSignaturesCmd = APIEndpoint{
+	Path:            "images/{fingerprint}/signatures",
+	MetricsType:     entity.TypeImage,
+	ProjectSpecific: true,
+
+	Post: APIEndpointAction{Handler: imageSignaturesPost, AccessHandler: imageAccessHandler(auth.EntitlementCanEdit)},
+}
+
+var imageSigningKeyCmd = APIEndpoint{
+	Path:        "image-signing-key",
+	MetricsType: entity.TypeServer,
+
+	Get:  APIEndpointAction{Handler: imageSigningKeyGet, AccessHandler: allowAuthenticated},
+	Post: APIEndpointAction{Handler: imageSigningKeyPost, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// imageSigningKeySecret returns the key material of the image signing key of the server. If no key exists yet, it is
+// created if create is true, and an [api.StatusError] with [http.StatusNotFound] is returned otherwise.
+func imageSigningKeySecret(ctx context.Context, s *state.State, create bool) (*dbCluster.AuthSecret, error) {
+	var secret *dbCluster.AuthSecret
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		secret, err = dbCluster.GetImageSigningKey(ctx, tx.Tx())
+		if create && api.StatusErrorCheck(err, http.StatusNotFound) {
+			secret, err = dbCluster.RotateImageSigningKey(ctx, tx.Tx())
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// imageSigningKeyFromSecret returns the image signing key derived from the given key material. The key is kept apart
+// from the certificate of the server, so that it can be rotated separately, and is the same on all cluster members.
+func imageSigningKeyFromSecret(s *state.State, secret *dbCluster.AuthSecret) (ed25519.PrivateKey, error) {
+	key, err := encryption.ImageSigningKey(secret.Value, s.GlobalConfig.ClusterUUID())
+	if err != nil {
+		return nil, fmt.Errorf("Failed deriving image signing key: %w", err)
+	}
+
+	return key, nil
+}
+
+// imageSigningKeyToAPI returns the public part of the image signing key derived from the given key material.
+func imageSigningKeyToAPI(s *state.State, secret *dbCluster.AuthSecret) (*api.ImageSigningKey, error) {
+	key, err := imageSigningKeyFromSecret(s, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprint, err := trust.KeyFingerprint(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding image signing key: %w", err)
+	}
+
+	return &api.ImageSigningKey{
+		Fingerprint: fingerprint,
+		PublicKey:   base64.StdEncoding.EncodeToString(publicKey),
+		CreatedAt:   secret.CreationDate,
+	}, nil
+}
+
+// imageSigningKeyFingerprintGet returns the fingerprint of the image signing key of the server, or an empty string if
+// no image has been signed by the server yet.
+func imageSigningKeyFingerprintGet(ctx context.Context, s *state.State) (string, error) {
+	secret, err := imageSigningKeySecret(ctx, s, false)
+	if err != nil {
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	key, err := imageSigningKeyToAPI(s, secret)
+	if err != nil {
+		return "", err
+	}
+
+	return key.Fingerprint, nil
+}
+
+// imageSignWithServerKey signs the image with the given fingerprint with the image signing key of the server and
+// stores the signature. The key is created on first use.
+func imageSignWithServerKey(ctx context.Context, s *state.State, fingerprint string) error {
+	secret, err := imageSigningKeySecret(ctx, s, true)
+	if err != nil {
+		return err
+	}
+
+	key, err := imageSigningKeyFromSecret(s, secret)
+	if err != nil {
+		return err
+	}
+
+	signature, err := trust.SignImage(key, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	return s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.CreateImageSignatures(ctx, tx.Tx(), fingerprint, []api.ImageSignature{*signature})
+	})
+}
+
+// imageSignCheck returns an error if the image requested by the given request can't be signed with the image
+// signing key of the server by the caller. Only images published from instances can be signed, as the server can't
+// vouch for the content of uploaded or copied images, and only by callers with the server-wide
+// [auth.EntitlementCanSignImages] entitlement, as the signatures apply to the image in all projects.
+func imageSignCheck(ctx context.Context, authorizer auth.Authorizer, req api.ImagesPost) error {
+	if !req.Sign {
+		return nil
+	}
+
+	if req.Source == nil || !slices.Contains([]api.SourceType{"container", "instance", "virtual-machine", "snapshot"}, req.Source.Type) {
+		return api.NewStatusError(http.StatusBadRequest, "Only images published from instances can be signed with the key of the server")
+	}
+
+	return authorizer.CheckPermission(ctx, entity.ServerURL(), auth.EntitlementCanSignImages)
+}
+
+// validateImageSigningKeyFingerprint validates the fingerprint of a key trusted to sign images.
+func validateImageSigningKeyFingerprint(value string) error {
+	if len(value) != 64 {
+		return errors.New("Key fingerprint must contain 64 characters")
+	}
+
+	return validate.IsLowercaseHex(value)
+}
+
+// verifyImageSignatures checks that all the given signatures are valid for the image with the given fingerprint.
+func verifyImageSignatures(fingerprint string, signatures []api.ImageSignature) error {
+	for _, signature := range signatures {
+		err := trust.VerifyImageSignature(signature, fingerprint)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Failed verifying signature of image %q: %w", fingerprint, err)
+		}
+	}
+
+	return nil
+}
+
+// imageSignaturePolicy is the policy of a project about the signatures of its images.
+type imageSignaturePolicy struct {
+	required    bool
+	trustedKeys []string
+}
+
+// newImageSignaturePolicy returns the image signature policy defined by the given project configuration.
+func newImageSignaturePolicy(projectConfig map[string]string) imageSignaturePolicy {
+	return imageSignaturePolicy{
+		required:    shared.IsTrue(projectConfig["images.require_signature"]),
+		trustedKeys: shared.SplitNTrimSpace(projectConfig["images.trusted_keys"], ",", -1, true),
+	}
+}
+
+// loadImageSignaturePolicy returns the image signature policy of the given project.
+func loadImageSignaturePolicy(ctx context.Context, s *state.State, projectName string) (imageSignaturePolicy, error) {
+	var projectConfig map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		projectConfig, err = dbCluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return imageSignaturePolicy{}, fmt.Errorf("Failed loading config for project %q: %w", projectName, err)
+	}
+
+	return newImageSignaturePolicy(projectConfig), nil
+}
+
+// check returns an error if the policy requires the image with the given fingerprint to be signed by a trusted key,
+// and none of the given signatures is.
+func (p imageSignaturePolicy) check(fingerprint string, signatures []api.ImageSignature) error {
+	if !p.required {
+		return nil
+	}
+
+	err := trust.VerifyImageTrusted(signatures, fingerprint, p.trustedKeys)
+	if err != nil {
+		return api.StatusErrorf(http.StatusForbidden, "Image %q is rejected by the signature policy of the project: %w", fingerprint, err)
+	}
+
+	return nil
+}
+
+// imageSignaturesFromHeader parses the signatures sent in the X-LXD-signatures header of an image upload.
+func imageSignaturesFromHeader(r *http.Request) ([]api.ImageSignature, error) {
+	header := r.Header.Get("X-LXD-signatures")
+	if header == "" {
+		return nil, nil
+	}
+
+	var signatures []api.ImageSignature
+	err := json.NewDecoder(strings.NewReader(header)).Decode(&signatures)
+	if err != nil {
+		return nil, api.StatusErrorf(http.StatusBadRequest, "Invalid image signatures: %w", err)
+	}
+
+	return signatures, nil
+}
+
+// swagger:operation POST /1.0/images/{fingerprint}/signatures images image_signatures_post
+//
+//	Add a signature to the image
+//
+//	Adds a signature made by the client.
+//	A signature made with the same key replaces the existing one.
+//	Images are signed with the key of the server when they are published from an instance.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: signature
+//	    description: Image signature
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/ImageSignaturesPost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSignaturesPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := request.ProjectParam(r)
+	details, err := request.GetContextValue[imageDetails](r.Context(), ctxImageDetails)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.ImageSignaturesPost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	fingerprint := details.image.Fingerprint
+
+	if req.Signature == nil {
+		return response.BadRequest(errors.New("No signature provided"))
+	}
+
+	err = verifyImageSignatures(fingerprint, []api.ImageSignature{*req.Signature})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.CreateImageSignatures(ctx, tx.Tx(), fingerprint, []api.ImageSignature{*req.Signature})
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	requestor := request.CreateRequestor(r.Context())
+	s.Events.SendLifecycle(projectName, lifecycle.ImageUpdated.Event(fingerprint, projectName, requestor, nil))
+
+	return response.EmptySyncResponse
+}
+
+// swagger:operation GET /1.0/image-signing-key images image_signing_key_get
+//
+//	Get the image signing key
+//
+//	Gets the public part of the key used by the server to sign images.
+//	The key is created when the server signs its first image.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: Image signing key
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ImageSigningKey"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "404":
+//	    $ref: "#/responses/NotFound"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSigningKeyGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	secret, err := imageSigningKeySecret(r.Context(), s, false)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	key, err := imageSigningKeyToAPI(s, secret)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, key)
+}
+
+// swagger:operation POST /1.0/image-signing-key images image_signing_key_post
+//
+//	Rotate the image signing key
+//
+//	Replaces the key used by the server to sign images with a new key.
+//	The existing signatures remain valid, but the projects that trust the previous key must be updated to trust the new key.
+//
+//	---
+//	produces:
+//	  - application/json
+//	responses:
+//	  "200":
+//	    description: New image signing key
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          $ref: "#/definitions/ImageSigningKey"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageSigningKeyPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	var secret *dbCluster.AuthSecret
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		secret, err = dbCluster.RotateImageSigningKey(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	key, err := imageSigningKeyToAPI(s, secret)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, key)
+}
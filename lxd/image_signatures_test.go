@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	authDrivers "github.com/canonical/lxd/lxd/auth/drivers"
+	"github.com/canonical/lxd/lxd/identity"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// imageSignTestContext returns a request context with a requestor built from the given arguments. TLS clients are
+// restricted to the default project.
+func imageSignTestContext(t *testing.T, args request.RequestorArgs) context.Context {
+	r := httptest.NewRequest(http.MethodPost, "/1.0/images", nil)
+	err := request.SetRequestor(r, func(ctx context.Context, authenticationMethod string, identifier string) (*request.RequestorHookResult, error) {
+		return &request.RequestorHookResult{
+			IdentityID:   1,
+			IdentityType: identity.CertificateClientRestricted{},
+			Projects:     []string{"default"},
+		}, nil
+	}, args)
+	require.NoError(t, err)
+
+	return r.Context()
+}
+
+func TestImageSignCheck(t *testing.T) {
+	authorizer, err := authDrivers.LoadAuthorizer(context.Background(), authDrivers.DriverTLS, logger.Log)
+	require.NoError(t, err)
+
+	admin := imageSignTestContext(t, request.RequestorArgs{Trusted: true, Username: "root", Protocol: request.ProtocolUnix})
+	restricted := imageSignTestContext(t, request.RequestorArgs{Trusted: true, Username: "restricted", Protocol: api.AuthenticationMethodTLS})
+
+	publish := api.ImagesPost{Sign: true, Source: &api.ImagesPostSource{Type: "instance", Name: "c1"}}
+	copied := api.ImagesPost{Sign: true, Source: &api.ImagesPostSource{Type: "image", Fingerprint: "abc"}}
+
+	// Images that aren't signed by the server aren't checked.
+	assert.NoError(t, imageSignCheck(restricted, authorizer, api.ImagesPost{Source: publish.Source}))
+
+	// Images published from instances can be signed by callers allowed to sign images.
+	assert.NoError(t, imageSignCheck(admin, authorizer, publish))
+
+	// A client restricted to a project can't get a server signature, even for an image of its project.
+	err = imageSignCheck(restricted, authorizer, publish)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+
+	// Only images published from instances can be signed.
+	err = imageSignCheck(admin, authorizer, copied)
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	err = imageSignCheck(admin, authorizer, api.ImagesPost{Sign: true})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusBadRequest))
+}
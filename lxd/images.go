@@ -198,6 +198,10 @@ func imageEndpointResolver(r *http.Request) *APIEndpoint {
 		return &imageSecretCmd
 	case "refresh":
 		return &imageRefreshCmd
+	case "signatures":
+		return &imageSignaturesCmd
+	case "sbom":
+		return &imageSBOMCmd
 	default:
 		return nil
 	}
@@ -205,7 +209,8 @@ func imageEndpointResolver(r *http.Request) *APIEndpoint {
 
 // imageSubCmd is a dispatcher endpoint registered as images/{path...} to avoid ServeMux pattern
 // conflicts between images/aliases/{name...} (alias names can contain escaped slashes) and
-// images/{fingerprint}/export, images/{fingerprint}/secret, images/{fingerprint}/refresh.
+// images/{fingerprint}/export, images/{fingerprint}/secret, images/{fingerprint}/refresh,
+// images/{fingerprint}/signatures, images/{fingerprint}/sbom.
 // It resolves the request path to the appropriate sub-endpoint.
 // If alias names are not escaped then the resolver will return nil (meaning 404).
 var imageSubCmd = APIEndpoint{
@@ -946,6 +951,34 @@ func getImgPostInfo(s *state.State, r *http.Request, builddir string, project st
 		return nil, err
 	}
 
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the signatures, unless the image is being synchronized between cluster members.
+	signatures, err := imageSignaturesFromHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !requestor.IsClusterNotification() {
+		err = verifyImageSignatures(info.Fingerprint, signatures)
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err := loadImageSignaturePolicy(r.Context(), s, project)
+		if err != nil {
+			return nil, err
+		}
+
+		err = policy.check(info.Fingerprint, signatures)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	unlock, err := imageOperationLock(info.Fingerprint)
 	if err != nil {
 		return nil, err
@@ -1070,11 +1103,6 @@ func getImgPostInfo(s *state.State, r *http.Request, builddir string, project st
 		return nil, err
 	}
 
-	requestor, err := request.GetRequestor(r.Context())
-	if err != nil {
-		return nil, err
-	}
-
 	if exists {
 		// Do not create a database entry if the request is coming from the internal
 		// cluster communications for image synchronization
@@ -1091,7 +1119,12 @@ func getImgPostInfo(s *state.State, r *http.Request, builddir string, project st
 	} else {
 		err = s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
 			// Create the database entry
-			return tx.CreateImage(ctx, project, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, profileIDs)
+			err := tx.CreateImage(ctx, project, info.Fingerprint, info.Filename, info.Size, info.Public, info.AutoUpdate, info.Architecture, info.CreatedAt, info.ExpiresAt, info.Properties, info.Type, profileIDs)
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.CreateImageSignatures(ctx, tx.Tx(), info.Fingerprint, signatures)
 		})
 		if err != nil {
 			return nil, err
@@ -1390,6 +1423,11 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Image download from client-specified URL is not supported"))
 	}
 
+	err = imageSignCheck(r.Context(), s.Authorizer, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	if !imageUpload && req.Source.Mode == "push" {
 		metadata := map[string]any{
 			"aliases":    req.Aliases,
@@ -1401,10 +1439,29 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		return createImageTokenResponse(s, r, dbProject.Name, req.Source.Fingerprint, metadata, operationtype.ImageUploadToken)
 	}
 
-	if !imageUpload && !slices.Contains([]api.SourceType{"container", "instance", "virtual-machine", "snapshot", "image"}, req.Source.Type) {
+	if !imageUpload && !slices.Contains([]api.SourceType{"container", "instance", "virtual-machine", "snapshot", "image", "build"}, req.Source.Type) {
 		return response.InternalError(errors.New("Invalid images JSON"))
 	}
 
+	// Image builds also create a temporary instance in the project.
+	var build *imageBuildSource
+	if !imageUpload && req.Source.Type == "build" {
+		err = s.Authorizer.CheckPermission(r.Context(), entity.ProjectURL(dbProject.Name), auth.EntitlementCanCreateInstances)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		err = validateImageBuildRecipe(req.Source.Build)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		build, err = imageBuildPrepare(r, s, dbProject.Name, req.Source.Build)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	if req.CompressionAlgorithm != "" {
 		err = validate.IsCompressionAlgorithm(req.CompressionAlgorithm)
 		if err != nil {
@@ -1475,6 +1532,9 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 
 				/* Processing image copy from remote */
 				info, err = imgPostRemoteInfo(ctx, s, req, op, profileProject, imageProject, budget, proxy)
+			case "build":
+				/* Processing image build from recipe */
+				info, err = imgPostBuildInfo(ctx, s, req, op, build, imageProject, builddir, budget)
 			default:
 				/* Processing image creation from container */
 				imagePublishLock.Lock()
@@ -1510,6 +1570,13 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 			return nil
 		}
 
+		if req.Sign {
+			err = imageSignWithServerKey(ctx, s, info.Fingerprint)
+			if err != nil {
+				return fmt.Errorf("Failed signing image: %w", err)
+			}
+		}
+
 		// Apply any provided alias
 		aliases, ok := imageMetadata["aliases"]
 		if ok {
@@ -1574,10 +1641,15 @@ func imagesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	opType := operationtype.ImageDownload
+	if build != nil {
+		opType = operationtype.ImageBuild
+	}
+
 	args := operations.OperationArgs{
 		ProjectName: dbProject.Name,
 		EntityURL:   api.NewURL().Path(version.APIVersion, "projects", dbProject.Name),
-		Type:        operationtype.ImageDownload,
+		Type:        opType,
 		Class:       operationtype.OperationClassTask,
 		Metadata:    metadata,
 		RunHook:     run,
@@ -3119,7 +3191,17 @@ func imageDelete(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// Deletions notified by other cluster members have already been approved on the member that received them.
+	var approvalExpiry time.Duration
+	if !requestor.IsClusterNotification() {
+		approvalExpiry, err = projectApprovalExpiry(r.Context(), s, projectName, operationApprovalImageDelete)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	var opCreator operations.OperationScheduler = func(s *state.State, args operations.OperationArgs) (*operations.Operation, error) {
+		args.ApprovalExpiry = approvalExpiry
 		return operations.ScheduleUserOperationFromRequest(s, r, args)
 	}
 
@@ -3315,6 +3397,32 @@ func imageDeleteFromDisk(daemonStorageVolume string, fingerprint string) error {
 		return fmt.Errorf("Failed deleting image file %q: %w", fname+".rootfs", err)
 	}
 
+	// Remove the files generated to serve the image over simplestreams.
+	simplestreamsFiles, err := filepath.Glob(fname + ".simplestreams*")
+	if err != nil {
+		return err
+	}
+
+	for _, simplestreamsFile := range simplestreamsFiles {
+		err = os.Remove(simplestreamsFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed deleting image file %q: %w", simplestreamsFile, err)
+		}
+	}
+
+	// Remove the generated software bills of materials.
+	sbomFiles, err := filepath.Glob(fname + ".sbom.*")
+	if err != nil {
+		return err
+	}
+
+	for _, sbomFile := range sbomFiles {
+		err = os.Remove(sbomFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed deleting image file %q: %w", sbomFile, err)
+		}
+	}
+
 	return nil
 }
 
@@ -4867,6 +4975,11 @@ func imageExportPost(d *Daemon, r *http.Request) response.Response {
 			},
 		}
 
+		// Only send the signatures to servers that can check them.
+		if remote.HasExtension("image_signatures") {
+			createArgs.Signatures = details.image.Signatures
+		}
+
 		if req.Project != "" {
 			remote = remote.UseProject(req.Project)
 		}
@@ -0,0 +1,493 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/osarch"
+)
+
+// imageBuildAgentTimeout is how long to wait for the LXD agent of a virtual machine used for an image build.
+const imageBuildAgentTimeout = 5 * time.Minute
+
+// imageBuildShutdownTimeout is how long the build instance is given to shut down cleanly before it gets stopped.
+const imageBuildShutdownTimeout = 2 * time.Minute
+
+// imageBuildSource holds everything resolved at request time that is needed to create the build instance.
+type imageBuildSource struct {
+	project  api.Project
+	profiles []api.Profile
+	image    *api.Image
+	imageRef string
+}
+
+// validateImageBuildRecipe checks an image build recipe and fills in its defaults.
+func validateImageBuildRecipe(recipe *api.ImageBuildRecipe) error {
+	if recipe == nil {
+		return errors.New("No build recipe provided")
+	}
+
+	if recipe.Source.Type == "" {
+		recipe.Source.Type = api.SourceTypeImage
+	}
+
+	if recipe.Source.Type != api.SourceTypeImage {
+		return fmt.Errorf("Invalid build source type %q, only %q is supported", recipe.Source.Type, api.SourceTypeImage)
+	}
+
+	if recipe.Source.Alias == "" && recipe.Source.Fingerprint == "" && len(recipe.Source.Properties) == 0 {
+		return errors.New("No base image provided")
+	}
+
+	if recipe.Type == "" {
+		recipe.Type = api.InstanceTypeContainer
+	}
+
+	if recipe.Type != api.InstanceTypeContainer && recipe.Type != api.InstanceTypeVM {
+		return fmt.Errorf("Invalid build instance type %q", recipe.Type)
+	}
+
+	for _, file := range recipe.Files {
+		if !filepath.IsAbs(file.Path) {
+			return fmt.Errorf("File path %q must be absolute", file.Path)
+		}
+
+		if file.Mode != "" {
+			_, err := strconv.ParseUint(file.Mode, 8, 32)
+			if err != nil {
+				return fmt.Errorf("Invalid mode %q for file %q: %w", file.Mode, file.Path, err)
+			}
+		}
+
+		if file.UID < 0 || file.GID < 0 {
+			return fmt.Errorf("Invalid ownership for file %q", file.Path)
+		}
+	}
+
+	for _, command := range append(recipe.Commands, recipe.Cleanup...) {
+		if strings.TrimSpace(command) == "" {
+			return errors.New("Build commands cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// imageBuildPrepare resolves the base image and profiles of an image build and checks that the project allows
+// the creation of the build instance.
+func imageBuildPrepare(r *http.Request, s *state.State, projectName string, recipe *api.ImageBuildRecipe) (*imageBuildSource, error) {
+	build := &imageBuildSource{}
+
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProject, err := dbCluster.GetProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return fmt.Errorf("Failed loading project %q: %w", projectName, err)
+		}
+
+		p, err := dbProject.ToAPI(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		build.project = *p
+
+		build.image, err = resolveSourceImageFromCache(r, s, tx, projectName, recipe.Source, &build.imageRef, string(recipe.Type))
+		if err != nil {
+			return err
+		}
+
+		// Use the profiles of the base image if known, same as when creating an instance from it.
+		profileNames := recipe.Profiles
+		if profileNames == nil && build.image != nil {
+			profileNames = build.image.Profiles
+		}
+
+		if profileNames == nil {
+			profileNames = []string{"default"}
+		}
+
+		build.profiles = make([]api.Profile, 0, len(profileNames))
+		if len(profileNames) > 0 {
+			build.profiles, err = instanceProfilesFromNames(ctx, tx, project.ProfileProjectFromRecord(p), profileNames)
+			if err != nil {
+				return err
+			}
+		}
+
+		restrictions, err := limits.FetchProject(ctx, tx, projectName, true)
+		if err != nil {
+			return err
+		}
+
+		if restrictions != nil {
+			instReq := api.InstancesPost{
+				InstancePut: api.InstancePut{
+					Config:   recipe.Config,
+					Profiles: profileNames,
+				},
+				Name:   "image-build",
+				Type:   recipe.Type,
+				Source: recipe.Source,
+			}
+
+			err = limits.AllowInstanceCreation(s.GlobalConfig, *restrictions, instReq)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if build.image == nil && recipe.Source.Server == "" {
+		return nil, api.StatusErrorf(http.StatusNotFound, "Base image not found")
+	}
+
+	return build, nil
+}
+
+// imgPostBuildInfo runs the steps of an image build recipe in a temporary instance and publishes the result.
+// The output of the build commands is streamed through the operation metadata.
+func imgPostBuildInfo(ctx context.Context, s *state.State, req api.ImagesPost, op *operations.Operation, build *imageBuildSource, imageProject string, builddir string, budget int64) (*api.Image, error) {
+	recipe := req.Source.Build
+
+	if s.DB.Cluster.LocalNodeIsEvacuated() {
+		return nil, errors.New("Cluster member is evacuated")
+	}
+
+	img := build.image
+	var err error
+	if recipe.Source.Server != "" {
+		img, err = ensureDownloadedImageFitWithinBudget(ctx, s, op, build.project, build.imageRef, recipe.Source, string(recipe.Type))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		err = ensureImageIsLocallyAvailable(ctx, s, img, build.project.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = newImageSignaturePolicy(build.project.Config).check(img.Fingerprint, img.Signatures)
+	if err != nil {
+		return nil, err
+	}
+
+	dbType, err := instancetype.New(string(recipe.Type))
+	if err != nil {
+		return nil, err
+	}
+
+	architecture, err := osarch.ArchitectureId(img.Architecture)
+	if err != nil {
+		return nil, err
+	}
+
+	// The random suffix avoids clashing with existing instances.
+	suffix, err := shared.RandomCryptoString()
+	if err != nil {
+		return nil, err
+	}
+
+	args := db.InstanceArgs{
+		Project:      build.project.Name,
+		Architecture: architecture,
+		Config:       recipe.Config,
+		Type:         dbType,
+		Description:  "Temporary instance for image build",
+		Devices:      deviceConfig.ApplyDeviceInitialValues(deviceConfig.Devices{}, build.profiles),
+		Name:         "image-build-" + suffix[:12],
+		Profiles:     build.profiles,
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": args.Project, "instance": args.Name, "image": img.Fingerprint})
+	buildLog := &imageBuildLog{op: op}
+
+	buildLog.step("Creating build instance")
+
+	err = instanceCreateFromImage(ctx, s, img, args, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed creating build instance: %w", err)
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, args.Project, args.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// The build instance is never kept, whether the build succeeds or not.
+	// It isn't created as ephemeral as it needs to be stopped before being published.
+	defer func() {
+		if inst.IsRunning() {
+			_ = inst.Stop(context.Background(), false)
+		}
+
+		err := inst.Delete(context.Background(), true, "", nil)
+		if err != nil {
+			l.Warn("Failed deleting image build instance", logger.Ctx{"err": err})
+		}
+	}()
+
+	buildLog.step("Starting build instance")
+
+	err = inst.Start(ctx, false, op)
+	if err != nil {
+		return nil, fmt.Errorf("Failed starting build instance: %w", err)
+	}
+
+	err = imageBuildWaitReady(ctx, inst)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(recipe.Files) > 0 {
+		buildLog.step("Writing files")
+
+		err = imageBuildWriteFiles(inst, recipe.Files)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, command := range recipe.Commands {
+		buildLog.step(fmt.Sprintf("Running command %d/%d: %s", i+1, len(recipe.Commands), command))
+
+		err = imageBuildExec(ctx, inst, buildLog, recipe.Environment, command)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, command := range recipe.Cleanup {
+		buildLog.step(fmt.Sprintf("Running cleanup command %d/%d: %s", i+1, len(recipe.Cleanup), command))
+
+		err = imageBuildExec(ctx, inst, buildLog, recipe.Environment, command)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	buildLog.step("Stopping build instance")
+
+	err = inst.Shutdown(ctx, imageBuildShutdownTimeout)
+	if err != nil {
+		l.Warn("Failed shutting down image build instance, stopping it", logger.Ctx{"err": err})
+
+		err = inst.Stop(ctx, false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed stopping build instance: %w", err)
+		}
+	}
+
+	buildLog.step("Publishing image")
+
+	publishReq := req
+	publishReq.Source = &api.ImagesPostSource{
+		Type: "instance",
+		Name: inst.Name(),
+	}
+
+	imagePublishLock.Lock()
+	info, err := imgPostInstanceInfo(s, publishReq, op, args.Project, imageProject, builddir, budget)
+	imagePublishLock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	l.Info("Built image", logger.Ctx{"fingerprint": info.Fingerprint})
+
+	return info, nil
+}
+
+// imageBuildLog streams the progress of an image build through the operation metadata.
+// As the metadata of each event carries the last line, lines are numbered so that clients can skip repeated ones.
+type imageBuildLog struct {
+	op    *operations.Operation
+	lines int
+}
+
+// step records the current step of the build.
+func (l *imageBuildLog) step(step string) {
+	_ = l.op.ExtendMetadata(map[string]any{"build_step": step})
+}
+
+// line records a line of output of the build commands.
+func (l *imageBuildLog) line(line string) {
+	l.lines++
+	_ = l.op.ExtendMetadata(map[string]any{"build_log_line": line, "build_log_lines": l.lines})
+}
+
+// imageBuildWaitReady waits for a virtual machine used for an image build to be able to run commands.
+func imageBuildWaitReady(ctx context.Context, inst instance.Instance) error {
+	vm, ok := inst.(instance.VM)
+	if !ok {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, imageBuildAgentTimeout)
+	defer cancel()
+
+	for !vm.AgentStarted() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("LXD agent of the build instance didn't start within %s", imageBuildAgentTimeout)
+		case <-time.After(time.Second):
+		}
+	}
+
+	return nil
+}
+
+// imageBuildWriteFiles writes the files of an image build recipe into the build instance.
+func imageBuildWriteFiles(inst instance.Instance, files []api.ImageBuildFile) error {
+	client, err := inst.FileSFTP()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = client.Close() }()
+
+	for _, file := range files {
+		err = client.MkdirAll(filepath.Dir(file.Path))
+		if err != nil {
+			return fmt.Errorf("Failed creating parent directory of %q: %w", file.Path, err)
+		}
+
+		f, err := client.OpenFile(file.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return fmt.Errorf("Failed opening %q in build instance: %w", file.Path, err)
+		}
+
+		_, err = io.WriteString(f, file.Content)
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("Failed writing %q: %w", file.Path, err)
+		}
+
+		mode := uint64(0644)
+		if file.Mode != "" {
+			mode, err = strconv.ParseUint(file.Mode, 8, 32)
+			if err != nil {
+				_ = f.Close()
+				return err
+			}
+		}
+
+		err = f.Chmod(fs.FileMode(mode))
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("Failed setting mode of %q: %w", file.Path, err)
+		}
+
+		uid, gid := file.UID, file.GID
+
+		// For containers, make sure we are not trying to apply IDs outside of the allowed range.
+		c, ok := inst.(instance.Container)
+		if ok {
+			uid, gid, err = effectiveFileOwnership(c, &shared.LXDFileHeaders{UID: uid, GID: gid}, file.Path)
+			if err != nil {
+				_ = f.Close()
+				return err
+			}
+		}
+
+		err = f.Chown(int(uid), int(gid))
+		if err != nil {
+			_ = f.Close()
+			return fmt.Errorf("Failed setting ownership of %q: %w", file.Path, err)
+		}
+
+		err = f.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// imageBuildExec runs a build command in the build instance and streams its output through the operation
+// metadata, one line at a time. A non-zero exit status fails the build.
+func imageBuildExec(ctx context.Context, inst instance.Instance, buildLog *imageBuildLog, environment map[string]string, command string) error {
+	post := api.InstanceExecPost{
+		Command:     []string{"/bin/sh", "-c", command},
+		Environment: make(map[string]string, len(environment)),
+	}
+
+	maps.Copy(post.Environment, environment)
+
+	instanceExecEnvironment(inst, &post)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	logDone := make(chan struct{})
+	go func() {
+		defer close(logDone)
+
+		lines := bufio.NewReader(reader)
+		for {
+			line, err := lines.ReadString('\n')
+			if line != "" {
+				buildLog.line(strings.TrimRight(line, "\r\n"))
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	// Both output streams go to the same log.
+	cmd, err := inst.Exec(ctx, post, nil, writer, writer)
+	if err != nil {
+		_ = writer.Close()
+		<-logDone
+		return fmt.Errorf("Failed running %q: %w", command, err)
+	}
+
+	exitStatus, err := cmd.Wait()
+	_ = writer.Close()
+	<-logDone
+
+	if err != nil {
+		return fmt.Errorf("Failed running %q: %w", command, err)
+	}
+
+	if exitStatus != 0 {
+		return fmt.Errorf("Command %q exited with status %d", command, exitStatus)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestValidateImageBuildRecipe(t *testing.T) {
+	tests := []struct {
+		name    string
+		recipe  *api.ImageBuildRecipe
+		wantErr bool
+	}{
+		{
+			name:    "No recipe",
+			wantErr: true,
+		},
+		{
+			name:    "No base image",
+			recipe:  &api.ImageBuildRecipe{},
+			wantErr: true,
+		},
+		{
+			name:   "Base image alias",
+			recipe: &api.ImageBuildRecipe{Source: api.InstanceSource{Alias: "ubuntu/24.04"}},
+		},
+		{
+			name:    "Wrong source type",
+			recipe:  &api.ImageBuildRecipe{Source: api.InstanceSource{Type: api.SourceTypeCopy, Alias: "ubuntu/24.04"}},
+			wantErr: true,
+		},
+		{
+			name:    "Wrong instance type",
+			recipe:  &api.ImageBuildRecipe{Source: api.InstanceSource{Alias: "ubuntu/24.04"}, Type: api.InstanceType("lxc")},
+			wantErr: true,
+		},
+		{
+			name: "Files and commands",
+			recipe: &api.ImageBuildRecipe{
+				Source:   api.InstanceSource{Fingerprint: "abcdef"},
+				Type:     api.InstanceTypeVM,
+				Files:    []api.ImageBuildFile{{Path: "/etc/motd", Content: "Welcome", Mode: "0600", UID: 1000, GID: 1000}},
+				Commands: []string{"apt-get update"},
+				Cleanup:  []string{"apt-get clean"},
+			},
+		},
+		{
+			name: "Relative file path",
+			recipe: &api.ImageBuildRecipe{
+				Source: api.InstanceSource{Alias: "ubuntu/24.04"},
+				Files:  []api.ImageBuildFile{{Path: "etc/motd"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Invalid file mode",
+			recipe: &api.ImageBuildRecipe{
+				Source: api.InstanceSource{Alias: "ubuntu/24.04"},
+				Files:  []api.ImageBuildFile{{Path: "/etc/motd", Mode: "0999"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Empty command",
+			recipe: &api.ImageBuildRecipe{
+				Source:  api.InstanceSource{Alias: "ubuntu/24.04"},
+				Cleanup: []string{" "},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImageBuildRecipe(tt.recipe)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, api.SourceTypeImage, tt.recipe.Source.Type)
+			assert.NotEmpty(t, tt.recipe.Type)
+		})
+	}
+}
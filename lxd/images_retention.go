@@ -0,0 +1,493 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/operations"
+	projectutils "github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+var imageRetentionCmd = APIEndpoint{
+	Path:            "images/retention",
+	MetricsType:     entity.TypeImage,
+	ProjectSpecific: true,
+
+	Get: APIEndpointAction{Handler: imageRetentionGet, AccessHandler: allowPermission(entity.TypeProject, auth.EntitlementCanViewImages)},
+}
+
+// Image retention grouping modes for the images.retention.group_by project setting.
+const (
+	imageRetentionGroupByProperties = "properties"
+	imageRetentionGroupByAlias      = "alias"
+)
+
+// imageRetentionPolicy represents the image retention settings of a project.
+type imageRetentionPolicy struct {
+	versions        int
+	groupBy         string
+	unaliasedExpiry time.Duration
+}
+
+// imageRetentionImage represents an image of a project as seen by the retention policy.
+type imageRetentionImage struct {
+	id           int
+	fingerprint  string
+	architecture string
+	uploadDate   time.Time
+	cached       bool
+	used         bool
+	properties   map[string]string
+
+	// Names of the aliases currently pointing at the image.
+	aliases []string
+
+	// Names of all aliases that ever pointed at the image.
+	aliasHistory []string
+}
+
+// newImageRetentionPolicy returns the image retention policy defined by the given project configuration.
+func newImageRetentionPolicy(config map[string]string) (*imageRetentionPolicy, error) {
+	policy := &imageRetentionPolicy{groupBy: imageRetentionGroupByProperties}
+
+	if config["images.retention.versions"] != "" {
+		versions, err := strconv.ParseUint(config["images.retention.versions"], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid images.retention.versions: %w", err)
+		}
+
+		policy.versions = int(versions)
+	}
+
+	if config["images.retention.group_by"] != "" {
+		policy.groupBy = config["images.retention.group_by"]
+	}
+
+	if config["images.retention.unaliased_expiry"] != "" {
+		days, err := strconv.ParseUint(config["images.retention.unaliased_expiry"], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid images.retention.unaliased_expiry: %w", err)
+		}
+
+		policy.unaliasedExpiry = time.Duration(days) * 24 * time.Hour
+	}
+
+	return policy, nil
+}
+
+// enabled returns whether the policy can delete any image.
+func (p *imageRetentionPolicy) enabled() bool {
+	return p.versions > 0 || p.unaliasedExpiry > 0
+}
+
+// groups returns the names of the version groups the image belongs to.
+func (p *imageRetentionPolicy) groups(image imageRetentionImage) []string {
+	if p.groupBy == imageRetentionGroupByAlias {
+		groups := make([]string, 0, len(image.aliasHistory))
+		for _, name := range image.aliasHistory {
+			groups = append(groups, fmt.Sprintf("alias %q", name))
+		}
+
+		return groups
+	}
+
+	// Images without any distribution information can't be told apart.
+	if image.properties["os"] == "" && image.properties["release"] == "" {
+		return nil
+	}
+
+	fields := []string{}
+	for _, key := range []string{"os", "release", "variant"} {
+		if image.properties[key] != "" {
+			fields = append(fields, key+"="+image.properties[key])
+		}
+	}
+
+	fields = append(fields, "architecture="+image.architecture)
+
+	return []string{strings.Join(fields, ", ")}
+}
+
+// candidates returns the images that the policy deletes, oldest first.
+// Cached images are left to images.remote_cache_expiry and images used by instances are never deleted.
+func (p *imageRetentionPolicy) candidates(projectName string, images []imageRetentionImage, now time.Time) []api.ImageRetentionCandidate {
+	local := make([]imageRetentionImage, 0, len(images))
+	for _, image := range images {
+		if !image.cached {
+			local = append(local, image)
+		}
+	}
+
+	// Sort the images newest first so that the first images of each group are the ones to keep.
+	slices.SortStableFunc(local, func(a imageRetentionImage, b imageRetentionImage) int {
+		return b.uploadDate.Compare(a.uploadDate)
+	})
+
+	reasons := map[string]string{}
+
+	if p.versions > 0 {
+		groupSizes := map[string]int{}
+		kept := map[string]bool{}
+		exceeded := map[string]string{}
+
+		for _, image := range local {
+			for _, group := range p.groups(image) {
+				groupSizes[group]++
+				if groupSizes[group] <= p.versions {
+					kept[image.fingerprint] = true
+				} else if exceeded[image.fingerprint] == "" {
+					exceeded[image.fingerprint] = group
+				}
+			}
+
+			// The current target of an alias is always kept when grouping by alias.
+			if p.groupBy == imageRetentionGroupByAlias && len(image.aliases) > 0 {
+				kept[image.fingerprint] = true
+			}
+		}
+
+		for fingerprint, group := range exceeded {
+			if !kept[fingerprint] {
+				reasons[fingerprint] = fmt.Sprintf("Exceeds the %d versions kept for %s", p.versions, group)
+			}
+		}
+	}
+
+	if p.unaliasedExpiry > 0 {
+		days := int(p.unaliasedExpiry.Hours() / 24)
+
+		for _, image := range local {
+			if len(image.aliases) > 0 || reasons[image.fingerprint] != "" {
+				continue
+			}
+
+			if image.uploadDate.Add(p.unaliasedExpiry).Before(now) {
+				reasons[image.fingerprint] = fmt.Sprintf("Has no alias and was added more than %d days ago", days)
+			}
+		}
+	}
+
+	candidates := []api.ImageRetentionCandidate{}
+	for i := len(local) - 1; i >= 0; i-- {
+		image := local[i]
+		if image.used || reasons[image.fingerprint] == "" {
+			continue
+		}
+
+		candidates = append(candidates, api.ImageRetentionCandidate{
+			Fingerprint: image.fingerprint,
+			Project:     projectName,
+			UploadedAt:  image.uploadDate,
+			Reason:      reasons[image.fingerprint],
+		})
+	}
+
+	return candidates
+}
+
+// imageRetentionImages returns the images of the given project along with their alias history. The used map holds
+// the fingerprints of the images used by instances.
+func imageRetentionImages(ctx context.Context, tx *db.ClusterTx, projectName string, used map[string]bool) ([]imageRetentionImage, error) {
+	dbImages, err := dbCluster.GetImages(ctx, tx.Tx(), dbCluster.ImageFilter{Project: &projectName})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading images of project %q: %w", projectName, err)
+	}
+
+	history, err := dbCluster.GetImageAliasHistory(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	images := make([]imageRetentionImage, 0, len(dbImages))
+	for _, dbImage := range dbImages {
+		apiImage, err := dbImage.ToAPI(ctx, tx.Tx(), "")
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading image %q of project %q: %w", dbImage.Fingerprint, projectName, err)
+		}
+
+		image := imageRetentionImage{
+			id:           dbImage.ID,
+			fingerprint:  dbImage.Fingerprint,
+			architecture: apiImage.Architecture,
+			uploadDate:   dbImage.UploadDate,
+			cached:       dbImage.Cached,
+			used:         used[dbImage.Fingerprint],
+			properties:   apiImage.Properties,
+			aliasHistory: history[dbImage.ID],
+		}
+
+		for _, alias := range apiImage.Aliases {
+			image.aliases = append(image.aliases, alias.Name)
+		}
+
+		images = append(images, image)
+	}
+
+	return images, nil
+}
+
+// swagger:operation GET /1.0/images/retention images images_retention_get
+//
+//	Get the image retention report
+//
+//	Returns the images that the retention policy of the project deletes on its next run.
+//
+//	---
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	responses:
+//	  "200":
+//	    description: API endpoints
+//	    schema:
+//	      type: object
+//	      description: Sync response
+//	      properties:
+//	        type:
+//	          type: string
+//	          description: Response type
+//	          example: sync
+//	        status:
+//	          type: string
+//	          description: Status description
+//	          example: Success
+//	        status_code:
+//	          type: integer
+//	          description: Status code
+//	          example: 200
+//	        metadata:
+//	          type: array
+//	          description: List of images to be deleted
+//	          items:
+//	            $ref: "#/definitions/ImageRetentionCandidate"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func imageRetentionGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+	projectName := request.ProjectParam(r)
+
+	var candidates []api.ImageRetentionCandidate
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		effectiveProjectName, err := projectutils.ImageProject(ctx, tx.Tx(), projectName)
+		if err != nil {
+			return err
+		}
+
+		candidates, err = imageRetentionProjectCandidates(ctx, tx, effectiveProjectName, nil, time.Now())
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.SyncResponse(true, candidates)
+}
+
+// imageRetentionProjectCandidates returns the images of the given project that its retention policy deletes.
+// The used map holds the fingerprints of the images used by instances and is loaded when nil.
+func imageRetentionProjectCandidates(ctx context.Context, tx *db.ClusterTx, projectName string, used map[string]bool, now time.Time) ([]api.ImageRetentionCandidate, error) {
+	config, err := dbCluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading configuration of project %q: %w", projectName, err)
+	}
+
+	policy, err := newImageRetentionPolicy(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if !policy.enabled() {
+		return []api.ImageRetentionCandidate{}, nil
+	}
+
+	if used == nil {
+		used, err = dbCluster.GetImagesUsedByInstances(ctx, tx.Tx())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	images, err := imageRetentionImages(ctx, tx, projectName, used)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy.candidates(projectName, images, now), nil
+}
+
+// enforceImageRetention deletes the images that the retention policies of all projects with their own images
+// don't keep.
+func enforceImageRetention(ctx context.Context, s *state.State) error {
+	var candidates []api.ImageRetentionCandidate
+	imageIDs := map[string]map[string]int{}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		projectNames, err := dbCluster.GetProjectNames(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		used, err := dbCluster.GetImagesUsedByInstances(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, projectName := range projectNames {
+			hasImages, err := dbCluster.ProjectHasImages(ctx, tx.Tx(), projectName)
+			if err != nil {
+				return err
+			}
+
+			if !hasImages {
+				continue
+			}
+
+			projectCandidates, err := imageRetentionProjectCandidates(ctx, tx, projectName, used, now)
+			if err != nil {
+				return err
+			}
+
+			if len(projectCandidates) == 0 {
+				continue
+			}
+
+			images, err := dbCluster.GetImages(ctx, tx.Tx(), dbCluster.ImageFilter{Project: &projectName})
+			if err != nil {
+				return err
+			}
+
+			imageIDs[projectName] = make(map[string]int, len(images))
+			for _, image := range images {
+				imageIDs[projectName][image.Fingerprint] = image.ID
+			}
+
+			candidates = append(candidates, projectCandidates...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed evaluating image retention policies: %w", err)
+	}
+
+	// A failure to delete an image doesn't stop the deletion of the other candidates.
+	var errs []error
+	for _, candidate := range candidates {
+		// It is safe to stop here as the remaining images are deleted on the next run.
+		if ctx.Err() != nil {
+			break
+		}
+
+		l := logger.AddContext(logger.Ctx{"fingerprint": candidate.Fingerprint, "project": candidate.Project, "reason": candidate.Reason})
+
+		// An instance may have been created from the image since the candidates were evaluated.
+		var used map[string]bool
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			used, err = dbCluster.GetImagesUsedByInstances(ctx, tx.Tx())
+			return err
+		})
+		if err != nil {
+			l.Error("Failed checking whether image is used by instances", logger.Ctx{"err": err})
+			errs = append(errs, fmt.Errorf("Failed checking whether image %q in project %q is used by instances: %w", candidate.Fingerprint, candidate.Project, err))
+			continue
+		}
+
+		if used[candidate.Fingerprint] {
+			l.Info("Skipped deleting image by retention policy as it is now used by instances")
+			continue
+		}
+
+		op, err := doImageDelete(false, operations.ScheduleServerOperation, s, candidate.Fingerprint, imageIDs[candidate.Project][candidate.Fingerprint], candidate.Project, candidate.Project)
+		if err != nil {
+			l.Error("Failed creating image delete operation", logger.Ctx{"err": err})
+			errs = append(errs, fmt.Errorf("Failed creating delete operation for image %q in project %q: %w", candidate.Fingerprint, candidate.Project, err))
+			continue
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			l.Error("Failed deleting image by retention policy", logger.Ctx{"err": err})
+			errs = append(errs, fmt.Errorf("Failed deleting image %q in project %q: %w", candidate.Fingerprint, candidate.Project, err))
+			continue
+		}
+
+		l.Info("Deleted image by retention policy")
+	}
+
+	return errors.Join(errs...)
+}
+
+func imageRetentionTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil {
+			logger.Error("Failed getting leader cluster member address", logger.Ctx{"err": err})
+			return
+		}
+
+		// Only one member of a cluster enforces the retention policies.
+		if leaderInfo.Clustered && !leaderInfo.Leader {
+			logger.Debug("Skipping image retention task since we're not leader")
+			return
+		}
+
+		opRun := func(ctx context.Context, op *operations.Operation) error {
+			return enforceImageRetention(ctx, s)
+		}
+
+		args := operations.OperationArgs{
+			Type:    operationtype.ImagesRetention,
+			Class:   operationtype.OperationClassTask,
+			RunHook: opRun,
+		}
+
+		logger.Debug("Acquiring image task lock")
+		imageTaskMu.Lock()
+		defer imageTaskMu.Unlock()
+		logger.Debug("Acquired image task lock")
+
+		logger.Info("Enforcing image retention policies")
+		op, err := operations.ScheduleServerOperation(s, args)
+		if err != nil {
+			logger.Error("Failed creating image retention operation", logger.Ctx{"err": err})
+			return
+		}
+
+		err = op.Wait(ctx)
+		if err != nil {
+			logger.Error("Failed enforcing image retention policies", logger.Ctx{"err": err})
+			return
+		}
+
+		logger.Info("Done enforcing image retention policies")
+	}
+
+	return f, task.Daily()
+}
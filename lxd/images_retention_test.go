@@ -0,0 +1,87 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageRetentionPolicy(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	nightly := func(fingerprint string, age int, aliases ...string) imageRetentionImage {
+		return imageRetentionImage{
+			fingerprint:  fingerprint,
+			architecture: "x86_64",
+			uploadDate:   now.Add(-time.Duration(age) * day),
+			properties:   map[string]string{"os": "Ubuntu", "release": "noble", "variant": "ci"},
+			aliases:      aliases,
+			aliasHistory: []string{"nightly"},
+		}
+	}
+
+	images := []imageRetentionImage{
+		nightly("a", 0, "nightly"),
+		nightly("b", 1),
+		nightly("c", 2),
+		nightly("d", 3),
+		nightly("e", 4),
+		{fingerprint: "f", architecture: "x86_64", uploadDate: now.Add(-40 * day)},
+		{fingerprint: "g", architecture: "x86_64", uploadDate: now.Add(-40 * day), aliases: []string{"golden"}, aliasHistory: []string{"golden"}},
+		{fingerprint: "h", architecture: "x86_64", uploadDate: now.Add(-40 * day), cached: true},
+	}
+
+	// Image "d" is used by an instance.
+	images[3].used = true
+
+	fingerprints := func(config map[string]string) []string {
+		policy, err := newImageRetentionPolicy(config)
+		require.NoError(t, err)
+
+		result := []string{}
+		for _, candidate := range policy.candidates("default", images, now) {
+			assert.Equal(t, "default", candidate.Project)
+			result = append(result, candidate.Fingerprint)
+		}
+
+		return result
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		policy, err := newImageRetentionPolicy(map[string]string{})
+		require.NoError(t, err)
+		assert.False(t, policy.enabled())
+		assert.Empty(t, fingerprints(map[string]string{}))
+	})
+
+	t.Run("versions by properties", func(t *testing.T) {
+		assert.Equal(t, []string{"e", "c"}, fingerprints(map[string]string{"images.retention.versions": "2"}))
+	})
+
+	t.Run("versions by alias", func(t *testing.T) {
+		config := map[string]string{"images.retention.versions": "1", "images.retention.group_by": "alias"}
+		assert.Equal(t, []string{"e", "c", "b"}, fingerprints(config))
+	})
+
+	t.Run("unaliased expiry", func(t *testing.T) {
+		assert.Equal(t, []string{"f", "e"}, fingerprints(map[string]string{"images.retention.unaliased_expiry": "2"}))
+	})
+
+	t.Run("reasons", func(t *testing.T) {
+		policy, err := newImageRetentionPolicy(map[string]string{"images.retention.versions": "4", "images.retention.unaliased_expiry": "30"})
+		require.NoError(t, err)
+
+		candidates := policy.candidates("default", images, now)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, "Has no alias and was added more than 30 days ago", candidates[0].Reason)
+		assert.Equal(t, "Exceeds the 4 versions kept for os=Ubuntu, release=noble, variant=ci, architecture=x86_64", candidates[1].Reason)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := newImageRetentionPolicy(map[string]string{"images.retention.versions": "-1"})
+		assert.Error(t, err)
+	})
+}
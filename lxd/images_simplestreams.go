@@ -0,0 +1,759 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/locking"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/simplestreams"
+)
+
+// imageSimplestreamsCmd serves the published images of a project as a simplestreams tree.
+var imageSimplestreamsCmd = APIEndpoint{
+	Path: "simplestreams/{project}/{path...}",
+
+	Get: APIEndpointAction{Handler: imageSimplestreamsGet, AllowUntrusted: true},
+}
+
+// Paths of the simplestreams index and products files, relative to the root of the tree.
+const (
+	imageSimplestreamsIndexPath    = "streams/v1/index.json"
+	imageSimplestreamsProductsPath = "streams/v1/images.json"
+)
+
+// imageSimplestreamsVersionLayout is the layout of the version names, which simplestreams clients parse as the
+// creation date of the images.
+const imageSimplestreamsVersionLayout = "20060102_1504"
+
+// imageSimplestreamsFile describes a file of an image served in a simplestreams tree.
+type imageSimplestreamsFile struct {
+	Name     string `json:"name"`
+	FileType string `json:"ftype"`
+	Size     int64  `json:"size"`
+	Sha256   string `json:"sha256"`
+}
+
+// imageSimplestreamsFiles describes the files of an image served in a simplestreams tree.
+// It is cached next to the image files as those never change.
+type imageSimplestreamsFiles struct {
+	Meta imageSimplestreamsFile  `json:"meta"`
+	Root *imageSimplestreamsFile `json:"root,omitempty"`
+
+	// Deltas are keyed by the fingerprint of their base image.
+	Deltas map[string]imageSimplestreamsFile `json:"deltas,omitempty"`
+}
+
+// imageSimplestreamsEntry is an image published in a simplestreams tree along with its files.
+type imageSimplestreamsEntry struct {
+	image api.Image
+	files imageSimplestreamsFiles
+}
+
+// imageSimplestreamsGet serves the simplestreams index, products and image files of a project which publishes its
+// images over simplestreams.
+func imageSimplestreamsGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName := r.PathValue("project")
+	path := r.PathValue("path")
+
+	var projectConfig map[string]string
+	err := s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		projectConfig, err = dbCluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil && api.StatusErrorCheck(err, http.StatusNotFound) {
+		return response.NotFound(nil)
+	} else if err != nil {
+		return response.SmartError(err)
+	}
+
+	// Don't reveal the existence of projects which don't publish their images.
+	if shared.IsFalseOrEmpty(projectConfig["images.simplestreams.enabled"]) {
+		return response.NotFound(nil)
+	}
+
+	images, err := imageSimplestreamsPublished(r.Context(), s, projectName, projectConfig["images.simplestreams.property"])
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	switch path {
+	case imageSimplestreamsIndexPath, imageSimplestreamsProductsPath:
+		entries, err := imageSimplestreamsEntries(s, projectName, images)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		products := imageSimplestreamsProducts(entries)
+		if path == imageSimplestreamsProductsPath {
+			return imageSimplestreamsJSON(products)
+		}
+
+		return imageSimplestreamsJSON(imageSimplestreamsIndex(products))
+	}
+
+	// Image files are served under images/<fingerprint>/<name>.
+	fields := strings.Split(path, "/")
+	if len(fields) != 3 || fields[0] != "images" {
+		return response.NotFound(nil)
+	}
+
+	idx := slices.IndexFunc(images, func(img api.Image) bool { return img.Fingerprint == fields[1] })
+	if idx < 0 {
+		return response.NotFound(nil)
+	}
+
+	img := images[idx]
+	imagePath := filepath.Join(s.ImagesStoragePath(projectName), img.Fingerprint)
+
+	// Only the files that were prepared by the background task are served.
+	files, err := imageSimplestreamsReadFiles(imagePath)
+	if err != nil && errors.Is(err, os.ErrNotExist) {
+		return response.NotFound(nil)
+	} else if err != nil {
+		return response.SmartError(err)
+	}
+
+	var filePath string
+	if fields[2] == files.Meta.Name {
+		filePath = imagePath
+	} else if files.Root != nil && fields[2] == files.Root.Name {
+		filePath = imagePath + ".rootfs"
+	} else {
+		for base, delta := range files.Deltas {
+			if fields[2] == delta.Name {
+				filePath = imageSimplestreamsDeltaPath(imagePath, base)
+				break
+			}
+		}
+	}
+
+	if filePath == "" || !shared.PathExists(filePath) {
+		return response.NotFound(nil)
+	}
+
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+
+		defer func() { _ = f.Close() }()
+
+		// Replace the Content-Type header pre-set by createCmd.
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, fields[2], img.UploadedAt, f)
+		return nil
+	})
+}
+
+// validateImageSimplestreamsProperty validates the property selecting the images served over simplestreams.
+func validateImageSimplestreamsProperty(value string) error {
+	key, _, found := strings.Cut(value, "=")
+	if !found || key == "" {
+		return errors.New("Property must be in the key=value form")
+	}
+
+	return nil
+}
+
+// imageSimplestreamsJSON returns a response rendering the given simplestreams data as is.
+func imageSimplestreamsJSON(data any) response.Response {
+	return response.ManualResponse(func(w http.ResponseWriter) error {
+		w.Header().Set("Content-Type", "application/json")
+		return util.WriteJSON(w, data, nil)
+	})
+}
+
+// imageSimplestreamsPublished returns the images published over simplestreams by a project. Those are the images
+// with the given property (in the key=value form) if set, or the public images otherwise.
+func imageSimplestreamsPublished(ctx context.Context, s *state.State, projectName string, property string) ([]api.Image, error) {
+	propertyKey, propertyValue, filterProperty := strings.Cut(property, "=")
+
+	var images []api.Image
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		fingerprints, err := tx.GetImagesFingerprints(ctx, projectName, !filterProperty)
+		if err != nil {
+			return err
+		}
+
+		for _, fingerprint := range fingerprints {
+			image, err := doImageGet(ctx, tx, projectName, fingerprint, false)
+			if err != nil {
+				return err
+			}
+
+			if filterProperty && image.Properties[propertyKey] != propertyValue {
+				continue
+			}
+
+			images = append(images, *image)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading published images: %w", err)
+	}
+
+	return images, nil
+}
+
+// imageSimplestreamsEntries returns the published images along with their files. Images whose files weren't prepared
+// yet on the local member are left out.
+func imageSimplestreamsEntries(s *state.State, projectName string, images []api.Image) ([]imageSimplestreamsEntry, error) {
+	entries := make([]imageSimplestreamsEntry, 0, len(images))
+	for _, img := range images {
+		files, err := imageSimplestreamsReadFiles(filepath.Join(s.ImagesStoragePath(projectName), img.Fingerprint))
+		if err != nil && errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, imageSimplestreamsEntry{image: img, files: *files})
+	}
+
+	return entries, nil
+}
+
+// imageSimplestreamsTask returns a task preparing the files of the images published over simplestreams.
+func imageSimplestreamsTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := imageSimplestreamsRefresh(ctx, stateFunc())
+		if err != nil {
+			logger.Error("Failed preparing simplestreams images", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Minute)
+}
+
+// imageSimplestreamsEventHandler returns an event handler resetting the given task when the images published over
+// simplestreams may have changed, so that their files are prepared right away.
+func imageSimplestreamsEventHandler(t *task.Task) events.EventHandler {
+	return func(event api.Event) {
+		if event.Type != api.EventTypeLifecycle {
+			return
+		}
+
+		lifecycleEvent := api.EventLifecycle{}
+		err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+		if err != nil {
+			return
+		}
+
+		switch lifecycleEvent.Action {
+		case api.EventLifecycleImageCreated, api.EventLifecycleImageRefreshed, api.EventLifecycleImageUpdated, api.EventLifecycleProjectUpdated:
+			t.Reset()
+		}
+	}
+}
+
+// imageSimplestreamsRefresh prepares the files of the images published over simplestreams by all projects.
+func imageSimplestreamsRefresh(ctx context.Context, s *state.State) error {
+	var projectConfigs map[string]map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		projectConfigs, err = dbCluster.GetProjectsConfigByKeyPrefix(ctx, tx.Tx(), "images.simplestreams.")
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading project configuration: %w", err)
+	}
+
+	for projectName, config := range projectConfigs {
+		if shared.IsFalseOrEmpty(config["images.simplestreams.enabled"]) {
+			continue
+		}
+
+		images, err := imageSimplestreamsPublished(ctx, s, projectName, config["images.simplestreams.property"])
+		if err != nil {
+			return err
+		}
+
+		deltas, _ := strconv.Atoi(config["images.simplestreams.deltas"])
+
+		err = imageSimplestreamsPrepare(ctx, s, projectName, images, deltas)
+		if err != nil {
+			logger.Warn("Failed preparing simplestreams images", logger.Ctx{"project": projectName, "err": err})
+		}
+	}
+
+	return nil
+}
+
+// imageSimplestreamsPrepare ensures the published images are available on the local member, and hashes their files.
+// Up to the given number of delta files are generated for each image, from the previous versions of its product.
+func imageSimplestreamsPrepare(ctx context.Context, s *state.State, projectName string, images []api.Image, deltas int) error {
+	files := make(map[string]*imageSimplestreamsFiles, len(images))
+	for _, img := range images {
+		err := ensureImageIsLocallyAvailable(ctx, s, &img, projectName)
+		if err != nil {
+			return err
+		}
+
+		files[img.Fingerprint], err = imageSimplestreamsLoadFiles(ctx, s, projectName, img)
+		if err != nil {
+			return err
+		}
+	}
+
+	if deltas > 0 {
+		_, err := exec.LookPath("xdelta3")
+		if err != nil {
+			logger.Warn("Skipping generation of simplestreams delta files as xdelta3 isn't available", logger.Ctx{"project": projectName})
+			return nil
+		}
+	}
+
+	for _, versions := range imageSimplestreamsGroupProducts(images) {
+		for i, target := range versions {
+			targetFiles := files[target.Fingerprint]
+			if targetFiles.Root == nil || !slices.Contains([]string{"squashfs", "disk-kvm.img"}, targetFiles.Root.FileType) {
+				continue
+			}
+
+			for _, base := range versions[max(0, i-deltas):i] {
+				baseFiles := files[base.Fingerprint]
+				if baseFiles.Root == nil || baseFiles.Root.FileType != targetFiles.Root.FileType {
+					continue
+				}
+
+				_, err := imageSimplestreamsGenerateDelta(ctx, s, projectName, target.Fingerprint, base.Fingerprint)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// imageSimplestreamsFilesPath returns the path of the file caching the description of the simplestreams files of an
+// image. It is prefixed by the fingerprint of the image so that it gets cleaned up with the image files.
+func imageSimplestreamsFilesPath(imagePath string) string {
+	return imagePath + ".simplestreams.json"
+}
+
+// imageSimplestreamsDeltaPath returns the path of the delta file of an image from the given base image.
+func imageSimplestreamsDeltaPath(imagePath string, base string) string {
+	return imagePath + ".simplestreams.delta-" + base
+}
+
+// imageSimplestreamsLock locks the simplestreams files of an image.
+func imageSimplestreamsLock(ctx context.Context, fingerprint string) (locking.UnlockFunc, error) {
+	return locking.Lock(ctx, "ImageSimplestreams_"+fingerprint)
+}
+
+// imageSimplestreamsLoadFiles returns the simplestreams files of a locally available image, hashing them the first
+// time.
+func imageSimplestreamsLoadFiles(ctx context.Context, s *state.State, projectName string, img api.Image) (*imageSimplestreamsFiles, error) {
+	unlock, err := imageSimplestreamsLock(ctx, img.Fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	imagePath := filepath.Join(s.ImagesStoragePath(projectName), img.Fingerprint)
+
+	files, err := imageSimplestreamsReadFiles(imagePath)
+	if err == nil {
+		return files, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	files = &imageSimplestreamsFiles{}
+
+	_, metaExt, _, err := shared.DetectCompression(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("Failed detecting compression of image %q: %w", img.Fingerprint, err)
+	}
+
+	rootfsPath := imagePath + ".rootfs"
+	if shared.PathExists(rootfsPath) {
+		_, rootExt, _, err := shared.DetectCompression(rootfsPath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed detecting compression of image %q: %w", img.Fingerprint, err)
+		}
+
+		rootType := "root.tar.xz"
+		if img.Type == "virtual-machine" {
+			rootType = "disk-kvm.img"
+		} else if rootExt == ".squashfs" {
+			rootType = "squashfs"
+		}
+
+		files.Meta = imageSimplestreamsFile{Name: "meta-" + img.Fingerprint + metaExt, FileType: "lxd.tar.xz"}
+		files.Root = &imageSimplestreamsFile{Name: img.Fingerprint + rootExt, FileType: rootType}
+
+		files.Root.Size, files.Root.Sha256, err = imageSimplestreamsHashFile(rootfsPath)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		files.Meta = imageSimplestreamsFile{Name: img.Fingerprint + metaExt, FileType: "lxd_combined.tar.gz"}
+	}
+
+	files.Meta.Size, files.Meta.Sha256, err = imageSimplestreamsHashFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = imageSimplestreamsWriteFiles(imagePath, files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// imageSimplestreamsGenerateDelta generates the delta file of an image from a base image, if not done yet, and
+// returns the updated simplestreams files of the image.
+func imageSimplestreamsGenerateDelta(ctx context.Context, s *state.State, projectName string, fingerprint string, base string) (*imageSimplestreamsFiles, error) {
+	unlock, err := imageSimplestreamsLock(ctx, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	defer unlock()
+
+	imagesDir := s.ImagesStoragePath(projectName)
+	imagePath := filepath.Join(imagesDir, fingerprint)
+
+	files, err := imageSimplestreamsReadFiles(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	_, ok := files.Deltas[base]
+	if ok {
+		return files, nil
+	}
+
+	deltaPath := imageSimplestreamsDeltaPath(imagePath, base)
+	_, err = shared.RunCommand(ctx, "xdelta3", "-e", "-f", "-s", filepath.Join(imagesDir, base+".rootfs"), imagePath+".rootfs", deltaPath)
+	if err != nil {
+		_ = os.Remove(deltaPath)
+		return nil, fmt.Errorf("Failed generating delta of image %q from %q: %w", fingerprint, base, err)
+	}
+
+	delta := imageSimplestreamsFile{Name: "delta-" + base + ".vcdiff", FileType: files.Root.FileType + ".vcdiff"}
+	delta.Size, delta.Sha256, err = imageSimplestreamsHashFile(deltaPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if files.Deltas == nil {
+		files.Deltas = map[string]imageSimplestreamsFile{}
+	}
+
+	files.Deltas[base] = delta
+
+	err = imageSimplestreamsWriteFiles(imagePath, files)
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// imageSimplestreamsReadFiles reads the cached description of the simplestreams files of an image.
+func imageSimplestreamsReadFiles(imagePath string) (*imageSimplestreamsFiles, error) {
+	data, err := os.ReadFile(imageSimplestreamsFilesPath(imagePath))
+	if err != nil {
+		return nil, err
+	}
+
+	files := &imageSimplestreamsFiles{}
+	err = json.Unmarshal(data, files)
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing simplestreams files of image: %w", err)
+	}
+
+	return files, nil
+}
+
+// imageSimplestreamsWriteFiles caches the description of the simplestreams files of an image.
+func imageSimplestreamsWriteFiles(imagePath string, files *imageSimplestreamsFiles) error {
+	data, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+
+	path := imageSimplestreamsFilesPath(imagePath)
+	err = os.WriteFile(path+".tmp", data, 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// imageSimplestreamsHashFile returns the size and SHA-256 hash of a file.
+func imageSimplestreamsHashFile(path string) (int64, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return -1, "", err
+	}
+
+	defer func() { _ = f.Close() }()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return -1, "", fmt.Errorf("Failed hashing %q: %w", path, err)
+	}
+
+	return size, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// imageSimplestreamsArchitecture returns the simplestreams architecture name of an image.
+func imageSimplestreamsArchitecture(img api.Image) string {
+	architecture := img.Properties["architecture"]
+	if architecture == "" {
+		return img.Architecture
+	}
+
+	return architecture
+}
+
+// imageSimplestreamsProductName returns the name of the simplestreams product an image is a version of. Images with
+// the same operating system, release, variant, architecture and type are versions of the same product, while other
+// images are products on their own.
+func imageSimplestreamsProductName(img api.Image) string {
+	fields := []string{img.Properties["os"], img.Properties["release"]}
+	if fields[0] == "" || fields[1] == "" {
+		fields = []string{"image", img.Fingerprint}
+	} else if img.Properties["variant"] != "" {
+		fields = append(fields, img.Properties["variant"])
+	}
+
+	fields = append(fields, imageSimplestreamsArchitecture(img), img.Type)
+
+	return strings.ToLower(strings.Join(fields, ":"))
+}
+
+// imageSimplestreamsCreatedAt returns the creation date of an image, used to order the versions of a product.
+func imageSimplestreamsCreatedAt(img api.Image) time.Time {
+	if shared.TimeIsSet(img.CreatedAt) {
+		return img.CreatedAt.UTC()
+	}
+
+	return img.UploadedAt.UTC()
+}
+
+// imageSimplestreamsGroupProducts groups images by product, each sorted from the oldest to the newest version.
+func imageSimplestreamsGroupProducts(images []api.Image) map[string][]api.Image {
+	products := map[string][]api.Image{}
+	for _, img := range images {
+		name := imageSimplestreamsProductName(img)
+		products[name] = append(products[name], img)
+	}
+
+	for _, versions := range products {
+		slices.SortStableFunc(versions, func(a api.Image, b api.Image) int {
+			c := imageSimplestreamsCreatedAt(a).Compare(imageSimplestreamsCreatedAt(b))
+			if c != 0 {
+				return c
+			}
+
+			return strings.Compare(a.Fingerprint, b.Fingerprint)
+		})
+	}
+
+	return products
+}
+
+// imageSimplestreamsProducts builds the simplestreams products of the given images.
+func imageSimplestreamsProducts(entries []imageSimplestreamsEntry) simplestreams.Products {
+	images := make([]api.Image, 0, len(entries))
+	files := make(map[string]imageSimplestreamsFiles, len(entries))
+	for _, entry := range entries {
+		images = append(images, entry.image)
+		files[entry.image.Fingerprint] = entry.files
+	}
+
+	products := simplestreams.Products{
+		ContentID: "images",
+		DataType:  "image-downloads",
+		Format:    "products:1.0",
+		Products:  map[string]simplestreams.Product{},
+	}
+
+	var updated time.Time
+	for name, versions := range imageSimplestreamsGroupProducts(images) {
+		// Name the versions after their creation date, disambiguating versions created at the same time.
+		versionNames := make(map[string]string, len(versions))
+		used := map[string]bool{}
+		for _, img := range versions {
+			versionName := imageSimplestreamsCreatedAt(img).Format(imageSimplestreamsVersionLayout)
+			for i := 2; used[versionName]; i++ {
+				versionName = imageSimplestreamsCreatedAt(img).Format(imageSimplestreamsVersionLayout) + "_" + strconv.Itoa(i)
+			}
+
+			used[versionName] = true
+			versionNames[img.Fingerprint] = versionName
+
+			if img.UploadedAt.After(updated) {
+				updated = img.UploadedAt
+			}
+		}
+
+		// The product is described by its newest version.
+		latest := versions[len(versions)-1]
+		product := simplestreams.Product{
+			Architecture:    imageSimplestreamsArchitecture(latest),
+			OperatingSystem: latest.Properties["os"],
+			Release:         latest.Properties["release"],
+			ReleaseCodename: latest.ReleaseCodename,
+			ReleaseTitle:    latest.ReleaseTitle,
+			Variant:         latest.Properties["variant"],
+			Version:         latest.Properties["version"],
+			Versions:        make(map[string]simplestreams.ProductVersion, len(versions)),
+		}
+
+		if product.ReleaseTitle == "" {
+			product.ReleaseTitle = latest.Properties["release"]
+		}
+
+		if product.OperatingSystem == "" {
+			product.OperatingSystem = latest.Properties["description"]
+		}
+
+		for key, value := range latest.Properties {
+			requirement, ok := strings.CutPrefix(key, "requirements.")
+			if ok {
+				if product.Requirements == nil {
+					product.Requirements = map[string]string{}
+				}
+
+				product.Requirements[requirement] = value
+			}
+		}
+
+		var aliases []string
+		for _, img := range slices.Backward(versions) {
+			for _, alias := range img.Aliases {
+				if !slices.Contains(aliases, alias.Name) {
+					aliases = append(aliases, alias.Name)
+				}
+			}
+		}
+
+		product.Aliases = strings.Join(aliases, ",")
+
+		for _, img := range versions {
+			imageFiles := files[img.Fingerprint]
+			prefix := "images/" + img.Fingerprint + "/"
+
+			meta := simplestreams.ProductVersionItem{
+				FileType:   imageFiles.Meta.FileType,
+				Path:       prefix + imageFiles.Meta.Name,
+				HashSha256: imageFiles.Meta.Sha256,
+				Size:       imageFiles.Meta.Size,
+			}
+
+			items := map[string]simplestreams.ProductVersionItem{}
+			if imageFiles.Root != nil {
+				switch imageFiles.Root.FileType {
+				case "squashfs":
+					meta.LXDHashSha256SquashFs = img.Fingerprint
+				case "disk-kvm.img":
+					meta.LXDHashSha256DiskKvmImg = img.Fingerprint
+				default:
+					meta.LXDHashSha256RootXz = img.Fingerprint
+				}
+
+				items[imageFiles.Root.FileType] = simplestreams.ProductVersionItem{
+					FileType:   imageFiles.Root.FileType,
+					Path:       prefix + imageFiles.Root.Name,
+					HashSha256: imageFiles.Root.Sha256,
+					Size:       imageFiles.Root.Size,
+				}
+
+				for base, delta := range imageFiles.Deltas {
+					baseVersion, ok := versionNames[base]
+					if !ok {
+						continue
+					}
+
+					items["delta-"+baseVersion] = simplestreams.ProductVersionItem{
+						FileType:   delta.FileType,
+						Path:       prefix + delta.Name,
+						HashSha256: delta.Sha256,
+						Size:       delta.Size,
+						DeltaBase:  baseVersion,
+					}
+				}
+			}
+
+			items[meta.FileType] = meta
+
+			product.Versions[versionNames[img.Fingerprint]] = simplestreams.ProductVersion{
+				Items: items,
+				Label: img.Properties["label"],
+			}
+		}
+
+		products.Products[name] = product
+	}
+
+	if !updated.IsZero() {
+		products.Updated = updated.UTC().Format(time.RFC1123Z)
+	}
+
+	return products
+}
+
+// imageSimplestreamsIndex builds the simplestreams index referencing the given products.
+func imageSimplestreamsIndex(products simplestreams.Products) simplestreams.Stream {
+	names := make([]string, 0, len(products.Products))
+	for name := range products.Products {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return simplestreams.Stream{
+		Format:  "index:1.0",
+		Updated: products.Updated,
+		Index: map[string]simplestreams.StreamIndex{
+			products.ContentID: {
+				DataType: products.DataType,
+				Path:     imageSimplestreamsProductsPath,
+				Updated:  products.Updated,
+				Products: names,
+				Format:   products.Format,
+			},
+		},
+	}
+}
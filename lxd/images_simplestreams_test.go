@@ -0,0 +1,126 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestImageSimplestreamsProducts(t *testing.T) {
+	fingerprint := func(c string) string {
+		return strings.Repeat(c, 64)
+	}
+
+	newImage := func(fp string, imageType string, createdAt time.Time, aliases ...string) api.Image {
+		img := api.Image{
+			Fingerprint:  fp,
+			Architecture: "x86_64",
+			Type:         imageType,
+			CreatedAt:    createdAt,
+			UploadedAt:   createdAt,
+			Properties: map[string]string{
+				"os":           "Ubuntu",
+				"release":      "noble",
+				"architecture": "amd64",
+			},
+		}
+
+		for _, alias := range aliases {
+			img.Aliases = append(img.Aliases, api.ImageAlias{Name: alias})
+		}
+
+		return img
+	}
+
+	splitFiles := func(fp string, rootType string) imageSimplestreamsFiles {
+		return imageSimplestreamsFiles{
+			Meta: imageSimplestreamsFile{Name: "meta-" + fp + ".tar.xz", FileType: "lxd.tar.xz", Size: 1, Sha256: fingerprint("1")},
+			Root: &imageSimplestreamsFile{Name: fp + ".squashfs", FileType: rootType, Size: 2, Sha256: fingerprint("2")},
+		}
+	}
+
+	day1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	oldContainer := fingerprint("a")
+	newContainer := fingerprint("b")
+	vm := fingerprint("c")
+	unified := fingerprint("d")
+
+	newContainerFiles := splitFiles(newContainer, "squashfs")
+	newContainerFiles.Deltas = map[string]imageSimplestreamsFile{
+		oldContainer: {Name: "delta-" + oldContainer + ".vcdiff", FileType: "squashfs.vcdiff", Size: 3, Sha256: fingerprint("3")},
+	}
+
+	unifiedImage := newImage(unified, "container", day1, "custom")
+	unifiedImage.Properties = map[string]string{"description": "Custom image"}
+
+	entries := []imageSimplestreamsEntry{
+		{image: newImage(oldContainer, "container", day1), files: splitFiles(oldContainer, "squashfs")},
+		{image: newImage(newContainer, "container", day2, "ubuntu/noble"), files: newContainerFiles},
+		{image: newImage(vm, "virtual-machine", day2, "ubuntu/noble"), files: splitFiles(vm, "disk-kvm.img")},
+		{image: unifiedImage, files: imageSimplestreamsFiles{Meta: imageSimplestreamsFile{Name: unified + ".tar.gz", FileType: "lxd_combined.tar.gz", Size: 4, Sha256: unified}}},
+	}
+
+	products := imageSimplestreamsProducts(entries)
+	assert.Len(t, products.Products, 3)
+
+	index := imageSimplestreamsIndex(products)
+	require.Contains(t, index.Index, "images")
+	assert.Equal(t, imageSimplestreamsProductsPath, index.Index["images"].Path)
+	assert.Len(t, index.Index["images"].Products, 3)
+
+	// The generated products must be understood by the simplestreams client.
+	images, downloads := products.ToLXD()
+	assert.Len(t, images, 4)
+
+	types := map[string]string{}
+	for _, img := range images {
+		types[img.Fingerprint] = img.Type
+	}
+
+	assert.Equal(t, map[string]string{oldContainer: "container", newContainer: "container", vm: "virtual-machine", unified: "container"}, types)
+
+	// Split images have a metadata and a root file, and the newest container has a delta from the previous version.
+	require.Len(t, downloads[oldContainer], 2)
+	require.Len(t, downloads[vm], 2)
+	require.Len(t, downloads[newContainer], 3)
+	assert.Equal(t, "images/"+newContainer+"/meta-"+newContainer+".tar.xz", downloads[newContainer][0][0])
+	assert.Equal(t, "root.delta-"+oldContainer, downloads[newContainer][2][2])
+
+	// Unified images have a single file.
+	require.Len(t, downloads[unified], 1)
+	assert.Equal(t, "images/"+unified+"/"+unified+".tar.gz", downloads[unified][0][0])
+}
+
+type imageSimplestreamsTestSuite struct {
+	lxdTestSuite
+}
+
+// TestImageSimplestreamsEntries tests that only the images whose files were prepared are listed.
+func (suite *imageSimplestreamsTestSuite) TestImageSimplestreamsEntries() {
+	s := suite.d.State()
+
+	prepared := api.Image{Fingerprint: strings.Repeat("a", 64)}
+	pending := api.Image{Fingerprint: strings.Repeat("b", 64)}
+
+	files := &imageSimplestreamsFiles{Meta: imageSimplestreamsFile{Name: prepared.Fingerprint + ".tar.gz", FileType: "lxd_combined.tar.gz"}}
+	suite.Req.NoError(imageSimplestreamsWriteFiles(filepath.Join(s.ImagesStoragePath("default"), prepared.Fingerprint), files))
+
+	entries, err := imageSimplestreamsEntries(s, "default", []api.Image{prepared, pending})
+	suite.Req.NoError(err)
+	suite.Req.Len(entries, 1)
+	suite.Equal(prepared.Fingerprint, entries[0].image.Fingerprint)
+	suite.Equal(*files, entries[0].files)
+}
+
+func TestImageSimplestreamsTestSuite(t *testing.T) {
+	suite.Run(t, new(imageSimplestreamsTestSuite))
+}
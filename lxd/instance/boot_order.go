@@ -0,0 +1,149 @@
+package instance
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// BootDependencies returns the names of the instances listed in the boot.depends_on key of the given config.
+func BootDependencies(config map[string]string) []string {
+	return shared.SplitNTrimSpace(config["boot.depends_on"], ",", -1, true)
+}
+
+// ValidateBootDependencies checks that the given boot dependencies of the instance with the given name don't
+// introduce a dependency cycle. The others argument holds the boot dependencies of the other instances of the
+// project, keyed by instance name.
+func ValidateBootDependencies(name string, dependencies []string, others map[string][]string) error {
+	visited := make(map[string]bool, len(others))
+
+	var visit func(current []string, path []string) []string
+	visit = func(current []string, path []string) []string {
+		for _, dependency := range current {
+			if dependency == name {
+				return append(path, dependency)
+			}
+
+			if visited[dependency] {
+				continue
+			}
+
+			visited[dependency] = true
+
+			cycle := visit(others[dependency], append(path, dependency))
+			if cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	cycle := visit(dependencies, []string{name})
+	if cycle != nil {
+		return fmt.Errorf("Boot dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// bootNode represents an instance in the boot order computation.
+type bootNode struct {
+	project      string
+	name         string
+	priority     int
+	dependencies []string
+}
+
+// BootOrder returns the given instances in the order in which they should be started.
+// Instances are started after the instances listed in their boot.depends_on key and otherwise ordered by
+// boot.autostart.priority (highest first) and name. Dependencies on instances that aren't part of the given list
+// are ignored.
+func BootOrder(instances []Instance) []Instance {
+	nodes := make([]bootNode, 0, len(instances))
+	for _, inst := range instances {
+		config := inst.ExpandedConfig()
+		priority, _ := strconv.Atoi(config["boot.autostart.priority"])
+
+		nodes = append(nodes, bootNode{
+			project:      inst.Project().Name,
+			name:         inst.Name(),
+			priority:     priority,
+			dependencies: BootDependencies(config),
+		})
+	}
+
+	ordered := make([]Instance, 0, len(instances))
+	for _, i := range bootOrder(nodes) {
+		ordered = append(ordered, instances[i])
+	}
+
+	return ordered
+}
+
+// bootOrder returns the indexes of the given nodes in the order in which they should be started.
+func bootOrder(nodes []bootNode) []int {
+	indexes := make(map[string]int, len(nodes))
+	for i, node := range nodes {
+		indexes[node.project+"/"+node.name] = i
+	}
+
+	// Count the dependencies left to start for each node and record the reverse edges.
+	pending := make([]int, len(nodes))
+	dependents := make([][]int, len(nodes))
+	for i, node := range nodes {
+		for _, dependency := range node.dependencies {
+			j, ok := indexes[node.project+"/"+dependency]
+			if !ok || j == i {
+				continue
+			}
+
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	before := func(a int, b int) bool {
+		return cmp.Or(
+			cmp.Compare(nodes[b].priority, nodes[a].priority),
+			cmp.Compare(nodes[a].name, nodes[b].name),
+			cmp.Compare(nodes[a].project, nodes[b].project),
+		) < 0
+	}
+
+	done := make([]bool, len(nodes))
+	order := make([]int, 0, len(nodes))
+	for len(order) < len(nodes) {
+		next := -1
+		for i := range nodes {
+			if done[i] || pending[i] > 0 {
+				continue
+			}
+
+			if next < 0 || before(i, next) {
+				next = i
+			}
+		}
+
+		// Only nodes that are part of a dependency cycle are left, fall back to the priority order.
+		if next < 0 {
+			for i := range nodes {
+				if !done[i] && (next < 0 || before(i, next)) {
+					next = i
+				}
+			}
+		}
+
+		done[next] = true
+		order = append(order, next)
+
+		for _, dependent := range dependents[next] {
+			pending[dependent]--
+		}
+	}
+
+	return order
+}
@@ -0,0 +1,63 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBootDependencies(t *testing.T) {
+	others := map[string][]string{
+		"db":    nil,
+		"queue": {"db"},
+		"app":   {"queue", "db"},
+	}
+
+	assert.NoError(t, ValidateBootDependencies("web", []string{"app"}, others))
+	assert.NoError(t, ValidateBootDependencies("db", []string{"missing"}, others))
+
+	err := ValidateBootDependencies("db", []string{"app"}, others)
+	assert.EqualError(t, err, "Boot dependency cycle detected: db -> app -> queue -> db")
+
+	err = ValidateBootDependencies("db", []string{"db"}, others)
+	assert.EqualError(t, err, "Boot dependency cycle detected: db -> db")
+}
+
+func TestBootOrder(t *testing.T) {
+	names := func(nodes []bootNode, order []int) []string {
+		result := make([]string, 0, len(order))
+		for _, i := range order {
+			result = append(result, nodes[i].project+"/"+nodes[i].name)
+		}
+
+		return result
+	}
+
+	// Dependencies take precedence over the priority.
+	nodes := []bootNode{
+		{project: "default", name: "app", priority: 10, dependencies: []string{"queue"}},
+		{project: "default", name: "db"},
+		{project: "default", name: "queue", dependencies: []string{"db", "missing"}},
+		{project: "default", name: "web", priority: 5},
+	}
+
+	assert.Equal(t, []string{"default/web", "default/db", "default/queue", "default/app"}, names(nodes, bootOrder(nodes)))
+
+	// Dependencies are resolved within the same project only.
+	nodes = []bootNode{
+		{project: "p1", name: "app", priority: 10, dependencies: []string{"db"}},
+		{project: "p2", name: "db"},
+		{project: "p1", name: "db", priority: 1},
+	}
+
+	assert.Equal(t, []string{"p1/db", "p1/app", "p2/db"}, names(nodes, bootOrder(nodes)))
+
+	// Instances that are part of a cycle are started last, by priority.
+	nodes = []bootNode{
+		{project: "default", name: "a", dependencies: []string{"b"}},
+		{project: "default", name: "b", dependencies: []string{"a"}},
+		{project: "default", name: "c", priority: -1},
+	}
+
+	assert.Equal(t, []string{"default/c", "default/a", "default/b"}, names(nodes, bootOrder(nodes)))
+}
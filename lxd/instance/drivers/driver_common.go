@@ -980,6 +980,21 @@ func (d *common) getAttachedVolumes(inst instance.Instance) (attachedVolumes map
 	return attachedVolumes, nil
 }
 
+// snapshotApplicationConsistent returns whether snapshots of the running instance should quiesce the guest.
+func (d *common) snapshotApplicationConsistent() bool {
+	return d.expandedConfig["snapshots.consistency"] == "application"
+}
+
+// snapshotConsistencyTimeout returns how long the guest may stay quiesced for an application-consistent snapshot.
+func (d *common) snapshotConsistencyTimeout() time.Duration {
+	timeout, err := strconv.ParseUint(d.expandedConfig["snapshots.consistency.timeout"], 10, 32)
+	if err != nil || timeout == 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
 // snapshotCommon handles the common part of a snapshot.
 // It creates the DB record and snapshots the instance, derives expiry from
 // inst's "snapshots.expiry" if expiry is nil, mounts the instance to update
@@ -2316,6 +2331,35 @@ func (d *common) removeDiskDevices() error {
 	return nil
 }
 
+// validateBootDependencies checks that the boot.depends_on setting of the instance doesn't introduce a dependency
+// cycle among the instances of its project.
+func (d *common) validateBootDependencies() error {
+	dependencies := instance.BootDependencies(d.expandedConfig)
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	others := make(map[string][]string)
+	err := d.state.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		filter := dbCluster.InstanceFilter{Project: &d.project.Name}
+
+		return tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+			if inst.Name == d.name {
+				return nil
+			}
+
+			others[inst.Name] = instance.BootDependencies(instancetype.ExpandInstanceConfig(nil, inst.Config, inst.Profiles))
+
+			return nil
+		}, filter)
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading boot dependencies: %w", err)
+	}
+
+	return instance.ValidateBootDependencies(d.name, dependencies, others)
+}
+
 // validateConfig validates the configuration.
 func (d *common) validateConfig(allUpdatedDeviceKeys []string, addDevices deviceConfig.Devices, removeDevices deviceConfig.Devices, oldExpandedDevices deviceConfig.Devices, changedConfigKeys []string, oldExpandedConfig map[string]string, actionType instance.UpdateAction) error {
 	if shared.StringPrefixInSlice(deviceConfig.ConfigInitialPrefix, allUpdatedDeviceKeys) {
@@ -2348,6 +2392,13 @@ func (d *common) validateConfig(allUpdatedDeviceKeys []string, addDevices device
 		}
 	}
 
+	if slices.Contains(changedConfigKeys, "boot.depends_on") {
+		err := d.validateBootDependencies()
+		if err != nil {
+			return err
+		}
+	}
+
 	userRequested := d.isUserRequested(actionType)
 
 	if userRequested {
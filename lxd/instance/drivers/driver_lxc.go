@@ -28,6 +28,7 @@ import (
 
 	"github.com/flosch/pongo2"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	liblxc "github.com/lxc/go-lxc"
 	"github.com/pkg/sftp"
 	yaml "go.yaml.in/yaml/v2"
@@ -73,6 +74,14 @@ import (
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/termios"
 	"github.com/canonical/lxd/shared/units"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+// Container directories containing the executables run before and after taking application-consistent snapshots.
+// Those are the same directories as used by the lxd-agent in virtual machines.
+const (
+	snapshotPreHooksDir  = "/etc/lxd/pre-snapshot.d"
+	snapshotPostHooksDir = "/etc/lxd/post-snapshot.d"
 )
 
 // Helper functions.
@@ -171,6 +180,11 @@ func lxcCreate(ctx context.Context, s *state.State, args db.InstanceArgs, p api.
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = d.validateBootDependencies()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid config: %w", err)
+		}
 	}
 
 	_, rootDiskDevice, err := d.getRootDiskDevice()
@@ -3257,6 +3271,7 @@ func (d *lxc) renderState(statusCode api.StatusCode, hostInterfaces []net.Interf
 		// CPU and Memory are always included (can't be nil)
 		status.CPU = d.cpuState()
 		status.Memory = d.memoryState()
+		status.Pressure = d.pressureState()
 
 		// Network - conditionally fetch
 		if options.IncludeNetwork {
@@ -3292,7 +3307,58 @@ func (d *lxc) snapshot(ctx context.Context, name string, expiry *time.Time, disk
 	// Wait for any file operations to complete to have a more consistent snapshot.
 	d.StopForkFile(false)
 
-	return d.snapshotCommon(ctx, d, name, expiry, false, diskVolumesMode, progressReporter)
+	if !d.snapshotApplicationConsistent() || !d.IsRunning() {
+		return d.snapshotCommon(ctx, d, name, expiry, false, diskVolumesMode, progressReporter)
+	}
+
+	// Let the applications of the container flush their data to disk.
+	hooksCtx, cancel := context.WithTimeout(ctx, d.snapshotConsistencyTimeout())
+	err := d.runSnapshotHooks(hooksCtx, snapshotPreHooksDir)
+	cancel()
+	if err != nil {
+		d.logger.Warn("Failed running pre-snapshot hooks, taking a crash-consistent snapshot instead", logger.Ctx{"snapshot": name, "err": err})
+	}
+
+	err = d.snapshotCommon(ctx, d, name, expiry, false, diskVolumesMode, progressReporter)
+
+	hooksCtx, cancel = context.WithTimeout(context.Background(), d.snapshotConsistencyTimeout())
+	hooksErr := d.runSnapshotHooks(hooksCtx, snapshotPostHooksDir)
+	cancel()
+	if hooksErr != nil {
+		d.logger.Warn("Failed running post-snapshot hooks", logger.Ctx{"snapshot": name, "err": hooksErr})
+	}
+
+	return err
+}
+
+// runSnapshotHooks runs the executables of the given directory of the container in lexical order.
+func (d *lxc) runSnapshotHooks(ctx context.Context, dir string) error {
+	script := `rc=0
+for hook in "$1"/*; do
+    [ -f "$hook" ] && [ -x "$hook" ] || continue
+    "$hook" || { echo "Hook $hook failed" >&2; rc=1; }
+done
+exit $rc`
+
+	req := api.InstanceExecPost{
+		Command: []string{"/bin/sh", "-c", script, "sh", dir},
+	}
+
+	cmd, err := d.Exec(ctx, req, nil, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	status, err := cmd.Wait()
+	if err != nil {
+		return err
+	}
+
+	if status != 0 {
+		return fmt.Errorf("Snapshot hooks in %q failed with status %d", dir, status)
+	}
+
+	return nil
 }
 
 // Snapshot takes a new snapshot.
@@ -6087,6 +6153,62 @@ func (d *lxc) ConsoleLog(ctx context.Context, opts liblxc.ConsoleLogOptions) (st
 	return string(msg), nil
 }
 
+// PortForward connects to an address inside the container's network namespace and mirrors the websocket traffic to it.
+func (d *lxc) PortForward(ctx context.Context, conn *websocket.Conn, protocol string, address string) error {
+	pid := d.InitPID()
+	if pid < 1 {
+		return errors.New("Container isn't running")
+	}
+
+	// The connection is established by forknet and its socket sent back over a socket pair.
+	fds, err := unix.Socketpair(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return fmt.Errorf("Failed creating socket pair: %w", err)
+	}
+
+	localSock := os.NewFile(uintptr(fds[0]), "forknet-local")
+	defer func() { _ = localSock.Close() }()
+
+	remoteSock := os.NewFile(uintptr(fds[1]), "forknet-remote")
+	defer func() { _ = remoteSock.Close() }()
+
+	pidFdNr, pidFd := d.inheritInitPidFd()
+	if pidFdNr >= 0 {
+		defer func() { _ = pidFd.Close() }()
+	}
+
+	// The socket always follows the pidfd slot, whether or not a pidfd is passed.
+	_, _, err = shared.RunCommandSplit(
+		ctx,
+		nil,
+		[]*os.File{pidFd, remoteSock},
+		d.state.OS.ExecPath,
+		"forknet",
+		"dial",
+		"--",
+		strconv.Itoa(pid),
+		strconv.Itoa(pidFdNr),
+		"4",
+		protocol,
+		address)
+	if err != nil {
+		return fmt.Errorf("Failed connecting to %s %q: %w", protocol, address, err)
+	}
+
+	targetFile, err := netutils.AbstractUnixReceiveFd(int(localSock.Fd()), netutils.UnixFdsAcceptExact)
+	if err != nil {
+		return err
+	}
+
+	target, err := net.FileConn(targetFile)
+	_ = targetFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return ws.Forward(conn, target)
+}
+
 // Exec executes a command inside the instance.
 func (d *lxc) Exec(ctx context.Context, req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	// Generate the LXC config if missing.
@@ -6229,6 +6351,48 @@ func (d *lxc) cpuState() api.InstanceStateCPU {
 	return cpu
 }
 
+// pressureState returns the pressure stall information of the container, keyed by resource.
+func (d *lxc) pressureState() map[string]api.InstanceStatePressure {
+	cc, err := d.initLXC(false)
+	if err != nil {
+		return nil
+	}
+
+	cg, err := d.cgroup(cc, true)
+	if err != nil {
+		return nil
+	}
+
+	return d.pressureStateFromCgroup(cg)
+}
+
+// pressureStateFromCgroup returns the pressure stall information found in the given cgroup, keyed by resource.
+// Resources for which no pressure stall information is available are omitted.
+func (d *lxc) pressureStateFromCgroup(cg *cgroup.CGroup) map[string]api.InstanceStatePressure {
+	var pressures map[string]api.InstanceStatePressure
+
+	for _, resource := range metrics.PressureResources {
+		content, err := cg.GetPressure(resource)
+		if err != nil {
+			continue
+		}
+
+		pressure, err := metrics.ParsePressure(content)
+		if err != nil {
+			d.logger.Warn("Failed parsing pressure stall information", logger.Ctx{"resource": resource, "err": err})
+			continue
+		}
+
+		if pressures == nil {
+			pressures = make(map[string]api.InstanceStatePressure, len(metrics.PressureResources))
+		}
+
+		pressures[resource] = pressure
+	}
+
+	return pressures
+}
+
 func (d *lxc) diskState() map[string]api.InstanceStateDisk {
 	disk := map[string]api.InstanceStateDisk{}
 
@@ -7389,6 +7553,11 @@ func (d *lxc) Metrics(hostInterfaces []net.Interface) (*metrics.MetricSet, error
 		out.AddSamples(metrics.NetworkTransmitDropTotal, metrics.Sample{Value: float64(state.Counters.PacketsDroppedOutbound), Labels: labels})
 	}
 
+	// Get pressure stall information
+	for resource, pressure := range d.pressureStateFromCgroup(cg) {
+		out.AddPressureSamples(resource, pressure)
+	}
+
 	// Get number of processes
 	pids, err := d.processesState(d.InitPID())
 	if err != nil {
@@ -83,6 +83,7 @@ import (
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/units"
 	"github.com/canonical/lxd/shared/version"
+	"github.com/canonical/lxd/shared/ws"
 )
 
 // QEMUDefaultCPUCores defines the default number of cores a VM will get if no limit specified.
@@ -272,6 +273,11 @@ func qemuCreate(ctx context.Context, s *state.State, args db.InstanceArgs, p api
 		if err != nil {
 			return nil, nil, fmt.Errorf("Invalid devices: %w", err)
 		}
+
+		err = d.validateBootDependencies()
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid config: %w", err)
+		}
 	}
 
 	// Retrieve the instance's storage pool.
@@ -348,6 +354,20 @@ type qemu struct {
 	migrationReceiveStateful map[string]io.ReadWriteCloser
 }
 
+// AgentStarted returns whether the lxd-agent has been detected inside the running VM.
+func (d *qemu) AgentStarted() bool {
+	if !d.IsRunning() {
+		return false
+	}
+
+	monitor, err := qmp.Connect(d.monitorPath(), qemuSerialChardevName, d.getMonitorEventHandler())
+	if err != nil {
+		return false
+	}
+
+	return monitor.AgentStarted()
+}
+
 // getAgentClient returns the current agent client handle.
 // Callers should check that the instance is running (and therefore mounted) before caling this function,
 // otherwise the qmp.Connect call will fail to use the monitor socket file.
@@ -5647,8 +5667,25 @@ func (d *qemu) snapshot(ctx context.Context, name string, expiry *time.Time, sta
 		}
 	}
 
+	// Quiesce the guest so that applications and file systems are consistent on disk.
+	var thaw func() error
+	if !stateful && d.snapshotApplicationConsistent() && d.IsRunning() {
+		thaw, err = d.agentFreezeFilesystems(d.snapshotConsistencyTimeout())
+		if err != nil {
+			d.logger.Warn("Failed quiescing guest, taking a crash-consistent snapshot instead", logger.Ctx{"snapshot": name, "err": err})
+		}
+	}
+
 	// Create the snapshot.
 	err = d.snapshotCommon(ctx, d, name, expiry, stateful, diskVolumesMode, progressReporter)
+
+	if thaw != nil {
+		thawErr := thaw()
+		if thawErr != nil {
+			d.logger.Warn("Failed thawing guest file systems, the snapshot may only be crash-consistent", logger.Ctx{"snapshot": name, "err": thawErr})
+		}
+	}
+
 	if err != nil {
 		return err
 	}
@@ -8266,6 +8303,39 @@ func (d *qemu) Console(ctx context.Context, protocol string) (*os.File, chan err
 	return file, chDisconnect, nil
 }
 
+// PortForward connects to an address inside the VM through the lxd-agent and mirrors the websocket traffic to it.
+func (d *qemu) PortForward(ctx context.Context, conn *websocket.Conn, protocol string, address string) error {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return err
+	}
+
+	agent, err := lxd.ConnectLXDHTTPWithContext(ctx, nil, client)
+	if err != nil {
+		d.logger.Error("Failed connecting to lxd-agent", logger.Ctx{"err": err})
+		return errors.New("Failed connecting to lxd-agent")
+	}
+
+	defer agent.Disconnect()
+
+	values := url.Values{}
+	values.Set("protocol", protocol)
+	values.Set("address", address)
+
+	agentConn, err := agent.RawWebsocket("/port-forward?" + values.Encode())
+	if err != nil {
+		return fmt.Errorf("Failed connecting to %s %q: %w", protocol, address, err)
+	}
+
+	select {
+	case <-ws.Proxy(conn, agentConn):
+	case <-ctx.Done():
+		_ = agentConn.Close()
+	}
+
+	return nil
+}
+
 // Exec a command inside the instance.
 func (d *qemu) Exec(ctx context.Context, req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (instance.Cmd, error) {
 	revert := revert.New()
@@ -8636,6 +8706,61 @@ func (d *qemu) agentGetState() (*api.InstanceState, error) {
 	return status, nil
 }
 
+// agentFreezeFilesystems asks the lxd-agent to run the pre-snapshot hooks of the guest and to freeze its file
+// systems for at most the given duration. The returned function thaws them and runs the post-snapshot hooks.
+func (d *qemu) agentFreezeFilesystems(timeout time.Duration) (func() error, error) {
+	client, err := d.getAgentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	// The pre-snapshot hooks alone can take up to the freeze timeout, so the request is given more time than that.
+	// Otherwise it could time out right before the file systems get frozen, leaving them frozen during the snapshot.
+	client.Timeout = 2*timeout + 30*time.Second
+
+	agent, err := lxd.ConnectLXDHTTP(nil, client)
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to lxd-agent: %w", err)
+	}
+
+	req := agentAPI.FreezePost{Timeout: int64(timeout / time.Second)}
+	resp, _, err := agent.RawQuery(http.MethodPost, "/1.0/freeze", req, "")
+	if err == nil {
+		freeze := agentAPI.Freeze{}
+		err = json.Unmarshal(resp.Metadata, &freeze)
+		if err == nil {
+			d.logger.Debug("Froze guest file systems", logger.Ctx{"filesystems": freeze.Filesystems})
+		}
+	}
+
+	if err != nil {
+		// Unless the lxd-agent rejected the request, the file systems may have been frozen even though the request
+		// failed, such as when it timed out. The lxd-agent handles the thaw request once it is done with the freeze
+		// request.
+		if !api.StatusErrorCheck(err) {
+			_, _, thawErr := agent.RawQuery(http.MethodDelete, "/1.0/freeze", nil, "")
+			if thawErr != nil && !api.StatusErrorCheck(thawErr, http.StatusNotFound) {
+				d.logger.Warn("Failed thawing guest file systems", logger.Ctx{"err": thawErr})
+			}
+		}
+
+		agent.Disconnect()
+		return nil, err
+	}
+
+	return func() error {
+		defer agent.Disconnect()
+
+		// The lxd-agent thaws the file systems by itself once the timeout is reached.
+		_, _, err := agent.RawQuery(http.MethodDelete, "/1.0/freeze", nil, "")
+		if api.StatusErrorCheck(err, http.StatusNotFound) {
+			return fmt.Errorf("File systems were thawed after %s, before the snapshot completed", timeout)
+		}
+
+		return err
+	}, nil
+}
+
 // IsRunning returns whether or not the instance is running.
 func (d *qemu) IsRunning() bool {
 	return d.isRunningStatusCode(d.statusCode())
@@ -9211,6 +9336,63 @@ func (d *qemu) devlxdEventSend(eventType string, eventMessage map[string]any) er
 	return nil
 }
 
+// ApplyForkIdentity sends the identity of the instance to the lxd-agent after the instance was started from the
+// memory state of another instance. The source config is used to find the MAC addresses that the guest inherited.
+func (d *qemu) ApplyForkIdentity(ctx context.Context, sourceConfig map[string]string) error {
+	if !d.IsRunning() {
+		return errors.New("Instance is not running")
+	}
+
+	// Matches the fields of agentAPI.IdentityEvent.
+	event := map[string]any{
+		"name":       d.name,
+		"uuid":       d.localConfig["volatile.uuid"],
+		"interfaces": forkInterfaceAddresses(d.expandedDevices, sourceConfig, d.localConfig),
+	}
+
+	// The guest keeps running the lxd-agent of the source instance, so wait for it to be reachable through the
+	// vsock address of this instance.
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	for {
+		_, err := d.getAgentClient()
+		if err == nil {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Failed waiting for lxd-agent: %w", err)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+
+	return d.devlxdEventSend("identity", event)
+}
+
+// forkInterfaceAddresses returns a map of the MAC addresses of the NICs of the source instance to the
+// MAC addresses of the same NICs in the forked instance.
+func forkInterfaceAddresses(devices deviceConfig.Devices, sourceConfig map[string]string, localConfig map[string]string) map[string]string {
+	interfaces := make(map[string]string)
+	for devName, dev := range devices {
+		if dev["type"] != "nic" {
+			continue
+		}
+
+		hwaddrKey := "volatile." + devName + ".hwaddr"
+		oldHwaddr := sourceConfig[hwaddrKey]
+		newHwaddr := localConfig[hwaddrKey]
+		if oldHwaddr == "" || newHwaddr == "" || oldHwaddr == newHwaddr {
+			continue
+		}
+
+		interfaces[oldHwaddr] = newHwaddr
+	}
+
+	return interfaces
+}
+
 // Info returns "qemu" and the currently loaded qemu version.
 func (d *qemu) Info() instance.Info {
 	data := instance.Info{
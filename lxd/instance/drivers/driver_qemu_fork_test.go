@@ -0,0 +1,47 @@
+package drivers
+
+import (
+	"reflect"
+	"testing"
+
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+)
+
+func TestForkInterfaceAddresses(t *testing.T) {
+	devices := deviceConfig.Devices{
+		"eth0": {"type": "nic", "network": "lxdbr0"},
+		"eth1": {"type": "nic", "nictype": "p2p"},
+		"eth2": {"type": "nic", "nictype": "p2p"},
+		"eth3": {"type": "nic", "nictype": "p2p", "hwaddr": "00:16:3e:00:00:03"},
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+	}
+
+	sourceConfig := map[string]string{
+		"volatile.eth0.hwaddr": "00:16:3e:00:00:00",
+		"volatile.eth1.hwaddr": "00:16:3e:00:00:01",
+		"volatile.eth3.hwaddr": "00:16:3e:00:00:03",
+		"volatile.root.hwaddr": "00:16:3e:00:00:04",
+	}
+
+	localConfig := map[string]string{
+		"volatile.eth0.hwaddr": "00:16:3e:00:01:00",
+		"volatile.eth2.hwaddr": "00:16:3e:00:01:02",
+		"volatile.eth3.hwaddr": "00:16:3e:00:00:03",
+		"volatile.root.hwaddr": "00:16:3e:00:01:04",
+	}
+
+	// Only NICs with a MAC address on both sides that changed are mapped.
+	expected := map[string]string{
+		"00:16:3e:00:00:00": "00:16:3e:00:01:00",
+	}
+
+	actual := forkInterfaceAddresses(devices, sourceConfig, localConfig)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Unexpected interface addresses: got %v, want %v", actual, expected)
+	}
+
+	actual = forkInterfaceAddresses(deviceConfig.Devices{}, sourceConfig, localConfig)
+	if len(actual) != 0 {
+		t.Errorf("Expected no interface addresses without NICs, got %v", actual)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/gorilla/websocket"
 	liblxc "github.com/lxc/go-lxc"
 	"github.com/pkg/sftp"
 	"google.golang.org/protobuf/proto"
@@ -132,6 +133,9 @@ type Instance interface {
 	Console(ctx context.Context, protocol string) (*os.File, chan error, error)
 	Exec(ctx context.Context, req api.InstanceExecPost, stdin *os.File, stdout *os.File, stderr *os.File) (Cmd, error)
 
+	// Port forwarding - Connect to an address inside the instance and mirror the websocket traffic to it.
+	PortForward(ctx context.Context, conn *websocket.Conn, protocol string, address string) error
+
 	// Status
 	Render(options ...func(response any) error) (any, any, error)
 	RenderFull(hostInterfaces []net.Interface, opts ...StateRenderOptions) (*api.InstanceFull, any, error)
@@ -209,12 +213,16 @@ type VM interface {
 	Instance
 
 	AgentCertificate() *x509.Certificate
+	AgentStarted() bool
 
 	FirmwarePath() string
 
 	// UEFI vars handling.
 	UEFIVars() (*api.InstanceUEFIVars, error)
 	UEFIVarsUpdate(newUEFIVarsSet api.InstanceUEFIVars) error
+
+	// Fork handling.
+	ApplyForkIdentity(ctx context.Context, sourceConfig map[string]string) error
 }
 
 // CriuMigrationArgs arguments for CRIU migration.
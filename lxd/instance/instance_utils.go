@@ -115,6 +115,20 @@ func ValidConfig(sysOS *sys.OS, config map[string]string, expanded bool, instanc
 		return errors.New(`CPU pinning specified, but pinning strategy is set to "auto"`)
 	}
 
+	if expanded {
+		switch config["boot.health_check.type"] {
+		case "tcp":
+			if config["boot.health_check.port"] == "" {
+				return errors.New(`"boot.health_check.port" is required when "boot.health_check.type" is "tcp"`)
+			}
+
+		case "command":
+			if config["boot.health_check.command"] == "" {
+				return errors.New(`"boot.health_check.command" is required when "boot.health_check.type" is "command"`)
+			}
+		}
+	}
+
 	return nil
 }
 
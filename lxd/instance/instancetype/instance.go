@@ -121,6 +121,59 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: What order to start the instances in
 	"boot.autostart.priority": validate.Optional(validate.IsInt64),
 
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.depends_on)
+	// Comma-separated list of instances in the same project that must be started (and pass their health check)
+	// before this instance is started.
+	// This is honored when LXD starts, when starting several instances of a project with a single request, and when
+	// a cluster member is restored after an evacuation. When LXD starts or a cluster member is restored, only
+	// dependencies located on the same cluster member are waited for.
+	// Dependency cycles are rejected.
+	// See {ref}`instances-boot-order` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: no
+	//  shortdesc: Instances to start before this instance
+	"boot.depends_on": validate.Optional(validate.IsListOf(validate.IsHostname)),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.health_check.type)
+	// Possible values are `agent` (wait for the LXD agent to be started inside the virtual machine),
+	// `tcp` (wait for a TCP port of the instance to accept connections) and `command` (wait for a command
+	// run inside the instance to exit with status 0).
+	// Instances that depend on this instance through `boot.depends_on` are only started once the check passes.
+	// For containers, the `agent` check passes as soon as the container is running.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: How to check that the instance is ready after it started
+	"boot.health_check.type": validate.Optional(validate.IsOneOf("agent", "tcp", "command")),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.health_check.port)
+	// LXD connects to this port on the global addresses of the instance.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  condition: `boot.health_check.type` set to `tcp`
+	//  shortdesc: TCP port to check
+	"boot.health_check.port": validate.Optional(validate.IsNetworkPort),
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.health_check.command)
+	// The command is run with `/bin/sh -c` inside the instance.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  condition: `boot.health_check.type` set to `command`
+	//  shortdesc: Command to run
+	"boot.health_check.command": validate.IsAny,
+
+	// lxdmeta:generate(entities=instance; group=boot; key=boot.health_check.timeout)
+	// Number of seconds to wait for the health check to pass after the instance started.
+	// ---
+	//  type: integer
+	//  defaultdesc: `300`
+	//  liveupdate: yes
+	//  shortdesc: How long to wait for the health check to pass
+	"boot.health_check.timeout": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=boot; key=boot.stop.priority)
 	// The instance with the highest value is shut down first.
 	// ---
@@ -301,6 +354,36 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	// shortdesc: Placement group controlling instance scheduling
 	"placement.group": validate.IsDeviceName,
 
+	// lxdmeta:generate(entities=instance; group=pressure; key=pressure.cpu.threshold)
+	// Percentage of time during which at least some tasks of the instance were stalled waiting for CPU, averaged
+	// over 60 seconds, above which LXD raises a warning for the instance.
+	// See {ref}`instance-options-pressure` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  shortdesc: CPU pressure that raises a warning
+	"pressure.cpu.threshold": validate.Optional(validate.IsInRange(1, 100)),
+
+	// lxdmeta:generate(entities=instance; group=pressure; key=pressure.io.threshold)
+	// Percentage of time during which at least some tasks of the instance were stalled waiting for I/O, averaged
+	// over 60 seconds, above which LXD raises a warning for the instance.
+	// See {ref}`instance-options-pressure` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  shortdesc: I/O pressure that raises a warning
+	"pressure.io.threshold": validate.Optional(validate.IsInRange(1, 100)),
+
+	// lxdmeta:generate(entities=instance; group=pressure; key=pressure.memory.threshold)
+	// Percentage of time during which at least some tasks of the instance were stalled waiting for memory, averaged
+	// over 60 seconds, above which LXD raises a warning for the instance.
+	// See {ref}`instance-options-pressure` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  shortdesc: Memory pressure that raises a warning
+	"pressure.memory.threshold": validate.Optional(validate.IsInRange(1, 100)),
+
 	// Caller is responsible for full validation of any raw.* value.
 
 	// lxdmeta:generate(entities=instance; group=raw; key=raw.apparmor)
@@ -320,6 +403,31 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Raw idmap configuration
 	"raw.idmap": validate.IsAny,
 
+	// lxdmeta:generate(entities=instance; group=placement; key=reservations.cpu)
+	// Number of CPUs that are set aside for the instance on its cluster member.
+	// Unlike {config:option}`instance-resource-limits:limits.cpu`, this value is taken into account when placing
+	// the instance and doesn't restrict what the instance can use.
+	//
+	// See {ref}`clustering-instance-reservations` for more information.
+	// ---
+	//  type: integer
+	//  liveupdate: yes
+	//  shortdesc: Number of CPUs reserved for the instance
+	"reservations.cpu": validate.Optional(validate.IsUint32),
+
+	// lxdmeta:generate(entities=instance; group=placement; key=reservations.memory)
+	// Amount of memory in bytes that is set aside for the instance on its cluster member.
+	// Various suffixes are supported.
+	// Unlike {config:option}`instance-resource-limits:limits.memory`, this value is taken into account when placing
+	// the instance and doesn't restrict what the instance can use.
+	//
+	// See {ref}`clustering-instance-reservations` for more information.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: Amount of memory reserved for the instance
+	"reservations.memory": validate.Optional(validate.IsSize),
+
 	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd)
 	// See {ref}`dev-lxd` for more information.
 	// ---
@@ -338,6 +446,31 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 	//  shortdesc: Controls the availability of the `/1.0/images` API over `devlxd`
 	"security.devlxd.images": validate.Optional(validate.IsBool),
 
+	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd.identity_tokens)
+	// Identity tokens also require {config:option}`server-miscellaneous:instances.identity_tokens.issuer` to be set.
+	// See {ref}`instances-identity-tokens` for more information.
+	// ---
+	//  type: bool
+	//  defaultdesc: `false`
+	//  liveupdate: yes
+	//  shortdesc: Controls the availability of identity tokens over `devlxd`
+	"security.devlxd.identity_tokens": validate.Optional(validate.IsBool),
+
+	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd.identity_tokens.user_keys)
+	// Specify a comma-separated list of `user.*` configuration keys, for example `user.role,user.team`.
+	// The selected keys and their values are included in the `user` claim of the instance identity tokens.
+	// ---
+	//  type: string
+	//  liveupdate: yes
+	//  shortdesc: User keys included in identity tokens
+	"security.devlxd.identity_tokens.user_keys": validate.Optional(validate.IsListOf(func(value string) error {
+		if !strings.HasPrefix(value, "user.") || value == "user." {
+			return fmt.Errorf("Invalid user key %q", value)
+		}
+
+		return nil
+	})),
+
 	// lxdmeta:generate(entities=instance; group=security; key=security.devlxd.management.volumes)
 	//
 	// ---
@@ -408,6 +541,30 @@ var InstanceConfigKeysAny = map[string]func(value string) error{
 		return err
 	},
 
+	// lxdmeta:generate(entities=instance; group=snapshots; key=snapshots.consistency)
+	// Possible values are `crash` and `application`.
+	// With `application`, LXD runs the pre-snapshot hooks of the guest before taking a snapshot of a running instance
+	// and the post-snapshot hooks afterwards. For virtual machines, the LXD agent also freezes the guest file systems
+	// in between. If this fails, a crash-consistent snapshot is taken instead.
+	//
+	// See {ref}`instances-snapshots-consistency` for more information.
+	// ---
+	//  type: string
+	//  defaultdesc: `crash`
+	//  liveupdate: yes
+	//  shortdesc: Consistency of the snapshots of running instances
+	"snapshots.consistency": validate.Optional(validate.IsOneOf("crash", "application")),
+
+	// lxdmeta:generate(entities=instance; group=snapshots; key=snapshots.consistency.timeout)
+	// Specify the number of seconds after which the pre-snapshot hooks are stopped and the guest file systems are
+	// thawed again, even if the snapshot isn't complete.
+	// ---
+	//  type: integer
+	//  defaultdesc: `30`
+	//  liveupdate: yes
+	//  shortdesc: How long the guest may stay frozen for a snapshot
+	"snapshots.consistency.timeout": validate.Optional(validate.IsUint32),
+
 	// lxdmeta:generate(entities=instance; group=miscellaneous; key=ubuntu_pro.guest_attach)
 	// Indicate whether the guest should auto-attach Ubuntu Pro at start up.
 	//
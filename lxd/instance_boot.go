@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// instanceBootHealthCheckDefaultTimeout is the default time to wait for the boot health check of an instance to pass.
+const instanceBootHealthCheckDefaultTimeout = 300 * time.Second
+
+// instanceBootHealthCheckInterval is the time between two attempts of the boot health check of an instance.
+const instanceBootHealthCheckInterval = 2 * time.Second
+
+var internalInstanceBootHealthCmd = APIEndpoint{
+	Path: "instances/{name}/boot-health",
+
+	Get: APIEndpointAction{Handler: internalInstanceBootHealthGet, AccessHandler: allowPermission(entity.TypeServer, auth.EntitlementCanEdit)},
+}
+
+// internalInstanceBootHealthGet waits for the boot health check of a started instance to pass.
+// It is used when starting instances on other cluster members whose dependents are waiting for them.
+func internalInstanceBootHealthGet(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	projectName, name, resp := forwardedInstanceResponse(s, r)
+	if resp != nil {
+		return resp
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = instanceBootWaitHealthy(r.Context(), inst)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	return response.EmptySyncResponse
+}
+
+// instanceBootKey returns the key identifying an instance in boot dependency lookups.
+func instanceBootKey(projectName string, instanceName string) string {
+	return projectName + "/" + instanceName
+}
+
+// instancesBootDependencies returns the keys of the instances that other instances of the list depend on.
+func instancesBootDependencies(instances []instance.Instance) map[string]bool {
+	dependencies := make(map[string]bool)
+	for _, inst := range instances {
+		for _, dependency := range instance.BootDependencies(inst.ExpandedConfig()) {
+			dependencies[instanceBootKey(inst.Project().Name, dependency)] = true
+		}
+	}
+
+	return dependencies
+}
+
+// instanceBootDependencyError returns an error if one of the boot dependencies of the instance is recorded as
+// failed in the given map.
+func instanceBootDependencyError(inst instance.Instance, failed map[string]error) error {
+	for _, dependency := range instance.BootDependencies(inst.ExpandedConfig()) {
+		err, ok := failed[instanceBootKey(inst.Project().Name, dependency)]
+		if ok {
+			return fmt.Errorf("Boot dependency %q isn't ready: %w", dependency, err)
+		}
+	}
+
+	return nil
+}
+
+// instanceBootWaitHealthy waits for the boot health check of a started instance to pass.
+func instanceBootWaitHealthy(ctx context.Context, inst instance.Instance) error {
+	config := inst.ExpandedConfig()
+	if config["boot.health_check.type"] == "" {
+		return nil
+	}
+
+	timeout := instanceBootHealthCheckDefaultTimeout
+	if config["boot.health_check.timeout"] != "" {
+		seconds, err := strconv.Atoi(config["boot.health_check.timeout"])
+		if err == nil {
+			timeout = time.Duration(seconds) * time.Second
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		err := instanceBootHealthCheck(ctx, inst)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Health check of instance %q didn't pass within %s: %w", inst.Name(), timeout, err)
+		case <-time.After(instanceBootHealthCheckInterval):
+		}
+	}
+}
+
+// instanceBootHealthCheck runs the boot health check of a started instance once.
+func instanceBootHealthCheck(ctx context.Context, inst instance.Instance) error {
+	if !inst.IsRunning() {
+		return errors.New("Instance isn't running")
+	}
+
+	config := inst.ExpandedConfig()
+
+	switch config["boot.health_check.type"] {
+	case "agent":
+		vm, ok := inst.(instance.VM)
+		if ok && !vm.AgentStarted() {
+			return errors.New("LXD agent isn't started")
+		}
+
+	case "tcp":
+		return instanceBootHealthCheckTCP(ctx, inst, config["boot.health_check.port"])
+
+	case "command":
+		req := api.InstanceExecPost{
+			Command: []string{"/bin/sh", "-c", config["boot.health_check.command"]},
+		}
+
+		cmd, err := inst.Exec(ctx, req, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf("Failed running health check command: %w", err)
+		}
+
+		status, err := cmd.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed running health check command: %w", err)
+		}
+
+		if status != 0 {
+			return fmt.Errorf("Health check command exited with status %d", status)
+		}
+	}
+
+	return nil
+}
+
+// instanceBootHealthCheckTCP checks whether the given TCP port accepts connections on any global address of the
+// instance.
+func instanceBootHealthCheckTCP(ctx context.Context, inst instance.Instance, port string) error {
+	hostInterfaces, _ := net.Interfaces()
+
+	state, err := inst.RenderState(hostInterfaces, instance.StateRenderOptions{IncludeNetwork: true})
+	if err != nil {
+		return fmt.Errorf("Failed getting instance state: %w", err)
+	}
+
+	var dialer net.Dialer
+	for _, network := range state.Network {
+		if network.Type == "loopback" {
+			continue
+		}
+
+		for _, address := range network.Addresses {
+			if address.Scope != "global" {
+				continue
+			}
+
+			dialCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			conn, err := dialer.DialContext(dialCtx, "tcp", net.JoinHostPort(address.Address, port))
+			cancel()
+			if err == nil {
+				_ = conn.Close()
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("Port %s isn't open on any global address of the instance", port)
+}
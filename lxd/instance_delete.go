@@ -56,7 +56,13 @@ func instanceDelete(d *Daemon, r *http.Request) response.Response {
 		return resp
 	}
 
+	approvalExpiry, err := projectApprovalExpiry(r.Context(), s, projectName, operationApprovalInstanceDelete)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	var opScheduler operations.OperationScheduler = func(s *state.State, args operations.OperationArgs) (*operations.Operation, error) {
+		args.ApprovalExpiry = approvalExpiry
 		return operations.ScheduleUserOperationFromRequest(s, r, args)
 	}
 
@@ -601,66 +601,7 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(errors.New("Instance is frozen"))
 	}
 
-	// Process environment.
-	if post.Environment == nil {
-		post.Environment = map[string]string{}
-	}
-
-	// Override any environment variable settings from the instance if not manually specified in post.
-	for k, v := range inst.ExpandedConfig() {
-		envKey, found := strings.CutPrefix(k, "environment.")
-		if found {
-			_, found = post.Environment[envKey]
-			if !found {
-				post.Environment[envKey] = v
-			}
-		}
-	}
-
-	// Set default value for PATH.
-	_, ok := post.Environment["PATH"]
-	if !ok {
-		post.Environment["PATH"] = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
-
-		if inst.Type() == instancetype.Container {
-			// Add some additional paths. This directly looks through /proc
-			// rather than use FileExists as none of those paths are expected to be
-			// symlinks and this is much faster than forking a sub-process and
-			// attaching to the instance.
-			extraPaths := map[string]string{
-				"/snap":      "/snap/bin",
-				"/etc/NIXOS": "/run/current-system/sw/bin",
-			}
-
-			instPID := inst.InitPID()
-			for k, v := range extraPaths {
-				if shared.PathExists(fmt.Sprintf("/proc/%d/root%s", instPID, k)) {
-					post.Environment["PATH"] = post.Environment["PATH"] + ":" + v
-				}
-			}
-		}
-	}
-
-	// If running as root, set some env variables.
-	if post.User == 0 {
-		// Set default value for HOME.
-		_, ok = post.Environment["HOME"]
-		if !ok {
-			post.Environment["HOME"] = "/root"
-		}
-
-		// Set default value for USER.
-		_, ok = post.Environment["USER"]
-		if !ok {
-			post.Environment["USER"] = "root"
-		}
-	}
-
-	// Set default value for LANG.
-	_, ok = post.Environment["LANG"]
-	if !ok {
-		post.Environment["LANG"] = "C.UTF-8"
-	}
+	instanceExecEnvironment(inst, &post)
 
 	if post.WaitForWS {
 		ws := &execWs{}
@@ -788,3 +729,68 @@ func instanceExecPost(d *Daemon, r *http.Request) response.Response {
 
 	return response.OperationResponse(op)
 }
+
+// instanceExecEnvironment fills in the environment of an exec request with the instance's "environment.*"
+// configuration and the default PATH, HOME, USER and LANG variables, unless they are already set.
+func instanceExecEnvironment(inst instance.Instance, post *api.InstanceExecPost) {
+	// Process environment.
+	if post.Environment == nil {
+		post.Environment = map[string]string{}
+	}
+
+	// Override any environment variable settings from the instance if not manually specified in post.
+	for k, v := range inst.ExpandedConfig() {
+		envKey, found := strings.CutPrefix(k, "environment.")
+		if found {
+			_, found = post.Environment[envKey]
+			if !found {
+				post.Environment[envKey] = v
+			}
+		}
+	}
+
+	// Set default value for PATH.
+	_, ok := post.Environment["PATH"]
+	if !ok {
+		post.Environment["PATH"] = "/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin"
+
+		if inst.Type() == instancetype.Container {
+			// Add some additional paths. This directly looks through /proc
+			// rather than use FileExists as none of those paths are expected to be
+			// symlinks and this is much faster than forking a sub-process and
+			// attaching to the instance.
+			extraPaths := map[string]string{
+				"/snap":      "/snap/bin",
+				"/etc/NIXOS": "/run/current-system/sw/bin",
+			}
+
+			instPID := inst.InitPID()
+			for k, v := range extraPaths {
+				if shared.PathExists(fmt.Sprintf("/proc/%d/root%s", instPID, k)) {
+					post.Environment["PATH"] = post.Environment["PATH"] + ":" + v
+				}
+			}
+		}
+	}
+
+	// If running as root, set some env variables.
+	if post.User == 0 {
+		// Set default value for HOME.
+		_, ok = post.Environment["HOME"]
+		if !ok {
+			post.Environment["HOME"] = "/root"
+		}
+
+		// Set default value for USER.
+		_, ok = post.Environment["USER"]
+		if !ok {
+			post.Environment["USER"] = "root"
+		}
+	}
+
+	// Set default value for LANG.
+	_, ok = post.Environment["LANG"]
+	if !ok {
+		post.Environment["LANG"] = "C.UTF-8"
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/canonical/lxd/lxd/auth"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/revert"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// instanceForkPost handles POST /1.0/instances/{name}?fork requests.
+// It creates new instances from the memory state of a running virtual machine.
+func instanceForkPost(s *state.State, r *http.Request, inst instance.Instance) response.Response {
+	req := api.InstanceForkPost{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	if len(req.Names) == 0 {
+		return response.BadRequest(errors.New("At least one instance name must be provided"))
+	}
+
+	for i, name := range req.Names {
+		err = instancetype.ValidName(name, false)
+		if err != nil {
+			return response.BadRequest(err)
+		}
+
+		if slices.Contains(req.Names[i+1:], name) {
+			return response.BadRequest(fmt.Errorf("Duplicate instance name %q", name))
+		}
+	}
+
+	if inst.Type() != instancetype.VM {
+		return response.BadRequest(errors.New("Only virtual machines can be forked"))
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Only running instances can be forked"))
+	}
+
+	if shared.IsFalseOrEmpty(inst.ExpandedConfig()["migration.stateful"]) {
+		return response.BadRequest(errors.New("Forking requires migration.stateful to be set to true"))
+	}
+
+	if s.DB.Cluster.LocalNodeIsEvacuated() {
+		return response.Forbidden(errors.New("Cluster member is evacuated"))
+	}
+
+	projectName := inst.Project().Name
+
+	// Forking creates new instances in the project of the source instance.
+	err = s.Authorizer.CheckPermission(r.Context(), entity.ProjectURL(projectName), auth.EntitlementCanCreateInstances)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	var restrictions *limits.ProjectInfo
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		for _, name := range req.Names {
+			id, err := tx.GetInstanceID(ctx, projectName, name)
+			if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return fmt.Errorf("Failed checking for existing instance %q: %w", name, err)
+			} else if id > 0 {
+				return api.StatusErrorf(http.StatusConflict, "Name %q already in use", name)
+			}
+		}
+
+		restrictions, err = limits.FetchProject(ctx, tx, projectName, true)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	profileNames := make([]string, 0, len(inst.Profiles()))
+	for _, profile := range inst.Profiles() {
+		profileNames = append(profileNames, profile.Name)
+	}
+
+	// Check project restrictions for each of the new instances.
+	if restrictions != nil {
+		for _, name := range req.Names {
+			instReq := api.InstancesPost{
+				InstancePut: api.InstancePut{
+					Config:   instanceForkConfig(inst.LocalConfig()),
+					Devices:  inst.LocalDevices().CloneNative(),
+					Profiles: profileNames,
+				},
+				Name:   name,
+				Type:   api.InstanceTypeVM,
+				Source: api.InstanceSource{Type: api.SourceTypeCopy},
+			}
+
+			err = limits.AllowInstanceCreation(s.GlobalConfig, *restrictions, instReq)
+			if err != nil {
+				return response.SmartError(err)
+			}
+		}
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		return instanceFork(ctx, s, inst, req, op)
+	}
+
+	resources := make([]string, 0, len(req.Names))
+	for _, name := range req.Names {
+		resources = append(resources, api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName).String())
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   api.NewURL().Path(version.APIVersion, "instances", inst.Name()).Project(projectName),
+		Type:        operationtype.InstanceFork,
+		Class:       operationtype.OperationClassTask,
+		Metadata:    map[string]any{"instances": resources},
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// instanceForkConfig returns the local config of a new instance forked from an instance with the given config.
+// All volatile keys but volatile.base_image are removed so that the new instance gets fresh MAC addresses,
+// UUIDs and vsock ID.
+func instanceForkConfig(sourceConfig map[string]string) map[string]string {
+	config := make(map[string]string, len(sourceConfig))
+	maps.Copy(config, sourceConfig)
+
+	api.InstanceRemoteCopyConfigKeyPolicy.Apply(config, nil)
+	api.InstanceCreateConfigKeyPolicy.Apply(config, nil)
+
+	return config
+}
+
+// instanceFork saves the memory state of a running virtual machine to a temporary stateful snapshot and then
+// creates and starts a new instance from that snapshot for each of the requested names.
+func instanceFork(ctx context.Context, s *state.State, inst instance.Instance, req api.InstanceForkPost, op *operations.Operation) error {
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "names": req.Names})
+
+	revert := revert.New()
+	defer revert.Fail()
+
+	// Pause the instance, save its device and memory state alongside a snapshot of its volumes and resume it.
+	// The random suffix avoids clashing with existing snapshots.
+	suffix, err := shared.RandomCryptoString()
+	if err != nil {
+		return err
+	}
+
+	snapName := "fork-" + suffix[:12]
+
+	l.Info("Saving instance state for fork", logger.Ctx{"snapshot": snapName})
+	err = inst.Snapshot(ctx, snapName, nil, true, api.DiskVolumesModeRoot, op)
+	if err != nil {
+		return fmt.Errorf("Failed creating stateful snapshot: %w", err)
+	}
+
+	snap, err := instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name()+shared.SnapshotDelimiter+snapName)
+	if err != nil {
+		return fmt.Errorf("Failed loading stateful snapshot: %w", err)
+	}
+
+	// The temporary snapshot is removed once all the new instances have been created.
+	// Storage drivers that share blocks between the snapshot and its copies keep them alive as needed.
+	defer func() {
+		err := snap.Delete(context.Background(), true, "", op)
+		if err != nil {
+			l.Warn("Failed deleting temporary fork snapshot", logger.Ctx{"snapshot": snapName, "err": err})
+		}
+	}()
+
+	// FIXME: Each new instance restores its own copy of the memory state, so guest memory isn't shared
+	//        copy-on-write between the new instances yet. This requires restoring them from a file-backed
+	//        memory template mapped privately (share=off), which vhost-user devices such as virtiofsd don't
+	//        support as they need shared guest memory.
+	sourceConfig := inst.LocalConfig()
+	forked := make([]instance.Instance, 0, len(req.Names))
+
+	for _, name := range req.Names {
+		args := db.InstanceArgs{
+			Project:      inst.Project().Name,
+			Architecture: snap.Architecture(),
+			Config:       instanceForkConfig(snap.LocalConfig()),
+			Type:         snap.Type(),
+			Description:  inst.Description(),
+			Devices:      snap.LocalDevices(),
+			Ephemeral:    req.Ephemeral,
+			Name:         name,
+			Profiles:     snap.Profiles(),
+			Stateful:     true,
+		}
+
+		newInst, err := instanceCreateAsCopy(ctx, s, instanceCreateAsCopyOpts{
+			sourceInstance: snap,
+			targetInstance: args,
+			instanceOnly:   true,
+		}, op)
+		if err != nil {
+			return fmt.Errorf("Failed creating instance %q: %w", name, err)
+		}
+
+		revert.Add(func() { _ = newInst.Delete(context.Background(), true, "", nil) })
+
+		err = newInst.Start(ctx, true, op)
+		if err != nil {
+			return fmt.Errorf("Failed starting instance %q from the forked state: %w", name, err)
+		}
+
+		revert.Add(func() { _ = newInst.Stop(context.Background(), false) })
+
+		forked = append(forked, newInst)
+	}
+
+	// Let the guests replace the identity inherited from the source instance.
+	// This is best effort as it depends on the lxd-agent running in the guest.
+	for _, newInst := range forked {
+		vm, ok := newInst.(instance.VM)
+		if !ok {
+			continue
+		}
+
+		err = vm.ApplyForkIdentity(ctx, sourceConfig)
+		if err != nil {
+			l.Warn("Failed applying fork identity", logger.Ctx{"forked": newInst.Name(), "err": err})
+		}
+	}
+
+	revert.Success()
+
+	s.Events.SendLifecycle(inst.Project().Name, lifecycle.InstanceForked.Event(ctx, inst, map[string]any{"instances": req.Names}))
+	l.Info("Forked instance", logger.Ctx{"snapshot": snapName})
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+)
+
+// TestInstanceForkConfig tests that forked instances don't inherit the volatile keys of the source instance.
+func TestInstanceForkConfig(t *testing.T) {
+	sourceConfig := map[string]string{
+		"limits.cpu":                "2",
+		"migration.stateful":        "true",
+		"user.foo":                  "bar",
+		"volatile.base_image":       "a1b2c3",
+		"volatile.uuid":             "4d1e2a2c-7a4b-4d2f-9c1d-5b1f0e0b8a11",
+		"volatile.uuid.generation":  "4d1e2a2c-7a4b-4d2f-9c1d-5b1f0e0b8a12",
+		"volatile.eth0.hwaddr":      "00:16:3e:00:00:00",
+		"volatile.vsock_id":         "12345",
+		"volatile.last_state.power": "RUNNING",
+	}
+
+	config := instanceForkConfig(sourceConfig)
+
+	assert.Equal(t, map[string]string{
+		"limits.cpu":          "2",
+		"migration.stateful":  "true",
+		"user.foo":            "bar",
+		"volatile.base_image": "a1b2c3",
+	}, config)
+
+	// The config of the source instance is left untouched.
+	assert.Equal(t, "00:16:3e:00:00:00", sourceConfig["volatile.eth0.hwaddr"])
+}
+
+type instanceForkTestSuite struct {
+	lxdTestSuite
+}
+
+// TestInstanceForkPost_Validation tests the requests rejected before any operation is created.
+func (suite *instanceForkTestSuite) TestInstanceForkPost_Validation() {
+	args := db.InstanceArgs{
+		Type: instancetype.Container,
+		Name: "c1",
+	}
+
+	inst, op, _, err := instance.CreateInternal(suite.T().Context(), suite.d.State(), args, true)
+	suite.Req.NoError(err)
+	op.Done(nil)
+	defer func() { _ = inst.Delete(suite.T().Context(), true, "", nil) }()
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{
+			name:    "invalid body",
+			body:    `{"names": "f1"}`,
+			message: "cannot unmarshal",
+		},
+		{
+			name:    "no names",
+			body:    `{"names": []}`,
+			message: "At least one instance name must be provided",
+		},
+		{
+			name:    "invalid name",
+			body:    `{"names": [".."]}`,
+			message: "Invalid instance name",
+		},
+		{
+			name:    "duplicate names",
+			body:    `{"names": ["f1", "f2", "f1"]}`,
+			message: `Duplicate instance name "f1"`,
+		},
+		{
+			name:    "container",
+			body:    `{"names": ["f1"]}`,
+			message: "Only virtual machines can be forked",
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			req := httptest.NewRequest(http.MethodPost, "/1.0/instances/c1?fork", strings.NewReader(test.body))
+			resp := instanceForkPost(suite.d.State(), req, inst)
+
+			w := httptest.NewRecorder()
+			suite.Req.NoError(resp.Render(w, req))
+			suite.Equal(http.StatusBadRequest, w.Result().StatusCode)
+			suite.Contains(resp.String(), test.message)
+		})
+	}
+}
+
+func TestInstanceForkTestSuite(t *testing.T) {
+	suite.Run(t, new(instanceForkTestSuite))
+}
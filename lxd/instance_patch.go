@@ -10,13 +10,16 @@ import (
 
 	"github.com/canonical/lxd/lxd/db"
 	"github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
 	deviceConfig "github.com/canonical/lxd/lxd/device/config"
 	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/response"
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/osarch"
 )
 
@@ -45,6 +48,8 @@ import (
 //	responses:
 //	  "200":
 //	    $ref: "#/responses/EmptySyncResponse"
+//	  "202":
+//	    $ref: "#/responses/Operation"
 //	  "400":
 //	    $ref: "#/responses/BadRequest"
 //	  "403":
@@ -166,6 +171,16 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = webhookAdmit(r, s, entity.TypeInstance, api.WebhookAdmissionOperationUpdate, projectName, entity.InstanceURL(projectName, name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	approvalExpiry, err := instanceProtectionApprovalExpiry(r.Context(), s, projectName, c.LocalConfig(), req.Config)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check project limits.
 	apiProfiles := make([]api.Profile, 0, len(req.Profiles))
 	err = s.DB.Cluster.Transaction(s.ShutdownCtx, func(ctx context.Context, tx *db.ClusterTx) error {
@@ -210,6 +225,30 @@ func instancePatch(d *Daemon, r *http.Request) response.Response {
 		Project:      projectName,
 	}
 
+	// Updates requiring approval are applied by an operation once approved.
+	if approvalExpiry > 0 {
+		etag, err := instanceEtag(c)
+		if err != nil {
+			return response.InternalError(err)
+		}
+
+		run := instanceApprovedUpdate(s, projectName, name, etag, req, args)
+
+		op, err := operations.ScheduleUserOperationFromRequest(s, r, operations.OperationArgs{
+			ProjectName:    projectName,
+			EntityURL:      entity.InstanceURL(projectName, name),
+			Type:           operationtype.InstanceUpdate,
+			Class:          operationtype.OperationClassTask,
+			RunHook:        run,
+			ApprovalExpiry: approvalExpiry,
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.OperationResponse(op)
+	}
+
 	err = c.Update(r.Context(), args, instance.UpdateActionUser)
 	if err != nil {
 		return response.SmartError(err)
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/cancel"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+	"github.com/canonical/lxd/shared/ws"
+)
+
+type portForwardWs struct {
+	// instance currently worked on
+	instance instance.Instance
+
+	// protocol and address to connect to inside the instance
+	protocol string
+	address  string
+
+	// map file descriptors to secret (-1 is the control websocket, 0 the data websockets)
+	fds map[int]string
+
+	// control websocket connection
+	control *websocket.Conn
+
+	// data websocket connections, each forwarded to its own connection inside the instance
+	conns map[*websocket.Conn]struct{}
+
+	// locks needed to access the "control" and "conns" members
+	connsLock sync.Mutex
+
+	// channel to wait until the control socket is connected
+	controlConnected chan bool
+
+	// track either server or client disconnected
+	done cancel.Canceller
+}
+
+// Metadata returns a map of metadata.
+func (s *portForwardWs) Metadata() map[string]any {
+	fds := make(map[string]string, len(s.fds))
+	for fd, secret := range s.fds {
+		if fd == -1 {
+			fds[api.SecretNameControl] = secret
+		} else {
+			fds[strconv.Itoa(fd)] = secret
+		}
+	}
+
+	return map[string]any{"fds": fds}
+}
+
+// Connect connects to the websocket.
+// Each connection to the data websocket is forwarded to a new connection inside the instance.
+func (s *portForwardWs) Connect(op *operations.Operation, r *http.Request, w http.ResponseWriter) error {
+	err := op.CheckRequestor(r)
+	if err != nil {
+		return err
+	}
+
+	secret := r.FormValue("secret")
+	if secret == "" {
+		return errors.New("missing secret")
+	}
+
+	secretBytes := []byte(secret)
+
+	for fd, fdSecret := range s.fds {
+		if subtle.ConstantTimeCompare(secretBytes, []byte(fdSecret)) != 1 {
+			continue
+		}
+
+		s.connsLock.Lock()
+		defer s.connsLock.Unlock()
+
+		if s.done.Err() != nil {
+			return errors.New("Port forwarding has ended")
+		}
+
+		if fd == -1 && s.control != nil {
+			return errors.New("Control websocket already connected")
+		}
+
+		conn, err := ws.Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return err
+		}
+
+		ws.StartKeepAlive(conn)
+
+		if fd == -1 {
+			logger.Debug("Port forward control websocket connected")
+
+			s.control = conn
+			s.controlConnected <- true
+			return nil
+		}
+
+		s.conns[conn] = struct{}{}
+
+		go s.forward(conn)
+
+		return nil
+	}
+
+	// If we didn't find the right secret, the user provided a bad one,
+	// which 403, not 404, since this operation actually exists.
+	return os.ErrPermission
+}
+
+// forward mirrors a data websocket to a new connection inside the instance until either side is done.
+func (s *portForwardWs) forward(conn *websocket.Conn) {
+	l := logger.AddContext(logger.Ctx{"instance": s.instance.Name(), "project": s.instance.Project().Name, "protocol": s.protocol, "address": s.address, "remote": conn.RemoteAddr().String()})
+
+	l.Debug("Started port forwarding")
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+
+	err := s.instance.PortForward(s.done, conn, s.protocol, s.address)
+	if err != nil {
+		l.Debug("Failed port forwarding", logger.Ctx{"err": err})
+
+		// Let the client know why its connection was closed, within the limits of a close frame.
+		reason := err.Error()
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+
+		closeMsg = websocket.FormatCloseMessage(websocket.CloseInternalServerErr, reason)
+	}
+
+	_ = conn.WriteMessage(websocket.CloseMessage, closeMsg)
+	_ = conn.Close()
+
+	s.connsLock.Lock()
+	delete(s.conns, conn)
+	s.connsLock.Unlock()
+
+	l.Debug("Finished port forwarding")
+}
+
+// Do waits for the control websocket to be closed and then closes all the forwarded connections.
+func (s *portForwardWs) Do(ctx context.Context, _ *operations.Operation) error {
+	defer logger.Debug("Port forward websocket finished")
+
+	// The control socket is used to terminate the operation.
+	go func() {
+		defer logger.Debug("Port forward control websocket finished")
+
+		select {
+		case <-s.controlConnected:
+		case <-s.done.Done():
+			return
+		}
+
+		s.connsLock.Lock()
+		conn := s.control
+		s.connsLock.Unlock()
+
+		for {
+			_, _, err := conn.NextReader()
+			if err != nil {
+				logger.Debugf("Got error getting next reader: %v", err)
+				s.done.Cancel()
+				return
+			}
+		}
+	}()
+
+	// Wait until the control channel is done or the context is cancelled.
+	select {
+	case <-s.done.Done():
+	case <-ctx.Done():
+		s.done.Cancel()
+	}
+
+	s.connsLock.Lock()
+	defer s.connsLock.Unlock()
+
+	if s.control != nil {
+		_ = s.control.Close()
+	}
+
+	// Close all the data connections, ending their forwarding.
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+
+	return nil
+}
+
+// swagger:operation POST /1.0/instances/{name}/port-forward instances instance_port_forward_post
+//
+//	Forward a port
+//
+//	Forwards connections to a TCP or UDP port inside the instance.
+//
+//	The returned operation metadata will contain two websockets, one for data and one for control.
+//	Each connection to the data websocket opens a new connection to the port inside the instance.
+//	Closing the control websocket ends the operation and all of its connections.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: query
+//	    name: project
+//	    description: Project name
+//	    type: string
+//	    example: default
+//	  - in: body
+//	    name: port-forward
+//	    description: Port forwarding request
+//	    schema:
+//	      $ref: "#/definitions/InstancePortForwardPost"
+//	responses:
+//	  "202":
+//	    $ref: "#/responses/Operation"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func instancePortForwardPost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	instanceType, err := urlInstanceTypeDetect(r)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := request.ProjectParam(r)
+	name := r.PathValue("name")
+	if shared.IsSnapshot(name) {
+		return response.BadRequest(errors.New("Invalid instance name"))
+	}
+
+	post := api.InstancePortForwardPost{}
+	err = json.NewDecoder(r.Body).Decode(&post)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	// Forward the request if the instance is remote.
+	client, err := cluster.ConnectIfInstanceIsRemote(r.Context(), s, projectName, name, instanceType)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if client != nil {
+		url := api.NewURL().Path(version.APIVersion, "instances", name, "port-forward").Project(projectName)
+		resp, _, err := client.RawQuery(http.MethodPost, url.String(), post, "")
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		opAPI, err := resp.MetadataAsOperation()
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.ForwardedOperationResponse(opAPI)
+	}
+
+	if post.Protocol == "" {
+		post.Protocol = "tcp"
+	}
+
+	if post.Address == "" {
+		post.Address = "127.0.0.1"
+	}
+
+	// Basic parameter validation.
+	if !slices.Contains([]string{"tcp", "udp"}, post.Protocol) {
+		return response.BadRequest(fmt.Errorf("Unsupported protocol %q", post.Protocol))
+	}
+
+	if net.ParseIP(post.Address) == nil {
+		return response.BadRequest(fmt.Errorf("Invalid IP address %q", post.Address))
+	}
+
+	if post.Port < 1 || post.Port > 65535 {
+		return response.BadRequest(fmt.Errorf("Invalid port %d", post.Port))
+	}
+
+	inst, err := instance.LoadByProjectAndName(s, projectName, name)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if !inst.IsRunning() {
+		return response.BadRequest(errors.New("Instance is not running"))
+	}
+
+	if inst.IsFrozen() {
+		return response.BadRequest(errors.New("Instance is frozen"))
+	}
+
+	ws := &portForwardWs{}
+	ws.fds = map[int]string{}
+	for _, fd := range []int{-1, 0} {
+		ws.fds[fd], err = shared.RandomCryptoString()
+		if err != nil {
+			return response.InternalError(err)
+		}
+	}
+
+	ws.instance = inst
+	ws.protocol = post.Protocol
+	ws.address = net.JoinHostPort(post.Address, strconv.Itoa(post.Port))
+	ws.conns = map[*websocket.Conn]struct{}{}
+	ws.controlConnected = make(chan bool, 1)
+	ws.done = cancel.New()
+
+	instanceURL := api.NewURL().Path(version.APIVersion, "instances", ws.instance.Name()).Project(projectName)
+	args := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   instanceURL,
+		Type:        operationtype.InstancePortForward,
+		Class:       operationtype.OperationClassWebsocket,
+		Metadata:    ws.Metadata(),
+		RunHook:     ws.Do,
+		ConnectHook: ws.Connect,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/canonical/lxd/lxd/instance"
 	"github.com/canonical/lxd/lxd/instance/instancetype"
 	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/placement"
 	"github.com/canonical/lxd/lxd/project/limits"
 	"github.com/canonical/lxd/lxd/request"
 	"github.com/canonical/lxd/lxd/response"
@@ -43,6 +44,9 @@ import (
 //	operation with progress data, for the pull case, it will be a websocket
 //	operation with a number of secrets to be passed to the target server.
 //
+//	When the `fork` query parameter is set, the request body is an InstanceForkPost
+//	and new instances are started from the memory state of the running instance.
+//
 //	---
 //	consumes:
 //	  - application/json
@@ -54,6 +58,16 @@ import (
 //	    description: Project name
 //	    type: string
 //	    example: default
+//	  - in: query
+//	    name: fork
+//	    description: Fork the running instance (see InstanceForkPost)
+//	    type: boolean
+//	    example: true
+//	  - in: query
+//	    name: target-cluster-link
+//	    description: Cluster link to migrate the instance to
+//	    type: string
+//	    example: backup-cluster
 //	  - in: body
 //	    name: migration
 //	    description: Migration request
@@ -185,6 +199,15 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.SmartError(err)
 	}
 
+	// A POST to /instances/<name>?fork creates new instances from the memory state of a running instance.
+	if r.URL.Query().Has("fork") {
+		if target != "" {
+			return response.BadRequest(errors.New("Target cannot be used when forking an instance"))
+		}
+
+		return instanceForkPost(s, r, inst)
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		return response.InternalError(err)
@@ -222,6 +245,16 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// A POST to /instances/<name>?target-cluster-link=<link> migrates the instance to the cluster of the link.
+	targetClusterLink := request.QueryParam(r, "target-cluster-link")
+	if targetClusterLink != "" {
+		if target != "" {
+			return response.BadRequest(errors.New("Target and target cluster link cannot be used together"))
+		}
+
+		return instancePostClusterLink(s, r, inst, req, targetClusterLink)
+	}
+
 	var targetGroupName string
 	after, ok := strings.CutPrefix(target, instancetype.TargetClusterGroupPrefix)
 	if ok {
@@ -256,6 +289,11 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				return err
 			}
 
+			reservations, err := placement.InstanceReservations(inst.ExpandedConfig())
+			if err != nil {
+				return err
+			}
+
 			if targetMemberInfo == nil {
 				clusterGroupsAllowed := limits.GetRestrictedClusterGroups(targetProject)
 
@@ -263,6 +301,16 @@ func instancePost(d *Daemon, r *http.Request) response.Response {
 				if err != nil {
 					return err
 				}
+
+				candidateMembers, err = placement.FilterReservations(ctx, tx, candidateMembers, reservations, inst.ID())
+				if err != nil {
+					return err
+				}
+			} else if targetMemberInfo.Name != inst.Location() {
+				err = placement.CheckReservations(ctx, tx, *targetMemberInfo, reservations, inst.ID())
+				if err != nil {
+					return err
+				}
 			}
 
 			return nil
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/canonical/lxd/lxd/auth"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// instancePostClusterLink handles POST /1.0/instances/{name}?target-cluster-link={link} requests.
+// It migrates a local instance to the cluster of the given cluster link, using the identity of the link.
+func instancePostClusterLink(s *state.State, r *http.Request, inst instance.Instance, req api.InstancePost, clusterLinkName string) response.Response {
+	// The cluster link identity is used to create the instance on the other cluster.
+	err := s.Authorizer.CheckPermission(r.Context(), entity.ClusterLinkURL(clusterLinkName), auth.EntitlementCanEdit)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	if inst.IsSnapshot() {
+		return response.BadRequest(errors.New("Snapshots cannot be migrated to another cluster"))
+	}
+
+	if inst.IsRunning() && !req.Live {
+		return response.BadRequest(errors.New("Running instances can only be migrated to another cluster with live migration, stop the instance first"))
+	}
+
+	if req.Target != nil {
+		return response.BadRequest(errors.New("A migration target cannot be used together with a target cluster link"))
+	}
+
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+	err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
+		return err
+	})
+	if err != nil {
+		return response.SmartError(fmt.Errorf("Failed loading cluster link %q: %w", clusterLinkName, err))
+	}
+
+	targetProjectName := req.Project
+	if targetProjectName == "" {
+		targetProjectName = inst.Project().Name
+	}
+
+	instReq, err := instanceClusterLinkRequest(inst, req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	run := func(ctx context.Context, op *operations.Operation) error {
+		targetClient, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(s.Endpoints.NetworkCert(), targetCert))
+		if err != nil {
+			return fmt.Errorf("Failed connecting to cluster link %q: %w", clusterLinkName, err)
+		}
+
+		defer targetClient.Disconnect()
+
+		targetClient = targetClient.UseProject(targetProjectName)
+
+		// Set up a push-mode migration sink on the other cluster, so that it doesn't need to reach back to us.
+		destOp, err := targetClient.CreateInstance(instReq)
+		if err != nil {
+			return fmt.Errorf("Failed creating instance on cluster link %q: %w", clusterLinkName, err)
+		}
+
+		destOpCancelled := false
+		defer func() {
+			if !destOpCancelled {
+				_ = destOp.Cancel()
+			}
+		}()
+
+		destOpAPI := destOp.Get()
+		destSecrets, err := destOpAPI.WebsocketSecrets()
+		if err != nil {
+			return fmt.Errorf("Failed getting websocket secrets from cluster link %q: %w", clusterLinkName, err)
+		}
+
+		pushTarget := &api.InstancePostTarget{
+			Operation:   destOp.URL().String(),
+			Websockets:  destSecrets,
+			Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: targetCert.Raw})),
+		}
+
+		srcMigration, err := newMigrationSource(inst, req.Live && inst.IsRunning(), req.InstanceOnly, req.AllowInconsistent, "", pushTarget)
+		if err != nil {
+			return fmt.Errorf("Failed setting up migration source: %w", err)
+		}
+
+		// The source is now connected to the sink, cancelling the sink would interrupt an in-flight transfer.
+		destOpCancelled = true
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-done:
+			case <-ctx.Done():
+				srcMigration.disconnect()
+			}
+		}()
+
+		err = srcMigration.Do(ctx, s, op)
+		if err != nil {
+			return err
+		}
+
+		err = destOp.Wait()
+		if err != nil {
+			return fmt.Errorf("Failed migrating instance to cluster link %q: %w", clusterLinkName, err)
+		}
+
+		if req.KeepSource {
+			return nil
+		}
+
+		// Remove the source instance now that it runs on the other cluster.
+		if inst.IsRunning() {
+			err = inst.Stop(ctx, false)
+			if err != nil {
+				return fmt.Errorf("Failed stopping source instance: %w", err)
+			}
+		}
+
+		err = inst.Delete(ctx, true, "", op)
+		if err != nil {
+			return fmt.Errorf("Failed deleting source instance: %w", err)
+		}
+
+		return nil
+	}
+
+	args := operations.OperationArgs{
+		ProjectName: inst.Project().Name,
+		EntityURL:   entity.InstanceURL(inst.Project().Name, inst.Name()),
+		Type:        operationtype.InstanceMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook:     run,
+	}
+
+	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
+	if err != nil {
+		return response.InternalError(err)
+	}
+
+	return response.OperationResponse(op)
+}
+
+// instanceClusterLinkRequest returns the request creating the migration sink of an instance on another cluster.
+func instanceClusterLinkRequest(inst instance.Instance, req api.InstancePost) (api.InstancesPost, error) {
+	renderRes, _, err := inst.Render()
+	if err != nil {
+		return api.InstancesPost{}, fmt.Errorf("Failed rendering instance: %w", err)
+	}
+
+	apiInst, ok := renderRes.(*api.Instance)
+	if !ok {
+		return api.InstancesPost{}, errors.New("Unexpected result from instance render")
+	}
+
+	instReq := api.InstancesPost{
+		Name:        req.Name,
+		Type:        api.InstanceType(apiInst.Type),
+		InstancePut: apiInst.Writable(),
+		Source: api.InstanceSource{
+			Type:              api.SourceTypeMigration,
+			Mode:              "push",
+			Live:              req.Live && inst.IsRunning(),
+			InstanceOnly:      req.InstanceOnly,
+			AllowInconsistent: req.AllowInconsistent,
+		},
+	}
+
+	if req.Config != nil {
+		instReq.Config = req.Config
+	}
+
+	if req.Devices != nil {
+		instReq.Devices = req.Devices
+	}
+
+	if req.Profiles != nil {
+		instReq.Profiles = req.Profiles
+	}
+
+	// Keep the root disk of the instance as a local device when moving it to a specific storage pool.
+	if req.Pool != "" {
+		rootDevKey, rootDev, err := api.GetRootDiskDevice(inst.ExpandedDevices().CloneNative())
+		if err != nil {
+			return api.InstancesPost{}, err
+		}
+
+		if instReq.Devices == nil {
+			instReq.Devices = map[string]map[string]string{}
+		}
+
+		rootDev["pool"] = req.Pool
+		instReq.Devices[rootDevKey] = rootDev
+	}
+
+	return instReq, nil
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/lxd/db"
+	deviceConfig "github.com/canonical/lxd/lxd/device/config"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// clusterLinkTestInstance is an instance that only implements what instanceClusterLinkRequest needs.
+type clusterLinkTestInstance struct {
+	instance.Instance
+
+	apiInst api.Instance
+	running bool
+}
+
+func (c *clusterLinkTestInstance) Render(options ...func(response any) error) (any, any, error) {
+	// Like a real instance, each render returns new device maps.
+	apiInst := c.apiInst
+	apiInst.Devices = deviceConfig.NewDevices(c.apiInst.Devices).CloneNative()
+
+	return &apiInst, apiInst.Writable(), nil
+}
+
+func (c *clusterLinkTestInstance) IsRunning() bool {
+	return c.running
+}
+
+func (c *clusterLinkTestInstance) ExpandedDevices() deviceConfig.Devices {
+	return deviceConfig.NewDevices(c.apiInst.ExpandedDevices)
+}
+
+func TestInstanceClusterLinkRequest(t *testing.T) {
+	apiInst := api.Instance{
+		Name:     "c1",
+		Type:     string(api.InstanceTypeVM),
+		Config:   map[string]string{"limits.cpu": "2"},
+		Devices:  map[string]map[string]string{"eth0": {"type": "nic", "network": "lxdbr0"}},
+		Profiles: []string{"default"},
+		ExpandedDevices: map[string]map[string]string{
+			"eth0": {"type": "nic", "network": "lxdbr0"},
+			"root": {"type": "disk", "path": "/", "pool": "default", "size": "10GiB"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		running bool
+		req     api.InstancePost
+		want    api.InstancesPost
+	}{
+		{
+			name: "Stopped instance",
+			req:  api.InstancePost{Name: "c2", Live: true, InstanceOnly: true},
+			want: api.InstancesPost{
+				Name:        "c2",
+				Type:        api.InstanceTypeVM,
+				InstancePut: apiInst.Writable(),
+				Source:      api.InstanceSource{Type: api.SourceTypeMigration, Mode: "push", InstanceOnly: true},
+			},
+		},
+		{
+			name:    "Live migration",
+			running: true,
+			req:     api.InstancePost{Name: "c1", Live: true, AllowInconsistent: true},
+			want: api.InstancesPost{
+				Name:        "c1",
+				Type:        api.InstanceTypeVM,
+				InstancePut: apiInst.Writable(),
+				Source:      api.InstanceSource{Type: api.SourceTypeMigration, Mode: "push", Live: true, AllowInconsistent: true},
+			},
+		},
+		{
+			name: "Overridden config, devices and profiles",
+			req: api.InstancePost{
+				Name:     "c1",
+				Config:   map[string]string{"limits.cpu": "4"},
+				Devices:  map[string]map[string]string{},
+				Profiles: []string{},
+			},
+			want: api.InstancesPost{
+				Name: "c1",
+				Type: api.InstanceTypeVM,
+				InstancePut: api.InstancePut{
+					Config:   map[string]string{"limits.cpu": "4"},
+					Devices:  map[string]map[string]string{},
+					Profiles: []string{},
+				},
+				Source: api.InstanceSource{Type: api.SourceTypeMigration, Mode: "push"},
+			},
+		},
+		{
+			name: "Target storage pool",
+			req:  api.InstancePost{Name: "c1", Pool: "remote"},
+			want: api.InstancesPost{
+				Name: "c1",
+				Type: api.InstanceTypeVM,
+				InstancePut: api.InstancePut{
+					Config: map[string]string{"limits.cpu": "2"},
+					Devices: map[string]map[string]string{
+						"eth0": {"type": "nic", "network": "lxdbr0"},
+						"root": {"type": "disk", "path": "/", "pool": "remote", "size": "10GiB"},
+					},
+					Profiles: []string{"default"},
+				},
+				Source: api.InstanceSource{Type: api.SourceTypeMigration, Mode: "push"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := &clusterLinkTestInstance{apiInst: apiInst, running: tt.running}
+
+			instReq, err := instanceClusterLinkRequest(inst, tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, instReq)
+		})
+	}
+
+	// The devices of the source instance are left untouched.
+	assert.Equal(t, "default", apiInst.ExpandedDevices["root"]["pool"])
+}
+
+type instancePostClusterLinkTestSuite struct {
+	lxdTestSuite
+}
+
+// TestInstancePostClusterLink_Validation tests the requests rejected before any operation is created.
+func (suite *instancePostClusterLinkTestSuite) TestInstancePostClusterLink_Validation() {
+	args := db.InstanceArgs{
+		Type: instancetype.Container,
+		Name: "c1",
+	}
+
+	inst, op, _, err := instance.CreateInternal(suite.T().Context(), suite.d.State(), args, true)
+	suite.Req.NoError(err)
+	op.Done(nil)
+	defer func() { _ = inst.Delete(suite.T().Context(), true, "", nil) }()
+
+	tests := []struct {
+		name       string
+		trusted    bool
+		req        api.InstancePost
+		statusCode int
+		message    string
+	}{
+		{
+			name:       "Untrusted caller",
+			req:        api.InstancePost{Name: "c1"},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Migration target",
+			trusted:    true,
+			req:        api.InstancePost{Name: "c1", Migration: true, Target: &api.InstancePostTarget{}},
+			statusCode: http.StatusBadRequest,
+			message:    "A migration target cannot be used together with a target cluster link",
+		},
+		{
+			name:       "Unknown cluster link",
+			trusted:    true,
+			req:        api.InstancePost{Name: "c1"},
+			statusCode: http.StatusNotFound,
+			message:    `Failed loading cluster link "dr"`,
+		},
+	}
+
+	for _, test := range tests {
+		suite.Run(test.name, func() {
+			req := httptest.NewRequest(http.MethodPost, "/1.0/instances/c1?target-cluster-link=dr", nil)
+
+			requestorArgs := request.RequestorArgs{}
+			if test.trusted {
+				requestorArgs = request.RequestorArgs{Trusted: true, Username: "root", Protocol: request.ProtocolUnix}
+			}
+
+			suite.Req.NoError(request.SetRequestor(req, nil, requestorArgs))
+
+			resp := instancePostClusterLink(suite.d.State(), req, inst, test.req, "dr")
+
+			w := httptest.NewRecorder()
+			suite.Req.NoError(resp.Render(w, req))
+			suite.Equal(test.statusCode, w.Result().StatusCode)
+			suite.Contains(resp.String(), test.message)
+		})
+	}
+}
+
+func TestInstancePostClusterLinkTestSuite(t *testing.T) {
+	suite.Run(t, new(instancePostClusterLinkTestSuite))
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/warningtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/warnings"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// instancePressureCheckTask returns a task that checks the resource pressure of the local instances against their
+// pressure.*.threshold settings.
+func instancePressureCheckTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		instances, err := instance.LoadNodeAll(s, instancetype.Any)
+		if err != nil {
+			logger.Error("Failed loading instances for pressure check", logger.Ctx{"err": err})
+			return
+		}
+
+		for _, inst := range instances {
+			instancePressureCheck(ctx, s, inst)
+		}
+	}
+
+	first := true
+	schedule := func() (time.Duration, error) {
+		// Skip the first run to give the instances some time to start after daemon startup.
+		if first {
+			first = false
+			return time.Minute, task.ErrSkip
+		}
+
+		return time.Minute, nil
+	}
+
+	return f, schedule
+}
+
+// instancePressureThresholds returns the pressure thresholds set in the given config, keyed by resource.
+func instancePressureThresholds(config map[string]string) map[string]float64 {
+	thresholds := make(map[string]float64)
+	for _, resource := range metrics.PressureResources {
+		value := config["pressure."+resource+".threshold"]
+		if value == "" {
+			continue
+		}
+
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+
+		thresholds[resource] = threshold
+	}
+
+	return thresholds
+}
+
+// instancePressureExceeded returns a description of the resources whose pressure is above the given thresholds.
+func instancePressureExceeded(pressure map[string]api.InstanceStatePressure, thresholds map[string]float64) string {
+	exceeded := []string{}
+	for _, resource := range metrics.PressureResources {
+		threshold, ok := thresholds[resource]
+		if !ok {
+			continue
+		}
+
+		value, ok := pressure[resource]
+		if !ok || value.SomeAvg60 <= threshold {
+			continue
+		}
+
+		exceeded = append(exceeded, fmt.Sprintf("%s pressure %.2f%% is above threshold %.0f%%", resource, value.SomeAvg60, threshold))
+	}
+
+	return strings.Join(exceeded, ", ")
+}
+
+// instancePressureCheck raises or resolves the pressure warning of a local instance.
+func instancePressureCheck(ctx context.Context, s *state.State, inst instance.Instance) {
+	thresholds := instancePressureThresholds(inst.ExpandedConfig())
+	if len(thresholds) == 0 {
+		return
+	}
+
+	l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+	exceeded := ""
+	if inst.IsRunning() {
+		instState, err := inst.RenderState(nil, instance.StateRenderOptions{})
+		if err != nil {
+			l.Debug("Failed getting instance state for pressure check", logger.Ctx{"err": err})
+			return
+		}
+
+		exceeded = instancePressureExceeded(instState.Pressure, thresholds)
+	}
+
+	if exceeded == "" {
+		err := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, inst.Project().Name, warningtype.InstancePressureThresholdExceeded, entity.TypeInstance, inst.ID())
+		if err != nil {
+			l.Warn("Failed resolving instance pressure warning", logger.Ctx{"err": err})
+		}
+
+		return
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, entity.TypeInstance, inst.ID(), warningtype.InstancePressureThresholdExceeded, exceeded)
+	})
+	if err != nil {
+		l.Warn("Failed creating instance pressure warning", logger.Ctx{"err": err})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"maps"
 	"net/http"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -21,6 +22,7 @@ import (
 	"github.com/canonical/lxd/lxd/util"
 	"github.com/canonical/lxd/shared"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
 	"github.com/canonical/lxd/shared/osarch"
 	"github.com/canonical/lxd/shared/revert"
 	"github.com/canonical/lxd/shared/version"
@@ -98,14 +100,25 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
-	architecture, err := osarch.ArchitectureId(configRaw.Architecture)
-	if err != nil {
-		architecture = 0
-	}
-
 	var do func(context.Context, *operations.Operation) error
 	var opType operationtype.Type
+	var approvalExpiry time.Duration
 	if configRaw.Restore == "" {
+		err = webhookAdmit(r, s, entity.TypeInstance, api.WebhookAdmissionOperationUpdate, projectName, entity.InstanceURL(projectName, name), &configRaw)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		approvalExpiry, err = instanceProtectionApprovalExpiry(r.Context(), s, projectName, inst.LocalConfig(), configRaw.Config)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		architecture, err := osarch.ArchitectureId(configRaw.Architecture)
+		if err != nil {
+			architecture = 0
+		}
+
 		// Check project limits.
 		apiProfiles := make([]api.Profile, 0, len(configRaw.Profiles))
 		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
@@ -139,20 +152,20 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 			return response.SmartError(err)
 		}
 
+		args := db.InstanceArgs{
+			Architecture: architecture,
+			Config:       configRaw.Config,
+			Description:  configRaw.Description,
+			Devices:      deviceConfig.NewDevices(configRaw.Devices),
+			Ephemeral:    configRaw.Ephemeral,
+			Profiles:     apiProfiles,
+			Project:      projectName,
+		}
+
 		// Update container configuration
 		do = func(ctx context.Context, _ *operations.Operation) error {
 			defer unlock()
 
-			args := db.InstanceArgs{
-				Architecture: architecture,
-				Config:       configRaw.Config,
-				Description:  configRaw.Description,
-				Devices:      deviceConfig.NewDevices(configRaw.Devices),
-				Ephemeral:    configRaw.Ephemeral,
-				Profiles:     apiProfiles,
-				Project:      projectName,
-			}
-
 			err = inst.Update(ctx, args, instance.UpdateActionUser)
 			if err != nil {
 				return err
@@ -162,6 +175,20 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 		}
 
 		opType = operationtype.InstanceUpdate
+
+		if approvalExpiry > 0 {
+			// Don't hold the instance lock while waiting for approval. It is taken again and the update is checked
+			// against the current instance once approved.
+			unlock()
+			unlock = func() {}
+
+			etag, err := instanceEtag(inst)
+			if err != nil {
+				return response.InternalError(err)
+			}
+
+			do = instanceApprovedUpdate(s, projectName, name, etag, configRaw, args)
+		}
 	} else {
 		// Snapshot Restore
 		do = func(ctx context.Context, op *operations.Operation) error {
@@ -174,11 +201,12 @@ func instancePut(d *Daemon, r *http.Request) response.Response {
 	}
 
 	args := operations.OperationArgs{
-		ProjectName: projectName,
-		EntityURL:   api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName),
-		Type:        opType,
-		Class:       operationtype.OperationClassTask,
-		RunHook:     do,
+		ProjectName:    projectName,
+		EntityURL:      api.NewURL().Path(version.APIVersion, "instances", name).Project(projectName),
+		Type:           opType,
+		Class:          operationtype.OperationClassTask,
+		RunHook:        do,
+		ApprovalExpiry: approvalExpiry,
 	}
 
 	op, err := operations.ScheduleUserOperationFromRequest(s, r, args)
@@ -132,6 +132,14 @@ var instanceExecCmd = APIEndpoint{
 	Post: APIEndpointAction{Handler: instanceExecPost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanExec, "name")},
 }
 
+var instancePortForwardCmd = APIEndpoint{
+	Path:            "instances/{name}/port-forward",
+	MetricsType:     entity.TypeInstance,
+	ProjectSpecific: true,
+
+	Post: APIEndpointAction{Handler: instancePortForwardPost, AccessHandler: allowPermission(entity.TypeInstance, auth.EntitlementCanPortForward, "name")},
+}
+
 var instanceMetadataCmd = APIEndpoint{
 	Path:            "instances/{name}/metadata",
 	MetricsType:     entity.TypeInstance,
@@ -179,29 +187,6 @@ var instanceBackupExportCmd = APIEndpoint{
 	Get: APIEndpointAction{Handler: instanceBackupExportGet, AccessHandler: allowPermission(entity.TypeInstanceBackup, auth.EntitlementCanView, "name", "backupName")},
 }
 
-type instanceAutostartList []instance.Instance
-
-func (slice instanceAutostartList) Len() int {
-	return len(slice)
-}
-
-func (slice instanceAutostartList) Less(i, j int) bool {
-	iOrder := slice[i].ExpandedConfig()["boot.autostart.priority"]
-	jOrder := slice[j].ExpandedConfig()["boot.autostart.priority"]
-
-	if iOrder != jOrder {
-		iOrderInt, _ := strconv.Atoi(iOrder)
-		jOrderInt, _ := strconv.Atoi(jOrder)
-		return iOrderInt > jOrderInt
-	}
-
-	return slice[i].Name() < slice[j].Name()
-}
-
-func (slice instanceAutostartList) Swap(i, j int) {
-	slice[i], slice[j] = slice[j], slice[i]
-}
-
 var instancesStartMu sync.Mutex
 
 // instanceShouldAutoStart returns whether the instance should be auto-started.
@@ -227,8 +212,12 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 	instancesStartMu.Lock()
 	defer instancesStartMu.Unlock()
 
-	// Sort based on instance boot priority.
-	sort.Sort(instanceAutostartList(instances))
+	// Sort based on instance boot dependencies and priority.
+	instances = instance.BootOrder(instances)
+
+	// Keep track of the instances that others depend on and of those that failed to start or become healthy.
+	dependencies := instancesBootDependencies(instances)
+	failed := make(map[string]error)
 
 	// Let's make up to 3 attempts to start instances.
 	maxAttempts := 3
@@ -248,8 +237,19 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 		config := inst.ExpandedConfig()
 		autoStartDelay := config["boot.autostart.delay"]
 
+		instKey := instanceBootKey(inst.Project().Name, inst.Name())
 		instLogger := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
 
+		// Don't start instances whose dependencies aren't ready.
+		err := instanceBootDependencyError(inst, failed)
+		if err != nil {
+			failed[instKey] = err
+			instanceAutostartWarning(s, inst, err)
+			instLogger.Error("Failed auto-starting instance", logger.Ctx{"err": err})
+
+			continue
+		}
+
 		// Try to start the instance.
 		var attempt = 0
 		for {
@@ -258,6 +258,8 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 			// Don't track progress here as there is no client to return the updates to.
 			err := inst.Start(ctx, false, nil)
 			if err != nil {
+				failed[instKey] = err
+
 				if api.StatusErrorCheck(err, http.StatusServiceUnavailable) {
 					break // Don't log or retry instances that are not ready to start yet.
 				}
@@ -265,14 +267,8 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 				instLogger.Warn("Failed auto start instance attempt", logger.Ctx{"attempt": attempt, "maxAttempts": maxAttempts, "err": err})
 
 				if attempt >= maxAttempts {
-					warnErr := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
-						// If unable to start after 3 tries, record a warning.
-						return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, entity.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, err.Error())
-					})
-					if warnErr != nil {
-						instLogger.Warn("Failed creating instance autostart failure warning", logger.Ctx{"err": warnErr})
-					}
-
+					// If unable to start after 3 tries, record a warning.
+					instanceAutostartWarning(s, inst, err)
 					instLogger.Error("Failed auto-starting instance", logger.Ctx{"err": err})
 
 					break
@@ -283,12 +279,23 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 				continue
 			}
 
+			delete(failed, instKey)
+
 			// Resolve any previous warning.
 			warnErr := warnings.ResolveWarningsByLocalNodeAndProjectAndTypeAndEntity(s.DB.Cluster, inst.Project().Name, warningtype.InstanceAutostartFailure, entity.TypeInstance, inst.ID())
 			if warnErr != nil {
 				instLogger.Warn("Failed resolving instance autostart failure warning", logger.Ctx{"err": warnErr})
 			}
 
+			// Wait for the instance to be healthy if other instances depend on it.
+			if dependencies[instKey] {
+				err = instanceBootWaitHealthy(ctx, inst)
+				if err != nil {
+					failed[instKey] = err
+					instLogger.Warn("Instance didn't become healthy", logger.Ctx{"err": err})
+				}
+			}
+
 			// Wait the auto-start delay if set.
 			autoStartDelayInt, err := strconv.Atoi(autoStartDelay)
 			if err == nil {
@@ -300,6 +307,16 @@ func instancesStart(ctx context.Context, s *state.State, instances []instance.In
 	}
 }
 
+// instanceAutostartWarning records an instance autostart failure warning.
+func instanceAutostartWarning(s *state.State, inst instance.Instance, err error) {
+	warnErr := s.DB.Cluster.Transaction(context.TODO(), func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.UpsertWarningLocalNode(ctx, inst.Project().Name, entity.TypeInstance, inst.ID(), warningtype.InstanceAutostartFailure, err.Error())
+	})
+	if warnErr != nil {
+		logger.Warn("Failed creating instance autostart failure warning", logger.Ctx{"project": inst.Project().Name, "instance": inst.Name(), "err": warnErr})
+	}
+}
+
 type instanceStopList []instance.Instance
 
 func (slice instanceStopList) Len() int {
@@ -131,6 +131,11 @@ func createFromImage(r *http.Request, s *state.State, p api.Project, profiles []
 			return errors.New("Image not provided for instance creation")
 		}
 
+		err = newImageSignaturePolicy(p.Config).check(img.Fingerprint, img.Signatures)
+		if err != nil {
+			return err
+		}
+
 		args.Architecture, err = osarch.ArchitectureId(img.Architecture)
 		if err != nil {
 			return err
@@ -1428,6 +1433,14 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// Requests forwarded to the target member were already reviewed by the member that received them.
+	if !requestor.IsForwarded() {
+		err = webhookAdmit(r, s, entity.TypeInstance, api.WebhookAdmissionOperationCreate, targetProjectName, entity.InstanceURL(targetProjectName, req.Name), &req)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	var targetProject *api.Project
 	var profiles []api.Profile
 	var sourceInst *dbCluster.Instance
@@ -1682,8 +1695,25 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 			}
 
 			expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
+			reservations, err := placement.InstanceReservations(expandedConfig)
+			if err != nil {
+				return err
+			}
+
 			placementGroupName = expandedConfig["placement.group"]
-			targetMemberInfo, err = instancesPostSelectClusterMember(ctx, tx, placementGroupName, candidateMembers, targetProject.Name)
+			targetMemberInfo, err = instancesPostSelectClusterMember(ctx, tx, placementGroupName, candidateMembers, targetProject.Name, reservations)
+			if err != nil {
+				return err
+			}
+		} else if s.ServerClustered && !clusterNotification && target != "" && !req.Source.Refresh && targetMemberInfo != nil {
+			// Refuse placing the instance on the requested member if it cannot accommodate its reservations.
+			expandedConfig := instancetype.ExpandInstanceConfig(s.GlobalConfig.Dump(), req.Config, profiles)
+			reservations, err := placement.InstanceReservations(expandedConfig)
+			if err != nil {
+				return err
+			}
+
+			err = placement.CheckReservations(ctx, tx, *targetMemberInfo, reservations, 0)
 			if err != nil {
 				return err
 			}
@@ -1804,7 +1834,14 @@ func instancesPost(d *Daemon, r *http.Request) response.Response {
 // It first checks whether the instance belongs to a placement group and, if so, applies the placement group’s policy and rigor to filter the available members.
 // Among the remaining candidates, the member with the fewest existing instances is selected.
 // If the instance does not belong to a placement group, the member with the fewest instances is chosen from all candidates.
-func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string) (*db.NodeInfo, error) {
+// Members that cannot accommodate the given reservations are never selected.
+func instancesPostSelectClusterMember(ctx context.Context, tx *db.ClusterTx, placementGroupName string, candidateMembers []db.NodeInfo, projectName string, reservations placement.Reservations) (*db.NodeInfo, error) {
+	// Skip members whose committed reservations leave no room for the instance.
+	candidateMembers, err := placement.FilterReservations(ctx, tx, candidateMembers, reservations, 0)
+	if err != nil {
+		return nil, err
+	}
+
 	// Check if instance is using a placement group.
 	if placementGroupName == "" {
 		return tx.GetNodeWithLeastInstances(ctx, candidateMembers)
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"sync"
 
 	"github.com/canonical/lxd/lxd/auth"
 	"github.com/canonical/lxd/lxd/cluster"
@@ -108,6 +110,21 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	// Only consider the requested instances.
+	if len(req.Instances) > 0 {
+		selected := make([]instance.Instance, 0, len(req.Instances))
+		for _, name := range req.Instances {
+			idx := slices.IndexFunc(instances, func(inst instance.Instance) bool { return inst.Name() == name })
+			if idx < 0 {
+				return response.NotFound(fmt.Errorf("Instance %q not found", name))
+			}
+
+			selected = append(selected, instances[idx])
+		}
+
+		instances = selected
+	}
+
 	action := instancetype.InstanceAction(req.State.Action)
 
 	userHasPermission, err := s.Authorizer.GetPermissionChecker(r.Context(), auth.EntitlementCanUpdateState, entity.TypeInstance)
@@ -122,6 +139,18 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	// When starting instances, each instance waits for its boot dependencies to be started (and healthy).
+	bootDependencies := map[string]bool{}
+	bootDone := map[string]chan struct{}{}
+	bootFailed := map[string]error{}
+	var bootFailedMu sync.Mutex
+	if action == instancetype.Start {
+		bootDependencies = instancesBootDependencies(instances)
+		for _, inst := range instances {
+			bootDone[instanceBootKey(inst.Project().Name, inst.Name())] = make(chan struct{})
+		}
+	}
+
 	// Batch the changes.
 	childRunHookDo := func(ctx context.Context, op *operations.Operation, inst instance.Instance) error {
 		// Get node member where the instance is located.
@@ -143,7 +172,17 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 			// However, the operation is not available on other members handling instance updates. So, we don't set
 			// the operation on instance here to keep the same behavior on all members.
 
-			return doInstanceStatePut(ctx, inst, *req.State, op)
+			err := doInstanceStatePut(ctx, inst, *req.State, op)
+			if err != nil {
+				return err
+			}
+
+			// Wait for the instance to be healthy if other instances depend on it.
+			if bootDependencies[instanceBootKey(inst.Project().Name, inst.Name())] {
+				return instanceBootWaitHealthy(ctx, inst)
+			}
+
+			return nil
 		}
 
 		// Record the results.
@@ -164,11 +203,62 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 			Stateful: req.State.Stateful,
 		}
 
+		// Wait for the instance to be started and healthy if other instances depend on it.
+		if bootDependencies[instanceBootKey(inst.Project().Name, inst.Name())] {
+			stateOp, err := client.UseProject(projectName).UpdateInstanceState(inst.Name(), req, "")
+			if err != nil {
+				return err
+			}
+
+			err = stateOp.WaitContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			url := api.NewURL().Path("internal", "instances", inst.Name(), "boot-health").Project(projectName)
+			_, _, err = client.RawQuery(http.MethodGet, url.String(), nil, "")
+			return err
+		}
+
 		url := api.NewURL().Path(version.APIVersion, "instances", inst.Name(), "state").Project(projectName)
 		_, _, err = client.RawQuery(http.MethodPut, url.String(), req, "")
 		return err
 	}
 
+	// Start the instance once its boot dependencies are ready.
+	childRunHookStart := func(ctx context.Context, op *operations.Operation, inst instance.Instance) error {
+		instKey := instanceBootKey(inst.Project().Name, inst.Name())
+		defer close(bootDone[instKey])
+
+		for _, dependency := range instance.BootDependencies(inst.ExpandedConfig()) {
+			done, ok := bootDone[instanceBootKey(inst.Project().Name, dependency)]
+			if !ok {
+				continue
+			}
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		bootFailedMu.Lock()
+		err := instanceBootDependencyError(inst, bootFailed)
+		bootFailedMu.Unlock()
+		if err == nil {
+			err = childRunHookDo(ctx, op, inst)
+		}
+
+		if err != nil {
+			bootFailedMu.Lock()
+			bootFailed[instKey] = err
+			bootFailedMu.Unlock()
+		}
+
+		return err
+	}
+
 	// Set the child operations for each instance under a single parent operation on the project.
 	opType, err := instanceActionToOptype(string(action))
 	if err != nil {
@@ -180,6 +270,10 @@ func instancesPut(d *Daemon, r *http.Request) response.Response {
 		// Create a run hook function for the child operations that captures the instance in its closure.
 		childRunHook := func(inst instance.Instance) func(ctx context.Context, op *operations.Operation) error {
 			return func(ctx context.Context, op *operations.Operation) error {
+				if action == instancetype.Start {
+					return childRunHookStart(ctx, op, inst)
+				}
+
 				return childRunHookDo(ctx, op, inst)
 			}
 		}
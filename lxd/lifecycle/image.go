@@ -16,6 +16,7 @@ const (
 	ImageRetrieved     = ImageAction(api.EventLifecycleImageRetrieved)
 	ImageRefreshed     = ImageAction(api.EventLifecycleImageRefreshed)
 	ImageSecretCreated = ImageAction(api.EventLifecycleImageSecretCreated)
+	ImageSBOMGenerated = ImageAction(api.EventLifecycleImageSBOMGenerated)
 )
 
 // Event creates the lifecycle event for an action on an image.
@@ -39,6 +39,7 @@ const (
 	InstanceFileRetrieved    = InstanceAction(api.EventLifecycleInstanceFileRetrieved)
 	InstanceFilePushed       = InstanceAction(api.EventLifecycleInstanceFilePushed)
 	InstanceFileDeleted      = InstanceAction(api.EventLifecycleInstanceFileDeleted)
+	InstanceForked           = InstanceAction(api.EventLifecycleInstanceForked)
 )
 
 // Event creates the lifecycle event for an action on an instance.
@@ -15,7 +15,9 @@ type OperationAction string
 
 // All supported lifecycle events for operations.
 const (
+	OperationApproved  = OperationAction(api.EventLifecycleOperationApproved)
 	OperationCancelled = OperationAction(api.EventLifecycleOperationCancelled)
+	OperationRejected  = OperationAction(api.EventLifecycleOperationRejected)
 )
 
 // Event creates the lifecycle event for an action on an operation.
@@ -10,10 +10,14 @@ type ProjectAction string
 
 // All supported lifecycle events for projects.
 const (
-	ProjectCreated = ProjectAction(api.EventLifecycleProjectCreated)
-	ProjectDeleted = ProjectAction(api.EventLifecycleProjectDeleted)
-	ProjectUpdated = ProjectAction(api.EventLifecycleProjectUpdated)
-	ProjectRenamed = ProjectAction(api.EventLifecycleProjectRenamed)
+	ProjectCreated           = ProjectAction(api.EventLifecycleProjectCreated)
+	ProjectDeleted           = ProjectAction(api.EventLifecycleProjectDeleted)
+	ProjectUpdated           = ProjectAction(api.EventLifecycleProjectUpdated)
+	ProjectRenamed           = ProjectAction(api.EventLifecycleProjectRenamed)
+	ProjectFenced            = ProjectAction(api.EventLifecycleProjectFenced)
+	ProjectLeaderReachable   = ProjectAction(api.EventLifecycleProjectLeaderReachable)
+	ProjectLeaderUnreachable = ProjectAction(api.EventLifecycleProjectLeaderUnreachable)
+	ProjectPromoted          = ProjectAction(api.EventLifecycleProjectPromoted)
 )
 
 // Event creates the lifecycle event for an action on a project.
@@ -0,0 +1,29 @@
+package lifecycle
+
+import (
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// WebhookAction represents a lifecycle event action for webhooks.
+type WebhookAction string
+
+// All supported lifecycle events for webhooks.
+const (
+	WebhookCreated = WebhookAction(api.EventLifecycleWebhookCreated)
+	WebhookDeleted = WebhookAction(api.EventLifecycleWebhookDeleted)
+	WebhookRenamed = WebhookAction(api.EventLifecycleWebhookRenamed)
+	WebhookUpdated = WebhookAction(api.EventLifecycleWebhookUpdated)
+)
+
+// Event creates the lifecycle event for an action on a webhook.
+func (a WebhookAction) Event(name string, requestor *api.EventLifecycleRequestor, ctx map[string]any) api.EventLifecycle {
+	u := entity.WebhookURL(name)
+
+	return api.EventLifecycle{
+		Action:    string(a),
+		Source:    u.String(),
+		Context:   ctx,
+		Requestor: requestor,
+	}
+}
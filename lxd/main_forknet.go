@@ -100,7 +100,7 @@ void forknet(void)
 	}
 
 	// Call the subcommands
-	if (strcmp(command, "info") == 0) {
+	if (strcmp(command, "info") == 0 || strcmp(command, "dial") == 0) {
 		int ns_fd, pidfd;
 		pid = atoi(cur);
 
@@ -125,7 +125,10 @@ import (
 	"fmt"
 	"net"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 
@@ -160,6 +163,13 @@ func (c *cmdForknet) Command() *cobra.Command {
 	cmdInfo.RunE = c.RunInfo
 	cmd.AddCommand(cmdInfo)
 
+	// dial
+	cmdDial := &cobra.Command{}
+	cmdDial.Use = "dial <PID> <PidFd> <socket fd> <protocol> <address>"
+	cmdDial.Args = cobra.ExactArgs(5)
+	cmdDial.RunE = c.RunDial
+	cmd.AddCommand(cmdDial)
+
 	// detach
 	cmdDetach := &cobra.Command{}
 	cmdDetach.Use = "detach <netns file> <LXD PID> <ifname> <hostname>"
@@ -191,6 +201,41 @@ func (c *cmdForknet) RunInfo(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// RunDial connects to an address inside the network namespace and sends the resulting socket over the socket fd.
+func (c *cmdForknet) RunDial(cmd *cobra.Command, args []string) error {
+	sockFD, err := strconv.Atoi(args[2])
+	if err != nil {
+		return fmt.Errorf("Invalid socket fd %q: %w", args[2], err)
+	}
+
+	protocol := args[3]
+	if !slices.Contains([]string{"tcp", "udp"}, protocol) {
+		return fmt.Errorf("Unsupported protocol %q", protocol)
+	}
+
+	conn, err := net.Dial(protocol, args[4])
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	rawConn, err := conn.(syscall.Conn).SyscallConn()
+	if err != nil {
+		return err
+	}
+
+	var sendErr error
+	err = rawConn.Control(func(fd uintptr) {
+		sendErr = netutils.AbstractUnixSendFd(sockFD, int(fd))
+	})
+	if err != nil {
+		return err
+	}
+
+	return sendErr
+}
+
 // RunDetach detaches a NIC from the host.
 func (c *cmdForknet) RunDetach(cmd *cobra.Command, args []string) error {
 	lxdPID := args[1]
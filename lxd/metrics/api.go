@@ -1,14 +1,19 @@
 package metrics
 
+import (
+	"github.com/canonical/lxd/shared/api"
+)
+
 // Metrics represents instance metrics.
 type Metrics struct {
-	CPU            map[string]CPUMetrics        `json:"cpu_seconds_total" yaml:"cpu_seconds_total"`
-	CPUs           int                          `json:"cpus" yaml:"cpus"`
-	Disk           map[string]DiskMetrics       `json:"disk" yaml:"disk"`
-	Filesystem     map[string]FilesystemMetrics `json:"filesystem" yaml:"filesystem"`
-	Memory         MemoryMetrics                `json:"memory" yaml:"memory"`
-	Network        map[string]NetworkMetrics    `json:"network" yaml:"network"`
-	ProcessesTotal uint64                       `json:"procs_total" yaml:"procs_total"`
+	CPU            map[string]CPUMetrics                `json:"cpu_seconds_total" yaml:"cpu_seconds_total"`
+	CPUs           int                                  `json:"cpus" yaml:"cpus"`
+	Disk           map[string]DiskMetrics               `json:"disk" yaml:"disk"`
+	Filesystem     map[string]FilesystemMetrics         `json:"filesystem" yaml:"filesystem"`
+	Memory         MemoryMetrics                        `json:"memory" yaml:"memory"`
+	Network        map[string]NetworkMetrics            `json:"network" yaml:"network"`
+	Pressure       map[string]api.InstanceStatePressure `json:"pressure,omitempty" yaml:"pressure,omitempty"`
+	ProcessesTotal uint64                               `json:"procs_total" yaml:"procs_total"`
 }
 
 // CPUMetrics represents CPU metrics for an instance.
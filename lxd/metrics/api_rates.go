@@ -31,6 +31,35 @@ func GetRequestResultsNames() map[RequestResult]string {
 	return requestResultNames
 }
 
+// ThrottleReason represents the API limit that caused a request to be rejected.
+type ThrottleReason int8
+
+// This defines every possible throttle reason to be used as a metric label.
+const (
+	ThrottledRequestRate ThrottleReason = iota
+	ThrottledOperations
+)
+
+var throttleReasonNames = map[ThrottleReason]string{
+	ThrottledRequestRate: "request_rate",
+	ThrottledOperations:  "operations",
+}
+
+// GetThrottleReasonNames returns a map containing all possible throttle reasons and their names.
+func GetThrottleReasonNames() map[ThrottleReason]string {
+	return throttleReasonNames
+}
+
+// ThrottledMetricsEntityTypes returns the entity types that API limits are configured on.
+func ThrottledMetricsEntityTypes() []entity.Type {
+	return []entity.Type{entity.TypeIdentity, entity.TypeProject}
+}
+
+type throttledMetricsLabeling struct {
+	entityType entity.Type
+	reason     ThrottleReason
+}
+
 type completedMetricsLabeling struct {
 	entityType entity.Type
 	result     RequestResult
@@ -38,6 +67,7 @@ type completedMetricsLabeling struct {
 
 var ongoingRequests map[entity.Type]*atomic.Int64
 var completedRequests map[completedMetricsLabeling]*atomic.Int64
+var throttledRequests map[throttledMetricsLabeling]*atomic.Int64
 
 // InitAPIMetrics initializes maps with initial values for the API rates metrics.
 func InitAPIMetrics() {
@@ -51,6 +81,14 @@ func InitAPIMetrics() {
 			completedRequests[completedMetricsLabeling{entityType: entityType, result: result}] = new(atomic.Int64)
 		}
 	}
+
+	throttledEntityTypes := ThrottledMetricsEntityTypes()
+	throttledRequests = make(map[throttledMetricsLabeling]*atomic.Int64, len(throttledEntityTypes)*len(throttleReasonNames))
+	for _, entityType := range throttledEntityTypes {
+		for reason := range throttleReasonNames {
+			throttledRequests[throttledMetricsLabeling{entityType: entityType, reason: reason}] = new(atomic.Int64)
+		}
+	}
 }
 
 // countStartedRequest should be called before each request handler to keep track of ongoing requests.
@@ -74,6 +112,16 @@ func GetCompletedRequests(entityType entity.Type, result RequestResult) int64 {
 	return completedRequests[completedMetricsLabeling{entityType: entityType, result: result}].Load()
 }
 
+// CountThrottledRequest should be called when a request is rejected because of an API limit of the given entity type.
+func CountThrottledRequest(entityType entity.Type, reason ThrottleReason) {
+	throttledRequests[throttledMetricsLabeling{entityType: entityType, reason: reason}].Add(1)
+}
+
+// GetThrottledRequests gets the value of throttled requests filtered by entity type and reason.
+func GetThrottledRequests(entityType entity.Type, reason ThrottleReason) int64 {
+	return throttledRequests[throttledMetricsLabeling{entityType: entityType, reason: reason}].Load()
+}
+
 // TrackStartedRequest tracks the request as started for the API metrics and
 // injects a callback function to track the request as completed.
 func TrackStartedRequest(r *http.Request, endpointType entity.Type) {
@@ -53,6 +53,11 @@ func (m *MetricSet) AddSamples(metricType MetricType, samples ...Sample) {
 	m.set[metricType] = append(m.set[metricType], samples...)
 }
 
+// Samples returns the samples of the type metricType in the MetricSet.
+func (m *MetricSet) Samples(metricType MetricType) []Sample {
+	return m.set[metricType]
+}
+
 // Merge merges two MetricSets. Missing labels from m's samples are added to all samples in n.
 func (m *MetricSet) Merge(metricSet *MetricSet) {
 	if metricSet == nil {
@@ -273,6 +278,11 @@ func MetricSetFromAPI(metrics *Metrics, labels map[string]string) (*MetricSet, e
 		set.AddSamples(NetworkTransmitPacketsTotal, Sample{Value: float64(stats.TransmitPackets), Labels: labels})
 	}
 
+	// Pressure stats
+	for resource, stats := range metrics.Pressure {
+		set.AddPressureSamples(resource, stats)
+	}
+
 	// Procs stats
 	set.AddSamples(ProcsTotal, Sample{Value: float64(metrics.ProcessesTotal)})
 
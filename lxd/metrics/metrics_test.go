@@ -125,3 +125,28 @@ func TestMetricSet_FilterSamples(t *testing.T) {
 		require.Contains(t, hasKeys, "project")
 	}
 }
+
+func TestParsePressure(t *testing.T) {
+	pressure, err := ParsePressure("some avg10=1.50 avg60=0.75 avg300=0.25 total=2500000\nfull avg10=0.50 avg60=0.10 avg300=0.00 total=1000000\n")
+	require.NoError(t, err)
+	require.Equal(t, 1.5, pressure.SomeAvg10)
+	require.Equal(t, 0.75, pressure.SomeAvg60)
+	require.Equal(t, 0.25, pressure.SomeAvg300)
+	require.Equal(t, uint64(2500000), pressure.SomeTotal)
+	require.Equal(t, 0.1, pressure.FullAvg60)
+	require.Equal(t, uint64(1000000), pressure.FullTotal)
+
+	m := NewMetricSet(nil)
+	m.AddPressureSamples("cpu", pressure)
+	require.Equal(t, []Sample{{Value: 2.5, Labels: map[string]string{"resource": "cpu"}}}, m.set[PressureWaitingSecondsTotal])
+	require.Equal(t, []Sample{{Value: 1, Labels: map[string]string{"resource": "cpu"}}}, m.set[PressureStalledSecondsTotal])
+
+	// Older kernels don't report the "full" line for the CPU.
+	pressure, err = ParsePressure("some avg10=0.00 avg60=0.00 avg300=0.00 total=42")
+	require.NoError(t, err)
+	require.Equal(t, uint64(42), pressure.SomeTotal)
+	require.Equal(t, uint64(0), pressure.FullTotal)
+
+	_, err = ParsePressure("some avg10=abc")
+	require.Error(t, err)
+}
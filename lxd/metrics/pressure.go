@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// PressureResources lists the resources for which pressure stall information is collected.
+var PressureResources = []string{"cpu", "memory", "io"}
+
+// ParsePressure parses the content of a pressure stall information file, such as /proc/pressure/cpu or the
+// cpu.pressure file of a cgroup.
+func ParsePressure(content string) (api.InstanceStatePressure, error) {
+	pressure := api.InstanceStatePressure{}
+
+	for line := range strings.SplitSeq(strings.TrimSpace(content), "\n") {
+		// A line looks like this: "some avg10=0.00 avg60=0.00 avg300=0.00 total=0".
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var avg10, avg60, avg300 *float64
+		var total *uint64
+
+		switch fields[0] {
+		case "some":
+			avg10, avg60, avg300, total = &pressure.SomeAvg10, &pressure.SomeAvg60, &pressure.SomeAvg300, &pressure.SomeTotal
+		case "full":
+			avg10, avg60, avg300, total = &pressure.FullAvg10, &pressure.FullAvg60, &pressure.FullAvg300, &pressure.FullTotal
+		default:
+			return api.InstanceStatePressure{}, fmt.Errorf("Unknown pressure line %q", line)
+		}
+
+		for _, field := range fields[1:] {
+			key, value, found := strings.Cut(field, "=")
+			if !found {
+				return api.InstanceStatePressure{}, fmt.Errorf("Invalid pressure field %q", field)
+			}
+
+			var err error
+
+			switch key {
+			case "avg10":
+				*avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				*avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				*avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				*total, err = strconv.ParseUint(value, 10, 64)
+			}
+
+			if err != nil {
+				return api.InstanceStatePressure{}, fmt.Errorf("Failed parsing pressure field %q: %w", field, err)
+			}
+		}
+	}
+
+	return pressure, nil
+}
+
+// AddPressureSamples adds the samples of the pressure stall information of the given resource to the set.
+func (m *MetricSet) AddPressureSamples(resource string, pressure api.InstanceStatePressure) {
+	labels := map[string]string{"resource": resource}
+
+	// The totals are reported in microseconds.
+	m.AddSamples(PressureWaitingSecondsTotal, Sample{Value: float64(pressure.SomeTotal) / 1000000, Labels: labels})
+	m.AddSamples(PressureStalledSecondsTotal, Sample{Value: float64(pressure.FullTotal) / 1000000, Labels: labels})
+}
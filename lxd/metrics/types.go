@@ -20,6 +20,8 @@ const (
 	APICompletedRequests MetricType = iota
 	// APIOngoingRequests represents the number of requests currently being handled.
 	APIOngoingRequests
+	// APIThrottledRequests represents the total number of requests rejected because of API limits.
+	APIThrottledRequests
 	// CPUs represents the total number of effective CPUs.
 	CPUs
 	// CPUSecondsTotal represents the total CPU seconds used.
@@ -146,6 +148,10 @@ const (
 	NetworkTransmitPacketsTotal
 	// OperationsTotal represents the number of running operations.
 	OperationsTotal
+	// PressureStalledSecondsTotal represents the total time during which all non-idle tasks were stalled on a resource.
+	PressureStalledSecondsTotal
+	// PressureWaitingSecondsTotal represents the total time during which at least some tasks were stalled on a resource.
+	PressureWaitingSecondsTotal
 	// ProcsTotal represents the number of running processes.
 	ProcsTotal
 	// UptimeSeconds represents the daemon uptime in seconds.
@@ -158,6 +164,7 @@ const (
 var MetricNames = map[MetricType]string{
 	APICompletedRequests:        "lxd_api_requests_completed_total",
 	APIOngoingRequests:          "lxd_api_requests_ongoing",
+	APIThrottledRequests:        "lxd_api_requests_throttled_total",
 	CPUSecondsTotal:             "lxd_cpu_seconds_total",
 	CPUs:                        "lxd_cpu_effective_total",
 	DiskReadBytesTotal:          "lxd_disk_read_bytes_total",
@@ -220,6 +227,8 @@ var MetricNames = map[MetricType]string{
 	NetworkTransmitErrsTotal:    "lxd_network_transmit_errs_total",
 	NetworkTransmitPacketsTotal: "lxd_network_transmit_packets_total",
 	OperationsTotal:             "lxd_operations_total",
+	PressureStalledSecondsTotal: "lxd_pressure_stalled_seconds_total",
+	PressureWaitingSecondsTotal: "lxd_pressure_waiting_seconds_total",
 	ProcsTotal:                  "lxd_procs_total",
 	UptimeSeconds:               "lxd_uptime_seconds",
 	WarningsTotal:               "lxd_warnings_total",
@@ -230,6 +239,7 @@ var MetricNames = map[MetricType]string{
 var MetricHeaders = map[MetricType]string{
 	APICompletedRequests:        "# HELP lxd_api_requests_completed_total The total number of completed API requests.",
 	APIOngoingRequests:          "# HELP lxd_api_requests_ongoing The number of API requests currently being handled.",
+	APIThrottledRequests:        "# HELP lxd_api_requests_throttled_total The total number of API requests rejected because of API limits.",
 	CPUSecondsTotal:             "# HELP lxd_cpu_seconds_total The total number of CPU time used in seconds.",
 	CPUs:                        "# HELP lxd_cpu_effective_total The total number of effective CPUs.",
 	DiskReadBytesTotal:          "# HELP lxd_disk_read_bytes_total The total number of bytes read.",
@@ -292,6 +302,8 @@ var MetricHeaders = map[MetricType]string{
 	NetworkTransmitErrsTotal:    "# HELP lxd_network_transmit_errs_total The amount of transmitted errors on a given interface.",
 	NetworkTransmitPacketsTotal: "# HELP lxd_network_transmit_packets_total The amount of transmitted packets on a given interface.",
 	OperationsTotal:             "# HELP lxd_operations_total The number of running operations",
+	PressureStalledSecondsTotal: "# HELP lxd_pressure_stalled_seconds_total The total time in seconds during which all non-idle tasks were stalled on a given resource.",
+	PressureWaitingSecondsTotal: "# HELP lxd_pressure_waiting_seconds_total The total time in seconds during which at least some tasks were stalled on a given resource.",
 	ProcsTotal:                  "# HELP lxd_procs_total The number of running processes.",
 	UptimeSeconds:               "# HELP lxd_uptime_seconds The daemon uptime in seconds.",
 	WarningsTotal:               "# HELP lxd_warnings_total The number of active warnings.",
@@ -457,6 +457,11 @@ func networksPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeNetwork, api.WebhookAdmissionOperationCreate, effectiveProjectName, entity.NetworkURL(effectiveProjectName, req.Name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Quick checks.
 	if req.Name == "" {
 		return response.BadRequest(errors.New("No name provided"))
@@ -1609,6 +1614,25 @@ func networkPut(d *Daemon, r *http.Request) response.Response {
 	clustered := s.ServerClustered
 	entityURL := entity.NetworkURL(effectiveProjectName, details.networkName)
 
+	// Admission webhooks review the full proposed configuration, so partial updates are merged first.
+	if httpMethod == http.MethodPatch && !clientType.IsClusterOperationNotification() {
+		if req.Config == nil {
+			req.Config = map[string]string{}
+		}
+
+		for k, v := range n.Config() {
+			_, ok := req.Config[k]
+			if !ok {
+				req.Config[k] = v
+			}
+		}
+	}
+
+	err = webhookAdmit(r, s, entity.TypeNetwork, api.WebhookAdmissionOperationUpdate, effectiveProjectName, entityURL, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	run := func(ctx context.Context, op *operations.Operation) error {
 		err := doNetworkUpdate(n, req, targetNode, clientType, httpMethod, clustered)
 		if err != nil {
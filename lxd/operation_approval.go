@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project/limits"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/util"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// Actions that can require approval via the `approval.actions` project configuration key.
+const (
+	operationApprovalInstanceDelete     = "instance-delete"
+	operationApprovalInstanceProtection = "instance-protection"
+	operationApprovalImageDelete        = "image-delete"
+	operationApprovalProjectDelete      = "project-delete"
+)
+
+// operationApprovalActions lists the actions that can require approval.
+var operationApprovalActions = []string{
+	operationApprovalInstanceDelete,
+	operationApprovalInstanceProtection,
+	operationApprovalImageDelete,
+	operationApprovalProjectDelete,
+}
+
+// operationApprovalDefaultExpiry is the default time after which an operation pending approval expires.
+const operationApprovalDefaultExpiry = "1d"
+
+// operationApprovalValidateExpiry validates an approval expiry.
+func operationApprovalValidateExpiry(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	_, err := shared.GetExpiry(time.Time{}, value)
+	return err
+}
+
+// operationApprovalDuration converts an approval expiry into a duration from now.
+func operationApprovalDuration(expiry string) (time.Duration, error) {
+	now := time.Now()
+	expiresAt, err := shared.GetExpiry(now, expiry)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid approval expiry %q: %w", expiry, err)
+	}
+
+	return expiresAt.Sub(now), nil
+}
+
+// projectApprovalExpiry returns the time within which the given action in the project must be approved, or zero if the
+// project doesn't require approval for the action.
+func projectApprovalExpiry(ctx context.Context, s *state.State, projectName string, action string) (time.Duration, error) {
+	var config map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		config, err = dbCluster.GetProjectConfig(ctx, tx.Tx(), projectName)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("Failed loading project config: %w", err)
+	}
+
+	actions := shared.SplitNTrimSpace(config["approval.actions"], ",", -1, true)
+	if !slices.Contains(actions, action) {
+		return 0, nil
+	}
+
+	expiry := config["approval.expiry"]
+	if expiry == "" {
+		expiry = operationApprovalDefaultExpiry
+	}
+
+	return operationApprovalDuration(expiry)
+}
+
+// clusterMemberRemoveApprovalExpiry returns the time within which the removal of a cluster member must be approved, or
+// zero if it doesn't require approval.
+func clusterMemberRemoveApprovalExpiry(s *state.State) (time.Duration, error) {
+	if !s.GlobalConfig.ClusterApprovalMemberRemove() {
+		return 0, nil
+	}
+
+	return operationApprovalDuration(s.GlobalConfig.ClusterApprovalExpiry())
+}
+
+// instanceProtectionApprovalExpiry returns the time within which an instance update changing the given local config
+// must be approved, or zero if it doesn't require approval.
+// Only updates changing `security.protection.*` options can require approval.
+func instanceProtectionApprovalExpiry(ctx context.Context, s *state.State, projectName string, oldConfig map[string]string, newConfig map[string]string) (time.Duration, error) {
+	changed := false
+	for _, config := range []map[string]string{oldConfig, newConfig} {
+		for key := range config {
+			if strings.HasPrefix(key, "security.protection.") && oldConfig[key] != newConfig[key] {
+				changed = true
+				break
+			}
+		}
+	}
+
+	if !changed {
+		return 0, nil
+	}
+
+	return projectApprovalExpiry(ctx, s, projectName, operationApprovalInstanceProtection)
+}
+
+// instanceEtag returns the ETag of an instance as checked by instance update requests.
+func instanceEtag(inst instance.Instance) (string, error) {
+	return util.EtagHash([]any{inst.Architecture(), inst.LocalConfig(), inst.LocalDevices(), inst.IsEphemeral(), inst.Profiles()})
+}
+
+// instanceApprovedUpdate returns the run hook of an instance update requiring approval.
+// As the instance and the project can change while the update is pending approval, the update is only applied if the
+// instance still has the given ETag and the update still fits in the project limits.
+func instanceApprovedUpdate(s *state.State, projectName string, name string, etag string, req api.InstancePut, args db.InstanceArgs) func(context.Context, *operations.Operation) error {
+	return func(ctx context.Context, _ *operations.Operation) error {
+		unlock, err := instanceOperationLock(ctx, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		defer unlock()
+
+		inst, err := instance.LoadByProjectAndName(s, projectName, name)
+		if err != nil {
+			return err
+		}
+
+		currentEtag, err := instanceEtag(inst)
+		if err != nil {
+			return err
+		}
+
+		if currentEtag != etag {
+			return api.StatusErrorf(http.StatusPreconditionFailed, "Instance %q was modified while the update was pending approval", name)
+		}
+
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			return limits.AllowInstanceUpdate(ctx, s.GlobalConfig, tx, projectName, name, req, inst.LocalConfig())
+		})
+		if err != nil {
+			return err
+		}
+
+		return inst.Update(ctx, args, instance.UpdateActionUser)
+	}
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+type operationApprovalTestSuite struct {
+	lxdTestSuite
+}
+
+// request returns a request made by the given user.
+func (suite *operationApprovalTestSuite) request(username string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/1.0/operations", nil)
+	suite.Req.NoError(request.SetRequestor(req, nil, request.RequestorArgs{Trusted: true, Username: username, Protocol: request.ProtocolUnix}))
+	request.SetContextValue(req, request.CtxMetricsCallbackFunc, func(metrics.RequestResult) {})
+
+	return req
+}
+
+// requestor returns the requestor of a request made by the given user.
+func (suite *operationApprovalTestSuite) requestor(username string) *request.Requestor {
+	requestor, err := request.GetRequestor(suite.request(username).Context())
+	suite.Req.NoError(err)
+
+	return requestor
+}
+
+// schedule schedules an operation requested by "alice" that requires approval within the given expiry. The returned
+// channel is closed when the run hook is called.
+func (suite *operationApprovalTestSuite) schedule(expiry time.Duration) (*operations.Operation, chan struct{}) {
+	ran := make(chan struct{})
+	op, err := operations.ScheduleUserOperationFromRequest(suite.d.State(), suite.request("alice"), operations.OperationArgs{
+		Type:      operationtype.ProjectDelete,
+		Class:     operationtype.OperationClassTask,
+		EntityURL: entity.ProjectURL("default"),
+		RunHook: func(ctx context.Context, op *operations.Operation) error {
+			close(ran)
+			return nil
+		},
+		ApprovalExpiry: expiry,
+	})
+	suite.Req.NoError(err)
+
+	return op, ran
+}
+
+// wait waits for the operation to complete.
+func (suite *operationApprovalTestSuite) wait(op *operations.Operation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := op.Wait(ctx)
+	suite.Req.NotErrorIs(err, context.DeadlineExceeded)
+
+	return err
+}
+
+func (suite *operationApprovalTestSuite) TestApprove() {
+	op, ran := suite.schedule(time.Hour)
+	suite.Equal(api.PendingApproval, op.Status())
+	suite.False(op.IsRunning())
+
+	// The requestor can't approve their own operation.
+	err := op.Approve(suite.requestor("alice"), true, "")
+	suite.True(api.StatusErrorCheck(err, http.StatusForbidden))
+	suite.Equal(api.PendingApproval, op.Status())
+
+	suite.Req.NoError(op.Approve(suite.requestor("bob"), true, "Planned maintenance"))
+	suite.Req.NoError(suite.wait(op))
+	suite.Equal(api.Success, op.Status())
+
+	select {
+	case <-ran:
+	default:
+		suite.Fail("The operation didn't run once approved")
+	}
+
+	// An operation can only be approved once.
+	err = op.Approve(suite.requestor("carol"), true, "")
+	suite.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+}
+
+func (suite *operationApprovalTestSuite) TestReject() {
+	op, ran := suite.schedule(time.Hour)
+
+	suite.Req.NoError(op.Approve(suite.requestor("bob"), false, "Not during business hours"))
+
+	err := suite.wait(op)
+	suite.True(api.StatusErrorCheck(err, http.StatusForbidden))
+	suite.ErrorContains(err, "Operation rejected: Not during business hours")
+	suite.Equal(api.Failure, op.Status())
+
+	select {
+	case <-ran:
+		suite.Fail("A rejected operation was run")
+	default:
+	}
+}
+
+func (suite *operationApprovalTestSuite) TestExpiry() {
+	op, ran := suite.schedule(100 * time.Millisecond)
+
+	err := suite.wait(op)
+	suite.True(api.StatusErrorCheck(err, http.StatusForbidden))
+	suite.ErrorContains(err, "Operation approval expired")
+	suite.Equal(api.Failure, op.Status())
+
+	// An expired operation can't be approved anymore.
+	err = op.Approve(suite.requestor("bob"), true, "")
+	suite.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	select {
+	case <-ran:
+		suite.Fail("An expired operation was run")
+	default:
+	}
+}
+
+func (suite *operationApprovalTestSuite) TestCancel() {
+	op, ran := suite.schedule(time.Hour)
+
+	op.Cancel()
+
+	err := suite.wait(op)
+	suite.ErrorIs(err, context.Canceled)
+	suite.Equal(api.Cancelled, op.Status())
+
+	err = op.Approve(suite.requestor("bob"), true, "")
+	suite.True(api.StatusErrorCheck(err, http.StatusBadRequest))
+
+	select {
+	case <-ran:
+		suite.Fail("A cancelled operation was run")
+	default:
+	}
+}
+
+// TestInstanceApprovedUpdate tests that an approved instance update isn't applied if the instance changed while the
+// update was pending approval.
+func (suite *operationApprovalTestSuite) TestInstanceApprovedUpdate() {
+	args := db.InstanceArgs{
+		Type: instancetype.Container,
+		Name: "c1",
+	}
+
+	inst, op, _, err := instance.CreateInternal(suite.T().Context(), suite.d.State(), args, true)
+	suite.Req.NoError(err)
+	op.Done(nil)
+	defer func() { _ = inst.Delete(suite.T().Context(), true, "", nil) }()
+
+	etag, err := instanceEtag(inst)
+	suite.Req.NoError(err)
+
+	run := instanceApprovedUpdate(suite.d.State(), "default", "c1", etag+"-stale", api.InstancePut{}, db.InstanceArgs{Project: "default"})
+	err = run(context.Background(), nil)
+	suite.True(api.StatusErrorCheck(err, http.StatusPreconditionFailed))
+	suite.ErrorContains(err, "modified while the update was pending approval")
+}
+
+func TestOperationApprovalTestSuite(t *testing.T) {
+	suite.Run(t, new(operationApprovalTestSuite))
+}
@@ -39,6 +39,13 @@ var operationCmd = APIEndpoint{
 	Get:    APIEndpointAction{Handler: operationGet, AccessHandler: allowAuthenticated},
 }
 
+var operationApprove = APIEndpoint{
+	Path:        "operations/{id}/approve",
+	MetricsType: entity.TypeOperation,
+
+	Post: APIEndpointAction{Handler: operationApprovePost, AccessHandler: allowAuthenticated},
+}
+
 var operationsCmd = APIEndpoint{
 	Path:            "operations",
 	MetricsType:     entity.TypeOperation,
@@ -274,7 +281,7 @@ func operationDelete(d *Daemon, r *http.Request) response.Response {
 			}
 		}
 
-		if !op.IsRunning() {
+		if !op.IsRunning() && op.Status() != api.PendingApproval {
 			return response.BadRequest(errors.New("Only running operations can be cancelled"))
 		}
 
@@ -316,6 +323,105 @@ func operationDelete(d *Daemon, r *http.Request) response.Response {
 	return response.ForwardedResponse(client)
 }
 
+// swagger:operation POST /1.0/operations/{id}/approve operations operation_approve_post
+//
+//	Approve or reject the operation
+//
+//	Approves or rejects an operation pending approval.
+//	The operation must be approved by a different identity than the one that requested it.
+//
+//	---
+//	consumes:
+//	  - application/json
+//	produces:
+//	  - application/json
+//	parameters:
+//	  - in: body
+//	    name: operation
+//	    description: Approval decision
+//	    required: true
+//	    schema:
+//	      $ref: "#/definitions/OperationApprovePost"
+//	responses:
+//	  "200":
+//	    $ref: "#/responses/EmptySyncResponse"
+//	  "400":
+//	    $ref: "#/responses/BadRequest"
+//	  "403":
+//	    $ref: "#/responses/Forbidden"
+//	  "500":
+//	    $ref: "#/responses/InternalServerError"
+func operationApprovePost(d *Daemon, r *http.Request) response.Response {
+	s := d.State()
+
+	id := r.PathValue("id")
+	op, err := operations.OperationGetInternal(id)
+	if err != nil {
+		// Check if the operation is running on another member, and, if so, forward the request.
+		var operation *dbCluster.Operation
+		err = s.DB.Cluster.Transaction(r.Context(), func(ctx context.Context, tx *db.ClusterTx) error {
+			operation, err = dbCluster.GetOperation(ctx, tx.Tx(), id)
+			return err
+		})
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		if operation.NodeAddress == "" || operation.NodeAddress == s.LocalConfig.ClusterAddress() {
+			return response.BadRequest(errors.New("Operation is not pending approval"))
+		}
+
+		client, err := cluster.Connect(r.Context(), operation.NodeAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return response.SmartError(err)
+		}
+
+		return response.ForwardedResponse(client)
+	}
+
+	// Operations that aren't associated with a project (e.g. project deletion) can only be approved by identities
+	// allowed to approve operations on the server.
+	entityURL := entity.ServerURL()
+	if op.Project() != "" {
+		entityURL = entity.ProjectURL(op.Project())
+	}
+
+	err = s.Authorizer.CheckPermission(r.Context(), entityURL, auth.EntitlementCanApproveOperations)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	req := api.OperationApprovePost{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return response.BadRequest(err)
+	}
+
+	requestor, err := request.GetRequestor(r.Context())
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	err = op.Approve(requestor, req.Approved, req.Reason)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
+	projectName := op.Project()
+	if projectName == "" {
+		projectName = api.ProjectDefaultName
+	}
+
+	action := lifecycle.OperationRejected
+	if req.Approved {
+		action = lifecycle.OperationApproved
+	}
+
+	s.Events.SendLifecycle(projectName, action.Event(op, request.CreateRequestor(r.Context()), map[string]any{"reason": req.Reason}))
+
+	return response.EmptySyncResponse
+}
+
 // operationCancelToken cancels a token operation that exists on any member.
 func operationCancelToken(ctx context.Context, s *state.State, projectName string, op *api.Operation) error {
 	if op.Class != api.OperationClassToken {
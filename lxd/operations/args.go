@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/canonical/lxd/lxd/db/operationtype"
 	"github.com/canonical/lxd/lxd/metrics"
@@ -55,6 +56,11 @@ type OperationArgs struct {
 	// It is not valid to provide a conflict reference if the Type has [operationtype.ConflictActionNone].
 	ConflictReference string
 
+	// ApprovalExpiry, if set, creates the operation in the [api.PendingApproval] state instead of running it straight
+	// away. The operation runs once another identity approves it (see [Operation.Approve]), and fails if it isn't
+	// approved within the expiry. Only user task operations without children can require approval.
+	ApprovalExpiry time.Duration
+
 	// Children are sub-operations of a bulk operation. It is not valid to provide children if [operationtype.Type.IsBulk]
 	// returns false for the Type.
 	Children []*OperationArgs
@@ -141,6 +147,14 @@ func (a OperationArgs) validate(isChild bool) error {
 		return errors.New("Only task operations can be child operations")
 	}
 
+	if a.ApprovalExpiry < 0 {
+		return errors.New("Approval expiry cannot be negative")
+	}
+
+	if a.ApprovalExpiry > 0 && (a.Class != operationtype.OperationClassTask || isChild || isBulkOperation) {
+		return errors.New("Only task operations without children can require approval")
+	}
+
 	if a.ConflictReference != "" && a.Type.ConflictAction() == operationtype.ConflictActionNone {
 		return fmt.Errorf("Conflict reference %q provided for operation type %q that does not support conflicts", a.ConflictReference, a.Type.Description())
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 
@@ -86,6 +87,16 @@ func (s *argsSuite) TestValidate() {
 			isChild:   false,
 			expectErr: false,
 		},
+		{
+			name: "valid task operation requiring approval",
+			args: func() OperationArgs {
+				args := validTaskOperationArgs()
+				args.ApprovalExpiry = time.Hour
+				return args
+			}(),
+			isChild:   false,
+			expectErr: false,
+		},
 		{
 			name: "valid task operation with children",
 			args: func() OperationArgs {
@@ -391,6 +402,40 @@ func (s *argsSuite) TestValidate() {
 			expectErr: true,
 			errMsg:    "Child operations cannot have a different project to the parent operation",
 		},
+		// Unhappy paths - approval restrictions
+		{
+			name: "negative approval expiry",
+			args: func() OperationArgs {
+				args := validTaskOperationArgs()
+				args.ApprovalExpiry = -time.Hour
+				return args
+			}(),
+			isChild:   false,
+			expectErr: true,
+			errMsg:    "Approval expiry cannot be negative",
+		},
+		{
+			name: "websocket operation requiring approval",
+			args: func() OperationArgs {
+				args := validWebsocketOperationArgs()
+				args.ApprovalExpiry = time.Hour
+				return args
+			}(),
+			isChild:   false,
+			expectErr: true,
+			errMsg:    "Only task operations without children can require approval",
+		},
+		{
+			name: "child operation requiring approval",
+			args: func() OperationArgs {
+				args := validTaskOperationArgs()
+				args.ApprovalExpiry = time.Hour
+				return args
+			}(),
+			isChild:   true,
+			expectErr: true,
+			errMsg:    "Only task operations without children can require approval",
+		},
 		// Unhappy paths - child validation failure propagation
 		{
 			name: "child operation validation failure",
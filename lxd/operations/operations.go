@@ -85,6 +85,11 @@ type Operation struct {
 	// Operations which conflict with each other share the same conflict reference.
 	conflictReference string
 
+	// approvalTimer fails the operation if it is still pending approval when the timer fires.
+	// Operations pending approval only exist in memory, as their run hook can't be persisted. They are lost when the
+	// member restarts, like any other operation.
+	approvalTimer *time.Timer
+
 	// If this operation is part of a bulk operation, parent will point to the parent operation.
 	parent   *Operation
 	children []*Operation
@@ -155,6 +160,11 @@ func scheduleOperation(s *state.State, args OperationArgs) (*Operation, error) {
 		return nil, fmt.Errorf("Failed validating operation arguments: %w", err)
 	}
 
+	// The approval must be given by a different identity than the requestor, so the requestor must be known.
+	if args.ApprovalExpiry > 0 && args.requestor == nil {
+		return nil, errors.New("Operations requiring approval must have a requestor")
+	}
+
 	// initOperation initializes a single operation structure.
 	initOperation := func(s *state.State, args OperationArgs) (*Operation, error) {
 		// Don't allow new operations when LXD is shutting down.
@@ -225,6 +235,11 @@ func scheduleOperation(s *state.State, args OperationArgs) (*Operation, error) {
 			metadata[api.MetadataEntityURL] = metadataURL.String()
 		}
 
+		if args.ApprovalExpiry > 0 {
+			op.status = api.PendingApproval
+			metadata[api.MetadataApprovalExpiresAt] = op.createdAt.Add(args.ApprovalExpiry)
+		}
+
 		err = validateMetadata(metadata)
 		if err != nil {
 			return nil, fmt.Errorf("Failed validating operation metadata: %w", err)
@@ -279,10 +294,97 @@ func scheduleOperation(s *state.State, args OperationArgs) (*Operation, error) {
 
 	operationsLock.Unlock()
 
+	if op.status == api.PendingApproval {
+		op.awaitApproval(args.ApprovalExpiry)
+		return op, nil
+	}
+
 	op.start()
 	return op, nil
 }
 
+// awaitApproval notifies about an operation pending approval and fails it if it isn't approved within the expiry.
+func (op *Operation) awaitApproval(expiry time.Duration) {
+	op.lock.Lock()
+	op.approvalTimer = time.AfterFunc(expiry, func() {
+		op.lock.Lock()
+		if op.status != api.PendingApproval {
+			op.lock.Unlock()
+			return
+		}
+
+		op.err = "Operation approval expired"
+		op.errCode = http.StatusForbidden
+		updateStatus(op, api.Failure)
+		op.running.Cancel()
+		op.lock.Unlock()
+		op.done()
+
+		op.logger.Info("Operation approval expired")
+		_, md := op.Render()
+
+		op.lock.Lock()
+		op.sendEvent(md)
+		op.lock.Unlock()
+	})
+
+	op.lock.Unlock()
+
+	op.logger.Info("Operation pending approval", logger.Ctx{"expiry": expiry})
+	_, md := op.Render()
+
+	op.lock.Lock()
+	op.sendEvent(md)
+	op.lock.Unlock()
+}
+
+// Approve approves or rejects an operation pending approval. The approver must be a different caller than the
+// requestor of the operation. An approved operation is started, a rejected one fails straight away.
+func (op *Operation) Approve(approver *request.Requestor, approved bool, reason string) error {
+	op.lock.Lock()
+	if op.status != api.PendingApproval {
+		op.lock.Unlock()
+		return api.StatusErrorf(http.StatusBadRequest, "Operation is not pending approval")
+	}
+
+	if approver == nil || approver.CallerIsEqual(op.requestor) {
+		op.lock.Unlock()
+		return api.StatusErrorf(http.StatusForbidden, "Operations cannot be approved or rejected by their requestor")
+	}
+
+	op.approvalTimer.Stop()
+
+	if approved {
+		updateStatus(op, api.Running)
+		op.lock.Unlock()
+
+		op.logger.Info("Operation approved", logger.Ctx{"approver": approver.Username, "reason": reason})
+		op.start()
+
+		return nil
+	}
+
+	op.err = "Operation rejected"
+	if reason != "" {
+		op.err = "Operation rejected: " + reason
+	}
+
+	op.errCode = http.StatusForbidden
+	updateStatus(op, api.Failure)
+	op.running.Cancel()
+	op.lock.Unlock()
+	op.done()
+
+	op.logger.Info("Operation rejected", logger.Ctx{"approver": approver.Username, "reason": reason})
+	_, md := op.Render()
+
+	op.lock.Lock()
+	op.sendEvent(md)
+	op.lock.Unlock()
+
+	return nil
+}
+
 // addChild adds a child operation to the parent operation. It also sets the parent of the child operation to the parent operation.
 func (op *Operation) addChild(child *Operation) {
 	op.lock.Lock()
@@ -559,7 +661,7 @@ func (op *Operation) start() {
 
 // IsRunning returns true if the operation run hook is still in progress.
 func (op *Operation) IsRunning() bool {
-	return op.running.Err() == nil
+	return op.running.Err() == nil && op.Status() != api.PendingApproval
 }
 
 // Cancel cancels a running operation. If the operation cannot be cancelled, it
@@ -575,7 +677,14 @@ func (op *Operation) Cancel() {
 	// Signal the operation to stop.
 	op.running.Cancel()
 
-	if op.onRun != nil || len(op.children) > 0 {
+	pending := op.status == api.PendingApproval
+	if pending {
+		// The run hook of an operation pending approval hasn't been started, so there is nothing to clean up.
+		op.approvalTimer.Stop()
+		op.err = context.Canceled.Error()
+		op.errCode = http.StatusInternalServerError
+		updateStatus(op, api.Cancelled)
+	} else if op.onRun != nil || len(op.children) > 0 {
 		// If the operation has a run hook, or this is a parent operation waiting for children, set the status to cancelling.
 		// If there's a run hook, the status, error and error code will be set to cancelled by the start routine because the run context is cancelled.
 		// The allows an operation to emit a cancelling status if it is in the middle of something that could take a while to clean up.
@@ -604,9 +713,9 @@ func (op *Operation) Cancel() {
 	op.sendEvent(md)
 	op.lock.Unlock()
 
-	// If the operation does not have a run hook (e.g. a token operation) we need to call op.done(), because it won't be
-	// called automatically when the run hook completes.
-	if op.onRun == nil {
+	// If the operation does not have a run hook (e.g. a token operation) or it hasn't been started yet, we need to
+	// call op.done(), because it won't be called automatically when the run hook completes.
+	if op.onRun == nil || pending {
 		op.done()
 	}
 }
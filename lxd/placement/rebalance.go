@@ -0,0 +1,207 @@
+package placement
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// RebalanceInstance represents a running instance considered by the cluster rebalancer.
+type RebalanceInstance struct {
+	Project string
+	Name    string
+	Member  string
+
+	// Live indicates whether the instance would be live-migrated.
+	Live bool
+
+	// Targets lists the cluster members the instance may be moved to. It is empty for instances that cannot be moved.
+	Targets []string
+}
+
+// LoadSample is a sample of the metrics of the instances running on a cluster member.
+type LoadSample struct {
+	Time time.Time
+
+	// CPUSeconds is the total CPU time used by the instances, idle time excluded.
+	CPUSeconds float64
+
+	// MemoryBytes is the memory in use by the instances.
+	MemoryBytes float64
+
+	// PressureSeconds is the total time during which some tasks were stalled, by instance and resource.
+	PressureSeconds map[string]float64
+}
+
+// NewLoadSample returns a load sample built from the metrics of the instances running on a cluster member,
+// indexed by instance.
+func NewLoadSample(t time.Time, instanceMetrics map[string]*metrics.MetricSet) LoadSample {
+	sample := LoadSample{
+		Time:            t,
+		PressureSeconds: map[string]float64{},
+	}
+
+	for name, set := range instanceMetrics {
+		for _, cpu := range set.Samples(metrics.CPUSecondsTotal) {
+			if cpu.Labels["mode"] == "idle" {
+				continue
+			}
+
+			sample.CPUSeconds += cpu.Value
+		}
+
+		var total, available float64
+		for _, mem := range set.Samples(metrics.MemoryMemTotalBytes) {
+			total += mem.Value
+		}
+
+		for _, mem := range set.Samples(metrics.MemoryMemAvailableBytes) {
+			available += mem.Value
+		}
+
+		sample.MemoryBytes += max(total-available, 0)
+
+		for _, pressure := range set.Samples(metrics.PressureWaitingSecondsTotal) {
+			sample.PressureSeconds[name+"/"+pressure.Labels["resource"]] += pressure.Value
+		}
+	}
+
+	return sample
+}
+
+// MemberLoad computes the load of a cluster member from two load samples of its instances, the number of logical
+// CPUs and the total memory of the member.
+// The CPU load is the CPU time used by the instances between both samples per logical CPU and the memory load is
+// the share of the member's memory used by the instances, both in percent. The pressure is the highest share of
+// time during which some tasks of an instance were stalled on a resource between both samples, in percent.
+// The score of the member is the highest of the three values.
+func MemberLoad(name string, previous LoadSample, current LoadSample, cpus int, totalMemory uint64) api.ClusterRebalanceMember {
+	load := api.ClusterRebalanceMember{Name: name}
+
+	elapsed := current.Time.Sub(previous.Time).Seconds()
+	if elapsed > 0 {
+		if cpus > 0 {
+			load.CPU = max(current.CPUSeconds-previous.CPUSeconds, 0) / elapsed / float64(cpus) * 100
+		}
+
+		for key, seconds := range current.PressureSeconds {
+			previousSeconds, ok := previous.PressureSeconds[key]
+			if !ok {
+				continue
+			}
+
+			load.Pressure = max(load.Pressure, max(seconds-previousSeconds, 0)/elapsed*100)
+		}
+	}
+
+	if totalMemory > 0 {
+		load.Memory = current.MemoryBytes / float64(totalMemory) * 100
+	}
+
+	load.CPU = min(load.CPU, 100)
+	load.Memory = min(load.Memory, 100)
+	load.Pressure = min(load.Pressure, 100)
+	load.Score = max(load.CPU, load.Memory, load.Pressure)
+
+	return load
+}
+
+// PlanRebalance returns at most batch instance moves that reduce the load difference between cluster members.
+// Instances are moved off the busiest members as long as their score exceeds the score of an eligible target by
+// at least threshold percent. As the load of individual instances isn't known, each running instance is assumed to
+// account for an equal share of the load of its member.
+func PlanRebalance(members []api.ClusterRebalanceMember, instances []RebalanceInstance, threshold float64, batch int) []api.ClusterRebalanceMove {
+	scores := make(map[string]float64, len(members))
+	for _, member := range members {
+		scores[member.Name] = member.Score
+	}
+
+	counts := make(map[string]int, len(members))
+	for _, inst := range instances {
+		counts[inst.Member]++
+	}
+
+	// Process instances in a stable order so that the same input always gives the same plan.
+	instances = slices.Clone(instances)
+	slices.SortFunc(instances, func(a RebalanceInstance, b RebalanceInstance) int {
+		return cmp.Or(cmp.Compare(a.Project, b.Project), cmp.Compare(a.Name, b.Name))
+	})
+
+	moved := make([]bool, len(instances))
+	moves := []api.ClusterRebalanceMove{}
+
+	for len(moves) < batch {
+		// Look at the busiest members first.
+		sources := make([]string, 0, len(scores))
+		for name := range scores {
+			sources = append(sources, name)
+		}
+
+		slices.SortFunc(sources, func(a string, b string) int {
+			return cmp.Or(cmp.Compare(scores[b], scores[a]), cmp.Compare(a, b))
+		})
+
+		move := planRebalanceMove(sources, instances, moved, scores, counts, threshold)
+		if move == nil {
+			break
+		}
+
+		moves = append(moves, *move)
+	}
+
+	return moves
+}
+
+// planRebalanceMove finds the next instance to move off the busiest possible source member and updates the
+// estimated scores and instance counts accordingly.
+func planRebalanceMove(sources []string, instances []RebalanceInstance, moved []bool, scores map[string]float64, counts map[string]int, threshold float64) *api.ClusterRebalanceMove {
+	for _, source := range sources {
+		for i, inst := range instances {
+			if moved[i] || inst.Member != source || counts[source] == 0 {
+				continue
+			}
+
+			// Pick the least busy eligible target.
+			target := ""
+			for _, t := range inst.Targets {
+				_, known := scores[t]
+				if !known || t == source {
+					continue
+				}
+
+				if target == "" || scores[t] < scores[target] {
+					target = t
+				}
+			}
+
+			if target == "" || scores[source]-scores[target] < threshold {
+				continue
+			}
+
+			// Skip moves that would only shift the hot spot to the target.
+			share := scores[source] / float64(counts[source])
+			if scores[target]+share >= scores[source]-share {
+				continue
+			}
+
+			scores[source] -= share
+			scores[target] += share
+			counts[source]--
+			counts[target]++
+			moved[i] = true
+
+			return &api.ClusterRebalanceMove{
+				Project:  inst.Project,
+				Instance: inst.Name,
+				Source:   source,
+				Target:   target,
+				Live:     inst.Live,
+			}
+		}
+	}
+
+	return nil
+}
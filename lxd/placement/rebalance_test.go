@@ -0,0 +1,127 @@
+package placement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestNewLoadSample(t *testing.T) {
+	now := time.Now()
+
+	c1 := metrics.NewMetricSet(nil)
+	c1.AddSamples(metrics.CPUSecondsTotal,
+		metrics.Sample{Value: 10, Labels: map[string]string{"mode": "user", "cpu": "0"}},
+		metrics.Sample{Value: 5, Labels: map[string]string{"mode": "system", "cpu": "0"}},
+		metrics.Sample{Value: 100, Labels: map[string]string{"mode": "idle", "cpu": "0"}},
+	)
+	c1.AddSamples(metrics.MemoryMemTotalBytes, metrics.Sample{Value: 400})
+	c1.AddSamples(metrics.MemoryMemAvailableBytes, metrics.Sample{Value: 100})
+	c1.AddPressureSamples("cpu", api.InstanceStatePressure{SomeTotal: 2000000})
+
+	// Instances without memory limit don't report their total and available memory.
+	v1 := metrics.NewMetricSet(nil)
+	v1.AddSamples(metrics.CPUSecondsTotal, metrics.Sample{Value: 20, Labels: map[string]string{"mode": "user", "cpu": "0"}})
+	v1.AddPressureSamples("io", api.InstanceStatePressure{SomeTotal: 1000000})
+
+	sample := NewLoadSample(now, map[string]*metrics.MetricSet{"c1": c1, "v1": v1})
+
+	assert.Equal(t, now, sample.Time)
+	assert.InDelta(t, 35, sample.CPUSeconds, 0.001)
+	assert.InDelta(t, 300, sample.MemoryBytes, 0.001)
+	assert.Equal(t, map[string]float64{"c1/cpu": 2, "v1/io": 1}, sample.PressureSeconds)
+}
+
+func TestMemberLoad(t *testing.T) {
+	now := time.Now()
+
+	previous := LoadSample{
+		Time:            now.Add(-10 * time.Second),
+		CPUSeconds:      100,
+		MemoryBytes:     100,
+		PressureSeconds: map[string]float64{"c1/cpu": 1, "c2/memory": 4},
+	}
+
+	current := LoadSample{
+		Time:            now,
+		CPUSeconds:      140,
+		MemoryBytes:     300,
+		PressureSeconds: map[string]float64{"c1/cpu": 2, "c2/memory": 6, "c3/io": 100},
+	}
+
+	load := MemberLoad("member01", previous, current, 8, 1000)
+
+	assert.Equal(t, "member01", load.Name)
+	assert.InDelta(t, 50, load.CPU, 0.001)
+	assert.InDelta(t, 30, load.Memory, 0.001)
+
+	// Instances missing from the previous sample are ignored.
+	assert.InDelta(t, 20, load.Pressure, 0.001)
+	assert.InDelta(t, 50, load.Score, 0.001)
+
+	// The pressure counts in the score.
+	current.PressureSeconds["c1/cpu"] = 9
+	load = MemberLoad("member01", previous, current, 8, 1000)
+	assert.InDelta(t, 80, load.Pressure, 0.001)
+	assert.InDelta(t, 80, load.Score, 0.001)
+
+	// Rates can't be computed from a single sample.
+	load = MemberLoad("member01", current, current, 8, 1000)
+	assert.Zero(t, load.CPU)
+	assert.Zero(t, load.Pressure)
+	assert.InDelta(t, 30, load.Score, 0.001)
+}
+
+func TestPlanRebalance(t *testing.T) {
+	members := []api.ClusterRebalanceMember{
+		{Name: "member01", Score: 90},
+		{Name: "member02", Score: 20},
+		{Name: "member03", Score: 40},
+	}
+
+	all := []string{"member01", "member02", "member03"}
+
+	instances := []RebalanceInstance{
+		{Project: "default", Name: "c1", Member: "member01", Targets: all},
+		{Project: "default", Name: "c2", Member: "member01", Live: true, Targets: all},
+		{Project: "default", Name: "c3", Member: "member01"},
+		{Project: "default", Name: "c4", Member: "member01", Targets: all},
+		{Project: "default", Name: "c5", Member: "member01", Targets: all},
+		{Project: "default", Name: "c6", Member: "member01", Targets: all},
+		{Project: "default", Name: "c7", Member: "member02", Targets: all},
+		{Project: "default", Name: "c8", Member: "member03", Targets: all},
+	}
+
+	// A single move goes from the busiest to the least busy member.
+	moves := PlanRebalance(members, instances, 20, 1)
+	assert.Equal(t, []api.ClusterRebalanceMove{
+		{Project: "default", Instance: "c1", Source: "member01", Target: "member02"},
+	}, moves)
+
+	// Scores are estimated after each move (member01 goes from 90 to 75 and then 60, member02 from 20 to 35 and
+	// then 50) and no move is planned that would make the target busier than the source.
+	moves = PlanRebalance(members, instances, 20, 5)
+	assert.Equal(t, []api.ClusterRebalanceMove{
+		{Project: "default", Instance: "c1", Source: "member01", Target: "member02"},
+		{Project: "default", Instance: "c2", Source: "member01", Target: "member02", Live: true},
+	}, moves)
+
+	// Nothing to do when the difference is below the threshold.
+	moves = PlanRebalance(members, instances, 80, 5)
+	assert.Empty(t, moves)
+
+	// Instances are only moved to eligible targets.
+	for i := range instances[:6] {
+		instances[i].Targets = nil
+	}
+
+	instances[0].Targets = []string{"member03"}
+	moves = PlanRebalance(members, instances, 20, 5)
+	assert.Equal(t, []api.ClusterRebalanceMove{
+		{Project: "default", Instance: "c1", Source: "member01", Target: "member03"},
+	}, moves)
+}
@@ -0,0 +1,241 @@
+package placement
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/lxd/instance/instancetype"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/units"
+)
+
+// Cluster member configuration keys recording the capacity that each member detected at startup.
+const (
+	// CapacityCPUKey records the number of logical CPUs of a cluster member.
+	CapacityCPUKey = "volatile.capacity.cpu"
+
+	// CapacityMemoryKey records the total memory in bytes of a cluster member.
+	CapacityMemoryKey = "volatile.capacity.memory"
+)
+
+// Reservations represents the amount of CPU and memory reserved on a cluster member.
+type Reservations struct {
+	CPU    int64
+	Memory int64
+}
+
+// IsZero returns true if no resources are reserved.
+func (r Reservations) IsZero() bool {
+	return r.CPU == 0 && r.Memory == 0
+}
+
+// Add returns the sum of both reservations.
+func (r Reservations) Add(other Reservations) Reservations {
+	return Reservations{
+		CPU:    r.CPU + other.CPU,
+		Memory: r.Memory + other.Memory,
+	}
+}
+
+// InstanceReservations returns the resources reserved by an instance with the given expanded config.
+func InstanceReservations(expandedConfig map[string]string) (Reservations, error) {
+	var res Reservations
+	var err error
+
+	cpu := expandedConfig["reservations.cpu"]
+	if cpu != "" {
+		res.CPU, err = strconv.ParseInt(cpu, 10, 64)
+		if err != nil {
+			return Reservations{}, fmt.Errorf("Invalid reservations.cpu value %q: %w", cpu, err)
+		}
+	}
+
+	memory := expandedConfig["reservations.memory"]
+	if memory != "" {
+		res.Memory, err = units.ParseByteSizeString(memory)
+		if err != nil {
+			return Reservations{}, fmt.Errorf("Invalid reservations.memory value %q: %w", memory, err)
+		}
+	}
+
+	return res, nil
+}
+
+// Allocatable returns the resources that can be reserved on a cluster member with the given config, that is the
+// recorded capacity multiplied by the configured overcommit ratio.
+// A value of -1 means that the capacity of the member is unknown and that reservations aren't enforced.
+func Allocatable(memberConfig map[string]string) (Reservations, error) {
+	cpu, err := allocatable(memberConfig, CapacityCPUKey, "scheduler.overcommit.cpu")
+	if err != nil {
+		return Reservations{}, err
+	}
+
+	memory, err := allocatable(memberConfig, CapacityMemoryKey, "scheduler.overcommit.memory")
+	if err != nil {
+		return Reservations{}, err
+	}
+
+	return Reservations{CPU: cpu, Memory: memory}, nil
+}
+
+// allocatable returns the recorded capacity under capacityKey multiplied by the ratio under ratioKey.
+func allocatable(memberConfig map[string]string, capacityKey string, ratioKey string) (int64, error) {
+	capacityStr := memberConfig[capacityKey]
+	if capacityStr == "" {
+		return -1, nil
+	}
+
+	capacity, err := strconv.ParseInt(capacityStr, 10, 64)
+	if err != nil {
+		return -1, fmt.Errorf("Invalid %q value %q: %w", capacityKey, capacityStr, err)
+	}
+
+	ratio := 1.0
+	ratioStr := memberConfig[ratioKey]
+	if ratioStr != "" {
+		ratio, err = strconv.ParseFloat(ratioStr, 64)
+		if err != nil {
+			return -1, fmt.Errorf("Invalid %q value %q: %w", ratioKey, ratioStr, err)
+		}
+	}
+
+	return int64(float64(capacity) * ratio), nil
+}
+
+// Fits returns an error if adding the requested reservations to the committed ones exceeds the allocatable
+// resources.
+func Fits(allocatable Reservations, committed Reservations, requested Reservations) error {
+	total := committed.Add(requested)
+
+	if requested.CPU > 0 && allocatable.CPU >= 0 && total.CPU > allocatable.CPU {
+		return fmt.Errorf("Not enough unreserved CPUs (requested %d, committed %d, allocatable %d)", requested.CPU, committed.CPU, allocatable.CPU)
+	}
+
+	if requested.Memory > 0 && allocatable.Memory >= 0 && total.Memory > allocatable.Memory {
+		return fmt.Errorf("Not enough unreserved memory (requested %s, committed %s, allocatable %s)", units.GetByteSizeStringIEC(requested.Memory, 2), units.GetByteSizeStringIEC(committed.Memory, 2), units.GetByteSizeStringIEC(allocatable.Memory, 2))
+	}
+
+	return nil
+}
+
+// GetCommittedReservations returns the resources reserved by the instances on each cluster member, keyed by member
+// name. The instance identified by excludeInstanceID (if non-zero) is ignored, which allows checking whether an
+// instance can be moved to another member.
+func GetCommittedReservations(ctx context.Context, tx *db.ClusterTx, excludeInstanceID int) (map[string]Reservations, error) {
+	committed := make(map[string]Reservations)
+
+	err := tx.InstanceList(ctx, func(inst db.InstanceArgs, _ api.Project) error {
+		if inst.ID == excludeInstanceID {
+			return nil
+		}
+
+		res, err := InstanceReservations(instancetype.ExpandInstanceConfig(nil, inst.Config, inst.Profiles))
+		if err != nil {
+			return fmt.Errorf("Failed getting reservations of instance %q in project %q: %w", inst.Name, inst.Project, err)
+		}
+
+		if res.IsZero() {
+			return nil
+		}
+
+		committed[inst.Node] = committed[inst.Node].Add(res)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return committed, nil
+}
+
+// FilterReservations returns the candidate cluster members that have enough unreserved resources left to
+// accommodate the requested reservations. The instance identified by excludeInstanceID (if non-zero) isn't counted
+// as committed.
+func FilterReservations(ctx context.Context, tx *db.ClusterTx, candidates []db.NodeInfo, requested Reservations, excludeInstanceID int) ([]db.NodeInfo, error) {
+	if requested.IsZero() {
+		return candidates, nil
+	}
+
+	committed, err := GetCommittedReservations(ctx, tx, excludeInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return filterReservations(candidates, committed, requested)
+}
+
+// filterReservations returns the candidates whose committed reservations leave room for the requested ones.
+func filterReservations(candidates []db.NodeInfo, committed map[string]Reservations, requested Reservations) ([]db.NodeInfo, error) {
+	filteredCandidates := make([]db.NodeInfo, 0, len(candidates))
+	for _, c := range candidates {
+		allocatable, err := Allocatable(c.Config)
+		if err != nil {
+			return nil, err
+		}
+
+		err = Fits(allocatable, committed[c.Name], requested)
+		if err != nil {
+			continue
+		}
+
+		filteredCandidates = append(filteredCandidates, c)
+	}
+
+	if len(filteredCandidates) == 0 {
+		return nil, api.StatusErrorf(http.StatusConflict, "No cluster member has enough unreserved resources for the instance")
+	}
+
+	return filteredCandidates, nil
+}
+
+// CheckReservations returns an error if the given cluster member hasn't enough unreserved resources left to
+// accommodate the requested reservations.
+func CheckReservations(ctx context.Context, tx *db.ClusterTx, member db.NodeInfo, requested Reservations, excludeInstanceID int) error {
+	if requested.IsZero() {
+		return nil
+	}
+
+	committed, err := GetCommittedReservations(ctx, tx, excludeInstanceID)
+	if err != nil {
+		return err
+	}
+
+	allocatable, err := Allocatable(member.Config)
+	if err != nil {
+		return err
+	}
+
+	err = Fits(allocatable, committed[member.Name], requested)
+	if err != nil {
+		return api.StatusErrorf(http.StatusConflict, "Cluster member %q cannot accommodate the instance: %w", member.Name, err)
+	}
+
+	return nil
+}
+
+// RecordCapacity records the given number of logical CPUs and total memory in the config of the cluster member
+// with the given ID, so that other members can check reservations against it.
+func RecordCapacity(ctx context.Context, tx *db.ClusterTx, memberID int64, cpu uint64, memory uint64) error {
+	member, err := tx.GetNodeByID(ctx, memberID)
+	if err != nil {
+		return fmt.Errorf("Failed loading cluster member: %w", err)
+	}
+
+	cpuStr := strconv.FormatUint(cpu, 10)
+	memoryStr := strconv.FormatUint(memory, 10)
+	if member.Config[CapacityCPUKey] == cpuStr && member.Config[CapacityMemoryKey] == memoryStr {
+		return nil
+	}
+
+	config := make(map[string]string, len(member.Config)+2)
+	maps.Copy(config, member.Config)
+	config[CapacityCPUKey] = cpuStr
+	config[CapacityMemoryKey] = memoryStr
+
+	return tx.UpdateNodeConfig(ctx, memberID, config)
+}
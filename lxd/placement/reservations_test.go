@@ -0,0 +1,98 @@
+package placement
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/lxd/db"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func TestInstanceReservations(t *testing.T) {
+	res, err := InstanceReservations(map[string]string{"reservations.cpu": "2", "reservations.memory": "1GiB"})
+	require.NoError(t, err)
+	assert.Equal(t, Reservations{CPU: 2, Memory: 1024 * 1024 * 1024}, res)
+
+	res, err = InstanceReservations(map[string]string{"limits.cpu": "4"})
+	require.NoError(t, err)
+	assert.True(t, res.IsZero())
+
+	_, err = InstanceReservations(map[string]string{"reservations.cpu": "two"})
+	assert.Error(t, err)
+}
+
+func TestAllocatable(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		want   Reservations
+	}{
+		{
+			name:   "Unknown capacity",
+			config: map[string]string{},
+			want:   Reservations{CPU: -1, Memory: -1},
+		},
+		{
+			name:   "Default ratio",
+			config: map[string]string{CapacityCPUKey: "8", CapacityMemoryKey: "1000"},
+			want:   Reservations{CPU: 8, Memory: 1000},
+		},
+		{
+			name:   "Overcommit",
+			config: map[string]string{CapacityCPUKey: "8", CapacityMemoryKey: "1000", "scheduler.overcommit.cpu": "4", "scheduler.overcommit.memory": "1.5"},
+			want:   Reservations{CPU: 32, Memory: 1500},
+		},
+		{
+			name:   "Undercommit",
+			config: map[string]string{CapacityCPUKey: "8", CapacityMemoryKey: "1000", "scheduler.overcommit.cpu": "0.5", "scheduler.overcommit.memory": "0.9"},
+			want:   Reservations{CPU: 4, Memory: 900},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Allocatable(tt.config)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterReservations(t *testing.T) {
+	candidates := []db.NodeInfo{
+		{Name: "member01", Config: map[string]string{CapacityCPUKey: "4", CapacityMemoryKey: "4096"}},
+		{Name: "member02", Config: map[string]string{CapacityCPUKey: "4", CapacityMemoryKey: "4096", "scheduler.overcommit.cpu": "2"}},
+		{Name: "member03", Config: map[string]string{}},
+	}
+
+	committed := map[string]Reservations{
+		"member01": {CPU: 3, Memory: 1024},
+		"member02": {CPU: 6, Memory: 3072},
+		"member03": {CPU: 100, Memory: 100000},
+	}
+
+	names := func(members []db.NodeInfo) []string {
+		result := make([]string, 0, len(members))
+		for _, member := range members {
+			result = append(result, member.Name)
+		}
+
+		return result
+	}
+
+	// Members with unknown capacity are always eligible.
+	filtered, err := filterReservations(candidates, committed, Reservations{CPU: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"member02", "member03"}, names(filtered))
+
+	filtered, err = filterReservations(candidates, committed, Reservations{CPU: 1, Memory: 2048})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"member01", "member03"}, names(filtered))
+
+	// No member left.
+	_, err = filterReservations(candidates[:2], committed, Reservations{CPU: 3})
+	assert.True(t, api.StatusErrorCheck(err, http.StatusConflict))
+}
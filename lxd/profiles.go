@@ -387,6 +387,11 @@ func profilesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProfile, api.WebhookAdmissionOperationCreate, p.Name, entity.ProfileURL(p.Name, req.Name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Quick checks.
 	if req.Name == "" {
 		return response.BadRequest(errors.New("No name provided"))
@@ -668,6 +673,11 @@ func profilePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProfile, api.WebhookAdmissionOperationUpdate, details.effectiveProject.Name, entity.ProfileURL(details.effectiveProject.Name, details.profileName), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	run := func(ctx context.Context, op *operations.Operation) error {
 		err = doProfileUpdate(ctx, s, details.effectiveProject, details.profileName, profile, req)
 
@@ -835,6 +845,11 @@ func profilePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	err = webhookAdmit(r, s, entity.TypeProfile, api.WebhookAdmissionOperationUpdate, details.effectiveProject.Name, entity.ProfileURL(details.effectiveProject.Name, details.profileName), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	run := func(ctx context.Context, op *operations.Operation) error {
 		requestor := request.CreateRequestor(ctx)
 		s.Events.SendLifecycle(details.effectiveProject.Name, lifecycle.ProfileUpdated.Event(details.profileName, details.effectiveProject.Name, requestor, nil))
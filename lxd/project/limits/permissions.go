@@ -1333,6 +1333,11 @@ var countConfigInstanceType = map[string]api.InstanceType{
 // Return true if the project has some limits or restrictions set.
 func projectHasLimitsOrRestrictions(project api.Project) bool {
 	for k, v := range project.Config {
+		// The API limits don't restrict the resources of the project.
+		if strings.HasPrefix(k, "limits.api.") {
+			continue
+		}
+
 		if strings.HasPrefix(k, "limits.") {
 			return true
 		}
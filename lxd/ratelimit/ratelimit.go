@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// pruneInterval is the minimum interval between two removals of the idle buckets of a [Limiter].
+const pruneInterval = time.Minute
+
+// Limit is the configuration of a token bucket.
+type Limit struct {
+	// Rate is the number of tokens added to the bucket per second. A rate of zero means no limit.
+	Rate float64
+
+	// Burst is the size of the bucket. If zero, the bucket holds the tokens added in one second (and at least one).
+	Burst int
+}
+
+// size returns the number of tokens that the bucket can hold.
+func (l Limit) size() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	limit   Limit
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens accumulated since the last update of the bucket.
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated)
+	if elapsed > 0 {
+		b.tokens = math.Min(b.limit.size(), b.tokens+elapsed.Seconds()*b.limit.Rate)
+		b.updated = now
+	}
+}
+
+// Limiter is a set of token buckets identified by a key.
+type Limiter struct {
+	mu         sync.Mutex
+	buckets    map[string]*bucket
+	lastPruned time.Time
+
+	// now returns the current time (overridable for testing).
+	now func() time.Time
+}
+
+// NewLimiter returns a new [Limiter] without buckets.
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Bucket identifies a token bucket of a [Limiter] along with its limit.
+type Bucket struct {
+	Key   string
+	Limit Limit
+}
+
+// Allow takes a token from the bucket of the given key, creating a full bucket if the key isn't known yet.
+// If the bucket is empty, Allow returns false and the duration after which a token is available.
+// The limit of an existing bucket is updated with the given limit.
+func (l *Limiter) Allow(key string, limit Limit) (bool, time.Duration) {
+	rejected, retryAfter := l.AllowAll(Bucket{Key: key, Limit: limit})
+	return rejected < 0, retryAfter
+}
+
+// AllowAll is like [Limiter.Allow] for several buckets at once. A token is only taken from the buckets if all of them
+// have one. Otherwise, AllowAll returns the index of the first empty bucket and the duration after which it has a token.
+// If a token was taken, the returned index is -1.
+func (l *Limiter) AllowAll(buckets ...Bucket) (int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	allowed := make([]*bucket, 0, len(buckets))
+	for i, requested := range buckets {
+		if requested.Limit.Rate <= 0 {
+			continue
+		}
+
+		b, ok := l.buckets[requested.Key]
+		if !ok {
+			b = &bucket{limit: requested.Limit, tokens: requested.Limit.size(), updated: now}
+			l.buckets[requested.Key] = b
+		} else {
+			b.refill(now)
+			b.limit = requested.Limit
+			b.tokens = math.Min(requested.Limit.size(), b.tokens)
+		}
+
+		if b.tokens < 1 {
+			return i, time.Duration((1 - b.tokens) / requested.Limit.Rate * float64(time.Second))
+		}
+
+		allowed = append(allowed, b)
+	}
+
+	for _, b := range allowed {
+		b.tokens--
+	}
+
+	return -1, 0
+}
+
+// prune removes the full buckets, as they are equivalent to a new bucket.
+// It must be called with the lock held.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPruned) < pruneInterval {
+		return
+	}
+
+	l.lastPruned = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= b.limit.size() {
+			delete(l.buckets, key)
+		}
+	}
+}
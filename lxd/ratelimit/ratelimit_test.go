@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter()
+	l.now = func() time.Time { return now }
+
+	limit := Limit{Rate: 2, Burst: 3}
+
+	// The burst is available at once.
+	for range 3 {
+		allowed, _ := l.Allow("foo", limit)
+		assert.True(t, allowed)
+	}
+
+	allowed, wait := l.Allow("foo", limit)
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Other keys have their own bucket.
+	allowed, _ = l.Allow("bar", limit)
+	assert.True(t, allowed)
+
+	// Tokens are added at the configured rate.
+	now = now.Add(time.Second)
+	for range 2 {
+		allowed, _ = l.Allow("foo", limit)
+		assert.True(t, allowed)
+	}
+
+	allowed, _ = l.Allow("foo", limit)
+	assert.False(t, allowed)
+
+	// Lowering the burst drops the extra tokens.
+	now = now.Add(time.Hour)
+	for range 2 {
+		allowed, _ = l.Allow("foo", Limit{Rate: 1})
+		if !allowed {
+			break
+		}
+	}
+
+	assert.False(t, allowed)
+
+	// No rate means no limit.
+	for range 10 {
+		allowed, _ = l.Allow("foo", Limit{})
+		assert.True(t, allowed)
+	}
+}
+
+func TestLimiterPrune(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter()
+	l.now = func() time.Time { return now }
+
+	l.Allow("foo", Limit{Rate: 1, Burst: 10})
+	l.Allow("bar", Limit{Rate: 0.001, Burst: 10})
+	assert.Len(t, l.buckets, 2)
+
+	// Only the refilled bucket is removed.
+	now = now.Add(2 * pruneInterval)
+	l.Allow("baz", Limit{Rate: 1})
+	assert.Len(t, l.buckets, 2)
+	assert.NotContains(t, l.buckets, "foo")
+}
+
+func TestLimiterAllowAll(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter()
+	l.now = func() time.Time { return now }
+
+	identity := Bucket{Key: "identity", Limit: Limit{Rate: 1, Burst: 2}}
+	project := Bucket{Key: "project", Limit: Limit{Rate: 1}}
+
+	rejected, _ := l.AllowAll(identity, project)
+	assert.Equal(t, -1, rejected)
+
+	// The project bucket is empty, so no token is taken from the identity bucket either.
+	rejected, wait := l.AllowAll(identity, project)
+	assert.Equal(t, 1, rejected)
+	assert.Equal(t, time.Second, wait)
+	assert.Equal(t, 1.0, l.buckets["identity"].tokens)
+
+	// The first empty bucket is reported.
+	allowed, _ := l.Allow("identity", identity.Limit)
+	assert.True(t, allowed)
+
+	rejected, _ = l.AllowAll(identity, project)
+	assert.Equal(t, 0, rejected)
+
+	// Buckets without a rate are ignored.
+	now = now.Add(time.Second)
+	rejected, _ = l.AllowAll(Bucket{Key: "other"}, project)
+	assert.Equal(t, -1, rejected)
+}
@@ -0,0 +1,659 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/canonical/lxd/client"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/db/operationtype"
+	"github.com/canonical/lxd/lxd/device/filters"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/lxd/project"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// replicatorDependencies holds the entities of a project that its instances depend on and that a replicator run
+// reconciles on the target before replicating the instances.
+type replicatorDependencies struct {
+	acls     []api.NetworkACL
+	zones    []replicatorZone
+	networks []api.Network
+	profiles []api.Profile
+	volumes  []replicatorVolume
+}
+
+// replicatorZone is a network zone along with its records.
+type replicatorZone struct {
+	zone    api.NetworkZone
+	records []api.NetworkZoneRecord
+}
+
+// replicatorVolume is a custom volume attached to an instance of the project.
+// The sourceLocation is only set for volumes on local pools loaded from the current leader cluster.
+type replicatorVolume struct {
+	poolName       string
+	volume         api.StorageVolume
+	sourceLocation string
+}
+
+// replicatorLoadDependencies loads the entities that the given instances of the project depend on.
+// Entities that are shared with other projects, such as the profiles of a project without the features.profiles
+// feature, are left out. Only OVN networks are replicated, as other network types are tied to the cluster members.
+func replicatorLoadDependencies(ctx context.Context, tx *db.ClusterTx, p *api.Project, allInsts []instance.Instance) (*replicatorDependencies, error) {
+	deps := &replicatorDependencies{}
+
+	if project.NetworkProjectFromRecord(p) == p.Name {
+		aclNames, err := tx.GetNetworkACLs(ctx, p.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network ACLs: %w", err)
+		}
+
+		for _, aclName := range aclNames {
+			_, acl, err := tx.GetNetworkACL(ctx, p.Name, aclName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading network ACL %q: %w", aclName, err)
+			}
+
+			deps.acls = append(deps.acls, *acl)
+		}
+
+		networks, err := tx.GetCreatedNetworksByProject(ctx, p.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading networks: %w", err)
+		}
+
+		for _, network := range networks {
+			if network.Type != "ovn" {
+				continue
+			}
+
+			deps.networks = append(deps.networks, network)
+		}
+
+		slices.SortFunc(deps.networks, func(a api.Network, b api.Network) int { return strings.Compare(a.Name, b.Name) })
+	}
+
+	if project.NetworkZoneProjectFromRecord(p) == p.Name {
+		zoneNames, err := tx.GetNetworkZonesByProject(ctx, p.Name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network zones: %w", err)
+		}
+
+		for _, zoneName := range zoneNames {
+			zoneID, zone, err := tx.GetNetworkZoneByProject(ctx, p.Name, zoneName)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading network zone %q: %w", zoneName, err)
+			}
+
+			recordNames, err := tx.GetNetworkZoneRecordNames(ctx, zoneID)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading records of network zone %q: %w", zoneName, err)
+			}
+
+			records := make([]api.NetworkZoneRecord, 0, len(recordNames))
+			for _, recordName := range recordNames {
+				_, record, err := tx.GetNetworkZoneRecord(ctx, zoneID, recordName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed loading record %q of network zone %q: %w", recordName, zoneName, err)
+				}
+
+				records = append(records, *record)
+			}
+
+			deps.zones = append(deps.zones, replicatorZone{zone: *zone, records: records})
+		}
+	}
+
+	if project.ProfileProjectFromRecord(p) == p.Name {
+		profiles, err := dbCluster.GetProfiles(ctx, tx.Tx(), dbCluster.ProfileFilter{Project: &p.Name})
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading profiles: %w", err)
+		}
+
+		for _, profile := range profiles {
+			apiProfile, err := profile.ToAPI(ctx, tx.Tx(), nil, nil)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading profile %q: %w", profile.Name, err)
+			}
+
+			deps.profiles = append(deps.profiles, *apiProfile)
+		}
+	}
+
+	if project.StorageVolumeProjectFromRecord(p, dbCluster.StoragePoolVolumeTypeCustom) == p.Name {
+		seen := make(map[string]bool)
+		volumeType := dbCluster.StoragePoolVolumeTypeCustom
+
+		for _, inst := range allInsts {
+			for _, namedDev := range inst.ExpandedDevices().Sorted() {
+				dev := namedDev.Config
+				if !filters.IsCustomVolumeDisk(dev) || (dev["source.type"] != "" && dev["source.type"] != dbCluster.StoragePoolVolumeTypeNameCustom) {
+					continue
+				}
+
+				poolName := dev["pool"]
+				volumeName := dev["source"]
+				key := poolName + "/" + volumeName
+				if seen[key] {
+					continue
+				}
+
+				seen[key] = true
+
+				poolID, err := tx.GetStoragePoolID(ctx, poolName)
+				if err != nil {
+					return nil, fmt.Errorf("Failed loading storage pool %q: %w", poolName, err)
+				}
+
+				volumes, err := tx.GetStorageVolumes(ctx, false, db.StorageVolumeFilter{
+					Project: &p.Name,
+					Type:    &volumeType,
+					Name:    &volumeName,
+					PoolID:  &poolID,
+				})
+				if err != nil {
+					return nil, fmt.Errorf("Failed loading custom volume %q: %w", volumeName, err)
+				}
+
+				// Volumes on local pools live on the cluster member of the instance.
+				for _, volume := range volumes {
+					if volume.Location == "" || volume.Location == inst.Location() {
+						deps.volumes = append(deps.volumes, replicatorVolume{poolName: poolName, volume: volume.StorageVolume})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// replicatorDependenciesResult holds the outcome of the reconciliation of the dependencies of a replicator run.
+// The done channel is closed once the other fields are set.
+type replicatorDependenciesResult struct {
+	done     chan struct{}
+	err      error
+	entities []dbCluster.ReplicatorEntity
+}
+
+// newReplicatorDependenciesResult returns a new pending replicatorDependenciesResult.
+func newReplicatorDependenciesResult() *replicatorDependenciesResult {
+	return &replicatorDependenciesResult{done: make(chan struct{})}
+}
+
+// wait waits for the dependencies to be reconciled. It returns an error if they weren't all synced.
+func (r *replicatorDependenciesResult) wait(ctx context.Context) error {
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if r.err != nil {
+		return fmt.Errorf("Instance dependencies weren't replicated: %w", r.err)
+	}
+
+	return nil
+}
+
+// replicatorDependenciesOperation returns the child operation of a replicator run that reconciles the dependencies
+// of the project using the given function. The outcome is stored in the given result, whose done channel is closed
+// once the operation has finished, whether it succeeded or not.
+func replicatorDependenciesOperation(projectName string, result *replicatorDependenciesResult, reconcile func(ctx context.Context, op *operations.Operation) ([]dbCluster.ReplicatorEntity, error)) *operations.OperationArgs {
+	return &operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entity.ProjectURL(projectName),
+		Type:        operationtype.ReplicatorRunDependencies,
+		Class:       operationtype.OperationClassTask,
+		RunHook: func(ctx context.Context, op *operations.Operation) error {
+			defer close(result.done)
+
+			result.entities, result.err = reconcile(ctx, op)
+			if result.err == nil {
+				result.err = replicatorDependenciesError(result.entities)
+			}
+
+			return result.err
+		},
+	}
+}
+
+// replicatorDependenciesError returns an error if some of the given entities weren't synced.
+func replicatorDependenciesError(entities []dbCluster.ReplicatorEntity) error {
+	for _, result := range entities {
+		if result.Status != api.ReplicatorEntityStatusSynced {
+			return errors.New("Some instance dependencies couldn't be replicated, see the replicator state for details")
+		}
+	}
+
+	return nil
+}
+
+// replicatorReconcileDependencies creates or updates the dependencies of the project on the destination and returns
+// the outcome for each entity. Network ACLs and zones are reconciled first as networks can refer to them, followed by
+// networks, profiles and custom volumes, which are copied using the given function.
+func replicatorReconcileDependencies(projectName string, deps *replicatorDependencies, dstClient lxd.InstanceServer, syncVolume func(vol replicatorVolume) error) []dbCluster.ReplicatorEntity {
+	results := []dbCluster.ReplicatorEntity{}
+
+	record := func(u *api.URL, err error) {
+		result := dbCluster.ReplicatorEntity{
+			EntityURL: u.String(),
+			Status:    api.ReplicatorEntityStatusSynced,
+		}
+
+		if err != nil {
+			result.Status = api.ReplicatorEntityStatusFailed
+			result.Message = err.Error()
+
+			// The destination refusing the entity means that it exists there in a form that can't be reconciled.
+			if api.StatusErrorCheck(err, http.StatusBadRequest, http.StatusConflict) {
+				result.Status = api.ReplicatorEntityStatusConflict
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	for _, acl := range deps.acls {
+		record(entity.NetworkACLURL(projectName, acl.Name), replicatorSyncNetworkACL(dstClient, acl))
+	}
+
+	for _, zone := range deps.zones {
+		record(entity.NetworkZoneURL(projectName, zone.zone.Name), replicatorSyncNetworkZone(dstClient, zone))
+	}
+
+	for _, network := range deps.networks {
+		record(entity.NetworkURL(projectName, network.Name), replicatorSyncNetwork(dstClient, network))
+	}
+
+	for _, profile := range deps.profiles {
+		record(entity.ProfileURL(projectName, profile.Name), replicatorSyncProfile(dstClient, profile))
+	}
+
+	for _, vol := range deps.volumes {
+		u := entity.StorageVolumeURL(projectName, vol.volume.Location, vol.poolName, vol.volume.Type, vol.volume.Name)
+		record(u, syncVolume(vol))
+	}
+
+	return results
+}
+
+// replicatorLoadRemoteDependencies loads from the current leader cluster the entities that the given instances of
+// the project depend on, for a restore run. It follows the same rules as replicatorLoadDependencies.
+// Custom volumes get the location of the local instance they are attached to, if any.
+func replicatorLoadRemoteDependencies(srcClient lxd.InstanceServer, p *api.Project, remoteInsts []api.Instance, allInsts []instance.Instance) (*replicatorDependencies, error) {
+	deps := &replicatorDependencies{}
+
+	if project.NetworkProjectFromRecord(p) == p.Name {
+		acls, err := srcClient.GetNetworkACLs()
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network ACLs: %w", err)
+		}
+
+		deps.acls = acls
+
+		networks, err := srcClient.GetNetworks()
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading networks: %w", err)
+		}
+
+		for _, network := range networks {
+			if !network.Managed || network.Type != "ovn" {
+				continue
+			}
+
+			deps.networks = append(deps.networks, network)
+		}
+
+		slices.SortFunc(deps.networks, func(a api.Network, b api.Network) int { return strings.Compare(a.Name, b.Name) })
+	}
+
+	if project.NetworkZoneProjectFromRecord(p) == p.Name {
+		zones, err := srcClient.GetNetworkZones()
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading network zones: %w", err)
+		}
+
+		for _, zone := range zones {
+			records, err := srcClient.GetNetworkZoneRecords(zone.Name)
+			if err != nil {
+				return nil, fmt.Errorf("Failed loading records of network zone %q: %w", zone.Name, err)
+			}
+
+			deps.zones = append(deps.zones, replicatorZone{zone: zone, records: records})
+		}
+	}
+
+	if project.ProfileProjectFromRecord(p) == p.Name {
+		profiles, err := srcClient.GetProfiles()
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading profiles: %w", err)
+		}
+
+		deps.profiles = profiles
+	}
+
+	if project.StorageVolumeProjectFromRecord(p, dbCluster.StoragePoolVolumeTypeCustom) == p.Name {
+		localLocations := make(map[string]string, len(allInsts))
+		for _, inst := range allInsts {
+			localLocations[inst.Name()] = inst.Location()
+		}
+
+		seen := make(map[string]bool)
+		for _, inst := range remoteInsts {
+			for _, devName := range slices.Sorted(maps.Keys(inst.ExpandedDevices)) {
+				dev := inst.ExpandedDevices[devName]
+				if !filters.IsCustomVolumeDisk(dev) || (dev["source.type"] != "" && dev["source.type"] != dbCluster.StoragePoolVolumeTypeNameCustom) {
+					continue
+				}
+
+				key := dev["pool"] + "/" + dev["source"]
+				if seen[key] {
+					continue
+				}
+
+				seen[key] = true
+
+				volume, _, err := srcClient.GetStoragePoolVolume(dev["pool"], dbCluster.StoragePoolVolumeTypeNameCustom, dev["source"])
+				if err != nil {
+					return nil, fmt.Errorf("Failed loading custom volume %q: %w", dev["source"], err)
+				}
+
+				// Volumes on local pools are restored on the cluster member of the local instance.
+				sourceLocation := volume.Location
+				if sourceLocation != "" {
+					volume.Location = localLocations[inst.Name]
+				}
+
+				deps.volumes = append(deps.volumes, replicatorVolume{poolName: dev["pool"], volume: *volume, sourceLocation: sourceLocation})
+			}
+		}
+	}
+
+	return deps, nil
+}
+
+// replicatorWait waits for the operation returned by a client call to complete.
+func replicatorWait(op lxd.Operation, err error) error {
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// replicatorDevicesEqual returns whether the given device sets are identical.
+func replicatorDevicesEqual(a map[string]map[string]string, b map[string]map[string]string) bool {
+	return maps.EqualFunc(a, b, func(x map[string]string, y map[string]string) bool { return maps.Equal(x, y) })
+}
+
+// replicatorSyncNetworkACL creates or updates a network ACL on the target.
+func replicatorSyncNetworkACL(dstClient lxd.InstanceServer, acl api.NetworkACL) error {
+	current, etag, err := dstClient.GetNetworkACL(acl.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return replicatorWait(dstClient.CreateNetworkACL(api.NetworkACLsPost{NetworkACLPost: api.NetworkACLPost{Name: acl.Name}, NetworkACLPut: acl.Writable()}))
+	}
+
+	if current.Description == acl.Description && maps.Equal(current.Config, acl.Config) && slices.Equal(current.Ingress, acl.Ingress) && slices.Equal(current.Egress, acl.Egress) {
+		return nil
+	}
+
+	return replicatorWait(dstClient.UpdateNetworkACL(acl.Name, acl.Writable(), etag))
+}
+
+// replicatorSyncNetworkZone creates or updates a network zone and its records on the target.
+func replicatorSyncNetworkZone(dstClient lxd.InstanceServer, zone replicatorZone) error {
+	current, etag, err := dstClient.GetNetworkZone(zone.zone.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		err = replicatorWait(dstClient.CreateNetworkZone(api.NetworkZonesPost{Name: zone.zone.Name, NetworkZonePut: zone.zone.Writable()}))
+		if err != nil {
+			return err
+		}
+	} else if current.Description != zone.zone.Description || !maps.Equal(current.Config, zone.zone.Config) {
+		err = replicatorWait(dstClient.UpdateNetworkZone(zone.zone.Name, zone.zone.Writable(), etag))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, record := range zone.records {
+		current, etag, err := dstClient.GetNetworkZoneRecord(zone.zone.Name, record.Name)
+		if err != nil {
+			if !api.StatusErrorCheck(err, http.StatusNotFound) {
+				return err
+			}
+
+			err = replicatorWait(dstClient.CreateNetworkZoneRecord(zone.zone.Name, api.NetworkZoneRecordsPost{Name: record.Name, NetworkZoneRecordPut: record.Writable()}))
+			if err != nil {
+				return fmt.Errorf("Failed creating record %q: %w", record.Name, err)
+			}
+
+			continue
+		}
+
+		if current.Description == record.Description && maps.Equal(current.Config, record.Config) && slices.Equal(current.Entries, record.Entries) {
+			continue
+		}
+
+		err = replicatorWait(dstClient.UpdateNetworkZoneRecord(zone.zone.Name, record.Name, record.Writable(), etag))
+		if err != nil {
+			return fmt.Errorf("Failed updating record %q: %w", record.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// replicatorSyncNetwork creates or updates a network on the target.
+// Volatile keys are specific to each cluster and aren't replicated.
+func replicatorSyncNetwork(dstClient lxd.InstanceServer, network api.Network) error {
+	put := network.Writable()
+	put.Config = make(map[string]string, len(network.Config))
+	for key, value := range network.Config {
+		if !strings.HasPrefix(key, "volatile.") {
+			put.Config[key] = value
+		}
+	}
+
+	current, etag, err := dstClient.GetNetwork(network.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return replicatorWait(dstClient.CreateNetwork(api.NetworksPost{Name: network.Name, Type: network.Type, NetworkPut: put}))
+	}
+
+	if !current.Managed || current.Type != network.Type {
+		return api.StatusErrorf(http.StatusConflict, "Network type %q differs from type %q on the target", network.Type, current.Type)
+	}
+
+	currentConfig := make(map[string]string, len(current.Config))
+	for key, value := range current.Config {
+		if !strings.HasPrefix(key, "volatile.") {
+			currentConfig[key] = value
+		}
+	}
+
+	if current.Description == put.Description && maps.Equal(currentConfig, put.Config) {
+		return nil
+	}
+
+	// Keep the volatile keys of the target network.
+	for key, value := range current.Config {
+		if strings.HasPrefix(key, "volatile.") {
+			put.Config[key] = value
+		}
+	}
+
+	return replicatorWait(dstClient.UpdateNetwork(network.Name, put, etag))
+}
+
+// replicatorSyncProfile creates or updates a profile on the target.
+func replicatorSyncProfile(dstClient lxd.InstanceServer, profile api.Profile) error {
+	current, etag, err := dstClient.GetProfile(profile.Name)
+	if err != nil {
+		if !api.StatusErrorCheck(err, http.StatusNotFound) {
+			return err
+		}
+
+		return dstClient.CreateProfile(api.ProfilesPost{Name: profile.Name, ProfilePut: profile.Writable()})
+	}
+
+	if current.Description == profile.Description && maps.Equal(current.Config, profile.Config) && replicatorDevicesEqual(current.Devices, profile.Devices) {
+		return nil
+	}
+
+	return replicatorWait(dstClient.UpdateProfile(profile.Name, profile.Writable(), etag))
+}
+
+// replicatorSyncVolume creates or refreshes a custom volume on the target, including its snapshots.
+// Volumes on other cluster members are pushed to the target by the hosting cluster member.
+func replicatorSyncVolume(ctx context.Context, s *state.State, op *operations.Operation, projectName string, vol replicatorVolume, dstClient lxd.InstanceServer, memberAddress string, targetCertPEM string) error {
+	current, _, err := dstClient.GetStoragePoolVolume(vol.poolName, vol.volume.Type, vol.volume.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	refresh := current != nil
+	if refresh && current.ContentType != vol.volume.ContentType {
+		return api.StatusErrorf(http.StatusConflict, "Volume content type %q differs from content type %q on the target", vol.volume.ContentType, current.ContentType)
+	}
+
+	if vol.volume.Location != "" && vol.volume.Location != s.ServerName {
+		if memberAddress == "" {
+			return fmt.Errorf("Failed resolving cluster member address for volume %q", vol.volume.Name)
+		}
+
+		memberClient, err := lxdCluster.Connect(ctx, memberAddress, s.Endpoints.NetworkCert(), s.ServerCert(), false)
+		if err != nil {
+			return fmt.Errorf("Failed connecting to hosting cluster member for volume %q: %w", vol.volume.Name, err)
+		}
+
+		memberClient = memberClient.UseProject(projectName)
+
+		copyOp, err := dstClient.CopyStoragePoolVolume(vol.poolName, memberClient, vol.poolName, vol.volume, &lxd.StoragePoolVolumeCopyArgs{Mode: "push", Refresh: refresh})
+		if err != nil {
+			return fmt.Errorf("Failed starting replication of volume %q: %w", vol.volume.Name, err)
+		}
+
+		return copyOp.Wait()
+	}
+
+	// Set up a push-mode migration sink on the destination.
+	destOp, err := dstClient.CreateStoragePoolVolume(vol.poolName, api.StorageVolumesPost{
+		Name:             vol.volume.Name,
+		Type:             vol.volume.Type,
+		ContentType:      vol.volume.ContentType,
+		StorageVolumePut: vol.volume.Writable(),
+		Source: api.StorageVolumeSource{
+			Type:    api.SourceTypeMigration,
+			Mode:    "push",
+			Refresh: refresh,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("Failed requesting volume create on destination: %w", err)
+	}
+
+	destOpCancelled := false
+	defer func() {
+		if !destOpCancelled {
+			_ = destOp.Cancel()
+		}
+	}()
+
+	destOpAPI := destOp.Get()
+	destSecrets, err := destOpAPI.WebsocketSecrets()
+	if err != nil {
+		return fmt.Errorf("Failed getting websocket secrets from destination for volume %q: %w", vol.volume.Name, err)
+	}
+
+	srcMigration, err := newStorageMigrationSource(false, &api.StorageVolumePostTarget{
+		Operation:   destOp.URL().String(),
+		Websockets:  destSecrets,
+		Certificate: targetCertPEM,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed setting up migration source for volume %q: %w", vol.volume.Name, err)
+	}
+
+	migrArgs := operations.OperationArgs{
+		ProjectName: projectName,
+		EntityURL:   entity.StorageVolumeURL(projectName, vol.volume.Location, vol.poolName, vol.volume.Type, vol.volume.Name),
+		Type:        operationtype.VolumeMigrate,
+		Class:       operationtype.OperationClassTask,
+		RunHook: func(ctx context.Context, innerOp *operations.Operation) error {
+			return srcMigration.DoStorage(s, projectName, vol.poolName, vol.volume.Name, innerOp)
+		},
+	}
+
+	var srcOp *operations.Operation
+	if op.Requestor() != nil {
+		srcOp, err = operations.ScheduleUserOperationFromOperation(s, op, migrArgs)
+	} else {
+		srcOp, err = operations.ScheduleServerOperation(s, migrArgs)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	destOpCancelled = true
+
+	err = srcOp.Wait(context.Background())
+	if err != nil {
+		return fmt.Errorf("Replication of volume %q failed on source: %w", vol.volume.Name, err)
+	}
+
+	return destOp.Wait()
+}
+
+// replicatorRestoreVolume creates or refreshes a custom volume from the current leader cluster on the local cluster,
+// including its snapshots. The local server pulls the volume from the current leader cluster.
+func replicatorRestoreVolume(srcClient lxd.InstanceServer, dstClient lxd.InstanceServer, vol replicatorVolume) error {
+	current, _, err := dstClient.GetStoragePoolVolume(vol.poolName, vol.volume.Type, vol.volume.Name)
+	if err != nil && !api.StatusErrorCheck(err, http.StatusNotFound) {
+		return err
+	}
+
+	refresh := current != nil
+	if refresh && current.ContentType != vol.volume.ContentType {
+		return api.StatusErrorf(http.StatusConflict, "Volume content type %q differs from content type %q on the target", vol.volume.ContentType, current.ContentType)
+	}
+
+	if vol.sourceLocation != "" {
+		srcClient = srcClient.UseTarget(vol.sourceLocation)
+	}
+
+	if vol.volume.Location != "" {
+		dstClient = dstClient.UseTarget(vol.volume.Location)
+	}
+
+	copyOp, err := dstClient.CopyStoragePoolVolume(vol.poolName, srcClient, vol.poolName, vol.volume, &lxd.StoragePoolVolumeCopyArgs{Mode: "pull", Refresh: refresh})
+	if err != nil {
+		return fmt.Errorf("Failed starting restore of volume %q: %w", vol.volume.Name, err)
+	}
+
+	return copyOp.Wait()
+}
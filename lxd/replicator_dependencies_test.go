@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/client"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/operations"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// replicatorTestOperation is a completed client operation.
+type replicatorTestOperation struct {
+	lxd.Operation
+}
+
+// Wait returns immediately as the operation is already complete.
+func (op replicatorTestOperation) Wait() error {
+	return nil
+}
+
+// replicatorTestServer is a destination server on which no entity exists yet. It records the entities that are
+// created in order and fails the creation of the entities listed in failures.
+type replicatorTestServer struct {
+	lxd.InstanceServer
+
+	networks map[string]api.Network
+	failures map[string]error
+	created  []string
+}
+
+func (r *replicatorTestServer) create(name string) error {
+	err := r.failures[name]
+	if err != nil {
+		return err
+	}
+
+	r.created = append(r.created, name)
+	return nil
+}
+
+func (r *replicatorTestServer) GetNetworkACL(name string) (*api.NetworkACL, string, error) {
+	return nil, "", api.NewStatusError(http.StatusNotFound, "Network ACL not found")
+}
+
+func (r *replicatorTestServer) CreateNetworkACL(acl api.NetworkACLsPost) (lxd.Operation, error) {
+	return replicatorTestOperation{}, r.create("acl/" + acl.Name)
+}
+
+func (r *replicatorTestServer) GetNetworkZone(name string) (*api.NetworkZone, string, error) {
+	return nil, "", api.NewStatusError(http.StatusNotFound, "Network zone not found")
+}
+
+func (r *replicatorTestServer) CreateNetworkZone(zone api.NetworkZonesPost) (lxd.Operation, error) {
+	return replicatorTestOperation{}, r.create("zone/" + zone.Name)
+}
+
+func (r *replicatorTestServer) GetNetworkZoneRecord(zone string, name string) (*api.NetworkZoneRecord, string, error) {
+	return nil, "", api.NewStatusError(http.StatusNotFound, "Network zone record not found")
+}
+
+func (r *replicatorTestServer) CreateNetworkZoneRecord(zone string, record api.NetworkZoneRecordsPost) (lxd.Operation, error) {
+	return replicatorTestOperation{}, r.create("zone/" + zone + "/" + record.Name)
+}
+
+func (r *replicatorTestServer) GetNetwork(name string) (*api.Network, string, error) {
+	network, ok := r.networks[name]
+	if !ok {
+		return nil, "", api.NewStatusError(http.StatusNotFound, "Network not found")
+	}
+
+	return &network, "", nil
+}
+
+func (r *replicatorTestServer) CreateNetwork(network api.NetworksPost) (lxd.Operation, error) {
+	return replicatorTestOperation{}, r.create("network/" + network.Name)
+}
+
+func (r *replicatorTestServer) GetProfile(name string) (*api.Profile, string, error) {
+	return nil, "", api.NewStatusError(http.StatusNotFound, "Profile not found")
+}
+
+func (r *replicatorTestServer) CreateProfile(profile api.ProfilesPost) error {
+	return r.create("profile/" + profile.Name)
+}
+
+func replicatorTestDependencies() *replicatorDependencies {
+	return &replicatorDependencies{
+		acls: []api.NetworkACL{{Name: "acl1"}},
+		zones: []replicatorZone{{
+			zone:    api.NetworkZone{Name: "example.com"},
+			records: []api.NetworkZoneRecord{{Name: "www"}},
+		}},
+		networks: []api.Network{{Name: "ovn1", Type: "ovn", Managed: true}},
+		profiles: []api.Profile{{Name: "web"}},
+		volumes: []replicatorVolume{{
+			poolName: "default",
+			volume:   api.StorageVolume{Name: "data", Type: dbCluster.StoragePoolVolumeTypeNameCustom},
+		}},
+	}
+}
+
+func TestReplicatorReconcileDependencies(t *testing.T) {
+	dstClient := &replicatorTestServer{}
+
+	var syncedVolumes []string
+	entities := replicatorReconcileDependencies("p1", replicatorTestDependencies(), dstClient, func(vol replicatorVolume) error {
+		// The volumes are copied once all the other entities exist on the destination.
+		assert.Len(t, dstClient.created, 5)
+		syncedVolumes = append(syncedVolumes, vol.poolName+"/"+vol.volume.Name)
+		return nil
+	})
+
+	// Network ACLs and zones come first, as networks can refer to them.
+	assert.Equal(t, []string{"acl/acl1", "zone/example.com", "zone/example.com/www", "network/ovn1", "profile/web"}, dstClient.created)
+	assert.Equal(t, []string{"default/data"}, syncedVolumes)
+
+	urls := make([]string, 0, len(entities))
+	for _, result := range entities {
+		assert.Equal(t, api.ReplicatorEntityStatusSynced, result.Status)
+		urls = append(urls, result.EntityURL)
+	}
+
+	assert.Equal(t, []string{
+		"/1.0/network-acls/acl1?project=p1",
+		"/1.0/network-zones/example.com?project=p1",
+		"/1.0/networks/ovn1?project=p1",
+		"/1.0/profiles/web?project=p1",
+		"/1.0/storage-pools/default/volumes/custom/data?project=p1",
+	}, urls)
+	assert.NoError(t, replicatorDependenciesError(entities))
+}
+
+func TestReplicatorReconcileDependencies_Failures(t *testing.T) {
+	dstClient := &replicatorTestServer{
+		// A bridge of the same name exists on the destination.
+		networks: map[string]api.Network{"ovn1": {Name: "ovn1", Type: "bridge", Managed: true}},
+		failures: map[string]error{"profile/web": errors.New("Database is unavailable")},
+	}
+
+	entities := replicatorReconcileDependencies("p1", replicatorTestDependencies(), dstClient, func(vol replicatorVolume) error {
+		return api.NewStatusError(http.StatusBadRequest, "Volume content type differs")
+	})
+
+	// A failing entity doesn't prevent the others from being reconciled.
+	assert.Equal(t, []string{"acl/acl1", "zone/example.com", "zone/example.com/www"}, dstClient.created)
+
+	statuses := make(map[string]string, len(entities))
+	for _, result := range entities {
+		statuses[result.EntityURL] = result.Status
+	}
+
+	assert.Equal(t, map[string]string{
+		"/1.0/network-acls/acl1?project=p1":                         api.ReplicatorEntityStatusSynced,
+		"/1.0/network-zones/example.com?project=p1":                 api.ReplicatorEntityStatusSynced,
+		"/1.0/networks/ovn1?project=p1":                             api.ReplicatorEntityStatusConflict,
+		"/1.0/profiles/web?project=p1":                              api.ReplicatorEntityStatusFailed,
+		"/1.0/storage-pools/default/volumes/custom/data?project=p1": api.ReplicatorEntityStatusConflict,
+	}, statuses)
+	assert.Error(t, replicatorDependenciesError(entities))
+}
+
+func TestReplicatorDependenciesOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		entities  []dbCluster.ReplicatorEntity
+		reconcile error
+		wantErr   bool
+	}{
+		{
+			name:     "All entities synced",
+			entities: []dbCluster.ReplicatorEntity{{EntityURL: "/1.0/profiles/web", Status: api.ReplicatorEntityStatusSynced}},
+		},
+		{
+			name:     "Entity conflict",
+			entities: []dbCluster.ReplicatorEntity{{EntityURL: "/1.0/profiles/web", Status: api.ReplicatorEntityStatusConflict}},
+			wantErr:  true,
+		},
+		{
+			name:      "Unreachable destination",
+			reconcile: errors.New("Failed connecting to target cluster"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := newReplicatorDependenciesResult()
+			args := replicatorDependenciesOperation("p1", result, func(ctx context.Context, _ *operations.Operation) ([]dbCluster.ReplicatorEntity, error) {
+				return tt.entities, tt.reconcile
+			})
+
+			err := args.RunHook(context.Background(), nil)
+
+			// The instances waiting for the dependencies are released in all cases and fail with them.
+			waitErr := result.wait(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Error(t, waitErr)
+			} else {
+				require.NoError(t, err)
+				assert.NoError(t, waitErr)
+			}
+
+			assert.Equal(t, tt.entities, result.entities)
+		})
+	}
+}
+
+func TestReplicatorDependenciesResultWait_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := newReplicatorDependenciesResult().wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,657 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/client"
+	lxdCluster "github.com/canonical/lxd/lxd/cluster"
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/instance"
+	"github.com/canonical/lxd/lxd/lifecycle"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// replicatorFailoverDefaultInterval is the default time between two health checks of the leader of a standby project.
+const replicatorFailoverDefaultInterval = 30 * time.Second
+
+// replicatorFailoverDefaultThreshold is the default number of consecutive failed health checks before a standby
+// project is promoted.
+const replicatorFailoverDefaultThreshold = 3
+
+// replicatorFailoverLeaseKey is the configuration key of the project on the witness cluster that holds the lease
+// of the leader project.
+const replicatorFailoverLeaseKey = "user.replica.lease"
+
+// errReplicatorFailoverLeaseLost is returned when renewing a lease that is held by another cluster.
+var errReplicatorFailoverLeaseLost = errors.New("Lease is held by another cluster")
+
+// replicatorFailoverPolicyKeys maps the replicator configuration keys of the failover policy to the configuration
+// keys of the standby project.
+var replicatorFailoverPolicyKeys = map[string]string{
+	"failover.mode":                   "replica.failover.mode",
+	"failover.witness":                "replica.failover.witness",
+	"failover.health_check.interval":  "replica.failover.health_check.interval",
+	"failover.health_check.threshold": "replica.failover.health_check.threshold",
+}
+
+// replicatorFailoverPolicy is an automatic failover policy, either from the configuration of a replicator or from
+// the replica.failover.* configuration keys of a project.
+type replicatorFailoverPolicy struct {
+	witness   string
+	interval  time.Duration
+	threshold int
+}
+
+// replicatorFailoverPolicyFromConfig returns the automatic failover policy defined by the given configuration, whose
+// keys start with the given prefix. It returns nil if the failover mode isn't automatic.
+func replicatorFailoverPolicyFromConfig(config map[string]string, prefix string) *replicatorFailoverPolicy {
+	if config[prefix+"failover.mode"] != "automatic" {
+		return nil
+	}
+
+	policy := &replicatorFailoverPolicy{
+		witness:   config[prefix+"failover.witness"],
+		interval:  replicatorFailoverDefaultInterval,
+		threshold: replicatorFailoverDefaultThreshold,
+	}
+
+	seconds, err := strconv.Atoi(config[prefix+"failover.health_check.interval"])
+	if err == nil {
+		policy.interval = time.Duration(seconds) * time.Second
+	}
+
+	threshold, err := strconv.Atoi(config[prefix+"failover.health_check.threshold"])
+	if err == nil {
+		policy.threshold = threshold
+	}
+
+	return policy
+}
+
+// fenceAfter returns how long the leader keeps its project active without renewing its lease on the witness.
+func (p *replicatorFailoverPolicy) fenceAfter() time.Duration {
+	return time.Duration(p.threshold) * p.interval
+}
+
+// leaseTimeout returns how long the lease of the leader must stay unchanged on the witness before the standby takes
+// it over. It leaves the leader two more health check intervals to fence itself.
+func (p *replicatorFailoverPolicy) leaseTimeout() time.Duration {
+	return time.Duration(p.threshold+2) * p.interval
+}
+
+// replicatorFailoverState tracks the health checks of the leader of a standby project, or the lease renewals of a
+// leader project.
+type replicatorFailoverState struct {
+	lastCheck time.Time
+	failures  int
+
+	// Lease of the leader as last seen on the witness by the standby, and since when it's unchanged.
+	leaseValue string
+	leaseSince time.Time
+
+	// Last successful renewal of the lease by the leader.
+	lastRenewal time.Time
+}
+
+// healthCheckFailed records a failed health check of the leader and returns whether the threshold is reached.
+func (st *replicatorFailoverState) healthCheckFailed(threshold int) bool {
+	st.failures++
+	return st.failures >= threshold
+}
+
+// healthCheckSucceeded records a successful health check of the leader and returns whether the leader was
+// previously unreachable. The observed lease is forgotten as the leader is alive.
+func (st *replicatorFailoverState) healthCheckSucceeded() bool {
+	recovered := st.failures > 0
+	st.failures = 0
+	st.leaseValue = ""
+	st.leaseSince = time.Time{}
+
+	return recovered
+}
+
+// observeLease records the lease of the leader as seen on the witness and returns how long it has been unchanged.
+func (st *replicatorFailoverState) observeLease(value string, now time.Time) time.Duration {
+	if st.leaseSince.IsZero() || value != st.leaseValue {
+		st.leaseValue = value
+		st.leaseSince = now
+	}
+
+	return now.Sub(st.leaseSince)
+}
+
+// replicatorApplyFailoverPolicy sets the failover policy of the replicator on the standby project.
+func replicatorApplyFailoverPolicy(targetClient lxd.InstanceServer, targetProject *api.Project, ETag string, config map[string]string) error {
+	put := targetProject.Writable()
+	if put.Config == nil {
+		put.Config = map[string]string{}
+	}
+
+	changed := false
+	for replicatorKey, projectKey := range replicatorFailoverPolicyKeys {
+		if put.Config[projectKey] == config[replicatorKey] {
+			continue
+		}
+
+		changed = true
+		if config[replicatorKey] == "" {
+			delete(put.Config, projectKey)
+		} else {
+			put.Config[projectKey] = config[replicatorKey]
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return targetClient.UpdateProject(targetProject.Name, put, ETag)
+}
+
+// replicatorFailoverProject is a project with an automatic failover policy.
+type replicatorFailoverProject struct {
+	project api.Project
+	policy  *replicatorFailoverPolicy
+}
+
+// replicatorFailoverTask returns a task that monitors the leaders of the standby projects with an automatic
+// failover policy, promotes the standby projects whose leader lease has expired on the witness and fences the old
+// leaders once they come back. Leader projects with an automatic failover policy renew their lease on the witness
+// and fence themselves if they can't. The task only runs on the cluster leader.
+func replicatorFailoverTask(stateFunc func() *state.State) (task.Func, task.Schedule) {
+	states := make(map[string]*replicatorFailoverState)
+
+	f := func(ctx context.Context) {
+		s := stateFunc()
+
+		leaderInfo, err := s.LeaderInfo()
+		if err != nil || (leaderInfo.Clustered && !leaderInfo.Leader) {
+			return
+		}
+
+		projects, err := replicatorFailoverLoadProjects(ctx, s)
+		if err != nil {
+			logger.Error("Failed loading projects for replicator failover", logger.Ctx{"err": err})
+			return
+		}
+
+		seen := make(map[string]bool, len(projects))
+		for _, fp := range projects {
+			p := fp.project
+			seen[p.Name] = true
+
+			failoverState, ok := states[p.Name]
+			if !ok {
+				failoverState = &replicatorFailoverState{lastRenewal: time.Now()}
+				states[p.Name] = failoverState
+			}
+
+			if time.Since(failoverState.lastCheck) < fp.policy.interval {
+				continue
+			}
+
+			failoverState.lastCheck = time.Now()
+
+			switch p.ReplicaMode {
+			case api.ReplicatorProjectModeStandby:
+				replicatorFailoverCheckLeader(ctx, s, p, fp.policy, failoverState)
+			case api.ReplicatorProjectModeLeader:
+				if !replicatorFailoverRenewLeadership(ctx, s, p, fp.policy, failoverState) {
+					continue
+				}
+
+				// A project promoted by automatic failover fences the old leader once it comes back.
+				if p.Config["replica.failover.mode"] == "automatic" && p.Config["replica.cluster"] != "" {
+					replicatorFailoverFence(ctx, s, p)
+				}
+			}
+		}
+
+		for name := range states {
+			if !seen[name] {
+				delete(states, name)
+			}
+		}
+	}
+
+	return f, task.Every(10 * time.Second)
+}
+
+// replicatorFailoverLoadProjects loads the standby projects with an automatic failover policy, and the leader
+// projects with an automatic failover policy either from their own configuration or from one of their replicators.
+func replicatorFailoverLoadProjects(ctx context.Context, s *state.State) ([]replicatorFailoverProject, error) {
+	var projects []replicatorFailoverProject
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		dbProjects, err := dbCluster.GetProjects(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, dbProject := range dbProjects {
+			p, err := dbProject.ToAPI(ctx, tx.Tx())
+			if err != nil {
+				return err
+			}
+
+			policy := replicatorFailoverPolicyFromConfig(p.Config, "replica.")
+
+			switch p.ReplicaMode {
+			case api.ReplicatorProjectModeStandby:
+				if p.Config["replica.cluster"] == "" {
+					continue
+				}
+
+			case api.ReplicatorProjectModeLeader:
+				if policy != nil {
+					break
+				}
+
+				replicators, _, err := dbCluster.GetReplicatorsAndURLs(ctx, tx.Tx(), &p.Name, nil)
+				if err != nil {
+					return fmt.Errorf("Failed loading replicators of project %q: %w", p.Name, err)
+				}
+
+				for _, r := range replicators {
+					configs, err := dbCluster.ReplicatorsConfigStore().GetByEntityIDs(ctx, tx.Tx(), r.Row.ID)
+					if err != nil {
+						return fmt.Errorf("Failed loading replicator config: %w", err)
+					}
+
+					policy = replicatorFailoverPolicyFromConfig(configs[r.Row.ID], "")
+					if policy != nil {
+						break
+					}
+				}
+
+			default:
+				continue
+			}
+
+			if policy == nil {
+				continue
+			}
+
+			// Without a witness, the clusters can't tell a failed leader from a network partition.
+			if policy.witness == "" {
+				logger.Warn("Ignoring automatic failover policy without witness", logger.Ctx{"project": p.Name})
+				continue
+			}
+
+			projects = append(projects, replicatorFailoverProject{project: *p, policy: policy})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// replicatorFailoverConnect connects to the cluster of the given cluster link and uses the given project.
+func replicatorFailoverConnect(ctx context.Context, s *state.State, clusterLinkName string, projectName string) (lxd.InstanceServer, error) {
+	var clusterLink *api.ClusterLink
+	var targetCert *x509.Certificate
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+		_, clusterLink, targetCert, err = lxdCluster.LoadClusterLinkAndCert(ctx, tx.Tx(), clusterLinkName)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading cluster link %q: %w", clusterLinkName, err)
+	}
+
+	client, err := lxdCluster.ConnectCluster(ctx, *clusterLink, lxdCluster.GetClusterLinkConnectionArgs(s.Endpoints.NetworkCert(), targetCert))
+	if err != nil {
+		return nil, fmt.Errorf("Failed connecting to cluster link %q: %w", clusterLinkName, err)
+	}
+
+	return client.UseProject(projectName), nil
+}
+
+// replicatorFailoverLeaseHolder returns the identifier of the local cluster in the leases on the witness.
+func replicatorFailoverLeaseHolder(s *state.State) string {
+	return s.Endpoints.NetworkCert().Fingerprint()
+}
+
+// replicatorFailoverParseLease returns the holder and the renewal counter of a lease.
+func replicatorFailoverParseLease(value string) (holder string, counter uint64) {
+	holder, counterStr, _ := strings.Cut(value, "/")
+	counter, _ = strconv.ParseUint(counterStr, 10, 64)
+	return holder, counter
+}
+
+// replicatorFailoverGetLease returns the lease of the project on the witness.
+func replicatorFailoverGetLease(witness lxd.InstanceServer, projectName string) (string, error) {
+	p, _, err := witness.GetProject(projectName)
+	if err != nil {
+		return "", err
+	}
+
+	return p.Config[replicatorFailoverLeaseKey], nil
+}
+
+// replicatorFailoverRenewLease renews the lease of the project on the witness for the given holder.
+// It fails with a conflict if the lease is held by another cluster.
+func replicatorFailoverRenewLease(witness lxd.InstanceServer, projectName string, holder string) error {
+	p, etag, err := witness.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	currentHolder, counter := replicatorFailoverParseLease(p.Config[replicatorFailoverLeaseKey])
+	if currentHolder != "" && currentHolder != holder {
+		return errReplicatorFailoverLeaseLost
+	}
+
+	return replicatorFailoverWriteLease(witness, p, etag, holder, counter+1)
+}
+
+// replicatorFailoverTakeLease takes over the lease of the project on the witness for the given holder, provided it
+// still has the given value. The update is conditional on the ETag of the witness project so that a concurrent
+// renewal makes it fail.
+func replicatorFailoverTakeLease(witness lxd.InstanceServer, projectName string, holder string, expected string) error {
+	p, etag, err := witness.GetProject(projectName)
+	if err != nil {
+		return err
+	}
+
+	current := p.Config[replicatorFailoverLeaseKey]
+	if current != expected {
+		return errors.New("Lease was renewed")
+	}
+
+	_, counter := replicatorFailoverParseLease(current)
+
+	return replicatorFailoverWriteLease(witness, p, etag, holder, counter+1)
+}
+
+// replicatorFailoverWriteLease writes the lease of the project on the witness.
+func replicatorFailoverWriteLease(witness lxd.InstanceServer, p *api.Project, etag string, holder string, counter uint64) error {
+	put := p.Writable()
+	if put.Config == nil {
+		put.Config = map[string]string{}
+	}
+
+	put.Config[replicatorFailoverLeaseKey] = holder + "/" + strconv.FormatUint(counter, 10)
+
+	return witness.UpdateProject(p.Name, put, etag)
+}
+
+// replicatorFailoverRenewLeadership renews the lease of a leader project on its witness. If the lease is held by
+// another cluster, or couldn't be renewed for too long, the project fences itself as the standby may have taken
+// over. It returns whether the project is still the leader.
+func replicatorFailoverRenewLeadership(ctx context.Context, s *state.State, p api.Project, policy *replicatorFailoverPolicy, failoverState *replicatorFailoverState) bool {
+	l := logger.AddContext(logger.Ctx{"project": p.Name, "witness": policy.witness})
+
+	renewCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	witness, err := replicatorFailoverConnect(renewCtx, s, policy.witness, p.Name)
+	if err == nil {
+		err = replicatorFailoverRenewLease(witness, p.Name, replicatorFailoverLeaseHolder(s))
+		witness.Disconnect()
+	}
+
+	if err == nil {
+		failoverState.lastRenewal = time.Now()
+		return true
+	}
+
+	if !errors.Is(err, errReplicatorFailoverLeaseLost) && time.Since(failoverState.lastRenewal) < policy.fenceAfter() {
+		l.Warn("Failed renewing leader lease on witness", logger.Ctx{"err": err})
+		return true
+	}
+
+	l.Warn("Fencing leader project that lost its lease on the witness", logger.Ctx{"err": err})
+
+	client, err := lxd.ConnectLXDUnix(s.OS.GetUnixSocket(), nil)
+	if err == nil {
+		err = replicatorFailoverFenceProject(client.UseProject(p.Name), p.Name)
+	}
+
+	if err != nil {
+		l.Error("Failed fencing leader project", logger.Ctx{"err": err})
+		return false
+	}
+
+	s.Events.SendLifecycle(p.Name, lifecycle.ProjectFenced.Event(p.Name, nil, map[string]any{"witness": policy.witness}))
+
+	return false
+}
+
+// replicatorFailoverCheckLeader runs a health check of the leader of a standby project. Once the number of
+// consecutive failed health checks reaches the configured threshold, the project is promoted if the lease of the
+// leader has expired on the witness and the standby could take it over. A leader that is only cut off from the
+// standby keeps renewing its lease, so the standby isn't promoted.
+func replicatorFailoverCheckLeader(ctx context.Context, s *state.State, p api.Project, policy *replicatorFailoverPolicy, failoverState *replicatorFailoverState) {
+	l := logger.AddContext(logger.Ctx{"project": p.Name, "clusterLink": p.Config["replica.cluster"], "witness": policy.witness})
+
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := replicatorFailoverConnect(checkCtx, s, p.Config["replica.cluster"], p.Name)
+	if err == nil {
+		_, _, err = client.GetProject(p.Name)
+		client.Disconnect()
+	}
+
+	if err == nil {
+		if failoverState.healthCheckSucceeded() {
+			l.Info("Leader of standby project is reachable again")
+			s.Events.SendLifecycle(p.Name, lifecycle.ProjectLeaderReachable.Event(p.Name, nil, map[string]any{"cluster_link": p.Config["replica.cluster"]}))
+		}
+
+		return
+	}
+
+	thresholdReached := failoverState.healthCheckFailed(policy.threshold)
+
+	l.Warn("Failed health check of the leader of standby project", logger.Ctx{"failures": failoverState.failures, "threshold": policy.threshold, "err": err})
+
+	if failoverState.failures == 1 {
+		s.Events.SendLifecycle(p.Name, lifecycle.ProjectLeaderUnreachable.Event(p.Name, nil, map[string]any{"cluster_link": p.Config["replica.cluster"], "failures": failoverState.failures}))
+	}
+
+	// Watch the lease of the leader on the witness from the first failed health check.
+	witness, err := replicatorFailoverConnect(checkCtx, s, policy.witness, p.Name)
+	if err != nil {
+		l.Warn("Failed connecting to witness of standby project", logger.Ctx{"err": err})
+		return
+	}
+
+	defer witness.Disconnect()
+
+	lease, err := replicatorFailoverGetLease(witness, p.Name)
+	if err != nil {
+		l.Warn("Failed getting leader lease from witness", logger.Ctx{"err": err})
+		return
+	}
+
+	unchanged := failoverState.observeLease(lease, time.Now())
+	if !thresholdReached || unchanged < policy.leaseTimeout() {
+		return
+	}
+
+	// The leader lease expired, so the leader has fenced itself or is down. Take the lease over before promoting
+	// so that the old leader fences itself if it comes back.
+	err = replicatorFailoverTakeLease(witness, p.Name, replicatorFailoverLeaseHolder(s), lease)
+	if err != nil {
+		l.Warn("Failed taking over leader lease on witness", logger.Ctx{"err": err})
+		return
+	}
+
+	err = projectPromote(ctx, s, p.Name, true)
+	if err != nil {
+		l.Error("Failed promoting standby project", logger.Ctx{"err": err})
+		return
+	}
+
+	failoverState.healthCheckSucceeded()
+	failoverState.lastRenewal = time.Now()
+
+	l.Warn("Promoted standby project after losing its leader")
+	s.Events.SendLifecycle(p.Name, lifecycle.ProjectPromoted.Event(p.Name, nil, map[string]any{"cluster_link": p.Config["replica.cluster"]}))
+
+	replicatorFailoverStartInstances(ctx, s, p.Name)
+}
+
+// replicatorFailoverStartInstances starts the instances of a promoted project in boot order, on whichever cluster
+// member they're located.
+func replicatorFailoverStartInstances(ctx context.Context, s *state.State, projectName string) {
+	var instances []instance.Instance
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return tx.InstanceList(ctx, func(dbInst db.InstanceArgs, p api.Project) error {
+			inst, err := instance.Load(s, dbInst, p)
+			if err != nil {
+				return fmt.Errorf("Failed loading instance %q: %w", dbInst.Name, err)
+			}
+
+			instances = append(instances, inst)
+			return nil
+		}, dbCluster.InstanceFilter{Project: &projectName})
+	})
+	if err != nil {
+		logger.Error("Failed loading instances of promoted project", logger.Ctx{"project": projectName, "err": err})
+		return
+	}
+
+	failed := make(map[string]error)
+	for _, inst := range instance.BootOrder(instances) {
+		if !instanceShouldAutoStart(inst) {
+			continue
+		}
+
+		instKey := instanceBootKey(inst.Project().Name, inst.Name())
+		l := logger.AddContext(logger.Ctx{"project": inst.Project().Name, "instance": inst.Name()})
+
+		err := instanceBootDependencyError(inst, failed)
+		if err == nil {
+			err = replicatorFailoverStartInstance(ctx, s, inst)
+		}
+
+		if err != nil {
+			failed[instKey] = err
+			l.Error("Failed starting instance of promoted project", logger.Ctx{"err": err})
+		}
+	}
+}
+
+// replicatorFailoverStartInstance starts an instance and waits for it to be healthy.
+func replicatorFailoverStartInstance(ctx context.Context, s *state.State, inst instance.Instance) error {
+	client, err := lxdCluster.ConnectIfInstanceIsRemote(ctx, s, inst.Project().Name, inst.Name(), inst.Type())
+	if err != nil {
+		return err
+	}
+
+	if client != nil {
+		instState, _, err := client.GetInstanceState(inst.Name())
+		if err != nil {
+			return err
+		}
+
+		if instState.StatusCode == api.Running {
+			return nil
+		}
+
+		op, err := client.UpdateInstanceState(inst.Name(), api.InstanceStatePut{Action: "start"}, "")
+		if err != nil {
+			return err
+		}
+
+		return op.Wait()
+	}
+
+	// Reload the local instance to get its actual state.
+	inst, err = instance.LoadByProjectAndName(s, inst.Project().Name, inst.Name())
+	if err != nil {
+		return err
+	}
+
+	if !inst.IsRunning() {
+		err = inst.Start(ctx, false, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return instanceBootWaitHealthy(ctx, inst)
+}
+
+// replicatorFailoverFence fences the old leader of a project promoted by automatic failover. If the old leader is
+// reachable and still in leader mode, its instances are stopped and its project is demoted to standby.
+func replicatorFailoverFence(ctx context.Context, s *state.State, p api.Project) {
+	l := logger.AddContext(logger.Ctx{"project": p.Name, "clusterLink": p.Config["replica.cluster"]})
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := replicatorFailoverConnect(connectCtx, s, p.Config["replica.cluster"], p.Name)
+	if err != nil {
+		// The old leader is still unreachable, nothing to fence.
+		return
+	}
+
+	defer client.Disconnect()
+
+	fenced, err := replicatorFailoverFencePeer(client, p.Name)
+	if err != nil {
+		l.Error("Failed fencing old leader", logger.Ctx{"err": err})
+		return
+	}
+
+	if fenced {
+		l.Warn("Fenced old leader of promoted project")
+		s.Events.SendLifecycle(p.Name, lifecycle.ProjectFenced.Event(p.Name, nil, map[string]any{"cluster_link": p.Config["replica.cluster"]}))
+	}
+}
+
+// replicatorFailoverFencePeer fences the project of the peer cluster if it's still in leader mode.
+// It returns whether the project was fenced.
+func replicatorFailoverFencePeer(client lxd.InstanceServer, projectName string) (bool, error) {
+	peerProject, _, err := client.GetProject(projectName)
+	if err != nil || peerProject.ReplicaMode != api.ReplicatorProjectModeLeader {
+		return false, nil
+	}
+
+	err = replicatorFailoverFenceProject(client, projectName)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// replicatorFailoverFenceProject stops the instances of the project and demotes it to standby.
+func replicatorFailoverFenceProject(client lxd.InstanceServer, projectName string) error {
+	op, err := client.UpdateInstances(api.InstancesPut{State: &api.InstanceStatePut{Action: "stop", Force: true}}, "")
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed stopping instances: %w", err)
+	}
+
+	op, err = client.UpdateProjectState(projectName, api.ProjectStatePut{ReplicaMode: api.ReplicatorProjectModeStandby}, true)
+	if err == nil {
+		err = op.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed demoting project: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// replicatorFailoverTestServer is a cluster holding a single project. Project updates are conditional on the ETag,
+// which changes on each update, and the calls changing the state of the project are recorded.
+type replicatorFailoverTestServer struct {
+	lxd.InstanceServer
+
+	project api.Project
+	etag    int
+	calls   []string
+}
+
+func (r *replicatorFailoverTestServer) GetProject(name string) (*api.Project, string, error) {
+	if name != r.project.Name {
+		return nil, "", api.NewStatusError(http.StatusNotFound, "Project not found")
+	}
+
+	p := r.project
+	p.Config = make(map[string]string, len(r.project.Config))
+	for k, v := range r.project.Config {
+		p.Config[k] = v
+	}
+
+	return &p, strconv.Itoa(r.etag), nil
+}
+
+func (r *replicatorFailoverTestServer) UpdateProject(name string, project api.ProjectPut, ETag string) error {
+	if ETag != strconv.Itoa(r.etag) {
+		return api.NewStatusError(http.StatusPreconditionFailed, "ETag doesn't match")
+	}
+
+	r.etag++
+	r.project.Config = project.Config
+	return nil
+}
+
+func (r *replicatorFailoverTestServer) UpdateInstances(state api.InstancesPut, ETag string) (lxd.Operation, error) {
+	r.calls = append(r.calls, "instances/"+state.State.Action)
+	return replicatorTestOperation{}, nil
+}
+
+func (r *replicatorFailoverTestServer) UpdateProjectState(name string, state api.ProjectStatePut, force bool) (lxd.Operation, error) {
+	r.calls = append(r.calls, "project/"+state.ReplicaMode)
+	r.project.ReplicaMode = state.ReplicaMode
+	return replicatorTestOperation{}, nil
+}
+
+func TestReplicatorFailoverPolicyFromConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]string
+		prefix string
+		want   *replicatorFailoverPolicy
+	}{
+		{
+			name:   "Manual failover",
+			config: map[string]string{"failover.mode": "manual", "failover.witness": "w"},
+		},
+		{
+			name:   "Replicator defaults",
+			config: map[string]string{"failover.mode": "automatic", "failover.witness": "w"},
+			want:   &replicatorFailoverPolicy{witness: "w", interval: 30 * time.Second, threshold: 3},
+		},
+		{
+			name: "Project keys",
+			config: map[string]string{
+				"replica.failover.mode":                   "automatic",
+				"replica.failover.witness":                "w",
+				"replica.failover.health_check.interval":  "10",
+				"replica.failover.health_check.threshold": "5",
+			},
+			prefix: "replica.",
+			want:   &replicatorFailoverPolicy{witness: "w", interval: 10 * time.Second, threshold: 5},
+		},
+		{
+			name:   "Replicator keys on a project",
+			config: map[string]string{"failover.mode": "automatic"},
+			prefix: "replica.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, replicatorFailoverPolicyFromConfig(tt.config, tt.prefix))
+		})
+	}
+
+	// The standby only takes over once the leader had time to fence itself.
+	policy := &replicatorFailoverPolicy{interval: 10 * time.Second, threshold: 1}
+	assert.Greater(t, policy.leaseTimeout(), policy.fenceAfter()+policy.interval)
+}
+
+func TestReplicatorFailoverState_HealthChecks(t *testing.T) {
+	st := &replicatorFailoverState{}
+
+	assert.False(t, st.healthCheckFailed(3))
+	assert.False(t, st.healthCheckFailed(3))
+	assert.True(t, st.healthCheckFailed(3))
+	assert.True(t, st.healthCheckFailed(3))
+	assert.Equal(t, 4, st.failures)
+
+	// The leader coming back resets the failures and the observed lease.
+	st.observeLease("leader/1", time.Now())
+	assert.True(t, st.healthCheckSucceeded())
+	assert.Equal(t, 0, st.failures)
+	assert.Empty(t, st.leaseValue)
+	assert.False(t, st.healthCheckSucceeded())
+}
+
+func TestReplicatorFailoverState_ObserveLease(t *testing.T) {
+	st := &replicatorFailoverState{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Duration(0), st.observeLease("leader/1", now))
+	assert.Equal(t, 30*time.Second, st.observeLease("leader/1", now.Add(30*time.Second)))
+
+	// A renewal by the leader restarts the timeout.
+	assert.Equal(t, time.Duration(0), st.observeLease("leader/2", now.Add(40*time.Second)))
+	assert.Equal(t, 20*time.Second, st.observeLease("leader/2", now.Add(60*time.Second)))
+
+	// A leader that never took a lease is treated the same way.
+	st = &replicatorFailoverState{}
+	assert.Equal(t, time.Duration(0), st.observeLease("", now))
+	assert.Equal(t, time.Minute, st.observeLease("", now.Add(time.Minute)))
+}
+
+func TestReplicatorFailoverLease(t *testing.T) {
+	witness := &replicatorFailoverTestServer{project: api.Project{Name: "p1"}}
+
+	// The leader takes the lease on its first renewal and renews it afterwards.
+	require.NoError(t, replicatorFailoverRenewLease(witness, "p1", "leader"))
+	require.NoError(t, replicatorFailoverRenewLease(witness, "p1", "leader"))
+	lease, err := replicatorFailoverGetLease(witness, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "leader/2", lease)
+
+	// The standby can't take over a lease that was renewed since it was observed.
+	require.NoError(t, replicatorFailoverRenewLease(witness, "p1", "leader"))
+	assert.Error(t, replicatorFailoverTakeLease(witness, "p1", "standby", lease))
+
+	lease, err = replicatorFailoverGetLease(witness, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "leader/3", lease)
+
+	// Once the standby has taken over, the old leader can't renew the lease anymore.
+	require.NoError(t, replicatorFailoverTakeLease(witness, "p1", "standby", lease))
+	assert.ErrorIs(t, replicatorFailoverRenewLease(witness, "p1", "leader"), errReplicatorFailoverLeaseLost)
+	require.NoError(t, replicatorFailoverRenewLease(witness, "p1", "standby"))
+
+	lease, err = replicatorFailoverGetLease(witness, "p1")
+	require.NoError(t, err)
+	assert.Equal(t, "standby/5", lease)
+
+	// A missing project on the witness is reported.
+	_, err = replicatorFailoverGetLease(witness, "p2")
+	assert.True(t, api.StatusErrorCheck(err, http.StatusNotFound))
+}
+
+func TestReplicatorFailoverFencePeer(t *testing.T) {
+	// An old leader that is still in leader mode is stopped, then demoted.
+	peer := &replicatorFailoverTestServer{project: api.Project{Name: "p1", ReplicaMode: api.ReplicatorProjectModeLeader}}
+
+	fenced, err := replicatorFailoverFencePeer(peer, "p1")
+	require.NoError(t, err)
+	assert.True(t, fenced)
+	assert.Equal(t, []string{"instances/stop", "project/" + api.ReplicatorProjectModeStandby}, peer.calls)
+	assert.Equal(t, api.ReplicatorProjectModeStandby, peer.project.ReplicaMode)
+
+	// An old leader that is already in standby mode is left alone.
+	fenced, err = replicatorFailoverFencePeer(peer, "p1")
+	require.NoError(t, err)
+	assert.False(t, fenced)
+	assert.Len(t, peer.calls, 2)
+}
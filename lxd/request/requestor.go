@@ -78,6 +78,12 @@ func (r *Requestor) IsClusterNotification() bool {
 	return r.ClientType().IsClusterNotification() && r.isInternal()
 }
 
+// IsClusterOperationNotification returns true if this an API request coming from a
+// cluster node as part of an operation started on that node.
+func (r *Requestor) IsClusterOperationNotification() bool {
+	return r.ClientType().IsClusterOperationNotification() && r.isInternal()
+}
+
 // IsTrusted returns true if the caller is authenticated and false otherwise.
 func (r *Requestor) IsTrusted() bool {
 	return r.isTrusted
@@ -0,0 +1,291 @@
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/version"
+)
+
+// Generate renders the inventory of a root filesystem as an SBOM document in the given format. The name
+// identifies the image or instance the root filesystem belongs to.
+func Generate(inventory *Inventory, format string, name string, created time.Time) ([]byte, error) {
+	var document any
+
+	switch format {
+	case api.SBOMFormatSPDX:
+		document = spdxDocument(inventory, name, created)
+	case api.SBOMFormatCycloneDX:
+		document = cycloneDXDocument(inventory, name, created)
+	default:
+		return nil, fmt.Errorf("Unsupported SBOM format %q", format)
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// PackageURL returns the package URL (purl) of a package of the given operating system.
+func PackageURL(pkg Package, release OSRelease) string {
+	namespace := release.ID
+	if namespace == "" {
+		namespace = "unknown"
+	}
+
+	qualifiers := []string{}
+	if pkg.Arch != "" {
+		qualifiers = append(qualifiers, "arch="+url.QueryEscape(pkg.Arch))
+	}
+
+	if release.ID != "" && release.VersionID != "" {
+		qualifiers = append(qualifiers, "distro="+url.QueryEscape(release.ID+"-"+release.VersionID))
+	}
+
+	if pkg.Epoch != "" && pkg.Epoch != "0" {
+		qualifiers = append(qualifiers, "epoch="+url.QueryEscape(pkg.Epoch))
+	}
+
+	purl := "pkg:" + pkg.Type + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(pkg.Name)
+	if pkg.Version != "" {
+		purl += "@" + url.QueryEscape(pkg.Version)
+	}
+
+	if len(qualifiers) > 0 {
+		purl += "?" + strings.Join(qualifiers, "&")
+	}
+
+	return purl
+}
+
+// osName returns the name of the operating system package of the SBOM.
+func osName(release OSRelease) string {
+	if release.ID != "" {
+		return release.ID
+	}
+
+	return "unknown"
+}
+
+type spdxDoc struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name                  string            `json:"name"`
+	SPDXID                string            `json:"SPDXID"`
+	VersionInfo           string            `json:"versionInfo,omitempty"`
+	Supplier              string            `json:"supplier"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	LicenseComments       string            `json:"licenseComments,omitempty"`
+	CopyrightText         string            `json:"copyrightText"`
+	SourceInfo            string            `json:"sourceInfo,omitempty"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// spdxDocument returns the inventory as an SPDX 2.3 document.
+func spdxDocument(inventory *Inventory, name string, created time.Time) spdxDoc {
+	const noAssertion = "NOASSERTION"
+
+	doc := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: "https://documentation.ubuntu.com/lxd/sbom/" + url.PathEscape(name) + "-" + uuid.New().String(),
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: lxd-" + version.Version},
+		},
+	}
+
+	osID := "SPDXRef-OperatingSystem"
+	doc.Packages = append(doc.Packages, spdxPackage{
+		Name:                  osName(inventory.OS),
+		SPDXID:                osID,
+		VersionInfo:           inventory.OS.VersionID,
+		Supplier:              noAssertion,
+		DownloadLocation:      noAssertion,
+		LicenseConcluded:      noAssertion,
+		LicenseDeclared:       noAssertion,
+		CopyrightText:         noAssertion,
+		PrimaryPackagePurpose: "OPERATING-SYSTEM",
+	})
+
+	doc.Relationships = append(doc.Relationships, spdxRelationship{
+		SPDXElementID:      doc.SPDXID,
+		RelationshipType:   "DESCRIBES",
+		RelatedSPDXElement: osID,
+	})
+
+	for i, pkg := range inventory.Packages {
+		pkgID := "SPDXRef-Package-" + pkg.Type + "-" + strconv.Itoa(i+1)
+
+		spdxPkg := spdxPackage{
+			Name:                  pkg.Name,
+			SPDXID:                pkgID,
+			VersionInfo:           pkg.Version,
+			Supplier:              noAssertion,
+			DownloadLocation:      noAssertion,
+			LicenseConcluded:      noAssertion,
+			LicenseDeclared:       noAssertion,
+			CopyrightText:         noAssertion,
+			PrimaryPackagePurpose: "LIBRARY",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  PackageURL(pkg, inventory.OS),
+			}},
+		}
+
+		// Licenses declared by package managers aren't always valid SPDX license expressions.
+		if pkg.License != "" {
+			spdxPkg.LicenseComments = "Declared license: " + pkg.License
+		}
+
+		if pkg.Source != "" {
+			spdxPkg.SourceInfo = "Built from source package " + pkg.Source
+		}
+
+		doc.Packages = append(doc.Packages, spdxPkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      osID,
+			RelationshipType:   "CONTAINS",
+			RelatedSPDXElement: pkgID,
+		})
+	}
+
+	return doc
+}
+
+type cycloneDXDoc struct {
+	BOMFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber"`
+	Version      int                   `json:"version"`
+	Metadata     cycloneDXMetadata     `json:"metadata"`
+	Components   []cycloneDXComponent  `json:"components"`
+	Dependencies []cycloneDXDependency `json:"dependencies"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	BOMRef   string             `json:"bom-ref,omitempty"`
+	Type     string             `json:"type"`
+	Name     string             `json:"name"`
+	Version  string             `json:"version,omitempty"`
+	PURL     string             `json:"purl,omitempty"`
+	Licenses []cycloneDXLicense `json:"licenses,omitempty"`
+}
+
+type cycloneDXLicense struct {
+	License cycloneDXLicenseName `json:"license"`
+}
+
+type cycloneDXLicenseName struct {
+	Name string `json:"name"`
+}
+
+type cycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// cycloneDXDocument returns the inventory as a CycloneDX 1.5 document.
+func cycloneDXDocument(inventory *Inventory, name string, created time.Time) cycloneDXDoc {
+	doc := cycloneDXDoc{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + uuid.New().String(),
+		Version:      1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools: cycloneDXTools{
+				Components: []cycloneDXComponent{{Type: "application", Name: "lxd", Version: version.Version}},
+			},
+			Component: cycloneDXComponent{
+				BOMRef: "root",
+				Type:   "container",
+				Name:   name,
+			},
+		},
+	}
+
+	osRef := "operating-system"
+	doc.Components = append(doc.Components, cycloneDXComponent{
+		BOMRef:  osRef,
+		Type:    "operating-system",
+		Name:    osName(inventory.OS),
+		Version: inventory.OS.VersionID,
+	})
+
+	osDependency := cycloneDXDependency{Ref: osRef, DependsOn: []string{}}
+
+	for _, pkg := range inventory.Packages {
+		purl := PackageURL(pkg, inventory.OS)
+
+		component := cycloneDXComponent{
+			BOMRef:  purl,
+			Type:    "library",
+			Name:    pkg.Name,
+			Version: pkg.Version,
+			PURL:    purl,
+		}
+
+		if pkg.License != "" {
+			component.Licenses = []cycloneDXLicense{{License: cycloneDXLicenseName{Name: pkg.License}}}
+		}
+
+		doc.Components = append(doc.Components, component)
+		osDependency.DependsOn = append(osDependency.DependsOn, purl)
+	}
+
+	doc.Dependencies = []cycloneDXDependency{
+		{Ref: "root", DependsOn: []string{osRef}},
+		osDependency,
+	}
+
+	return doc
+}
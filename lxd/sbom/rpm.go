@@ -0,0 +1,193 @@
+package sbom
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"strconv"
+
+	_ "github.com/mattn/go-sqlite3" // Used to read rpm SQLite databases.
+)
+
+// RPM header tags.
+const (
+	rpmTagName      = 1000
+	rpmTagVersion   = 1001
+	rpmTagRelease   = 1002
+	rpmTagEpoch     = 1003
+	rpmTagLicense   = 1014
+	rpmTagArch      = 1022
+	rpmTagSourceRPM = 1044
+)
+
+// RPM header data types.
+const (
+	rpmTypeInt32       = 4
+	rpmTypeString      = 6
+	rpmTypeStringArray = 8
+	rpmTypeI18NString  = 9
+)
+
+// scanRPM returns the packages of the rpm database of a root filesystem. Only the SQLite database format, used
+// since rpm 4.16, is supported.
+func scanRPM(open FileOpener) ([]Package, error) {
+	for _, path := range []string{pathRPMSQLite, pathRPMSQLiteUsr} {
+		var packages []Package
+		found, err := readFile(open, path, func(r io.Reader) error {
+			var err error
+			packages, err = readRPMSQLite(r)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			return packages, nil
+		}
+	}
+
+	for _, path := range []string{pathRPMBerkeleyDB, pathRPMBerkeleyDBDb} {
+		f, err := open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("Failed opening %q: %w", path, err)
+		}
+
+		_ = f.Close()
+		return nil, fmt.Errorf("Unsupported rpm database %q, only the SQLite format is supported", path)
+	}
+
+	return nil, nil
+}
+
+// readRPMSQLite returns the packages of an rpm SQLite database.
+func readRPMSQLite(r io.Reader) ([]Package, error) {
+	// SQLite can only open databases from files.
+	f, err := os.CreateTemp("", "lxd_sbom_rpmdb_")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	_, err = io.Copy(f, r)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	err = f.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+url.PathEscape(f.Name())+"?mode=ro&immutable=1")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.Query("SELECT blob FROM Packages")
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	packages := []Package{}
+	for rows.Next() {
+		var blob []byte
+		err = rows.Scan(&blob)
+		if err != nil {
+			return nil, err
+		}
+
+		pkg, err := parseRPMHeader(blob)
+		if err != nil {
+			return nil, err
+		}
+
+		// Public keys imported in the database are stored as pseudo packages.
+		if pkg.Name == "gpg-pubkey" {
+			continue
+		}
+
+		packages = append(packages, *pkg)
+	}
+
+	return packages, rows.Err()
+}
+
+// parseRPMHeader returns the package described by an rpm header blob, as stored in the rpm database. The blob
+// starts with the number of index entries and the size of the data store, followed by the index entries and the
+// data store.
+func parseRPMHeader(blob []byte) (*Package, error) {
+	if len(blob) < 8 {
+		return nil, errors.New("Invalid rpm header: Too short")
+	}
+
+	indexCount := int(binary.BigEndian.Uint32(blob[0:4]))
+	dataLength := int(binary.BigEndian.Uint32(blob[4:8]))
+
+	dataStart := 8 + indexCount*16
+	if indexCount < 0 || dataLength < 0 || dataStart+dataLength > len(blob) {
+		return nil, errors.New("Invalid rpm header: Truncated")
+	}
+
+	data := blob[dataStart : dataStart+dataLength]
+
+	values := map[uint32]string{}
+	for i := range indexCount {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		dataType := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(binary.BigEndian.Uint32(entry[8:12]))
+
+		if offset < 0 || offset >= len(data) {
+			continue
+		}
+
+		switch dataType {
+		case rpmTypeString, rpmTypeStringArray, rpmTypeI18NString:
+			// Only keep the first string of arrays.
+			end := offset
+			for end < len(data) && data[end] != 0 {
+				end++
+			}
+
+			values[tag] = string(data[offset:end])
+		case rpmTypeInt32:
+			if offset+4 <= len(data) {
+				values[tag] = strconv.FormatUint(uint64(binary.BigEndian.Uint32(data[offset:offset+4])), 10)
+			}
+		}
+	}
+
+	if values[rpmTagName] == "" {
+		return nil, errors.New("Invalid rpm header: Missing package name")
+	}
+
+	pkg := &Package{
+		Type:    PackageTypeRPM,
+		Name:    values[rpmTagName],
+		Version: values[rpmTagVersion],
+		Arch:    values[rpmTagArch],
+		Epoch:   values[rpmTagEpoch],
+		Source:  values[rpmTagSourceRPM],
+		License: values[rpmTagLicense],
+	}
+
+	if values[rpmTagRelease] != "" {
+		pkg.Version += "-" + values[rpmTagRelease]
+	}
+
+	return pkg, nil
+}
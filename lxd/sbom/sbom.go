@@ -0,0 +1,265 @@
+// Package sbom builds the software bill of materials of a root filesystem from the databases of its package
+// managers, without running anything inside of it.
+package sbom
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+// Package types, as used in package URLs.
+const (
+	PackageTypeDeb = "deb"
+	PackageTypeRPM = "rpm"
+	PackageTypeAPK = "apk"
+)
+
+// Package is a software package installed in a root filesystem.
+type Package struct {
+	Type    string
+	Name    string
+	Version string
+	Arch    string
+
+	// Epoch is the epoch of RPM packages, it isn't part of the version.
+	Epoch string
+
+	// Source is the name of the source package the package was built from.
+	Source string
+
+	// License is the license declared by the package, in the format used by its package manager.
+	License string
+}
+
+// OSRelease is the operating system of a root filesystem, as described in its os-release file.
+type OSRelease struct {
+	ID         string
+	VersionID  string
+	Name       string
+	PrettyName string
+}
+
+// Inventory is the list of the packages installed in a root filesystem.
+type Inventory struct {
+	OS       OSRelease
+	Packages []Package
+}
+
+// Package database paths, relative to the root filesystem.
+const (
+	pathOSRelease       = "etc/os-release"
+	pathOSReleaseUsr    = "usr/lib/os-release"
+	pathDpkgStatus      = "var/lib/dpkg/status"
+	pathAPKInstalled    = "lib/apk/db/installed"
+	pathRPMSQLite       = "var/lib/rpm/rpmdb.sqlite"
+	pathRPMSQLiteUsr    = "usr/lib/sysimage/rpm/rpmdb.sqlite"
+	pathRPMBerkeleyDB   = "var/lib/rpm/Packages"
+	pathRPMBerkeleyDBDb = "var/lib/rpm/Packages.db"
+)
+
+// Paths are the paths of the files read by Scan, relative to the root filesystem. Only those files need to be
+// extracted from an archive of the root filesystem to build its inventory.
+var Paths = []string{
+	pathOSRelease,
+	pathOSReleaseUsr,
+	pathDpkgStatus,
+	pathAPKInstalled,
+	pathRPMSQLite,
+	pathRPMSQLiteUsr,
+	pathRPMBerkeleyDB,
+	pathRPMBerkeleyDBDb,
+}
+
+// FileOpener opens a file of a root filesystem given its path relative to the root. Missing files are reported
+// with an error matching fs.ErrNotExist.
+type FileOpener func(path string) (io.ReadCloser, error)
+
+// Scan builds the inventory of a root filesystem from its os-release file and the databases of the dpkg, apk and
+// rpm package managers.
+func Scan(open FileOpener) (*Inventory, error) {
+	inventory := &Inventory{}
+
+	for _, path := range []string{pathOSRelease, pathOSReleaseUsr} {
+		found, err := readFile(open, path, func(r io.Reader) error {
+			release, err := parseOSRelease(r)
+			inventory.OS = release
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			break
+		}
+	}
+
+	parsers := []struct {
+		path  string
+		parse func(r io.Reader) ([]Package, error)
+	}{
+		{pathDpkgStatus, parseDpkgStatus},
+		{pathAPKInstalled, parseAPKInstalled},
+	}
+
+	for _, parser := range parsers {
+		_, err := readFile(open, parser.path, func(r io.Reader) error {
+			packages, err := parser.parse(r)
+			if err != nil {
+				return err
+			}
+
+			inventory.Packages = append(inventory.Packages, packages...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	packages, err := scanRPM(open)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory.Packages = append(inventory.Packages, packages...)
+
+	slices.SortFunc(inventory.Packages, func(a Package, b Package) int {
+		return strings.Compare(a.Type+"/"+a.Name+"/"+a.Arch, b.Type+"/"+b.Name+"/"+b.Arch)
+	})
+
+	return inventory, nil
+}
+
+// readFile calls read with the content of the file at path, and returns whether the file exists.
+func readFile(open FileOpener, path string, read func(r io.Reader) error) (bool, error) {
+	f, err := open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("Failed opening %q: %w", path, err)
+	}
+
+	defer func() { _ = f.Close() }()
+
+	err = read(f)
+	if err != nil {
+		return true, fmt.Errorf("Failed reading %q: %w", path, err)
+	}
+
+	return true, nil
+}
+
+// parseOSRelease parses an os-release file.
+func parseOSRelease(r io.Reader) (OSRelease, error) {
+	release := OSRelease{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+
+		value = strings.Trim(value, `'"`)
+
+		switch key {
+		case "ID":
+			release.ID = value
+		case "VERSION_ID":
+			release.VersionID = value
+		case "NAME":
+			release.Name = value
+		case "PRETTY_NAME":
+			release.PrettyName = value
+		}
+	}
+
+	return release, scanner.Err()
+}
+
+// controlParagraphs calls fn with the fields of each paragraph of a Debian control style file, where paragraphs
+// are separated by empty lines, fields are in the "key: value" form and continuation lines start with a space.
+func controlParagraphs(r io.Reader, fn func(fields map[string]string)) error {
+	fields := map[string]string{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(fields) > 0 {
+				fn(fields)
+				fields = map[string]string{}
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if found {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+
+	if len(fields) > 0 {
+		fn(fields)
+	}
+
+	return scanner.Err()
+}
+
+// parseDpkgStatus returns the installed packages listed in a dpkg status file.
+func parseDpkgStatus(r io.Reader) ([]Package, error) {
+	packages := []Package{}
+
+	err := controlParagraphs(r, func(fields map[string]string) {
+		if fields["Package"] == "" || !strings.HasSuffix(fields["Status"], " installed") {
+			return
+		}
+
+		// The source may include the version the package was built from, like "glibc (2.39-0ubuntu8)".
+		source, _, _ := strings.Cut(fields["Source"], " ")
+
+		packages = append(packages, Package{
+			Type:    PackageTypeDeb,
+			Name:    fields["Package"],
+			Version: fields["Version"],
+			Arch:    fields["Architecture"],
+			Source:  source,
+		})
+	})
+
+	return packages, err
+}
+
+// parseAPKInstalled returns the packages listed in an apk installed database.
+func parseAPKInstalled(r io.Reader) ([]Package, error) {
+	packages := []Package{}
+
+	err := controlParagraphs(r, func(fields map[string]string) {
+		if fields["P"] == "" {
+			return
+		}
+
+		packages = append(packages, Package{
+			Type:    PackageTypeAPK,
+			Name:    fields["P"],
+			Version: fields["V"],
+			Arch:    fields["A"],
+			Source:  fields["o"],
+			License: fields["L"],
+		})
+	})
+
+	return packages, err
+}
@@ -0,0 +1,219 @@
+package sbom
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+const testDpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Source: glibc (2.39-0ubuntu8)
+Version: 2.39-0ubuntu8.3
+Description: GNU C Library: Shared libraries
+ Contains the standard libraries that are used by nearly all programs on
+ the system.
+
+Package: removed
+Status: deinstall ok config-files
+Architecture: amd64
+Version: 1.0-1
+
+Package: bash
+Status: install ok installed
+Architecture: amd64
+Version: 5.2.21-2ubuntu4
+`
+
+const testAPKInstalled = `C:Q1abc=
+P:musl
+V:1.2.5-r0
+A:x86_64
+o:musl
+L:MIT
+
+C:Q1def=
+P:busybox
+V:1.36.1-r29
+A:x86_64
+o:busybox
+L:GPL-2.0-only
+`
+
+func testOpener(files fstest.MapFS) FileOpener {
+	return func(path string) (io.ReadCloser, error) {
+		return files.Open(path)
+	}
+}
+
+func TestScan(t *testing.T) {
+	files := fstest.MapFS{
+		"usr/lib/os-release":   {Data: []byte("NAME=\"Ubuntu\"\nID=ubuntu\nVERSION_ID=\"24.04\"\n# Comment\nPRETTY_NAME='Ubuntu 24.04 LTS'\n")},
+		"var/lib/dpkg/status":  {Data: []byte(testDpkgStatus)},
+		"lib/apk/db/installed": {Data: []byte(testAPKInstalled)},
+	}
+
+	inventory, err := Scan(testOpener(files))
+	require.NoError(t, err)
+
+	assert.Equal(t, OSRelease{ID: "ubuntu", VersionID: "24.04", Name: "Ubuntu", PrettyName: "Ubuntu 24.04 LTS"}, inventory.OS)
+	assert.Equal(t, []Package{
+		{Type: PackageTypeAPK, Name: "busybox", Version: "1.36.1-r29", Arch: "x86_64", Source: "busybox", License: "GPL-2.0-only"},
+		{Type: PackageTypeAPK, Name: "musl", Version: "1.2.5-r0", Arch: "x86_64", Source: "musl", License: "MIT"},
+		{Type: PackageTypeDeb, Name: "bash", Version: "5.2.21-2ubuntu4", Arch: "amd64"},
+		{Type: PackageTypeDeb, Name: "libc6", Version: "2.39-0ubuntu8.3", Arch: "amd64", Source: "glibc"},
+	}, inventory.Packages)
+}
+
+func TestScanBerkeleyDB(t *testing.T) {
+	files := fstest.MapFS{
+		"var/lib/rpm/Packages": {Data: []byte("BerkeleyDB")},
+	}
+
+	_, err := Scan(testOpener(files))
+	assert.ErrorContains(t, err, "only the SQLite format is supported")
+}
+
+// rpmHeader builds an rpm header blob from string and int32 tags.
+func rpmHeader(strings map[uint32]string, ints map[uint32]uint32) []byte {
+	index := []byte{}
+	data := []byte{}
+
+	addEntry := func(tag uint32, dataType uint32, value []byte) {
+		entry := make([]byte, 16)
+		binary.BigEndian.PutUint32(entry[0:4], tag)
+		binary.BigEndian.PutUint32(entry[4:8], dataType)
+		binary.BigEndian.PutUint32(entry[8:12], uint32(len(data)))
+		binary.BigEndian.PutUint32(entry[12:16], 1)
+		index = append(index, entry...)
+		data = append(data, value...)
+	}
+
+	for tag, value := range strings {
+		addEntry(tag, rpmTypeString, append([]byte(value), 0))
+	}
+
+	for tag, value := range ints {
+		addEntry(tag, rpmTypeInt32, binary.BigEndian.AppendUint32(nil, value))
+	}
+
+	blob := binary.BigEndian.AppendUint32(nil, uint32(len(index)/16))
+	blob = binary.BigEndian.AppendUint32(blob, uint32(len(data)))
+	blob = append(blob, index...)
+	return append(blob, data...)
+}
+
+func TestParseRPMHeader(t *testing.T) {
+	blob := rpmHeader(map[uint32]string{
+		rpmTagName:      "bash",
+		rpmTagVersion:   "5.2.26",
+		rpmTagRelease:   "3.fc40",
+		rpmTagArch:      "x86_64",
+		rpmTagLicense:   "GPL-3.0-or-later",
+		rpmTagSourceRPM: "bash-5.2.26-3.fc40.src.rpm",
+	}, map[uint32]uint32{
+		rpmTagEpoch: 1,
+	})
+
+	pkg, err := parseRPMHeader(blob)
+	require.NoError(t, err)
+	assert.Equal(t, &Package{
+		Type:    PackageTypeRPM,
+		Name:    "bash",
+		Version: "5.2.26-3.fc40",
+		Arch:    "x86_64",
+		Epoch:   "1",
+		Source:  "bash-5.2.26-3.fc40.src.rpm",
+		License: "GPL-3.0-or-later",
+	}, pkg)
+
+	_, err = parseRPMHeader(blob[:20])
+	assert.Error(t, err)
+
+	_, err = parseRPMHeader(rpmHeader(map[uint32]string{rpmTagVersion: "1.0"}, nil))
+	assert.Error(t, err)
+}
+
+func TestPackageURL(t *testing.T) {
+	release := OSRelease{ID: "fedora", VersionID: "40"}
+
+	tests := []struct {
+		pkg  Package
+		want string
+	}{
+		{
+			pkg:  Package{Type: PackageTypeRPM, Name: "bash", Version: "5.2.26-3.fc40", Arch: "x86_64", Epoch: "1"},
+			want: "pkg:rpm/fedora/bash@5.2.26-3.fc40?arch=x86_64&distro=fedora-40&epoch=1",
+		},
+		{
+			pkg:  Package{Type: PackageTypeRPM, Name: "libstdc++", Version: "14.1.1-7.fc40", Epoch: "0"},
+			want: "pkg:rpm/fedora/libstdc++@14.1.1-7.fc40?distro=fedora-40",
+		},
+		{
+			pkg:  Package{Type: PackageTypeRPM, Name: "tzdata"},
+			want: "pkg:rpm/fedora/tzdata?distro=fedora-40",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pkg.Name, func(t *testing.T) {
+			assert.Equal(t, test.want, PackageURL(test.pkg, release))
+		})
+	}
+
+	assert.Equal(t, "pkg:deb/unknown/bash", PackageURL(Package{Type: PackageTypeDeb, Name: "bash"}, OSRelease{}))
+}
+
+func TestGenerate(t *testing.T) {
+	inventory := &Inventory{
+		OS: OSRelease{ID: "alpine", VersionID: "3.20.0"},
+		Packages: []Package{
+			{Type: PackageTypeAPK, Name: "musl", Version: "1.2.5-r0", Arch: "x86_64", License: "MIT"},
+		},
+	}
+
+	created := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("spdx", func(t *testing.T) {
+		content, err := Generate(inventory, api.SBOMFormatSPDX, "alpine-image", created)
+		require.NoError(t, err)
+
+		var doc spdxDoc
+		require.NoError(t, json.Unmarshal(content, &doc))
+		assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+		assert.Equal(t, "2024-06-01T12:00:00Z", doc.CreationInfo.Created)
+		require.Len(t, doc.Packages, 2)
+		assert.Equal(t, "OPERATING-SYSTEM", doc.Packages[0].PrimaryPackagePurpose)
+		assert.Equal(t, "musl", doc.Packages[1].Name)
+		assert.Equal(t, "pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64&distro=alpine-3.20.0", doc.Packages[1].ExternalRefs[0].ReferenceLocator)
+		assert.Len(t, doc.Relationships, 2)
+	})
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		content, err := Generate(inventory, api.SBOMFormatCycloneDX, "alpine-image", created)
+		require.NoError(t, err)
+
+		var doc cycloneDXDoc
+		require.NoError(t, json.Unmarshal(content, &doc))
+		assert.Equal(t, "CycloneDX", doc.BOMFormat)
+		assert.Equal(t, "alpine-image", doc.Metadata.Component.Name)
+		require.Len(t, doc.Components, 2)
+		assert.Equal(t, "operating-system", doc.Components[0].Type)
+		assert.Equal(t, "MIT", doc.Components[1].Licenses[0].License.Name)
+		assert.Equal(t, []string{doc.Components[1].PURL}, doc.Dependencies[1].DependsOn)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := Generate(inventory, "swid", "alpine-image", created)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,192 @@
+package scim
+
+// Attribute is a SCIM schema attribute definition.
+type Attribute struct {
+	Name          string      `json:"name"`
+	Type          string      `json:"type"`
+	MultiValued   bool        `json:"multiValued"`
+	Required      bool        `json:"required"`
+	CaseExact     bool        `json:"caseExact"`
+	Mutability    string      `json:"mutability"`
+	Returned      string      `json:"returned"`
+	Uniqueness    string      `json:"uniqueness"`
+	SubAttributes []Attribute `json:"subAttributes,omitempty"`
+}
+
+// Schema is a SCIM schema definition.
+type Schema struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Attributes  []Attribute `json:"attributes"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+// ResourceType is a SCIM resource type definition.
+type ResourceType struct {
+	Schemas          []string          `json:"schemas"`
+	ID               string            `json:"id"`
+	Name             string            `json:"name"`
+	Endpoint         string            `json:"endpoint"`
+	Description      string            `json:"description"`
+	Schema           string            `json:"schema"`
+	SchemaExtensions []SchemaExtension `json:"schemaExtensions,omitempty"`
+	Meta             *Meta             `json:"meta,omitempty"`
+}
+
+// SchemaExtension references a schema extension of a [ResourceType].
+type SchemaExtension struct {
+	Schema   string `json:"schema"`
+	Required bool   `json:"required"`
+}
+
+// Supported indicates whether a feature is supported in the [ServiceProviderConfig].
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+// BulkSupport describes support for bulk operations in the [ServiceProviderConfig].
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// FilterSupport describes support for filtering in the [ServiceProviderConfig].
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// AuthenticationScheme describes a supported authentication scheme in the [ServiceProviderConfig].
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ServiceProviderConfig describes the SCIM features supported by the server.
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkSupport            `json:"bulk"`
+	Filter                FilterSupport          `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  *Meta                  `json:"meta,omitempty"`
+}
+
+// NewServiceProviderConfig returns the [ServiceProviderConfig] of the LXD SCIM server.
+func NewServiceProviderConfig() ServiceProviderConfig {
+	return ServiceProviderConfig{
+		Schemas: []string{SchemaServiceProviderConfig},
+		Patch:   Supported{Supported: true},
+		Filter:  FilterSupport{Supported: true, MaxResults: 1000},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "OAuth Bearer Token",
+			Description: "Authentication with a token issued for a SCIM token bearer identity",
+		}},
+		Meta: &Meta{ResourceType: "ServiceProviderConfig"},
+	}
+}
+
+// NewResourceTypes returns the resource types served by the LXD SCIM server.
+func NewResourceTypes() []ResourceType {
+	return []ResourceType{
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceTypeUser,
+			Name:        ResourceTypeUser,
+			Endpoint:    "/Users",
+			Description: "OIDC identity",
+			Schema:      SchemaUser,
+			Meta:        &Meta{ResourceType: "ResourceType"},
+		},
+		{
+			Schemas:          []string{SchemaResourceType},
+			ID:               ResourceTypeGroup,
+			Name:             ResourceTypeGroup,
+			Endpoint:         "/Groups",
+			Description:      "Identity provider group",
+			Schema:           SchemaGroup,
+			SchemaExtensions: []SchemaExtension{{Schema: SchemaLXDGroup, Required: false}},
+			Meta:             &Meta{ResourceType: "ResourceType"},
+		},
+	}
+}
+
+// attribute returns a single-valued, optional, read-write [Attribute] of the given type.
+func attribute(name string, attributeType string, subAttributes ...Attribute) Attribute {
+	return Attribute{
+		Name:          name,
+		Type:          attributeType,
+		Mutability:    "readWrite",
+		Returned:      "default",
+		Uniqueness:    "none",
+		SubAttributes: subAttributes,
+	}
+}
+
+// NewSchemas returns the schemas supported by the LXD SCIM server.
+func NewSchemas() []Schema {
+	userName := attribute("userName", "string")
+	userName.Required = true
+	userName.Uniqueness = "server"
+
+	emails := attribute("emails", "complex", attribute("value", "string"), attribute("type", "string"), attribute("primary", "boolean"))
+	emails.MultiValued = true
+
+	userGroups := attribute("groups", "complex", attribute("value", "string"), attribute("display", "string"), attribute("$ref", "reference"))
+	userGroups.MultiValued = true
+	userGroups.Mutability = "readOnly"
+
+	displayName := attribute("displayName", "string")
+	displayName.Required = true
+	displayName.Uniqueness = "server"
+
+	members := attribute("members", "complex", attribute("value", "string"), attribute("display", "string"), attribute("$ref", "reference"))
+	members.MultiValued = true
+
+	authGroups := attribute("authGroups", "string")
+	authGroups.MultiValued = true
+	authGroups.CaseExact = true
+
+	return []Schema{
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaUser,
+			Name:        ResourceTypeUser,
+			Description: "OIDC identity. The user name is the email address of the identity",
+			Attributes: []Attribute{
+				userName,
+				attribute("externalId", "string"),
+				attribute("name", "complex", attribute("formatted", "string"), attribute("givenName", "string"), attribute("familyName", "string")),
+				attribute("displayName", "string"),
+				emails,
+				attribute("active", "boolean"),
+				userGroups,
+			},
+			Meta: &Meta{ResourceType: "Schema"},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaGroup,
+			Name:        ResourceTypeGroup,
+			Description: "Identity provider group",
+			Attributes:  []Attribute{displayName, members},
+			Meta:        &Meta{ResourceType: "Schema"},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaLXDGroup,
+			Name:        "LXDGroup",
+			Description: "LXD extension of the group schema",
+			Attributes:  []Attribute{authGroups},
+			Meta:        &Meta{ResourceType: "Schema"},
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Filter is a parsed SCIM filter. Only equality filters of the form `attribute eq "value"` are supported, which is
+// what identity providers use to look up existing resources before provisioning them.
+type Filter struct {
+	// Attribute is the filtered attribute name in lower case.
+	Attribute string
+
+	// Value is the value the attribute must be equal to.
+	Value string
+}
+
+// ParseFilter parses the given filter expression. An empty expression returns a nil [Filter].
+func ParseFilter(expression string) (*Filter, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, nil
+	}
+
+	attribute, rest, ok := strings.Cut(expression, " ")
+	if !ok {
+		return nil, NewError(http.StatusBadRequest, ErrorTypeInvalidFilter, "Invalid filter %q", expression)
+	}
+
+	operator, value, ok := strings.Cut(strings.TrimSpace(rest), " ")
+	if !ok || !strings.EqualFold(operator, "eq") {
+		return nil, NewError(http.StatusBadRequest, ErrorTypeInvalidFilter, "Unsupported filter %q: Only the \"eq\" operator is supported", expression)
+	}
+
+	value, err := strconv.Unquote(strings.TrimSpace(value))
+	if err != nil {
+		return nil, NewError(http.StatusBadRequest, ErrorTypeInvalidFilter, "Invalid filter %q: Value must be a quoted string", expression)
+	}
+
+	return &Filter{Attribute: strings.ToLower(attribute), Value: value}, nil
+}
+
+// Matches returns true if the filter is nil or if the given attribute values contain the filtered attribute with the
+// filtered value. Attribute names in the map must be lower case. Values of the "username" attribute are compared
+// case-insensitively, as the attribute is case-insensitive in the user schema.
+func (f *Filter) Matches(attributes map[string]string) bool {
+	if f == nil {
+		return true
+	}
+
+	value, ok := attributes[f.Attribute]
+	if !ok {
+		return false
+	}
+
+	if f.Attribute == "username" {
+		return strings.EqualFold(value, f.Value)
+	}
+
+	return value == f.Value
+}
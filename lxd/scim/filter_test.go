@@ -0,0 +1,93 @@
+package scim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       *Filter
+		wantErr    bool
+	}{
+		{
+			name:       "Empty",
+			expression: "",
+			want:       nil,
+		},
+		{
+			name:       "Equality",
+			expression: `userName eq "jane@example.com"`,
+			want:       &Filter{Attribute: "username", Value: "jane@example.com"},
+		},
+		{
+			name:       "Upper case operator and extra spaces",
+			expression: ` displayName  EQ "Engineering" `,
+			want:       &Filter{Attribute: "displayname", Value: "Engineering"},
+		},
+		{
+			name:       "Escaped quotes",
+			expression: `externalId eq "a\"b"`,
+			want:       &Filter{Attribute: "externalid", Value: `a"b`},
+		},
+		{
+			name:       "Unsupported operator",
+			expression: `userName sw "jane"`,
+			wantErr:    true,
+		},
+		{
+			name:       "Unquoted value",
+			expression: `userName eq jane`,
+			wantErr:    true,
+		},
+		{
+			name:       "Missing operator",
+			expression: `userName`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.expression)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	attributes := map[string]string{"username": "Jane@example.com", "externalid": "abc"}
+
+	assert.True(t, (*Filter)(nil).Matches(attributes))
+	assert.True(t, (&Filter{Attribute: "username", Value: "jane@example.com"}).Matches(attributes))
+	assert.True(t, (&Filter{Attribute: "externalid", Value: "abc"}).Matches(attributes))
+	assert.False(t, (&Filter{Attribute: "externalid", Value: "ABC"}).Matches(attributes))
+	assert.False(t, (&Filter{Attribute: "displayname", Value: "Jane"}).Matches(attributes))
+}
+
+func TestNewListResponse(t *testing.T) {
+	resources := []string{"a", "b", "c"}
+
+	res := NewListResponse(resources, 1, -1)
+	assert.Equal(t, 3, res.TotalResults)
+	assert.Equal(t, []any{"a", "b", "c"}, res.Resources)
+
+	res = NewListResponse(resources, 2, 1)
+	assert.Equal(t, 3, res.TotalResults)
+	assert.Equal(t, 2, res.StartIndex)
+	assert.Equal(t, 1, res.ItemsPerPage)
+	assert.Equal(t, []any{"b"}, res.Resources)
+
+	res = NewListResponse(resources, 5, 10)
+	assert.Equal(t, []any{}, res.Resources)
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Patch operation names. These are matched case-insensitively as some identity providers capitalize them.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+)
+
+// patchFunc applies a single operation with a lower case operation name to an attribute path.
+type patchFunc func(op string, path string, value json.RawMessage) error
+
+// ApplyUserPatch applies the given patch operations to the user.
+// Attributes that are not part of the [User] type are ignored, as LXD does not store them.
+func ApplyUserPatch(user *User, operations []PatchOperation) error {
+	return applyPatch(operations, func(op string, path string, value json.RawMessage) error {
+		return patchUser(user, op, path, value)
+	})
+}
+
+// ApplyGroupPatch applies the given patch operations to the group.
+func ApplyGroupPatch(group *Group, operations []PatchOperation) error {
+	return applyPatch(operations, func(op string, path string, value json.RawMessage) error {
+		return patchGroup(group, op, path, value)
+	})
+}
+
+// applyPatch validates each operation and calls apply for each targeted attribute. Operations without a path must
+// have an object value, whose keys are the attribute paths.
+func applyPatch(operations []PatchOperation, apply patchFunc) error {
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if !slices.Contains([]string{PatchOpAdd, PatchOpRemove, PatchOpReplace}, op) {
+			return NewError(http.StatusBadRequest, ErrorTypeInvalidSyntax, "Invalid patch operation %q", operation.Op)
+		}
+
+		if operation.Path != "" {
+			err := apply(op, operation.Path, operation.Value)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ErrorTypeNoTarget, "Remove operations require a path")
+		}
+
+		values := map[string]json.RawMessage{}
+		err := json.Unmarshal(operation.Value, &values)
+		if err != nil {
+			return NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "Patch operations without a path require an object value")
+		}
+
+		for path, value := range values {
+			err := apply(op, path, value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func patchUser(user *User, op string, path string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(path) {
+	case "active":
+		if op == PatchOpRemove {
+			user.Active = nil
+			return nil
+		}
+
+		active, err := unmarshalBool(value)
+		if err != nil {
+			return err
+		}
+
+		user.Active = &active
+	case "username":
+		if op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ErrorTypeMutability, "The userName attribute is required")
+		}
+
+		user.UserName, err = unmarshalString(value)
+	case "displayname":
+		user.DisplayName, err = unmarshalOptionalString(op, value)
+	case "externalid":
+		user.ExternalID, err = unmarshalOptionalString(op, value)
+	case "name":
+		user.Name = nil
+		if op != PatchOpRemove {
+			err = unmarshal(value, &user.Name)
+		}
+
+	case "name.formatted", "name.givenname", "name.familyname":
+		if user.Name == nil {
+			user.Name = &Name{}
+		}
+
+		var s string
+		s, err = unmarshalOptionalString(op, value)
+		switch strings.ToLower(path) {
+		case "name.formatted":
+			user.Name.Formatted = s
+		case "name.givenname":
+			user.Name.GivenName = s
+		case "name.familyname":
+			user.Name.FamilyName = s
+		}
+	}
+
+	return err
+}
+
+func patchGroup(group *Group, op string, path string, value json.RawMessage) error {
+	lowerPath := strings.ToLower(path)
+	switch {
+	case lowerPath == "displayname":
+		if op == PatchOpRemove {
+			return NewError(http.StatusBadRequest, ErrorTypeMutability, "The displayName attribute is required")
+		}
+
+		displayName, err := unmarshalString(value)
+		if err != nil {
+			return err
+		}
+
+		group.DisplayName = displayName
+	case lowerPath == "externalid":
+		// External IDs of groups are not stored.
+		return nil
+	case lowerPath == "members":
+		if op == PatchOpRemove && len(value) == 0 {
+			group.Members = nil
+			return nil
+		}
+
+		var members []MultiValue
+		err := unmarshal(value, &members)
+		if err != nil {
+			return err
+		}
+
+		switch op {
+		case PatchOpAdd:
+			group.Members = addMembers(group.Members, members)
+		case PatchOpRemove:
+			group.Members = removeMembers(group.Members, members)
+		case PatchOpReplace:
+			group.Members = addMembers(nil, members)
+		}
+
+	case strings.HasPrefix(lowerPath, "members[") && strings.HasSuffix(lowerPath, "]"):
+		filter, err := ParseFilter(path[len("members[") : len(path)-1])
+		if err != nil || filter == nil || filter.Attribute != "value" {
+			return NewError(http.StatusBadRequest, ErrorTypeInvalidPath, "Invalid path %q", path)
+		}
+
+		if op != PatchOpRemove {
+			return NewError(http.StatusBadRequest, ErrorTypeInvalidPath, "Only remove operations are supported for path %q", path)
+		}
+
+		group.Members = removeMembers(group.Members, []MultiValue{{Value: filter.Value}})
+	case lowerPath == strings.ToLower(SchemaLXDGroup):
+		if op == PatchOpRemove {
+			group.LXD = nil
+			return nil
+		}
+
+		var extension GroupExtension
+		err := unmarshal(value, &extension)
+		if err != nil {
+			return err
+		}
+
+		authGroups, err := json.Marshal(extension.AuthGroups)
+		if err != nil {
+			return err
+		}
+
+		return patchGroup(group, op, SchemaLXDGroup+":authGroups", authGroups)
+	case lowerPath == strings.ToLower(SchemaLXDGroup+":authGroups"):
+		if group.LXD == nil {
+			group.LXD = &GroupExtension{}
+		}
+
+		if op == PatchOpRemove {
+			group.LXD.AuthGroups = nil
+			return nil
+		}
+
+		var authGroups []string
+		err := unmarshal(value, &authGroups)
+		if err != nil {
+			return err
+		}
+
+		if op == PatchOpReplace {
+			group.LXD.AuthGroups = nil
+		}
+
+		for _, authGroup := range authGroups {
+			if !slices.Contains(group.LXD.AuthGroups, authGroup) {
+				group.LXD.AuthGroups = append(group.LXD.AuthGroups, authGroup)
+			}
+		}
+
+	default:
+		return NewError(http.StatusBadRequest, ErrorTypeInvalidPath, "Unsupported path %q", path)
+	}
+
+	return nil
+}
+
+// addMembers returns the members with the given additions, skipping any that are already present.
+func addMembers(members []MultiValue, additions []MultiValue) []MultiValue {
+	for _, addition := range additions {
+		if !slices.ContainsFunc(members, func(member MultiValue) bool { return member.Value == addition.Value }) {
+			members = append(members, addition)
+		}
+	}
+
+	return members
+}
+
+// removeMembers returns the members without the given removals.
+func removeMembers(members []MultiValue, removals []MultiValue) []MultiValue {
+	return slices.DeleteFunc(members, func(member MultiValue) bool {
+		return slices.ContainsFunc(removals, func(removal MultiValue) bool { return member.Value == removal.Value })
+	})
+}
+
+func unmarshal(value json.RawMessage, target any) error {
+	err := json.Unmarshal(value, target)
+	if err != nil {
+		return NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "Invalid value %q", string(value))
+	}
+
+	return nil
+}
+
+func unmarshalString(value json.RawMessage) (string, error) {
+	var s string
+	err := unmarshal(value, &s)
+	return s, err
+}
+
+// unmarshalOptionalString returns an empty string for remove operations, and otherwise unmarshals the value.
+func unmarshalOptionalString(op string, value json.RawMessage) (string, error) {
+	if op == PatchOpRemove {
+		return "", nil
+	}
+
+	return unmarshalString(value)
+}
+
+// unmarshalBool unmarshals a boolean value. Some identity providers send boolean values as strings (e.g. "False").
+func unmarshalBool(value json.RawMessage) (bool, error) {
+	var b bool
+	err := json.Unmarshal(value, &b)
+	if err == nil {
+		return b, nil
+	}
+
+	s, err := unmarshalString(value)
+	if err != nil {
+		return false, err
+	}
+
+	b, err = strconv.ParseBool(strings.ToLower(s))
+	if err != nil {
+		return false, NewError(http.StatusBadRequest, ErrorTypeInvalidValue, "Invalid boolean value %q", s)
+	}
+
+	return b, nil
+}
@@ -0,0 +1,143 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyUserPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    User
+		wantErr bool
+	}{
+		{
+			name:  "Deactivate",
+			patch: `[{"op":"replace","path":"active","value":false}]`,
+			want:  User{UserName: "jane@example.com", Active: new(false)},
+		},
+		{
+			name:  "Deactivate with a string value and no path",
+			patch: `[{"op":"Replace","value":{"active":"False"}}]`,
+			want:  User{UserName: "jane@example.com", Active: new(false)},
+		},
+		{
+			name:  "Set name components",
+			patch: `[{"op":"add","path":"name.givenName","value":"Jane"},{"op":"add","path":"name.familyName","value":"Doe"}]`,
+			want:  User{UserName: "jane@example.com", Name: &Name{GivenName: "Jane", FamilyName: "Doe"}},
+		},
+		{
+			name:  "Unknown attributes are ignored",
+			patch: `[{"op":"replace","path":"title","value":"Engineer"}]`,
+			want:  User{UserName: "jane@example.com"},
+		},
+		{
+			name:    "Remove required attribute",
+			patch:   `[{"op":"remove","path":"userName"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid operation",
+			patch:   `[{"op":"move","path":"userName","value":"x"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid boolean",
+			patch:   `[{"op":"replace","path":"active","value":"maybe"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.patch), &operations))
+
+			user := User{UserName: "jane@example.com"}
+			err := ApplyUserPatch(&user, operations)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+		})
+	}
+}
+
+func TestApplyGroupPatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    Group
+		wantErr bool
+	}{
+		{
+			name:  "Add members",
+			patch: `[{"op":"add","path":"members","value":[{"value":"2"},{"value":"3"}]}]`,
+			want:  Group{DisplayName: "eng", Members: []MultiValue{{Value: "1"}, {Value: "2"}, {Value: "3"}}},
+		},
+		{
+			name:  "Remove member by filter",
+			patch: `[{"op":"remove","path":"members[value eq \"1\"]"}]`,
+			want:  Group{DisplayName: "eng", Members: []MultiValue{}},
+		},
+		{
+			name:  "Replace members",
+			patch: `[{"op":"replace","path":"members","value":[{"value":"4"}]}]`,
+			want:  Group{DisplayName: "eng", Members: []MultiValue{{Value: "4"}}},
+		},
+		{
+			name:  "Rename",
+			patch: `[{"op":"replace","value":{"displayName":"engineering"}}]`,
+			want:  Group{DisplayName: "engineering", Members: []MultiValue{{Value: "1"}}},
+		},
+		{
+			name:  "Map authorization groups",
+			patch: `[{"op":"add","path":"urn:canonical:lxd:scim:schemas:2.0:Group:authGroups","value":["admins"]}]`,
+			want:  Group{DisplayName: "eng", Members: []MultiValue{{Value: "1"}}, LXD: &GroupExtension{AuthGroups: []string{"admins"}}},
+		},
+		{
+			name:  "Map authorization groups without a path",
+			patch: `[{"op":"replace","value":{"urn:canonical:lxd:scim:schemas:2.0:Group":{"authGroups":["viewers"]}}}]`,
+			want:  Group{DisplayName: "eng", Members: []MultiValue{{Value: "1"}}, LXD: &GroupExtension{AuthGroups: []string{"viewers"}}},
+		},
+		{
+			name:    "Unsupported path",
+			patch:   `[{"op":"replace","path":"owner","value":"x"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Add member by filter",
+			patch:   `[{"op":"add","path":"members[value eq \"1\"]","value":"x"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Remove without a path",
+			patch:   `[{"op":"remove"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var operations []PatchOperation
+			require.NoError(t, json.Unmarshal([]byte(tt.patch), &operations))
+
+			group := Group{DisplayName: "eng", Members: []MultiValue{{Value: "1"}}}
+			err := ApplyGroupPatch(&group, operations)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, group)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/canonical/lxd/lxd/metrics"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/shared/api"
+)
+
+// SCIM error types (RFC 7644 section 3.12).
+const (
+	ErrorTypeInvalidFilter = "invalidFilter"
+	ErrorTypeInvalidPath   = "invalidPath"
+	ErrorTypeInvalidValue  = "invalidValue"
+	ErrorTypeInvalidSyntax = "invalidSyntax"
+	ErrorTypeMutability    = "mutability"
+	ErrorTypeNoTarget      = "noTarget"
+	ErrorTypeUniqueness    = "uniqueness"
+)
+
+// Error is a SCIM error. It is both the error response body and a Go error.
+type Error struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail,omitempty"`
+
+	code int
+}
+
+// NewError returns a new [Error] with the given HTTP status code, SCIM error type, and detail.
+func NewError(code int, scimType string, format string, args ...any) *Error {
+	return &Error{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(code),
+		ScimType: scimType,
+		Detail:   fmt.Sprintf(format, args...),
+		code:     code,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Detail
+}
+
+// scimResponse is a response rendered in the SCIM media type.
+type scimResponse struct {
+	code     int
+	content  any
+	location string
+}
+
+// Response returns a SCIM response with the given status code and content. If location is not empty, it is set as the
+// Location header.
+func Response(code int, content any, location string) response.Response {
+	return &scimResponse{code: code, content: content, location: location}
+}
+
+// ErrorResponse returns a SCIM error response for the given error. The status code is taken from the [Error] or
+// [api.StatusError] if the error wraps one, and is otherwise a 500.
+func ErrorResponse(err error) response.Response {
+	var scimErr *Error
+	if !errors.As(err, &scimErr) {
+		code, ok := api.StatusErrorMatch(err)
+		if !ok {
+			code = http.StatusInternalServerError
+		}
+
+		scimType := ""
+		if code == http.StatusConflict {
+			scimType = ErrorTypeUniqueness
+		}
+
+		scimErr = NewError(code, scimType, "%s", err.Error())
+	}
+
+	return &scimResponse{code: scimErr.code, content: scimErr}
+}
+
+// Render renders the response.
+func (r *scimResponse) Render(w http.ResponseWriter, req *http.Request) error {
+	defer func() {
+		if r.code < 400 {
+			metrics.UseMetricsCallback(req, metrics.Success)
+		} else if r.code < 500 {
+			metrics.UseMetricsCallback(req, metrics.ErrorClient)
+		} else {
+			metrics.UseMetricsCallback(req, metrics.ErrorServer)
+		}
+	}()
+
+	if r.location != "" {
+		w.Header().Set("Location", r.location)
+	}
+
+	if r.content == nil {
+		w.WriteHeader(r.code)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(r.code)
+	return json.NewEncoder(w).Encode(r.content)
+}
+
+// String returns a description of the response.
+func (r *scimResponse) String() string {
+	if r.code < 400 {
+		return "success"
+	}
+
+	return "failure"
+}
@@ -0,0 +1,153 @@
+// Package scim contains the resource types and protocol helpers of the SCIM 2.0 provisioning API (RFC 7643 and RFC 7644).
+package scim
+
+import (
+	"encoding/json"
+)
+
+// ContentType is the media type of SCIM requests and responses.
+const ContentType = "application/scim+json"
+
+// SCIM schema URNs.
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	// SchemaLXDGroup is the LXD extension to the group schema. It holds the LXD authorization groups that the group
+	// is mapped to.
+	SchemaLXDGroup = "urn:canonical:lxd:scim:schemas:2.0:Group"
+)
+
+// Resource type names.
+const (
+	ResourceTypeUser  = "User"
+	ResourceTypeGroup = "Group"
+)
+
+// Meta contains the resource metadata common to all resources.
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location,omitempty"`
+}
+
+// Name contains the components of the name of a user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// String returns the formatted name, or the given and family names if no formatted name is set.
+func (n Name) String() string {
+	if n.Formatted != "" {
+		return n.Formatted
+	}
+
+	if n.GivenName == "" || n.FamilyName == "" {
+		return n.GivenName + n.FamilyName
+	}
+
+	return n.GivenName + " " + n.FamilyName
+}
+
+// MultiValue is a multi-valued attribute entry, such as an email address or a reference to a group or user.
+type MultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// User is the SCIM user resource.
+type User struct {
+	Schemas     []string     `json:"schemas"`
+	ID          string       `json:"id,omitempty"`
+	ExternalID  string       `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        *Name        `json:"name,omitempty"`
+	DisplayName string       `json:"displayName,omitempty"`
+	Emails      []MultiValue `json:"emails,omitempty"`
+	Active      *bool        `json:"active,omitempty"`
+	Groups      []MultiValue `json:"groups,omitempty"`
+	Meta        *Meta        `json:"meta,omitempty"`
+}
+
+// IsActive returns false if the user was explicitly marked as inactive.
+func (u User) IsActive() bool {
+	return u.Active == nil || *u.Active
+}
+
+// GetDisplayName returns the display name of the user, falling back to the formatted name.
+func (u User) GetDisplayName() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+
+	return u.Name.String()
+}
+
+// GroupExtension is the LXD extension of the group resource.
+type GroupExtension struct {
+	// AuthGroups are the names of the LXD authorization groups that the group is mapped to.
+	AuthGroups []string `json:"authGroups"`
+}
+
+// Group is the SCIM group resource.
+type Group struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id,omitempty"`
+	DisplayName string          `json:"displayName"`
+	Members     []MultiValue    `json:"members,omitempty"`
+	LXD         *GroupExtension `json:"urn:canonical:lxd:scim:schemas:2.0:Group,omitempty"`
+	Meta        *Meta           `json:"meta,omitempty"`
+}
+
+// ListResponse is the response to a query for resources.
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// NewListResponse returns a [ListResponse] for the page of the given resources starting at the one-based startIndex
+// and containing at most count resources. A negative count returns all remaining resources.
+func NewListResponse[T any](resources []T, startIndex int, count int) ListResponse {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+
+	page := []any{}
+	for i := startIndex - 1; i < len(resources) && (count < 0 || len(page) < count); i++ {
+		page = append(page, resources[i])
+	}
+
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
+
+// PatchOperation is a single operation of a [PatchRequest].
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// PatchRequest is the body of a PATCH request.
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/response"
+	"github.com/canonical/lxd/lxd/scim"
+	"github.com/canonical/lxd/shared/api"
+)
+
+type scimTestSuite struct {
+	lxdTestSuite
+}
+
+// do calls the given SCIM handler and returns the status code and the body of the response.
+func (suite *scimTestSuite) do(handler func(*Daemon, *http.Request) response.Response, method string, id string, body any) (int, []byte) {
+	var reqBody bytes.Buffer
+	if body != nil {
+		suite.Req.NoError(json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req := httptest.NewRequest(method, "/1.0/scim/v2/Users/"+id, &reqBody)
+	req.SetPathValue("id", id)
+	suite.Req.NoError(request.SetRequestor(req, nil, request.RequestorArgs{Trusted: true, Username: "root", Protocol: request.ProtocolUnix}))
+
+	w := httptest.NewRecorder()
+	suite.Req.NoError(handler(suite.d, req).Render(w, req))
+
+	return w.Code, w.Body.Bytes()
+}
+
+// createUser provisions a user and returns it.
+func (suite *scimTestSuite) createUser(userName string) scim.User {
+	code, body := suite.do(scimUsersPost, http.MethodPost, "", scim.User{Schemas: []string{scim.SchemaUser}, UserName: userName, ExternalID: "ext-" + userName})
+	suite.Req.Equal(http.StatusCreated, code, string(body))
+
+	var user scim.User
+	suite.Req.NoError(json.Unmarshal(body, &user))
+
+	return user
+}
+
+// identityMetadata returns the OIDC metadata of the identity with the given email address.
+func (suite *scimTestSuite) identityMetadata(userName string) *dbCluster.OIDCMetadata {
+	var metadata *dbCluster.OIDCMetadata
+	err := suite.d.State().DB.Cluster.Transaction(context.Background(), func(ctx context.Context, tx *db.ClusterTx) error {
+		id, err := dbCluster.GetIdentityByAuthenticationMethodAndIdentifier(ctx, tx.Tx(), api.AuthenticationMethodOIDC, userName)
+		if err != nil {
+			return err
+		}
+
+		metadata, err = id.OIDCMetadata()
+		return err
+	})
+	suite.Req.NoError(err)
+
+	return metadata
+}
+
+func (suite *scimTestSuite) TestUsersPost() {
+	user := suite.createUser("jane@example.com")
+	suite.Equal("jane@example.com", user.UserName)
+	suite.Equal("ext-jane@example.com", user.ExternalID)
+	suite.True(user.IsActive())
+
+	code, body := suite.do(scimUserGet, http.MethodGet, user.ID, nil)
+	suite.Equal(http.StatusOK, code, string(body))
+
+	// User names are unique.
+	code, body = suite.do(scimUsersPost, http.MethodPost, "", scim.User{UserName: "jane@example.com"})
+	suite.Equal(http.StatusConflict, code, string(body))
+
+	code, body = suite.do(scimUsersPost, http.MethodPost, "", scim.User{})
+	suite.Equal(http.StatusBadRequest, code, string(body))
+}
+
+func (suite *scimTestSuite) TestUserDeactivate() {
+	user := suite.createUser("jane@example.com")
+
+	code, body := suite.do(scimUserPatch, http.MethodPatch, user.ID, scim.PatchRequest{
+		Schemas:    []string{scim.SchemaPatchOp},
+		Operations: []scim.PatchOperation{{Op: "replace", Path: "active", Value: json.RawMessage("false")}},
+	})
+	suite.Req.Equal(http.StatusOK, code, string(body))
+
+	var updated scim.User
+	suite.Req.NoError(json.Unmarshal(body, &updated))
+	suite.False(updated.IsActive())
+	suite.True(suite.identityMetadata("jane@example.com").Disabled)
+}
+
+func (suite *scimTestSuite) TestUserDelete() {
+	user := suite.createUser("jane@example.com")
+
+	code, body := suite.do(scimUserDelete, http.MethodDelete, user.ID, nil)
+	suite.Req.Equal(http.StatusNoContent, code, string(body))
+
+	// The identity is kept disabled, so that it isn't created again on its next login.
+	metadata := suite.identityMetadata("jane@example.com")
+	suite.True(metadata.Disabled)
+	suite.True(metadata.Deprovisioned)
+	suite.Empty(metadata.SCIMExternalID)
+
+	// It is no longer served by the SCIM API.
+	code, _ = suite.do(scimUserGet, http.MethodGet, user.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+
+	code, _ = suite.do(scimUserDelete, http.MethodDelete, user.ID, nil)
+	suite.Equal(http.StatusNotFound, code)
+
+	code, body = suite.do(scimUsersGet, http.MethodGet, "", nil)
+	suite.Req.Equal(http.StatusOK, code, string(body))
+	suite.NotContains(string(body), "jane@example.com")
+
+	// Provisioning the user again reuses the identity.
+	reprovisioned := suite.createUser("jane@example.com")
+	suite.Equal(user.ID, reprovisioned.ID)
+	suite.True(reprovisioned.IsActive())
+
+	metadata = suite.identityMetadata("jane@example.com")
+	suite.False(metadata.Disabled)
+	suite.False(metadata.Deprovisioned)
+}
+
+func TestSCIMTestSuite(t *testing.T) {
+	suite.Run(t, new(scimTestSuite))
+}
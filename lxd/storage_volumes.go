@@ -1018,6 +1018,11 @@ func storagePoolVolumesPost(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	err = webhookAdmit(r, s, entity.TypeStorageVolume, api.WebhookAdmissionOperationCreate, projectName, entity.StorageVolumeURL(projectName, target, poolName, cluster.StoragePoolVolumeTypeNameCustom, req.Name), &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	// Check new volume name is valid.
 	err = storageDrivers.ValidVolumeName(req.Name)
 	if err != nil {
@@ -2257,6 +2262,16 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 		return response.BadRequest(err)
 	}
 
+	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
+
+	// Requests only restoring a snapshot don't propose any change to review.
+	if req.Restore == "" || req.Config != nil {
+		err = webhookAdmit(r, s, entity.TypeStorageVolume, api.WebhookAdmissionOperationUpdate, effectiveProjectName, volumeURL, &req)
+		if err != nil {
+			return response.SmartError(err)
+		}
+	}
+
 	run := func(ctx context.Context, op *operations.Operation) error {
 		// Checks that applying putReq to the volume doesn't exceed project limits.
 		checkVolumeUpdateLimits := func(putReq api.StorageVolumePut) error {
@@ -2331,7 +2346,6 @@ func storagePoolVolumePut(d *Daemon, r *http.Request) response.Response {
 		return nil
 	}
 
-	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
 	args := operations.OperationArgs{
 		ProjectName: request.ProjectParam(r),
 		Type:        operationtype.VolumeUpdate,
@@ -2458,11 +2472,16 @@ func storagePoolVolumePatch(d *Daemon, r *http.Request) response.Response {
 		}
 	}
 
+	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
+	err = webhookAdmit(r, s, entity.TypeStorageVolume, api.WebhookAdmissionOperationUpdate, effectiveProjectName, volumeURL, &req)
+	if err != nil {
+		return response.SmartError(err)
+	}
+
 	run := func(ctx context.Context, op *operations.Operation) error {
 		return details.pool.UpdateCustomVolume(ctx, effectiveProjectName, dbVolume.Name, req.Description, req.Config, op)
 	}
 
-	volumeURL := entity.StorageVolumeURL(effectiveProjectName, details.location, details.pool.Name(), details.volumeTypeName, details.volumeName)
 	args := operations.OperationArgs{
 		ProjectName: request.ProjectParam(r),
 		Type:        operationtype.VolumeUpdate,
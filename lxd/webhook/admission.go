@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/version"
+)
+
+const (
+	// FailurePolicyFail rejects the request when the admission webhook can't be reached or returns an invalid response.
+	FailurePolicyFail = "fail"
+
+	// FailurePolicyIgnore ignores the admission webhook when it can't be reached or returns an invalid response.
+	FailurePolicyIgnore = "ignore"
+)
+
+// maxResponseSize is the maximum size of the response of an admission webhook.
+const maxResponseSize = 1024 * 1024
+
+// Hook is an admission webhook.
+type Hook struct {
+	// Name of the webhook.
+	Name string
+
+	// URL of the endpoint.
+	URL string
+
+	// Secret used to sign the request body (optional).
+	Secret string
+
+	// CACert is the PEM-encoded CA certificate of the endpoint (optional).
+	CACert string
+
+	// Timeout of the request.
+	Timeout time.Duration
+
+	// FailurePolicy is either FailurePolicyFail or FailurePolicyIgnore.
+	FailurePolicy string
+
+	// Proxy returns the proxy to use for the request.
+	Proxy func(*http.Request) (*url.URL, error)
+}
+
+// Review sends the admission request to each hook in turn and returns the object resulting from the patches returned
+// by the hooks. Each hook receives the object as patched by the previous hooks.
+// A denial by any of the hooks is returned as a 403 error.
+func Review(ctx context.Context, hooks []Hook, req api.WebhookAdmissionRequest) (json.RawMessage, error) {
+	for _, hook := range hooks {
+		object, err := review(ctx, hook, req)
+		if err != nil {
+			if api.StatusErrorCheck(err, http.StatusForbidden) {
+				return nil, err
+			}
+
+			if hook.FailurePolicy == FailurePolicyIgnore {
+				logger.Warn("Ignoring failed admission webhook", logger.Ctx{"webhook": hook.Name, "err": err})
+				continue
+			}
+
+			return nil, api.StatusErrorf(http.StatusInternalServerError, "Admission webhook %q failed: %w", hook.Name, err)
+		}
+
+		req.Object = object
+	}
+
+	return req.Object, nil
+}
+
+// review sends the admission request to a hook and returns the patched object.
+func review(ctx context.Context, hook Hook, req api.WebhookAdmissionRequest) (json.RawMessage, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewHTTPClient(hook.Proxy, hook.CACert, hook.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.CloseIdleConnections()
+
+	ctx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", version.UserAgent)
+	httpReq.Header.Set("X-LXD-Delivery", req.UUID)
+	if hook.Secret != "" {
+		httpReq.Header.Set(SignatureHeader, Sign(hook.Secret, body))
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("Unexpected status %q", resp.Status)
+	}
+
+	var response api.WebhookAdmissionResponse
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid response: %w", err)
+	}
+
+	if response.UUID != req.UUID {
+		return nil, errors.New("Response UUID doesn't match the request UUID")
+	}
+
+	if !response.Allowed {
+		message := response.Message
+		if message == "" {
+			message = "No reason given"
+		}
+
+		return nil, api.StatusErrorf(http.StatusForbidden, "Admission webhook %q denied the request: %s", hook.Name, message)
+	}
+
+	if len(response.Patch) == 0 || string(response.Patch) == "null" {
+		return req.Object, nil
+	}
+
+	object, err := ApplyPatch(req.Object, response.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	return object, nil
+}
@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// newTestHook returns a hook backed by a local HTTP server using the given handler to build the response.
+func newTestHook(t *testing.T, name string, secret string, handler func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse) Hook {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if secret != "" && r.Header.Get(SignatureHeader) != Sign(secret, body) {
+			http.Error(w, "Bad signature", http.StatusUnauthorized)
+			return
+		}
+
+		var req api.WebhookAdmissionRequest
+		err = json.Unmarshal(body, &req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(handler(req))
+	}))
+
+	t.Cleanup(server.Close)
+
+	return Hook{
+		Name:          name,
+		URL:           server.URL,
+		Secret:        secret,
+		Timeout:       time.Second,
+		FailurePolicy: FailurePolicyFail,
+	}
+}
+
+func TestReview(t *testing.T) {
+	request := api.WebhookAdmissionRequest{
+		UUID:       "8ca1f7d4-0bb2-4ef4-9d4e-0d6b6b8c3a3f",
+		Operation:  api.WebhookAdmissionOperationCreate,
+		EntityType: "instance",
+		EntityURL:  "/1.0/instances/c1",
+		Project:    "default",
+		Object:     json.RawMessage(`{"name":"c1","config":{}}`),
+	}
+
+	allow := func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+		return api.WebhookAdmissionResponse{UUID: req.UUID, Allowed: true}
+	}
+
+	t.Run("Allowed without patch", func(t *testing.T) {
+		hooks := []Hook{newTestHook(t, "allow", "secret", allow)}
+
+		object, err := Review(context.Background(), hooks, request)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(request.Object), string(object))
+	})
+
+	t.Run("Patches are chained", func(t *testing.T) {
+		hooks := []Hook{
+			newTestHook(t, "owner", "", func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+				return api.WebhookAdmissionResponse{UUID: req.UUID, Allowed: true, Patch: json.RawMessage(`[{"op":"add","path":"/config/user.owner","value":"alice"}]`)}
+			}),
+			newTestHook(t, "team", "", func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+				return api.WebhookAdmissionResponse{UUID: req.UUID, Allowed: true, Patch: json.RawMessage(`[{"op":"test","path":"/config/user.owner","value":"alice"},{"op":"add","path":"/config/user.team","value":"infra"}]`)}
+			}),
+		}
+
+		object, err := Review(context.Background(), hooks, request)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"name":"c1","config":{"user.owner":"alice","user.team":"infra"}}`, string(object))
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		hooks := []Hook{newTestHook(t, "deny", "", func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+			return api.WebhookAdmissionResponse{UUID: req.UUID, Message: "Missing owner"}
+		})}
+
+		hooks[0].FailurePolicy = FailurePolicyIgnore
+
+		_, err := Review(context.Background(), hooks, request)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusForbidden))
+		assert.ErrorContains(t, err, "Missing owner")
+	})
+
+	t.Run("Mismatched UUID", func(t *testing.T) {
+		hooks := []Hook{newTestHook(t, "bad", "", func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+			return api.WebhookAdmissionResponse{UUID: "other", Allowed: true}
+		})}
+
+		_, err := Review(context.Background(), hooks, request)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusInternalServerError))
+	})
+
+	t.Run("Failure policy", func(t *testing.T) {
+		unreachable := Hook{
+			Name:          "unreachable",
+			URL:           "http://127.0.0.1:1",
+			Timeout:       time.Second,
+			FailurePolicy: FailurePolicyFail,
+		}
+
+		_, err := Review(context.Background(), []Hook{unreachable}, request)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusInternalServerError))
+
+		unreachable.FailurePolicy = FailurePolicyIgnore
+		object, err := Review(context.Background(), []Hook{unreachable, newTestHook(t, "allow", "", allow)}, request)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(request.Object), string(object))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		slow := newTestHook(t, "slow", "", func(req api.WebhookAdmissionRequest) api.WebhookAdmissionResponse {
+			time.Sleep(500 * time.Millisecond)
+			return api.WebhookAdmissionResponse{UUID: req.UUID, Allowed: true}
+		})
+
+		slow.Timeout = 100 * time.Millisecond
+
+		_, err := Review(context.Background(), []Hook{slow}, request)
+		assert.True(t, api.StatusErrorCheck(err, http.StatusInternalServerError))
+	})
+}
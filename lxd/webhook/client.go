@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/canonical/lxd/shared"
+)
+
+// SignatureHeader is the header carrying the signature of the request body.
+const SignatureHeader = "X-LXD-Signature-256"
+
+// Sign returns the value of the [SignatureHeader] for the given body and secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewHTTPClient returns an HTTP client for a webhook using the given proxy function and timeout.
+// If caCert is set, the endpoint certificate must be signed by this PEM-encoded CA certificate.
+func NewHTTPClient(proxy func(*http.Request) (*url.URL, error), caCert string, timeout time.Duration) (*http.Client, error) {
+	transport := &http.Transport{
+		Proxy:               proxy,
+		TLSHandshakeTimeout: timeout,
+	}
+
+	if caCert != "" {
+		tlsConfig, err := shared.GetTLSConfigMem("", "", caCert, "", false)
+		if err != nil {
+			return nil, fmt.Errorf("Failed loading CA certificate: %w", err)
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
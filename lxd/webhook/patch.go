@@ -0,0 +1,292 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// patchOperation is an operation of a JSON patch (RFC 6902).
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyPatch applies a JSON patch (RFC 6902) to a JSON document and returns the patched document.
+func ApplyPatch(document []byte, patch []byte) ([]byte, error) {
+	var operations []patchOperation
+	err := json.Unmarshal(patch, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid JSON patch: %w", err)
+	}
+
+	doc, err := decodeJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid JSON document: %w", err)
+	}
+
+	for i, operation := range operations {
+		doc, err = applyOperation(doc, operation)
+		if err != nil {
+			return nil, fmt.Errorf("Failed applying JSON patch operation %d (%s %q): %w", i, operation.Op, operation.Path, err)
+		}
+	}
+
+	return json.Marshal(doc)
+}
+
+// decodeJSON decodes a JSON value, keeping numbers as [json.Number] so that they are not altered.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// applyOperation applies a single patch operation to the document and returns the new document.
+func applyOperation(doc any, operation patchOperation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		if operation.Value == nil {
+			return nil, errors.New("Missing value")
+		}
+
+		value, err := decodeJSON(operation.Value)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value: %w", err)
+		}
+
+		switch operation.Op {
+		case "add":
+			return addValue(doc, path, value)
+		case "replace":
+			doc, err = removeValue(doc, path)
+			if err != nil {
+				return nil, err
+			}
+
+			return addValue(doc, path, value)
+		}
+
+		current, err := getValue(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(current, value) {
+			return nil, errors.New("Test failed")
+		}
+
+		return doc, nil
+	case "remove":
+		return removeValue(doc, path)
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := getValue(doc, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == "move" {
+			if strings.HasPrefix(operation.Path, operation.From+"/") {
+				return nil, errors.New("Cannot move a value into one of its children")
+			}
+
+			doc, err = removeValue(doc, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// Copy the value so that later operations don't modify both copies.
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err = decodeJSON(encoded)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return addValue(doc, path, value)
+	}
+
+	return nil, fmt.Errorf("Unknown operation %q", operation.Op)
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("Invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses an array index token. The index may be equal to the length of the array if allowEnd is true.
+func arrayIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("Invalid array index %q", token)
+	}
+
+	if index > length || (index == length && !allowEnd) {
+		return 0, fmt.Errorf("Array index %d out of bounds", index)
+	}
+
+	return index, nil
+}
+
+// getValue returns the value at the given path.
+func getValue(doc any, path []string) (any, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("Key %q not found", token)
+			}
+
+			doc = value
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("Cannot reference %q in a scalar value", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// updateParent calls update with the parent of the value at the given path and the last token of the path, and
+// replaces the parent with the value returned by update, as arrays change when values are added or removed.
+func updateParent(doc any, path []string, update func(parent any, token string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return update(doc, path[0])
+	}
+
+	switch node := doc.(type) {
+	case map[string]any:
+		child, ok := node[path[0]]
+		if !ok {
+			return nil, fmt.Errorf("Key %q not found", path[0])
+		}
+
+		child, err := updateParent(child, path[1:], update)
+		if err != nil {
+			return nil, err
+		}
+
+		node[path[0]] = child
+		return node, nil
+	case []any:
+		index, err := arrayIndex(path[0], len(node), false)
+		if err != nil {
+			return nil, err
+		}
+
+		child, err := updateParent(node[index], path[1:], update)
+		if err != nil {
+			return nil, err
+		}
+
+		node[index] = child
+		return node, nil
+	}
+
+	return nil, fmt.Errorf("Cannot reference %q in a scalar value", path[0])
+}
+
+// addValue adds the value at the given path, replacing the member of an object or inserting into an array.
+func addValue(doc any, path []string, value any) (any, error) {
+	// An empty path replaces the whole document.
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return updateParent(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			node[token] = value
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(node[:index], append([]any{value}, node[index:]...)...), nil
+		}
+
+		return nil, fmt.Errorf("Cannot add %q to a scalar value", token)
+	})
+}
+
+// removeValue removes the value at the given path, which must exist.
+func removeValue(doc any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.New("Cannot remove the whole document")
+	}
+
+	return updateParent(doc, path, func(parent any, token string) (any, error) {
+		switch node := parent.(type) {
+		case map[string]any:
+			_, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("Key %q not found", token)
+			}
+
+			delete(node, token)
+			return node, nil
+		case []any:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(node[:index], node[index+1:]...), nil
+		}
+
+		return nil, fmt.Errorf("Cannot remove %q from a scalar value", token)
+	})
+}
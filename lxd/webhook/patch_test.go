@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPatch(t *testing.T) {
+	document := `{"name":"c1","config":{"limits.cpu":"2"},"profiles":["default"],"size":10737418240}`
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Add a member",
+			patch: `[{"op":"add","path":"/config/user.owner","value":"alice"}]`,
+			want:  `{"config":{"limits.cpu":"2","user.owner":"alice"},"name":"c1","profiles":["default"],"size":10737418240}`,
+		},
+		{
+			name:  "Append to an array",
+			patch: `[{"op":"add","path":"/profiles/-","value":"extra"}]`,
+			want:  `{"config":{"limits.cpu":"2"},"name":"c1","profiles":["default","extra"],"size":10737418240}`,
+		},
+		{
+			name:  "Insert into an array",
+			patch: `[{"op":"add","path":"/profiles/0","value":"base"}]`,
+			want:  `{"config":{"limits.cpu":"2"},"name":"c1","profiles":["base","default"],"size":10737418240}`,
+		},
+		{
+			name:  "Replace a value",
+			patch: `[{"op":"replace","path":"/config/limits.cpu","value":"1"}]`,
+			want:  `{"config":{"limits.cpu":"1"},"name":"c1","profiles":["default"],"size":10737418240}`,
+		},
+		{
+			name:  "Remove a value",
+			patch: `[{"op":"remove","path":"/profiles/0"}]`,
+			want:  `{"config":{"limits.cpu":"2"},"name":"c1","profiles":[],"size":10737418240}`,
+		},
+		{
+			name:  "Move a value",
+			patch: `[{"op":"move","from":"/config/limits.cpu","path":"/config/limits.memory"}]`,
+			want:  `{"config":{"limits.memory":"2"},"name":"c1","profiles":["default"],"size":10737418240}`,
+		},
+		{
+			name:  "Copy a value",
+			patch: `[{"op":"copy","from":"/name","path":"/config/user.name"}]`,
+			want:  `{"config":{"limits.cpu":"2","user.name":"c1"},"name":"c1","profiles":["default"],"size":10737418240}`,
+		},
+		{
+			name:  "Escaped pointer",
+			patch: `[{"op":"add","path":"/config/user.a~1b~0c","value":"x"}]`,
+			want:  `{"config":{"limits.cpu":"2","user.a/b~c":"x"},"name":"c1","profiles":["default"],"size":10737418240}`,
+		},
+		{
+			name:  "Successful test",
+			patch: `[{"op":"test","path":"/size","value":10737418240},{"op":"remove","path":"/size"}]`,
+			want:  `{"config":{"limits.cpu":"2"},"name":"c1","profiles":["default"]}`,
+		},
+		{
+			name:    "Failed test",
+			patch:   `[{"op":"test","path":"/name","value":"c2"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Replace a missing value",
+			patch:   `[{"op":"replace","path":"/config/missing","value":"1"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Array index out of bounds",
+			patch:   `[{"op":"add","path":"/profiles/2","value":"extra"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Unknown operation",
+			patch:   `[{"op":"merge","path":"/name","value":"c2"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Invalid patch",
+			patch:   `{"op":"add"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPatch([]byte(document), []byte(tt.patch))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
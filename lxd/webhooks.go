@@ -0,0 +1,593 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/events"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/task"
+	"github.com/canonical/lxd/lxd/webhook"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+	"github.com/canonical/lxd/shared/logger"
+	"github.com/canonical/lxd/shared/validate"
+	"github.com/canonical/lxd/shared/version"
+)
+
+const (
+	// webhookDefaultTypes are the event types sent to a webhook without `types` configuration.
+	webhookDefaultTypes = api.EventTypeLifecycle + "," + api.EventTypeSecurity
+
+	// webhookMaxAttempts is the number of delivery attempts after which a delivery is given up.
+	webhookMaxAttempts = 10
+
+	// webhookRetryInterval is the delay before the first retry of a delivery, doubled on each failed attempt.
+	webhookRetryInterval = 10 * time.Second
+
+	// webhookRetryMaxInterval is the maximum delay between two delivery attempts.
+	webhookRetryMaxInterval = time.Hour
+
+	// webhookMaxPending is the number of pending deliveries of a webhook on a member above which new events are
+	// dropped, so that an unreachable endpoint can't grow the queue indefinitely.
+	webhookMaxPending = 1000
+
+	// webhookFailedExpiry is how long failed deliveries are kept for inspection.
+	webhookFailedExpiry = 7 * 24 * time.Hour
+
+	// webhookTimeout is the timeout of a delivery attempt.
+	webhookTimeout = 10 * time.Second
+
+	// webhookAdmissionDefaultTimeout is the timeout of an admission webhook without `admission.timeout` configuration.
+	webhookAdmissionDefaultTimeout = 10 * time.Second
+)
+
+// webhookAdmissionEntityTypes are the entity types whose creation and update can be reviewed by admission webhooks.
+var webhookAdmissionEntityTypes = []entity.Type{entity.TypeInstance, entity.TypeProfile, entity.TypeStorageVolume, entity.TypeNetwork, entity.TypeProject}
+
+// webhookValidateConfig validates the configuration keys and values of a webhook.
+func webhookValidateConfig(config map[string]string) error {
+	webhookConfigKeys := map[string]func(value string) error{
+		// lxdmeta:generate(entities=webhook; group=conf; key=url)
+		// Events and admission requests are sent as JSON in the body of `POST` requests to this URL.
+		// ---
+		//  type: string
+		//  required: "yes"
+		//  shortdesc: URL to send the events or admission requests to
+		"url": validate.Required(validate.IsRequestURL),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=type)
+		// Possible values are `event` to receive events, and `admission` to review the requests creating or updating
+		// entities before they are applied.
+		// ---
+		//  type: string
+		//  defaultdesc: `event`
+		//  shortdesc: Type of webhook
+		"type": validate.Optional(validate.IsOneOf(api.WebhookTypeEvent, api.WebhookTypeAdmission)),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=types)
+		// Specify a comma-separated list of event types to send to the webhook.
+		// The types can be any combination of `lifecycle`, `logging`, `ovn`, and `security`.
+		// ---
+		//  type: string
+		//  defaultdesc: `lifecycle,security`
+		//  condition: event webhooks
+		//  shortdesc: Event types to send
+		"types": validate.Optional(validate.IsListOf(validate.IsOneOf(api.EventTypeLifecycle, api.EventTypeLogging, api.EventTypeOVN, api.EventTypeSecurity))),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=projects)
+		// Specify a comma-separated list of projects whose events are sent to the webhook.
+		// Events that don't belong to a project, like security events, are always sent.
+		// For admission webhooks, only the requests on entities of these projects are reviewed.
+		// ---
+		//  type: string
+		//  defaultdesc: all projects
+		//  shortdesc: Projects to send the events of
+		"projects": validate.Optional(validate.IsListOf(validate.IsAny)),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=lifecycle.actions)
+		// Specify a comma-separated list of lifecycle actions to send to the webhook, for example `instance-started,instance-stopped`.
+		// A trailing `*` matches all the actions with the given prefix, for example `instance-*`.
+		// Other event types aren't filtered by action.
+		// ---
+		//  type: string
+		//  defaultdesc: all actions
+		//  condition: event webhooks
+		//  shortdesc: Lifecycle actions to send
+		"lifecycle.actions": validate.Optional(validate.IsListOf(validate.IsAny)),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=secret)
+		// When set, each request carries an `X-LXD-Signature-256` header containing `sha256=` followed by the
+		// hexadecimal HMAC-SHA256 of the request body, computed with this secret.
+		// ---
+		//  type: string
+		//  shortdesc: Secret used to sign the requests
+		"secret": validate.IsAny,
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=tls.ca)
+		// Use this to trust an endpoint whose certificate isn't signed by a system certificate authority.
+		// ---
+		//  type: string
+		//  shortdesc: PEM-encoded CA certificate of the endpoint
+		"tls.ca": validate.Optional(validate.IsX509Certificate),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=admission.entities)
+		// Specify a comma-separated list of entity types whose requests are reviewed by an admission webhook.
+		// The types can be any combination of `instance`, `profile`, `storage_volume`, `network`, and `project`.
+		// ---
+		//  type: string
+		//  defaultdesc: all entity types
+		//  condition: admission webhooks
+		//  shortdesc: Entity types to review
+		"admission.entities": validate.Optional(validate.IsListOf(func(value string) error {
+			if !slices.Contains(webhookAdmissionEntityTypes, entity.Type(value)) {
+				return fmt.Errorf("Unsupported entity type %q", value)
+			}
+
+			return nil
+		})),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=admission.operations)
+		// Specify a comma-separated list of operations reviewed by an admission webhook, among `create` and `update`.
+		// ---
+		//  type: string
+		//  defaultdesc: `create,update`
+		//  condition: admission webhooks
+		//  shortdesc: Operations to review
+		"admission.operations": validate.Optional(validate.IsListOf(validate.IsOneOf(api.WebhookAdmissionOperationCreate, api.WebhookAdmissionOperationUpdate))),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=admission.failure_policy)
+		// Possible values are `fail` to reject the request when the webhook can't be reached or returns an invalid
+		// response, and `ignore` to carry on with the request as if the webhook had allowed it.
+		// ---
+		//  type: string
+		//  defaultdesc: `fail`
+		//  condition: admission webhooks
+		//  shortdesc: What to do when the webhook fails
+		"admission.failure_policy": validate.Optional(validate.IsOneOf(webhook.FailurePolicyFail, webhook.FailurePolicyIgnore)),
+
+		// lxdmeta:generate(entities=webhook; group=conf; key=admission.timeout)
+		// Specify the timeout in seconds of the requests to an admission webhook, between 1 and 30.
+		// ---
+		//  type: integer
+		//  defaultdesc: `10`
+		//  condition: admission webhooks
+		//  shortdesc: Timeout of the admission requests
+		"admission.timeout": validate.Optional(validate.IsInRange(1, 30)),
+	}
+
+	for k, v := range config {
+		// lxdmeta:generate(entities=webhook; group=miscellaneous; key=user.*)
+		// User keys can be used in search.
+		// ---
+		//  type: string
+		//  shortdesc: Free form user key/value storage
+		if strings.HasPrefix(k, "user.") {
+			continue
+		}
+
+		validator, ok := webhookConfigKeys[k]
+		if !ok {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid webhook configuration key %q", k)
+		}
+
+		err := validator(v)
+		if err != nil {
+			return api.StatusErrorf(http.StatusBadRequest, "Invalid value for webhook configuration key %q: %v", k, err)
+		}
+	}
+
+	// The validator loop only runs for keys present in config, so a missing "url" key must be caught separately.
+	if config["url"] == "" {
+		return api.StatusErrorf(http.StatusBadRequest, "Webhook configuration key %q is required", "url")
+	}
+
+	// Event filters only apply to event webhooks, and admission settings to admission webhooks.
+	for k := range config {
+		isAdmissionKey := strings.HasPrefix(k, "admission.")
+		isEventKey := k == "types" || k == "lifecycle.actions"
+
+		if config["type"] == api.WebhookTypeAdmission && isEventKey {
+			return api.StatusErrorf(http.StatusBadRequest, "Webhook configuration key %q can't be used with admission webhooks", k)
+		}
+
+		if config["type"] != api.WebhookTypeAdmission && isAdmissionKey {
+			return api.StatusErrorf(http.StatusBadRequest, "Webhook configuration key %q can only be used with admission webhooks", k)
+		}
+	}
+
+	return nil
+}
+
+// webhookSubscription is the part of a webhook configuration needed to match events.
+type webhookSubscription struct {
+	id       int64
+	types    []string
+	projects []string
+	actions  []string
+
+	// full is set when the webhook has too many pending deliveries on this member.
+	full bool
+}
+
+// matches returns whether the event should be sent to the webhook.
+func (w webhookSubscription) matches(event api.Event) bool {
+	if !slices.Contains(w.types, event.Type) {
+		return false
+	}
+
+	if len(w.projects) > 0 && event.Project != "" && !slices.Contains(w.projects, event.Project) {
+		return false
+	}
+
+	if len(w.actions) == 0 || event.Type != api.EventTypeLifecycle {
+		return true
+	}
+
+	lifecycleEvent := api.EventLifecycle{}
+	err := json.Unmarshal(event.Metadata, &lifecycleEvent)
+	if err != nil {
+		return false
+	}
+
+	for _, action := range w.actions {
+		prefix, isPrefix := strings.CutSuffix(action, "*")
+		if action == lifecycleEvent.Action || (isPrefix && strings.HasPrefix(lifecycleEvent.Action, prefix)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// webhookDispatcher queues the events of this member for delivery to the matching webhooks.
+type webhookDispatcher struct {
+	stateFunc func() *state.State
+	listener  *events.InternalListener
+	events    chan api.Event
+
+	mu            sync.Mutex
+	started       bool
+	subscriptions []webhookSubscription
+	lastPrune     time.Time
+}
+
+// newWebhookDispatcher returns a webhook dispatcher receiving the events of the given internal listener.
+func newWebhookDispatcher(stateFunc func() *state.State, listener *events.InternalListener) *webhookDispatcher {
+	return &webhookDispatcher{
+		stateFunc: stateFunc,
+		listener:  listener,
+		events:    make(chan api.Event, webhookMaxPending),
+	}
+}
+
+// start starts queuing the events received until the given context is cancelled.
+func (w *webhookDispatcher) start(ctx context.Context) {
+	w.mu.Lock()
+	w.started = true
+	w.mu.Unlock()
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-w.events:
+				w.enqueue(ctx, event)
+			}
+		}
+	}()
+}
+
+// handleEvent is the internal listener handler receiving the events of this member.
+//
+// Warn: This must not log anything, as logging events would be sent back to the webhooks.
+func (w *webhookDispatcher) handleEvent(event api.Event) {
+	select {
+	case w.events <- event:
+	default:
+		// Drop the event when the queue is saturated.
+	}
+}
+
+// reload refreshes the subscriptions from the database. The event handler is only registered while there are
+// webhooks, to avoid running an event listener for nothing.
+func (w *webhookDispatcher) reload(ctx context.Context) error {
+	s := w.stateFunc()
+
+	var subscriptions []webhookSubscription
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		webhooks, err := dbCluster.GetWebhooks(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if len(webhooks) == 0 {
+			return nil
+		}
+
+		configs, err := dbCluster.WebhooksConfigStore().GetAll(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, hook := range webhooks {
+			config := configs[hook.ID]
+
+			// Admission webhooks are called while handling API requests and don't receive events.
+			if config["type"] == api.WebhookTypeAdmission {
+				continue
+			}
+
+			types := config["types"]
+			if types == "" {
+				types = webhookDefaultTypes
+			}
+
+			subscription := webhookSubscription{
+				id:       hook.ID,
+				types:    shared.SplitNTrimSpace(types, ",", -1, true),
+				projects: shared.SplitNTrimSpace(config["projects"], ",", -1, true),
+				actions:  shared.SplitNTrimSpace(config["lifecycle.actions"], ",", -1, true),
+			}
+
+			pending, err := dbCluster.CountWebhookDeliveries(ctx, tx.Tx(), hook.ID, s.DB.Cluster.GetNodeID())
+			if err != nil {
+				return err
+			}
+
+			subscription.full = pending >= webhookMaxPending
+			subscriptions = append(subscriptions, subscription)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Failed loading webhooks: %w", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscriptions = subscriptions
+
+	// Events are only received once the dispatcher is started.
+	if !w.started {
+		return nil
+	}
+
+	if len(subscriptions) > 0 {
+		w.listener.AddHandler("webhooks", w.handleEvent)
+	} else {
+		w.listener.RemoveHandler("webhooks")
+	}
+
+	return nil
+}
+
+// enqueue adds the event to the delivery queue of the matching webhooks.
+func (w *webhookDispatcher) enqueue(ctx context.Context, event api.Event) {
+	w.mu.Lock()
+	subscriptions := w.subscriptions
+	w.mu.Unlock()
+
+	s := w.stateFunc()
+	now := time.Now().UTC()
+
+	var body []byte
+	var deliveries []dbCluster.WebhookDeliveryRow
+	for _, subscription := range subscriptions {
+		if subscription.full || !subscription.matches(event) {
+			continue
+		}
+
+		if body == nil {
+			var err error
+			body, err = json.Marshal(event)
+			if err != nil {
+				webhookLogError(event, "Failed encoding webhook event", err)
+				return
+			}
+		}
+
+		deliveries = append(deliveries, dbCluster.WebhookDeliveryRow{
+			UUID:            uuid.New().String(),
+			WebhookID:       subscription.id,
+			NodeID:          s.DB.Cluster.GetNodeID(),
+			EventType:       event.Type,
+			Event:           string(body),
+			Status:          api.WebhookDeliveryStatusPending,
+			CreationDate:    now,
+			NextAttemptDate: now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return
+	}
+
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		return dbCluster.CreateWebhookDeliveries(ctx, tx.Tx(), deliveries)
+	})
+	if err != nil {
+		webhookLogError(event, "Failed queuing webhook event", err)
+	}
+}
+
+// webhookLogError logs a failure to queue the given event. Failures on logging events aren't logged, as the log
+// entry would itself be sent back to the webhooks as a logging event and fail the same way.
+func webhookLogError(event api.Event, msg string, err error) {
+	if event.Type == api.EventTypeLogging {
+		return
+	}
+
+	logger.Warn(msg, logger.Ctx{"type": event.Type, "project": event.Project, "err": err})
+}
+
+// webhookDeliveryTask returns a task delivering the queued events of this member to their webhooks.
+func webhookDeliveryTask(dispatcher *webhookDispatcher) (task.Func, task.Schedule) {
+	f := func(ctx context.Context) {
+		err := dispatcher.reload(ctx)
+		if err != nil {
+			logger.Warn("Failed reloading webhooks", logger.Ctx{"err": err})
+			return
+		}
+
+		err = dispatcher.deliver(ctx)
+		if err != nil {
+			logger.Warn("Failed delivering webhook events", logger.Ctx{"err": err})
+		}
+	}
+
+	return f, task.Every(5 * time.Second)
+}
+
+// deliver attempts the due deliveries of this member, oldest first. The deliveries of a webhook are sent in order,
+// so the remaining deliveries of a webhook are postponed when one of them fails.
+func (w *webhookDispatcher) deliver(ctx context.Context) error {
+	s := w.stateFunc()
+	nodeID := s.DB.Cluster.GetNodeID()
+	now := time.Now().UTC()
+
+	var deliveries []dbCluster.WebhookDeliveryRow
+	var configs map[int64]map[string]string
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		var err error
+
+		// Remove the failed deliveries once they have been available for inspection long enough.
+		if now.Sub(w.lastPrune) > time.Hour {
+			err = dbCluster.DeleteFailedWebhookDeliveriesBefore(ctx, tx.Tx(), nodeID, now.Add(-webhookFailedExpiry))
+			if err != nil {
+				return err
+			}
+
+			w.lastPrune = now
+		}
+
+		deliveries, err = dbCluster.GetDueWebhookDeliveries(ctx, tx.Tx(), nodeID, now, webhookMaxPending)
+		if err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		configs, err = dbCluster.WebhooksConfigStore().GetAll(ctx, tx.Tx())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	failedWebhooks := map[int64]bool{}
+	for _, delivery := range deliveries {
+		if failedWebhooks[delivery.WebhookID] {
+			continue
+		}
+
+		sendErr := webhookSend(ctx, s, configs[delivery.WebhookID], delivery)
+		if sendErr != nil && ctx.Err() != nil {
+			// Shutting down, the delivery is attempted again on the next start.
+			return nil
+		}
+
+		attemptDate := time.Now().UTC()
+		err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+			if sendErr == nil {
+				err := dbCluster.DeleteWebhookDelivery(ctx, tx.Tx(), delivery)
+				if err != nil {
+					return err
+				}
+
+				return dbCluster.SetWebhookDelivered(ctx, tx.Tx(), delivery.WebhookID, attemptDate)
+			}
+
+			delivery.Attempts++
+			delivery.LastError = sendErr.Error()
+			delivery.NextAttemptDate = attemptDate.Add(webhookRetryDelay(delivery.Attempts))
+			if delivery.Attempts >= webhookMaxAttempts {
+				delivery.Status = api.WebhookDeliveryStatusFailed
+			}
+
+			err := dbCluster.UpdateWebhookDelivery(ctx, tx.Tx(), delivery)
+			if err != nil {
+				return err
+			}
+
+			return dbCluster.SetWebhookFailed(ctx, tx.Tx(), delivery.WebhookID, attemptDate, delivery.LastError)
+		})
+		if err != nil {
+			return fmt.Errorf("Failed updating webhook delivery %q: %w", delivery.UUID, err)
+		}
+
+		if sendErr != nil {
+			failedWebhooks[delivery.WebhookID] = true
+		}
+	}
+
+	return nil
+}
+
+// webhookRetryDelay returns the delay before the next attempt of a delivery that failed the given number of times.
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryInterval
+	for i := 1; i < attempts && delay < webhookRetryMaxInterval; i++ {
+		delay *= 2
+	}
+
+	return min(delay, webhookRetryMaxInterval)
+}
+
+// webhookSend sends a queued event to the webhook with the given configuration.
+func webhookSend(ctx context.Context, s *state.State, config map[string]string, delivery dbCluster.WebhookDeliveryRow) error {
+	if config["url"] == "" {
+		return errors.New("Webhook has no URL")
+	}
+
+	client, err := webhook.NewHTTPClient(s.Proxy, config["tls.ca"], webhookTimeout)
+	if err != nil {
+		return err
+	}
+
+	defer client.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config["url"], strings.NewReader(delivery.Event))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent)
+	req.Header.Set("X-LXD-Delivery", delivery.UUID)
+	req.Header.Set("X-LXD-Event", delivery.EventType)
+
+	if config["secret"] != "" {
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(config["secret"], []byte(delivery.Event)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
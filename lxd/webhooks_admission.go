@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/lxd/request"
+	"github.com/canonical/lxd/lxd/state"
+	"github.com/canonical/lxd/lxd/webhook"
+	"github.com/canonical/lxd/shared"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/canonical/lxd/shared/entity"
+)
+
+// webhookAdmissionHooks returns the admission webhooks reviewing the given operation on an entity of the given type
+// and project, in name order.
+func webhookAdmissionHooks(ctx context.Context, s *state.State, entityType entity.Type, operation string, projectName string) ([]webhook.Hook, error) {
+	var hooks []webhook.Hook
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		webhooks, err := dbCluster.GetWebhooks(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		if len(webhooks) == 0 {
+			return nil
+		}
+
+		configs, err := dbCluster.WebhooksConfigStore().GetAll(ctx, tx.Tx())
+		if err != nil {
+			return err
+		}
+
+		for _, row := range webhooks {
+			config := configs[row.ID]
+			if config["type"] != api.WebhookTypeAdmission {
+				continue
+			}
+
+			entities := shared.SplitNTrimSpace(config["admission.entities"], ",", -1, true)
+			if len(entities) > 0 && !slices.Contains(entities, string(entityType)) {
+				continue
+			}
+
+			operations := shared.SplitNTrimSpace(config["admission.operations"], ",", -1, true)
+			if len(operations) > 0 && !slices.Contains(operations, operation) {
+				continue
+			}
+
+			projects := shared.SplitNTrimSpace(config["projects"], ",", -1, true)
+			if len(projects) > 0 && !slices.Contains(projects, projectName) {
+				continue
+			}
+
+			hook := webhook.Hook{
+				Name:          row.Name,
+				URL:           config["url"],
+				Secret:        config["secret"],
+				CACert:        config["tls.ca"],
+				Timeout:       webhookAdmissionDefaultTimeout,
+				FailurePolicy: config["admission.failure_policy"],
+				Proxy:         s.Proxy,
+			}
+
+			if hook.FailurePolicy == "" {
+				hook.FailurePolicy = webhook.FailurePolicyFail
+			}
+
+			if config["admission.timeout"] != "" {
+				timeout, err := strconv.Atoi(config["admission.timeout"])
+				if err != nil {
+					return fmt.Errorf("Invalid admission timeout of webhook %q: %w", row.Name, err)
+				}
+
+				hook.Timeout = time.Duration(timeout) * time.Second
+			}
+
+			hooks = append(hooks, hook)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Failed loading admission webhooks: %w", err)
+	}
+
+	return hooks, nil
+}
+
+// webhookAdmit sends the proposed object of a request creating or updating an entity to the matching admission
+// webhooks. The object is replaced with the object patched by the webhooks, and an error is returned if any of the
+// webhooks rejects the request.
+// Notifications from other cluster members are not reviewed again, as the request was reviewed by the member that
+// received it.
+func webhookAdmit[T any](r *http.Request, s *state.State, entityType entity.Type, operation string, projectName string, entityURL *api.URL, object *T) error {
+	requestor, err := request.GetRequestor(r.Context())
+	if err == nil && (requestor.IsClusterNotification() || requestor.IsClusterOperationNotification()) {
+		return nil
+	}
+
+	hooks, err := webhookAdmissionHooks(r.Context(), s, entityType, operation, projectName)
+	if err != nil {
+		return err
+	}
+
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+
+	review := api.WebhookAdmissionRequest{
+		UUID:       uuid.New().String(),
+		Operation:  operation,
+		EntityType: string(entityType),
+		EntityURL:  entityURL.String(),
+		Project:    projectName,
+		Requestor:  request.CreateRequestor(r.Context()),
+		Object:     body,
+	}
+
+	patched, err := webhook.Review(r.Context(), hooks, review)
+	if err != nil {
+		return err
+	}
+
+	var result T
+	err = json.Unmarshal(patched, &result)
+	if err != nil {
+		return api.StatusErrorf(http.StatusBadRequest, "Invalid object returned by admission webhooks: %w", err)
+	}
+
+	*object = result
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/canonical/lxd/lxd/db"
+	dbCluster "github.com/canonical/lxd/lxd/db/cluster"
+	"github.com/canonical/lxd/shared/api"
+)
+
+func webhookTestLifecycleEvent(t *testing.T, projectName string, action string, source string) api.Event {
+	metadata, err := json.Marshal(api.EventLifecycle{Action: action, Source: source})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return api.Event{Type: api.EventTypeLifecycle, Project: projectName, Metadata: metadata}
+}
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	started := webhookTestLifecycleEvent(t, "p1", "instance-started", "/1.0/instances/c1")
+	security := api.Event{Type: api.EventTypeSecurity}
+
+	tests := []struct {
+		name         string
+		subscription webhookSubscription
+		event        api.Event
+		want         bool
+	}{
+		{
+			name:         "Matching type",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}},
+			event:        started,
+			want:         true,
+		},
+		{
+			name:         "Other type",
+			subscription: webhookSubscription{types: []string{api.EventTypeLogging}},
+			event:        started,
+		},
+		{
+			name:         "Matching project",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, projects: []string{"default", "p1"}},
+			event:        started,
+			want:         true,
+		},
+		{
+			name:         "Other project",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, projects: []string{"default"}},
+			event:        started,
+		},
+		{
+			name:         "Event without project",
+			subscription: webhookSubscription{types: []string{api.EventTypeSecurity}, projects: []string{"default"}},
+			event:        security,
+			want:         true,
+		},
+		{
+			name:         "Matching action",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, actions: []string{"instance-stopped", "instance-started"}},
+			event:        started,
+			want:         true,
+		},
+		{
+			name:         "Matching action prefix",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, actions: []string{"instance-*"}},
+			event:        started,
+			want:         true,
+		},
+		{
+			name:         "Other action",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, actions: []string{"instance-stopped", "image-*"}},
+			event:        started,
+		},
+		{
+			name:         "Actions don't filter other types",
+			subscription: webhookSubscription{types: []string{api.EventTypeSecurity}, actions: []string{"instance-stopped"}},
+			event:        security,
+			want:         true,
+		},
+		{
+			name:         "Invalid lifecycle metadata",
+			subscription: webhookSubscription{types: []string{api.EventTypeLifecycle}, actions: []string{"instance-*"}},
+			event:        api.Event{Type: api.EventTypeLifecycle, Metadata: json.RawMessage(`"invalid"`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.subscription.matches(tt.event))
+		})
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 9, want: 2560 * time.Second},
+		{attempts: 10, want: time.Hour},
+		{attempts: 100, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, webhookRetryDelay(tt.attempts), "attempts=%d", tt.attempts)
+	}
+}
+
+// webhookTestServer is a webhook endpoint recording the actions of the lifecycle events it receives.
+type webhookTestServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	actions []string
+}
+
+func newWebhookTestServer(statusCode int) *webhookTestServer {
+	w := &webhookTestServer{}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		event := api.Event{}
+		lifecycleEvent := api.EventLifecycle{}
+		_ = json.Unmarshal(body, &event)
+		_ = json.Unmarshal(event.Metadata, &lifecycleEvent)
+
+		w.mu.Lock()
+		w.actions = append(w.actions, lifecycleEvent.Action)
+		w.mu.Unlock()
+
+		rw.WriteHeader(statusCode)
+	}))
+
+	return w
+}
+
+func (w *webhookTestServer) received() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]string{}, w.actions...)
+}
+
+type webhooksTestSuite struct {
+	lxdTestSuite
+}
+
+// TestWebhookDeliver tests that queued events are delivered in order, and that the deliveries of a failing webhook
+// are postponed without holding back the other webhooks.
+func (suite *webhooksTestSuite) TestWebhookDeliver() {
+	ctx := context.Background()
+	s := suite.d.State()
+
+	up := newWebhookTestServer(http.StatusOK)
+	defer up.Close()
+
+	down := newWebhookTestServer(http.StatusInternalServerError)
+	defer down.Close()
+
+	webhooks := map[string]map[string]string{
+		"up":        {"url": up.URL, "lifecycle.actions": "instance-*"},
+		"down":      {"url": down.URL},
+		"admission": {"url": up.URL, "type": api.WebhookTypeAdmission},
+	}
+
+	ids := map[string]int64{}
+	err := s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		for name, config := range webhooks {
+			id, err := dbCluster.CreateWebhook(ctx, tx.Tx(), dbCluster.WebhookRow{Name: name})
+			if err != nil {
+				return err
+			}
+
+			err = dbCluster.WebhooksConfigStore().Set(ctx, tx.Tx(), id, config)
+			if err != nil {
+				return err
+			}
+
+			ids[name] = id
+		}
+
+		return nil
+	})
+	suite.Req.NoError(err)
+
+	dispatcher := newWebhookDispatcher(suite.d.State, nil)
+	suite.Req.NoError(dispatcher.reload(ctx))
+
+	// Admission webhooks don't receive events.
+	suite.Len(dispatcher.subscriptions, 2)
+
+	dispatcher.enqueue(ctx, webhookTestLifecycleEvent(suite.T(), "default", "instance-created", "/1.0/instances/c1"))
+	dispatcher.enqueue(ctx, webhookTestLifecycleEvent(suite.T(), "default", "image-created", "/1.0/images/i1"))
+	dispatcher.enqueue(ctx, webhookTestLifecycleEvent(suite.T(), "default", "instance-started", "/1.0/instances/c1"))
+	dispatcher.enqueue(ctx, webhookTestLifecycleEvent(suite.T(), "default", "instance-stopped", "/1.0/instances/c1"))
+
+	suite.Req.NoError(dispatcher.deliver(ctx))
+
+	// The events are received in order by the webhook that is up, and only its actions are queued.
+	suite.Equal([]string{"instance-created", "instance-started", "instance-stopped"}, up.received())
+
+	// The webhook that is down only gets the first event, the others wait for it to be delivered.
+	suite.Equal([]string{"instance-created"}, down.received())
+
+	// Nothing is due until the failed delivery is retried.
+	suite.Req.NoError(dispatcher.deliver(ctx))
+	suite.Len(up.received(), 3)
+	suite.Len(down.received(), 1)
+
+	var upDeliveries, downDeliveries []dbCluster.WebhookDelivery
+	var upStatus, downStatus *dbCluster.WebhookStatusRow
+	err = s.DB.Cluster.Transaction(ctx, func(ctx context.Context, tx *db.ClusterTx) error {
+		upDeliveries, err = dbCluster.GetWebhookDeliveries(ctx, tx.Tx(), "up")
+		if err != nil {
+			return err
+		}
+
+		downDeliveries, err = dbCluster.GetWebhookDeliveries(ctx, tx.Tx(), "down")
+		if err != nil {
+			return err
+		}
+
+		upStatus, err = dbCluster.GetWebhookStatus(ctx, tx.Tx(), ids["up"])
+		if err != nil {
+			return err
+		}
+
+		downStatus, err = dbCluster.GetWebhookStatus(ctx, tx.Tx(), ids["down"])
+		return err
+	})
+	suite.Req.NoError(err)
+
+	// Delivered events are removed from the queue.
+	suite.Empty(upDeliveries)
+	suite.True(upStatus.LastDeliveryDate.Valid)
+
+	// The failed delivery is scheduled for a retry, and the others are left untouched.
+	suite.Req.Len(downDeliveries, 4)
+	first := downDeliveries[0].Row
+	suite.Equal(api.WebhookDeliveryStatusPending, string(first.Status))
+	suite.Equal(1, first.Attempts)
+	suite.Equal("Unexpected status code 500", first.LastError)
+	suite.True(first.NextAttemptDate.After(time.Now()))
+
+	for _, delivery := range downDeliveries[1:] {
+		suite.Equal(0, delivery.Row.Attempts)
+	}
+
+	suite.False(downStatus.LastDeliveryDate.Valid)
+	suite.True(downStatus.LastFailureDate.Valid)
+	suite.Equal("Unexpected status code 500", downStatus.LastError)
+}
+
+func TestWebhooksTestSuite(t *testing.T) {
+	suite.Run(t, new(webhooksTestSuite))
+}
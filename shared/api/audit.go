@@ -0,0 +1,52 @@
+package api
+
+import (
+	"time"
+)
+
+// AuditEntry represents an entry of the audit log.
+//
+// swagger:model
+//
+// API extension: audit_log.
+type AuditEntry struct {
+	// When the request was handled
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	Date time.Time `json:"date" yaml:"date"`
+
+	// What cluster member handled the request
+	// Example: node1
+	Location string `json:"location" yaml:"location"`
+
+	// Identifier of the requestor (username, certificate fingerprint or email address)
+	// Example: 2e28a8a1b6d1ec8ea2bdb88ad7b5d12a3ea7dc2a6f7e3f48d1c4cd1f12c7e2d6
+	Identity string `json:"identity" yaml:"identity"`
+
+	// Authentication protocol of the requestor
+	// Example: tls
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Address the request came from
+	// Example: 10.0.2.15:48532
+	SourceAddress string `json:"source_address" yaml:"source_address"`
+
+	// HTTP method of the request
+	// Example: POST
+	Method string `json:"method" yaml:"method"`
+
+	// Project the request applied to
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// URL of the entity targeted by the request
+	// Example: /1.0/instances/c1?project=default
+	EntityURL string `json:"entity_url" yaml:"entity_url"`
+
+	// SHA-256 hash of the request body
+	// Example: e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855
+	RequestHash string `json:"request_hash" yaml:"request_hash"`
+
+	// HTTP status code of the response, or the final status code of the operation for asynchronous requests
+	// Example: 200
+	StatusCode int `json:"status_code" yaml:"status_code"`
+}
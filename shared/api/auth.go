@@ -59,6 +59,9 @@ const (
 
 	// IdentityTypeCertificateClusterLinkPending represents cluster links for which a token has been issued but who have not yet authenticated with a linked LXD cluster.
 	IdentityTypeCertificateClusterLinkPending = "Cluster link certificate (pending)"
+
+	// IdentityTypeBearerTokenSCIM represents an identity that bears a LXD token that can be used to interact with the SCIM provisioning API.
+	IdentityTypeBearerTokenSCIM = "SCIM token bearer"
 )
 
 // WithEntitlements is meant to be an embedded struct to API types eligible for entitlement enrichment,
@@ -120,6 +123,12 @@ type Identity struct {
 	//
 	// API extension: access_management_expiry.
 	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
+	// Config is the configuration of the identity.
+	// Example: {"limits.api.requests.rate": "10"}
+	//
+	// API extension: api_rate_limits.
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // Writable converts a Identity struct into a IdentityPut struct (filters read-only fields).
@@ -127,6 +136,7 @@ func (i Identity) Writable() IdentityPut {
 	return IdentityPut{
 		Groups:         i.Groups,
 		TLSCertificate: i.TLSCertificate,
+		Config:         i.Config,
 	}
 }
 
@@ -134,6 +144,7 @@ func (i Identity) Writable() IdentityPut {
 func (i *Identity) SetWritable(put IdentityPut) {
 	i.Groups = put.Groups
 	i.TLSCertificate = put.TLSCertificate
+	i.Config = put.Config
 }
 
 // IdentityInfo expands an Identity to include effective group membership and effective permissions.
@@ -173,6 +184,12 @@ type IdentityPut struct {
 	//
 	// API extension: access_management_tls.
 	TLSCertificate string `json:"tls_certificate" yaml:"tls_certificate"`
+
+	// Config is the configuration of the identity.
+	// Example: {"limits.api.requests.rate": "10"}
+	//
+	// API extension: api_rate_limits.
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // IdentitiesTLSPost contains required information for the creation of a TLS identity.
@@ -265,6 +282,12 @@ type AuthGroup struct {
 	// includes this group.
 	// Example: ["sales", "operations"]
 	IdentityProviderGroups []string `json:"identity_provider_groups" yaml:"identity_provider_groups"`
+
+	// Config is the configuration of the group.
+	// Example: {"limits.api.operations": "5"}
+	//
+	// API extension: api_rate_limits.
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // Writable converts a AuthGroup struct into a AuthGroupPut struct (filters read-only fields).
@@ -272,6 +295,7 @@ func (g AuthGroup) Writable() AuthGroupPut {
 	return AuthGroupPut{
 		Description: g.Description,
 		Permissions: g.Permissions,
+		Config:      g.Config,
 	}
 }
 
@@ -279,6 +303,7 @@ func (g AuthGroup) Writable() AuthGroupPut {
 func (g *AuthGroup) SetWritable(put AuthGroupPut) {
 	g.Description = put.Description
 	g.Permissions = put.Permissions
+	g.Config = put.Config
 }
 
 // AuthGroupsPost is used for creating a new group.
@@ -314,6 +339,12 @@ type AuthGroupPut struct {
 
 	// Permissions are a list of permissions.
 	Permissions []Permission `json:"permissions" yaml:"permissions"`
+
+	// Config is the configuration of the group.
+	// Example: {"limits.api.operations": "5"}
+	//
+	// API extension: api_rate_limits.
+	Config map[string]string `json:"config" yaml:"config"`
 }
 
 // IdentityProviderGroup represents a mapping between LXD groups and groups defined by an identity provider.
@@ -0,0 +1,68 @@
+package api
+
+// ClusterRebalanceMember represents the load of a cluster member as seen by the cluster rebalancer.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance.
+type ClusterRebalanceMember struct {
+	// Name of the cluster member
+	// Example: server01
+	Name string `json:"name" yaml:"name"`
+
+	// CPU usage of the instances on the cluster member (per logical CPU, in percent)
+	// Example: 85.5
+	CPU float64 `json:"cpu" yaml:"cpu"`
+
+	// Memory usage of the instances on the cluster member (share of the member's memory, in percent)
+	// Example: 60.2
+	Memory float64 `json:"memory" yaml:"memory"`
+
+	// Highest share of time some tasks of an instance on the cluster member were stalled on a resource (in percent)
+	// Example: 12.5
+	Pressure float64 `json:"pressure" yaml:"pressure"`
+
+	// Load score of the cluster member (the highest of the CPU, memory and pressure values)
+	// Example: 85.5
+	Score float64 `json:"score" yaml:"score"`
+}
+
+// ClusterRebalanceMove represents an instance move decided by the cluster rebalancer.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance.
+type ClusterRebalanceMove struct {
+	// Project of the instance
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Name of the instance
+	// Example: c1
+	Instance string `json:"instance" yaml:"instance"`
+
+	// Cluster member currently running the instance
+	// Example: server01
+	Source string `json:"source" yaml:"source"`
+
+	// Cluster member the instance is moved to
+	// Example: server02
+	Target string `json:"target" yaml:"target"`
+
+	// Whether the instance is live-migrated
+	// Example: true
+	Live bool `json:"live" yaml:"live"`
+}
+
+// ClusterRebalancePlan represents the moves that the cluster rebalancer would perform.
+//
+// swagger:model
+//
+// API extension: cluster_rebalance.
+type ClusterRebalancePlan struct {
+	// Load of each online cluster member
+	Members []ClusterRebalanceMember `json:"members" yaml:"members"`
+
+	// Instance moves, in the order they would be performed
+	Moves []ClusterRebalanceMove `json:"moves" yaml:"moves"`
+}
@@ -26,4 +26,32 @@ type ClusterMemberSysInfo struct {
 type ClusterMemberState struct {
 	SysInfo      ClusterMemberSysInfo        `json:"sysinfo" yaml:"sysinfo"`
 	StoragePools map[string]StoragePoolState `json:"storage_pools" yaml:"storage_pools"`
+
+	// Resources reserved by instances on the cluster member
+	//
+	// API extension: instance_reservations
+	Reservations ClusterMemberReservations `json:"reservations" yaml:"reservations"`
+}
+
+// ClusterMemberReservations represents the resources reserved by instances on a cluster member.
+//
+// swagger:model
+//
+// API extension: instance_reservations.
+type ClusterMemberReservations struct {
+	// Number of CPUs reserved by instances
+	// Example: 6
+	CPUCommitted int64 `json:"cpu_committed" yaml:"cpu_committed"`
+
+	// Number of CPUs that can be reserved (-1 if unknown)
+	// Example: 16
+	CPUAllocatable int64 `json:"cpu_allocatable" yaml:"cpu_allocatable"`
+
+	// Memory reserved by instances (in bytes)
+	// Example: 8589934592
+	MemoryCommitted int64 `json:"memory_committed" yaml:"memory_committed"`
+
+	// Memory that can be reserved (in bytes, -1 if unknown)
+	// Example: 34359738368
+	MemoryAllocatable int64 `json:"memory_allocatable" yaml:"memory_allocatable"`
 }
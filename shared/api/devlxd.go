@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // DevLXDResponse represents the response from the devLXD API.
@@ -106,3 +107,18 @@ type DevLXDUbuntuProSettings struct {
 	// Example: on
 	GuestAttach string `json:"guest_attach"`
 }
+
+// DevLXDIdentityToken is a signed identity token issued to an instance.
+//
+// API extension: instance_identity_tokens.
+type DevLXDIdentityToken struct {
+	// Token is the signed JSON Web Token.
+	//
+	// Example: eyJhbGciOiJFUzI1NiIsImtpZCI6Ii4uLiIsInR5cCI6IkpXVCJ9...
+	Token string `json:"token"`
+
+	// ExpiresAt is the time at which the token expires.
+	//
+	// Example: 2025-03-23T20:00:00Z
+	ExpiresAt time.Time `json:"expires_at"`
+}
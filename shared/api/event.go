@@ -42,6 +42,13 @@ type Event struct {
 	//
 	// API extension: event_project
 	Project string `yaml:"project,omitempty" json:"project,omitempty"`
+
+	// ID of the event in the event stream of the cluster member the client is connected to, made of the boot ID
+	// of the member and of the sequence number of the event
+	// Example: 5c8c2b0e-5b8a-4a4f-9c43-1c5e0d3f2a61:1042
+	//
+	// API extension: event_history
+	ID string `yaml:"id,omitempty" json:"id,omitempty"`
 }
 
 // owaspLevelToLogrusLevel maps OWASP security event levels to logrus-compatible level strings.
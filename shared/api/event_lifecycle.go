@@ -15,6 +15,8 @@ const (
 	EventLifecycleClusterMemberAdded                = "cluster-member-added"
 	EventLifecycleClusterMemberEvacuated            = "cluster-member-evacuated"
 	EventLifecycleClusterMemberHealed               = "cluster-member-healed"
+	EventLifecycleClusterMemberRebalancePlanned     = "cluster-member-rebalance-planned"
+	EventLifecycleClusterMemberRebalanced           = "cluster-member-rebalanced"
 	EventLifecycleClusterMemberRemoved              = "cluster-member-removed"
 	EventLifecycleClusterMemberRenamed              = "cluster-member-renamed"
 	EventLifecycleClusterMemberRestored             = "cluster-member-restored"
@@ -38,6 +40,7 @@ const (
 	EventLifecycleImageDeleted                      = "image-deleted"
 	EventLifecycleImageRefreshed                    = "image-refreshed"
 	EventLifecycleImageRetrieved                    = "image-retrieved"
+	EventLifecycleImageSBOMGenerated                = "image-sbom-generated"
 	EventLifecycleImageSecretCreated                = "image-secret-created"
 	EventLifecycleImageUpdated                      = "image-updated"
 	EventLifecycleInstanceBackupCreated             = "instance-backup-created"
@@ -53,6 +56,7 @@ const (
 	EventLifecycleInstanceFileDeleted               = "instance-file-deleted"
 	EventLifecycleInstanceFilePushed                = "instance-file-pushed"
 	EventLifecycleInstanceFileRetrieved             = "instance-file-retrieved"
+	EventLifecycleInstanceForked                    = "instance-forked"
 	EventLifecycleInstanceLogDeleted                = "instance-log-deleted"
 	EventLifecycleInstanceLogRetrieved              = "instance-log-retrieved"
 	EventLifecycleInstanceMetadataRetrieved         = "instance-metadata-retrieved"
@@ -98,13 +102,19 @@ const (
 	EventLifecycleNetworkZoneRecordDeleted          = "network-zone-record-deleted"
 	EventLifecycleNetworkZoneRecordUpdated          = "network-zone-record-updated"
 	EventLifecycleNetworkZoneUpdated                = "network-zone-updated"
+	EventLifecycleOperationApproved                 = "operation-approved"
 	EventLifecycleOperationCancelled                = "operation-cancelled"
+	EventLifecycleOperationRejected                 = "operation-rejected"
 	EventLifecycleProfileCreated                    = "profile-created"
 	EventLifecycleProfileDeleted                    = "profile-deleted"
 	EventLifecycleProfileRenamed                    = "profile-renamed"
 	EventLifecycleProfileUpdated                    = "profile-updated"
 	EventLifecycleProjectCreated                    = "project-created"
 	EventLifecycleProjectDeleted                    = "project-deleted"
+	EventLifecycleProjectFenced                     = "project-fenced"
+	EventLifecycleProjectLeaderReachable            = "project-leader-reachable"
+	EventLifecycleProjectLeaderUnreachable          = "project-leader-unreachable"
+	EventLifecycleProjectPromoted                   = "project-promoted"
 	EventLifecycleProjectRenamed                    = "project-renamed"
 	EventLifecycleProjectUpdated                    = "project-updated"
 	EventLifecycleStoragePoolCreated                = "storage-pool-created"
@@ -132,6 +142,10 @@ const (
 	EventLifecycleWarningAcknowledged               = "warning-acknowledged"
 	EventLifecycleWarningDeleted                    = "warning-deleted"
 	EventLifecycleWarningReset                      = "warning-reset"
+	EventLifecycleWebhookCreated                    = "webhook-created"
+	EventLifecycleWebhookDeleted                    = "webhook-deleted"
+	EventLifecycleWebhookRenamed                    = "webhook-renamed"
+	EventLifecycleWebhookUpdated                    = "webhook-updated"
 	EventLifecycleIdentityCreated                   = "identity-created"
 	EventLifecycleIdentityUpdated                   = "identity-updated"
 	EventLifecycleIdentityDeleted                   = "identity-deleted"
@@ -0,0 +1,77 @@
+package api
+
+// JSONWebKey is a public key used to verify instance identity tokens (RFC 7517).
+//
+// swagger:model
+//
+// API extension: instance_identity_tokens.
+type JSONWebKey struct {
+	// Key type
+	// Example: EC
+	KeyType string `json:"kty" yaml:"kty"`
+
+	// Intended use of the key
+	// Example: sig
+	Use string `json:"use" yaml:"use"`
+
+	// Key ID (RFC 7638 thumbprint of the key)
+	// Example: 1Bzr8Vf7tVY6e1Xq5hPvQ2m0XjRw4DZ9mAfMCrPkbVc
+	KeyID string `json:"kid" yaml:"kid"`
+
+	// Signing algorithm used with the key
+	// Example: ES256
+	Algorithm string `json:"alg" yaml:"alg"`
+
+	// Elliptic curve of the key
+	// Example: P-256
+	Curve string `json:"crv" yaml:"crv"`
+
+	// X coordinate of the public key (base64url encoded)
+	// Example: f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU
+	X string `json:"x" yaml:"x"`
+
+	// Y coordinate of the public key (base64url encoded)
+	// Example: x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0
+	Y string `json:"y" yaml:"y"`
+}
+
+// JSONWebKeySet is the set of public keys used to verify instance identity tokens (RFC 7517).
+//
+// swagger:model
+//
+// API extension: instance_identity_tokens.
+type JSONWebKeySet struct {
+	// Public keys
+	Keys []JSONWebKey `json:"keys" yaml:"keys"`
+}
+
+// OpenIDConfiguration is the OpenID Connect discovery document of the instance identity token issuer.
+//
+// swagger:model
+//
+// API extension: instance_identity_tokens.
+type OpenIDConfiguration struct {
+	// Issuer URL
+	// Example: https://lxd.example.net:8443
+	Issuer string `json:"issuer" yaml:"issuer"`
+
+	// URL of the JSON Web Key Set
+	// Example: https://lxd.example.net:8443/.well-known/jwks.json
+	JWKSURI string `json:"jwks_uri" yaml:"jwks_uri"`
+
+	// Supported response types
+	// Example: ["id_token"]
+	ResponseTypesSupported []string `json:"response_types_supported" yaml:"response_types_supported"`
+
+	// Supported subject identifier types
+	// Example: ["public"]
+	SubjectTypesSupported []string `json:"subject_types_supported" yaml:"subject_types_supported"`
+
+	// Supported token signing algorithms
+	// Example: ["ES256"]
+	IDTokenSigningAlgValuesSupported []string `json:"id_token_signing_alg_values_supported" yaml:"id_token_signing_alg_values_supported"`
+
+	// Claims that tokens may contain
+	// Example: ["iss", "sub", "aud", "exp", "iat", "project", "instance"]
+	ClaimsSupported []string `json:"claims_supported" yaml:"claims_supported"`
+}
@@ -62,6 +62,12 @@ type ImagesPost struct {
 	//
 	// API extension: image_create_aliases
 	Aliases []ImageAlias `json:"aliases" yaml:"aliases"`
+
+	// Whether to sign the image with the image signing key of the server (only for images published from instances)
+	// Example: true
+	//
+	// API extension: image_signatures
+	Sign bool `json:"sign" yaml:"sign"`
 }
 
 // ImagesPostSource represents the source of a new LXD image
@@ -74,7 +80,7 @@ type ImagesPostSource struct {
 	// Example: pull
 	Mode string `json:"mode" yaml:"mode"`
 
-	// Type of image source (instance, snapshot, or image)
+	// Type of image source (instance, snapshot, image or build)
 	// Example: instance
 	Type SourceType `json:"type" yaml:"type"`
 
@@ -101,6 +107,75 @@ type ImagesPostSource struct {
 	//
 	// API extension: image_source_project
 	Project string `json:"project" yaml:"project"`
+
+	// Build recipe (for type "build")
+	//
+	// API extension: image_build
+	Build *ImageBuildRecipe `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+// ImageBuildRecipe represents the steps used to build a new LXD image
+//
+// swagger:model
+//
+// API extension: image_build.
+type ImageBuildRecipe struct {
+	// Image to build from (with type "image")
+	Source InstanceSource `json:"source" yaml:"source"`
+
+	// Type of the build instance (container or virtual-machine)
+	// Example: container
+	Type InstanceType `json:"type" yaml:"type"`
+
+	// List of profiles applied to the build instance
+	// Example: ["default"]
+	Profiles []string `json:"profiles" yaml:"profiles"`
+
+	// Configuration of the build instance
+	// Example: {"limits.cpu": "4"}
+	Config map[string]string `json:"config" yaml:"config"`
+
+	// Environment variables set for the commands
+	// Example: {"DEBIAN_FRONTEND": "noninteractive"}
+	Environment map[string]string `json:"environment" yaml:"environment"`
+
+	// Files to write into the build instance before running the commands
+	Files []ImageBuildFile `json:"files" yaml:"files"`
+
+	// Commands to run in order, each through "/bin/sh -c"
+	// Example: ["apt-get update", "apt-get install -y nginx"]
+	Commands []string `json:"commands" yaml:"commands"`
+
+	// Commands to run after all other commands have succeeded and before publishing the image
+	// Example: ["apt-get clean", "rm -rf /var/lib/apt/lists/*"]
+	Cleanup []string `json:"cleanup" yaml:"cleanup"`
+}
+
+// ImageBuildFile represents a file written into the build instance
+//
+// swagger:model
+//
+// API extension: image_build.
+type ImageBuildFile struct {
+	// Absolute path of the file in the build instance
+	// Example: /etc/motd
+	Path string `json:"path" yaml:"path"`
+
+	// File content
+	// Example: Welcome!
+	Content string `json:"content" yaml:"content"`
+
+	// File mode in octal
+	// Example: 0644
+	Mode string `json:"mode" yaml:"mode"`
+
+	// Owner user ID
+	// Example: 0
+	UID int64 `json:"uid" yaml:"uid"`
+
+	// Owner group ID
+	// Example: 0
+	GID int64 `json:"gid" yaml:"gid"`
 }
 
 // ImagePut represents the modifiable fields of a LXD image
@@ -223,6 +298,11 @@ type Image struct {
 	//
 	// API extension: image_extended_metadata
 	ReleaseTitle string `json:"release_title,omitempty" yaml:"release_title,omitempty"`
+
+	// Signatures of the image
+	//
+	// API extension: image_signatures
+	Signatures []ImageSignature `json:"signatures" yaml:"signatures"`
 }
 
 // Writable converts a full Image struct into a ImagePut struct (filters read-only fields).
@@ -250,6 +330,99 @@ func (img *Image) URL(apiVersion string, project string) *URL {
 	return NewURL().Path(apiVersion, "images", img.Fingerprint).Project(project)
 }
 
+// ImageSignature represents a signature of a LXD image
+//
+// swagger:model
+//
+// API extension: image_signatures.
+type ImageSignature struct {
+	// SHA-256 fingerprint of the public key (hash of its DER encoding)
+	// Example: 2b2f5a5e1d1c1b4d54a1c2a1fb22fe1a7a4d3f9a7c1e5b6d8c0d0a4f6d5e3c2b
+	KeyFingerprint string `json:"key_fingerprint" yaml:"key_fingerprint"`
+
+	// Public key used to verify the signature (base64 encoded DER)
+	// Example: MCowBQYDK2VwAyEA3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// Signature of the image fingerprint (base64 encoded)
+	// Example: 6sbv0aJv4YoO3N0Jm6d1U3u5cW9f6fQq3oE3kq2X2oVh2pJt9cF6lXy3cD2kZ8n9x0A6oB1cZ3dW4eE5fF6gGw==
+	Signature string `json:"signature" yaml:"signature"`
+}
+
+// ImageSignaturesPost represents a new signature of a LXD image
+//
+// swagger:model
+//
+// API extension: image_signatures.
+type ImageSignaturesPost struct {
+	// Signature made by the client
+	Signature *ImageSignature `json:"signature" yaml:"signature"`
+}
+
+// ImageSigningKey represents the key used by the server to sign images
+//
+// swagger:model
+//
+// API extension: image_signatures.
+type ImageSigningKey struct {
+	// SHA-256 fingerprint of the public key (hash of its DER encoding)
+	// Example: 2b2f5a5e1d1c1b4d54a1c2a1fb22fe1a7a4d3f9a7c1e5b6d8c0d0a4f6d5e3c2b
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// Public key (base64 encoded DER)
+	// Example: MCowBQYDK2VwAyEA3p7bfXt9wbTTW2HC7OQ1Nz+DQ8hbeGdNrfx+FG+IK08=
+	PublicKey string `json:"public_key" yaml:"public_key"`
+
+	// When the key was created
+	// Example: 2026-10-18T15:04:05Z
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// SBOM document formats.
+//
+// API extension: image_sbom.
+const (
+	// SBOMFormatSPDX is the SPDX 2.3 JSON format.
+	SBOMFormatSPDX = "spdx"
+
+	// SBOMFormatCycloneDX is the CycloneDX 1.5 JSON format.
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// ImageSBOMPost represents a request to regenerate the software bill of materials of a LXD image
+//
+// swagger:model
+//
+// API extension: image_sbom.
+type ImageSBOMPost struct {
+	// Format of the SBOM document to generate (spdx or cyclonedx)
+	// Example: spdx
+	Format string `json:"format" yaml:"format"`
+}
+
+// ImageRetentionCandidate represents an image that the retention policy of its project deletes
+//
+// swagger:model
+//
+// API extension: image_retention.
+type ImageRetentionCandidate struct {
+	// Image fingerprint
+	// Example: 06b86454720d36b20f94e31c6812e05ec51c1b568cf3a8abd273769d213394bb
+	Fingerprint string `json:"fingerprint" yaml:"fingerprint"`
+
+	// Project of the image
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// When the image was added to the image store
+	// Example: 2026-10-18T12:00:00Z
+	UploadedAt time.Time `json:"uploaded_at" yaml:"uploaded_at"`
+
+	// Why the image is deleted
+	// Example: Exceeds the 3 versions kept for alias "nightly"
+	Reason string `json:"reason" yaml:"reason"`
+}
+
 // ImageAlias represents an alias from the alias list of a LXD image
 //
 // swagger:model
@@ -80,6 +80,12 @@ type InstancesPost struct {
 type InstancesPut struct {
 	// Desired runtime state
 	State *InstanceStatePut `json:"state" yaml:"state"`
+
+	// Names of the instances to update (all instances of the project if empty)
+	// Example: ["db", "app"]
+	//
+	// API extension: instance_boot_dependencies
+	Instances []string `json:"instances,omitempty" yaml:"instances,omitempty"`
 }
 
 // InstancePost represents the fields required to rename/move a LXD instance.
@@ -154,6 +160,12 @@ type InstancePost struct {
 	//
 	// API extension: override_snapshot_profiles_on_copy
 	OverrideSnapshotProfiles bool `json:"override_snapshot_profiles" yaml:"override_snapshot_profiles"`
+
+	// Whether to keep the source instance when migrating to another cluster through a cluster link
+	// Example: false
+	//
+	// API extension: instance_move_cluster_link
+	KeepSource bool `json:"keep_source" yaml:"keep_source"`
 }
 
 // InstancePostTarget represents the migration target host and operation.
@@ -240,6 +252,21 @@ type InstanceRebuildPost struct {
 	Source InstanceSource `json:"source" yaml:"source"`
 }
 
+// InstanceForkPost represents the fields required to fork a running instance.
+//
+// swagger:model
+//
+// API extension: instance_fork.
+type InstanceForkPost struct {
+	// Names of the instances to create from the running instance
+	// Example: ["runner-1", "runner-2"]
+	Names []string `json:"names" yaml:"names"`
+
+	// Whether the new instances are ephemeral (deleted on shutdown)
+	// Example: true
+	Ephemeral bool `json:"ephemeral" yaml:"ephemeral"`
+}
+
 // Instance represents a LXD instance.
 //
 // swagger:model
@@ -0,0 +1,20 @@
+package api
+
+// InstancePortForwardPost represents a LXD instance port forwarding request.
+//
+// swagger:model
+//
+// API extension: instance_port_forward.
+type InstancePortForwardPost struct {
+	// Protocol of the port to forward (tcp or udp)
+	// Example: tcp
+	Protocol string `json:"protocol" yaml:"protocol"`
+
+	// Address to connect to inside the instance (defaults to 127.0.0.1)
+	// Example: 127.0.0.1
+	Address string `json:"address" yaml:"address"`
+
+	// Port to connect to inside the instance
+	// Example: 80
+	Port int `json:"port" yaml:"port"`
+}
@@ -56,6 +56,50 @@ type InstanceState struct {
 
 	// CPU usage information
 	CPU InstanceStateCPU `json:"cpu" yaml:"cpu"`
+
+	// Pressure stall information per resource (cpu, memory or io)
+	//
+	// API extension: instance_pressure
+	Pressure map[string]InstanceStatePressure `json:"pressure,omitempty" yaml:"pressure,omitempty"`
+}
+
+// InstanceStatePressure represents the pressure stall information (PSI) of a resource of a LXD instance.
+//
+// swagger:model
+//
+// API extension: instance_pressure.
+type InstanceStatePressure struct {
+	// Share of time during which at least some tasks were stalled, over the last 10 seconds (in percent)
+	// Example: 2.5
+	SomeAvg10 float64 `json:"some_avg10" yaml:"some_avg10"`
+
+	// Share of time during which at least some tasks were stalled, over the last 60 seconds (in percent)
+	// Example: 1.8
+	SomeAvg60 float64 `json:"some_avg60" yaml:"some_avg60"`
+
+	// Share of time during which at least some tasks were stalled, over the last 300 seconds (in percent)
+	// Example: 0.9
+	SomeAvg300 float64 `json:"some_avg300" yaml:"some_avg300"`
+
+	// Total time during which at least some tasks were stalled (in microseconds)
+	// Example: 1520301
+	SomeTotal uint64 `json:"some_total" yaml:"some_total"`
+
+	// Share of time during which all non-idle tasks were stalled, over the last 10 seconds (in percent)
+	// Example: 0.5
+	FullAvg10 float64 `json:"full_avg10" yaml:"full_avg10"`
+
+	// Share of time during which all non-idle tasks were stalled, over the last 60 seconds (in percent)
+	// Example: 0.3
+	FullAvg60 float64 `json:"full_avg60" yaml:"full_avg60"`
+
+	// Share of time during which all non-idle tasks were stalled, over the last 300 seconds (in percent)
+	// Example: 0.1
+	FullAvg300 float64 `json:"full_avg300" yaml:"full_avg300"`
+
+	// Total time during which all non-idle tasks were stalled (in microseconds)
+	// Example: 210455
+	FullTotal uint64 `json:"full_total" yaml:"full_total"`
 }
 
 // InstanceStateDisk represents the disk information section of a LXD instance's state.
@@ -29,6 +29,10 @@ const (
 	// MetadataOriginalEntityURL is set in operation metadata when renaming a resource.
 	// Callers are expected to set both MetadataOriginalEntityURL and MetadataEntityURL in operation metadata.
 	MetadataOriginalEntityURL = "original_entity_url"
+
+	// MetadataApprovalExpiresAt is set in operation metadata when the operation requires approval.
+	// It holds the time after which the operation fails if it hasn't been approved.
+	MetadataApprovalExpiresAt = "approval_expires_at"
 )
 
 // Operation represents a LXD background operation
@@ -132,6 +136,21 @@ type OperationRequestor struct {
 	Address string `yaml:"address" json:"address"`
 }
 
+// OperationApprovePost represents the fields used to approve or reject an operation pending approval
+//
+// swagger:model
+//
+// API extension: operation_approval.
+type OperationApprovePost struct {
+	// Whether the operation is approved (otherwise it is rejected)
+	// Example: true
+	Approved bool `json:"approved" yaml:"approved"`
+
+	// Reason for the decision
+	// Example: Planned decommissioning
+	Reason string `json:"reason" yaml:"reason"`
+}
+
 // ToCertificateAddToken creates a certificate add token from the operation metadata.
 func (op *Operation) ToCertificateAddToken() (*CertificateAddToken, error) {
 	req, ok := op.Metadata["request"].(map[string]any)
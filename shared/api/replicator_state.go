@@ -14,6 +14,18 @@ const (
 	ReplicatorStatusFailed = "Failed"
 )
 
+const (
+	// ReplicatorEntityStatusSynced represents a dependent entity that matches the source after a replicator run.
+	ReplicatorEntityStatusSynced = "Synced"
+
+	// ReplicatorEntityStatusConflict represents a dependent entity that exists on the target but couldn't be
+	// reconciled with the source.
+	ReplicatorEntityStatusConflict = "Conflict"
+
+	// ReplicatorEntityStatusFailed represents a dependent entity that couldn't be replicated.
+	ReplicatorEntityStatusFailed = "Failed"
+)
+
 // ReplicatorState represents the state of a replicator job.
 //
 // swagger:model
@@ -23,6 +35,31 @@ type ReplicatorState struct {
 	// Status of the replicator job.
 	// Example: Pending
 	Status string `json:"status" yaml:"status"`
+
+	// Outcome of the last run for the profiles, networks, network ACLs, network zones and custom volumes
+	// that the instances of the project depend on.
+	//
+	// API extension: replicator_dependencies.
+	Entities []ReplicatorStateEntity `json:"entities" yaml:"entities"`
+}
+
+// ReplicatorStateEntity represents the outcome of the last replicator run for a dependent entity.
+//
+// swagger:model
+//
+// API extension: replicator_dependencies.
+type ReplicatorStateEntity struct {
+	// URL of the entity in the source project.
+	// Example: /1.0/profiles/default?project=web
+	EntityURL string `json:"entity_url" yaml:"entity_url"`
+
+	// Outcome of the reconciliation (Synced, Conflict or Failed).
+	// Example: Conflict
+	Status string `json:"status" yaml:"status"`
+
+	// Details about the conflict or failure.
+	// Example: Network type "ovn" differs from type "bridge" on the target
+	Message string `json:"message" yaml:"message"`
 }
 
 // ReplicatorStatePut represents the fields available to change the state of a replicator.
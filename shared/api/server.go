@@ -32,6 +32,12 @@ type ServerEnvironment struct {
 	// Example: fd200419b271f1dc2a5591b693cc5774b7f234e1ff8c6b78ad703b6888fe2b69
 	CertificateFingerprint string `json:"certificate_fingerprint" yaml:"certificate_fingerprint"`
 
+	// Fingerprint of the key used by the server to sign images
+	// Example: 2b2f5a5e1d1c1b4d54a1c2a1fb22fe1a7a4d3f9a7c1e5b6d8c0d0a4f6d5e3c2b
+	//
+	// API extension: image_signatures
+	ImageSigningKeyFingerprint string `json:"image_signing_key_fingerprint" yaml:"image_signing_key_fingerprint"`
+
 	// List of supported instance drivers (separate by " | ")
 	// Example: lxc | qemu
 	Driver string `json:"driver" yaml:"driver"`
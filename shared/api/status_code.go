@@ -19,6 +19,7 @@ const (
 	Thawed           StatusCode = 111
 	Error            StatusCode = 112
 	Ready            StatusCode = 113
+	PendingApproval  StatusCode = 114
 
 	Success StatusCode = 200
 
@@ -45,6 +46,7 @@ var StatusCodeNames = map[StatusCode]string{
 	Thawed:           "Thawed",
 	Error:            "Error",
 	Ready:            "Ready",
+	PendingApproval:  "Pending approval",
 }
 
 // String returns a suitable string representation for the status code.
@@ -0,0 +1,69 @@
+package api
+
+// Webhook represents an outbound subscription to the events of the server.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type Webhook struct {
+	WithEntitlements `yaml:",inline"`
+
+	// Name of the webhook.
+	// Example: chat-ops
+	Name string `json:"name" yaml:"name"`
+
+	// Description of the webhook.
+	// Example: Notify the operations channel
+	Description string `json:"description" yaml:"description"`
+
+	// Webhook configuration map (refer to doc/reference/webhook_config.md).
+	// Example: {"url": "https://hooks.example.com/lxd", "types": "lifecycle"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// WebhookPut represents the modifiable fields of a webhook.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookPut struct {
+	// Description of the webhook.
+	// Example: Notify the operations channel
+	Description string `json:"description" yaml:"description"`
+
+	// Webhook configuration map (refer to doc/reference/webhook_config.md).
+	// Example: {"url": "https://hooks.example.com/lxd", "types": "lifecycle"}
+	Config map[string]string `json:"config" yaml:"config"`
+}
+
+// WebhooksPost represents the fields required to create a webhook.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhooksPost struct {
+	// Name of the webhook.
+	// Example: chat-ops
+	Name string `json:"name" yaml:"name"`
+
+	WebhookPut `yaml:",inline"`
+}
+
+// WebhookPost represents the fields required to rename a webhook.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookPost struct {
+	// The new name for the webhook.
+	// Example: ticketing
+	Name string `json:"name" yaml:"name"`
+}
+
+// Writable converts a full Webhook struct into a [WebhookPut] struct (filters read-only fields).
+func (webhook *Webhook) Writable() WebhookPut {
+	return WebhookPut{
+		Description: webhook.Description,
+		Config:      webhook.Config,
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+const (
+	// WebhookTypeEvent is the type of webhooks receiving events.
+	WebhookTypeEvent = "event"
+
+	// WebhookTypeAdmission is the type of webhooks reviewing API requests.
+	WebhookTypeAdmission = "admission"
+)
+
+const (
+	// WebhookAdmissionOperationCreate is the operation of a request creating an entity.
+	WebhookAdmissionOperationCreate = "create"
+
+	// WebhookAdmissionOperationUpdate is the operation of a request updating an entity.
+	WebhookAdmissionOperationUpdate = "update"
+)
+
+// WebhookAdmissionRequest represents the review of an API request sent to an admission webhook.
+//
+// swagger:model
+//
+// API extension: webhooks_admission.
+type WebhookAdmissionRequest struct {
+	// Unique identifier of the review, to be returned in the response
+	// Example: 8ca1f7d4-0bb2-4ef4-9d4e-0d6b6b8c3a3f
+	UUID string `json:"uuid" yaml:"uuid"`
+
+	// Operation of the request (create or update)
+	// Example: create
+	Operation string `json:"operation" yaml:"operation"`
+
+	// Type of the entity (instance, profile, storage_volume, network or project)
+	// Example: instance
+	EntityType string `json:"entity_type" yaml:"entity_type"`
+
+	// URL of the entity
+	// Example: /1.0/instances/c1?project=default
+	EntityURL string `json:"entity_url" yaml:"entity_url"`
+
+	// Project of the entity
+	// Example: default
+	Project string `json:"project" yaml:"project"`
+
+	// Requestor of the request
+	Requestor *EventLifecycleRequestor `json:"requestor" yaml:"requestor"`
+
+	// Proposed object, with the same structure as the body of the request
+	// Example: {"name": "c1", "config": {"user.owner": "alice"}}
+	Object json.RawMessage `json:"object" yaml:"object"`
+}
+
+// WebhookAdmissionResponse represents the response of an admission webhook.
+//
+// swagger:model
+//
+// API extension: webhooks_admission.
+type WebhookAdmissionResponse struct {
+	// Unique identifier of the review
+	// Example: 8ca1f7d4-0bb2-4ef4-9d4e-0d6b6b8c3a3f
+	UUID string `json:"uuid" yaml:"uuid"`
+
+	// Whether the request is allowed
+	// Example: false
+	Allowed bool `json:"allowed" yaml:"allowed"`
+
+	// Reason for rejecting the request
+	// Example: Instances must have a user.owner configuration key
+	Message string `json:"message,omitempty" yaml:"message,omitempty"`
+
+	// JSON patch (RFC 6902) to apply to the proposed object
+	// Example: [{"op": "add", "path": "/config/user.owner", "value": "alice"}]
+	Patch json.RawMessage `json:"patch,omitempty" yaml:"patch,omitempty"`
+}
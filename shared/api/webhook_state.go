@@ -0,0 +1,82 @@
+package api
+
+import (
+	"time"
+)
+
+const (
+	// WebhookDeliveryStatusPending represents a delivery that is waiting for its next attempt.
+	WebhookDeliveryStatusPending = "Pending"
+
+	// WebhookDeliveryStatusFailed represents a delivery that was given up after its last attempt failed.
+	WebhookDeliveryStatusFailed = "Failed"
+)
+
+// WebhookState represents the delivery status of a webhook.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookState struct {
+	// Number of deliveries waiting for their next attempt.
+	// Example: 2
+	Pending int `json:"pending" yaml:"pending"`
+
+	// Number of deliveries that were given up.
+	// Example: 0
+	Failed int `json:"failed" yaml:"failed"`
+
+	// Timestamp of the last successful delivery.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastDeliveryAt time.Time `json:"last_delivery_at" yaml:"last_delivery_at"`
+
+	// Timestamp of the last failed delivery attempt.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	LastFailureAt time.Time `json:"last_failure_at" yaml:"last_failure_at"`
+
+	// Error of the last failed delivery attempt.
+	// Example: Unexpected status code 503
+	LastError string `json:"last_error" yaml:"last_error"`
+
+	// Pending and failed deliveries, oldest first.
+	Deliveries []WebhookDelivery `json:"deliveries" yaml:"deliveries"`
+}
+
+// WebhookDelivery represents an event that hasn't been delivered to a webhook yet.
+//
+// swagger:model
+//
+// API extension: webhooks.
+type WebhookDelivery struct {
+	// Unique identifier of the delivery, sent in the X-LXD-Delivery header.
+	// Example: 3c4b2a4b-7e1d-4cb9-9b1e-bd6e2c2b9f0a
+	UUID string `json:"uuid" yaml:"uuid"`
+
+	// Cluster member delivering the event.
+	// Example: server01
+	Location string `json:"location" yaml:"location"`
+
+	// Type of the event.
+	// Example: lifecycle
+	EventType string `json:"event_type" yaml:"event_type"`
+
+	// Status of the delivery (Pending or Failed).
+	// Example: Pending
+	Status string `json:"status" yaml:"status"`
+
+	// Number of failed delivery attempts.
+	// Example: 3
+	Attempts int `json:"attempts" yaml:"attempts"`
+
+	// Timestamp of the event.
+	// Example: 2021-03-23T17:38:37.753398689-04:00
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+
+	// Timestamp of the next delivery attempt.
+	// Example: 2021-03-23T17:39:37.753398689-04:00
+	NextAttemptAt time.Time `json:"next_attempt_at" yaml:"next_attempt_at"`
+
+	// Error of the last delivery attempt.
+	// Example: Unexpected status code 503
+	LastError string `json:"last_error" yaml:"last_error"`
+}
@@ -121,6 +121,9 @@ const (
 
 	// TypeReplicator represents replicator resources.
 	TypeReplicator Type = "replicator"
+
+	// TypeWebhook represents webhook resources.
+	TypeWebhook Type = "webhook"
 )
 
 const (
@@ -190,6 +193,7 @@ var entityTypes = map[Type]typeInfo{
 	TypePlacementGroup:        placementGroup{},
 	TypeClusterLink:           clusterLink{},
 	TypeReplicator:            replicator{},
+	TypeWebhook:               webhook{},
 }
 
 // metricsEntityTypes is the source of truth for which entity types can be used to categorize endpoints
@@ -210,6 +214,7 @@ var metricsEntityTypes = []Type{
 	TypePlacementGroup,
 	TypeClusterLink,
 	TypeReplicator,
+	TypeWebhook,
 }
 
 // APIMetricsEntityTypes returns the list of entity types relevant for the API metrics.
@@ -648,3 +653,19 @@ func (replicator) path() []string {
 func (replicator) pathArgNames() []string {
 	return []string{"name"}
 }
+
+type webhook struct {
+	typeInfoCommon
+}
+
+func (webhook) requiresProject() bool {
+	return false
+}
+
+func (webhook) path() []string {
+	return []string{"webhooks", pathPlaceholder}
+}
+
+func (webhook) pathArgNames() []string {
+	return []string{"name"}
+}
@@ -344,3 +344,8 @@ func ClusterLinkURL(clusterLinkName string) *api.URL {
 func ReplicatorURL(projectName string, replicatorName string) *api.URL {
 	return TypeReplicator.urlMust(projectName, "", replicatorName)
 }
+
+// WebhookURL returns an [*api.URL] to a webhook.
+func WebhookURL(webhookName string) *api.URL {
+	return TypeWebhook.urlMust("", "", webhookName)
+}
@@ -268,6 +268,14 @@ func TestEntityPermissionURL_RoundTrip(t *testing.T) {
 				"name": "pail",
 			},
 		},
+		{
+			Name:     "Webhook",
+			URL:      "/1.0/webhooks/chat-ops",
+			WantType: TypeWebhook,
+			WantArgs: map[string]string{
+				"name": "chat-ops",
+			},
+		},
 		{
 			Name:     "Storage volume with project and location",
 			URL:      "/1.0/storage-pools/p1/volumes/custom/v1?project=foo&target=bar",
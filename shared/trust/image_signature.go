@@ -0,0 +1,172 @@
+package trust
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+// imageSignaturePrefix is prepended to the image fingerprint to build the signed message, so that image signatures
+// can't be mistaken for signatures of other data made with the same key.
+const imageSignaturePrefix = "lxd-image-signature-v1:"
+
+// imageSignatureMessage returns the message signed for the image with the given fingerprint.
+func imageSignatureMessage(fingerprint string) []byte {
+	return []byte(imageSignaturePrefix + fingerprint)
+}
+
+// KeyFingerprint returns the fingerprint of a public key, which is the SHA-256 hash of its PKIX DER encoding.
+func KeyFingerprint(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("Failed encoding public key: %w", err)
+	}
+
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// ParseSigningKey parses a PEM encoded private key (PKCS #8, SEC 1 or PKCS #1) that can sign images.
+func ParseSigningKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("Failed decoding PEM private key")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("Unsupported PEM block type %q", block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed parsing private key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
+
+// SignImage returns a signature of the image with the given fingerprint.
+// ECDSA, Ed25519 and RSA keys are supported.
+func SignImage(key crypto.Signer, fingerprint string) (*api.ImageSignature, error) {
+	message := imageSignatureMessage(fingerprint)
+
+	var signature []byte
+	var err error
+	switch key.Public().(type) {
+	case ed25519.PublicKey:
+		signature, err = key.Sign(rand.Reader, message, crypto.Hash(0))
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		signature, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("Unsupported key type %T", key.Public())
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed signing image: %w", err)
+	}
+
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return nil, fmt.Errorf("Failed encoding public key: %w", err)
+	}
+
+	keyFingerprint := sha256.Sum256(publicKey)
+
+	return &api.ImageSignature{
+		KeyFingerprint: hex.EncodeToString(keyFingerprint[:]),
+		PublicKey:      base64.StdEncoding.EncodeToString(publicKey),
+		Signature:      base64.StdEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// VerifyImageSignature checks that the signature is valid for the image with the given fingerprint, and that its key
+// fingerprint matches its public key.
+func VerifyImageSignature(signature api.ImageSignature, fingerprint string) error {
+	der, err := base64.StdEncoding.DecodeString(signature.PublicKey)
+	if err != nil {
+		return fmt.Errorf("Invalid public key encoding: %w", err)
+	}
+
+	keyFingerprint := sha256.Sum256(der)
+	if hex.EncodeToString(keyFingerprint[:]) != signature.KeyFingerprint {
+		return fmt.Errorf("Key fingerprint %q doesn't match the public key", signature.KeyFingerprint)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return fmt.Errorf("Invalid signature encoding: %w", err)
+	}
+
+	publicKey, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return fmt.Errorf("Failed parsing public key: %w", err)
+	}
+
+	message := imageSignatureMessage(fingerprint)
+	digest := sha256.Sum256(message)
+
+	var valid bool
+	switch key := publicKey.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, message, sig)
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], sig)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil
+	default:
+		return fmt.Errorf("Unsupported key type %T", publicKey)
+	}
+
+	if !valid {
+		return fmt.Errorf("Invalid signature from key %q", signature.KeyFingerprint)
+	}
+
+	return nil
+}
+
+// VerifyImageTrusted checks that at least one of the signatures is a valid signature of the image with the given
+// fingerprint made by one of the trusted keys (identified by their fingerprints).
+func VerifyImageTrusted(signatures []api.ImageSignature, fingerprint string, trustedKeys []string) error {
+	if len(signatures) == 0 {
+		return errors.New("Image isn't signed")
+	}
+
+	for _, signature := range signatures {
+		if !slices.Contains(trustedKeys, signature.KeyFingerprint) {
+			continue
+		}
+
+		err := VerifyImageSignature(signature, fingerprint)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return errors.New("Image isn't signed by a trusted key")
+}
@@ -0,0 +1,102 @@
+package trust
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/canonical/lxd/shared/api"
+)
+
+const testImageFingerprint = "8ae945c52bb2f2df51c923b04022312f99bbb72c356251f54fa89ea7cf1df1d0"
+
+func TestSignImage(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	for _, key := range []crypto.Signer{ecKey, edKey, rsaKey} {
+		signature, err := SignImage(key, testImageFingerprint)
+		require.NoError(t, err)
+
+		keyFingerprint, err := KeyFingerprint(key.Public())
+		require.NoError(t, err)
+		require.Equal(t, keyFingerprint, signature.KeyFingerprint)
+
+		require.NoError(t, VerifyImageSignature(*signature, testImageFingerprint))
+
+		// The signature is bound to the image fingerprint.
+		require.Error(t, VerifyImageSignature(*signature, "0"+testImageFingerprint[1:]))
+
+		// The key fingerprint must match the public key.
+		tampered := *signature
+		firstDigit := "0"
+		if tampered.KeyFingerprint[0] == '0' {
+			firstDigit = "1"
+		}
+
+		tampered.KeyFingerprint = firstDigit + tampered.KeyFingerprint[1:]
+		require.Error(t, VerifyImageSignature(tampered, testImageFingerprint))
+	}
+}
+
+func TestVerifyImageTrusted(t *testing.T) {
+	_, trustedKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	trustedSignature, err := SignImage(trustedKey, testImageFingerprint)
+	require.NoError(t, err)
+
+	otherSignature, err := SignImage(otherKey, testImageFingerprint)
+	require.NoError(t, err)
+
+	trustedKeys := []string{trustedSignature.KeyFingerprint}
+
+	require.Error(t, VerifyImageTrusted(nil, testImageFingerprint, trustedKeys))
+	require.Error(t, VerifyImageTrusted([]api.ImageSignature{*otherSignature}, testImageFingerprint, trustedKeys))
+	require.NoError(t, VerifyImageTrusted([]api.ImageSignature{*otherSignature, *trustedSignature}, testImageFingerprint, trustedKeys))
+
+	// A signature of another image from a trusted key isn't accepted.
+	require.Error(t, VerifyImageTrusted([]api.ImageSignature{*trustedSignature}, "0"+testImageFingerprint[1:], trustedKeys))
+}
+
+func TestParseSigningKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	for _, block := range []*pem.Block{{Type: "EC PRIVATE KEY", Bytes: ecDER}, {Type: "PRIVATE KEY", Bytes: edDER}} {
+		signer, err := ParseSigningKey(pem.EncodeToMemory(block))
+		require.NoError(t, err)
+
+		_, err = SignImage(signer, testImageFingerprint)
+		require.NoError(t, err)
+	}
+
+	_, err = ParseSigningKey([]byte("not a key"))
+	require.Error(t, err)
+}
@@ -500,6 +500,29 @@ var APIExtensions = []string{
 	"operation_child_count",
 	"storage_driver_powerstore_nvme",
 	"access_management_expiry",
+	"instance_fork",
+	"instance_reservations",
+	"cluster_rebalance",
+	"instance_boot_dependencies",
+	"instance_pressure",
+	"replicator_dependencies",
+	"replicator_failover",
+	"instance_move_cluster_link",
+	"audit_log",
+	"webhooks",
+	"event_history",
+	"webhooks_admission",
+	"operation_approval",
+	"scim",
+	"api_rate_limits",
+	"image_signatures",
+	"image_simplestreams",
+	"image_build",
+	"image_sbom",
+	"image_retention",
+	"instance_snapshot_consistency",
+	"instance_port_forward",
+	"instance_identity_tokens",
 }
 
 // APIExtensionsCount returns the number of available API extensions.
@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"errors"
+	"net"
+	"syscall"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/canonical/lxd/shared/logger"
+)
+
+// Forward mirrors the traffic between a websocket and a network connection until either side is done.
+// Stream connections are mirrored like with [Mirror] until both directions have ended, with the end of the
+// websocket stream shutting down the writing side of the connection. Packet connections, such as UDP sockets,
+// are mirrored one packet per binary message. The network connection is closed on return, the websocket isn't.
+func Forward(conn *websocket.Conn, target net.Conn) error {
+	defer func() { _ = target.Close() }()
+
+	_, isPacket := target.(net.PacketConn)
+	if isPacket {
+		return forwardPackets(conn, target)
+	}
+
+	readDone, writeDone := Mirror(conn, target)
+
+	for readDone != nil || writeDone != nil {
+		select {
+		case err := <-readDone:
+			if err != nil {
+				return err
+			}
+
+			readDone = nil
+		case err := <-writeDone:
+			if err != nil {
+				return err
+			}
+
+			writeDone = nil
+
+			// The other side is done sending, let the target know and keep sending its replies.
+			closeWriter, ok := target.(interface{ CloseWrite() error })
+			if ok {
+				_ = closeWriter.CloseWrite()
+			}
+		}
+	}
+
+	return nil
+}
+
+// forwardPackets mirrors the binary messages of a websocket to the packets of a connection and back.
+func forwardPackets(conn *websocket.Conn, target net.Conn) error {
+	l := logger.AddContext(logger.Ctx{"address": conn.RemoteAddr().String()})
+
+	chDone := make(chan error, 2)
+
+	go func() {
+		for {
+			mt, data, err := conn.ReadMessage()
+			if err != nil {
+				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+					err = nil
+				}
+
+				chDone <- err
+				return
+			}
+
+			// Text messages mark the end of the stream.
+			if mt != websocket.BinaryMessage {
+				chDone <- nil
+				return
+			}
+
+			_, err = target.Write(data)
+			if err != nil {
+				l.Debug("Websocket: Failed writing packet", logger.Ctx{"err": err})
+			}
+		}
+	}()
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, err := target.Read(buf)
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				continue // Nothing listening on the target port yet.
+			}
+
+			if err != nil {
+				if errors.Is(err, net.ErrClosed) {
+					err = nil
+				}
+
+				chDone <- err
+				return
+			}
+
+			err = conn.WriteMessage(websocket.BinaryMessage, buf[:n])
+			if err != nil {
+				chDone <- err
+				return
+			}
+		}
+	}()
+
+	return <-chDone
+}
@@ -0,0 +1,161 @@
+package ws
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// forwardServer returns the URL of a websocket server forwarding its connections to the given target.
+func forwardServer(t *testing.T, network string, address string) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		target, err := net.Dial(network, address)
+		if err != nil {
+			return
+		}
+
+		_ = Forward(conn, target)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestForwardStream(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	// Reply with the upper case version of everything received once the client is done sending.
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		data, _ := io.ReadAll(conn)
+		_, _ = conn.Write([]byte(strings.ToUpper(string(data))))
+	}()
+
+	conn, _, err := websocket.DefaultDialer.Dial(forwardServer(t, "tcp", listener.Addr().String()), nil)
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte("hello ")))
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte("world")))
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte{}))
+
+	reply, err := io.ReadAll(NewWrapper(conn))
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO WORLD", string(reply))
+}
+
+func TestForwardHalfClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = listener.Close() }()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		data, _ := io.ReadAll(conn)
+		_, _ = conn.Write([]byte(strings.ToUpper(string(data))))
+	}()
+
+	// Forward both ends, like a client listening locally would.
+	local, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = local.Close() }()
+
+	wsURL := forwardServer(t, "tcp", listener.Addr().String())
+	go func() {
+		localConn, err := local.Accept()
+		if err != nil {
+			return
+		}
+
+		conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+		if err != nil {
+			_ = localConn.Close()
+			return
+		}
+
+		defer func() { _ = conn.Close() }()
+
+		_ = Forward(conn, localConn)
+	}()
+
+	client, err := net.Dial("tcp", local.Addr().String())
+	require.NoError(t, err)
+
+	defer func() { _ = client.Close() }()
+
+	_, err = client.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, client.(*net.TCPConn).CloseWrite())
+
+	reply, err := io.ReadAll(client)
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", string(reply))
+}
+
+func TestForwardPackets(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer func() { _ = pc.Close() }()
+
+	// Echo each packet back twice.
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			_, _ = pc.WriteTo(buf[:n], addr)
+			_, _ = pc.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	conn, _, err := websocket.DefaultDialer.Dial(forwardServer(t, "udp", pc.LocalAddr().String()), nil)
+	require.NoError(t, err)
+
+	defer func() { _ = conn.Close() }()
+
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte("ping")))
+
+	for range 2 {
+		mt, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		assert.Equal(t, websocket.BinaryMessage, mt)
+		assert.Equal(t, "ping", string(data))
+	}
+}
@@ -120,6 +120,17 @@ func run(args []string) error {
 		}
 
 		return nil
+	case "identity-token":
+		if len(args) != 3 {
+			return fmt.Errorf("Usage: %s identity-token <audience>", args[0])
+		}
+
+		token, err := client.GetIdentityToken(args[2])
+		if err != nil {
+			return err
+		}
+
+		return printPrettyJSON(token)
 	case "cloud-init":
 		if len(args) != 3 {
 			return fmt.Errorf("Usage: %s cloud-init <user-data|vendor-data|network-config>", args[0])
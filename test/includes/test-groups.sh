@@ -96,7 +96,9 @@ readonly test_group_instance=(
     "exec"
     "exec_exit_code"
     "lxd_benchmark_basic"
+    "lxd_benchmark_scenarios"
     "vm_empty"
+    "vm_fork"
     "vm_pcie_bus"
 )
 
@@ -74,6 +74,25 @@ test_devlxd() {
   [ "$(lxc exec devlxd -- devlxd-client user.foo)" = "bar" ]
   [ "$(lxc exec devlxd -- devlxd-client user.xyz)" = "bar %s bar" ]
 
+  # Identity tokens must be enabled on both the instance and the server.
+  [ "$(lxc exec devlxd -- devlxd-client identity-token vault)" = "Forbidden" ]
+  lxc config set devlxd security.devlxd.identity_tokens=true security.devlxd.identity_tokens.user_keys=user.foo
+  [ "$(lxc exec devlxd -- devlxd-client identity-token vault)" = "Instance identity tokens are not enabled on the server" ]
+  [ "$(curl -s -k -o /dev/null -w "%{http_code}" "https://${LXD_ADDR}/.well-known/jwks.json")" = "404" ]
+  lxc config set instances.identity_tokens.issuer "https://lxd.example.net:8443"
+  curl -s -k "https://${LXD_ADDR}/.well-known/openid-configuration" | jq --exit-status '.jwks_uri == "https://lxd.example.net:8443/.well-known/jwks.json"'
+  kid="$(curl -s -k "https://${LXD_ADDR}/.well-known/jwks.json" | jq --exit-status --raw-output '.keys[0].kid')"
+  token="$(lxc exec devlxd -- devlxd-client identity-token vault | jq --exit-status --raw-output '.token')"
+  header="$(echo "${token}" | cut -d. -f1 | tr '_-' '/+')"
+  payload="$(echo "${token}" | cut -d. -f2 | tr '_-' '/+')"
+  while [ "$((${#header} % 4))" != "0" ]; do header="${header}="; done
+  while [ "$((${#payload} % 4))" != "0" ]; do payload="${payload}="; done
+  echo "${header}" | base64 -d | jq --exit-status '.alg == "ES256" and .kid == "'"${kid}"'"'
+  echo "${payload}" | base64 -d | jq --exit-status '.iss == "https://lxd.example.net:8443" and .sub == "instance:'"$(lxc config get devlxd volatile.uuid)"'" and .project == "default" and .instance == "devlxd" and .aud == ["vault"] and .user == {"foo": "bar"}'
+  lxc config unset instances.identity_tokens.issuer
+  lxc config unset devlxd security.devlxd.identity_tokens
+  lxc config unset devlxd security.devlxd.identity_tokens.user_keys
+
   # Make sure instance configuration keys are not accessible
   [ "$(lxc exec devlxd -- devlxd-client security.nesting)" = "Forbidden" ]
   lxc config set devlxd security.nesting true
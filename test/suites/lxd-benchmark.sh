@@ -33,3 +33,41 @@ test_lxd_benchmark_basic(){
   rm "${report_file}"
 }
 
+
+test_lxd_benchmark_scenarios(){
+  local count=2
+  local report_file
+  report_file="$(mktemp -p "${TEST_DIR}" XXX)"
+
+  ensure_import_testimage
+
+  lxd-benchmark launch --count "${count}" testimage
+
+  # Measure operations on the running containers.
+  lxd-benchmark exec --iterations 3 --report-file "${report_file}" --report-format json
+  lxd-benchmark file --iterations 2 --size 1MiB --report-file "${report_file}" --report-format json
+  lxd-benchmark snapshot --report-file "${report_file}" --report-format json
+  lxd-benchmark api --count 20 --report-file "${report_file}" --report-format json
+
+  # Each scenario records the latencies of the successful operations.
+  [ "$(jq -r '.results[] | select(.scenario == "exec") | .operations' "${report_file}")" = "6" ]
+  [ "$(jq -r '.results[] | select(.scenario == "file-pull") | .operations' "${report_file}")" = "4" ]
+  [ "$(jq -r '.results[] | select(.scenario == "snapshot-restore") | .operations' "${report_file}")" = "2" ]
+  [ "$(jq -r '.results[] | select(.scenario == "api") | .errors' "${report_file}")" = "0" ]
+  [ "$(jq -r '.results[] | select(.scenario == "exec") | .latency.p99 > 0' "${report_file}")" = "true" ]
+
+  # A report doesn't regress against itself, but does against a faster one.
+  lxd-benchmark compare "${report_file}" "${report_file}"
+  jq '.results[].latency.p50 /= 10' "${report_file}" > "${report_file}.fast"
+  ! lxd-benchmark compare "${report_file}.fast" "${report_file}" || false
+
+  # Prometheus report.
+  lxd-benchmark exec --iterations 1 --report-file "${report_file}.prom" --report-format prometheus
+  grep -F 'lxd_benchmark_latency_seconds{scenario="exec",label="",quantile="0.99"}' "${report_file}.prom"
+
+  lxd-benchmark delete
+  [ "$(lxc list -f csv -c n || echo fail)" = "" ]
+
+  # cleanup
+  rm "${report_file}" "${report_file}.fast" "${report_file}.prom"
+}
@@ -264,6 +264,61 @@ test_vm_pcie_bus() {
   fi
 }
 
+test_vm_fork() {
+  if [ "${LXD_TMPFS:-0}" = "1" ] && ! runsMinimumKernel 6.6; then
+    export TEST_UNMET_REQUIREMENT="QEMU requires direct-io support which requires a kernel >= 6.6 for tmpfs support (LXD_TMPFS=${LXD_TMPFS})"
+    return 0
+  fi
+
+  lxc init --vm --empty v1 -c limits.memory=128MiB -d "${SMALL_ROOT_DISK}" -d root,size.state=256MiB
+
+  echo "==> Only running instances with migration.stateful can be forked"
+  ! lxc fork v1 f1 || false
+  lxc start v1
+  ! lxc fork v1 f1 || false
+  lxc stop -f v1
+  lxc config set v1 migration.stateful=true
+  lxc start v1
+
+  echo "==> Invalid fork requests"
+  ! lxc query --request POST "/1.0/instances/v1?fork" --data '{"names": []}' || false
+  ! lxc fork v1 f1 f1 || false
+  ! lxc fork v1 v1 || false
+  ! lxc fork v1 ".." || false
+
+  echo "==> Fork a running VM"
+  lxc fork v1 f1 f2
+  for inst in f1 f2; do
+    [ "$(lxc list -f csv -c s "${inst}")" = "RUNNING" ]
+    [ "$(lxc config get "${inst}" volatile.eth0.hwaddr)" != "$(lxc config get v1 volatile.eth0.hwaddr)" ]
+    [ "$(lxc config get "${inst}" volatile.uuid)" != "$(lxc config get v1 volatile.uuid)" ]
+  done
+
+  # The source keeps running and the temporary snapshot is removed.
+  [ "$(lxc list -f csv -c s v1)" = "RUNNING" ]
+  [ "$(lxc list -f csv -c S v1)" = "0" ]
+  lxc delete -f f1 f2
+
+  echo "==> Ephemeral forks are deleted on stop"
+  lxc fork --ephemeral v1 f1
+  lxc stop -f f1
+  ! lxc info f1 || false
+
+  echo "==> Partially created forks are cleaned up on failure"
+  if [ "$(storage_backend "${LXD_DIR}")" = "dir" ]; then
+    pool="$(lxc profile device get default root pool)"
+    leftover="$(lxc storage get "${pool}" source)/virtual-machines/f2"
+    mkdir -p "${leftover}"
+    ! lxc fork v1 f1 f2 || false
+    ! lxc info f1 || false
+    ! lxc info f2 || false
+    [ "$(lxc list -f csv -c S v1)" = "0" ]
+    rmdir "${leftover}"
+  fi
+
+  lxc delete -f v1
+}
+
 test_snap_vm_empty() {
   # useful to test snap provided BIOS boot
   _boot_mode